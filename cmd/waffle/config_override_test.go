@@ -1,11 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/bedrock"
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
 )
 
 func TestLoadConfigWithOverrides(t *testing.T) {
@@ -29,7 +41,7 @@ func TestLoadConfigWithOverrides(t *testing.T) {
 			name:                "region flag overrides config",
 			regionFlag:          "eu-west-1",
 			profileFlag:         "",
-			expectedBedrockRegion: "eu-west-1",
+			expectedBedrockRegion: "us-east-1", // Bedrock keeps its own region
 			expectedAWSRegion:   "eu-west-1",
 			wantErr:             false,
 		},
@@ -45,7 +57,7 @@ func TestLoadConfigWithOverrides(t *testing.T) {
 			name:                "both flags are applied",
 			regionFlag:          "ap-southeast-1",
 			profileFlag:         "prod-profile",
-			expectedBedrockRegion: "ap-southeast-1",
+			expectedBedrockRegion: "us-east-1", // Bedrock keeps its own region
 			expectedAWSRegion:   "ap-southeast-1",
 			wantErr:             false,
 		},
@@ -85,22 +97,299 @@ func TestLoadConfigWithOverrides(t *testing.T) {
 	}
 }
 
-func TestRegionFlagOverridesBothBedrockAndAWS(t *testing.T) {
-	// Create a test command with region flag
+func TestRegionFlagLeavesBedrockRegion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWS_REGION", "")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("bedrock:\n  region: us-west-2\n"), 0644))
+
 	cmd := &cobra.Command{}
+	cmd.Flags().String("dir", "", "Directory")
 	cmd.Flags().String("region", "", "AWS region")
 	cmd.Flags().String("profile", "", "AWS profile")
+	cmd.Flags().Set("dir", dir)
+	cmd.Flags().Set("region", "eu-west-1")
 
-	// Set region flag
-	cmd.Flags().Set("region", "us-west-2")
-
-	// Load config
 	cfg, err := loadConfigWithOverrides(cmd)
 	require.NoError(t, err)
 
-	// Verify both AWS and Bedrock regions are set
-	assert.Equal(t, "us-west-2", cfg.AWS.Region, "AWS region should be overridden")
-	assert.Equal(t, "us-west-2", cfg.Bedrock.Region, "Bedrock region should be overridden")
+	assert.Equal(t, "eu-west-1", cfg.AWS.Region, "AWS region should be overridden")
+	assert.Equal(t, "us-west-2", cfg.Bedrock.Region, "the configured Bedrock region is kept")
+}
+
+func TestBedrockRegionFlag(t *testing.T) {
+	tests := []struct {
+		name                  string
+		regionFlag            string
+		bedrockRegionFlag     string
+		expectedBedrockRegion string
+		expectedAWSRegion     string
+	}{
+		{
+			name:                  "bedrock region alone",
+			bedrockRegionFlag:     "us-west-2",
+			expectedBedrockRegion: "us-west-2",
+			expectedAWSRegion:     "",
+		},
+		{
+			name:                  "bedrock region takes precedence over region",
+			regionFlag:            "eu-west-1",
+			bedrockRegionFlag:     "us-east-1",
+			expectedBedrockRegion: "us-east-1",
+			expectedAWSRegion:     "eu-west-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("region", "", "AWS region")
+			cmd.Flags().String("bedrock-region", "", "Bedrock region")
+			cmd.Flags().String("profile", "", "AWS profile")
+
+			if tt.regionFlag != "" {
+				cmd.Flags().Set("region", tt.regionFlag)
+			}
+			cmd.Flags().Set("bedrock-region", tt.bedrockRegionFlag)
+
+			cfg, err := loadConfigWithOverrides(cmd)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBedrockRegion, cfg.Bedrock.Region)
+			assert.Equal(t, tt.expectedAWSRegion, cfg.AWS.Region)
+		})
+	}
+}
+
+func TestClientsUseIndependentRegions(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.AWS.Region = "eu-west-1"
+	cfg.Bedrock.Region = "us-east-1"
+
+	bedrockClient, err := initializeBedrockClient(ctx, &cfg.AWS, cfg)
+	require.NoError(t, err)
+	client, ok := bedrockClient.(*bedrock.Client)
+	require.True(t, ok)
+	assert.Equal(t, "us-east-1", client.Region())
+
+	evaluator, err := initializeWAFREvaluator(ctx, &cfg.AWS, cfg, bedrockClient, nil)
+	require.NoError(t, err)
+	adapter, ok := evaluator.(*WAFREvaluatorAdapter)
+	require.True(t, ok)
+	assert.Equal(t, "eu-west-1", adapter.evaluator.Region())
+}
+
+func TestApplyModelParamFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    config.BedrockConfig
+		wantErr string
+	}{
+		{
+			name: "no flags keep the config",
+			want: config.BedrockConfig{Temperature: 0.7, TopP: 0.9, MaxTokens: 4096},
+		},
+		{
+			name:  "all flags",
+			flags: map[string]string{"temperature": "0", "top-p": "0.5", "max-tokens": "1024"},
+			want:  config.BedrockConfig{Temperature: 0, TopP: 0.5, MaxTokens: 1024},
+		},
+		{
+			name:  "temperature only",
+			flags: map[string]string{"temperature": "0.2"},
+			want:  config.BedrockConfig{Temperature: 0.2, TopP: 0.9, MaxTokens: 4096},
+		},
+		{
+			name:    "temperature out of range",
+			flags:   map[string]string{"temperature": "1.5"},
+			wantErr: "--temperature must be between 0 and 1",
+		},
+		{
+			name:    "negative top-p",
+			flags:   map[string]string{"top-p": "-0.1"},
+			wantErr: "--top-p must be between 0 and 1",
+		},
+		{
+			name:    "zero max tokens",
+			flags:   map[string]string{"max-tokens": "0"},
+			wantErr: "--max-tokens must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Float64("temperature", 0, "")
+			cmd.Flags().Float64("top-p", 0, "")
+			cmd.Flags().Int("max-tokens", 0, "")
+			for name, value := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
+
+			cfg := config.DefaultConfig()
+			err := applyModelParamFlags(cmd, &cfg.Bedrock)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// The overrides reach the Bedrock client configuration
+			bedrockCfg := newBedrockConfig(cfg)
+			assert.Equal(t, tt.want.Temperature, bedrockCfg.Temperature)
+			assert.Equal(t, tt.want.TopP, bedrockCfg.TopP)
+			assert.Equal(t, tt.want.MaxTokens, bedrockCfg.MaxTokens)
+		})
+	}
+}
+
+func TestApplyDeterministicFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    modelParams
+		wantErr string
+	}{
+		{
+			name: "not deterministic",
+			want: modelParams{Temperature: 0.7, TopP: 0.9, MaxTokens: 4096, SubmitConcurrency: 4},
+		},
+		{
+			name:  "deterministic",
+			flags: map[string]string{"deterministic": "true"},
+			want:  modelParams{Temperature: 0, TopP: 1, MaxTokens: 4096, Deterministic: true, ResourceOrder: "address", SubmitConcurrency: 1},
+		},
+		{
+			name:  "deterministic keeps max tokens",
+			flags: map[string]string{"deterministic": "true", "max-tokens": "1024"},
+			want:  modelParams{Temperature: 0, TopP: 1, MaxTokens: 1024, Deterministic: true, ResourceOrder: "address", SubmitConcurrency: 1},
+		},
+		{
+			name:    "deterministic with temperature",
+			flags:   map[string]string{"deterministic": "true", "temperature": "0.2"},
+			wantErr: "--deterministic cannot be combined with --temperature or --top-p",
+		},
+		{
+			name:    "deterministic with top-p",
+			flags:   map[string]string{"deterministic": "true", "top-p": "0.5"},
+			wantErr: "--deterministic cannot be combined with --temperature or --top-p",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Float64("temperature", 0, "")
+			cmd.Flags().Float64("top-p", 0, "")
+			cmd.Flags().Int("max-tokens", 0, "")
+			cmd.Flags().Bool("deterministic", false, "")
+			for name, value := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
+
+			cfg := config.DefaultConfig()
+			require.NoError(t, applyModelParamFlags(cmd, &cfg.Bedrock))
+			err := applyDeterministicFlag(cmd, cfg)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			deterministic, _ := cmd.Flags().GetBool("deterministic")
+			assert.Equal(t, &tt.want, newModelParams(cfg, deterministic))
+
+			// The effective parameters reach the Bedrock client configuration
+			bedrockCfg := newBedrockConfig(cfg)
+			assert.Equal(t, tt.want.Temperature, bedrockCfg.Temperature)
+			assert.Equal(t, tt.want.TopP, bedrockCfg.TopP)
+		})
+	}
+}
+
+func TestClientsUseHTTPTimeout(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	for _, seconds := range []int{15, 0} {
+		ctx := context.Background()
+		cfg := config.DefaultConfig()
+		cfg.AWS.Region = "eu-west-1"
+		cfg.Bedrock.Region = "us-east-1"
+		cfg.AWS.HTTPTimeout = seconds
+		want := time.Duration(seconds) * time.Second
+
+		bedrockClient, err := initializeBedrockClient(ctx, &cfg.AWS, cfg)
+		require.NoError(t, err)
+		client, ok := bedrockClient.(*bedrock.Client)
+		require.True(t, ok)
+		assert.Equal(t, want, client.HTTPTimeout())
+
+		evaluator, err := initializeWAFREvaluator(ctx, &cfg.AWS, cfg, bedrockClient, nil)
+		require.NoError(t, err)
+		adapter, ok := evaluator.(*WAFREvaluatorAdapter)
+		require.True(t, ok)
+		assert.Equal(t, want, adapter.evaluator.HTTPTimeout())
+	}
+}
+
+func TestClientsUseFIPSEndpoints(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	for _, useFIPS := range []bool{true, false} {
+		ctx := context.Background()
+		cfg := config.DefaultConfig()
+		cfg.AWS.Region = "us-gov-west-1"
+		cfg.Bedrock.Region = "us-gov-west-1"
+		cfg.AWS.UseFIPS = useFIPS
+
+		bedrockClient, err := initializeBedrockClient(ctx, &cfg.AWS, cfg)
+		require.NoError(t, err)
+		client, ok := bedrockClient.(*bedrock.Client)
+		require.True(t, ok)
+		assert.Equal(t, useFIPS, client.UsesFIPSEndpoint())
+
+		evaluator, err := initializeWAFREvaluator(ctx, &cfg.AWS, cfg, bedrockClient, nil)
+		require.NoError(t, err)
+		adapter, ok := evaluator.(*WAFREvaluatorAdapter)
+		require.True(t, ok)
+		assert.Equal(t, useFIPS, adapter.evaluator.UsesFIPSEndpoint())
+
+		// Clients built from the SDK config, including the STS client used
+		// to assume roles, read the endpoint setting from its load options
+		sdkCfg, err := loadAWSSDKConfig(ctx, &cfg.AWS)
+		require.NoError(t, err)
+		var state aws.FIPSEndpointState
+		for _, source := range sdkCfg.ConfigSources {
+			if options, ok := source.(awsconfig.LoadOptions); ok {
+				state = options.UseFIPSEndpoint
+			}
+		}
+		assert.Equal(t, cfg.AWS.FIPSEndpointState(), state)
+	}
+}
+
+func TestFIPSFlag(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("fips", false, "Use FIPS endpoints")
+	require.NoError(t, cmd.Flags().Set("fips", "true"))
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	require.NoError(t, err)
+	assert.True(t, cfg.AWS.UseFIPS)
+	assert.Equal(t, aws.FIPSEndpointStateEnabled, cfg.AWS.FIPSEndpointState())
 }
 
 func TestPersistentFlagsAvailableOnAllCommands(t *testing.T) {
@@ -116,11 +405,92 @@ func TestPersistentFlagsAvailableOnAllCommands(t *testing.T) {
 		t.Run(cmd.Name(), func(t *testing.T) {
 			// Check that persistent flags are inherited
 			regionFlag := cmd.Flag("region")
+			bedrockRegionFlag := cmd.Flag("bedrock-region")
 			profileFlag := cmd.Flag("profile")
 
 			// Flags should be available (either local or inherited from parent)
 			assert.NotNil(t, regionFlag, "region flag should be available on %s command", cmd.Name())
+			assert.NotNil(t, bedrockRegionFlag, "bedrock-region flag should be available on %s command", cmd.Name())
 			assert.NotNil(t, profileFlag, "profile flag should be available on %s command", cmd.Name())
 		})
 	}
 }
+
+func TestWorkingDir(t *testing.T) {
+	infraDir := t.TempDir()
+	filePath := filepath.Join(infraDir, "main.tf")
+	require.NoError(t, os.WriteFile(filePath, []byte(""), 0644))
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{name: "defaults to the current directory", dir: "", want: currentDir},
+		{name: "uses the flag", dir: infraDir, want: infraDir},
+		{name: "missing directory", dir: filepath.Join(infraDir, "missing"), wantErr: true},
+		{name: "file instead of directory", dir: filePath, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().StringP("dir", "C", "", "Directory")
+			require.NoError(t, cmd.Flags().Set("dir", tt.dir))
+
+			got, err := workingDir(cmd)
+
+			if tt.wantErr {
+				var dirErr *core.DirectoryAccessError
+				assert.ErrorAs(t, err, &dirErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInitializeIaCAnalyzer_WorkingDir(t *testing.T) {
+	infraDir := t.TempDir()
+
+	analyzer, err := initializeIaCAnalyzer(context.Background(), nil, infraDir)
+
+	require.NoError(t, err)
+	iacAnalyzer, ok := analyzer.(*iac.Analyzer)
+	require.True(t, ok)
+	assert.Equal(t, infraDir, iacAnalyzer.WorkingDir())
+}
+
+func TestDirFlagAvailableOnAllCommands(t *testing.T) {
+	for _, cmd := range []*cobra.Command{reviewCmd, statusCmd, resultsCmd} {
+		flag := cmd.Flag("dir")
+		require.NotNil(t, flag, "dir flag should be available on %s command", cmd.Name())
+		assert.Equal(t, "C", flag.Shorthand)
+	}
+}
+
+func TestPrintInitConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Bedrock.Region = "us-west-2"
+
+	var buf bytes.Buffer
+	printInitConfig(&buf, cfg)
+	assert.Contains(t, buf.String(), "Bedrock Region: us-west-2")
+	assert.Contains(t, buf.String(), "Bedrock Model: "+cfg.Bedrock.ModelID)
+
+	cfg.Model = config.ModelConfig{
+		Provider: config.ModelProviderOpenAICompatible,
+		BaseURL:  "https://llm.example.com/v1",
+		Name:     "llama",
+	}
+	buf.Reset()
+	printInitConfig(&buf, cfg)
+	assert.Contains(t, buf.String(), "Model Provider: openai-compatible")
+	assert.Contains(t, buf.String(), "Model Endpoint: https://llm.example.com/v1")
+	assert.Contains(t, buf.String(), "Model: llama")
+	assert.NotContains(t, buf.String(), "Bedrock")
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// defaultProposedAnswersFile is where proposed answers are written when no
+// file name is entered at the prompt
+const defaultProposedAnswersFile = "proposed-answers.json"
+
+// terminalSubmissionConfirmer shows all proposed answers and asks once
+// whether to submit them. Prompts are written to out so that stdout stays
+// reserved for JSON output.
+type terminalSubmissionConfirmer struct {
+	in  *bufio.Reader
+	out io.Writer
+	// assumeYes submits after printing the answers, without a prompt
+	assumeYes bool
+}
+
+// newTerminalSubmissionConfirmer creates a submission confirmer reading the
+// decision from in
+func newTerminalSubmissionConfirmer(in io.Reader, out io.Writer, assumeYes bool) *terminalSubmissionConfirmer {
+	return &terminalSubmissionConfirmer{
+		in:        bufio.NewReader(in),
+		out:       out,
+		assumeYes: assumeYes,
+	}
+}
+
+// ConfirmSubmission prints the proposed answers and reads whether to submit
+// them, abort, or write them to a file for offline review. Writing them to a
+// file also aborts, so nothing is submitted before they are reviewed.
+func (c *terminalSubmissionConfirmer) ConfirmSubmission(ctx context.Context, evaluations []*core.QuestionEvaluation) error {
+	fmt.Fprintf(c.out, "\n%d proposed answers:\n", len(evaluations))
+	if err := writeProposedAnswers(c.out, evaluations); err != nil {
+		return err
+	}
+	if c.assumeYes {
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fmt.Fprint(c.out, "Submit these answers to AWS? [y]es, [n]o, or [w]rite them to a file: ")
+		line, err := c.readLine()
+		if err != nil {
+			return fmt.Errorf("%w: %v", core.ErrSubmissionAborted, err)
+		}
+
+		switch strings.ToLower(line) {
+		case "y", "yes":
+			return nil
+		case "n", "no":
+			return fmt.Errorf("%w by user", core.ErrSubmissionAborted)
+		case "w", "write":
+			path, err := c.promptFile()
+			if err != nil {
+				return fmt.Errorf("%w: %v", core.ErrSubmissionAborted, err)
+			}
+			if err := writeProposedAnswersFile(path, evaluations); err != nil {
+				fmt.Fprintf(c.out, "Failed to write proposed answers: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(c.out, "Proposed answers written to %s\n", path)
+			return fmt.Errorf("%w: proposed answers written to %s for review", core.ErrSubmissionAborted, path)
+		default:
+			fmt.Fprintf(c.out, "Invalid choice %q\n", line)
+		}
+	}
+}
+
+// promptFile reads the file to write proposed answers to
+func (c *terminalSubmissionConfirmer) promptFile() (string, error) {
+	fmt.Fprintf(c.out, "File [%s]: ", defaultProposedAnswersFile)
+	path, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return defaultProposedAnswersFile, nil
+	}
+	return path, nil
+}
+
+// readLine reads one trimmed line of input. A final unterminated line is
+// returned; closed input without one is an error.
+func (c *terminalSubmissionConfirmer) readLine() (string, error) {
+	line, err := c.in.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "", errors.New("input closed before a choice was made")
+	}
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// writeProposedAnswers writes a table of each question with its selected
+// choices and confidence
+func writeProposedAnswers(w io.Writer, evaluations []*core.QuestionEvaluation) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "QUESTION\tPILLAR\tSELECTED CHOICES\tCONFIDENCE")
+	for _, evaluation := range evaluations {
+		choices := make([]string, 0, len(evaluation.SelectedChoices))
+		for _, choice := range evaluation.SelectedChoices {
+			choices = append(choices, choice.ID)
+		}
+		selected := strings.Join(choices, ", ")
+		if selected == "" {
+			selected = "(none)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f\n", evaluation.Question.ID, evaluation.Question.Pillar, selected, evaluation.ConfidenceScore)
+	}
+	return tw.Flush()
+}
+
+// writeProposedAnswersFile writes the proposed answers to path as JSON in
+// the form of the evaluations of the review output
+func writeProposedAnswersFile(path string, evaluations []*core.QuestionEvaluation) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"answers": core.ConvertEvaluationsToOutput(evaluations),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode proposed answers: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
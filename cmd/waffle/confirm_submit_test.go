@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func proposedEvaluations() []*core.QuestionEvaluation {
+	return []*core.QuestionEvaluation{
+		{
+			Question:        &core.WAFRQuestion{ID: "sec_data_1", Pillar: core.PillarSecurity, Title: "How do you protect your data at rest?"},
+			SelectedChoices: []core.Choice{{ID: "sec_data_1_a"}, {ID: "sec_data_1_c"}},
+			ConfidenceScore: 0.85,
+		},
+		{
+			Question:        &core.WAFRQuestion{ID: "rel_backup_1", Pillar: core.PillarReliability, Title: "How do you back up data?"},
+			ConfidenceScore: 0.3,
+		},
+	}
+}
+
+func TestWriteProposedAnswers(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeProposedAnswers(&buf, proposedEvaluations()))
+
+	assert.Equal(t, strings.Join([]string{
+		"QUESTION      PILLAR       SELECTED CHOICES            CONFIDENCE",
+		"sec_data_1    security     sec_data_1_a, sec_data_1_c  0.85",
+		"rel_backup_1  reliability  (none)                      0.30",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestTerminalSubmissionConfirmer(t *testing.T) {
+	t.Run("submits when confirmed", func(t *testing.T) {
+		var out bytes.Buffer
+		confirmer := newTerminalSubmissionConfirmer(strings.NewReader("maybe\ny\n"), &out, false)
+
+		require.NoError(t, confirmer.ConfirmSubmission(context.Background(), proposedEvaluations()))
+		assert.Contains(t, out.String(), "2 proposed answers:")
+		assert.Contains(t, out.String(), "sec_data_1_a, sec_data_1_c")
+		assert.Contains(t, out.String(), `Invalid choice "maybe"`)
+	})
+
+	t.Run("aborts when declined", func(t *testing.T) {
+		confirmer := newTerminalSubmissionConfirmer(strings.NewReader("n\n"), &bytes.Buffer{}, false)
+
+		err := confirmer.ConfirmSubmission(context.Background(), proposedEvaluations())
+
+		assert.ErrorIs(t, err, core.ErrSubmissionAborted)
+	})
+
+	t.Run("aborts when input closes", func(t *testing.T) {
+		confirmer := newTerminalSubmissionConfirmer(strings.NewReader(""), &bytes.Buffer{}, false)
+
+		err := confirmer.ConfirmSubmission(context.Background(), proposedEvaluations())
+
+		assert.ErrorIs(t, err, core.ErrSubmissionAborted)
+		assert.Contains(t, err.Error(), "input closed")
+	})
+
+	t.Run("writes answers for offline review and aborts", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.json")
+		var out bytes.Buffer
+		confirmer := newTerminalSubmissionConfirmer(strings.NewReader("w\n"+path+"\n"), &out, false)
+
+		err := confirmer.ConfirmSubmission(context.Background(), proposedEvaluations())
+
+		assert.ErrorIs(t, err, core.ErrSubmissionAborted)
+		assert.Contains(t, out.String(), "Proposed answers written to "+path)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var written struct {
+			Answers []core.EvaluationOutput `json:"answers"`
+		}
+		require.NoError(t, json.Unmarshal(data, &written))
+		require.Len(t, written.Answers, 2)
+		assert.Equal(t, "sec_data_1", written.Answers[0].QuestionID)
+		assert.Equal(t, []string{"sec_data_1_a", "sec_data_1_c"}, written.Answers[0].SelectedChoices)
+		assert.Equal(t, 0.3, written.Answers[1].ConfidenceScore)
+	})
+
+	t.Run("yes submits without prompting", func(t *testing.T) {
+		var out bytes.Buffer
+		confirmer := newTerminalSubmissionConfirmer(strings.NewReader(""), &out, true)
+
+		require.NoError(t, confirmer.ConfirmSubmission(context.Background(), proposedEvaluations()))
+		assert.Contains(t, out.String(), "rel_backup_1")
+		assert.NotContains(t, out.String(), "Submit these answers")
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/wafr"
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage [dir]",
+	Short: "Report how much of the Terraform configuration a review can reason about",
+	Long: `Run IaC analysis and report how many resources have resolved properties,
+source locations and a mapping to a Well-Architected pillar. Resources with
+properties left as unevaluated expressions, without a source location or of a
+type no pillar maps are where evaluation will be weakest; each is listed.
+
+Only IaC analysis runs: no AWS credentials are needed and nothing is sent to
+Bedrock or the Well-Architected Tool. The directory defaults to --dir or the
+current directory.`,
+	Example: `  # Coverage of the current directory
+  waffle coverage
+
+  # JSON coverage of another directory
+  waffle coverage infra/ --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCoverage,
+}
+
+// analyzeCoverage runs the IaC analysis steps of a review and measures the
+// coverage of the extracted resources
+func analyzeCoverage(ctx context.Context, analyzer core.IaCAnalyzer) (*core.CoverageReport, error) {
+	resources, err := analyzeResources(ctx, analyzer)
+	if err != nil {
+		return nil, err
+	}
+	return core.ComputeCoverage(resources, wafr.ResourceTypePillars), nil
+}
+
+// writeCoverage writes report in format, which must be text or json
+func writeCoverage(w io.Writer, report *core.CoverageReport, format string) error {
+	switch format {
+	case core.CoverageFormatText:
+		return core.WriteCoverageText(w, report)
+	case core.CoverageFormatJSON:
+		return core.WriteCoverageJSON(w, report)
+	default:
+		return fmt.Errorf("unsupported coverage format %q: use %s or %s", format, core.CoverageFormatText, core.CoverageFormatJSON)
+	}
+}
+
+func runCoverage(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != core.CoverageFormatText && format != core.CoverageFormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q: must be %s or %s\n", format, core.CoverageFormatText, core.CoverageFormatJSON)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	var dir string
+	var err error
+	if len(args) == 1 {
+		dir, err = resolveDir(args[0])
+	} else {
+		dir, err = workingDir(cmd)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitDirectoryAccess)
+	}
+
+	analyzer, err := initializeIaCAnalyzer(ctx, nil, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize IaC analyzer: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	report, err := analyzeCoverage(ctx, analyzer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		handleReviewError(err)
+	}
+
+	logger.Info("coverage complete", "directory", dir, "resource_count", report.TotalResources,
+		"resolved", report.Resolved, "mapped", report.Mapped)
+	if err := writeCoverage(os.Stdout, report, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write coverage: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+)
+
+func TestAnalyzeCoverage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+variable "env" {}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "acme-logs"
+}
+
+resource "aws_sqs_queue" "jobs" {
+  name = "${var.env}-jobs"
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+`), 0644))
+
+	report, err := analyzeCoverage(context.Background(), iac.NewAnalyzerWithDir(dir))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.TotalResources)
+	assert.Equal(t, 1, report.Resolved)
+	assert.Equal(t, []string{"aws_sqs_queue.jobs"}, report.UnresolvedResources)
+	assert.Equal(t, 2, report.WithSourceLocation)
+	assert.Equal(t, 1, report.Mapped)
+	assert.Equal(t, []string{"aws_sqs_queue"}, report.UnmappedTypes)
+	assert.Equal(t, 1, report.ByPillar[core.PillarSecurity])
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCoverage(&buf, report, core.CoverageFormatText))
+	assert.Contains(t, buf.String(), "Coverage of 2 resources:")
+
+	assert.Error(t, writeCoverage(&buf, report, "xml"))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain how review results were derived",
+}
+
+var explainConfidenceCmd = &cobra.Command{
+	Use:   "confidence <session-id> <question-id>",
+	Short: "Explain the confidence score of an evaluated question",
+	Long: `Show how the confidence score of a question was derived: the confidence
+reported by the model and the data completeness factors (resource
+availability, evidence quality and source type) applied to it.`,
+	Example: `  waffle explain confidence abc123 sec_data_1`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    runExplainConfidence,
+}
+
+// findEvaluation returns the evaluation of questionID in a completed session
+func findEvaluation(session *core.ReviewSession, questionID string) (*core.QuestionEvaluation, error) {
+	if session.Results == nil {
+		return nil, fmt.Errorf("session %s has no results yet", session.SessionID)
+	}
+	for _, evaluation := range session.Results.Evaluations {
+		if evaluation.Question != nil && evaluation.Question.ID == questionID {
+			return evaluation, nil
+		}
+	}
+	return nil, fmt.Errorf("question %s was not evaluated in session %s", questionID, session.SessionID)
+}
+
+func runExplainConfidence(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+	sessionID, questionID := args[0], args[1]
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	sessionManager, err := initializeSessionManager(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize session manager: %v\n", err)
+		logger.Error("failed to initialize session manager", "error", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	session, err := sessionManager.LoadSession(ctx, sessionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: session not found: %v\n", err)
+		logger.Error("session not found", "session_id", sessionID, "error", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	evaluation, err := findEvaluation(session, questionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	if err := core.WriteConfidenceExplanation(os.Stdout, evaluation); err != nil {
+		if errors.Is(err, core.ErrNoConfidenceBreakdown) {
+			fmt.Fprintln(os.Stderr, "Error: this session was recorded without confidence factors; run the review again to explain its scores")
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(ExitGeneralError)
+	}
+
+	return nil
+}
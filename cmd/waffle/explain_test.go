@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestFindEvaluation(t *testing.T) {
+	evaluation := &core.QuestionEvaluation{Question: &core.WAFRQuestion{ID: "sec_1"}}
+	session := &core.ReviewSession{
+		SessionID: "sess-1",
+		Results: &core.ReviewResults{
+			Evaluations: []*core.QuestionEvaluation{
+				{Question: &core.WAFRQuestion{ID: "ops_1"}},
+				evaluation,
+			},
+		},
+	}
+
+	got, err := findEvaluation(session, "sec_1")
+	require.NoError(t, err)
+	assert.Same(t, evaluation, got)
+
+	_, err = findEvaluation(session, "rel_1")
+	assert.ErrorContains(t, err, "question rel_1 was not evaluated")
+
+	_, err = findEvaluation(&core.ReviewSession{SessionID: "sess-2"}, "sec_1")
+	assert.ErrorContains(t, err, "has no results")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/issues"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+var exportIssuesCmd = &cobra.Command{
+	Use:   "export-issues <session-id>",
+	Short: "Create tracker issues for high-priority improvement items",
+	Long: `Create a GitHub or Jira issue for each improvement item of a completed
+session whose priority is at least issues.min_priority (100 by default, which
+selects high risks). Each issue holds the item description, affected
+resources and best-practice references.
+
+The URLs of the created issues are printed and recorded in the session, so
+running the export again only creates issues for items not exported to that
+target yet. The repository, project and tokens are read from the issues
+section of the configuration.`,
+	Example: `  # GitHub issues for the high risks of a session
+  waffle export-issues abc123 --target github
+
+  # Jira issues for high and medium risks
+  waffle export-issues abc123 --target jira --min-priority 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportIssues,
+}
+
+// newIssueClient creates the client for an export target from the issues
+// configuration
+func newIssueClient(target string, cfg config.IssuesConfig) (issues.Client, []string, error) {
+	switch target {
+	case issues.TargetGitHub:
+		client, err := issues.NewGitHubClient(cfg.GitHub.APIURL, cfg.GitHub.Repository, cfg.GitHub.ResolveToken())
+		return client, cfg.GitHub.Labels, err
+	case issues.TargetJira:
+		client, err := issues.NewJiraClient(cfg.Jira.BaseURL, cfg.Jira.Project, cfg.Jira.IssueType, cfg.Jira.Email, cfg.Jira.ResolveToken())
+		return client, cfg.Jira.Labels, err
+	default:
+		return nil, nil, fmt.Errorf("invalid --target %q: must be %s or %s", target, issues.TargetGitHub, issues.TargetJira)
+	}
+}
+
+func runExportIssues(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+	sessionID := args[0]
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	target, _ := cmd.Flags().GetString("target")
+	minPriority := cfg.Issues.MinPriority
+	if cmd.Flags().Changed("min-priority") {
+		minPriority, _ = cmd.Flags().GetInt("min-priority")
+	}
+
+	client, labels, err := newIssueClient(target, cfg.Issues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	sessionManager, err := initializeSessionManager(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize session manager: %v\n", err)
+		logger.Error("failed to initialize session manager", "error", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	session, err := sessionManager.LoadSession(ctx, sessionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: session not found: %v\n", err)
+		logger.Error("session not found", "session_id", sessionID, "error", err)
+		os.Exit(ExitResourceNotFound)
+	}
+
+	created, exportErr := issues.Export(ctx, client, target, session, minPriority, labels)
+	// Issues created before a failure are recorded so they are not created
+	// again by the next run
+	if len(created) > 0 {
+		if err := sessionManager.SaveSession(ctx, session); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to record exported issues: %v\n", err)
+			os.Exit(ExitGeneralError)
+		}
+	}
+	for _, issue := range created {
+		fmt.Fprintf(os.Stdout, "%s\t%s\n", issue.ItemID, issue.URL)
+	}
+	if exportErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", exportErr)
+		os.Exit(ExitGeneralError)
+	}
+
+	logger.Info("issues exported", "session_id", sessionID, "target", target, "created", len(created))
+	fmt.Fprintf(os.Stderr, "Created %d %s issue(s)\n", len(created), target)
+	return nil
+}
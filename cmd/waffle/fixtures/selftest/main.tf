@@ -0,0 +1,22 @@
+# Workload reviewed by `waffle selftest`. The bucket is encrypted and the
+# database has no backup plan, so the self-test sees both a met and an unmet
+# practice.
+
+resource "aws_s3_bucket" "assets" {
+  bucket = "waffle-selftest-assets"
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "assets" {
+  bucket = aws_s3_bucket.assets.id
+
+  rule {
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "aws:kms"
+    }
+  }
+}
+
+resource "aws_db_instance" "main" {
+  engine         = "postgres"
+  instance_class = "db.t3.micro"
+}
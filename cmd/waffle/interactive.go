@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// errNotTerminal is returned when --interactive is used without a terminal
+var errNotTerminal = errors.New("--interactive requires a terminal on stdin and stderr")
+
+// errConfirmNotTerminal is returned when --confirm-submit is used without a
+// terminal and without --yes
+var errConfirmNotTerminal = errors.New("--confirm-submit requires a terminal on stdin and stderr, or --yes to submit without prompting")
+
+// isTerminal reports whether f is attached to a character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalAnswerReviewer prompts for the choices of low-confidence evaluations.
+// Prompts are written to out so that stdout stays reserved for JSON output.
+type terminalAnswerReviewer struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// newTerminalAnswerReviewer creates an answer reviewer reading selections from in
+func newTerminalAnswerReviewer(in io.Reader, out io.Writer) *terminalAnswerReviewer {
+	return &terminalAnswerReviewer{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// ReviewAnswer shows the question and suggested choices and reads a selection.
+// Invalid input is reported and prompted for again.
+func (r *terminalAnswerReviewer) ReviewAnswer(ctx context.Context, evaluation *core.QuestionEvaluation) ([]core.Choice, error) {
+	r.printQuestion(evaluation)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		fmt.Fprint(r.out, "Selection [Enter to accept, numbers separated by commas, or 'none']: ")
+		line, readErr := r.in.ReadString('\n')
+		if readErr == io.EOF && line == "" {
+			return nil, errors.New("input closed before a selection was made")
+		}
+		if readErr != nil && readErr != io.EOF {
+			return nil, fmt.Errorf("failed to read selection: %w", readErr)
+		}
+
+		choices, err := parseChoiceSelection(line, evaluation.Question.Choices, evaluation.SelectedChoices)
+		if err == nil {
+			return choices, nil
+		}
+		fmt.Fprintf(r.out, "Invalid selection: %v\n", err)
+
+		// A final unterminated line cannot be retried
+		if readErr == io.EOF {
+			return nil, err
+		}
+	}
+}
+
+// printQuestion writes the question, Bedrock's suggestion and the choice list
+func (r *terminalAnswerReviewer) printQuestion(evaluation *core.QuestionEvaluation) {
+	question := evaluation.Question
+	suggested := make(map[string]bool, len(evaluation.SelectedChoices))
+	for _, choice := range evaluation.SelectedChoices {
+		suggested[choice.ID] = true
+	}
+
+	fmt.Fprintf(r.out, "\n[%s] %s (%s)\n", question.ID, question.Title, question.Pillar)
+	if question.Description != "" {
+		fmt.Fprintf(r.out, "%s\n", question.Description)
+	}
+	fmt.Fprintf(r.out, "Suggested by Bedrock with confidence %.2f:\n", evaluation.ConfidenceScore)
+	if evaluation.Notes != "" {
+		fmt.Fprintf(r.out, "  %s\n", evaluation.Notes)
+	}
+	for i, choice := range question.Choices {
+		marker := " "
+		if suggested[choice.ID] {
+			marker = "*"
+		}
+		fmt.Fprintf(r.out, "  %s %d) %s\n", marker, i+1, choice.Title)
+	}
+}
+
+// parseChoiceSelection converts a line of user input into selected choices.
+// An empty line keeps the suggestion, "none" selects nothing, and otherwise
+// the input is a comma or space separated list of 1-based choice numbers.
+func parseChoiceSelection(input string, choices []core.Choice, suggested []core.Choice) ([]core.Choice, error) {
+	input = strings.TrimSpace(input)
+	switch strings.ToLower(input) {
+	case "":
+		return suggested, nil
+	case "none":
+		return []core.Choice{}, nil
+	}
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	selected := make([]core.Choice, 0, len(fields))
+	seen := make(map[int]bool, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a choice number", field)
+		}
+		if n < 1 || n > len(choices) {
+			return nil, fmt.Errorf("choice %d is out of range 1-%d", n, len(choices))
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		selected = append(selected, choices[n-1])
+	}
+
+	return selected, nil
+}
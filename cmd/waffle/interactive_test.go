@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+var interactiveChoices = []core.Choice{
+	{ID: "sec_1_a", Title: "Encrypt data at rest"},
+	{ID: "sec_1_b", Title: "Encrypt data in transit"},
+	{ID: "sec_1_c", Title: "Rotate keys"},
+}
+
+func TestParseChoiceSelection(t *testing.T) {
+	suggested := []core.Choice{interactiveChoices[0]}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []core.Choice
+		wantErr string
+	}{
+		{
+			name:  "empty accepts suggestion",
+			input: "\n",
+			want:  suggested,
+		},
+		{
+			name:  "none clears selection",
+			input: " NONE \n",
+			want:  []core.Choice{},
+		},
+		{
+			name:  "comma separated numbers",
+			input: "3,2",
+			want:  []core.Choice{interactiveChoices[2], interactiveChoices[1]},
+		},
+		{
+			name:  "space separated with duplicates",
+			input: "1 1  2",
+			want:  []core.Choice{interactiveChoices[0], interactiveChoices[1]},
+		},
+		{
+			name:    "not a number",
+			input:   "1,b",
+			wantErr: `"b" is not a choice number`,
+		},
+		{
+			name:    "out of range",
+			input:   "4",
+			wantErr: "out of range 1-3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChoiceSelection(tt.input, interactiveChoices, suggested)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTerminalAnswerReviewer(t *testing.T) {
+	evaluation := &core.QuestionEvaluation{
+		Question: &core.WAFRQuestion{
+			ID:      "sec_1",
+			Pillar:  core.PillarSecurity,
+			Title:   "How do you protect your data?",
+			Choices: interactiveChoices,
+		},
+		SelectedChoices: []core.Choice{interactiveChoices[1]},
+		ConfidenceScore: 0.42,
+	}
+
+	t.Run("re-prompts after invalid input", func(t *testing.T) {
+		var out bytes.Buffer
+		reviewer := newTerminalAnswerReviewer(strings.NewReader("7\n1,3\n"), &out)
+
+		choices, err := reviewer.ReviewAnswer(context.Background(), evaluation)
+
+		require.NoError(t, err)
+		assert.Equal(t, []core.Choice{interactiveChoices[0], interactiveChoices[2]}, choices)
+		assert.Contains(t, out.String(), "[sec_1] How do you protect your data? (security)")
+		assert.Contains(t, out.String(), "confidence 0.42")
+		assert.Contains(t, out.String(), "* 2) Encrypt data in transit")
+		assert.Contains(t, out.String(), "Invalid selection: choice 7 is out of range")
+	})
+
+	t.Run("accepts final line without newline", func(t *testing.T) {
+		reviewer := newTerminalAnswerReviewer(strings.NewReader("none"), &bytes.Buffer{})
+
+		choices, err := reviewer.ReviewAnswer(context.Background(), evaluation)
+
+		require.NoError(t, err)
+		assert.Empty(t, choices)
+	})
+
+	t.Run("fails when input closes", func(t *testing.T) {
+		reviewer := newTerminalAnswerReviewer(strings.NewReader(""), &bytes.Buffer{})
+
+		_, err := reviewer.ReviewAnswer(context.Background(), evaluation)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "input closed")
+	})
+}
+
+func TestIsTerminal_RegularFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "input"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, isTerminal(f))
+}
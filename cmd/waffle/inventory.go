@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory [dir]",
+	Short: "Export an inventory of the resources defined in Terraform",
+	Long: `List every managed resource found in the Terraform configuration of a
+directory with its address, type, provider, region, module, source file and
+scalar properties. Only IaC analysis runs: no AWS credentials are needed and
+nothing is sent to Bedrock or the Well-Architected Tool. Secrets are redacted
+as in a review.
+
+The directory defaults to --dir or the current directory.`,
+	Example: `  # CSV inventory of the current directory
+  waffle inventory > inventory.csv
+
+  # JSON inventory of another directory
+  waffle inventory infra/ --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInventory,
+}
+
+// analyzeInventory runs the IaC analysis steps of a review and returns the
+// resulting inventory. Resources whose provider configuration sets no region
+// are listed in defaultRegion.
+func analyzeInventory(ctx context.Context, analyzer core.IaCAnalyzer, defaultRegion string) ([]core.InventoryItem, error) {
+	model, resources, err := analyzeModel(ctx, analyzer)
+	if err != nil {
+		return nil, err
+	}
+	providerRegions, _ := model.Metadata["provider_regions"].(map[string]string)
+	return core.BuildInventory(resources, core.InventoryRegions{Providers: providerRegions, Default: defaultRegion}), nil
+}
+
+// analyzeResources runs the IaC analysis steps of a review and returns the
+// extracted resources
+func analyzeResources(ctx context.Context, analyzer core.IaCAnalyzer) ([]core.Resource, error) {
+	_, resources, err := analyzeModel(ctx, analyzer)
+	return resources, err
+}
+
+// analyzeModel runs the IaC analysis steps of a review and returns the parsed
+// workload model with the extracted resources
+func analyzeModel(ctx context.Context, analyzer core.IaCAnalyzer) (*core.WorkloadModel, []core.Resource, error) {
+	files, err := analyzer.RetrieveIaCFiles(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve IaC files: %w", err)
+	}
+	if err := analyzer.ValidateTerraformFiles(ctx, files); err != nil {
+		return nil, nil, fmt.Errorf("terraform validation failed: %w", err)
+	}
+
+	model, err := analyzer.ParseTerraform(ctx, files)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse terraform configuration: %w", err)
+	}
+	resources, err := analyzer.ExtractResources(ctx, model)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to extract resources: %w", err)
+	}
+	return model, resources, nil
+}
+
+// writeInventory writes items in format, which must be csv or json
+func writeInventory(w io.Writer, items []core.InventoryItem, format string) error {
+	switch format {
+	case core.InventoryFormatCSV:
+		return core.WriteInventoryCSV(w, items)
+	case core.InventoryFormatJSON:
+		return core.WriteInventoryJSON(w, items)
+	default:
+		return fmt.Errorf("unsupported inventory format %q: use %s or %s", format, core.InventoryFormatCSV, core.InventoryFormatJSON)
+	}
+}
+
+func runInventory(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != core.InventoryFormatCSV && format != core.InventoryFormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q: must be %s or %s\n", format, core.InventoryFormatCSV, core.InventoryFormatJSON)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	var dir string
+	var err error
+	if len(args) == 1 {
+		dir, err = resolveDir(args[0])
+	} else {
+		dir, err = workingDir(cmd)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitDirectoryAccess)
+	}
+
+	analyzer, err := initializeIaCAnalyzer(ctx, nil, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize IaC analyzer: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	// The configured AWS region applies to resources whose provider sets none.
+	// Only config files and the environment are read, not credentials.
+	var defaultRegion string
+	if cfg, err := loadConfigWithOverrides(cmd); err != nil {
+		logger.Warn("configuration not loaded, inventory regions come only from Terraform", "error", err)
+	} else {
+		defaultRegion = cfg.AWS.Region
+	}
+
+	items, err := analyzeInventory(ctx, analyzer, defaultRegion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		handleReviewError(err)
+	}
+
+	logger.Info("inventory complete", "directory", dir, "resource_count", len(items))
+	if err := writeInventory(os.Stdout, items, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write inventory: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	return nil
+}
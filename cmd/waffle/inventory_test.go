@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+)
+
+func TestAnalyzeInventory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+resource "aws_s3_bucket" "logs" {
+  bucket = "acme-logs"
+}
+
+resource "aws_db_instance" "main" {
+  engine   = "postgres"
+  password = "Sup3rS3cretPassw0rd!"
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+`), 0644))
+
+	analyzer := iac.NewAnalyzerWithDir(dir)
+	items, err := analyzeInventory(context.Background(), analyzer, "")
+	require.NoError(t, err)
+
+	// The rows are the resources a review would analyze, minus data sources
+	files, err := analyzer.RetrieveIaCFiles(context.Background())
+	require.NoError(t, err)
+	model, err := analyzer.ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+	var want []string
+	for _, resource := range model.Resources {
+		if !resource.IsDataSource() {
+			want = append(want, resource.Address)
+		}
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Address)
+	}
+	assert.ElementsMatch(t, want, got)
+	assert.Equal(t, []string{"aws_db_instance.main", "aws_s3_bucket.logs"}, got)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeInventory(&buf, items, core.InventoryFormatCSV))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "aws_db_instance.main", records[1][0])
+	assert.NotContains(t, buf.String(), "Sup3rS3cretPassw0rd!", "secrets are redacted")
+}
+
+func TestAnalyzeInventory_MissingDirectory(t *testing.T) {
+	_, err := analyzeInventory(context.Background(), iac.NewAnalyzerWithDir(filepath.Join(t.TempDir(), "missing")), "")
+
+	var dirErr *core.DirectoryAccessError
+	assert.ErrorAs(t, err, &dirErr)
+}
+
+func TestWriteInventory_UnsupportedFormat(t *testing.T) {
+	err := writeInventory(&bytes.Buffer{}, nil, "xml")
+	assert.ErrorContains(t, err, `unsupported inventory format "xml"`)
+}
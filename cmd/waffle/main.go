@@ -1,10 +1,14 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,9 +16,11 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/spf13/cobra"
 	"github.com/waffle/waffle/internal/bedrock"
+	"github.com/waffle/waffle/internal/cassette"
 	"github.com/waffle/waffle/internal/config"
 	"github.com/waffle/waffle/internal/core"
 	"github.com/waffle/waffle/internal/iac"
+	"github.com/waffle/waffle/internal/llm"
 	"github.com/waffle/waffle/internal/logging"
 	"github.com/waffle/waffle/internal/report"
 	"github.com/waffle/waffle/internal/session"
@@ -35,6 +41,10 @@ const (
 	ExitDirectoryAccess    = 3
 	ExitBedrockAPIError    = 4
 	ExitAnalysisIncomplete = 5
+	ExitBaselineExceeded   = 6
+	ExitStrictModeFailure  = 7
+	ExitPermissionDenied   = 8
+	ExitResourceNotFound   = 9
 )
 
 func main() {
@@ -42,10 +52,7 @@ func main() {
 	rootCmd.ParseFlags(os.Args[1:])
 
 	// Initialize logging with command-line overrides
-	logConfig := logging.DefaultConfig()
-	logConfig.Level = getLogLevel()
-
-	if err := logging.InitGlobalLogger(logConfig); err != nil {
+	if err := logging.InitGlobalLogger(newLogConfig(os.Args[1:])); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(ExitGeneralError)
 	}
@@ -58,7 +65,9 @@ func main() {
 		"date", date,
 	)
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	saveCassette()
+	if err != nil {
 		logger.Error("command execution failed", "error", err)
 		// Error is already printed by cobra
 		// Exit code is set by the command
@@ -68,6 +77,15 @@ func main() {
 	logger.Info("waffle completed successfully")
 }
 
+// saveCassette writes the AWS requests recorded with WAFFLE_RECORD. Review
+// and resume call it before choosing their exit code, since os.Exit skips
+// the call in main. Failures are logged and do not fail the command.
+func saveCassette() {
+	if err := cassette.Close(); err != nil {
+		logging.GetLogger().Warn("failed to save cassette", "error", err)
+	}
+}
+
 // getLogLevel returns the log level from command-line flags or environment variable
 func getLogLevel() logging.LogLevel {
 	// Check command-line flags first (if rootCmd is available)
@@ -108,28 +126,94 @@ func getLogLevel() logging.LogLevel {
 	}
 }
 
+// newLogConfig returns the logging configuration for the command line args
+func newLogConfig(args []string) *logging.Config {
+	logConfig := logging.DefaultConfig()
+	logConfig.Level = getLogLevel()
+	if noStatusRequested(args) {
+		// Only warnings and errors reach stderr; the log file keeps the rest
+		logConfig.ConsoleLevel = logging.LevelWarning
+	}
+	return logConfig
+}
+
+// noStatusRequested reports whether args set --no-status. Logging is set up
+// before cobra parses the subcommand's flags, so they are scanned directly.
+func noStatusRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--no-status" {
+			return true
+		}
+		if value, ok := strings.CutPrefix(arg, "--no-status="); ok {
+			enabled, err := strconv.ParseBool(value)
+			return err == nil && enabled
+		}
+	}
+	return false
+}
+
 // loadConfigWithOverrides loads configuration and applies command-line flag overrides
 func loadConfigWithOverrides(cmd *cobra.Command) (*config.Config, error) {
-	// Load base configuration
-	cfg, err := config.Load()
+	// Load base configuration, with the repository config of the analyzed directory
+	dir, err := workingDir(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg, err := config.LoadFromDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Apply command-line flag overrides
+	// Only the workload region; Bedrock moves with --bedrock-region
 	if region, _ := cmd.Flags().GetString("region"); region != "" {
 		cfg.AWS.Region = region
-		cfg.Bedrock.Region = region
+	}
+
+	// Bedrock model availability varies by region, so it can be moved
+	// without moving the workload
+	if bedrockRegion, _ := cmd.Flags().GetString("bedrock-region"); bedrockRegion != "" {
+		cfg.Bedrock.Region = bedrockRegion
 	}
 
 	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
 		cfg.AWS.Profile = profile
 	}
 
+	if fips, _ := cmd.Flags().GetBool("fips"); fips {
+		cfg.AWS.UseFIPS = true
+	}
+
 	if modelID, _ := cmd.Flags().GetString("model-id"); modelID != "" {
 		cfg.Bedrock.ModelID = modelID
 	}
 
+	if lensVersion, _ := cmd.Flags().GetString("lens-version"); lensVersion != "" {
+		cfg.WAFR.LensVersion = lensVersion
+	}
+
+	if err := applyModelParamFlags(cmd, &cfg.Bedrock); err != nil {
+		return nil, err
+	}
+	if err := applyDeterministicFlag(cmd, cfg); err != nil {
+		return nil, err
+	}
+
+	// Resolve secretsmanager:// references with the configured credentials
+	if config.HasSecretReferences(cfg) {
+		ctx := context.Background()
+		client, err := config.NewSecretsManagerClient(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Secrets Manager client: %w", err)
+		}
+		if err := config.ResolveSecrets(ctx, cfg, config.NewSecretResolver(client)); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -156,7 +240,19 @@ func init() {
 	rootCmd.AddCommand(reviewCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(resultsCmd)
+	resultsCmd.AddCommand(resultsVerifyCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(inventoryCmd)
+	rootCmd.AddCommand(coverageCmd)
+	rootCmd.AddCommand(whyResourceCmd)
+	rootCmd.AddCommand(selftestCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(exportIssuesCmd)
+	explainCmd.AddCommand(explainConfidenceCmd)
+	planCmd.AddCommand(planDiffCmd)
 }
 
 var reviewCmd = &cobra.Command{
@@ -182,6 +278,15 @@ Examples:
   terraform show -json > state.json
   waffle review --workload-id my-app --plan-file state.json
 
+  # Report properties whose declared value differs from the plan
+  waffle review --workload-id my-app --plan-file plan.json --report-drift
+
+  # Fail if resources were added to the configuration after the plan
+  waffle review --workload-id my-app --plan-file plan.json --require-fresh-plan
+
+  # Require a specific Well-Architected lens version
+  waffle review --workload-id my-app --lens-version 2024-06-27
+
   # Review with quiet output (errors only)
   waffle review --workload-id my-app --quiet
 
@@ -228,6 +333,11 @@ var resultsCmd = &cobra.Command{
 Results can be exported in multiple formats:
 - JSON: Machine-readable format with IaC evidence and confidence scores
 - PDF: Professional report generated by AWS Well-Architected Tool
+- consolidated-json: Risk counts per lens, pillar and question from the AWS
+  consolidated report
+- all: Every format above, written to --output-dir
+- table: Pillar risk counts and the top improvement items as aligned
+  columns, colored on a terminal unless --no-color or NO_COLOR is set
 
 Examples:
   # Get results as JSON to stdout
@@ -236,9 +346,27 @@ Examples:
   # Get results as JSON to file
   waffle results abc123-def456-789 --format json --output results.json
 
+  # Get gzip-compressed JSON results (implied by a .gz output path)
+  waffle results abc123-def456-789 --output results.json.gz
+
   # Get results as PDF
-  waffle results abc123-def456-789 --format pdf --output report.pdf`,
-	Args: cobra.ExactArgs(1),
+  waffle results abc123-def456-789 --format pdf --output report.pdf
+
+  # Get the AWS consolidated report metrics as JSON
+  waffle results abc123-def456-789 --format consolidated-json
+
+  # Write every report format into a directory
+  waffle results abc123-def456-789 --format all --output-dir reports/
+
+  # Show pillar risk counts and the top improvements in the terminal
+  waffle results abc123-def456-789 --format table
+
+  # Check a saved results file against the current JSON schema version
+  waffle results --validate-schema results.json
+
+  # Fail when new results regress against known-good results
+  waffle results verify --expected good.json --actual new.json`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runResults,
 }
 
@@ -269,24 +397,127 @@ Examples:
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().String("region", "", "AWS region for Bedrock and WAFR (overrides config file and AWS_REGION)")
+	rootCmd.PersistentFlags().String("region", "", "AWS region for WAFR and other AWS clients, not Bedrock (overrides config file and AWS_REGION)")
+	rootCmd.PersistentFlags().String("bedrock-region", "", "AWS region for Bedrock (overrides bedrock.region in the config file)")
+	rootCmd.PersistentFlags().Bool("fips", false, "Use FIPS endpoints for Bedrock, the Well-Architected Tool and STS")
 	rootCmd.PersistentFlags().String("profile", "", "AWS profile to use (overrides config file and AWS_PROFILE)")
 	rootCmd.PersistentFlags().String("model-id", "", "Bedrock model ID to use for analysis (overrides config file and environment variables)")
+	rootCmd.PersistentFlags().Float64("temperature", 0, "Model sampling temperature between 0 and 1 for this run (overrides bedrock.temperature)")
+	rootCmd.PersistentFlags().Float64("top-p", 0, "Model nucleus sampling top_p between 0 and 1 for this run (overrides bedrock.top_p)")
+	rootCmd.PersistentFlags().Bool("deterministic", false, "Minimize run-to-run variance: temperature 0, top_p 1, resources ordered by address and answers submitted one at a time")
+	rootCmd.PersistentFlags().Int("max-tokens", 0, "Most tokens the model may generate per response for this run (overrides bedrock.max_tokens)")
 	rootCmd.PersistentFlags().String("log-level", "", "Log level: DEBUG, INFO, WARNING, ERROR (overrides config file and WAFFLE_LOG_LEVEL)")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Quiet mode - only show errors (equivalent to --log-level ERROR)")
+	rootCmd.PersistentFlags().StringP("dir", "C", "", "Analyze the Terraform in this directory instead of the current directory")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose mode - show debug information (equivalent to --log-level DEBUG)")
 
 	// Review command flags
 	reviewCmd.Flags().String("workload-id", "", "Workload identifier (required)")
 	reviewCmd.Flags().String("plan-file", "", "Path to Terraform JSON file (plan or state, alternative to HCL analysis)")
+	reviewCmd.Flags().String("state-source", "", "Terraform state to review instead of HCL: a state JSON file or tfc://<organization>/<workspace>")
 	reviewCmd.Flags().String("scope", "workload", "Review scope: workload, pillar, or question")
 	reviewCmd.Flags().String("pillar", "", "Specific pillar when scope is pillar (operationalExcellence, security, reliability, performance, costOptimization, sustainability)")
 	reviewCmd.Flags().String("question-id", "", "Specific question ID when scope is question")
+	reviewCmd.Flags().String("session-id", "", "Use this session ID instead of a generated one (letters, digits, '.', '_' and '-')")
+	reviewCmd.Flags().String("correlation-id", "", "External correlation ID (e.g. CI pipeline run ID) recorded with the session")
+	reviewCmd.Flags().String("lens-version", "", "Require the workload to use this Well-Architected lens version (overrides config file)")
+	reviewCmd.Flags().Bool("report-drift", false, "Compare Terraform configuration with the plan file and report property drift")
+	reviewCmd.Flags().Bool("require-fresh-plan", false, "Fail the review when the plan file is missing resources declared in the Terraform configuration")
+	reviewCmd.Flags().Bool("interactive", false, "Confirm or override the choices of low-confidence answers before they are submitted")
+	reviewCmd.Flags().Float64("interactive-threshold", 0, "Confidence below which --interactive prompts (defaults to risk.risk_confidence_threshold)")
+	reviewCmd.Flags().Bool("confirm-submit", false, "Show all proposed answers and confirm once before any is submitted")
+	reviewCmd.Flags().Bool("yes", false, "With --confirm-submit, show the proposed answers and submit them without prompting")
+	reviewCmd.Flags().String("graph-output", "", "Write the resource dependency graph to this file")
+	reviewCmd.Flags().String("graph-format", core.GraphFormatJSON, "Resource graph format for --graph-output: json or dot")
+	reviewCmd.Flags().String("emit-plan-json", "", "Write the analyzed resources to this file as Terraform plan JSON")
+	reviewCmd.Flags().Bool("allow-empty", false, "Continue the review when no Terraform resources are found")
+	reviewCmd.Flags().Bool("strict", false, "Fail before evaluation if any secret was redacted or any Terraform content could not be parsed")
+	reviewCmd.Flags().Int("max-questions", 0, "Evaluate at most this many questions; the review is marked partial (0 evaluates all)")
+	reviewCmd.Flags().StringArray("context-file", nil, "Text or markdown file to give the model as supplementary context (repeatable)")
+	reviewCmd.Flags().String("answers-override", "", "YAML file mapping question IDs to the choices to select instead of asking Bedrock")
+	reviewCmd.Flags().String("questions-file", "", "JSON question catalog to evaluate instead of retrieving questions from the Well-Architected Tool")
+	reviewCmd.Flags().String("baseline", "", "Compare pillar risk counts with this baseline JSON file and fail when they exceed it")
+	reviewCmd.Flags().StringArray("advisory-pillar", nil, "Report the risks of this pillar without holding it to --baseline (repeatable)")
+	reviewCmd.Flags().Bool("calibration", false, "Add the distribution of confidence scores in 0.1 bands and the average per pillar to the output")
+	reviewCmd.Flags().Bool("coverage-matrix", false, "Add the questions each resource was cited as evidence for, and the resources cited by none, to the output")
+	reviewCmd.Flags().Bool("preserve-answers", false, "Leave questions already answered in the Well-Architected Tool as they are and evaluate only unanswered ones")
+	reviewCmd.Flags().Bool("overwrite", false, "With --preserve-answers, evaluate answered questions too, keeping their notes ahead of Waffle's")
+	reviewCmd.Flags().Bool("cleanup", false, "Delete the AWS workload after the review if Waffle created it")
+	reviewCmd.Flags().Bool("no-milestone", false, "Do not create a milestone at the end of the review (same as wafr.create_milestone: false)")
+	reviewCmd.Flags().String("milestone-name", "", "Name of the milestone created after the review, instead of wafr.milestone_name_template")
+	reviewCmd.Flags().Bool("no-question-cache", false, "Retrieve the WAFR questions from AWS instead of reusing cached ones (same as wafr.question_cache_ttl_hours: 0)")
+	reviewCmd.Flags().Bool("no-status", false, "Suppress status, progress and INFO log lines on stderr, leaving only the JSON output, warnings and errors")
 	reviewCmd.MarkFlagRequired("workload-id")
 
 	// Results command flags
-	resultsCmd.Flags().String("format", "json", "Output format: json or pdf")
+	resultsCmd.Flags().String("format", "json", fmt.Sprintf("Output format: %s, %s, or %s", strings.Join(report.DefaultRegistry().Names(), ", "), formatTable, formatAll))
+	resultsCmd.Flags().Bool("no-color", false, "Do not color --format table output, even on a terminal")
 	resultsCmd.Flags().String("output", "", "Output file path (optional, defaults to stdout for JSON)")
+	resultsCmd.Flags().String("output-dir", ".", "Directory for report files when --format is all")
+	resultsCmd.Flags().Bool("compress", false, "Gzip-compress JSON output, including the JSON reports of --format all (implied when --output ends in .gz)")
+	resultsCmd.Flags().Bool("offline", false, "Render reports from the stored session only and fail if a format needs AWS")
+	resultsCmd.Flags().String("validate-schema", "", "Validate a saved results JSON file against the current schema version")
+
+	// Results verify command flags
+	resultsVerifyCmd.Flags().String("expected", "", "Known-good results JSON file")
+	resultsVerifyCmd.Flags().String("actual", "", "Results JSON file to check")
+	resultsVerifyCmd.Flags().Float64("tolerance", core.DefaultConfidenceTolerance, "How far a confidence score may drop before it is a regression")
+	resultsVerifyCmd.MarkFlagRequired("expected")
+	resultsVerifyCmd.MarkFlagRequired("actual")
+
+	// Inventory command flags
+	inventoryCmd.Flags().String("format", core.InventoryFormatCSV, "Output format: csv or json")
+
+	// Coverage command flags
+	coverageCmd.Flags().String("format", core.CoverageFormatText, "Output format: text or json")
+
+	// Why-resource command flags
+	whyResourceCmd.Flags().String("format", whyResourceFormatText, "Output format: text or json")
+
+	// Plan command flags
+	planCmd.Flags().String("workload-id", "", "Name of the reviewed workload, instead of a session ID")
+	planCmd.Flags().String("format", core.PlanFormatText, "Output format: text or json")
+
+	// Plan diff command flags
+	planDiffCmd.Flags().String("format", core.PlanDiffFormatText, "Output format: text or json")
+
+	// Export issues command flags
+	exportIssuesCmd.Flags().String("target", "", "Issue tracker: github or jira")
+	exportIssuesCmd.Flags().Int("min-priority", 0, "Lowest item priority exported (overrides issues.min_priority)")
+	exportIssuesCmd.MarkFlagRequired("target")
+
+	resumeCmd.Flags().String("from-checkpoint", "", "Rewind the session to this checkpoint and re-run every later step")
+
+	// Serve command flags
+	serveCmd.Flags().String("listen", "", "Address to serve metrics on (overrides metrics.listen_address)")
+}
+
+// validateResultsSchema checks the schema version of a saved results file,
+// transparently decompressing gzip files
+func validateResultsSchema(path string) error {
+	return readResultsFile(path, core.ValidateSchemaVersion)
+}
+
+// readResultsFile calls read with the content of a saved results file,
+// decompressed when its name ends in .gz
+func readResultsFile(path string, read func(io.Reader) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	return read(r)
 }
 
 // runReview executes the review command
@@ -297,9 +528,39 @@ func runReview(cmd *cobra.Command, args []string) error {
 	// Get flags
 	workloadID, _ := cmd.Flags().GetString("workload-id")
 	planFile, _ := cmd.Flags().GetString("plan-file")
+	stateSource, _ := cmd.Flags().GetString("state-source")
 	scopeStr, _ := cmd.Flags().GetString("scope")
 	pillarStr, _ := cmd.Flags().GetString("pillar")
 	questionID, _ := cmd.Flags().GetString("question-id")
+	customSessionID, _ := cmd.Flags().GetString("session-id")
+	correlationID, _ := cmd.Flags().GetString("correlation-id")
+	reportDrift, _ := cmd.Flags().GetBool("report-drift")
+	requireFreshPlan, _ := cmd.Flags().GetBool("require-fresh-plan")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	interactiveThreshold, _ := cmd.Flags().GetFloat64("interactive-threshold")
+	confirmSubmit, _ := cmd.Flags().GetBool("confirm-submit")
+	assumeYes, _ := cmd.Flags().GetBool("yes")
+	graphOutput, _ := cmd.Flags().GetString("graph-output")
+	graphFormat, _ := cmd.Flags().GetString("graph-format")
+	emitPlanJSON, _ := cmd.Flags().GetString("emit-plan-json")
+	noStatus, _ := cmd.Flags().GetBool("no-status")
+	allowEmpty, _ := cmd.Flags().GetBool("allow-empty")
+	strict, _ := cmd.Flags().GetBool("strict")
+	deterministic, _ := cmd.Flags().GetBool("deterministic")
+	cleanup, _ := cmd.Flags().GetBool("cleanup")
+	noMilestone, _ := cmd.Flags().GetBool("no-milestone")
+	milestoneName, _ := cmd.Flags().GetString("milestone-name")
+	noQuestionCache, _ := cmd.Flags().GetBool("no-question-cache")
+	maxQuestions, _ := cmd.Flags().GetInt("max-questions")
+	baselineFile, _ := cmd.Flags().GetString("baseline")
+	advisoryPillarFlags, _ := cmd.Flags().GetStringArray("advisory-pillar")
+	calibration, _ := cmd.Flags().GetBool("calibration")
+	coverageMatrix, _ := cmd.Flags().GetBool("coverage-matrix")
+	preserveAnswers, _ := cmd.Flags().GetBool("preserve-answers")
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	contextFiles, _ := cmd.Flags().GetStringArray("context-file")
+	answersOverrideFile, _ := cmd.Flags().GetString("answers-override")
+	questionsFile, _ := cmd.Flags().GetString("questions-file")
 
 	// Validate workload ID
 	if workloadID == "" {
@@ -307,37 +568,150 @@ func runReview(cmd *cobra.Command, args []string) error {
 		os.Exit(ExitInvalidArguments)
 	}
 
+	if err := checkReviewFlagConflicts(cmd); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", formatJoinedError("conflicting flags", err))
+		os.Exit(ExitInvalidArguments)
+	}
+
 	// Parse and validate scope
 	scope, err := parseReviewScope(scopeStr, pillarStr, questionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", formatScopeError(err))
+		os.Exit(ExitInvalidArguments)
+	}
+
+	// Prompts need a person at a terminal
+	if interactive && (!isTerminal(os.Stdin) || !isTerminal(os.Stderr)) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", errNotTerminal)
+		os.Exit(ExitInvalidArguments)
+	}
+	if confirmSubmit && !assumeYes && (!isTerminal(os.Stdin) || !isTerminal(os.Stderr)) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", errConfirmNotTerminal)
+		os.Exit(ExitInvalidArguments)
+	}
+	if interactiveThreshold < 0 || interactiveThreshold > 1 {
+		fmt.Fprintln(os.Stderr, "Error: --interactive-threshold must be between 0 and 1")
+		os.Exit(ExitInvalidArguments)
+	}
+	if err := core.ValidateGraphFormat(graphFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInvalidArguments)
+	}
+	if maxQuestions < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --max-questions must not be negative")
+		os.Exit(ExitInvalidArguments)
+	}
+	// The milestone name is checked before the review so an invalid name
+	// fails without spending model calls
+	if milestoneName != "" {
+		if err := core.ValidateMilestoneName(milestoneName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArguments)
+		}
+	}
+	advisoryPillars, err := parseAdvisoryPillars(advisoryPillarFlags)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(ExitInvalidArguments)
 	}
+	// A state source is parsed like a plan file; workspace references are
+	// fetched when the IaC analysis runs
+	if stateSource != "" {
+		if _, _, err := iac.ParseStateReference(stateSource); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArguments)
+		}
+		planFile = stateSource
+	}
 
-	// Validate scope
-	if err := scope.Validate(); err != nil {
+	// Read the baseline and context files up front so a bad file fails
+	// before the review runs
+	var baseline *core.Baseline
+	if baselineFile != "" {
+		baseline, err = loadBaseline(baselineFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArguments)
+		}
+	}
+	contextDocuments, err := iac.ReadContextFiles(ctx, contextFiles)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(ExitInvalidArguments)
 	}
+	var answerOverrides core.AnswerOverrides
+	if answersOverrideFile != "" {
+		answerOverrides, err = config.LoadAnswerOverrides(answersOverrideFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArguments)
+		}
+	}
+	var questionCatalog *core.QuestionCatalog
+	if questionsFile != "" {
+		questionCatalog, err = config.LoadQuestionCatalog(questionsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArguments)
+		}
+	}
 
-	// Get current directory
-	currentDir, err := os.Getwd()
+	// Attach caller-supplied identifiers to the logs; the session ID is also
+	// handed to the engine and the correlation ID recorded from the context
+	if customSessionID != "" {
+		if err := session.ValidateSessionID(customSessionID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArguments)
+		}
+		ctx = logging.WithSessionID(ctx, customSessionID)
+	}
+	if correlationID != "" {
+		ctx = logging.WithCorrelationID(ctx, correlationID)
+	}
+
+	// Resolve the directory to analyze
+	currentDir, err := workingDir(cmd)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to get current directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(ExitDirectoryAccess)
 	}
 
+	// Status lines and progress go to stderr unless --no-status is set
+	progress := newStatusReporter(os.Stderr, noStatus)
+
 	// Display initial information
-	fmt.Fprintf(os.Stderr, "Starting WAFR review...\n")
-	fmt.Fprintf(os.Stderr, "Workload ID: %s\n", workloadID)
-	fmt.Fprintf(os.Stderr, "Directory: %s\n", currentDir)
-	fmt.Fprintf(os.Stderr, "Scope: %s\n", formatScope(scope))
-	if planFile != "" {
-		fmt.Fprintf(os.Stderr, "Analysis: Terraform JSON file (%s)\n", planFile)
+	progress.Statusf("Starting WAFR review...\n")
+	progress.Statusf("Workload ID: %s\n", workloadID)
+	progress.Statusf("Directory: %s\n", currentDir)
+	progress.Statusf("Scope: %s\n", formatScope(scope))
+	if stateSource != "" {
+		progress.Statusf("Analysis: Terraform state (%s)\n", stateSource)
+	} else if planFile != "" {
+		progress.Statusf("Analysis: Terraform JSON file (%s)\n", planFile)
 	} else {
-		fmt.Fprintf(os.Stderr, "Analysis: Terraform configuration files (.tf)\n")
+		progress.Statusf("Analysis: Terraform configuration files (.tf)\n")
 	}
-	fmt.Fprintf(os.Stderr, "\n")
+	if maxQuestions > 0 {
+		progress.Statusf("Question cap: %d (partial review)\n", maxQuestions)
+	}
+	if baselineFile != "" {
+		progress.Statusf("Baseline: %s\n", baselineFile)
+	}
+	if len(advisoryPillars) > 0 {
+		progress.Statusf("Advisory pillars: %s\n", formatPillars(advisoryPillars))
+	}
+	if preserveAnswers && overwrite {
+		progress.Statusf("Existing answers: overwritten, notes kept\n")
+	} else if preserveAnswers {
+		progress.Statusf("Existing answers: preserved\n")
+	}
+	for _, path := range contextFiles {
+		progress.Statusf("Context file: %s\n", path)
+	}
+	if answersOverrideFile != "" {
+		progress.Statusf("Answer overrides: %s (%d questions)\n", answersOverrideFile, len(answerOverrides))
+	}
+	progress.Statusf("\n")
 
 	// Load configuration with command-line overrides
 	cfg, err := loadConfigWithOverrides(cmd)
@@ -345,82 +719,128 @@ func runReview(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(ExitGeneralError)
 	}
+	if noMilestone {
+		cfg.WAFR.CreateMilestone = false
+	}
+	if noQuestionCache {
+		cfg.WAFR.QuestionCacheTTLHours = 0
+	}
 
-	// Initialize dependencies
-	logger.Info("initializing dependencies")
-	engine, err := initializeEngine(ctx, cfg)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize engine: %v\n", err)
-		logger.Error("failed to initialize engine", "error", err)
-		os.Exit(ExitGeneralError)
+	// Drift is measured between the configuration and a plan
+	if reportDrift && planFile == "" && cfg.IaC.PlanFilePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --report-drift requires a plan file (--plan-file or iac.plan_file_path)")
+		os.Exit(ExitInvalidArguments)
+	}
+	if requireFreshPlan && planFile == "" && cfg.IaC.PlanFilePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --require-fresh-plan requires a plan file (--plan-file or iac.plan_file_path)")
+		os.Exit(ExitInvalidArguments)
 	}
 
-	// Create progress reporter
-	progress := core.NewCLIProgressReporter(os.Stderr)
+	// Check permissions before the review creates anything in AWS
+	preflightCtx, cancelPreflight := context.WithTimeout(ctx, 15*time.Second)
+	if simulator, err := config.NewPolicySimulator(preflightCtx, cfg); err != nil {
+		logger.Warn("skipping permission preflight", "error", err)
+	} else if err := preflightPermissions(preflightCtx, simulator, cfg.RequiredActions(), progress); err != nil {
+		cancelPreflight()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitPermissionDenied)
+	}
+	cancelPreflight()
 
-	// Initiate review
-	logger.Info("initiating review", "workload_id", workloadID)
-	session, err := engine.InitiateReview(ctx, workloadID, scope)
+	// Initialize dependencies
+	logger.Info("initializing dependencies")
+	workloadMetadata, err := config.LoadWorkloadMetadata(currentDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initiate review: %v\n", err)
-		logger.Error("failed to initiate review", "error", err)
-		handleReviewError(err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInvalidArguments)
 	}
-
-	// Set plan file path from flag or configuration
-	if planFile != "" {
-		session.PlanFilePath = planFile
-	} else if cfg.IaC.PlanFilePath != "" {
-		session.PlanFilePath = cfg.IaC.PlanFilePath
+	if workloadMetadata != nil {
+		progress.Statusf("Workload metadata: %s\n\n", core.WorkloadMetadataFileName)
 	}
 
-	logger.Info("executing review", "session_id", session.SessionID)
-
-	// Execute review with progress reporting
-	results, err := engine.ExecuteReviewWithProgress(ctx, session, progress)
+	engine, err := initializeEngine(ctx, cfg, currentDir, workloadMetadata)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: review execution failed: %v\n", err)
-		logger.Error("review execution failed",
-			"session_id", session.SessionID,
-			"error", err,
-		)
-		handleReviewError(err)
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize engine: %v\n", err)
+		logger.Error("failed to initialize engine", "error", err)
+		os.Exit(ExitGeneralError)
 	}
 
-	logger.Info("review completed successfully",
-		"session_id", session.SessionID,
-		"questions_evaluated", len(results.Evaluations),
-	)
-
-	// Output JSON for CI/CD integration
-	reviewOutput := &core.ReviewOutput{
-		SessionID:  session.SessionID,
-		WorkloadID: workloadID,
-		Status:     string(session.Status),
-		CreatedAt:  session.CreatedAt,
-		Summary: &core.ReviewSummaryOutput{
-			QuestionsEvaluated:  results.Summary.QuestionsEvaluated,
-			HighRisks:           results.Summary.HighRisks,
-			MediumRisks:         results.Summary.MediumRisks,
-			AverageConfidence:   results.Summary.AverageConfidence,
-			ImprovementPlanSize: results.Summary.ImprovementPlanSize,
-		},
-		Metadata: map[string]interface{}{
-			"scope":           formatScope(scope),
-			"directory":       currentDir,
-			"aws_workload_id": session.AWSWorkloadID,
-			"milestone_id":    session.MilestoneID,
-		},
+	engine.SetAllowEmptyWorkload(allowEmpty)
+	engine.SetStrict(strict)
+	engine.SetSessionID(customSessionID)
+	engine.SetFixedResourceOrder(deterministic)
+	engine.SetCleanupWorkload(cleanup)
+	engine.SetMaxQuestions(maxQuestions)
+	engine.SetContextDocuments(contextDocuments)
+	engine.SetAdvisoryPillars(advisoryPillars)
+	if preserveAnswers {
+		engine.SetPreserveAnswers(overwrite)
 	}
-
-	if planFile != "" {
-		reviewOutput.Metadata["plan_file"] = planFile
+	if answersOverrideFile != "" {
+		engine.SetAnswerOverrides(answerOverrides, filepath.Base(answersOverrideFile))
+	}
+	if questionCatalog != nil {
+		engine.SetQuestionCatalog(questionCatalog, questionsFile)
+	}
+	if milestoneName != "" {
+		engine.SetMilestoneNameOptions(core.MilestoneNameOptions{
+			Template: cfg.WAFR.MilestoneNameTemplate,
+			Name:     milestoneName,
+		})
 	}
 
-	if err := core.WriteJSON(os.Stdout, reviewOutput); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to write JSON output: %v\n", err)
-		logger.Error("failed to write JSON output", "error", err)
-		os.Exit(ExitGeneralError)
+	if interactive {
+		if interactiveThreshold == 0 {
+			interactiveThreshold = cfg.Risk.RiskConfidenceThreshold
+		}
+		engine.SetAnswerReviewer(newTerminalAnswerReviewer(os.Stdin, os.Stderr), interactiveThreshold)
+	}
+	if confirmSubmit {
+		engine.SetSubmissionConfirmer(newTerminalSubmissionConfirmer(os.Stdin, os.Stderr, assumeYes))
+	}
+
+	// Resolve plan file path from flag or configuration
+	if planFile == "" {
+		planFile = cfg.IaC.PlanFilePath
+	}
+
+	req := reviewRequest{
+		WorkloadID:       workloadID,
+		Directory:        currentDir,
+		PlanFile:         planFile,
+		Scope:            scope,
+		ReportDrift:      reportDrift,
+		GraphOutput:      graphOutput,
+		GraphFormat:      graphFormat,
+		EmitPlanJSON:     emitPlanJSON,
+		WorkloadMetadata: workloadMetadata,
+		Baseline:         baseline,
+		BaselineFile:     baselineFile,
+		Calibration:      calibration,
+		CoverageMatrix:   coverageMatrix,
+		ModelParams:      newModelParams(cfg, deterministic),
+		RequireFreshPlan: requireFreshPlan,
+	}
+	err = runReviewWorkflow(ctx, engine, req, progress, os.Stdout)
+	saveMetricsSnapshot(cfg)
+	saveCassette()
+	if err != nil {
+		progress.Stop()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if errors.Is(err, core.ErrEmptyWorkloadModel) {
+			fmt.Fprintln(os.Stderr, "Run the review from a directory with Terraform resource blocks, pass --plan-file, or use --allow-empty to review anyway")
+		}
+		if errors.Is(err, core.ErrStalePlan) {
+			fmt.Fprintln(os.Stderr, "Run terraform plan again and export it with terraform show -json before reviewing")
+		}
+		var strictErr *core.StrictModeError
+		if errors.As(err, &strictErr) {
+			fmt.Fprintln(os.Stderr, formatStrictModeWarnings(strictErr))
+		}
+		if errors.Is(err, core.ErrSubmissionAborted) {
+			fmt.Fprintln(os.Stderr, "No answers were submitted. Run 'waffle resume <session-id>' to submit the evaluated answers later")
+		}
+		handleReviewError(err)
 	}
 
 	return nil
@@ -470,6 +890,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 	if session.Results != nil && session.Results.Summary != nil {
 		fmt.Fprintf(os.Stderr, "  Questions Evaluated: %d\n", session.Results.Summary.QuestionsEvaluated)
+		if session.Results.Summary.Partial {
+			fmt.Fprintf(os.Stderr, "  Partial: %d questions not evaluated\n", session.Results.Summary.QuestionsSkipped)
+		}
+		if n := len(session.Results.Summary.TimedOutQuestions); n > 0 {
+			fmt.Fprintf(os.Stderr, "  Timed Out: %d questions\n", n)
+		}
 		fmt.Fprintf(os.Stderr, "  High Risks: %d\n", session.Results.Summary.HighRisks)
 		fmt.Fprintf(os.Stderr, "  Medium Risks: %d\n", session.Results.Summary.MediumRisks)
 	}
@@ -477,11 +903,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	// Build status output
 	statusOutput := &core.StatusOutput{
-		SessionID:  sessionID,
-		WorkloadID: session.WorkloadID,
-		Status:     string(session.Status),
-		CreatedAt:  session.CreatedAt,
-		UpdatedAt:  session.UpdatedAt,
+		SchemaVersion: core.SchemaVersion,
+		SessionID:     sessionID,
+		WorkloadID:    session.WorkloadID,
+		Status:        string(session.Status),
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
 		Metadata: map[string]interface{}{
 			"scope":           formatScope(session.Scope),
 			"aws_workload_id": session.AWSWorkloadID,
@@ -496,14 +923,20 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		statusOutput.Metadata["plan_file"] = session.PlanFilePath
 	}
 
+	if session.CorrelationID != "" {
+		statusOutput.Metadata["correlation_id"] = session.CorrelationID
+	}
+
+	if session.LensVersion != "" {
+		statusOutput.Metadata["lens_version"] = session.LensVersion
+	}
+
+	if len(session.FailedPillars) > 0 {
+		statusOutput.Metadata["failed_pillars"] = session.FailedPillars
+	}
+
 	if session.Results != nil && session.Results.Summary != nil {
-		statusOutput.Metadata["summary"] = &core.ReviewSummaryOutput{
-			QuestionsEvaluated:  session.Results.Summary.QuestionsEvaluated,
-			HighRisks:           session.Results.Summary.HighRisks,
-			MediumRisks:         session.Results.Summary.MediumRisks,
-			AverageConfidence:   session.Results.Summary.AverageConfidence,
-			ImprovementPlanSize: session.Results.Summary.ImprovementPlanSize,
-		}
+		statusOutput.Metadata["summary"] = core.ConvertResultsSummaryToOutput(session.Results.Summary)
 	}
 
 	// Output JSON
@@ -521,13 +954,71 @@ func runStatus(cmd *cobra.Command, args []string) error {
 func runResults(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	logger := logging.GetLogger()
+
+	// Schema validation mode works on a saved file and needs no session
+	if schemaFile, _ := cmd.Flags().GetString("validate-schema"); schemaFile != "" {
+		if err := validateResultsSchema(schemaFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", schemaFile, err)
+			os.Exit(ExitGeneralError)
+		}
+		fmt.Fprintf(os.Stderr, "%s matches schema version %s\n", schemaFile, core.SchemaVersion)
+		return nil
+	}
+
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: session ID is required\n")
+		os.Exit(ExitInvalidArguments)
+	}
 	sessionID := args[0]
 	format, _ := cmd.Flags().GetString("format")
 	outputPath, _ := cmd.Flags().GetString("output")
+	compress, _ := cmd.Flags().GetBool("compress")
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	offline, _ := cmd.Flags().GetBool("offline")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+
+	// Validate format against the registered report formats
+	registry := report.DefaultRegistry()
+	var reportFormat report.Format
+	var formats []report.Format
+	if format == formatAll {
+		if outputPath != "" {
+			fmt.Fprintf(os.Stderr, "Error: --output cannot be used with --format all, use --output-dir\n")
+			os.Exit(ExitInvalidArguments)
+		}
+		formats = registry.Formats()
+		if offline {
+			var skipped []report.Format
+			formats, skipped = offlineFormats(formats)
+			for _, f := range skipped {
+				fmt.Fprintf(os.Stderr, "Skipping %s report: generated by AWS\n", f.Name)
+			}
+		}
+	} else if format != formatTable {
+		f, err := registry.Lookup(format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid format '%s', must be one of: %s, %s, %s\n",
+				format, strings.Join(registry.Names(), ", "), formatTable, formatAll)
+			os.Exit(ExitInvalidArguments)
+		}
+		if offline && f.RequiresAWS {
+			fmt.Fprintf(os.Stderr, "Error: %s %v\n", f.Name, errFormatRequiresAWS)
+			os.Exit(ExitInvalidArguments)
+		}
+		reportFormat = f
+		formats = []report.Format{f}
+	}
+	if reportFormat.Binary && outputPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: output file path is required for %s format\n", format)
+		os.Exit(ExitInvalidArguments)
+	}
 
-	// Validate format
-	if format != "json" && format != "pdf" {
-		fmt.Fprintf(os.Stderr, "Error: invalid format '%s', must be 'json' or 'pdf'\n", format)
+	// A .gz output path implies compression
+	if strings.HasSuffix(strings.ToLower(outputPath), ".gz") {
+		compress = true
+	}
+	if compress && format != "json" && format != formatAll {
+		fmt.Fprintf(os.Stderr, "Error: --compress is only supported for JSON formats\n")
 		os.Exit(ExitInvalidArguments)
 	}
 
@@ -536,6 +1027,9 @@ func runResults(cmd *cobra.Command, args []string) error {
 	if outputPath != "" {
 		fmt.Fprintf(os.Stderr, "Output: %s\n", outputPath)
 	}
+	if format == formatAll {
+		fmt.Fprintf(os.Stderr, "Output directory: %s\n", outputDir)
+	}
 	fmt.Fprintf(os.Stderr, "\n")
 
 	// Load configuration
@@ -571,80 +1065,97 @@ func runResults(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Initialize AWS config
-	awsCfg, err := initializeAWSConfig(ctx, cfg)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize AWS config: %v\n", err)
-		logger.Error("failed to initialize AWS config", "error", err)
-		os.Exit(ExitGeneralError)
+	// The table is rendered from the stored session, without a report generator
+	if format == formatTable {
+		out, color := os.Stdout, useColor(os.Stdout, noColor)
+		if outputPath != "" {
+			file, err := os.Create(outputPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to create output file: %v\n", err)
+				os.Exit(ExitGeneralError)
+			}
+			defer file.Close()
+			out, color = file, false
+		}
+		if err := writeResultsTable(out, session, color); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write table: %v\n", err)
+			os.Exit(ExitGeneralError)
+		}
+		logger.Info("results retrieved successfully", "session_id", sessionID, "format", format)
+		return nil
 	}
 
-	// Initialize report generator
-	reportGen, err := initializeReportGenerator(ctx, awsCfg, cfg)
+	// Initialize report generator. AWS is only needed for formats it generates.
+	reportGen, err := newResultsReportGenerator(formats, func() (core.ReportGenerator, error) {
+		awsCfg, err := initializeAWSConfig(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AWS config: %w", err)
+		}
+		return initializeReportGenerator(ctx, awsCfg, cfg)
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to initialize report generator: %v\n", err)
 		logger.Error("failed to initialize report generator", "error", err)
 		os.Exit(ExitGeneralError)
 	}
 
-	if format == "json" {
-		// Get enhanced JSON results
-		logger.Info("retrieving JSON results", "aws_workload_id", session.AWSWorkloadID)
-		resultsData, err := reportGen.GetResultsJSON(ctx, session.AWSWorkloadID, session)
+	if format == formatAll {
+		// Write every report format into the output directory
+		logger.Info("writing all report formats", "output_dir", outputDir)
+		written, err := writeAllReports(ctx, formats, reportGen, session, outputDir, compress)
+		for _, path := range written {
+			fmt.Fprintf(os.Stderr, "Report written to %s\n", path)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to retrieve results: %v\n", err)
-			logger.Error("failed to retrieve results", "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			logger.Error("failed to write all reports", "written", len(written), "error", err)
 			os.Exit(ExitGeneralError)
 		}
+	} else {
+		logger.Info("generating report", "format", reportFormat.Name, "aws_workload_id", session.AWSWorkloadID)
+		render := renderReport(ctx, reportFormat, reportGen, session)
 
-		// Write to file or stdout
+		// Stream to file or stdout
 		if outputPath != "" {
-			file, err := os.Create(outputPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to create output file: %v\n", err)
-				os.Exit(ExitGeneralError)
-			}
-			defer file.Close()
-
-			if err := core.WriteJSON(file, resultsData); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to write JSON output: %v\n", err)
+			if err := writeReportFile(outputPath, func(w io.Writer) error {
+				return writeReport(w, compress, render)
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to write %s report: %v\n", reportFormat.Name, err)
+				logger.Error("failed to write report", "format", reportFormat.Name, "error", err)
 				os.Exit(ExitGeneralError)
 			}
 			fmt.Fprintf(os.Stderr, "Results written to %s\n", outputPath)
 		} else {
-			if err := core.WriteJSON(os.Stdout, resultsData); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to write JSON output: %v\n", err)
+			if err := writeReport(os.Stdout, compress, render); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to write %s report: %v\n", reportFormat.Name, err)
+				logger.Error("failed to write report", "format", reportFormat.Name, "error", err)
 				os.Exit(ExitGeneralError)
 			}
 		}
-	} else {
-		// Get PDF report from AWS
-		logger.Info("retrieving PDF report", "aws_workload_id", session.AWSWorkloadID)
-		pdfData, err := reportGen.GetConsolidatedReport(ctx, session.AWSWorkloadID, core.ReportFormatPDF)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to retrieve PDF report: %v\n", err)
-			logger.Error("failed to retrieve PDF report", "error", err)
-			os.Exit(ExitGeneralError)
-		}
-
-		// PDF must be written to a file
-		if outputPath == "" {
-			fmt.Fprintf(os.Stderr, "Error: output file path is required for PDF format\n")
-			os.Exit(ExitInvalidArguments)
-		}
-
-		if err := os.WriteFile(outputPath, pdfData, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to write PDF file: %v\n", err)
-			os.Exit(ExitGeneralError)
-		}
-
-		fmt.Fprintf(os.Stderr, "PDF report written to %s\n", outputPath)
 	}
 
 	logger.Info("results retrieved successfully", "session_id", sessionID, "format", format)
 	return nil
 }
 
+// printInitConfig prints the model and AWS settings init validates
+func printInitConfig(w io.Writer, cfg *config.Config) {
+	if cfg.Model.UsesBedrock() {
+		fmt.Fprintf(w, "  Bedrock Region: %s\n", cfg.Bedrock.Region)
+		fmt.Fprintf(w, "  Bedrock Model: %s\n", cfg.Bedrock.ModelID)
+	} else {
+		fmt.Fprintf(w, "  Model Provider: %s\n", cfg.Model.Provider)
+		fmt.Fprintf(w, "  Model Endpoint: %s\n", cfg.Model.BaseURL)
+		fmt.Fprintf(w, "  Model: %s\n", cfg.Model.Name)
+	}
+	if cfg.AWS.Profile != "" {
+		fmt.Fprintf(w, "  AWS Profile: %s\n", cfg.AWS.Profile)
+	}
+	if cfg.AWS.Region != "" {
+		fmt.Fprintf(w, "  AWS Region: %s\n", cfg.AWS.Region)
+	}
+}
+
 // runInit executes the init command
 func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(os.Stderr, "Validating Waffle setup...\n\n")
@@ -657,14 +1168,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Fprintf(os.Stderr, "Configuration loaded successfully\n")
-	fmt.Fprintf(os.Stderr, "  Bedrock Region: %s\n", cfg.Bedrock.Region)
-	fmt.Fprintf(os.Stderr, "  Bedrock Model: %s\n", cfg.Bedrock.ModelID)
-	if cfg.AWS.Profile != "" {
-		fmt.Fprintf(os.Stderr, "  AWS Profile: %s\n", cfg.AWS.Profile)
-	}
-	if cfg.AWS.Region != "" {
-		fmt.Fprintf(os.Stderr, "  AWS Region: %s\n", cfg.AWS.Region)
-	}
+	printInitConfig(os.Stderr, cfg)
 	fmt.Fprintf(os.Stderr, "\n")
 
 	// Create validator
@@ -717,60 +1221,111 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 // parseReviewScope parses the scope string and related flags into a ReviewScope
 func parseReviewScope(scopeStr, pillarStr, questionID string) (core.ReviewScope, error) {
-	scopeStr = strings.ToLower(scopeStr)
+	scope := core.ReviewScope{QuestionID: questionID}
 
-	switch scopeStr {
+	switch strings.ToLower(scopeStr) {
 	case "workload":
-		return core.ReviewScope{
-			Level: core.ScopeLevelWorkload,
-		}, nil
-
+		scope.Level = core.ScopeLevelWorkload
 	case "pillar":
-		if pillarStr == "" {
-			return core.ReviewScope{}, fmt.Errorf("pillar flag is required when scope is 'pillar'")
-		}
-		pillar, err := parsePillar(pillarStr)
-		if err != nil {
-			return core.ReviewScope{}, err
-		}
-		return core.ReviewScope{
-			Level:  core.ScopeLevelPillar,
-			Pillar: &pillar,
-		}, nil
-
+		scope.Level = core.ScopeLevelPillar
 	case "question":
-		if questionID == "" {
-			return core.ReviewScope{}, fmt.Errorf("question-id flag is required when scope is 'question'")
-		}
-		return core.ReviewScope{
-			Level:      core.ScopeLevelQuestion,
-			QuestionID: questionID,
-		}, nil
-
+		scope.Level = core.ScopeLevelQuestion
 	default:
-		return core.ReviewScope{}, fmt.Errorf("invalid scope '%s', must be 'workload', 'pillar', or 'question'", scopeStr)
+		return core.ReviewScope{}, fmt.Errorf("invalid scope '%s', must be 'workload', 'pillar', or 'question'", strings.ToLower(scopeStr))
+	}
+
+	// Keep every flag that was given so Validate can report stray or invalid
+	// values together with missing ones
+	if pillarStr != "" {
+		pillar := normalizePillar(pillarStr)
+		scope.Pillar = &pillar
+	}
+
+	return scope, scope.Validate()
+}
+
+// checkReviewFlagConflicts rejects review flag combinations where one flag
+// would be silently ignored or contradict another. Scope flags are checked by
+// parseReviewScope. Every conflict is reported, joined into a single error.
+func checkReviewFlagConflicts(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	enabled := func(name string) bool {
+		value, _ := flags.GetBool(name)
+		return value
+	}
+
+	var errs []error
+	if enabled("quiet") && enabled("verbose") {
+		errs = append(errs, errors.New("--quiet and --verbose cannot be used together"))
+	}
+	if enabled("interactive") && enabled("no-status") {
+		errs = append(errs, errors.New("--interactive cannot be used with --no-status, which is for unattended runs"))
+	}
+	if flags.Changed("interactive-threshold") && !enabled("interactive") {
+		errs = append(errs, errors.New("--interactive-threshold requires --interactive"))
+	}
+	if enabled("yes") && !enabled("confirm-submit") {
+		errs = append(errs, errors.New("--yes requires --confirm-submit"))
 	}
+	if flags.Changed("graph-format") && !flags.Changed("graph-output") {
+		errs = append(errs, errors.New("--graph-format requires --graph-output"))
+	}
+	if flags.Changed("state-source") && flags.Changed("plan-file") {
+		errs = append(errs, errors.New("--state-source and --plan-file cannot be used together"))
+	}
+	if enabled("require-fresh-plan") && flags.Changed("state-source") {
+		errs = append(errs, errors.New("--require-fresh-plan cannot be used with --state-source, which has no configuration to compare"))
+	}
+	if flags.Changed("milestone-name") && enabled("no-milestone") {
+		errs = append(errs, errors.New("--milestone-name cannot be used with --no-milestone"))
+	}
+	if enabled("overwrite") && !enabled("preserve-answers") {
+		errs = append(errs, errors.New("--overwrite requires --preserve-answers"))
+	}
+	return errors.Join(errs...)
 }
 
-// parsePillar parses a pillar string into a Pillar constant
-func parsePillar(pillarStr string) (core.Pillar, error) {
-	pillarStr = strings.ToLower(pillarStr)
+// formatScopeError lists each problem of an aggregated scope error on its
+// own line
+func formatScopeError(err error) string {
+	return formatJoinedError("invalid review scope", err)
+}
 
-	switch pillarStr {
+// formatJoinedError lists each error joined in err on its own line under
+// title. A single error is returned as is.
+func formatJoinedError(title string, err error) string {
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) < 2 {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	b.WriteString(title + ":")
+	for _, e := range joined.Unwrap() {
+		b.WriteString("\n  - ")
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
+
+// normalizePillar maps accepted pillar spellings to a Pillar constant.
+// Unrecognized names are returned unchanged for validation to reject.
+func normalizePillar(pillarStr string) core.Pillar {
+	switch strings.ToLower(pillarStr) {
 	case "operationalexcellence", "operational-excellence", "operational_excellence":
-		return core.PillarOperationalExcellence, nil
+		return core.PillarOperationalExcellence
 	case "security":
-		return core.PillarSecurity, nil
+		return core.PillarSecurity
 	case "reliability":
-		return core.PillarReliability, nil
+		return core.PillarReliability
 	case "performance", "performanceefficiency", "performance-efficiency", "performance_efficiency":
-		return core.PillarPerformanceEfficiency, nil
+		return core.PillarPerformanceEfficiency
 	case "cost", "costoptimization", "cost-optimization", "cost_optimization":
-		return core.PillarCostOptimization, nil
+		return core.PillarCostOptimization
 	case "sustainability":
-		return core.PillarSustainability, nil
+		return core.PillarSustainability
 	default:
-		return "", fmt.Errorf("invalid pillar '%s', must be one of: operationalExcellence, security, reliability, performance, costOptimization, sustainability", pillarStr)
+		return core.Pillar(pillarStr)
 	}
 }
 
@@ -791,8 +1346,10 @@ func formatScope(scope core.ReviewScope) string {
 	}
 }
 
-// initializeEngine initializes the core engine with all dependencies
-func initializeEngine(ctx context.Context, cfg *config.Config) (core.CoreEngine, error) {
+// initializeEngine initializes the core engine with all dependencies.
+// Workload metadata, when present, sets the review owner, tags and description
+// of the AWS workload.
+func initializeEngine(ctx context.Context, cfg *config.Config, workDir string, workloadMetadata *core.WorkloadMetadata) (*core.Engine, error) {
 	logger := logging.GetLogger()
 
 	// Initialize AWS clients
@@ -811,7 +1368,7 @@ func initializeEngine(ctx context.Context, cfg *config.Config) (core.CoreEngine,
 
 	// Initialize IaC Analyzer
 	logger.Debug("initializing IaC analyzer")
-	iacAnalyzer, err := initializeIaCAnalyzer(ctx, cfg)
+	iacAnalyzer, err := initializeIaCAnalyzer(ctx, cfg, workDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize IaC analyzer: %w", err)
 	}
@@ -825,7 +1382,7 @@ func initializeEngine(ctx context.Context, cfg *config.Config) (core.CoreEngine,
 
 	// Initialize WAFR Evaluator
 	logger.Debug("initializing WAFR evaluator")
-	wafrEvaluator, err := initializeWAFREvaluator(ctx, awsCfg, cfg, bedrockClient)
+	wafrEvaluator, err := initializeWAFREvaluator(ctx, awsCfg, cfg, bedrockClient, workloadMetadata)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize WAFR evaluator: %w", err)
 	}
@@ -846,11 +1403,56 @@ func initializeEngine(ctx context.Context, cfg *config.Config) (core.CoreEngine,
 		bedrockClient,
 		reportGen,
 	)
+	engine.SetRiskThresholds(core.RiskThresholds{
+		RiskConfidenceThreshold:   cfg.Risk.RiskConfidenceThreshold,
+		HighConfidenceThreshold:   cfg.Risk.HighConfidenceThreshold,
+		MediumConfidenceThreshold: cfg.Risk.MediumConfidenceThreshold,
+	})
+
+	provenance := core.WorkloadProvenance{
+		SourceDir:     workDir,
+		GitRef:        detectGitRef(workDir),
+		GitSHA:        detectGitSHA(workDir),
+		WaffleVersion: version,
+	}
+	if workloadMetadata != nil {
+		provenance.Description = workloadMetadata.Description
+	}
+	engine.SetWorkloadDescriptionOptions(core.WorkloadDescriptionOptions{
+		Template:       cfg.WAFR.WorkloadDescriptionTemplate,
+		Provenance:     provenance,
+		UpdateExisting: cfg.WAFR.UpdateWorkloadDescription,
+	})
+	engine.SetPinnedLensVersion(cfg.WAFR.LensVersion)
+	engine.SetCreateMilestone(cfg.WAFR.CreateMilestone)
+	engine.SetMilestoneNameOptions(core.MilestoneNameOptions{Template: cfg.WAFR.MilestoneNameTemplate})
+	engine.SetMetrics(metricsFromConfig(cfg))
+	engine.SetSubmitConcurrency(cfg.WAFR.SubmitConcurrency)
+
+	if cfg.WAFR.QuestionCacheTTLHours > 0 {
+		questionCache, err := session.NewQuestionCache(cfg.Storage.SessionDir)
+		if err != nil {
+			return nil, err
+		}
+		engine.SetQuestionCache(questionCache, time.Duration(cfg.WAFR.QuestionCacheTTLHours)*time.Hour)
+	}
 
 	logger.Info("engine initialized successfully")
 	return engine, nil
 }
 
+// formatStrictModeWarnings lists the warnings that failed a strict review,
+// one per line
+func formatStrictModeWarnings(err *core.StrictModeError) string {
+	var b strings.Builder
+	b.WriteString("Strict mode failed on:")
+	for _, warning := range err.Warnings {
+		b.WriteString("\n  - ")
+		b.WriteString(warning.String())
+	}
+	return b.String()
+}
+
 // handleReviewError handles errors during review execution and exits with appropriate code
 func handleReviewError(err error) {
 	logger := logging.GetLogger()
@@ -868,17 +1470,65 @@ func handleReviewError(err error) {
 		os.Exit(ExitBedrockAPIError)
 	}
 
+	var wafrErr *core.WAFRAPIError
+	if errors.As(err, &wafrErr) {
+		logger.Error("WAFR API error", "error", err, "error_code", wafrErr.ErrorCode)
+		if hint := wafrErrorHint(wafrErr); hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+		}
+		os.Exit(wafrErrorExitCode(wafrErr))
+	}
+
 	var iacErr *core.IaCParsingError
 	if errors.As(err, &iacErr) {
 		logger.Error("IaC parsing error", "error", err)
 		os.Exit(ExitAnalysisIncomplete)
 	}
 
+	var baselineErr *core.BaselineExceededError
+	if errors.As(err, &baselineErr) {
+		os.Exit(ExitBaselineExceeded)
+	}
+
+	var strictErr *core.StrictModeError
+	if errors.As(err, &strictErr) {
+		logger.Error("strict mode failure", "error", err)
+		os.Exit(ExitStrictModeFailure)
+	}
+
 	// Default to general error
 	logger.Error("general error", "error", err)
 	os.Exit(ExitGeneralError)
 }
 
+// wafrErrorExitCode returns the exit code for a WAFR API error. Only errors
+// the user can fix in their account get their own code.
+func wafrErrorExitCode(err *core.WAFRAPIError) int {
+	switch err.Kind() {
+	case core.WAFRErrorAccessDenied:
+		return ExitPermissionDenied
+	case core.WAFRErrorNotFound:
+		return ExitResourceNotFound
+	default:
+		return ExitGeneralError
+	}
+}
+
+// wafrErrorHint suggests how to fix a WAFR API error, or returns "" when
+// there is nothing more to say than the error itself
+func wafrErrorHint(err *core.WAFRAPIError) string {
+	switch err.Kind() {
+	case core.WAFRErrorAccessDenied:
+		return fmt.Sprintf("check that the AWS credentials are valid and allow wellarchitected:%s", err.Operation)
+	case core.WAFRErrorNotFound:
+		return "check that the workload exists in the configured account and region (--region, --profile)"
+	case core.WAFRErrorThrottled:
+		return "the Well-Architected Tool is throttling requests; resume the session later with waffle resume"
+	default:
+		return ""
+	}
+}
+
 // initializeAWSConfig initializes AWS configuration
 func initializeAWSConfig(ctx context.Context, cfg *config.Config) (*config.AWSConfig, error) {
 	// AWS configuration is already loaded in cfg
@@ -891,10 +1541,16 @@ func loadAWSSDKConfig(ctx context.Context, awsCfg *config.AWSConfig) (aws.Config
 	awsConfig, err := awsconfig.LoadDefaultConfig(ctx,
 		awsconfig.WithRegion(awsCfg.Region),
 		awsconfig.WithSharedConfigProfile(awsCfg.Profile),
+		awsconfig.WithUseFIPSEndpoint(awsCfg.FIPSEndpointState()),
+		awsconfig.WithHTTPClient(awsCfg.HTTPClient()),
 	)
 	if err != nil {
 		return aws.Config{}, fmt.Errorf("failed to load AWS SDK config: %w", err)
 	}
+	// WAFFLE_RECORD and WAFFLE_REPLAY route requests through a cassette
+	if err := cassette.Apply(&awsConfig); err != nil {
+		return aws.Config{}, err
+	}
 	return awsConfig, nil
 }
 
@@ -908,55 +1564,197 @@ func initializeSessionManager(cfg *config.Config) (core.SessionManager, error) {
 	return sessionMgr, nil
 }
 
-// initializeIaCAnalyzer initializes the IaC analyzer
-func initializeIaCAnalyzer(ctx context.Context, cfg *config.Config) (core.IaCAnalyzer, error) {
-	// Get current working directory
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %w", err)
+// initializeIaCAnalyzer initializes the IaC analyzer for workDir
+func initializeIaCAnalyzer(ctx context.Context, cfg *config.Config, workDir string) (core.IaCAnalyzer, error) {
+	analyzer := iac.NewAnalyzerWithDir(workDir)
+	if cfg != nil {
+		analyzer.SetStateFetcher(iac.NewTFCClient(cfg.TerraformCloud.Hostname, cfg.TerraformCloud.ResolveToken()))
 	}
-
-	// Create analyzer with current directory
-	analyzer := iac.NewAnalyzerWithDir(currentDir)
 	return analyzer, nil
 }
 
-// initializeBedrockClient initializes the Bedrock client
+// workingDir returns the absolute directory to analyze: the --dir flag if
+// set, otherwise the current directory
+func workingDir(cmd *cobra.Command) (string, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		return currentDir, nil
+	}
+	return resolveDir(dir)
+}
+
+// resolveDir returns dir as an absolute path after checking it is a
+// readable directory
+func resolveDir(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", &core.DirectoryAccessError{Path: dir, Err: err}
+	}
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return "", &core.DirectoryAccessError{Path: absDir, Err: err}
+	}
+	if !info.IsDir() {
+		return "", &core.DirectoryAccessError{Path: absDir, Err: errors.New("not a directory")}
+	}
+	return absDir, nil
+}
+
+// initializeBedrockClient initializes the model client of the configured
+// provider, Bedrock unless model.provider selects another backend
 func initializeBedrockClient(ctx context.Context, awsCfg *config.AWSConfig, cfg *config.Config) (core.BedrockClient, error) {
+	if cfg.Model.Provider == config.ModelProviderOpenAICompatible {
+		client, err := llm.NewOpenAICompatibleClient(llm.Config{
+			BaseURL:             cfg.Model.BaseURL,
+			APIKey:              cfg.Model.ResolveAPIKey(),
+			Model:               cfg.Model.Name,
+			MaxTokens:           cfg.Bedrock.MaxTokens,
+			Temperature:         cfg.Bedrock.Temperature,
+			TopP:                cfg.Bedrock.TopP,
+			MaxRetries:          cfg.Bedrock.MaxRetries,
+			TimeoutSeconds:      cfg.Bedrock.Timeout,
+			MaxParseRetries:     cfg.Bedrock.MaxParseRetries,
+			ResourceTokenBudget: cfg.Bedrock.ResourceTokenBudget,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s client: %w", config.ModelProviderOpenAICompatible, err)
+		}
+		return client, nil
+	}
+
 	// Load AWS SDK config
 	sdkCfg, err := loadAWSSDKConfig(ctx, awsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS SDK config: %w", err)
 	}
 
-	// Convert config.BedrockConfig to bedrock.Config
-	bedrockCfg := &bedrock.Config{
-		ModelID:        cfg.Bedrock.ModelID,
-		Region:         cfg.Bedrock.Region,
-		MaxTokens:      cfg.Bedrock.MaxTokens,
-		Temperature:    cfg.Bedrock.Temperature,
-		TopP:           0.9, // Default value
-		MaxRetries:     cfg.Bedrock.MaxRetries,
-		TimeoutSeconds: cfg.Bedrock.Timeout,
-		RateLimit:      2.0, // Default rate limit
+	client := bedrock.NewClient(sdkCfg, newBedrockConfig(cfg))
+	return client, nil
+}
+
+// newBedrockConfig converts config.BedrockConfig to bedrock.Config
+func newBedrockConfig(cfg *config.Config) *bedrock.Config {
+	return &bedrock.Config{
+		ModelID:             cfg.Bedrock.ModelID,
+		Region:              cfg.Bedrock.Region,
+		MaxTokens:           cfg.Bedrock.MaxTokens,
+		Temperature:         cfg.Bedrock.Temperature,
+		TopP:                cfg.Bedrock.TopP,
+		MaxRetries:          cfg.Bedrock.MaxRetries,
+		TimeoutSeconds:      cfg.Bedrock.Timeout,
+		RateLimit:           2.0, // Default rate limit
+		MaxParseRetries:     cfg.Bedrock.MaxParseRetries,
+		ResourceTokenBudget: cfg.Bedrock.ResourceTokenBudget,
+		Metrics:             metricsFromConfig(cfg),
 	}
+}
 
-	client := bedrock.NewClient(sdkCfg, bedrockCfg)
-	return client, nil
+// applyModelParamFlags overrides the sampling parameters of the model with
+// the --temperature, --top-p and --max-tokens flags that were set
+func applyModelParamFlags(cmd *cobra.Command, bedrockCfg *config.BedrockConfig) error {
+	if cmd.Flags().Changed("temperature") {
+		temperature, err := cmd.Flags().GetFloat64("temperature")
+		if err != nil {
+			return err
+		}
+		if temperature < 0 || temperature > 1 {
+			return fmt.Errorf("--temperature must be between 0 and 1, got %g", temperature)
+		}
+		bedrockCfg.Temperature = temperature
+	}
+	if cmd.Flags().Changed("top-p") {
+		topP, err := cmd.Flags().GetFloat64("top-p")
+		if err != nil {
+			return err
+		}
+		if topP < 0 || topP > 1 {
+			return fmt.Errorf("--top-p must be between 0 and 1, got %g", topP)
+		}
+		bedrockCfg.TopP = topP
+	}
+	if cmd.Flags().Changed("max-tokens") {
+		maxTokens, err := cmd.Flags().GetInt("max-tokens")
+		if err != nil {
+			return err
+		}
+		if maxTokens <= 0 {
+			return fmt.Errorf("--max-tokens must be positive, got %d", maxTokens)
+		}
+		bedrockCfg.MaxTokens = maxTokens
+	}
+	return nil
+}
+
+// applyDeterministicFlag applies --deterministic, which fixes the sampling
+// parameters at temperature 0 and top_p 1 and submits answers one at a
+// time. It cannot be combined with --temperature or --top-p.
+func applyDeterministicFlag(cmd *cobra.Command, cfg *config.Config) error {
+	if deterministic, _ := cmd.Flags().GetBool("deterministic"); !deterministic {
+		return nil
+	}
+	if cmd.Flags().Changed("temperature") || cmd.Flags().Changed("top-p") {
+		return errors.New("--deterministic cannot be combined with --temperature or --top-p")
+	}
+	cfg.Bedrock.Temperature = 0
+	cfg.Bedrock.TopP = 1
+	cfg.WAFR.SubmitConcurrency = 1
+	return nil
+}
+
+// newModelParams records the effective sampling and ordering settings of a
+// review so it can be reproduced
+func newModelParams(cfg *config.Config, deterministic bool) *modelParams {
+	params := &modelParams{
+		Temperature:       cfg.Bedrock.Temperature,
+		TopP:              cfg.Bedrock.TopP,
+		MaxTokens:         cfg.Bedrock.MaxTokens,
+		Deterministic:     deterministic,
+		SubmitConcurrency: cfg.WAFR.SubmitConcurrency,
+	}
+	if deterministic {
+		params.ResourceOrder = "address"
+	}
+	return params
 }
 
 // initializeWAFREvaluator initializes the WAFR evaluator
-func initializeWAFREvaluator(ctx context.Context, awsCfg *config.AWSConfig, cfg *config.Config, bedrockClient core.BedrockClient) (core.WAFREvaluator, error) {
+func initializeWAFREvaluator(ctx context.Context, awsCfg *config.AWSConfig, cfg *config.Config, bedrockClient core.BedrockClient, workloadMetadata *core.WorkloadMetadata) (core.WAFREvaluator, error) {
 	// Create WAFR client configuration
 	clientCfg := &wafr.ClientConfig{
-		Region:  awsCfg.Region,
-		Profile: awsCfg.Profile,
+		Region:      awsCfg.Region,
+		Profile:     awsCfg.Profile,
+		UseFIPS:     awsCfg.UseFIPS,
+		HTTPTimeout: time.Duration(awsCfg.HTTPTimeout) * time.Second,
+	}
+
+	var resourceTypes *core.ResourceTypeMapping
+	if cfg.WAFR.ResourceTypeMappingPath != "" {
+		var err error
+		resourceTypes, err = config.LoadResourceTypeMapping(cfg.WAFR.ResourceTypeMappingPath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Create evaluator configuration
 	evalCfg := &wafr.EvaluatorConfig{
-		MaxRetries: 3,
-		BaseDelay:  1 * time.Second,
+		MaxRetries:                 3,
+		BaseDelay:                  1 * time.Second,
+		UpdateDescriptionOnReuse:   cfg.WAFR.UpdateWorkloadDescription,
+		ExcludeDataSources:         cfg.WAFR.ExcludeDataSources,
+		ChoiceNotes:                cfg.WAFR.SubmitChoiceNotes,
+		ContinueOnPillarError:      cfg.WAFR.ContinueOnPillarError,
+		WorkloadMetadata:           workloadMetadata,
+		QuestionTimeout:            time.Duration(cfg.Bedrock.PerQuestionTimeout) * time.Second,
+		Metrics:                    metricsFromConfig(cfg),
+		SubmitRateLimit:            cfg.WAFR.SubmitRateLimit,
+		ResourceTypes:              resourceTypes,
+		DisableBuiltInRequirements: cfg.WAFR.DisableBuiltInRequirements,
+		SeverityOverrides:          cfg.Risk.SeverityOverrides(),
 	}
 
 	// Create evaluator with configuration
@@ -980,8 +1778,10 @@ func initializeWAFREvaluator(ctx context.Context, awsCfg *config.AWSConfig, cfg
 func initializeReportGenerator(ctx context.Context, awsCfg *config.AWSConfig, cfg *config.Config) (core.ReportGenerator, error) {
 	// Create WAFR client configuration
 	clientCfg := &wafr.ClientConfig{
-		Region:  awsCfg.Region,
-		Profile: awsCfg.Profile,
+		Region:      awsCfg.Region,
+		Profile:     awsCfg.Profile,
+		UseFIPS:     awsCfg.UseFIPS,
+		HTTPTimeout: time.Duration(awsCfg.HTTPTimeout) * time.Second,
 	}
 
 	// Create evaluator configuration
@@ -999,3 +1799,71 @@ func initializeReportGenerator(ctx context.Context, awsCfg *config.AWSConfig, cf
 	generator := report.NewGeneratorWithEvaluator(evaluator)
 	return generator, nil
 }
+
+// detectGitRef returns the current branch name, or the abbreviated commit for
+// a detached HEAD, of the git repository containing dir
+func detectGitRef(dir string) string {
+	for {
+		head, err := os.ReadFile(filepath.Join(dir, ".git", "HEAD"))
+		if err == nil {
+			ref := strings.TrimSpace(string(head))
+			if branch, ok := strings.CutPrefix(ref, "ref: refs/heads/"); ok {
+				return branch
+			}
+			if len(ref) > 12 {
+				return ref[:12]
+			}
+			return ref
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// detectGitSHA returns the abbreviated commit checked out in the git
+// repository containing dir, following a branch ref to its commit
+func detectGitSHA(dir string) string {
+	for {
+		gitDir := filepath.Join(dir, ".git")
+		head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+		if err == nil {
+			sha := strings.TrimSpace(string(head))
+			if ref, ok := strings.CutPrefix(sha, "ref: "); ok {
+				sha = resolveGitRef(gitDir, ref)
+			}
+			if len(sha) > 12 {
+				return sha[:12]
+			}
+			return sha
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// resolveGitRef returns the commit a ref such as refs/heads/main points at,
+// from its loose ref file or from packed-refs
+func resolveGitRef(gitDir, ref string) string {
+	if loose, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(loose))
+	}
+
+	packed, err := os.ReadFile(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(packed), "\n") {
+		if sha, name, ok := strings.Cut(strings.TrimSpace(line), " "); ok && name == ref {
+			return sha
+		}
+	}
+	return ""
+}
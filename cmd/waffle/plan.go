@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/wafr"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan [session-id]",
+	Short: "Retrieve or inspect improvement plans",
+	Long: `Retrieve the current improvement plan of an already reviewed workload from
+the Well-Architected Tool, without evaluating any question or submitting any
+answer. The workload is that of a stored session, or the one named by
+--workload-id.
+
+Risks are linked to the resources they affect using the workload model: the
+one stored with the session, or for --workload-id one analyzed afresh from
+--dir or the current directory. Remediation guidance is not generated, so
+Bedrock is not called.`,
+	Example: `  # Current plan of the workload reviewed in a session
+  waffle plan abc123
+
+  # JSON plan of a workload by name, linked to the Terraform in infra/
+  waffle plan --workload-id my-app --dir infra/ --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPlan,
+}
+
+var planDiffCmd = &cobra.Command{
+	Use:   "diff <session-a> <session-b>",
+	Short: "Compare the improvement plans of two review sessions",
+	Long: `Compare the improvement plan of session-a with that of a later session-b
+and list the items that were resolved (in session-a only), newly introduced
+(in session-b only) and those whose priority changed. Items are matched by
+ID, which is derived from the question of the risk they address.
+
+Only the stored sessions are read: no AWS credentials are needed.`,
+	Example: `  # What changed in the backlog since last week's review
+  waffle plan diff abc123 def456
+
+  # JSON for tracking the backlog over time
+  waffle plan diff abc123 def456 --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPlanDiff,
+}
+
+// improvementPlanFetcher retrieves the improvement plan of a workload.
+// *wafr.Evaluator implements it.
+type improvementPlanFetcher interface {
+	FindWorkload(ctx context.Context, workloadName string) (string, error)
+	GetImprovementPlan(ctx context.Context, awsWorkloadID string, workloadModel *core.WorkloadModel, bedrockClient wafr.BedrockClient) (*core.ImprovementPlan, error)
+}
+
+// planTarget is the workload whose improvement plan is retrieved: that of
+// a session, or one named by WorkloadID whose AWS ID is looked up
+type planTarget struct {
+	SessionID     string
+	WorkloadID    string
+	AWSWorkloadID string
+	// WorkloadModel links risks to resources and may be nil
+	WorkloadModel *core.WorkloadModel
+}
+
+// fetchImprovementPlan retrieves the current improvement plan of target.
+// Only the plan is read: nothing is evaluated or submitted.
+func fetchImprovementPlan(ctx context.Context, fetcher improvementPlanFetcher, target planTarget) (*core.PlanOutput, error) {
+	awsWorkloadID := target.AWSWorkloadID
+	if awsWorkloadID == "" {
+		var err error
+		awsWorkloadID, err = fetcher.FindWorkload(ctx, target.WorkloadID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := fetcher.GetImprovementPlan(ctx, awsWorkloadID, target.WorkloadModel, nil)
+	if err != nil {
+		return nil, err
+	}
+	output := core.NewPlanOutput(awsWorkloadID, plan)
+	output.SessionID = target.SessionID
+	output.WorkloadID = target.WorkloadID
+	output.ResourcesLinked = target.WorkloadModel != nil
+	return output, nil
+}
+
+// analyzeWorkloadModel runs the IaC analysis steps of a review and returns
+// the workload model with its resources and their relationships
+func analyzeWorkloadModel(ctx context.Context, analyzer core.IaCAnalyzer) (*core.WorkloadModel, error) {
+	files, err := analyzer.RetrieveIaCFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve IaC files: %w", err)
+	}
+	model, err := analyzer.ParseTerraform(ctx, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse terraform configuration: %w", err)
+	}
+	resources, err := analyzer.ExtractResources(ctx, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract resources: %w", err)
+	}
+	relationships, err := analyzer.IdentifyRelationships(ctx, resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify relationships: %w", err)
+	}
+	model.Resources = resources
+	model.Relationships = relationships
+	return model, nil
+}
+
+// writePlan writes output in format, which must be text or json
+func writePlan(w io.Writer, output *core.PlanOutput, format string) error {
+	switch format {
+	case core.PlanFormatText:
+		return core.WriteImprovementPlanText(w, output)
+	case core.PlanFormatJSON:
+		return core.WriteImprovementPlanJSON(w, output)
+	default:
+		return fmt.Errorf("unsupported plan format %q: use %s or %s", format, core.PlanFormatText, core.PlanFormatJSON)
+	}
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+
+	workloadID, _ := cmd.Flags().GetString("workload-id")
+	if (len(args) == 1) == (workloadID != "") {
+		fmt.Fprintf(os.Stderr, "Error: give either a session ID or --workload-id\n")
+		os.Exit(ExitInvalidArguments)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	if format != core.PlanFormatText && format != core.PlanFormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q: must be %s or %s\n", format, core.PlanFormatText, core.PlanFormatJSON)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	target := planTarget{WorkloadID: workloadID}
+	if len(args) == 1 {
+		sessionManager, err := initializeSessionManager(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to initialize session manager: %v\n", err)
+			logger.Error("failed to initialize session manager", "error", err)
+			os.Exit(ExitGeneralError)
+		}
+		session, err := sessionManager.LoadSession(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: session %s not found: %v\n", args[0], err)
+			logger.Error("session not found", "session_id", args[0], "error", err)
+			os.Exit(ExitResourceNotFound)
+		}
+		if session.AWSWorkloadID == "" {
+			fmt.Fprintf(os.Stderr, "Error: session %s has no workload in the Well-Architected Tool\n", session.SessionID)
+			os.Exit(ExitGeneralError)
+		}
+		target = planTarget{
+			SessionID:     session.SessionID,
+			WorkloadID:    session.WorkloadID,
+			AWSWorkloadID: session.AWSWorkloadID,
+			WorkloadModel: session.WorkloadModel,
+		}
+	} else {
+		dir, err := workingDir(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitDirectoryAccess)
+		}
+		analyzer, err := initializeIaCAnalyzer(ctx, nil, dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to initialize IaC analyzer: %v\n", err)
+			os.Exit(ExitGeneralError)
+		}
+		// The plan is still worth having when the directory cannot be analyzed
+		target.WorkloadModel, err = analyzeWorkloadModel(ctx, analyzer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: IaC analysis of %s failed, items will not be linked to resources: %v\n", dir, err)
+			logger.Warn("IaC analysis failed", "directory", dir, "error", err)
+		}
+	}
+
+	awsCfg, err := initializeAWSConfig(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize AWS config: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	evaluator, err := wafr.NewEvaluatorWithConfig(ctx, &wafr.ClientConfig{
+		Region:      awsCfg.Region,
+		Profile:     awsCfg.Profile,
+		UseFIPS:     awsCfg.UseFIPS,
+		HTTPTimeout: time.Duration(awsCfg.HTTPTimeout) * time.Second,
+	}, &wafr.EvaluatorConfig{
+		MaxRetries:        3,
+		BaseDelay:         1 * time.Second,
+		SeverityOverrides: cfg.Risk.SeverityOverrides(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create WAFR evaluator: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	output, err := fetchImprovementPlan(ctx, evaluator, target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to retrieve improvement plan: %v\n", err)
+		logger.Error("failed to retrieve improvement plan", "error", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	logger.Info("improvement plan retrieved", "aws_workload_id", output.AWSWorkloadID, "items", len(output.Improvements))
+	if err := writePlan(os.Stdout, output, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write plan: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	return nil
+}
+
+// writePlanDiff writes diff in format, which must be text or json
+func writePlanDiff(w io.Writer, diff *core.PlanDiff, format string) error {
+	switch format {
+	case core.PlanDiffFormatText:
+		return core.WritePlanDiffText(w, diff)
+	case core.PlanDiffFormatJSON:
+		return core.WritePlanDiffJSON(w, diff)
+	default:
+		return fmt.Errorf("unsupported plan diff format %q: use %s or %s", format, core.PlanDiffFormatText, core.PlanDiffFormatJSON)
+	}
+}
+
+func runPlanDiff(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != core.PlanDiffFormatText && format != core.PlanDiffFormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q: must be %s or %s\n", format, core.PlanDiffFormatText, core.PlanDiffFormatJSON)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	sessionManager, err := initializeSessionManager(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize session manager: %v\n", err)
+		logger.Error("failed to initialize session manager", "error", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	sessions := make([]*core.ReviewSession, len(args))
+	for i, sessionID := range args {
+		sessions[i], err = sessionManager.LoadSession(ctx, sessionID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: session %s not found: %v\n", sessionID, err)
+			logger.Error("session not found", "session_id", sessionID, "error", err)
+			os.Exit(ExitResourceNotFound)
+		}
+	}
+
+	diff := core.DiffImprovementPlans(sessions[0], sessions[1])
+	logger.Info("plan diff complete", "before", diff.BeforeSession, "after", diff.AfterSession,
+		"resolved", len(diff.Resolved), "introduced", len(diff.Introduced), "reprioritized", len(diff.Reprioritized))
+	if err := writePlanDiff(os.Stdout, diff, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write plan diff: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	return nil
+}
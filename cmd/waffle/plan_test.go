@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/wafr"
+)
+
+// planWAFRClient serves a workload and its answers and records the API
+// calls made. Any other call panics on the nil embedded client, so a test
+// fails if anything is evaluated or submitted.
+type planWAFRClient struct {
+	wafr.WAFRClient
+	calls []string
+}
+
+func (c *planWAFRClient) ListWorkloads(ctx context.Context, params *wellarchitected.ListWorkloadsInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListWorkloadsOutput, error) {
+	c.calls = append(c.calls, "ListWorkloads")
+	return &wellarchitected.ListWorkloadsOutput{
+		WorkloadSummaries: []types.WorkloadSummary{
+			{WorkloadName: aws.String("my-app-staging"), WorkloadId: aws.String("staging123")},
+			{WorkloadName: aws.String("my-app"), WorkloadId: aws.String("abc123")},
+		},
+	}, nil
+}
+
+func (c *planWAFRClient) ListAnswers(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+	c.calls = append(c.calls, "ListAnswers")
+	if aws.ToString(params.PillarId) != "security" {
+		return &wellarchitected.ListAnswersOutput{}, nil
+	}
+	return &wellarchitected.ListAnswersOutput{
+		AnswerSummaries: []types.AnswerSummary{
+			{
+				QuestionId:      aws.String("data-rest"),
+				QuestionTitle:   aws.String("How do you protect your data at rest?"),
+				Risk:            types.RiskHigh,
+				Choices:         []types.Choice{{ChoiceId: aws.String("sec_data_rest_encrypt_at_rest"), Title: aws.String("Enforce encryption at rest")}},
+				SelectedChoices: []string{},
+			},
+			{QuestionId: aws.String("identities"), Risk: types.RiskNone},
+		},
+	}, nil
+}
+
+func TestFetchImprovementPlan(t *testing.T) {
+	model := &core.WorkloadModel{
+		Resources: []core.Resource{{Address: "aws_s3_bucket.data", Type: "aws_s3_bucket"}},
+	}
+
+	tests := []struct {
+		name          string
+		target        planTarget
+		wantAWSID     string
+		wantLookup    bool
+		wantLinked    bool
+		wantSessionID string
+	}{
+		{
+			name:          "session",
+			target:        planTarget{SessionID: "session-1", WorkloadID: "my-app", AWSWorkloadID: "abc123", WorkloadModel: model},
+			wantAWSID:     "abc123",
+			wantLinked:    true,
+			wantSessionID: "session-1",
+		},
+		{
+			name:       "workload name",
+			target:     planTarget{WorkloadID: "my-app"},
+			wantAWSID:  "abc123",
+			wantLookup: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &planWAFRClient{}
+			evaluator := wafr.NewEvaluator(client, &wafr.EvaluatorConfig{MaxRetries: 1})
+
+			output, err := fetchImprovementPlan(context.Background(), evaluator, tt.target)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAWSID, output.AWSWorkloadID)
+			assert.Equal(t, tt.wantSessionID, output.SessionID)
+			assert.Equal(t, "my-app", output.WorkloadID)
+			assert.Equal(t, tt.wantLinked, output.ResourcesLinked)
+			require.Len(t, output.Improvements, 1)
+			assert.Equal(t, "data-rest", output.Improvements[0].RiskID)
+
+			// Only the workload lookup and the answers are read
+			for _, call := range client.calls {
+				assert.Contains(t, []string{"ListWorkloads", "ListAnswers"}, call)
+			}
+			assert.Equal(t, tt.wantLookup, client.calls[0] == "ListWorkloads")
+		})
+	}
+}
+
+func TestFetchImprovementPlan_WorkloadNotFound(t *testing.T) {
+	evaluator := wafr.NewEvaluator(&planWAFRClient{}, &wafr.EvaluatorConfig{MaxRetries: 1})
+
+	_, err := fetchImprovementPlan(context.Background(), evaluator, planTarget{WorkloadID: "other-app"})
+
+	assert.ErrorContains(t, err, "workload other-app not found")
+}
+
+func TestWritePlan(t *testing.T) {
+	output := core.NewPlanOutput("abc123", &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{
+		{ID: "security/data-rest", Description: "Encrypt data at rest", Priority: 1, EstimatedEffort: "low", AffectedResources: []string{"aws_s3_bucket.data"}},
+	}})
+
+	var buf bytes.Buffer
+	require.NoError(t, writePlan(&buf, output, core.PlanFormatText))
+	assert.Contains(t, buf.String(), "security/data-rest [priority 1, low effort]  Encrypt data at rest")
+
+	buf.Reset()
+	require.NoError(t, writePlan(&buf, output, core.PlanFormatJSON))
+	var decoded core.PlanOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "abc123", decoded.AWSWorkloadID)
+	require.Len(t, decoded.Improvements, 1)
+
+	assert.Error(t, writePlan(&buf, output, "xml"))
+}
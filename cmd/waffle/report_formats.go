@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+	"github.com/waffle/waffle/internal/report"
+)
+
+// formatAll is the --format value that writes every registered report format
+const formatAll = "all"
+
+// reportFileName returns the conventional file name for a session report
+func reportFileName(sessionID, extension string) string {
+	return fmt.Sprintf("waffle-%s.%s", sessionID, extension)
+}
+
+// isJSONFormat reports whether a format renders JSON, which --compress applies to
+func isJSONFormat(format report.Format) bool {
+	return !format.Binary && (format.Extension == "json" || strings.HasSuffix(format.Extension, ".json"))
+}
+
+// writeReport writes the report render produces to w, gzip-compressing it
+// when requested
+func writeReport(w io.Writer, compress bool, render func(io.Writer) error) error {
+	if !compress {
+		return render(w)
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := render(gz); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// renderReport returns a render function writing format's report for session
+func renderReport(ctx context.Context, format report.Format, reportGen core.ReportGenerator, session *core.ReviewSession) func(io.Writer) error {
+	return func(w io.Writer) error {
+		return format.Render(ctx, reportGen, session, w)
+	}
+}
+
+// errFormatRequiresAWS is returned when --offline is combined with a format
+// that AWS generates
+var errFormatRequiresAWS = errors.New("format is generated by AWS and cannot be used with --offline")
+
+// offlineFormats splits formats into those rendered from the stored session
+// and those generated by AWS
+func offlineFormats(formats []report.Format) (offline, skipped []report.Format) {
+	for _, f := range formats {
+		if f.RequiresAWS {
+			skipped = append(skipped, f)
+		} else {
+			offline = append(offline, f)
+		}
+	}
+	return offline, skipped
+}
+
+// newResultsReportGenerator returns the report generator for formats. A WAFR
+// client is only created, by newAWSGenerator, when a format is generated by
+// AWS; every other format is rendered from the stored session.
+func newResultsReportGenerator(formats []report.Format, newAWSGenerator func() (core.ReportGenerator, error)) (core.ReportGenerator, error) {
+	if !report.RequiresAWS(formats) {
+		return report.NewGenerator(), nil
+	}
+	return newAWSGenerator()
+}
+
+// writeAllReports writes each of formats into dir. With compress, JSON
+// reports are gzip-compressed and get a .gz extension.
+// A failing format does not stop the others; the paths that were written are
+// returned along with the combined error.
+func writeAllReports(ctx context.Context, formats []report.Format, reportGen core.ReportGenerator, session *core.ReviewSession, dir string, compress bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	written := make([]string, 0, len(formats))
+	var errs []error
+	for _, format := range formats {
+		path := filepath.Join(dir, reportFileName(session.SessionID, format.Extension))
+		compressFormat := compress && isJSONFormat(format)
+		if compressFormat {
+			path += ".gz"
+		}
+		render := renderReport(ctx, format, reportGen, session)
+		if err := writeReportFile(path, func(w io.Writer) error {
+			return writeReport(w, compressFormat, render)
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s report: %w", format.Name, err))
+			continue
+		}
+		written = append(written, path)
+	}
+
+	return written, errors.Join(errs...)
+}
+
+// writeReportFile writes the report render produces to path. A partially
+// written file is removed when rendering fails.
+func writeReportFile(path string, render func(io.Writer) error) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := render(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// writeGraphFile writes the resource dependency graph to path in the given
+// graph format
+func writeGraphFile(path string, graph *core.ResourceGraph, format string) error {
+	var buf bytes.Buffer
+	if err := core.WriteGraph(&buf, graph, format); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// writePlanJSONFile writes the resources of a workload model to path as
+// Terraform plan JSON
+func writePlanJSONFile(path string, model *core.WorkloadModel) error {
+	var buf bytes.Buffer
+	if err := iac.WritePlanJSON(&buf, model); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+	"github.com/waffle/waffle/internal/report"
+)
+
+type stubReportGenerator struct {
+	pdfErr error
+}
+
+func (s *stubReportGenerator) GetConsolidatedReport(ctx context.Context, awsWorkloadID string, format core.ReportFormat) ([]byte, error) {
+	if s.pdfErr != nil {
+		return nil, s.pdfErr
+	}
+	return []byte("%PDF-1.4"), nil
+}
+
+func (s *stubReportGenerator) GetResultsJSON(ctx context.Context, awsWorkloadID string, session *core.ReviewSession) (map[string]interface{}, error) {
+	return map[string]interface{}{"session_id": session.SessionID}, nil
+}
+
+func (s *stubReportGenerator) GetConsolidatedReportJSON(ctx context.Context, awsWorkloadID string) (*core.ConsolidatedReport, error) {
+	return &core.ConsolidatedReport{AWSWorkloadID: awsWorkloadID, RiskCounts: map[string]int32{"HIGH": 1}}, nil
+}
+
+func TestWriteAllReports(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	session := &core.ReviewSession{SessionID: "sess-1", AWSWorkloadID: "wl-1"}
+
+	written, err := writeAllReports(context.Background(), report.NewDefaultFormatRegistry().Formats(), &stubReportGenerator{}, session, dir, false)
+	require.NoError(t, err)
+
+	formats := report.NewDefaultFormatRegistry().Formats()
+	require.Len(t, written, len(formats))
+	for _, format := range formats {
+		path := filepath.Join(dir, reportFileName("sess-1", format.Extension))
+		assert.Contains(t, written, path)
+		info, err := os.Stat(path)
+		require.NoError(t, err, "expected %s report", format.Name)
+		assert.NotZero(t, info.Size())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "waffle-sess-1.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"session_id": "sess-1"`)
+}
+
+func TestWriteAllReports_Compress(t *testing.T) {
+	dir := t.TempDir()
+	session := &core.ReviewSession{SessionID: "sess-1", AWSWorkloadID: "wl-1"}
+
+	written, err := writeAllReports(context.Background(), report.NewDefaultFormatRegistry().Formats(), &stubReportGenerator{}, session, dir, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "waffle-sess-1.consolidated.json.gz"),
+		filepath.Join(dir, "waffle-sess-1.json.gz"),
+		filepath.Join(dir, "waffle-sess-1.pdf"),
+	}, written)
+
+	file, err := os.Open(filepath.Join(dir, "waffle-sess-1.json.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"session_id": "sess-1"`)
+
+	pdf, err := os.ReadFile(filepath.Join(dir, "waffle-sess-1.pdf"))
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.4", string(pdf), "binary reports are not compressed")
+}
+
+func TestIsJSONFormat(t *testing.T) {
+	registry := report.NewDefaultFormatRegistry()
+	for name, want := range map[string]bool{"json": true, "pdf": false} {
+		format, err := registry.Lookup(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, isJSONFormat(format), name)
+	}
+}
+
+func TestWriteAllReports_ContinuesAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	session := &core.ReviewSession{SessionID: "sess-1", AWSWorkloadID: "wl-1"}
+
+	written, err := writeAllReports(context.Background(), report.NewDefaultFormatRegistry().Formats(), &stubReportGenerator{pdfErr: errors.New("export failed")}, session, dir, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pdf")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "waffle-sess-1.consolidated.json"),
+		filepath.Join(dir, "waffle-sess-1.json"),
+	}, written)
+	assert.NoFileExists(t, filepath.Join(dir, "waffle-sess-1.pdf"))
+}
+
+func TestOfflineFormats(t *testing.T) {
+	offline, skipped := offlineFormats(report.NewDefaultFormatRegistry().Formats())
+
+	require.Len(t, offline, 1)
+	assert.Equal(t, "json", offline[0].Name)
+	require.Len(t, skipped, 2)
+	assert.Equal(t, "consolidated-json", skipped[0].Name)
+	assert.Equal(t, "pdf", skipped[1].Name)
+}
+
+func TestNewResultsReportGenerator(t *testing.T) {
+	registry := report.NewDefaultFormatRegistry()
+	jsonFormat, err := registry.Lookup("json")
+	require.NoError(t, err)
+	session := &core.ReviewSession{
+		SessionID: "sess-1",
+		Results:   &core.ReviewResults{Summary: &core.ResultsSummary{QuestionsEvaluated: 3}},
+	}
+
+	tests := []struct {
+		name    string
+		formats []report.Format
+		wantAWS bool
+	}{
+		{name: "json is rendered from the session", formats: []report.Format{jsonFormat}},
+		{name: "all formats include pdf", formats: registry.Formats(), wantAWS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awsInitialized := false
+			reportGen, err := newResultsReportGenerator(tt.formats, func() (core.ReportGenerator, error) {
+				awsInitialized = true
+				return &stubReportGenerator{}, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAWS, awsInitialized)
+			if tt.wantAWS {
+				return
+			}
+
+			data, err := jsonFormat.Generate(context.Background(), reportGen, session)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"questions_evaluated": 3`)
+		})
+	}
+}
+
+func TestWriteReport_Compress(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeReport(&buf, true, func(w io.Writer) error {
+		_, err := io.WriteString(w, `{"a":1}`)
+		return err
+	}))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+}
+
+func TestWriteGraphFile(t *testing.T) {
+	graph := &core.ResourceGraph{
+		Nodes: map[string]*core.Resource{
+			"aws_s3_bucket.data":         {Type: "aws_s3_bucket"},
+			"aws_s3_bucket_logging.data": {Type: "aws_s3_bucket_logging"},
+		},
+		Edges: map[string][]string{
+			"aws_s3_bucket_logging.data": {"aws_s3_bucket.data"},
+		},
+	}
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "graph.json")
+	require.NoError(t, writeGraphFile(jsonPath, graph, core.GraphFormatJSON))
+	f, err := os.Open(jsonPath)
+	require.NoError(t, err)
+	defer f.Close()
+	decoded, err := core.ReadGraphJSON(f)
+	require.NoError(t, err)
+	assert.Equal(t, graph.Export(), decoded.Export())
+
+	dotPath := filepath.Join(dir, "graph.dot")
+	require.NoError(t, writeGraphFile(dotPath, graph, core.GraphFormatDOT))
+	data, err := os.ReadFile(dotPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"aws_s3_bucket_logging.data" -> "aws_s3_bucket.data";`)
+
+	badPath := filepath.Join(dir, "graph.svg")
+	assert.Error(t, writeGraphFile(badPath, graph, "svg"))
+	assert.NoFileExists(t, badPath)
+}
+
+func TestWritePlanJSONFile(t *testing.T) {
+	model := &core.WorkloadModel{
+		Resources: []core.Resource{
+			{Type: "aws_s3_bucket", Address: "aws_s3_bucket.logs", Properties: map[string]interface{}{"bucket": "logs"}},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "analyzed-plan.json")
+	require.NoError(t, writePlanJSONFile(path, model))
+
+	parsed, err := iac.NewAnalyzer().ParseTerraformPlan(context.Background(), path)
+	require.NoError(t, err)
+	require.Len(t, parsed.Resources, 1)
+	assert.Equal(t, "aws_s3_bucket.logs", parsed.Resources[0].Address)
+	assert.Equal(t, "logs", parsed.Resources[0].Properties["bucket"])
+}
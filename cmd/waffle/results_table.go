@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// formatTable is the --format value that prints pillar risk counts and the
+// top improvement items as aligned columns
+const formatTable = "table"
+
+// tableImprovements is how many improvement items the table lists
+const tableImprovements = 10
+
+// ANSI escape codes used when the table is written to a terminal
+const (
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiReset  = "\033[0m"
+)
+
+// useColor reports whether output written to f may be colored: f must be a
+// terminal, and neither --no-color nor the NO_COLOR environment variable may
+// be set
+func useColor(f *os.File, noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// tableCell is one cell of a table with the color it is shown in, if any
+type tableCell struct {
+	text  string
+	color string
+}
+
+// textTable lays out rows in columns padded to the widest cell
+type textTable struct {
+	header []string
+	rows   [][]tableCell
+}
+
+// write renders the table, coloring the header and colored cells when color
+// is set. Padding is computed from the plain text so escape codes do not
+// shift the columns.
+func (t *textTable) write(w io.Writer, color bool) {
+	widths := make([]int, len(t.header))
+	for i, title := range t.header {
+		widths[i] = len(title)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell.text))
+		}
+	}
+
+	header := make([]tableCell, len(t.header))
+	for i, title := range t.header {
+		header[i] = tableCell{text: title, color: ansiBold}
+	}
+	for _, row := range append([][]tableCell{header}, t.rows...) {
+		var line strings.Builder
+		for i, cell := range row {
+			text := cell.text
+			if i < len(row)-1 {
+				text = fmt.Sprintf("%-*s  ", widths[i], cell.text)
+			}
+			if color && cell.color != "" {
+				text = cell.color + text + ansiReset
+			}
+			line.WriteString(text)
+		}
+		fmt.Fprintln(w, line.String())
+	}
+}
+
+// writeResultsTable prints the risk counts of each pillar and the highest
+// priority improvement items of a session's results
+func writeResultsTable(w io.Writer, session *core.ReviewSession, color bool) error {
+	if session.Results == nil || session.Results.Summary == nil {
+		return core.ErrInvalidSessionStatus
+	}
+	summary := session.Results.Summary
+
+	fmt.Fprintf(w, "Session: %s  Workload: %s\n", session.SessionID, session.WorkloadID)
+	fmt.Fprintf(w, "Questions evaluated: %d  Average confidence: %.2f\n\n", summary.QuestionsEvaluated, summary.AverageConfidence)
+
+	pillars := &textTable{header: []string{"PILLAR", "HIGH", "MEDIUM", "QUESTIONS", "CONFIDENCE"}}
+	for _, pillar := range core.AllPillars() {
+		ps, ok := summary.PillarSummaries[pillar]
+		if !ok {
+			continue
+		}
+		name := string(pillar)
+		if ps.Advisory {
+			name += " (advisory)"
+		}
+		pillars.rows = append(pillars.rows, []tableCell{
+			{text: name},
+			riskCountCell(ps.HighRisks, ansiRed),
+			riskCountCell(ps.MediumRisks, ansiYellow),
+			{text: strconv.Itoa(ps.QuestionsEvaluated)},
+			{text: fmt.Sprintf("%.2f", ps.AverageConfidence)},
+		})
+	}
+	pillars.rows = append(pillars.rows, []tableCell{
+		{text: "total"},
+		riskCountCell(summary.HighRisks, ansiRed),
+		riskCountCell(summary.MediumRisks, ansiYellow),
+		{text: strconv.Itoa(summary.QuestionsEvaluated)},
+		{text: fmt.Sprintf("%.2f", summary.AverageConfidence)},
+	})
+	pillars.write(w, color)
+
+	items := topImprovementItems(session.Results.ImprovementPlan, tableImprovements)
+	if len(items) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\nTop improvements\n")
+	improvements := &textTable{header: []string{"PRIORITY", "SEVERITY", "QUESTION", "DESCRIPTION"}}
+	for _, item := range items {
+		severity, questionID := "", ""
+		var severityColor string
+		if item.Risk != nil {
+			switch item.Risk.Severity {
+			case core.RiskLevelHigh:
+				severity, severityColor = "high", ansiRed
+			case core.RiskLevelMedium:
+				severity, severityColor = "medium", ansiYellow
+			case core.RiskLevelUnassessed:
+				severity = "unassessed"
+			default:
+				severity = "none"
+			}
+			if item.Risk.Question != nil {
+				questionID = item.Risk.Question.ID
+			}
+		}
+		improvements.rows = append(improvements.rows, []tableCell{
+			{text: strconv.Itoa(item.Priority)},
+			{text: severity, color: severityColor},
+			{text: questionID},
+			{text: item.Description},
+		})
+	}
+	improvements.write(w, color)
+	return nil
+}
+
+// riskCountCell shows a risk count, in color when it is not zero
+func riskCountCell(count int, color string) tableCell {
+	cell := tableCell{text: strconv.Itoa(count)}
+	if count > 0 {
+		cell.color = color
+	}
+	return cell
+}
+
+// topImprovementItems returns up to n improvement items, highest priority first
+func topImprovementItems(plan *core.ImprovementPlan, n int) []*core.ImprovementPlanItem {
+	if plan == nil {
+		return nil
+	}
+	items := make([]*core.ImprovementPlanItem, len(plan.Items))
+	copy(items, plan.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Priority > items[j].Priority
+	})
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
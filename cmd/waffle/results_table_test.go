@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func newTableSession() *core.ReviewSession {
+	dataRest := &core.Risk{ID: "risk-sec_data_1", Severity: core.RiskLevelHigh, Question: &core.WAFRQuestion{ID: "sec_data_1"}}
+	backups := &core.Risk{ID: "risk-rel_backup_1", Severity: core.RiskLevelMedium, Question: &core.WAFRQuestion{ID: "rel_backup_1"}}
+	return &core.ReviewSession{
+		SessionID:  "session-123",
+		WorkloadID: "my-app",
+		Results: &core.ReviewResults{
+			Summary: &core.ResultsSummary{
+				QuestionsEvaluated: 5,
+				HighRisks:          1,
+				MediumRisks:        2,
+				AverageConfidence:  0.64,
+				PillarSummaries: map[core.Pillar]core.PillarSummary{
+					core.PillarSecurity:       {QuestionsEvaluated: 3, HighRisks: 1, MediumRisks: 1, AverageConfidence: 0.55},
+					core.PillarReliability:    {QuestionsEvaluated: 1, MediumRisks: 1, AverageConfidence: 0.6},
+					core.PillarSustainability: {QuestionsEvaluated: 1, AverageConfidence: 0.95, Advisory: true},
+				},
+			},
+			ImprovementPlan: &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{
+				{ID: "imp-2", Risk: backups, Description: "Back up the database", Priority: 55},
+				{ID: "imp-1", Risk: dataRest, Description: "Encrypt the log bucket", Priority: 105},
+			}},
+		},
+	}
+}
+
+func TestWriteResultsTable(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeResultsTable(&buf, newTableSession(), false))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, []string{
+		"Session: session-123  Workload: my-app",
+		"Questions evaluated: 5  Average confidence: 0.64",
+		"",
+		"PILLAR                     HIGH  MEDIUM  QUESTIONS  CONFIDENCE",
+		"security                   1     1       3          0.55",
+		"reliability                0     1       1          0.60",
+		"sustainability (advisory)  0     0       1          0.95",
+		"total                      1     2       5          0.64",
+		"",
+		"Top improvements",
+		"PRIORITY  SEVERITY  QUESTION      DESCRIPTION",
+		"105       high      sec_data_1    Encrypt the log bucket",
+		"55        medium    rel_backup_1  Back up the database",
+		"",
+	}, lines)
+	assert.NotContains(t, buf.String(), "\033[", "no color codes without a terminal")
+}
+
+func TestWriteResultsTable_Color(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeResultsTable(&buf, newTableSession(), true))
+
+	out := buf.String()
+	assert.Contains(t, out, ansiBold+"PILLAR")
+	assert.Contains(t, out, ansiRed+"high      "+ansiReset)
+	assert.Contains(t, out, ansiYellow+"medium    "+ansiReset)
+	// Zero counts stay uncolored
+	assert.Contains(t, out, "sustainability (advisory)  0     0")
+}
+
+func TestWriteResultsTable_NoResults(t *testing.T) {
+	err := writeResultsTable(&bytes.Buffer{}, &core.ReviewSession{SessionID: "session-123"}, false)
+	assert.ErrorIs(t, err, core.ErrInvalidSessionStatus)
+}
+
+func TestUseColor(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "results.txt"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	t.Setenv("NO_COLOR", "")
+	assert.False(t, useColor(file, false), "files are not terminals")
+	assert.False(t, useColor(file, true))
+}
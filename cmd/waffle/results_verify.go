@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+var resultsVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check a results JSON file against known-good results",
+	Long: `Compare a results JSON file written by 'waffle results' with a known-good
+one and fail on regressions: a question that is no longer answered, a
+question whose risk got more severe, more high or medium risks in the
+summary, or a confidence score that dropped by more than --tolerance.
+
+Session and workload IDs, timestamps and small confidence changes are
+ignored, as they differ from run to run. Improvements, such as a risk that
+went away, are listed but do not fail. Regressions exit with code 6.`,
+	Example: `  # Fail CI when a new run regresses against the stored results
+  waffle results verify --expected good.json --actual new.json
+
+  # Allow confidence scores to drop by up to 0.2
+  waffle results verify --expected good.json --actual new.json --tolerance 0.2`,
+	Args: cobra.NoArgs,
+	RunE: runResultsVerify,
+}
+
+// readResultsSnapshot reads the comparable part of a saved results file
+func readResultsSnapshot(path string) (*core.ResultsSnapshot, error) {
+	var snapshot *core.ResultsSnapshot
+	err := readResultsFile(path, func(r io.Reader) error {
+		var err error
+		snapshot, err = core.ReadResultsSnapshot(r)
+		return err
+	})
+	return snapshot, err
+}
+
+func runResultsVerify(cmd *cobra.Command, args []string) error {
+	logger := logging.GetLogger()
+
+	expectedPath, _ := cmd.Flags().GetString("expected")
+	actualPath, _ := cmd.Flags().GetString("actual")
+	tolerance, _ := cmd.Flags().GetFloat64("tolerance")
+	if tolerance < 0 || tolerance > 1 {
+		fmt.Fprintf(os.Stderr, "Error: --tolerance must be between 0 and 1\n")
+		os.Exit(ExitInvalidArguments)
+	}
+
+	expected, err := readResultsSnapshot(expectedPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", expectedPath, err)
+		os.Exit(ExitGeneralError)
+	}
+	actual, err := readResultsSnapshot(actualPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", actualPath, err)
+		os.Exit(ExitGeneralError)
+	}
+
+	verification := core.VerifyResults(expected, actual, tolerance)
+	logger.Info("results verified", "expected", expectedPath, "actual", actualPath,
+		"regressions", len(verification.Regressions), "changes", len(verification.Changes))
+	if err := core.WriteResultsVerificationText(os.Stdout, verification); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write verification: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	if !verification.Passed() {
+		os.Exit(ExitBaselineExceeded)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const verifyResultsJSON = `{"schema_version": "1.0", "summary": {"high_risks": 1}, "evaluations": [{"question_id": "sec_data_1", "confidence_score": 0.8}]}`
+
+func TestReadResultsSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "results.json")
+	require.NoError(t, os.WriteFile(plain, []byte(verifyResultsJSON), 0o644))
+
+	compressed := filepath.Join(dir, "results.json.gz")
+	file, err := os.Create(compressed)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(file)
+	_, err = gz.Write([]byte(verifyResultsJSON))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, file.Close())
+
+	for _, path := range []string{plain, compressed} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			snapshot, err := readResultsSnapshot(path)
+			require.NoError(t, err)
+			assert.Equal(t, "1.0", snapshot.SchemaVersion)
+			assert.Equal(t, 1, snapshot.Summary.HighRisks)
+			require.Len(t, snapshot.Evaluations, 1)
+			assert.Equal(t, "sec_data_1", snapshot.Evaluations[0].QuestionID)
+		})
+	}
+}
+
+func TestReadResultsSnapshot_Missing(t *testing.T) {
+	_, err := readResultsSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
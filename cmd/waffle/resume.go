@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <session-id>",
+	Short: "Resume an interrupted review session",
+	Long: `Resume a review session from the last checkpoint it saved and write the
+review JSON to stdout.
+
+With --from-checkpoint, the session is first rewound to an earlier checkpoint
+and every step after it runs again, e.g. to evaluate the questions again after
+a prompt change. Completed sessions can be rewound too. Checkpoints, in order:
+` + strings.Join(core.ResumeCheckpoints(), ", ") + `
+
+A session cannot skip forward past the checkpoint it reached, and the
+evaluation results a later checkpoint starts from are only saved once a review
+completes.`,
+	Example: `  # Continue a failed review
+  waffle resume abc123-def456-789
+
+  # Evaluate the questions of a completed review again
+  waffle resume abc123-def456-789 --from-checkpoint questions_retrieved`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResume,
+}
+
+// sessionResumer resumes review sessions
+type sessionResumer interface {
+	ResumeSession(ctx context.Context, sessionID string) (*core.ReviewSession, error)
+	ResumeSessionFrom(ctx context.Context, sessionID string, checkpoint string) (*core.ReviewSession, error)
+}
+
+// resumeReview resumes a session, from checkpoint when one is given, and
+// writes the review JSON to stdout
+func resumeReview(ctx context.Context, resumer sessionResumer, sessionID, checkpoint string, stdout io.Writer) error {
+	var session *core.ReviewSession
+	var err error
+	if checkpoint != "" {
+		session, err = resumer.ResumeSessionFrom(ctx, sessionID, checkpoint)
+	} else {
+		session, err = resumer.ResumeSession(ctx, sessionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := core.WriteJSONStream(stdout, core.ConvertReviewSessionToOutput(session)); err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return nil
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+	sessionID := args[0]
+	checkpoint, _ := cmd.Flags().GetString("from-checkpoint")
+
+	currentDir, err := workingDir(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitDirectoryAccess)
+	}
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	workloadMetadata, err := config.LoadWorkloadMetadata(currentDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	engine, err := initializeEngine(ctx, cfg, currentDir, workloadMetadata)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize engine: %v\n", err)
+		logger.Error("failed to initialize engine", "error", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	if checkpoint != "" {
+		fmt.Fprintf(os.Stderr, "Resuming session %s from checkpoint %s\n", sessionID, checkpoint)
+	} else {
+		fmt.Fprintf(os.Stderr, "Resuming session %s\n", sessionID)
+	}
+
+	err = resumeReview(ctx, engine, sessionID, checkpoint, os.Stdout)
+	saveMetricsSnapshot(cfg)
+	saveCassette()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var validationErr *core.ValidationError
+		if errors.As(err, &validationErr) || errors.Is(err, core.ErrSessionAlreadyCompleted) {
+			os.Exit(ExitInvalidArguments)
+		}
+		handleReviewError(err)
+	}
+	return nil
+}
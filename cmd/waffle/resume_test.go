@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// fakeResumer records which resume method was called
+type fakeResumer struct {
+	checkpoint string
+	rewound    bool
+	err        error
+}
+
+func (f *fakeResumer) session(sessionID string) (*core.ReviewSession, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &core.ReviewSession{
+		SessionID:  sessionID,
+		WorkloadID: "test-workload",
+		Status:     core.SessionStatusCompleted,
+		Checkpoint: "milestone_created",
+	}, nil
+}
+
+func (f *fakeResumer) ResumeSession(ctx context.Context, sessionID string) (*core.ReviewSession, error) {
+	return f.session(sessionID)
+}
+
+func (f *fakeResumer) ResumeSessionFrom(ctx context.Context, sessionID string, checkpoint string) (*core.ReviewSession, error) {
+	f.rewound = true
+	f.checkpoint = checkpoint
+	return f.session(sessionID)
+}
+
+func TestResumeReview(t *testing.T) {
+	tests := []struct {
+		name        string
+		checkpoint  string
+		wantRewound bool
+	}{
+		{name: "from saved checkpoint"},
+		{name: "from named checkpoint", checkpoint: "questions_retrieved", wantRewound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resumer := &fakeResumer{}
+			var stdout bytes.Buffer
+			require.NoError(t, resumeReview(context.Background(), resumer, "session-1", tt.checkpoint, &stdout))
+
+			assert.Equal(t, tt.wantRewound, resumer.rewound)
+			assert.Equal(t, tt.checkpoint, resumer.checkpoint)
+
+			var output core.ReviewOutput
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			assert.Equal(t, "session-1", output.SessionID)
+			assert.Equal(t, "completed", output.Status)
+		})
+	}
+}
+
+func TestResumeReview_Error(t *testing.T) {
+	resumeErr := &core.ValidationError{Field: "checkpoint", Value: "bogus", Message: "must be one of created"}
+	resumer := &fakeResumer{err: resumeErr}
+
+	var stdout bytes.Buffer
+	err := resumeReview(context.Background(), resumer, "session-1", "bogus", &stdout)
+	var validationErr *core.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+// reviewRequest holds the validated inputs of the review command
+type reviewRequest struct {
+	WorkloadID  string
+	Directory   string
+	PlanFile    string
+	Scope       core.ReviewScope
+	ReportDrift bool
+	GraphOutput string
+	GraphFormat string
+	// EmitPlanJSON is the file to write the workload model to as plan JSON
+	EmitPlanJSON string
+	// WorkloadMetadata is the parsed workload metadata file, nil if absent
+	WorkloadMetadata *core.WorkloadMetadata
+	// Baseline is the risk baseline read from BaselineFile, nil if not given
+	Baseline     *core.Baseline
+	BaselineFile string
+	// Calibration adds the confidence score distribution to the output
+	Calibration bool
+	// CoverageMatrix adds the questions each resource was cited by
+	CoverageMatrix bool
+	// ModelParams are the sampling parameters the model was run with, nil
+	// if not recorded
+	ModelParams *modelParams
+	// RequireFreshPlan fails the review when PlanFile predates resources
+	// in the configuration
+	RequireFreshPlan bool
+}
+
+// modelParams are the effective sampling parameters of the model, after
+// the --temperature, --top-p, --max-tokens and --deterministic overrides
+type modelParams struct {
+	Temperature float64 `json:"temperature"`
+	TopP        float64 `json:"top_p"`
+	MaxTokens   int     `json:"max_tokens"`
+	// Deterministic is set when the review ran with --deterministic
+	Deterministic bool `json:"deterministic,omitempty"`
+	// ResourceOrder is "address" when resources were sorted by address,
+	// empty when they kept the order of the files they were read from
+	ResourceOrder     string `json:"resource_order,omitempty"`
+	SubmitConcurrency int    `json:"submit_concurrency,omitempty"`
+}
+
+// loadBaseline reads a risk baseline JSON file
+func loadBaseline(path string) (*core.Baseline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline file: %w", err)
+	}
+	defer file.Close()
+
+	baseline, err := core.ReadBaseline(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// parseAdvisoryPillars parses the --advisory-pillar values, dropping repeats
+func parseAdvisoryPillars(values []string) ([]core.Pillar, error) {
+	var pillars []core.Pillar
+	for _, value := range values {
+		pillar := normalizePillar(value)
+		if !pillar.IsValid() {
+			return nil, fmt.Errorf("%w for --advisory-pillar: %q", core.ErrInvalidPillar, value)
+		}
+		if !slices.Contains(pillars, pillar) {
+			pillars = append(pillars, pillar)
+		}
+	}
+	return pillars, nil
+}
+
+// formatPillars joins pillar names for display
+func formatPillars(pillars []core.Pillar) string {
+	names := make([]string, 0, len(pillars))
+	for _, pillar := range pillars {
+		names = append(names, string(pillar))
+	}
+	return strings.Join(names, ", ")
+}
+
+// preflightPermissions reports the IAM actions a review calls that the caller
+// is not allowed, and fails when a required one is missing. When the policies
+// cannot be simulated the review goes ahead.
+func preflightPermissions(ctx context.Context, simulator config.PolicySimulator, required []string, progress *core.CLIProgressReporter) error {
+	report := config.CheckPermissions(ctx, simulator, required)
+	if !report.Simulated {
+		logging.GetLogger().Warn("permission preflight skipped", "error", report.Error)
+		progress.Statusf("Permission preflight skipped: %v\n", report.Error)
+		return nil
+	}
+
+	for _, action := range report.UnscopedRequired {
+		progress.Statusf("Warning: %s is not allowed on every resource; the review fails unless it is allowed on the workload or model it uses\n", action)
+	}
+	for _, action := range report.MissingOptional {
+		progress.Statusf("Warning: %s is not allowed; %s will fail\n", action, config.OptionalActions[action])
+	}
+	if !report.OK() {
+		return fmt.Errorf("missing IAM permissions for %s: %s", report.Principal, strings.Join(report.MissingRequired, ", "))
+	}
+	return nil
+}
+
+// newStatusReporter returns the progress reporter for human status output.
+// With noStatus set, everything it is given is discarded. Progress is drawn
+// with a spinner when w is a terminal.
+func newStatusReporter(w io.Writer, noStatus bool) *core.CLIProgressReporter {
+	if noStatus {
+		w = io.Discard
+	}
+	reporter := core.NewCLIProgressReporter(w)
+	if f, ok := w.(*os.File); ok {
+		reporter.SetInteractive(isTerminal(f))
+	}
+	return reporter
+}
+
+// runReviewWorkflow initiates and executes a review and writes the review
+// JSON to stdout. Human-readable output goes only through progress.
+func runReviewWorkflow(ctx context.Context, engine core.CoreEngine, req reviewRequest, progress *core.CLIProgressReporter, stdout io.Writer) error {
+	logger := logging.GetLogger()
+
+	// Initiate review
+	logger.Info("initiating review", "workload_id", req.WorkloadID)
+	session, err := engine.InitiateReview(ctx, req.WorkloadID, req.Scope)
+	if err != nil {
+		logger.Error("failed to initiate review", "error", err)
+		return fmt.Errorf("failed to initiate review: %w", err)
+	}
+
+	session.PlanFilePath = req.PlanFile
+	session.ReportDrift = req.ReportDrift
+	session.RequireFreshPlan = req.RequireFreshPlan
+
+	logger.Info("executing review", "session_id", session.SessionID)
+
+	// Execute review with progress reporting
+	results, err := engine.ExecuteReviewWithProgress(ctx, session, progress)
+	if err != nil {
+		logger.Error("review execution failed",
+			"session_id", session.SessionID,
+			"error", err,
+		)
+		return fmt.Errorf("review execution failed: %w", err)
+	}
+
+	logger.Info("review completed successfully",
+		"session_id", session.SessionID,
+		"questions_evaluated", len(results.Evaluations),
+	)
+
+	// Output JSON for CI/CD integration
+	reviewOutput := &core.ReviewOutput{
+		SchemaVersion: core.SchemaVersion,
+		SessionID:     session.SessionID,
+		CorrelationID: session.CorrelationID,
+		WorkloadID:    req.WorkloadID,
+		LensVersion:   session.LensVersion,
+		Status:        string(session.Status),
+		CreatedAt:     session.CreatedAt,
+		Summary:       core.ConvertResultsSummaryToOutput(results.Summary),
+		Metadata: map[string]interface{}{
+			"scope":           formatScope(req.Scope),
+			"directory":       req.Directory,
+			"aws_workload_id": session.AWSWorkloadID,
+			"milestone_id":    session.MilestoneID,
+		},
+	}
+
+	if req.PlanFile != "" {
+		reviewOutput.Metadata["plan_file"] = req.PlanFile
+	}
+
+	if req.WorkloadMetadata != nil {
+		reviewOutput.Metadata["workload"] = req.WorkloadMetadata
+	}
+
+	if req.ModelParams != nil {
+		reviewOutput.Metadata["model_params"] = req.ModelParams
+	}
+
+	if session.ReportDrift && session.WorkloadModel != nil {
+		reviewOutput.Drift = core.ConvertPropertyDriftToOutput(session.WorkloadModel.Drift)
+		reviewOutput.Metadata["property_drift_count"] = len(session.WorkloadModel.Drift)
+	}
+
+	if session.WorkloadModel != nil && len(session.WorkloadModel.PublicExposure) > 0 {
+		reviewOutput.PublicExposure = core.ConvertPublicExposureToOutput(session.WorkloadModel.PublicExposure)
+		progress.Statusf("Public exposure: %d resources reachable from the internet\n", len(session.WorkloadModel.PublicExposure))
+	}
+
+	if session.WorkloadModel != nil && len(session.WorkloadModel.DestructiveChanges) > 0 {
+		reviewOutput.Metadata["destructive_changes"] = core.ConvertDestructiveChangesToOutput(session.WorkloadModel.DestructiveChanges)
+		for _, c := range session.WorkloadModel.DestructiveChanges {
+			progress.Statusf("Warning: plan will %s stateful resource %s\n", c.Action, c.Address)
+		}
+	}
+
+	if session.WorkloadModel != nil && len(session.WorkloadModel.Advisories) > 0 {
+		reviewOutput.Metadata["advisories"] = core.ConvertAdvisoriesToOutput(session.WorkloadModel.Advisories)
+		for _, a := range session.WorkloadModel.Advisories {
+			progress.Statusf("Advisory (%s): %s: %s\n", a.Pillar, a.Source, a.Message)
+		}
+	}
+
+	if len(session.FailedPillars) > 0 {
+		reviewOutput.Metadata["failed_pillars"] = session.FailedPillars
+	}
+
+	if session.QuestionCatalog != "" {
+		reviewOutput.Metadata["question_catalog"] = session.QuestionCatalog
+		progress.Statusf("Questions were read from the static catalog %s, not the Well-Architected Tool\n", session.QuestionCatalog)
+	}
+
+	if req.GraphOutput != "" && session.WorkloadModel != nil {
+		if err := writeGraphFile(req.GraphOutput, session.WorkloadModel.Relationships, req.GraphFormat); err != nil {
+			logger.Error("failed to write resource graph", "path", req.GraphOutput, "error", err)
+			return fmt.Errorf("failed to write resource graph: %w", err)
+		}
+		reviewOutput.Metadata["graph_file"] = req.GraphOutput
+	}
+
+	if req.EmitPlanJSON != "" && session.WorkloadModel != nil {
+		if err := writePlanJSONFile(req.EmitPlanJSON, session.WorkloadModel); err != nil {
+			logger.Error("failed to write plan JSON", "path", req.EmitPlanJSON, "error", err)
+			return fmt.Errorf("failed to write plan JSON: %w", err)
+		}
+		reviewOutput.Metadata["plan_json_file"] = req.EmitPlanJSON
+	}
+
+	if results.Summary != nil && results.Summary.Partial {
+		reviewOutput.Metadata["partial"] = true
+		reviewOutput.Metadata["questions_skipped"] = results.Summary.QuestionsSkipped
+	}
+
+	if results.Summary != nil && len(results.Summary.PreservedQuestions) > 0 {
+		progress.Statusf("Preserved %d already answered question(s): %s\n",
+			len(results.Summary.PreservedQuestions), strings.Join(results.Summary.PreservedQuestions, ", "))
+	}
+
+	if results.Summary != nil && len(results.Summary.TimedOutQuestions) > 0 {
+		progress.Statusf("Warning: evaluation timed out for %d question(s): %s\n",
+			len(results.Summary.TimedOutQuestions), strings.Join(results.Summary.TimedOutQuestions, ", "))
+	}
+
+	if session.ImprovementPlanError != "" {
+		progress.Statusf("Warning: improvement plan could not be retrieved, continuing without it: %s\n", session.ImprovementPlanError)
+	}
+	core.AddImprovementPlanMetadata(reviewOutput.Metadata, results.ImprovementPlan, session.ImprovementPlanError)
+
+	if session.MilestoneSkipped {
+		reviewOutput.Metadata["milestone_skipped"] = true
+	}
+
+	if session.WorkloadDeleted {
+		reviewOutput.Metadata["workload_deleted"] = true
+	}
+
+	if results.Timings != nil {
+		reviewOutput.Metadata["timings"] = core.ConvertReviewTimingsToOutput(results.Timings)
+	}
+
+	if len(results.Suppressions) > 0 {
+		reviewOutput.Suppressions = core.ConvertSuppressionsToOutput(results.Suppressions)
+		for _, s := range results.Suppressions {
+			reason := s.Reason
+			if reason == "" {
+				reason = "no reason given"
+			}
+			progress.Statusf("Suppressed: %s for %s (%s)\n", s.QuestionID, s.Resource, reason)
+		}
+	}
+
+	if req.Calibration {
+		calibration := core.BuildCalibration(results.Evaluations)
+		reviewOutput.Calibration = core.ConvertCalibrationToOutput(calibration)
+		progress.Statusf("Confidence calibration (%d questions, average %.2f):\n", calibration.Questions, calibration.AverageConfidence)
+		for _, band := range calibration.Bands {
+			progress.Statusf("  %.1f-%.1f: %d\n", band.Min, band.Max, band.Count)
+		}
+	}
+
+	if req.CoverageMatrix {
+		matrix := core.BuildCoverageMatrix(results.Evaluations, session.WorkloadModel)
+		reviewOutput.CoverageMatrix = core.ConvertCoverageMatrixToOutput(matrix)
+		progress.Statusf("Coverage matrix: %d of %d resources cited as evidence\n",
+			len(matrix.Resources)-len(matrix.Uncited), len(matrix.Resources))
+		if len(matrix.Uncited) > 0 {
+			progress.Statusf("  Influenced no answer: %s\n", strings.Join(matrix.Uncited, ", "))
+		}
+	}
+
+	var comparison *core.BaselineComparison
+	if req.Baseline != nil {
+		comparison = req.Baseline.Compare(results.Summary)
+		reviewOutput.Baseline = core.ConvertBaselineComparisonToOutput(req.BaselineFile, comparison)
+		for _, r := range comparison.Regressions {
+			progress.Statusf("Baseline exceeded: %s %s %d (baseline %d)\n", r.Pillar, r.Metric, r.Actual, r.Baseline)
+		}
+		for _, r := range comparison.Advisory {
+			progress.Statusf("Baseline exceeded (advisory): %s %s %d (baseline %d)\n", r.Pillar, r.Metric, r.Actual, r.Baseline)
+		}
+	}
+
+	if err := core.WriteJSONStream(stdout, reviewOutput); err != nil {
+		logger.Error("failed to write JSON output", "error", err)
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+
+	// The review itself succeeded, so the results above are still written
+	if comparison != nil && !comparison.Passed {
+		logger.Warn("review exceeds baseline", "pillars", comparison.RegressedPillars())
+		return &core.BaselineExceededError{Pillars: comparison.RegressedPillars()}
+	}
+
+	return nil
+}
@@ -0,0 +1,761 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+// TestMain starts every test with a console-only global logger. Tests that
+// need file logging point LogDir at t.TempDir()
+func TestMain(m *testing.M) {
+	if err := logging.InitGlobalLogger(&logging.Config{Level: logging.LevelInfo}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeReviewEngine completes a review immediately, reporting progress the
+// way the real engine does
+type fakeReviewEngine struct {
+	initiateErr error
+	// questionsSkipped marks the review partial when set
+	questionsSkipped int
+	pillarSummaries  map[core.Pillar]core.PillarSummary
+	// skipMilestone completes the review without a milestone
+	skipMilestone bool
+	evaluations   []*core.QuestionEvaluation
+	// destructiveChanges and resources make up the workload model of the
+	// session
+	destructiveChanges []core.DestructiveChange
+	resources          []core.Resource
+	// improvementPlan and improvementPlanErr are the retrieved plan and why
+	// it could not be retrieved
+	improvementPlan    *core.ImprovementPlan
+	improvementPlanErr string
+}
+
+func (f *fakeReviewEngine) InitiateReview(ctx context.Context, workloadID string, scope core.ReviewScope) (*core.ReviewSession, error) {
+	if f.initiateErr != nil {
+		return nil, f.initiateErr
+	}
+	return &core.ReviewSession{
+		SessionID:  "session-1",
+		WorkloadID: workloadID,
+		Scope:      scope,
+		Status:     core.SessionStatusCreated,
+		CreatedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, nil
+}
+
+func (f *fakeReviewEngine) ExecuteReview(ctx context.Context, session *core.ReviewSession) (*core.ReviewResults, error) {
+	return f.ExecuteReviewWithProgress(ctx, session, nil)
+}
+
+func (f *fakeReviewEngine) ExecuteReviewWithProgress(ctx context.Context, session *core.ReviewSession, progress core.ProgressReporter) (*core.ReviewResults, error) {
+	summary := &core.ResultsSummary{QuestionsEvaluated: 1, AverageConfidence: 0.8, PillarSummaries: f.pillarSummaries}
+	if f.questionsSkipped > 0 {
+		summary.Partial = true
+		summary.QuestionsSkipped = f.questionsSkipped
+	}
+	if progress != nil {
+		progress.ReportStep("evaluate_questions", "Evaluating questions using Bedrock...")
+		progress.ReportProgress(1, 1, "Evaluating question 1 of 1")
+		progress.ReportCompletion(summary)
+	}
+	session.Status = core.SessionStatusCompleted
+	session.MilestoneSkipped = f.skipMilestone
+	session.ImprovementPlanError = f.improvementPlanErr
+	if f.destructiveChanges != nil || f.resources != nil {
+		session.WorkloadModel = &core.WorkloadModel{Resources: f.resources, DestructiveChanges: f.destructiveChanges}
+	}
+	timings := &core.ReviewTimings{
+		Steps: []core.StepTiming{{Step: core.StepEvaluateQuestions, Offset: time.Second, Duration: 2 * time.Second}},
+		Total: 3 * time.Second,
+	}
+	return &core.ReviewResults{Evaluations: f.evaluations, ImprovementPlan: f.improvementPlan, Summary: summary, Timings: timings}, nil
+}
+
+func (f *fakeReviewEngine) GetSessionStatus(ctx context.Context, sessionID string) (core.SessionStatus, error) {
+	return core.SessionStatusCompleted, nil
+}
+
+func (f *fakeReviewEngine) ResumeSession(ctx context.Context, sessionID string) (*core.ReviewSession, error) {
+	return nil, core.ErrSessionNotFound
+}
+
+// captureStderr returns what fn writes to os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	original := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = original }()
+
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- string(data)
+	}()
+
+	fn()
+	require.NoError(t, w.Close())
+	return <-captured
+}
+
+func TestNoStatusRequested(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"review", "--no-status"}, want: true},
+		{args: []string{"review", "--no-status=true"}, want: true},
+		{args: []string{"review", "--no-status=false"}, want: false},
+		{args: []string{"review"}, want: false},
+		{args: []string{"review", "--", "--no-status"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			assert.Equal(t, tt.want, noStatusRequested(tt.args))
+		})
+	}
+}
+
+func TestRunReviewWorkflow_NoStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr bool
+	}{
+		{name: "status shown", args: []string{"review", "--workload-id", "my-app"}, wantStderr: true},
+		{name: "no status", args: []string{"review", "--workload-id", "my-app", "--no-status"}, wantStderr: false},
+	}
+
+	// INFO logs would otherwise reach stderr alongside the status lines
+	t.Setenv("WAFFLE_LOG_LEVEL", "INFO")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				require.NoError(t, logging.InitGlobalLogger(&logging.Config{Level: logging.LevelInfo}))
+			})
+
+			var stdout bytes.Buffer
+			stderr := captureStderr(t, func() {
+				logConfig := newLogConfig(tt.args)
+				logConfig.LogDir = t.TempDir()
+				require.NoError(t, logging.InitGlobalLogger(logConfig))
+				defer logging.CloseGlobalLogger()
+
+				progress := newStatusReporter(os.Stderr, noStatusRequested(tt.args))
+				progress.Statusf("Starting WAFR review...\n")
+
+				req := reviewRequest{
+					WorkloadID: "my-app",
+					Directory:  "/src/my-app",
+					Scope:      core.ReviewScope{Level: core.ScopeLevelWorkload},
+				}
+				err := runReviewWorkflow(context.Background(), &fakeReviewEngine{}, req, progress, &stdout)
+				require.NoError(t, err)
+			})
+
+			if tt.wantStderr {
+				assert.Contains(t, stderr, "Starting WAFR review...")
+				assert.Contains(t, stderr, "Review completed successfully")
+				assert.Contains(t, stderr, "level=INFO")
+			} else {
+				assert.Empty(t, stderr)
+			}
+
+			var output core.ReviewOutput
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			assert.Equal(t, "session-1", output.SessionID)
+			assert.Equal(t, "my-app", output.WorkloadID)
+			assert.Equal(t, "completed", output.Status)
+			assert.Equal(t, 1, output.Summary.QuestionsEvaluated)
+		})
+	}
+}
+
+func TestRunReviewWorkflow_InitiateError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	engine := &fakeReviewEngine{initiateErr: core.ErrWorkloadNotFound}
+
+	err := runReviewWorkflow(context.Background(), engine, reviewRequest{WorkloadID: "my-app"}, newStatusReporter(&stderr, true), &stdout)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, core.ErrWorkloadNotFound)
+	assert.Contains(t, err.Error(), "failed to initiate review")
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunReviewWorkflow_WorkloadMetadata(t *testing.T) {
+	var stdout bytes.Buffer
+	req := reviewRequest{
+		WorkloadID:       "my-app",
+		Scope:            core.ReviewScope{Level: core.ScopeLevelWorkload},
+		WorkloadMetadata: &core.WorkloadMetadata{Owner: "platform-team", Criticality: "high"},
+	}
+
+	err := runReviewWorkflow(context.Background(), &fakeReviewEngine{}, req, newStatusReporter(&bytes.Buffer{}, true), &stdout)
+	require.NoError(t, err)
+
+	var output core.ReviewOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.Equal(t, map[string]interface{}{"owner": "platform-team", "criticality": "high"}, output.Metadata["workload"])
+}
+
+func TestRunReviewWorkflow_ImprovementPlanStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		engine     *fakeReviewEngine
+		wantStatus interface{}
+		wantError  interface{}
+		wantStderr string
+	}{
+		{
+			name: "retrieved",
+			engine: &fakeReviewEngine{improvementPlan: &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{
+				{ID: "security/data-rest", Priority: 50},
+			}}},
+			wantStatus: core.ImprovementPlanStatusOK,
+		},
+		{
+			name:       "no risks",
+			engine:     &fakeReviewEngine{improvementPlan: &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{}}},
+			wantStatus: core.ImprovementPlanStatusEmpty,
+		},
+		{
+			name: "retrieval failed",
+			engine: &fakeReviewEngine{
+				improvementPlan:    &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{}},
+				improvementPlanErr: "ListAnswers: throttled",
+			},
+			wantStatus: core.ImprovementPlanStatusError,
+			wantError:  "ListAnswers: throttled",
+			wantStderr: "improvement plan could not be retrieved",
+		},
+		{
+			name:   "not retrieved",
+			engine: &fakeReviewEngine{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}}
+
+			err := runReviewWorkflow(context.Background(), tt.engine, req, newStatusReporter(&stderr, false), &stdout)
+			require.NoError(t, err)
+
+			var output core.ReviewOutput
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			assert.Equal(t, tt.wantStatus, output.Metadata["improvement_plan_status"])
+			assert.Equal(t, tt.wantError, output.Metadata["improvement_plan_error"])
+			if tt.wantStderr != "" {
+				assert.Contains(t, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunReviewWorkflow_ModelParams(t *testing.T) {
+	var stdout bytes.Buffer
+	req := reviewRequest{
+		WorkloadID:  "my-app",
+		Scope:       core.ReviewScope{Level: core.ScopeLevelWorkload},
+		ModelParams: &modelParams{Temperature: 0, TopP: 0.5, MaxTokens: 2048},
+	}
+
+	err := runReviewWorkflow(context.Background(), &fakeReviewEngine{}, req, newStatusReporter(&bytes.Buffer{}, true), &stdout)
+	require.NoError(t, err)
+
+	var output core.ReviewOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.Equal(t, map[string]interface{}{"temperature": 0.0, "top_p": 0.5, "max_tokens": 2048.0}, output.Metadata["model_params"])
+}
+
+func TestRunReviewWorkflow_Partial(t *testing.T) {
+	tests := []struct {
+		name             string
+		questionsSkipped int
+		wantPartial      bool
+	}{
+		{name: "complete review", questionsSkipped: 0},
+		{name: "capped review", questionsSkipped: 57, wantPartial: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}}
+			engine := &fakeReviewEngine{questionsSkipped: tt.questionsSkipped}
+
+			err := runReviewWorkflow(context.Background(), engine, req, newStatusReporter(&bytes.Buffer{}, true), &stdout)
+			require.NoError(t, err)
+
+			var output core.ReviewOutput
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			assert.Equal(t, tt.wantPartial, output.Summary.Partial)
+			if tt.wantPartial {
+				assert.Equal(t, true, output.Metadata["partial"])
+				assert.EqualValues(t, tt.questionsSkipped, output.Metadata["questions_skipped"])
+				assert.Equal(t, tt.questionsSkipped, output.Summary.QuestionsSkipped)
+			} else {
+				assert.NotContains(t, output.Metadata, "partial")
+				assert.NotContains(t, stdout.String(), "questions_skipped")
+			}
+		})
+	}
+}
+
+func TestRunReviewWorkflow_MilestoneSkipped(t *testing.T) {
+	for _, skip := range []bool{false, true} {
+		var stdout bytes.Buffer
+		req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}}
+
+		err := runReviewWorkflow(context.Background(), &fakeReviewEngine{skipMilestone: skip}, req, newStatusReporter(&bytes.Buffer{}, true), &stdout)
+		require.NoError(t, err)
+
+		var output core.ReviewOutput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+		if skip {
+			assert.Equal(t, true, output.Metadata["milestone_skipped"])
+		} else {
+			assert.NotContains(t, output.Metadata, "milestone_skipped")
+		}
+	}
+}
+
+func TestRunReviewWorkflow_Calibration(t *testing.T) {
+	evaluations := []*core.QuestionEvaluation{
+		{Question: &core.WAFRQuestion{ID: "sec-1", Pillar: core.PillarSecurity}, ConfidenceScore: 0.35},
+		{Question: &core.WAFRQuestion{ID: "sec-2", Pillar: core.PillarSecurity}, ConfidenceScore: 0.85},
+		{Question: &core.WAFRQuestion{ID: "rel-1", Pillar: core.PillarReliability}, ConfidenceScore: 0.9},
+	}
+
+	for _, enabled := range []bool{false, true} {
+		var stdout, stderr bytes.Buffer
+		req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}, Calibration: enabled}
+
+		err := runReviewWorkflow(context.Background(), &fakeReviewEngine{evaluations: evaluations}, req, newStatusReporter(&stderr, false), &stdout)
+		require.NoError(t, err)
+
+		var output core.ReviewOutput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+		if !enabled {
+			assert.Nil(t, output.Calibration)
+			assert.NotContains(t, stderr.String(), "Confidence calibration")
+			continue
+		}
+
+		require.NotNil(t, output.Calibration)
+		assert.Equal(t, 3, output.Calibration.Questions)
+		require.Len(t, output.Calibration.Bands, core.CalibrationBands)
+		assert.Equal(t, 1, output.Calibration.Bands[3].Count)
+		assert.Equal(t, 1, output.Calibration.Bands[8].Count)
+		assert.Equal(t, 1, output.Calibration.Bands[9].Count)
+		assert.InDelta(t, 0.6, output.Calibration.Pillars["security"].AverageConfidence, 1e-9)
+		assert.Contains(t, stderr.String(), "Confidence calibration (3 questions, average 0.70):")
+		assert.Contains(t, stderr.String(), "  0.3-0.4: 1\n")
+	}
+}
+
+func TestRunReviewWorkflow_CoverageMatrix(t *testing.T) {
+	engine := &fakeReviewEngine{
+		resources: []core.Resource{
+			{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+			{Address: "aws_iam_role.app", Type: "aws_iam_role"},
+			{Address: "aws_sqs_queue.jobs", Type: "aws_sqs_queue"},
+		},
+		evaluations: []*core.QuestionEvaluation{
+			{
+				Question: &core.WAFRQuestion{ID: "data-rest"},
+				Evidence: []core.Evidence{{Resources: []string{"aws_s3_bucket.logs"}}},
+			},
+			{
+				Question: &core.WAFRQuestion{ID: "identities"},
+				Evidence: []core.Evidence{{Resources: []string{"aws_iam_role.app", "aws_s3_bucket.logs"}}},
+			},
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}, CoverageMatrix: true}
+
+	err := runReviewWorkflow(context.Background(), engine, req, newStatusReporter(&stderr, false), &stdout)
+	require.NoError(t, err)
+
+	var output core.ReviewOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.NotNil(t, output.CoverageMatrix)
+	assert.Equal(t, []core.ResourceCoverageOutput{
+		{Address: "aws_iam_role.app", Questions: []string{"identities"}},
+		{Address: "aws_s3_bucket.logs", Questions: []string{"data-rest", "identities"}},
+		{Address: "aws_sqs_queue.jobs", Questions: []string{}},
+	}, output.CoverageMatrix.Resources)
+	assert.Equal(t, []string{"aws_sqs_queue.jobs"}, output.CoverageMatrix.Uncited)
+	assert.Contains(t, stderr.String(), "Coverage matrix: 2 of 3 resources cited as evidence")
+	assert.Contains(t, stderr.String(), "Influenced no answer: aws_sqs_queue.jobs")
+
+	// The matrix is only built on request
+	stdout.Reset()
+	req.CoverageMatrix = false
+	require.NoError(t, runReviewWorkflow(context.Background(), engine, req, newStatusReporter(&stderr, false), &stdout))
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.NotContains(t, stdout.String(), "coverage_matrix")
+}
+
+func TestRunReviewWorkflow_DestructiveChanges(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}}
+	engine := &fakeReviewEngine{destructiveChanges: []core.DestructiveChange{
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Action: "delete"},
+	}}
+
+	err := runReviewWorkflow(context.Background(), engine, req, newStatusReporter(&stderr, false), &stdout)
+	require.NoError(t, err)
+
+	var output struct {
+		Metadata struct {
+			DestructiveChanges []core.DestructiveChangeOutput `json:"destructive_changes"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.Equal(t, []core.DestructiveChangeOutput{
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Action: "delete"},
+	}, output.Metadata.DestructiveChanges)
+	assert.Contains(t, stderr.String(), "Warning: plan will delete stateful resource aws_db_instance.main\n")
+}
+
+func TestRunReviewWorkflow_Timings(t *testing.T) {
+	var stdout bytes.Buffer
+	req := reviewRequest{WorkloadID: "my-app", Scope: core.ReviewScope{Level: core.ScopeLevelWorkload}}
+
+	err := runReviewWorkflow(context.Background(), &fakeReviewEngine{}, req, newStatusReporter(&bytes.Buffer{}, true), &stdout)
+	require.NoError(t, err)
+
+	var output struct {
+		Metadata struct {
+			Timings core.TimingsOutput `json:"timings"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.Equal(t, core.TimingsOutput{
+		Steps:   []core.StepTimingOutput{{Step: core.StepEvaluateQuestions, OffsetMS: 1000, DurationMS: 2000}},
+		TotalMS: 3000,
+	}, output.Metadata.Timings)
+}
+
+func TestRunReviewWorkflow_Baseline(t *testing.T) {
+	baseline, err := core.ReadBaseline(strings.NewReader(`{"pillars": {"security": {"max_high_risks": 1}}}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		highRisks    int
+		wantExceeded bool
+	}{
+		{name: "within baseline", highRisks: 1},
+		{name: "regression", highRisks: 3, wantExceeded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			req := reviewRequest{
+				WorkloadID:   "my-app",
+				Scope:        core.ReviewScope{Level: core.ScopeLevelWorkload},
+				Baseline:     baseline,
+				BaselineFile: "baseline.json",
+			}
+			engine := &fakeReviewEngine{pillarSummaries: map[core.Pillar]core.PillarSummary{
+				core.PillarSecurity: {QuestionsEvaluated: 1, HighRisks: tt.highRisks},
+			}}
+
+			err := runReviewWorkflow(context.Background(), engine, req, newStatusReporter(&stderr, false), &stdout)
+
+			// The results are written whether or not the baseline passes
+			var output core.ReviewOutput
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			require.NotNil(t, output.Baseline)
+			assert.Equal(t, "baseline.json", output.Baseline.File)
+			assert.Equal(t, !tt.wantExceeded, output.Baseline.Passed)
+
+			if !tt.wantExceeded {
+				require.NoError(t, err)
+				assert.Empty(t, output.Baseline.Regressions)
+				assert.NotContains(t, stderr.String(), "Baseline exceeded")
+				return
+			}
+
+			var baselineErr *core.BaselineExceededError
+			require.ErrorAs(t, err, &baselineErr)
+			assert.Equal(t, []core.Pillar{core.PillarSecurity}, baselineErr.Pillars)
+			assert.Equal(t, []core.BaselineRegressionOutput{
+				{Pillar: "security", Metric: core.BaselineMetricHighRisks, Baseline: 1, Actual: 3},
+			}, output.Baseline.Regressions)
+			assert.Contains(t, stderr.String(), "Baseline exceeded: security high_risks 3 (baseline 1)")
+		})
+	}
+}
+
+func TestRunReviewWorkflow_AdvisoryPillar(t *testing.T) {
+	baseline, err := core.ReadBaseline(strings.NewReader(`{"pillars": {"security": {"max_high_risks": 1}, "sustainability": {"max_high_risks": 0}}}`))
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	req := reviewRequest{
+		WorkloadID:   "my-app",
+		Scope:        core.ReviewScope{Level: core.ScopeLevelWorkload},
+		Baseline:     baseline,
+		BaselineFile: "baseline.json",
+	}
+	engine := &fakeReviewEngine{pillarSummaries: map[core.Pillar]core.PillarSummary{
+		core.PillarSecurity:       {QuestionsEvaluated: 1, HighRisks: 1},
+		core.PillarSustainability: {QuestionsEvaluated: 2, HighRisks: 2, Advisory: true},
+	}}
+
+	err = runReviewWorkflow(context.Background(), engine, req, newStatusReporter(&stderr, false), &stdout)
+
+	// The advisory pillar exceeds its baseline without failing the review
+	require.NoError(t, err)
+	var output core.ReviewOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.NotNil(t, output.Baseline)
+	assert.True(t, output.Baseline.Passed)
+	assert.Empty(t, output.Baseline.Regressions)
+	assert.Equal(t, []core.BaselineRegressionOutput{
+		{Pillar: "sustainability", Metric: core.BaselineMetricHighRisks, Baseline: 0, Actual: 2},
+	}, output.Baseline.Advisory)
+	assert.Contains(t, stderr.String(), "Baseline exceeded (advisory): sustainability high_risks 2 (baseline 0)")
+
+	// Its risks are still in the summary, labeled advisory
+	require.NotNil(t, output.Summary)
+	sustainability := output.Summary.PillarSummaries["sustainability"]
+	require.NotNil(t, sustainability)
+	assert.Equal(t, 2, sustainability.HighRisks)
+	assert.True(t, sustainability.Advisory)
+	assert.False(t, output.Summary.PillarSummaries["security"].Advisory)
+}
+
+func TestParseAdvisoryPillars(t *testing.T) {
+	pillars, err := parseAdvisoryPillars([]string{"sustainability", "cost-optimization", "Sustainability"})
+	require.NoError(t, err)
+	assert.Equal(t, []core.Pillar{core.PillarSustainability, core.PillarCostOptimization}, pillars)
+
+	_, err = parseAdvisoryPillars([]string{"greenness"})
+	require.ErrorIs(t, err, core.ErrInvalidPillar)
+	assert.Contains(t, err.Error(), `"greenness"`)
+}
+
+func TestLoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "baseline.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"pillars": {"reliability": {"max_medium_risks": 4}}}`), 0o644))
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"pillars": {"resilience": {}}}`), 0o644))
+
+	baseline, err := loadBaseline(valid)
+	require.NoError(t, err)
+	assert.Equal(t, 4, *baseline.Pillars[core.PillarReliability].MaxMediumRisks)
+
+	_, err = loadBaseline(invalid)
+	assert.ErrorIs(t, err, core.ErrInvalidPillar)
+	assert.Contains(t, err.Error(), invalid)
+
+	_, err = loadBaseline(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestFormatStrictModeWarnings(t *testing.T) {
+	err := &core.StrictModeError{Warnings: []core.AnalysisWarning{
+		{Kind: core.AnalysisWarningRedaction, Source: "main.tf", Message: "redacted AWS Access Key"},
+		{Kind: core.AnalysisWarningParse, Source: "notes.txt", Message: "skipped, not a Terraform file"},
+	}}
+
+	assert.Equal(t, `Strict mode failed on:
+  - redaction: main.tf: redacted AWS Access Key
+  - parse: notes.txt: skipped, not a Terraform file`, formatStrictModeWarnings(err))
+}
+
+func TestWAFRErrorExitCode(t *testing.T) {
+	tests := []struct {
+		errorCode string
+		wantCode  int
+		wantHint  string
+	}{
+		{errorCode: "AccessDeniedException", wantCode: ExitPermissionDenied, wantHint: "allow wellarchitected:ListAnswers"},
+		{errorCode: "UnrecognizedClientException", wantCode: ExitPermissionDenied, wantHint: "credentials are valid"},
+		{errorCode: "ResourceNotFoundException", wantCode: ExitResourceNotFound, wantHint: "workload exists"},
+		{errorCode: "ThrottlingException", wantCode: ExitGeneralError, wantHint: "waffle resume"},
+		{errorCode: "ValidationException", wantCode: ExitGeneralError},
+		{errorCode: "", wantCode: ExitGeneralError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.errorCode, func(t *testing.T) {
+			err := &core.WAFRAPIError{Operation: "ListAnswers", ErrorCode: tt.errorCode}
+			assert.Equal(t, tt.wantCode, wafrErrorExitCode(err))
+
+			hint := wafrErrorHint(err)
+			if tt.wantHint == "" {
+				assert.Empty(t, hint)
+			} else {
+				assert.Contains(t, hint, tt.wantHint)
+			}
+		})
+	}
+}
+
+// stubPolicySimulator allows every action except those in denied, which
+// are denied explicitly, and those in notAllowed, which no statement allows
+type stubPolicySimulator struct {
+	denied      map[string]bool
+	notAllowed  map[string]bool
+	simulateErr error
+}
+
+func (s *stubPolicySimulator) CallerARN(ctx context.Context) (string, error) {
+	return "arn:aws:iam::123456789012:user/dev", nil
+}
+
+func (s *stubPolicySimulator) SimulatePrincipalPolicy(ctx context.Context, principalARN string, actions []string) (map[string]iamtypes.PolicyEvaluationDecisionType, error) {
+	if s.simulateErr != nil {
+		return nil, s.simulateErr
+	}
+	decisions := make(map[string]iamtypes.PolicyEvaluationDecisionType, len(actions))
+	for _, action := range actions {
+		switch {
+		case s.denied[action]:
+			decisions[action] = iamtypes.PolicyEvaluationDecisionTypeExplicitDeny
+		case s.notAllowed[action]:
+			decisions[action] = iamtypes.PolicyEvaluationDecisionTypeImplicitDeny
+		default:
+			decisions[action] = iamtypes.PolicyEvaluationDecisionTypeAllowed
+		}
+	}
+	return decisions, nil
+}
+
+func TestPreflightPermissions(t *testing.T) {
+	tests := []struct {
+		name       string
+		simulator  *stubPolicySimulator
+		wantErr    string
+		wantStatus string
+	}{
+		{
+			name:      "all allowed",
+			simulator: &stubPolicySimulator{},
+		},
+		{
+			name:      "required action denied",
+			simulator: &stubPolicySimulator{denied: map[string]bool{"bedrock:InvokeModel": true}},
+			wantErr:   "missing IAM permissions for arn:aws:iam::123456789012:user/dev: bedrock:InvokeModel",
+		},
+		{
+			name:       "model access scoped to the model",
+			simulator:  &stubPolicySimulator{notAllowed: map[string]bool{"bedrock:InvokeModel": true}},
+			wantStatus: "Warning: bedrock:InvokeModel is not allowed on every resource",
+		},
+		{
+			name:       "optional action denied",
+			simulator:  &stubPolicySimulator{denied: map[string]bool{"wellarchitected:DeleteWorkload": true}},
+			wantStatus: "Warning: wellarchitected:DeleteWorkload is not allowed; --cleanup will fail",
+		},
+		{
+			name:       "simulation not allowed",
+			simulator:  &stubPolicySimulator{simulateErr: errors.New("iam SimulatePrincipalPolicy failed [AccessDenied]: denied")},
+			wantStatus: "Permission preflight skipped: iam SimulatePrincipalPolicy failed [AccessDenied]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status bytes.Buffer
+			err := preflightPermissions(context.Background(), tt.simulator, config.RequiredActions, core.NewCLIProgressReporter(&status))
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantStatus != "" {
+				assert.Contains(t, status.String(), tt.wantStatus)
+			} else {
+				assert.Empty(t, status.String())
+			}
+		})
+	}
+}
+
+func TestDetectGitSHA(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "detached HEAD",
+			files: map[string]string{"HEAD": sha + "\n"},
+			want:  "0123456789ab",
+		},
+		{
+			name: "loose branch ref",
+			files: map[string]string{
+				"HEAD":            "ref: refs/heads/main\n",
+				"refs/heads/main": sha + "\n",
+			},
+			want: "0123456789ab",
+		},
+		{
+			name: "packed branch ref",
+			files: map[string]string{
+				"HEAD":        "ref: refs/heads/main\n",
+				"packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" + sha + " refs/heads/main\n",
+			},
+			want: "0123456789ab",
+		},
+		{
+			name:  "unborn branch",
+			files: map[string]string{"HEAD": "ref: refs/heads/main\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(repo, ".git", filepath.FromSlash(name))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+			dir := filepath.Join(repo, "infra")
+			require.NoError(t, os.MkdirAll(dir, 0755))
+
+			assert.Equal(t, tt.want, detectGitSHA(dir))
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func pillarPtr(p core.Pillar) *core.Pillar {
+	return &p
+}
+
+func TestParseReviewScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      string
+		pillar     string
+		questionID string
+		want       core.ReviewScope
+		wantErrs   []error
+	}{
+		{
+			name:  "workload",
+			scope: "workload",
+			want:  core.ReviewScope{Level: core.ScopeLevelWorkload},
+		},
+		{
+			name:   "pillar alias",
+			scope:  "Pillar",
+			pillar: "cost-optimization",
+			want:   core.ReviewScope{Level: core.ScopeLevelPillar, Pillar: pillarPtr(core.PillarCostOptimization)},
+		},
+		{
+			name:       "question",
+			scope:      "question",
+			questionID: "sec_data_1",
+			want:       core.ReviewScope{Level: core.ScopeLevelQuestion, QuestionID: "sec_data_1"},
+		},
+		{
+			name:       "bad pillar and stray question ID",
+			scope:      "pillar",
+			pillar:     "securty",
+			questionID: "sec_data_1",
+			wantErrs:   []error{core.ErrInvalidPillar, core.ErrUnexpectedQuestionID},
+		},
+		{
+			name:     "missing question ID and stray pillar",
+			scope:    "question",
+			pillar:   "security",
+			wantErrs: []error{core.ErrQuestionIDRequired, core.ErrUnexpectedPillar},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReviewScope(tt.scope, tt.pillar, tt.questionID)
+			if len(tt.wantErrs) > 0 {
+				require.Error(t, err)
+				for _, want := range tt.wantErrs {
+					assert.ErrorIs(t, err, want)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseReviewScope_ConflictingFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      string
+		pillar     string
+		questionID string
+		wantErr    error
+		wantMsg    string
+	}{
+		{
+			name:    "workload scope with pillar",
+			scope:   "workload",
+			pillar:  "security",
+			wantErr: core.ErrUnexpectedPillar,
+			wantMsg: "pillar is only valid when scope level is pillar, got pillar 'security' with workload scope",
+		},
+		{
+			name:       "workload scope with question ID",
+			scope:      "workload",
+			questionID: "sec_data_1",
+			wantErr:    core.ErrUnexpectedQuestionID,
+			wantMsg:    "question ID is only valid when scope level is question, got question ID 'sec_data_1' with workload scope",
+		},
+		{
+			name:       "pillar scope with question ID",
+			scope:      "pillar",
+			pillar:     "reliability",
+			questionID: "rel_1",
+			wantErr:    core.ErrUnexpectedQuestionID,
+			wantMsg:    "question ID is only valid when scope level is question, got question ID 'rel_1' with pillar scope",
+		},
+		{
+			name:       "question scope with pillar",
+			scope:      "question",
+			pillar:     "cost",
+			questionID: "cost_1",
+			wantErr:    core.ErrUnexpectedPillar,
+			wantMsg:    "pillar is only valid when scope level is pillar, got pillar 'costOptimization' with question scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseReviewScope(tt.scope, tt.pillar, tt.questionID)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantMsg, formatScopeError(err))
+		})
+	}
+}
+
+func TestCheckReviewFlagConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantMsg string
+	}{
+		{
+			name: "compatible flags",
+			args: []string{"--interactive", "--interactive-threshold", "0.4", "--graph-output", "graph.dot", "--graph-format", "dot", "--preserve-answers", "--overwrite", "--confirm-submit", "--yes"},
+		},
+		{
+			name:    "quiet and verbose",
+			args:    []string{"-q", "-v"},
+			wantMsg: "--quiet and --verbose cannot be used together",
+		},
+		{
+			name:    "interactive without status",
+			args:    []string{"--interactive", "--no-status"},
+			wantMsg: "--interactive cannot be used with --no-status, which is for unattended runs",
+		},
+		{
+			name:    "interactive threshold without interactive",
+			args:    []string{"--interactive-threshold", "0.4"},
+			wantMsg: "--interactive-threshold requires --interactive",
+		},
+		{
+			name:    "yes without confirm submit",
+			args:    []string{"--yes"},
+			wantMsg: "--yes requires --confirm-submit",
+		},
+		{
+			name:    "graph format without graph output",
+			args:    []string{"--graph-format", "dot"},
+			wantMsg: "--graph-format requires --graph-output",
+		},
+		{
+			name:    "state source and plan file",
+			args:    []string{"--state-source", "tfc://acme/payments-prod", "--plan-file", "plan.json"},
+			wantMsg: "--state-source and --plan-file cannot be used together",
+		},
+		{
+			name:    "require fresh plan and state source",
+			args:    []string{"--require-fresh-plan", "--state-source", "tfc://acme/payments-prod"},
+			wantMsg: "--require-fresh-plan cannot be used with --state-source, which has no configuration to compare",
+		},
+		{
+			name:    "milestone name without milestones",
+			args:    []string{"--milestone-name", "Release 2.0", "--no-milestone"},
+			wantMsg: "--milestone-name cannot be used with --no-milestone",
+		},
+		{
+			name:    "overwrite without preserving answers",
+			args:    []string{"--overwrite"},
+			wantMsg: "--overwrite requires --preserve-answers",
+		},
+		{
+			name: "every conflict is reported",
+			args: []string{"-q", "-v", "--graph-format", "dot"},
+			wantMsg: `conflicting flags:
+  - --quiet and --verbose cannot be used together
+  - --graph-format requires --graph-output`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().BoolP("quiet", "q", false, "")
+			cmd.Flags().BoolP("verbose", "v", false, "")
+			cmd.Flags().Bool("interactive", false, "")
+			cmd.Flags().Float64("interactive-threshold", 0, "")
+			cmd.Flags().Bool("no-status", false, "")
+			cmd.Flags().Bool("confirm-submit", false, "")
+			cmd.Flags().Bool("yes", false, "")
+			cmd.Flags().String("graph-output", "", "")
+			cmd.Flags().String("graph-format", core.GraphFormatJSON, "")
+			cmd.Flags().Bool("no-milestone", false, "")
+			cmd.Flags().String("milestone-name", "", "")
+			cmd.Flags().String("state-source", "", "")
+			cmd.Flags().String("plan-file", "", "")
+			cmd.Flags().Bool("require-fresh-plan", false, "")
+			cmd.Flags().Bool("preserve-answers", false, "")
+			cmd.Flags().Bool("overwrite", false, "")
+			require.NoError(t, cmd.Flags().Parse(tt.args))
+
+			err := checkReviewFlagConflicts(cmd)
+
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantMsg, formatJoinedError("conflicting flags", err))
+		})
+	}
+}
+
+func TestParseReviewScope_InvalidLevel(t *testing.T) {
+	_, err := parseReviewScope("account", "", "")
+	assert.EqualError(t, err, "invalid scope 'account', must be 'workload', 'pillar', or 'question'")
+}
+
+func TestFormatScopeError(t *testing.T) {
+	_, err := parseReviewScope("pillar", "securty", "sec_data_1")
+	require.Error(t, err)
+
+	assert.Equal(t, `invalid review scope:
+  - invalid pillar 'securty', must be one of: operationalExcellence, security, reliability, performance, costOptimization, sustainability
+  - question ID is only valid when scope level is question, got question ID 'sec_data_1' with pillar scope`, formatScopeError(err))
+
+	_, err = parseReviewScope("pillar", "", "")
+	assert.Equal(t, core.ErrPillarRequired.Error(), formatScopeError(err))
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+	"github.com/waffle/waffle/internal/report"
+	"github.com/waffle/waffle/internal/session"
+)
+
+//go:embed fixtures/selftest
+var selftestFixtures embed.FS
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that the review pipeline works, without AWS",
+	Long: `Run a complete review of a small bundled Terraform workload and report
+whether each step passed. The Well-Architected Tool and Bedrock are replaced by
+a local stand-in, so no AWS credentials or network access are needed. Sessions
+are kept in a temporary directory that is removed afterwards.
+
+Use it to validate an installation or container image before real use. The
+command exits with code 1 when any step fails.`,
+	Example: `  waffle selftest`,
+	Args:    cobra.NoArgs,
+	RunE:    runSelftestCmd,
+}
+
+// Self-test steps outside the review workflow
+const (
+	selftestStepInitiate = "initiate_review"
+	selftestStepReport   = "report"
+)
+
+// selftestReviewSteps are the review workflow steps a self-test expects to run
+var selftestReviewSteps = []string{
+	core.StepIaCAnalysis,
+	core.StepRetrieveQuestions,
+	core.StepEvaluateQuestions,
+	core.StepSubmitAnswers,
+	core.StepImprovementPlan,
+	core.StepCreateMilestone,
+}
+
+// selftestStep is the outcome of one self-test step. Steps after a failure
+// are skipped.
+type selftestStep struct {
+	Name    string
+	Err     error
+	Skipped bool
+}
+
+// selftestQuestion is a question asked by the self-test stand-in for AWS
+type selftestQuestion struct {
+	question *core.WAFRQuestion
+	// evidenceType is the resource type whose presence selects the
+	// question's choice
+	evidenceType string
+}
+
+// selftestQuestions are matched to the bundled fixtures so that one practice
+// is met and one is not
+func selftestQuestions() []selftestQuestion {
+	return []selftestQuestion{
+		{
+			question: &core.WAFRQuestion{
+				ID:     "data-rest",
+				Pillar: core.PillarSecurity,
+				Title:  "How do you protect your data at rest?",
+				Choices: []core.Choice{
+					{ID: "sec_protect_data_rest_encrypt", Title: "Enforce encryption at rest"},
+				},
+			},
+			evidenceType: "aws_s3_bucket_server_side_encryption_configuration",
+		},
+		{
+			question: &core.WAFRQuestion{
+				ID:     "backing-up-data",
+				Pillar: core.PillarReliability,
+				Title:  "How do you back up data?",
+				Choices: []core.Choice{
+					{ID: "rel_backing_up_data_automated_backups_data", Title: "Perform data backup automatically"},
+				},
+			},
+			evidenceType: "aws_backup_plan",
+		},
+	}
+}
+
+// selftestWAFR stands in for the Well-Architected Tool and Bedrock during a
+// self-test. A question's choice is selected when the workload has a resource
+// of its evidence type, and every unmet question becomes an improvement plan
+// item.
+type selftestWAFR struct {
+	questions []selftestQuestion
+	// submitted maps question IDs to the choice IDs submitted for them
+	submitted map[string][]string
+}
+
+func newSelftestWAFR() *selftestWAFR {
+	return &selftestWAFR{
+		questions: selftestQuestions(),
+		submitted: make(map[string][]string),
+	}
+}
+
+func (w *selftestWAFR) CreateWorkload(ctx context.Context, workloadID string, description string) (string, error) {
+	return "selftest-" + workloadID, nil
+}
+
+func (w *selftestWAFR) GetQuestions(ctx context.Context, awsWorkloadID string, scope core.ReviewScope) ([]*core.WAFRQuestion, error) {
+	questions := make([]*core.WAFRQuestion, 0, len(w.questions))
+	for _, q := range w.questions {
+		questions = append(questions, q.question)
+	}
+	return questions, nil
+}
+
+func (w *selftestWAFR) EvaluateQuestion(ctx context.Context, question *core.WAFRQuestion, workloadModel *core.WorkloadModel) (*core.QuestionEvaluation, error) {
+	var evidenceType string
+	for _, q := range w.questions {
+		if q.question.ID == question.ID {
+			evidenceType = q.evidenceType
+		}
+	}
+	if evidenceType == "" {
+		return nil, fmt.Errorf("unknown self-test question %s", question.ID)
+	}
+
+	var addresses []string
+	for _, resource := range workloadModel.Resources {
+		if resource.Type == evidenceType {
+			addresses = append(addresses, resource.Address)
+		}
+	}
+
+	evaluation := &core.QuestionEvaluation{
+		Question:        question,
+		SelectedChoices: []core.Choice{},
+		Evidence:        []core.Evidence{},
+		ConfidenceScore: 0.9,
+		Source:          core.SourceStaticCheck,
+	}
+	if len(addresses) == 0 {
+		evaluation.Notes = fmt.Sprintf("No %s resource found", evidenceType)
+		return evaluation, nil
+	}
+
+	choice := question.Choices[0]
+	evaluation.SelectedChoices = append(evaluation.SelectedChoices, choice)
+	evaluation.Evidence = append(evaluation.Evidence, core.Evidence{
+		ChoiceID:    choice.ID,
+		Explanation: fmt.Sprintf("Found %s", evidenceType),
+		Resources:   addresses,
+		Confidence:  0.9,
+	})
+	evaluation.Notes = fmt.Sprintf("Selected %s", choice.ID)
+	return evaluation, nil
+}
+
+func (w *selftestWAFR) SubmitAnswer(ctx context.Context, awsWorkloadID string, questionID string, evaluation *core.QuestionEvaluation) error {
+	choiceIDs := make([]string, 0, len(evaluation.SelectedChoices))
+	for _, choice := range evaluation.SelectedChoices {
+		choiceIDs = append(choiceIDs, choice.ID)
+	}
+	w.submitted[questionID] = choiceIDs
+	return nil
+}
+
+func (w *selftestWAFR) GetImprovementPlan(ctx context.Context, awsWorkloadID string) (*core.ImprovementPlan, error) {
+	plan := &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{}}
+	for _, q := range w.questions {
+		choiceIDs, ok := w.submitted[q.question.ID]
+		if !ok || len(choiceIDs) > 0 {
+			continue
+		}
+		description := fmt.Sprintf("Not met: %s", q.question.Choices[0].Title)
+		plan.Items = append(plan.Items, &core.ImprovementPlanItem{
+			ID: "selftest-" + q.question.ID,
+			Risk: &core.Risk{
+				ID:          q.question.ID,
+				Question:    q.question,
+				Pillar:      q.question.Pillar,
+				Severity:    core.RiskLevelMedium,
+				Description: description,
+				Source:      core.SourceStaticCheck,
+			},
+			Description: description,
+			Priority:    50,
+		})
+	}
+	return plan, nil
+}
+
+func (w *selftestWAFR) CreateMilestone(ctx context.Context, awsWorkloadID string, milestoneName string) (string, error) {
+	return "1", nil
+}
+
+// selftestProgress records the review steps as they start
+type selftestProgress struct {
+	steps []string
+}
+
+func (p *selftestProgress) ReportStep(step string, message string) {
+	p.steps = append(p.steps, step)
+}
+
+func (p *selftestProgress) ReportProgress(current, total int, message string) {}
+
+func (p *selftestProgress) ReportCompletion(summary *core.ResultsSummary) {}
+
+// writeSelftestFixtures copies the fixture files into dir
+func writeSelftestFixtures(fixtures fs.FS, dir string) error {
+	return fs.WalkDir(fixtures, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := fs.ReadFile(fixtures, path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+// runSelftest reviews fixtures with wafr standing in for AWS, keeping its
+// files under workDir, then renders the offline reports from the saved
+// session. It returns the outcome of every step in order; an error is only
+// returned when the self-test could not be set up.
+func runSelftest(ctx context.Context, workDir string, fixtures fs.FS, wafr *selftestWAFR) ([]selftestStep, error) {
+	iacDir := filepath.Join(workDir, "iac")
+	if err := writeSelftestFixtures(fixtures, iacDir); err != nil {
+		return nil, fmt.Errorf("failed to write self-test fixtures: %w", err)
+	}
+	sessions, err := session.NewManager(filepath.Join(workDir, "sessions"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session manager: %w", err)
+	}
+
+	names := append(append([]string{selftestStepInitiate}, selftestReviewSteps...), selftestStepReport)
+	steps := make([]selftestStep, 0, len(names))
+	fail := func(err error) []selftestStep {
+		steps = append(steps, selftestStep{Name: names[len(steps)], Err: err})
+		for _, name := range names[len(steps):] {
+			steps = append(steps, selftestStep{Name: name, Skipped: true})
+		}
+		return steps
+	}
+	pass := func() {
+		steps = append(steps, selftestStep{Name: names[len(steps)]})
+	}
+
+	engine := core.NewEngine(sessions, iac.NewAnalyzerWithDir(iacDir), wafr, nil, report.NewGenerator())
+	reviewSession, err := engine.InitiateReview(ctx, "waffle-selftest", core.ReviewScope{Level: core.ScopeLevelWorkload})
+	if err != nil {
+		return fail(err), nil
+	}
+	pass()
+
+	progress := &selftestProgress{}
+	results, reviewErr := engine.ExecuteReviewWithProgress(ctx, reviewSession, progress)
+
+	// The step that failed is the last one the engine started
+	failedStep := selftestReviewSteps[0]
+	if len(progress.steps) > 0 {
+		failedStep = progress.steps[len(progress.steps)-1]
+	}
+	started := make(map[string]bool, len(progress.steps))
+	for _, step := range progress.steps {
+		started[step] = true
+	}
+
+	for _, step := range selftestReviewSteps {
+		if reviewErr != nil && step == failedStep {
+			return fail(reviewErr), nil
+		}
+		if !started[step] {
+			return fail(errors.New("step did not run")), nil
+		}
+		if reviewErr == nil {
+			if err := checkSelftestStep(step, reviewSession, results, wafr); err != nil {
+				return fail(err), nil
+			}
+		}
+		pass()
+	}
+
+	saved, err := sessions.LoadSession(ctx, reviewSession.SessionID)
+	if err != nil {
+		return fail(fmt.Errorf("failed to load session: %w", err)), nil
+	}
+	formats, _ := offlineFormats(report.DefaultRegistry().Formats())
+	for _, format := range formats {
+		data, err := format.Generate(ctx, report.NewGenerator(), saved)
+		if err != nil {
+			return fail(fmt.Errorf("failed to generate %s report: %w", format.Name, err)), nil
+		}
+		if len(data) == 0 {
+			return fail(fmt.Errorf("%s report is empty", format.Name)), nil
+		}
+	}
+	pass()
+
+	return steps, nil
+}
+
+// checkSelftestStep checks the outcome of a step of a review that completed
+func checkSelftestStep(step string, reviewSession *core.ReviewSession, results *core.ReviewResults, wafr *selftestWAFR) error {
+	switch step {
+	case core.StepIaCAnalysis:
+		if reviewSession.WorkloadModel == nil || len(reviewSession.WorkloadModel.Resources) == 0 {
+			return errors.New("no resources found in the fixtures")
+		}
+	case core.StepRetrieveQuestions, core.StepEvaluateQuestions:
+		if len(results.Evaluations) == 0 {
+			return errors.New("no questions were evaluated")
+		}
+	case core.StepSubmitAnswers:
+		if len(wafr.submitted) != len(results.Evaluations) {
+			return fmt.Errorf("%d of %d answers submitted", len(wafr.submitted), len(results.Evaluations))
+		}
+	case core.StepImprovementPlan:
+		if results.ImprovementPlan == nil || len(results.ImprovementPlan.Items) == 0 {
+			return errors.New("improvement plan is empty")
+		}
+	case core.StepCreateMilestone:
+		if reviewSession.MilestoneID == "" {
+			return errors.New("no milestone was created")
+		}
+	}
+	return nil
+}
+
+// writeSelftestReport writes one line per step and reports whether every
+// step passed
+func writeSelftestReport(w io.Writer, steps []selftestStep) bool {
+	passed := true
+	for _, step := range steps {
+		switch {
+		case step.Err != nil:
+			passed = false
+			fmt.Fprintf(w, "FAIL  %s: %v\n", step.Name, step.Err)
+		case step.Skipped:
+			fmt.Fprintf(w, "SKIP  %s\n", step.Name)
+		default:
+			fmt.Fprintf(w, "PASS  %s\n", step.Name)
+		}
+	}
+	if passed {
+		fmt.Fprintln(w, "Self-test passed")
+	} else {
+		fmt.Fprintln(w, "Self-test failed")
+	}
+	return passed
+}
+
+func runSelftestCmd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	fixtures, err := fs.Sub(selftestFixtures, "fixtures/selftest")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	workDir, err := os.MkdirTemp("", "waffle-selftest-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create temporary directory: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	steps, err := runSelftest(ctx, workDir, fixtures, newSelftestWAFR())
+	os.RemoveAll(workDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	if !writeSelftestReport(os.Stdout, steps) {
+		os.Exit(ExitGeneralError)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundledSelftestFixtures(t *testing.T) fs.FS {
+	fixtures, err := fs.Sub(selftestFixtures, "fixtures/selftest")
+	require.NoError(t, err)
+	return fixtures
+}
+
+func TestRunSelftest_BundledFixtures(t *testing.T) {
+	wafr := newSelftestWAFR()
+	steps, err := runSelftest(context.Background(), t.TempDir(), bundledSelftestFixtures(t), wafr)
+	require.NoError(t, err)
+
+	var names []string
+	for _, step := range steps {
+		assert.NoError(t, step.Err, step.Name)
+		assert.False(t, step.Skipped, step.Name)
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{
+		"initiate_review",
+		"iac_analysis",
+		"retrieve_questions",
+		"evaluate_questions",
+		"submit_answers",
+		"improvement_plan",
+		"create_milestone",
+		"report",
+	}, names)
+
+	// The fixtures meet one practice and miss the other
+	assert.Equal(t, map[string][]string{
+		"data-rest":       {"sec_protect_data_rest_encrypt"},
+		"backing-up-data": {},
+	}, wafr.submitted)
+
+	var buf bytes.Buffer
+	assert.True(t, writeSelftestReport(&buf, steps))
+	assert.Contains(t, buf.String(), "PASS  create_milestone\n")
+	assert.Contains(t, buf.String(), "Self-test passed\n")
+}
+
+func TestRunSelftest_BrokenStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		fixtures   func(t *testing.T) fs.FS
+		wafr       func() *selftestWAFR
+		failedStep string
+	}{
+		{
+			name: "unparseable fixtures",
+			fixtures: func(t *testing.T) fs.FS {
+				return fstest.MapFS{"main.tf": {Data: []byte(`resource "aws_s3_bucket" {`)}}
+			},
+			wafr:       newSelftestWAFR,
+			failedStep: "iac_analysis",
+		},
+		{
+			name:     "no questions",
+			fixtures: bundledSelftestFixtures,
+			wafr: func() *selftestWAFR {
+				wafr := newSelftestWAFR()
+				wafr.questions = nil
+				return wafr
+			},
+			// The engine only fails once it has nothing to evaluate
+			failedStep: "evaluate_questions",
+		},
+		{
+			name: "every practice met",
+			fixtures: func(t *testing.T) fs.FS {
+				return fstest.MapFS{"main.tf": {Data: []byte(`
+resource "aws_s3_bucket_server_side_encryption_configuration" "assets" {
+  bucket = "assets"
+}
+
+resource "aws_backup_plan" "daily" {
+  name = "daily"
+}
+`)}}
+			},
+			wafr:       newSelftestWAFR,
+			failedStep: "improvement_plan",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := runSelftest(context.Background(), t.TempDir(), tt.fixtures(t), tt.wafr())
+			require.NoError(t, err)
+
+			failed := false
+			for _, step := range steps {
+				switch {
+				case step.Name == tt.failedStep:
+					assert.Error(t, step.Err)
+					failed = true
+				case failed:
+					assert.True(t, step.Skipped, step.Name)
+				default:
+					assert.NoError(t, step.Err, step.Name)
+				}
+			}
+			assert.True(t, failed)
+
+			var buf bytes.Buffer
+			assert.False(t, writeSelftestReport(&buf, steps))
+			assert.Contains(t, buf.String(), "FAIL  "+tt.failedStep+": ")
+			assert.Contains(t, buf.String(), "Self-test failed\n")
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/metrics"
+)
+
+// serveShutdownTimeout bounds how long in-flight scrapes may take on shutdown
+const serveShutdownTimeout = 5 * time.Second
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Expose operational metrics over HTTP",
+	Long: `Start an HTTP server exposing Waffle's Prometheus metrics at /metrics
+and a liveness probe at /healthz.
+
+Reviews and resumed sessions run in processes of their own and save the
+metrics they record to metrics.state_dir. The server adds up the metrics of
+every process saved there on each scrape, merging older files into a single
+cumulative file.
+
+Metrics must be enabled with metrics.enabled in the configuration file.`,
+	Example: `  # Serve metrics on the configured address
+  waffle serve
+
+  # Serve metrics on a specific address
+  waffle serve --listen 127.0.0.1:9100`,
+	RunE: runServe,
+}
+
+// metricsFromConfig returns the process-wide metrics when they are enabled.
+// It returns nil otherwise, which instrumented components treat as disabled.
+func metricsFromConfig(cfg *config.Config) *metrics.Metrics {
+	if !cfg.Metrics.Enabled {
+		return nil
+	}
+	_, m := metrics.Default()
+	return m
+}
+
+// metricsSnapshotFile is the file this process saves its metrics to in the
+// metrics state directory
+var metricsSnapshotFile = fmt.Sprintf("%d-%d.json", os.Getpid(), time.Now().UnixNano())
+
+// saveMetricsSnapshot saves the metrics recorded by this process for
+// `waffle serve` to expose. Failures are logged and do not fail the command.
+func saveMetricsSnapshot(cfg *config.Config) {
+	if !cfg.Metrics.Enabled || cfg.Metrics.StateDir == "" {
+		return
+	}
+	registry, _ := metrics.Default()
+	if err := registry.WriteSnapshotFile(filepath.Join(cfg.Metrics.StateDir, metricsSnapshotFile)); err != nil {
+		logging.GetLogger().Warn("failed to save metrics", "error", err)
+	}
+}
+
+// newMetricsMux routes the metrics and health endpoints
+func newMetricsMux(metricsHandler http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsHandler)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	logger := logging.GetLogger()
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	if !cfg.Metrics.Enabled {
+		fmt.Fprintln(os.Stderr, "Error: metrics are disabled; set metrics.enabled to true in the configuration file")
+		os.Exit(ExitInvalidArguments)
+	}
+
+	listenAddress := cfg.Metrics.ListenAddress
+	if listen, _ := cmd.Flags().GetString("listen"); listen != "" {
+		listenAddress = listen
+	}
+
+	server := &http.Server{
+		Addr:              listenAddress,
+		Handler:           newMetricsMux(metrics.SnapshotHandler(cfg.Metrics.StateDir)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", listenAddress)
+	logger.Info("metrics server started", "listen_address", listenAddress, "state_dir", cfg.Metrics.StateDir)
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: metrics server failed: %v\n", err)
+			logger.Error("metrics server failed", "error", err)
+			os.Exit(ExitGeneralError)
+		}
+	case <-ctx.Done():
+		logger.Info("shutting down metrics server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("metrics server shutdown failed", "error", err)
+		}
+	}
+
+	return nil
+}
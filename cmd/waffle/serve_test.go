@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+	"github.com/waffle/waffle/internal/metrics"
+	"github.com/waffle/waffle/internal/session"
+)
+
+func TestMetricsFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	assert.Nil(t, metricsFromConfig(cfg))
+
+	cfg.Metrics.Enabled = true
+	_, want := metrics.Default()
+	assert.Same(t, want, metricsFromConfig(cfg))
+}
+
+func TestNewMetricsMux(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := metrics.New(registry)
+	m.QuestionsEvaluated.Inc("security")
+	mux := newMetricsMux(registry.Handler())
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `waffle_questions_evaluated_total{pillar="security"} 1`)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// metricsWAFREvaluator answers every question without calling AWS. Other
+// calls panic on the nil embedded evaluator.
+type metricsWAFREvaluator struct {
+	core.WAFREvaluator
+}
+
+func (e *metricsWAFREvaluator) GetQuestions(ctx context.Context, awsWorkloadID string, scope core.ReviewScope) ([]*core.WAFRQuestion, error) {
+	return []*core.WAFRQuestion{{ID: "data-rest", Pillar: core.PillarSecurity, Title: "How do you protect your data at rest?"}}, nil
+}
+
+func (e *metricsWAFREvaluator) EvaluateQuestion(ctx context.Context, question *core.WAFRQuestion, workloadModel *core.WorkloadModel) (*core.QuestionEvaluation, error) {
+	return &core.QuestionEvaluation{Question: question, SelectedChoices: []core.Choice{{ID: "sec_data_rest_encrypt_at_rest"}}, ConfidenceScore: 0.9}, nil
+}
+
+func (e *metricsWAFREvaluator) SubmitAnswer(ctx context.Context, awsWorkloadID string, questionID string, evaluation *core.QuestionEvaluation) error {
+	return nil
+}
+
+func (e *metricsWAFREvaluator) GetImprovementPlan(ctx context.Context, awsWorkloadID string) (*core.ImprovementPlan, error) {
+	return &core.ImprovementPlan{}, nil
+}
+
+func (e *metricsWAFREvaluator) CreateMilestone(ctx context.Context, awsWorkloadID string, milestoneName string) (string, error) {
+	return "milestone-1", nil
+}
+
+func TestServeMetrics_CountsReviewsOfOtherProcesses(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.StateDir = t.TempDir()
+	served := newMetricsMux(metrics.SnapshotHandler(cfg.Metrics.StateDir))
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		served.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+	assert.NotContains(t, scrape(), `waffle_questions_evaluated_total{pillar="security"}`)
+
+	// The review records on the process-wide metrics, as the review command does
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`resource "aws_s3_bucket" "data" {
+  bucket = "acme-data"
+}
+`), 0644))
+	sessions, err := session.NewManager(t.TempDir())
+	require.NoError(t, err)
+	engine := core.NewEngine(sessions, iac.NewAnalyzerWithDir(dir), &metricsWAFREvaluator{}, nil, nil)
+	engine.SetMetrics(metricsFromConfig(cfg))
+	_, err = engine.ExecuteReview(context.Background(), &core.ReviewSession{
+		SessionID:     "session-1",
+		WorkloadID:    "my-app",
+		AWSWorkloadID: "abc123",
+		Scope:         core.ReviewScope{Level: core.ScopeLevelWorkload},
+		Status:        core.SessionStatusCreated,
+	})
+	require.NoError(t, err)
+	saveMetricsSnapshot(cfg)
+
+	// Other tests record on the process-wide metrics too
+	want := metricsFromConfig(cfg).QuestionsEvaluated.Value(string(core.PillarSecurity))
+	require.GreaterOrEqual(t, want, 1.0)
+	assert.Contains(t, scrape(), fmt.Sprintf(`waffle_questions_evaluated_total{pillar="security"} %g`, want))
+}
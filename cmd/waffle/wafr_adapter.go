@@ -37,6 +37,30 @@ func (a *WAFREvaluatorAdapter) CreateWorkload(
 	return a.evaluator.CreateWorkload(ctx, workloadID, description)
 }
 
+// UpdateWorkloadDescription replaces the description of an existing workload
+func (a *WAFREvaluatorAdapter) UpdateWorkloadDescription(
+	ctx context.Context,
+	awsWorkloadID string,
+	description string,
+) error {
+	return a.evaluator.UpdateWorkloadDescription(ctx, awsWorkloadID, description)
+}
+
+// DeleteWorkload deletes a workload created by Waffle
+func (a *WAFREvaluatorAdapter) DeleteWorkload(ctx context.Context, awsWorkloadID string) error {
+	return a.evaluator.DeleteWorkload(ctx, awsWorkloadID)
+}
+
+// GetLensVersion returns the lens version applied to a workload
+func (a *WAFREvaluatorAdapter) GetLensVersion(ctx context.Context, awsWorkloadID string) (string, error) {
+	return a.evaluator.GetLensVersion(ctx, awsWorkloadID)
+}
+
+// GetCurrentLensVersion returns the lens version new workloads are created with
+func (a *WAFREvaluatorAdapter) GetCurrentLensVersion(ctx context.Context) (string, error) {
+	return a.evaluator.GetCurrentLensVersion(ctx)
+}
+
 // GetQuestions retrieves WAFR questions based on scope
 func (a *WAFREvaluatorAdapter) GetQuestions(
 	ctx context.Context,
@@ -46,6 +70,15 @@ func (a *WAFREvaluatorAdapter) GetQuestions(
 	return a.evaluator.GetQuestions(ctx, awsWorkloadID, scope)
 }
 
+// GetExistingAnswers returns the answers the workload already has
+func (a *WAFREvaluatorAdapter) GetExistingAnswers(
+	ctx context.Context,
+	awsWorkloadID string,
+	questions []*core.WAFRQuestion,
+) (map[string]core.ExistingAnswer, error) {
+	return a.evaluator.GetExistingAnswers(ctx, awsWorkloadID, questions)
+}
+
 // EvaluateQuestion evaluates a single question against the workload
 func (a *WAFREvaluatorAdapter) EvaluateQuestion(
 	ctx context.Context,
@@ -73,7 +106,7 @@ func (a *WAFREvaluatorAdapter) GetImprovementPlan(
 	// Get the workload model for this AWS workload ID (may be nil)
 	workloadModel := a.workloadModels[awsWorkloadID]
 	// Pass the workload model to the evaluator (it can handle nil)
-	return a.evaluator.GetImprovementPlan(ctx, awsWorkloadID, workloadModel)
+	return a.evaluator.GetImprovementPlan(ctx, awsWorkloadID, workloadModel, a.bedrockClient)
 }
 
 // CreateMilestone creates a milestone in AWS
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/waffle/waffle/internal/config"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/wafr"
+)
+
+// Output formats of the why-resource command
+const (
+	whyResourceFormatText = "text"
+	whyResourceFormatJSON = "json"
+)
+
+var whyResourceCmd = &cobra.Command{
+	Use:   "why-resource <address> [dir]",
+	Short: "Explain which pillars and questions consider a Terraform resource",
+	Long: `Run IaC analysis and explain why a resource is or is not considered when
+evaluating each pillar and question: the pillars whose resource types match
+its type, the questions with resource types of their own that match it and
+the resource type that matched each. Types added by the resource type mapping
+(wafr.resource_type_mapping_path) are included. The resources it depends on
+and those that depend on it are listed too, since they are evaluated with it.
+
+Use it to debug coverage gaps reported by waffle coverage. Only IaC analysis
+runs: no AWS credentials are needed and nothing is sent to Bedrock or the
+Well-Architected Tool. The directory defaults to --dir or the current
+directory.`,
+	Example: `  # Why is this bucket evaluated under the security pillar?
+  waffle why-resource aws_s3_bucket.logs
+
+  # JSON explanation of a resource in a module of another directory
+  waffle why-resource module.queue.aws_sqs_queue.jobs infra/ --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runWhyResource,
+}
+
+// errResourceNotFound is returned when no analyzed resource has the address
+var errResourceNotFound = errors.New("resource not found")
+
+// resourceExplanation is why questions consider a resource
+type resourceExplanation struct {
+	Address    string `json:"address"`
+	Type       string `json:"type"`
+	SourceFile string `json:"source_file,omitempty"`
+	SourceLine int    `json:"source_line,omitempty"`
+	// Matches are the pillars and questions whose resource types match the
+	// resource's type
+	Matches []wafr.ResourceTypeMatch `json:"matches"`
+	// Dependencies are the resources the resource references directly
+	Dependencies []string `json:"dependencies"`
+	// Dependents are the resources that depend on it, directly or
+	// transitively
+	Dependents []string `json:"dependents"`
+}
+
+// explainResource runs the IaC analysis steps of a review and explains the
+// relevance of the resource at address. mapping may be nil.
+func explainResource(ctx context.Context, analyzer core.IaCAnalyzer, address string, mapping *core.ResourceTypeMapping) (*resourceExplanation, error) {
+	resources, err := analyzeResources(ctx, analyzer)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := analyzer.IdentifyRelationships(ctx, resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify resource relationships: %w", err)
+	}
+
+	for _, resource := range resources {
+		if resource.Address != address {
+			continue
+		}
+		explanation := &resourceExplanation{
+			Address:      resource.Address,
+			Type:         resource.Type,
+			SourceFile:   resource.SourceFile,
+			SourceLine:   resource.SourceLine,
+			Matches:      wafr.ResourceTypeRelevance(resource.Type, mapping),
+			Dependencies: []string{},
+			Dependents:   graph.Dependents([]string{address}),
+		}
+		if explanation.Matches == nil {
+			explanation.Matches = []wafr.ResourceTypeMatch{}
+		}
+		if graph != nil {
+			explanation.Dependencies = append(explanation.Dependencies, graph.Edges[address]...)
+		}
+		if explanation.Dependents == nil {
+			explanation.Dependents = []string{}
+		}
+		return explanation, nil
+	}
+	return nil, fmt.Errorf("%w: no resource with address %s", errResourceNotFound, address)
+}
+
+// writeResourceExplanation writes explanation in format, which must be text
+// or json
+func writeResourceExplanation(w io.Writer, explanation *resourceExplanation, format string) error {
+	switch format {
+	case whyResourceFormatText:
+		return writeResourceExplanationText(w, explanation)
+	case whyResourceFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explanation)
+	default:
+		return fmt.Errorf("unsupported why-resource format %q: use %s or %s", format, whyResourceFormatText, whyResourceFormatJSON)
+	}
+}
+
+func writeResourceExplanationText(w io.Writer, explanation *resourceExplanation) error {
+	fmt.Fprintf(w, "%s (%s)", explanation.Address, explanation.Type)
+	if explanation.SourceFile != "" {
+		fmt.Fprintf(w, " at %s:%d", explanation.SourceFile, explanation.SourceLine)
+	}
+	fmt.Fprintln(w)
+
+	var pillars, questions []wafr.ResourceTypeMatch
+	for _, match := range explanation.Matches {
+		if match.QuestionID != "" {
+			questions = append(questions, match)
+		} else {
+			pillars = append(pillars, match)
+		}
+	}
+
+	fmt.Fprintln(w, "\nPillars:")
+	if len(pillars) == 0 {
+		fmt.Fprintf(w, "  none: no pillar's resource types match %s\n", explanation.Type)
+	}
+	for _, match := range pillars {
+		fmt.Fprintf(w, "  %-24s %s\n", match.Pillar, describeMatch(match))
+	}
+
+	fmt.Fprintln(w, "\nQuestions with their own resource types:")
+	if len(questions) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, match := range questions {
+		fmt.Fprintf(w, "  %-24s %s\n", match.QuestionID, describeMatch(match))
+	}
+
+	writeAddressList(w, "Dependencies", explanation.Dependencies)
+	writeAddressList(w, "Dependents", explanation.Dependents)
+	return nil
+}
+
+// describeMatch describes the type that matched and where it comes from
+func describeMatch(match wafr.ResourceTypeMatch) string {
+	source := "built-in"
+	if match.FromMapping {
+		source = "resource type mapping"
+	}
+	return fmt.Sprintf("matched %s (%s)", match.MatchedType, source)
+}
+
+// writeAddressList writes a titled list of resource addresses
+func writeAddressList(w io.Writer, title string, addresses []string) {
+	fmt.Fprintf(w, "\n%s:\n", title)
+	if len(addresses) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, address := range addresses {
+		fmt.Fprintf(w, "  %s\n", address)
+	}
+}
+
+func runWhyResource(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	logger := logging.GetLogger()
+	address := args[0]
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != whyResourceFormatText && format != whyResourceFormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q: must be %s or %s\n", format, whyResourceFormatText, whyResourceFormatJSON)
+		os.Exit(ExitInvalidArguments)
+	}
+
+	cfg, err := loadConfigWithOverrides(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	var mapping *core.ResourceTypeMapping
+	if cfg.WAFR.ResourceTypeMappingPath != "" {
+		mapping, err = config.LoadResourceTypeMapping(cfg.WAFR.ResourceTypeMappingPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitGeneralError)
+		}
+	}
+
+	var dir string
+	if len(args) == 2 {
+		dir, err = resolveDir(args[1])
+	} else {
+		dir, err = workingDir(cmd)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitDirectoryAccess)
+	}
+
+	analyzer, err := initializeIaCAnalyzer(ctx, nil, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize IaC analyzer: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+
+	explanation, err := explainResource(ctx, analyzer, address, mapping)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if errors.Is(err, errResourceNotFound) {
+			os.Exit(ExitResourceNotFound)
+		}
+		handleReviewError(err)
+	}
+
+	logger.Info("resource explained", "directory", dir, "address", address, "matches", len(explanation.Matches))
+	if err := writeResourceExplanation(os.Stdout, explanation, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write explanation: %v\n", err)
+		os.Exit(ExitGeneralError)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/iac"
+	"github.com/waffle/waffle/internal/wafr"
+)
+
+func TestExplainResource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+resource "aws_kms_key" "logs" {
+  description = "logs"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket     = "acme-logs"
+  depends_on = [aws_kms_key.logs]
+}
+
+resource "aws_sqs_queue" "jobs" {
+  name       = "jobs"
+  depends_on = [aws_s3_bucket.logs]
+}
+`), 0644))
+	analyzer := iac.NewAnalyzerWithDir(dir)
+
+	explanation, err := explainResource(context.Background(), analyzer, "aws_s3_bucket.logs", nil)
+	require.NoError(t, err)
+
+	var pillars []core.Pillar
+	for _, match := range explanation.Matches {
+		if match.QuestionID == "" {
+			pillars = append(pillars, match.Pillar)
+		}
+	}
+	assert.Equal(t, []core.Pillar{core.PillarSecurity, core.PillarCostOptimization}, pillars)
+	assert.Contains(t, explanation.Matches, wafr.ResourceTypeMatch{QuestionID: "data-rest", MatchedType: "aws_s3_bucket"})
+	assert.Equal(t, []string{"aws_kms_key.logs"}, explanation.Dependencies)
+	assert.Equal(t, []string{"aws_sqs_queue.jobs"}, explanation.Dependents)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeResourceExplanation(&buf, explanation, whyResourceFormatText))
+	assert.Contains(t, buf.String(), "aws_s3_bucket.logs (aws_s3_bucket)")
+	assert.Contains(t, buf.String(), "security")
+	assert.Contains(t, buf.String(), "matched aws_s3_bucket (built-in)")
+
+	buf.Reset()
+	require.NoError(t, writeResourceExplanation(&buf, explanation, whyResourceFormatJSON))
+	var decoded resourceExplanation
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, explanation.Matches, decoded.Matches)
+
+	assert.Error(t, writeResourceExplanation(&buf, explanation, "xml"))
+
+	// A type no pillar maps gains pillars from the resource type mapping
+	mapping := &core.ResourceTypeMapping{Pillars: map[core.Pillar][]string{core.PillarReliability: {"aws_sqs"}}}
+	explanation, err = explainResource(context.Background(), analyzer, "aws_sqs_queue.jobs", mapping)
+	require.NoError(t, err)
+	assert.Contains(t, explanation.Matches, wafr.ResourceTypeMatch{Pillar: core.PillarReliability, MatchedType: "aws_sqs", FromMapping: true})
+	assert.Empty(t, explanation.Dependents)
+
+	_, err = explainResource(context.Background(), analyzer, "aws_s3_bucket.missing", nil)
+	assert.ErrorIs(t, err, errResourceNotFound)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/waffle/waffle/internal/core"
 	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/metrics"
 )
 
 // Config holds configuration for the Bedrock client
@@ -28,6 +29,15 @@ type Config struct {
 	MaxRetries     int
 	TimeoutSeconds int
 	RateLimit      float64 // requests per second
+	// MaxParseRetries is how many times an evaluation is re-prompted when
+	// the model response cannot be parsed
+	MaxParseRetries int
+	// ResourceTokenBudget is the estimated tokens the resources of a prompt
+	// may take before bulky property values are trimmed. 0 disables
+	// trimming.
+	ResourceTokenBudget int
+	// Metrics records invocations, retries and token usage. Nil disables them.
+	Metrics *metrics.Metrics
 }
 
 // DefaultConfig returns default Bedrock configuration
@@ -35,14 +45,18 @@ type Config struct {
 // Uses EU cross-region inference profile for model access
 func DefaultConfig() *Config {
 	return &Config{
-		ModelID:        "eu.anthropic.claude-sonnet-4-20250514-v1:0",
-		Region:         "eu-west-1",
-		MaxTokens:      4096,
-		Temperature:    0.7,
-		TopP:           0.9,
-		MaxRetries:     3,
-		TimeoutSeconds: 60,
-		RateLimit:      2.0, // 2 requests per second
+		ModelID:         "eu.anthropic.claude-sonnet-4-20250514-v1:0",
+		Region:          "eu-west-1",
+		MaxTokens:       4096,
+		Temperature:     0.7,
+		TopP:            0.9,
+		MaxRetries:      3,
+		TimeoutSeconds:  60,
+		RateLimit:       2.0, // 2 requests per second
+		MaxParseRetries: 1,
+		// Leaves room for the rest of the prompt and the response in a
+		// 200k token context window
+		ResourceTokenBudget: 150000,
 	}
 }
 
@@ -51,13 +65,21 @@ type BedrockRuntimeAPI interface {
 	InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
 }
 
+// ModelInvoker sends a prompt to a model and returns its text response
+type ModelInvoker interface {
+	InvokeModel(ctx context.Context, prompt string) (string, error)
+}
+
 // Client implements the BedrockClient interface
 type Client struct {
-	client       BedrockRuntimeAPI
+	client BedrockRuntimeAPI
+	// invoker answers prompts in place of Bedrock when set
+	invoker      ModelInvoker
 	config       *Config
 	limiter      *rate.Limiter
 	tokenTracker *TokenUsageTracker
 	auditLogger  *AuditLogger
+	metrics      *metrics.Metrics
 }
 
 // TokenUsageTracker tracks token usage for cost monitoring
@@ -79,7 +101,17 @@ func NewClient(awsConfig aws.Config, config *Config) *Client {
 		config = DefaultConfig()
 	}
 
-	client := bedrockruntime.NewFromConfig(awsConfig)
+	// Bedrock may run in a different region than the rest of the review
+	client := bedrockruntime.NewFromConfig(awsConfig, func(o *bedrockruntime.Options) {
+		if config.Region != "" {
+			o.Region = config.Region
+		}
+	})
+
+	m := config.Metrics
+	if m == nil {
+		m = metrics.Disabled()
+	}
 
 	return &Client{
 		client:       client,
@@ -87,14 +119,40 @@ func NewClient(awsConfig aws.Config, config *Config) *Client {
 		limiter:      rate.NewLimiter(rate.Limit(config.RateLimit), int(config.RateLimit)),
 		tokenTracker: &TokenUsageTracker{},
 		auditLogger:  &AuditLogger{logger: logging.GetLogger()},
+		metrics:      m,
+	}
+}
+
+// NewClientWithInvoker creates a client that builds prompts and parses
+// responses as for Bedrock but sends the prompts to invoker, which handles
+// its own retries. Only MaxParseRetries and ResourceTokenBudget are used
+// from config.
+func NewClientWithInvoker(invoker ModelInvoker, config *Config) *Client {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Client{
+		invoker:      invoker,
+		config:       config,
+		tokenTracker: &TokenUsageTracker{},
+		auditLogger:  &AuditLogger{logger: logging.GetLogger()},
+		metrics:      metrics.Disabled(),
 	}
 }
 
+// invoke sends a prompt to the invoker, or to Bedrock without one
+func (c *Client) invoke(ctx context.Context, prompt string) (string, error) {
+	if c.invoker != nil {
+		return c.invoker.InvokeModel(ctx, prompt)
+	}
+	return c.InvokeModel(ctx, prompt)
+}
+
 // ClaudeRequest represents a request to Claude models
 type ClaudeRequest struct {
 	AnthropicVersion string          `json:"anthropic_version"`
 	MaxTokens        int             `json:"max_tokens"`
-	Temperature      float64         `json:"temperature,omitempty"`
+	Temperature      float64         `json:"temperature"`
 	TopP             float64         `json:"top_p,omitempty"`
 	Messages         []ClaudeMessage `json:"messages"`
 }
@@ -156,6 +214,7 @@ func (c *Client) InvokeModel(ctx context.Context, prompt string) (string, error)
 			switch apiErr.ErrorCode() {
 			case "ThrottlingException":
 				c.auditLogger.LogThrottling(ctx, attempt+1, backoff)
+				c.metrics.Throttles.Inc(metrics.ClientBedrock)
 			case "ServiceUnavailableException":
 				c.auditLogger.LogServiceUnavailable(ctx, attempt+1)
 			case "ModelTimeoutException":
@@ -181,6 +240,7 @@ func (c *Client) InvokeModel(ctx context.Context, prompt string) (string, error)
 
 		// Retry with backoff
 		if attempt < c.config.MaxRetries-1 {
+			c.metrics.Retries.Inc(metrics.ClientBedrock, "InvokeModel")
 			select {
 			case <-time.After(backoff):
 				backoff *= 2
@@ -232,8 +292,10 @@ func (c *Client) invokeModelOnce(ctx context.Context, prompt string) (string, er
 	})
 
 	if err != nil {
+		c.metrics.BedrockCalls.Inc("error")
 		return "", fmt.Errorf("failed to invoke model: %w", err)
 	}
+	c.metrics.BedrockCalls.Inc("success")
 
 	// Parse response
 	var response ClaudeResponse
@@ -260,6 +322,8 @@ func (c *Client) invokeModelOnce(ctx context.Context, prompt string) (string, er
 
 	// Track token usage
 	c.tokenTracker.RecordInvocation(response.Usage.InputTokens, response.Usage.OutputTokens)
+	c.metrics.TokensUsed.Add(float64(response.Usage.InputTokens), "input")
+	c.metrics.TokensUsed.Add(float64(response.Usage.OutputTokens), "output")
 
 	// Log success
 	c.auditLogger.LogSuccess(ctx, response.Usage.InputTokens, response.Usage.OutputTokens)
@@ -274,7 +338,7 @@ func (c *Client) AnalyzeIaCSemantics(
 ) (*core.SemanticAnalysis, error) {
 	prompt := c.buildSemanticAnalysisPrompt(resources)
 
-	response, err := c.InvokeModel(ctx, prompt)
+	response, err := c.invoke(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze IaC semantics: %w", err)
 	}
@@ -297,29 +361,43 @@ func (c *Client) EvaluateWAFRQuestion(
 	question *core.WAFRQuestion,
 	workloadModel *core.WorkloadModel,
 ) (*core.QuestionEvaluation, error) {
-	prompt := c.buildWAFREvaluationPrompt(question, workloadModel)
+	basePrompt := c.buildWAFREvaluationPrompt(question, workloadModel)
+	prompt := basePrompt
+
+	var parseErr error
+	for attempt := 0; attempt <= c.config.MaxParseRetries; attempt++ {
+		if attempt > 0 {
+			// Re-prompt with a stricter instruction after a malformed response
+			c.auditLogger.LogParseRetry(ctx, attempt, parseErr)
+			prompt = buildStrictJSONRetryPrompt(basePrompt, parseErr)
+		}
 
-	response, err := c.InvokeModel(ctx, prompt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to evaluate WAFR question: %w", err)
-	}
+		response, err := c.invoke(ctx, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate WAFR question: %w", err)
+		}
 
-	evaluation, err := c.parseWAFREvaluationResponse(response, question)
-	if err != nil {
-		// Return low-confidence result
-		slog.WarnContext(ctx, "failed to parse WAFR evaluation, returning low confidence",
-			"error", err,
-		)
-		return &core.QuestionEvaluation{
-			Question:        question,
-			SelectedChoices: []core.Choice{},
-			Evidence:        []core.Evidence{},
-			ConfidenceScore: 0.0,
-			Notes:           fmt.Sprintf("Failed to parse response: %v", err),
-		}, nil
+		evaluation, err := c.parseWAFREvaluationResponse(response, question)
+		if err == nil {
+			evaluation.ParseRetries = attempt
+			return evaluation, nil
+		}
+		parseErr = err
 	}
 
-	return evaluation, nil
+	// Return low-confidence result
+	slog.WarnContext(ctx, "failed to parse WAFR evaluation, returning low confidence",
+		"error", parseErr,
+		"parse_retries", c.config.MaxParseRetries,
+	)
+	return &core.QuestionEvaluation{
+		Question:        question,
+		SelectedChoices: []core.Choice{},
+		Evidence:        []core.Evidence{},
+		ConfidenceScore: 0.0,
+		Notes:           fmt.Sprintf("Failed to parse response: %v", parseErr),
+		ParseRetries:    c.config.MaxParseRetries,
+	}, nil
 }
 
 // GenerateImprovementGuidance generates improvement guidance for a risk
@@ -330,7 +408,7 @@ func (c *Client) GenerateImprovementGuidance(
 ) (*core.ImprovementPlanItem, error) {
 	prompt := c.buildImprovementPrompt(risk, resources)
 
-	response, err := c.InvokeModel(ctx, prompt)
+	response, err := c.invoke(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate improvement guidance: %w", err)
 	}
@@ -343,6 +421,35 @@ func (c *Client) GenerateImprovementGuidance(
 	return item, nil
 }
 
+// Region returns the AWS region Bedrock is invoked in. It is empty for
+// runtime clients that do not expose their options, such as test doubles.
+func (c *Client) Region() string {
+	if client, ok := c.client.(interface{ Options() bedrockruntime.Options }); ok {
+		return client.Options().Region
+	}
+	return ""
+}
+
+// UsesFIPSEndpoint reports whether the Bedrock Runtime client resolves FIPS
+// endpoints
+func (c *Client) UsesFIPSEndpoint() bool {
+	if client, ok := c.client.(interface{ Options() bedrockruntime.Options }); ok {
+		return client.Options().EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled
+	}
+	return false
+}
+
+// HTTPTimeout returns the timeout of each HTTP request of the Bedrock Runtime
+// client, zero when requests are unbounded
+func (c *Client) HTTPTimeout() time.Duration {
+	if client, ok := c.client.(interface{ Options() bedrockruntime.Options }); ok {
+		if httpClient, ok := client.Options().HTTPClient.(interface{ GetTimeout() time.Duration }); ok {
+			return httpClient.GetTimeout()
+		}
+	}
+	return 0
+}
+
 // GetTokenUsageStats returns token usage statistics
 func (c *Client) GetTokenUsageStats() TokenUsageStats {
 	return c.tokenTracker.GetStats()
@@ -436,6 +543,15 @@ func (a *AuditLogger) LogServiceUnavailable(ctx context.Context, attempt int) {
 	)
 }
 
+func (a *AuditLogger) LogParseRetry(ctx context.Context, attempt int, parseErr error) {
+	a.logger.WarnContext(ctx, "bedrock_parse_retry",
+		"event_type", "bedrock_parse_retry",
+		"attempt", attempt,
+		"error", parseErr.Error(),
+		"timestamp", time.Now().UTC(),
+	)
+}
+
 func (a *AuditLogger) LogModelTimeout(ctx context.Context, attempt int) {
 	a.logger.WarnContext(ctx, "bedrock_timeout",
 		"event_type", "bedrock_timeout",
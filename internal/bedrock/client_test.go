@@ -3,18 +3,30 @@ package bedrock
 import (
 	"context"
 	"encoding/json"
-	"log/slog"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/metrics"
 )
 
+// TestMain gives clients a console-only audit logger instead of the default
+// one, which writes log files to the working directory
+func TestMain(m *testing.M) {
+	if err := logging.InitGlobalLogger(&logging.Config{Level: logging.LevelInfo}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
 // MockBedrockRuntimeClient is a mock implementation of the Bedrock Runtime client
 type MockBedrockRuntimeClient struct {
 	InvokeModelFunc func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
@@ -83,7 +95,7 @@ func TestTokenUsageTracker_CalculateCost(t *testing.T) {
 }
 
 func TestAuditLogger(t *testing.T) {
-	logger := &AuditLogger{logger: slog.Default()}
+	logger := &AuditLogger{logger: logging.GetLogger()}
 	ctx := context.Background()
 
 	// These should not panic
@@ -160,6 +172,63 @@ func TestBuildWAFREvaluationPrompt(t *testing.T) {
 	assert.Contains(t, prompt, "security")
 	assert.Contains(t, prompt, "selected_choices")
 	assert.Contains(t, prompt, "evidence")
+	assert.NotContains(t, prompt, "Supplementary Context")
+
+	model.Context = []core.ContextDocument{
+		{Path: "docs/runbook.md", Content: "Restore from the nightly snapshot.\n"},
+	}
+	prompt = client.buildWAFREvaluationPrompt(question, model)
+
+	assert.Contains(t, prompt, "Supplementary Context")
+	assert.Contains(t, prompt, "--- docs/runbook.md ---\nRestore from the nightly snapshot.\n")
+}
+
+func TestBuildWAFREvaluationPrompt_DocumentationCoverage(t *testing.T) {
+	client := NewClient(aws.Config{Region: "us-east-1"}, DefaultConfig())
+	model := &core.WorkloadModel{
+		Metadata: map[string]interface{}{
+			"documentation_coverage": core.DocumentationCoverage{
+				Variables:             2,
+				DescribedVariables:    1,
+				Modules:               2,
+				ModulesWithReadme:     1,
+				UndocumentedVariables: []string{"var.ami"},
+				ModulesWithoutReadme:  []string{"modules/compute"},
+			},
+		},
+	}
+
+	prompt := client.buildWAFREvaluationPrompt(&core.WAFRQuestion{ID: "ops-1", Pillar: core.PillarOperationalExcellence}, model)
+
+	assert.Contains(t, prompt, "Documentation Coverage: 50%")
+	assert.Contains(t, prompt, "Variables with a description: 1 of 2")
+	assert.Contains(t, prompt, "Undocumented: var.ami, modules/compute")
+
+	prompt = client.buildWAFREvaluationPrompt(&core.WAFRQuestion{ID: "sec-1", Pillar: core.PillarSecurity}, model)
+
+	assert.NotContains(t, prompt, "Documentation Coverage", "only operational excellence questions get coverage")
+}
+
+func TestBuildWAFREvaluationPrompt_PublicExposure(t *testing.T) {
+	client := NewClient(aws.Config{Region: "us-east-1"}, DefaultConfig())
+	model := &core.WorkloadModel{
+		PublicExposure: []core.ExposedResource{
+			{Address: "aws_lb.web", Type: "aws_lb", Reason: "internet-facing load balancer"},
+			{Address: "aws_instance.web", Type: "aws_instance", Reason: "reachable through aws_lb.web (internet-facing load balancer)", Path: []string{"aws_lb.web", "aws_lb_listener.http"}},
+		},
+	}
+
+	prompt := client.buildWAFREvaluationPrompt(&core.WAFRQuestion{ID: "network-protection", Pillar: core.PillarSecurity}, model)
+
+	assert.Contains(t, prompt, "Public Exposure (resources reachable from the internet):")
+	assert.Contains(t, prompt, "  - aws_lb.web: internet-facing load balancer\n")
+	assert.Contains(t, prompt, "    via aws_lb.web -> aws_lb_listener.http\n")
+
+	prompt = client.buildWAFREvaluationPrompt(&core.WAFRQuestion{ID: "identities", Pillar: core.PillarSecurity}, model)
+	assert.NotContains(t, prompt, "Public Exposure", "only questions about network and data protection get exposure")
+
+	prompt = client.buildWAFREvaluationPrompt(&core.WAFRQuestion{ID: "rel-1", Pillar: core.PillarReliability}, model)
+	assert.NotContains(t, prompt, "Public Exposure")
 }
 
 func TestBuildImprovementPrompt(t *testing.T) {
@@ -196,6 +265,7 @@ func TestBuildImprovementPrompt(t *testing.T) {
 	assert.Contains(t, prompt, "Data encryption")
 	assert.Contains(t, prompt, "HIGH")
 	assert.Contains(t, prompt, "description")
+	assert.Contains(t, prompt, "remediation")
 	assert.Contains(t, prompt, "rationale")
 }
 
@@ -374,6 +444,18 @@ func TestParseImprovementResponse(t *testing.T) {
 				assert.Equal(t, "Enable S3 bucket encryption", item.Description)
 				assert.Equal(t, "LOW", item.EstimatedEffort)
 				assert.Len(t, item.AffectedResources, 1)
+				assert.Empty(t, item.Remediation)
+			},
+		},
+		{
+			name: "response with remediation",
+			response: `{
+				"description": "Enable S3 bucket encryption",
+				"remediation": "Add an aws_s3_bucket_server_side_encryption_configuration for aws_s3_bucket.example using aws_kms_key.example",
+				"estimated_effort": "LOW"
+			}`,
+			checkResult: func(t *testing.T, item *core.ImprovementPlanItem) {
+				assert.Contains(t, item.Remediation, "aws_s3_bucket_server_side_encryption_configuration")
 			},
 		},
 		{
@@ -459,6 +541,19 @@ func TestFormatResources(t *testing.T) {
 			},
 			contains: []string{"aws_s3_bucket.example", "aws_s3_bucket", "test-bucket"},
 		},
+		{
+			name: "resource with static analysis hints",
+			resources: []core.Resource{
+				{
+					Address: "aws_s3_bucket.site",
+					Type:    "aws_s3_bucket",
+					Hints: []core.ResourceHint{
+						{Rule: "public-s3-bucket", Severity: core.RiskLevelHigh, Message: "S3 bucket ACL \"public-read\" makes objects readable by anyone"},
+					},
+				},
+			},
+			contains: []string{"Static analysis findings:", "[HIGH] S3 bucket ACL \"public-read\" makes objects readable by anyone (public-s3-bucket)"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -519,3 +614,158 @@ func TestInvokeModelOnce_Success(t *testing.T) {
 	assert.Equal(t, int64(50), stats.OutputTokens)
 	assert.Equal(t, int64(1), stats.TotalInvocations)
 }
+
+func TestInvokeModel_Metrics(t *testing.T) {
+	t.Run("success records call and tokens", func(t *testing.T) {
+		m := metrics.New(metrics.NewRegistry())
+		config := DefaultConfig()
+		config.Metrics = m
+		client := NewClient(aws.Config{Region: "us-east-1"}, config)
+		client.client = &MockBedrockRuntimeClient{
+			InvokeModelFunc: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+				return mockClaudeOutput(t, "ok"), nil
+			},
+		}
+
+		_, err := client.InvokeModel(context.Background(), "test prompt")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, m.BedrockCalls.Value("success"))
+		assert.Equal(t, 10.0, m.TokensUsed.Value("input"))
+		assert.Equal(t, 5.0, m.TokensUsed.Value("output"))
+	})
+
+	t.Run("throttling records error and throttle", func(t *testing.T) {
+		m := metrics.New(metrics.NewRegistry())
+		config := DefaultConfig()
+		config.MaxRetries = 1
+		config.Metrics = m
+		client := NewClient(aws.Config{Region: "us-east-1"}, config)
+		client.client = &MockBedrockRuntimeClient{
+			InvokeModelFunc: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded"}
+			},
+		}
+
+		_, err := client.InvokeModel(context.Background(), "test prompt")
+
+		require.Error(t, err)
+		assert.Equal(t, 1.0, m.BedrockCalls.Value("error"))
+		assert.Equal(t, 1.0, m.Throttles.Value(metrics.ClientBedrock))
+		assert.Zero(t, m.Retries.Value(metrics.ClientBedrock, "InvokeModel"))
+	})
+}
+
+// mockClaudeOutput wraps text in a Claude response body
+func mockClaudeOutput(t *testing.T, text string) *bedrockruntime.InvokeModelOutput {
+	body, err := json.Marshal(ClaudeResponse{
+		ID:      "test-id",
+		Type:    "message",
+		Role:    "assistant",
+		Content: []ClaudeContentBlock{{Type: "text", Text: text}},
+		Usage:   ClaudeUsage{InputTokens: 10, OutputTokens: 5},
+	})
+	require.NoError(t, err)
+	return &bedrockruntime.InvokeModelOutput{Body: body}
+}
+
+func TestEvaluateWAFRQuestion_ParseRetry(t *testing.T) {
+	question := &core.WAFRQuestion{
+		ID:     "sec_1",
+		Title:  "How do you protect data at rest?",
+		Pillar: core.PillarSecurity,
+		Choices: []core.Choice{
+			{ID: "sec_1_a", Title: "Encrypt data at rest"},
+		},
+	}
+	validResponse := `{"selected_choices": ["sec_1_a"], "evidence": [], "overall_confidence": 0.8, "notes": "ok"}`
+
+	tests := []struct {
+		name            string
+		maxParseRetries int
+		responses       []string
+		wantCalls       int
+		wantRetries     int
+		wantConfidence  float64
+	}{
+		{
+			name:            "first response malformed, retry succeeds",
+			maxParseRetries: 1,
+			responses:       []string{"Sure! Here is my analysis", validResponse},
+			wantCalls:       2,
+			wantRetries:     1,
+			wantConfidence:  0.8,
+		},
+		{
+			name:            "all responses malformed",
+			maxParseRetries: 2,
+			responses:       []string{"not json", "still not json", "{broken"},
+			wantCalls:       3,
+			wantRetries:     2,
+			wantConfidence:  0.0,
+		},
+		{
+			name:            "retries disabled",
+			maxParseRetries: 0,
+			responses:       []string{"not json", validResponse},
+			wantCalls:       1,
+			wantRetries:     0,
+			wantConfidence:  0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.MaxParseRetries = tt.maxParseRetries
+			config.RateLimit = 100
+			client := NewClient(aws.Config{Region: "us-east-1"}, config)
+
+			var prompts []string
+			client.client = &MockBedrockRuntimeClient{
+				InvokeModelFunc: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+					var req ClaudeRequest
+					require.NoError(t, json.Unmarshal(params.Body, &req))
+					prompts = append(prompts, req.Messages[0].Content)
+					return mockClaudeOutput(t, tt.responses[len(prompts)-1]), nil
+				},
+			}
+
+			evaluation, err := client.EvaluateWAFRQuestion(context.Background(), question, &core.WorkloadModel{})
+
+			require.NoError(t, err)
+			require.NotNil(t, evaluation)
+			assert.Len(t, prompts, tt.wantCalls)
+			assert.Equal(t, tt.wantRetries, evaluation.ParseRetries)
+			assert.Equal(t, tt.wantConfidence, evaluation.ConfidenceScore)
+
+			// Retries use the stricter JSON-only instruction
+			for _, prompt := range prompts[1:] {
+				assert.Contains(t, prompt, "could not be parsed")
+			}
+		})
+	}
+}
+
+func TestInvokeModel_SendsZeroTemperature(t *testing.T) {
+	config := DefaultConfig()
+	config.Temperature = 0
+	config.TopP = 1
+	config.RateLimit = 100
+	client := NewClient(aws.Config{Region: "us-east-1"}, config)
+
+	var body []byte
+	client.client = &MockBedrockRuntimeClient{
+		InvokeModelFunc: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+			body = params.Body
+			return mockClaudeOutput(t, "ok"), nil
+		},
+	}
+
+	_, err := client.invokeModelOnce(context.Background(), "prompt")
+
+	require.NoError(t, err)
+	// An omitted temperature would fall back to the model's default of 1
+	assert.Contains(t, string(body), `"temperature":0`)
+	assert.Contains(t, string(body), `"top_p":1`)
+}
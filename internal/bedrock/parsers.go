@@ -49,6 +49,7 @@ type EvidenceResponse struct {
 type ImprovementResponse struct {
 	Description       string   `json:"description"`
 	Rationale         string   `json:"rationale"`
+	Remediation       string   `json:"remediation"`
 	BestPracticeRefs  []string `json:"best_practice_refs"`
 	AffectedResources []string `json:"affected_resources"`
 	EstimatedEffort   string   `json:"estimated_effort"`
@@ -157,12 +158,13 @@ func (c *Client) parseImprovementResponse(responseBody string, risk *core.Risk)
 
 	// Convert to core type
 	item := &core.ImprovementPlanItem{
-		ID:                fmt.Sprintf("improvement-%s", risk.ID),
+		ID:                core.ImprovementItemID(risk),
 		Risk:              risk,
 		Description:       response.Description,
 		BestPracticeRefs:  response.BestPracticeRefs,
 		AffectedResources: response.AffectedResources,
 		EstimatedEffort:   response.EstimatedEffort,
+		Remediation:       response.Remediation,
 	}
 
 	return item, nil
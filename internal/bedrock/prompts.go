@@ -3,6 +3,7 @@ package bedrock
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/waffle/waffle/internal/core"
@@ -10,7 +11,7 @@ import (
 
 // buildSemanticAnalysisPrompt builds a prompt for IaC semantic analysis
 func (c *Client) buildSemanticAnalysisPrompt(resources []core.Resource) string {
-	resourcesJSON := formatResources(resources)
+	resourcesJSON := formatResourcesWithinBudget(resources, c.config.ResourceTokenBudget)
 
 	return fmt.Sprintf(`Analyze the following AWS resources for semantic understanding and security implications.
 
@@ -49,7 +50,7 @@ Respond ONLY with valid JSON, no additional text.`, resourcesJSON)
 func (c *Client) buildWAFREvaluationPrompt(question *core.WAFRQuestion, model *core.WorkloadModel) string {
 	bestPractices := formatBestPractices(question.BestPractices)
 	choices := formatChoices(question.Choices)
-	workloadJSON := formatWorkloadModel(model)
+	workloadJSON := formatWorkloadModel(model, c.config.ResourceTokenBudget)
 
 	return fmt.Sprintf(`You are evaluating an AWS workload against the Well-Architected Framework.
 
@@ -65,7 +66,7 @@ Available Choices:
 
 Workload Resources:
 %s
-
+%s
 Based on the infrastructure-as-code analysis, determine which choices apply to this workload.
 
 For each applicable choice:
@@ -95,13 +96,24 @@ Respond ONLY with valid JSON, no additional text.`,
 		bestPractices,
 		choices,
 		workloadJSON,
+		formatDocumentationCoverage(question, model)+formatPublicExposure(question, model)+formatContextDocuments(model),
 	)
 }
 
+// buildStrictJSONRetryPrompt re-issues a prompt after the model returned a
+// response that could not be parsed
+func buildStrictJSONRetryPrompt(prompt string, parseErr error) string {
+	return fmt.Sprintf(`%s
+
+IMPORTANT: Your previous response could not be parsed (%v).
+Respond with a single JSON object matching the structure above.
+Do not wrap it in markdown code fences and do not add any text before or after it.`, prompt, parseErr)
+}
+
 // buildImprovementPrompt builds a prompt for improvement plan generation
 func (c *Client) buildImprovementPrompt(risk *core.Risk, resources []core.Resource) string {
 	bestPractices := formatBestPractices(risk.MissingBestPractices)
-	resourcesJSON := formatResources(resources)
+	resourcesJSON := formatResourcesWithinBudget(resources, c.config.ResourceTokenBudget)
 
 	return fmt.Sprintf(`Generate an improvement plan item for the following WAFR risk.
 
@@ -117,16 +129,19 @@ Missing Best Practices:
 Affected Resources:
 %s
 
-Provide a high-level improvement plan that:
-1. Describes what changes are needed (no code)
+Provide an improvement plan that:
+1. Describes what changes are needed
 2. Explains why these changes improve the architecture
 3. References relevant best practices and AWS documentation
 4. Considers relationships between affected resources
+5. Gives concrete remediation steps for the affected resources by address,
+   naming the Terraform resources and attributes to add or change
 
 Return as JSON with this exact structure:
 {
   "description": "High-level description of recommended changes",
   "rationale": "Explanation of why these changes improve the architecture",
+  "remediation": "Concrete Terraform changes for the affected resources",
   "best_practice_refs": [
     "https://docs.aws.amazon.com/wellarchitected/..."
   ],
@@ -168,6 +183,13 @@ func formatResources(resources []core.Resource) string {
 		if len(resource.Dependencies) > 0 {
 			sb.WriteString(fmt.Sprintf("  Dependencies: %v\n", resource.Dependencies))
 		}
+
+		if len(resource.Hints) > 0 {
+			sb.WriteString("  Static analysis findings:\n")
+			for _, hint := range resource.Hints {
+				sb.WriteString(fmt.Sprintf("    - [%s] %s (%s)\n", severityToString(hint.Severity), hint.Message, hint.Rule))
+			}
+		}
 	}
 
 	return sb.String()
@@ -207,13 +229,87 @@ func formatChoices(choices []core.Choice) string {
 	return sb.String()
 }
 
-// formatWorkloadModel formats workload model for prompt inclusion
-func formatWorkloadModel(model *core.WorkloadModel) string {
+// formatWorkloadModel formats workload model for prompt inclusion, trimming
+// resource properties to fit budget tokens
+func formatWorkloadModel(model *core.WorkloadModel, budget int) string {
 	if model == nil {
 		return "No workload model provided"
 	}
 
-	return formatResources(model.Resources)
+	formatted := formatResourcesWithinBudget(model.Resources, budget)
+	if len(model.Advisories) == 0 {
+		return formatted
+	}
+
+	var sb strings.Builder
+	sb.WriteString(formatted)
+	sb.WriteString("\n\nWorkload findings:\n")
+	for _, advisory := range model.Advisories {
+		fmt.Fprintf(&sb, "  - [%s] %s: %s (%s)\n", advisory.Pillar, advisory.Source, advisory.Message, advisory.Rule)
+	}
+	return sb.String()
+}
+
+// formatDocumentationCoverage formats the documentation coverage of the
+// workload for operational excellence questions, or returns an empty string
+// for other questions or when coverage was not measured
+func formatDocumentationCoverage(question *core.WAFRQuestion, model *core.WorkloadModel) string {
+	if question.Pillar != core.PillarOperationalExcellence || model == nil {
+		return ""
+	}
+	coverage, ok := model.Metadata["documentation_coverage"].(core.DocumentationCoverage)
+	if !ok {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\nDocumentation Coverage: %.0f%%\n", coverage.Ratio()*100)
+	fmt.Fprintf(&sb, "  Variables with a description: %d of %d\n", coverage.DescribedVariables, coverage.Variables)
+	fmt.Fprintf(&sb, "  Outputs with a description: %d of %d\n", coverage.DescribedOutputs, coverage.Outputs)
+	fmt.Fprintf(&sb, "  Module directories with a README: %d of %d\n", coverage.ModulesWithReadme, coverage.Modules)
+	undocumented := slices.Concat(coverage.UndocumentedVariables, coverage.UndocumentedOutputs, coverage.ModulesWithoutReadme)
+	if len(undocumented) > 0 {
+		fmt.Fprintf(&sb, "  Undocumented: %s\n", strings.Join(undocumented, ", "))
+	}
+	return sb.String()
+}
+
+// publicExposureQuestions are the security questions whose answer depends on
+// what the internet can reach
+var publicExposureQuestions = []string{"network-protection", "protect-compute", "data-rest", "data-transit"}
+
+// formatPublicExposure lists the resources reachable from the public internet
+// for the security questions that depend on them, or returns an empty string
+// for other questions or when nothing is exposed
+func formatPublicExposure(question *core.WAFRQuestion, model *core.WorkloadModel) string {
+	if question.Pillar != core.PillarSecurity || !slices.Contains(publicExposureQuestions, question.ID) || model == nil || len(model.PublicExposure) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nPublic Exposure (resources reachable from the internet):\n")
+	for _, exposed := range model.PublicExposure {
+		fmt.Fprintf(&sb, "  - %s: %s\n", exposed.Address, exposed.Reason)
+		if len(exposed.Path) > 0 {
+			fmt.Fprintf(&sb, "    via %s\n", strings.Join(exposed.Path, " -> "))
+		}
+	}
+	return sb.String()
+}
+
+// formatContextDocuments formats the documents attached to a review as a
+// prompt section, or returns an empty string when there are none
+func formatContextDocuments(model *core.WorkloadModel) string {
+	if model == nil || len(model.Context) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nSupplementary Context (provided by the workload owner, not derived from IaC):\n")
+	for _, doc := range model.Context {
+		fmt.Fprintf(&sb, "\n--- %s ---\n%s\n", doc.Path, strings.TrimRight(doc.Content, "\n"))
+	}
+	return sb.String()
 }
 
 // severityToString converts risk severity to string
@@ -225,6 +321,8 @@ func severityToString(severity core.RiskLevel) string {
 		return "MEDIUM"
 	case core.RiskLevelNone:
 		return "NONE"
+	case core.RiskLevelUnassessed:
+		return "UNASSESSED"
 	default:
 		return "UNKNOWN"
 	}
@@ -0,0 +1,227 @@
+package bedrock
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// Property values longer than these are trimmed, the first limit before the
+// second, when the resources of a prompt exceed the token budget
+const (
+	bulkyValueLength = 512
+	shortValueLength = 64
+)
+
+// securityRelevantKeys are substrings of the property keys that are never
+// trimmed, as questions are answered from them: encryption, public access
+// and transport settings
+var securityRelevantKeys = []string{
+	"encrypt", "kms", "sse", "public", "acl", "ssl", "tls", "https",
+	"deletion_protection", "versioning", "logging", "mfa", "iam_auth",
+}
+
+// base64Blob matches values such as user data or certificates encoded with
+// base64, which carry little the model can reason about
+var base64Blob = regexp.MustCompile(`^[A-Za-z0-9+/\r\n]{64,}={0,2}$`)
+
+// estimateTokens estimates the tokens of text at four characters per token,
+// the usual approximation for English text and JSON
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// formatResourcesWithinBudget formats resources like formatResources. When
+// they exceed budget tokens, bulky property values are truncated, and policy
+// documents summarized, until they fit or nothing more can be trimmed; the
+// trimmed properties are then listed so the model knows values are missing.
+// Security-relevant properties are kept whole. A budget of 0 disables
+// trimming.
+func formatResourcesWithinBudget(resources []core.Resource, budget int) string {
+	formatted := formatResources(resources)
+	if budget <= 0 || estimateTokens(formatted) <= budget {
+		return formatted
+	}
+
+	var trimmedPaths []string
+	for _, limit := range []int{bulkyValueLength, shortValueLength} {
+		trimmer := &propertyTrimmer{limit: limit}
+		trimmed := make([]core.Resource, len(resources))
+		for i, resource := range resources {
+			trimmed[i] = resource
+			if len(resource.Properties) > 0 {
+				trimmed[i].Properties = trimmer.trimMap(resource.Address, resource.Properties)
+			}
+		}
+		formatted = formatResources(trimmed)
+		trimmedPaths = trimmer.paths
+		if estimateTokens(formatted) <= budget {
+			break
+		}
+	}
+
+	if len(trimmedPaths) == 0 {
+		return formatted
+	}
+	sort.Strings(trimmedPaths)
+	return fmt.Sprintf("%s\n\nNote: to fit the context window, these property values were truncated or summarized and are incomplete: %s",
+		formatted, strings.Join(trimmedPaths, ", "))
+}
+
+// propertyTrimmer trims the property values longer than limit and records
+// the path of each trimmed value
+type propertyTrimmer struct {
+	limit int
+	paths []string
+}
+
+// trimMap returns a copy of properties with bulky values trimmed
+func (t *propertyTrimmer) trimMap(path string, properties map[string]interface{}) map[string]interface{} {
+	trimmed := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		trimmed[key] = t.trimValue(path+"."+key, key, value)
+	}
+	return trimmed
+}
+
+// trimValue trims value, found under key at path
+func (t *propertyTrimmer) trimValue(path, key string, value interface{}) interface{} {
+	if isSecurityRelevant(key) {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return t.trimMap(path, v)
+	case []interface{}:
+		trimmed := make([]interface{}, len(v))
+		for i, element := range v {
+			trimmed[i] = t.trimValue(fmt.Sprintf("%s[%d]", path, i), key, element)
+		}
+		return trimmed
+	case string:
+		return t.trimString(path, key, v)
+	default:
+		return value
+	}
+}
+
+// trimString summarizes a long policy document and truncates other long
+// strings. Base64 blobs are truncated whatever the limit.
+func (t *propertyTrimmer) trimString(path, key, value string) string {
+	blob := len(value) > shortValueLength && base64Blob.MatchString(value)
+	if len(value) <= t.limit && !blob {
+		return value
+	}
+	t.paths = append(t.paths, path)
+
+	if strings.Contains(strings.ToLower(key), "policy") {
+		if summary, ok := summarizePolicy(value); ok {
+			return summary
+		}
+	}
+	if blob {
+		return fmt.Sprintf("[base64 data, %d chars, truncated]", len(value))
+	}
+	return fmt.Sprintf("%s... [truncated, %d chars]", truncateRunes(value, shortValueLength/2), len(value))
+}
+
+// isSecurityRelevant reports whether a property key holds security settings
+// that are kept whole
+func isSecurityRelevant(key string) bool {
+	key = strings.ToLower(key)
+	for _, relevant := range securityRelevantKeys {
+		if strings.Contains(key, relevant) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyStatement is the part of an IAM policy statement a summary reports
+type policyStatement struct {
+	Effect    string          `json:"Effect"`
+	Action    json.RawMessage `json:"Action"`
+	Principal json.RawMessage `json:"Principal"`
+	Resource  json.RawMessage `json:"Resource"`
+}
+
+// summarizePolicy summarizes an IAM policy document by its statements,
+// actions and wildcards. It returns false when value is not a policy
+// document.
+func summarizePolicy(value string) (string, bool) {
+	var document struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(value), &document); err != nil || len(document.Statement) == 0 {
+		return "", false
+	}
+	var statements []policyStatement
+	if err := json.Unmarshal(document.Statement, &statements); err != nil {
+		var statement policyStatement
+		if err := json.Unmarshal(document.Statement, &statement); err != nil {
+			return "", false
+		}
+		statements = []policyStatement{statement}
+	}
+
+	// Entries are deduplicated with their effect, so an action allowed in
+	// one statement and denied in another is listed both ways
+	var actions []string
+	var wildcards []string
+	seen := make(map[string]bool)
+	add := func(list *[]string, entry string) {
+		if !seen[entry] {
+			seen[entry] = true
+			*list = append(*list, entry)
+		}
+	}
+	for _, statement := range statements {
+		for _, action := range stringOrList(statement.Action) {
+			add(&actions, statement.Effect+" "+action)
+		}
+		if strings.Contains(string(statement.Principal), `"*"`) {
+			add(&wildcards, statement.Effect+" to any principal")
+		}
+		if strings.Contains(string(statement.Resource), `"*"`) {
+			add(&wildcards, statement.Effect+" on any resource")
+		}
+	}
+
+	summary := fmt.Sprintf("[policy summary: %d statements", len(statements))
+	if len(actions) > 10 {
+		actions = append(actions[:10], fmt.Sprintf("%d more", len(actions)-10))
+	}
+	if len(actions) > 0 {
+		summary += "; actions: " + strings.Join(actions, ", ")
+	}
+	if len(wildcards) > 0 {
+		summary += "; " + strings.Join(wildcards, ", ")
+	}
+	return fmt.Sprintf("%s; full document of %d chars truncated]", summary, len(value)), true
+}
+
+// stringOrList decodes a policy element that is a string or a list of
+// strings
+func stringOrList(raw json.RawMessage) []string {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+	var list []string
+	_ = json.Unmarshal(raw, &list)
+	return list
+}
+
+// truncateRunes returns at most n runes of s
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,149 @@
+package bedrock
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// bulkyPolicy is an inline policy document of many statements
+func bulkyPolicy() string {
+	statements := make([]string, 0, 40)
+	for i := 0; i < 40; i++ {
+		statements = append(statements, `{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "arn:aws:s3:::logs/prefix-with-a-long-name/*"}`)
+	}
+	statements = append(statements, `{"Effect": "Allow", "Principal": "*", "Action": "s3:ListBucket", "Resource": "*"}`)
+	return `{"Version": "2012-10-17", "Statement": [` + strings.Join(statements, ", ") + `]}`
+}
+
+func newBulkyResources() []core.Resource {
+	return []core.Resource{
+		{
+			Address: "aws_s3_bucket.logs",
+			Type:    "aws_s3_bucket",
+			Properties: map[string]interface{}{
+				"bucket": "logs",
+				"policy": bulkyPolicy(),
+				"server_side_encryption_configuration": map[string]interface{}{
+					"rule": map[string]interface{}{"sse_algorithm": "aws:kms", "kms_master_key_id": strings.Repeat("k", 600)},
+				},
+				"block_public_acls": true,
+			},
+		},
+		{
+			Address: "aws_instance.web",
+			Type:    "aws_instance",
+			Properties: map[string]interface{}{
+				"instance_type":    "t3.micro",
+				"user_data_base64": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("#!/bin/bash\necho hello\n", 200))),
+				"tags":             map[string]interface{}{"Description": strings.Repeat("a long description ", 40)},
+			},
+		},
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, estimateTokens(""))
+	assert.Equal(t, 1, estimateTokens("abc"))
+	assert.Equal(t, 1, estimateTokens("abcd"))
+	assert.Equal(t, 250, estimateTokens(strings.Repeat("x", 1000)))
+}
+
+func TestFormatResourcesWithinBudget(t *testing.T) {
+	resources := newBulkyResources()
+	untrimmed := formatResources(resources)
+	budget := estimateTokens(untrimmed) / 2
+
+	formatted := formatResourcesWithinBudget(resources, budget)
+
+	assert.LessOrEqual(t, estimateTokens(formatted), budget)
+
+	// Bulky values are trimmed
+	assert.NotContains(t, formatted, "prefix-with-a-long-name")
+	assert.Contains(t, formatted, "[policy summary: 41 statements; actions: Allow s3:GetObject, Allow s3:PutObject, Allow s3:ListBucket; Allow to any principal, Allow on any resource;")
+	assert.Contains(t, formatted, "[base64 data, ")
+	assert.Contains(t, formatted, "a long description a long descri... [truncated, 760 chars]")
+
+	// Security-relevant properties survive whole, however long
+	assert.Contains(t, formatted, strings.Repeat("k", 600))
+	assert.Contains(t, formatted, `"sse_algorithm": "aws:kms"`)
+	assert.Contains(t, formatted, `"block_public_acls": true`)
+	assert.Contains(t, formatted, `"instance_type": "t3.micro"`)
+
+	// The model is told which values are incomplete
+	assert.Contains(t, formatted, "Note: to fit the context window, these property values were truncated or summarized and are incomplete: "+
+		"aws_instance.web.tags.Description, aws_instance.web.user_data_base64, aws_s3_bucket.logs.policy")
+
+	// The resources themselves are left unchanged
+	assert.Equal(t, bulkyPolicy(), resources[0].Properties["policy"])
+}
+
+func TestFormatResourcesWithinBudget_WithinBudget(t *testing.T) {
+	resources := newBulkyResources()
+	untrimmed := formatResources(resources)
+
+	assert.Equal(t, untrimmed, formatResourcesWithinBudget(resources, estimateTokens(untrimmed)))
+	assert.Equal(t, untrimmed, formatResourcesWithinBudget(resources, 0), "a zero budget disables trimming")
+}
+
+func TestFormatResourcesWithinBudget_ShortValuesLast(t *testing.T) {
+	resources := []core.Resource{{
+		Address: "aws_lambda_function.api",
+		Type:    "aws_lambda_function",
+		Properties: map[string]interface{}{
+			"handler":     strings.Repeat("handler.", 25),
+			"runtime":     "python3.12",
+			"kms_key_arn": "arn:aws:kms:eu-west-1:123456789012:key/" + strings.Repeat("0", 200),
+		},
+	}}
+
+	// Values below the bulky limit are only shortened when the first pass
+	// is not enough
+	formatted := formatResourcesWithinBudget(resources, estimateTokens(formatResources(resources))-1)
+
+	assert.Contains(t, formatted, strings.Repeat("handler.", 4)+"... [truncated, 200 chars]")
+	assert.Contains(t, formatted, strings.Repeat("0", 200))
+	assert.Contains(t, formatted, "incomplete: aws_lambda_function.api.handler")
+}
+
+func TestSummarizePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:     "single statement object",
+			document: `{"Statement": {"Effect": "Deny", "Action": "s3:*", "Principal": {"AWS": "*"}}}`,
+			want:     "[policy summary: 1 statements; actions: Deny s3:*; Deny to any principal; full document of 78 chars truncated]",
+			wantOK:   true,
+		},
+		{
+			name:     "same action allowed and denied",
+			document: `{"Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "*"}, {"Effect": "Deny", "Action": "s3:PutObject", "Resource": "*"}]}`,
+			want:     "[policy summary: 2 statements; actions: Allow s3:GetObject, Allow s3:PutObject, Deny s3:PutObject; Allow on any resource, Deny on any resource; full document of 160 chars truncated]",
+			wantOK:   true,
+		},
+		{
+			name:     "not a policy",
+			document: `{"Version": "2012-10-17"}`,
+		},
+		{
+			name:     "not JSON",
+			document: "${data.aws_iam_policy_document.logs.json}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, ok := summarizePolicy(tt.document)
+			require.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, summary)
+		})
+	}
+}
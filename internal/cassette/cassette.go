@@ -0,0 +1,397 @@
+// Package cassette records the HTTP interactions of AWS clients to a file
+// and replays them without network access, for deterministic integration
+// tests and for reproducing issues.
+//
+// Recordings are redacted: credentials in headers are replaced, access keys
+// in bodies are masked, and the values of secret, token and password fields
+// of JSON and XML bodies are replaced or masked, so a cassette can be shared.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/waffle/waffle/internal/redaction"
+)
+
+// redactedValue replaces the values of sensitive headers
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders are request and response headers that carry credentials
+var sensitiveHeaders = []string{
+	"Authorization",
+	"X-Amz-Security-Token",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// HTTPClient sends HTTP requests. The AWS SDK's aws.HTTPClient has the same
+// method set.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Cassette is the file format of recorded interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder sends requests with the client it wraps and records each
+// interaction, writing them to its cassette file on Close
+type Recorder struct {
+	path     string
+	next     HTTPClient
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder writing to path. Interactions are kept in
+// memory until Close, so recording a long session costs one write.
+func NewRecorder(path string, next HTTPClient) *Recorder {
+	return &Recorder{
+		path:     path,
+		next:     next,
+		cassette: Cassette{Interactions: []Interaction{}},
+	}
+}
+
+// Do sends req and records it with its response. Requests that fail without
+// a response are not recorded.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	return r.do(req, r.next)
+}
+
+// do sends req with next and records the interaction
+func (r *Recorder) do(req *http.Request, next HTTPClient) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	resp, err := next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redactHeaders(req.Header),
+			Body:    redactBody(requestBody),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+			Body:       redactBody(responseBody),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+// Close writes the interactions recorded so far to the cassette file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return save(r.path, &r.cassette)
+}
+
+// Player serves recorded responses instead of sending requests
+type Player struct {
+	path         string
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// LoadPlayer reads the cassette at path
+func LoadPlayer(path string) (*Player, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &Player{
+		path:         path,
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// Do returns the recorded response to req. Each recorded interaction is
+// served once. An unused interaction with the same method, URL and body is
+// preferred; otherwise the first unused one with the same method and URL is
+// served, since some requests carry generated idempotency tokens.
+func (p *Player) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	url := req.URL.String()
+	redacted := redactBody(body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	match := -1
+	for i, interaction := range p.interactions {
+		if p.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != url {
+			continue
+		}
+		if interaction.Request.Body == redacted {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("cassette %s has no recorded response for %s %s", p.path, req.Method, url)
+	}
+	p.used[match] = true
+
+	recorded := p.interactions[match].Response
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody reads a request or response body and replaces it with a reader
+// of the same bytes
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// redactHeaders returns a copy of header with the values of sensitive
+// headers replaced
+func redactHeaders(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// redactorWithRule returns a redactor applying one of the default rules. The
+// other default rules insert quotes and would break JSON bodies.
+func redactorWithRule(name string) *redaction.Redactor {
+	var rules []redaction.RedactionRule
+	for _, rule := range redaction.DefaultRedactionRules() {
+		if rule.Name == name {
+			rules = append(rules, rule)
+		}
+	}
+	return redaction.NewRedactorWithRules(rules)
+}
+
+var (
+	// accessKeyRedactor masks access key IDs anywhere in a body. Their AKIA
+	// prefix keeps it from matching anything else.
+	accessKeyRedactor = redactorWithRule("AWS Access Key")
+	// secretKeyRedactor masks secret keys. It matches any 40 characters of
+	// base64, such as SHA-1 digests and ETags, so it is only applied to the
+	// values of credential fields.
+	secretKeyRedactor = redactorWithRule("AWS Secret Key")
+)
+
+// xmlElementPattern matches an XML element with text content only, as in the
+// query protocol responses of STS and IAM. Go regexps have no backreferences,
+// so the closing name is captured and compared separately.
+var xmlElementPattern = regexp.MustCompile(`<([A-Za-z][\w.-]*)>([^<]*)</([A-Za-z][\w.-]*)>`)
+
+// redactBody returns body as a string with secrets masked: access keys
+// anywhere, and the values of sensitive and credential fields of JSON and XML
+// bodies.
+func redactBody(body []byte) string {
+	redacted, _ := accessKeyRedactor.Redact(string(body))
+	redacted = redactXMLBody(redacted)
+	return redactJSONBody(redacted)
+}
+
+// sensitiveKey reports whether the value of a JSON key or XML element is a
+// secret, such as the SecretString of a Secrets Manager GetSecretValue
+// response or the SessionToken of an STS AssumeRole response
+func sensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	return lower == "secretstring" || lower == "secretbinary" || lower == "secretaccesskey" ||
+		strings.HasSuffix(lower, "token") || strings.HasSuffix(lower, "password")
+}
+
+// credentialKey reports whether the value of a JSON key or XML element may
+// hold a secret key, so that secret key masking applies to it
+func credentialKey(key string) bool {
+	lower := strings.ToLower(key)
+	return strings.Contains(lower, "secret") || strings.Contains(lower, "credential")
+}
+
+// redactValue returns the value of a JSON key or XML element with secrets
+// masked
+func redactValue(key, value string) string {
+	switch {
+	case sensitiveKey(key):
+		return redactedValue
+	case credentialKey(key):
+		redacted, _ := secretKeyRedactor.Redact(value)
+		return redacted
+	}
+	return value
+}
+
+// redactXMLBody replaces the values of sensitive and credential elements in
+// an XML body. Bodies that are not XML are returned unchanged.
+func redactXMLBody(body string) string {
+	if !strings.HasPrefix(strings.TrimSpace(body), "<") {
+		return body
+	}
+	return xmlElementPattern.ReplaceAllStringFunc(body, func(element string) string {
+		match := xmlElementPattern.FindStringSubmatch(element)
+		name, value := match[1], match[2]
+		if name != match[3] {
+			return element
+		}
+		return "<" + name + ">" + redactValue(name, value) + "</" + name + ">"
+	})
+}
+
+// redactJSONBody replaces the values of sensitive and credential keys in a
+// JSON body. Bodies that are not JSON objects or arrays, or have nothing to
+// redact, are returned unchanged.
+func redactJSONBody(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return body
+	}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	if !redactJSONValue(value) {
+		return body
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactJSONValue replaces the values of sensitive and credential keys in
+// place and reports whether any was replaced
+func redactJSONValue(value interface{}) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if s, isString := elem.(string); isString {
+				if replaced := redactValue(key, s); replaced != s {
+					v[key] = replaced
+					redacted = true
+				}
+				continue
+			}
+			redacted = redactJSONValue(elem) || redacted
+		}
+	case []interface{}:
+		for _, elem := range v {
+			redacted = redactJSONValue(elem) || redacted
+		}
+	}
+	return redacted
+}
+
+// save writes the cassette to path, replacing the file atomically
+func save(path string, cassette *Cassette) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
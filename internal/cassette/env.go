@@ -0,0 +1,110 @@
+package cassette
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// Environment variables naming the cassette file to record to or replay from
+const (
+	RecordEnvVar = "WAFFLE_RECORD"
+	ReplayEnvVar = "WAFFLE_REPLAY"
+)
+
+var (
+	envOnce   sync.Once
+	envShared *sharedCassette
+	envErr    error
+)
+
+// sharedCassette is the cassette of the process. All AWS clients use it, so
+// their interactions end up in one file in the order they happened.
+type sharedCassette struct {
+	recorder *Recorder
+	player   *Player
+}
+
+// fromEnv returns the cassette selected by WAFFLE_RECORD or WAFFLE_REPLAY,
+// nil when neither is set. It is created once per process.
+func fromEnv() (*sharedCassette, error) {
+	envOnce.Do(func() {
+		envShared, envErr = newSharedCassette(os.Getenv(RecordEnvVar), os.Getenv(ReplayEnvVar))
+	})
+	return envShared, envErr
+}
+
+// newSharedCassette creates the cassette to record to or replay from
+func newSharedCassette(recordPath, replayPath string) (*sharedCassette, error) {
+	switch {
+	case recordPath != "" && replayPath != "":
+		return nil, fmt.Errorf("%s and %s cannot both be set", RecordEnvVar, ReplayEnvVar)
+	case recordPath != "":
+		return &sharedCassette{recorder: NewRecorder(recordPath, nil)}, nil
+	case replayPath != "":
+		player, err := LoadPlayer(replayPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ReplayEnvVar, err)
+		}
+		return &sharedCassette{player: player}, nil
+	}
+	return nil, nil
+}
+
+// Close writes the cassette named by WAFFLE_RECORD. It does nothing when
+// not recording.
+func Close() error {
+	shared, err := fromEnv()
+	if err != nil || shared == nil || shared.recorder == nil {
+		return nil
+	}
+	return shared.recorder.Close()
+}
+
+// recordingClient records the requests of one AWS client to the shared
+// recorder, sending them with that client's own HTTP client
+type recordingClient struct {
+	recorder *Recorder
+	next     HTTPClient
+}
+
+// Do sends and records req
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	return c.recorder.do(req, c.next)
+}
+
+// Apply routes the requests of an AWS SDK config through the cassette named
+// by WAFFLE_RECORD or WAFFLE_REPLAY, and leaves it as it is when neither is
+// set. It is applied after the config is loaded so that the HTTP client it
+// wraps has its custom CA bundle and timeout.
+func Apply(cfg *aws.Config) error {
+	shared, err := fromEnv()
+	if err != nil {
+		return err
+	}
+	shared.apply(cfg)
+	return nil
+}
+
+// apply routes the requests of cfg through the cassette. Replay also uses
+// placeholder credentials, so requests are signed without looking any up.
+func (s *sharedCassette) apply(cfg *aws.Config) {
+	if s == nil {
+		return
+	}
+	if s.player != nil {
+		cfg.HTTPClient = s.player
+		cfg.Credentials = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider("REPLAY", "REPLAY", ""))
+		return
+	}
+	next := cfg.HTTPClient
+	if next == nil {
+		next = awshttp.NewBuildableClient()
+	}
+	cfg.HTTPClient = &recordingClient{recorder: s.recorder, next: next}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// LoadAnswerOverrides reads an answer overrides file mapping question IDs to
+// the choices to force, e.g.
+//
+//	sec_data_1:
+//	  choices: [sec_data_1_encrypt_at_rest]
+//	  reason: Encryption at rest is mandated by policy
+func LoadAnswerOverrides(path string) (core.AnswerOverrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answer overrides: %w", err)
+	}
+
+	var overrides core.AnswerOverrides
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&overrides); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := overrides.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid answer overrides in %s: %w", path, err)
+	}
+	return overrides, nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestLoadAnswerOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    core.AnswerOverrides
+		wantErr string
+	}{
+		{
+			name: "choices and reason",
+			content: `sec_data_1:
+  choices: [sec_data_1_a, sec_data_1_b]
+  reason: Encryption at rest is mandated by policy
+rel_1:
+  choices: [rel_1_a]
+`,
+			want: core.AnswerOverrides{
+				"sec_data_1": {Choices: []string{"sec_data_1_a", "sec_data_1_b"}, Reason: "Encryption at rest is mandated by policy"},
+				"rel_1":      {Choices: []string{"rel_1_a"}},
+			},
+		},
+		{
+			name:    "empty file",
+			content: "",
+		},
+		{
+			name:    "unknown field",
+			content: "sec_data_1:\n  choice: [sec_data_1_a]\n",
+			wantErr: "field choice not found",
+		},
+		{
+			name:    "no choices",
+			content: "sec_data_1:\n  reason: policy\n",
+			wantErr: "answer override for sec_data_1 selects no choices",
+		},
+		{
+			name:    "empty choice",
+			content: "sec_data_1:\n  choices: [\"\"]\n",
+			wantErr: "answer override for sec_data_1 has an empty choice ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "answers.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			overrides, err := LoadAnswerOverrides(path)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, overrides)
+		})
+	}
+}
+
+func TestLoadAnswerOverrides_Missing(t *testing.T) {
+	_, err := LoadAnswerOverrides(filepath.Join(t.TempDir(), "answers.yaml"))
+
+	assert.ErrorContains(t, err, "failed to read answer overrides")
+}
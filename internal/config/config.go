@@ -5,12 +5,19 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/spf13/viper"
+
+	"github.com/waffle/waffle/internal/core"
 )
 
 // Config represents the complete Waffle configuration
 type Config struct {
+	// Model selects the backend that evaluates questions
+	Model    ModelConfig    `mapstructure:"model"`
 	Bedrock  BedrockConfig  `mapstructure:"bedrock"`
 	Storage  StorageConfig  `mapstructure:"storage"`
 	IaC      IaCConfig      `mapstructure:"iac"`
@@ -18,6 +25,45 @@ type Config struct {
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	Security SecurityConfig `mapstructure:"security"`
 	AWS      AWSConfig      `mapstructure:"aws"`
+	Risk     RiskConfig     `mapstructure:"risk"`
+	Metrics  MetricsConfig  `mapstructure:"metrics"`
+	// TerraformCloud is used to fetch workspace state for tfc:// state sources
+	TerraformCloud TerraformCloudConfig `mapstructure:"terraform_cloud"`
+	// Issues configures the trackers export-issues creates issues in
+	Issues IssuesConfig `mapstructure:"issues"`
+}
+
+// Model providers
+const (
+	ModelProviderBedrock          = "bedrock"
+	ModelProviderOpenAICompatible = "openai-compatible"
+)
+
+// ModelConfig selects the model backend. The Bedrock backend is configured
+// in the bedrock section; BaseURL, APIKey and Name configure an
+// OpenAI-compatible chat completions endpoint, which also uses the bedrock
+// section's max_tokens, temperature, timeout and retry settings.
+type ModelConfig struct {
+	Provider string `mapstructure:"provider"`
+	// BaseURL is the API root, e.g. https://llm.internal.example.com/v1
+	BaseURL string `mapstructure:"base_url"`
+	// APIKey is sent as a bearer token. Empty falls back to OPENAI_API_KEY.
+	APIKey string `mapstructure:"api_key"`
+	Name   string `mapstructure:"name"`
+}
+
+// UsesBedrock reports whether the model is served by Bedrock, the default
+// when no provider is configured
+func (c ModelConfig) UsesBedrock() bool {
+	return c.Provider == "" || c.Provider == ModelProviderBedrock
+}
+
+// ResolveAPIKey returns the configured API key or OPENAI_API_KEY
+func (c ModelConfig) ResolveAPIKey() string {
+	if c.APIKey != "" {
+		return c.APIKey
+	}
+	return os.Getenv("OPENAI_API_KEY")
 }
 
 // BedrockConfig contains Bedrock-specific configuration
@@ -28,6 +74,19 @@ type BedrockConfig struct {
 	Timeout     int     `mapstructure:"timeout"`
 	MaxTokens   int     `mapstructure:"max_tokens"`
 	Temperature float64 `mapstructure:"temperature"`
+	// TopP is the nucleus sampling probability mass the model samples from
+	TopP float64 `mapstructure:"top_p"`
+	// MaxParseRetries is how many times a question evaluation is re-prompted
+	// when the model returns a response that cannot be parsed
+	MaxParseRetries int `mapstructure:"max_parse_retries"`
+	// PerQuestionTimeout limits the evaluation of one question, including
+	// retries, in seconds. A question that exceeds it is recorded as timed
+	// out with zero confidence and the review moves on. 0 disables it.
+	PerQuestionTimeout int `mapstructure:"per_question_timeout"`
+	// ResourceTokenBudget is the estimated tokens the resources in a prompt
+	// may take. Larger resources have bulky property values, such as inline
+	// policies and base64 data, trimmed. 0 disables trimming.
+	ResourceTokenBudget int `mapstructure:"resource_token_budget"`
 }
 
 // StorageConfig contains storage-related configuration
@@ -50,6 +109,47 @@ type IaCConfig struct {
 type WAFRConfig struct {
 	DefaultScope string `mapstructure:"default_scope"`
 	DefaultLens  string `mapstructure:"default_lens"`
+	// WorkloadDescriptionTemplate is a Go text/template for the AWS workload
+	// description. Available fields: WorkloadID, SourceDir, GitRef,
+	// WaffleVersion and ResourceCount. Empty uses the built-in template.
+	WorkloadDescriptionTemplate string `mapstructure:"workload_description_template"`
+	// UpdateWorkloadDescription rewrites the description of reused workloads
+	// and refreshes it with the resource count after IaC analysis
+	UpdateWorkloadDescription bool `mapstructure:"update_workload_description"`
+	// ExcludeDataSources keeps Terraform data sources out of the resource
+	// count used for confidence scoring
+	ExcludeDataSources bool `mapstructure:"exclude_data_sources"`
+	// SubmitChoiceNotes attaches evidence to each selected choice in WAFR
+	// rather than only to the question notes
+	SubmitChoiceNotes bool `mapstructure:"submit_choice_notes"`
+	// ContinueOnPillarError skips pillars whose questions cannot be retrieved
+	// instead of aborting a workload-scope review
+	ContinueOnPillarError bool `mapstructure:"continue_on_pillar_error"`
+	// LensVersion pins the Well-Architected lens version reviews must run
+	// against. Empty accepts the version the workload currently uses.
+	LensVersion string `mapstructure:"lens_version"`
+	// CreateMilestone records a milestone at the end of each review
+	CreateMilestone bool `mapstructure:"create_milestone"`
+	// MilestoneNameTemplate is a Go text/template for the milestone name.
+	// Available fields: WorkloadID, SessionID, GitRef, GitSHA, Timestamp and
+	// Time. Empty uses waffle-{{.Timestamp}}.
+	MilestoneNameTemplate string `mapstructure:"milestone_name_template"`
+	// QuestionCacheTTLHours is how long the questions retrieved for a
+	// workload are reused by later reviews, in hours. Cached questions are
+	// dropped when the lens version changes. 0 disables the cache.
+	QuestionCacheTTLHours int `mapstructure:"question_cache_ttl_hours"`
+	// SubmitConcurrency is how many answers are submitted to AWS at once
+	SubmitConcurrency int `mapstructure:"submit_concurrency"`
+	// SubmitRateLimit caps answer submissions per second across all
+	// concurrent submissions. 0 disables the limit.
+	SubmitRateLimit float64 `mapstructure:"submit_rate_limit"`
+	// ResourceTypeMappingPath is a YAML file of resource types to add to
+	// the built-in types relevant to each pillar and question
+	ResourceTypeMappingPath string `mapstructure:"resource_type_mapping_path"`
+	// DisableBuiltInRequirements stops answering the questions with built-in
+	// required resource types as not applicable. The requires map of the
+	// resource type mapping still applies.
+	DisableBuiltInRequirements bool `mapstructure:"disable_builtin_requirements"`
 }
 
 // LoggingConfig contains logging configuration
@@ -68,6 +168,136 @@ type SecurityConfig struct {
 type AWSConfig struct {
 	Profile string `mapstructure:"profile"`
 	Region  string `mapstructure:"region"`
+	// UseFIPS resolves FIPS endpoints for the Bedrock, Well-Architected Tool
+	// and STS clients
+	UseFIPS bool `mapstructure:"use_fips"`
+	// HTTPTimeout bounds each HTTP request of the Bedrock and
+	// Well-Architected Tool clients, in seconds, so that a hanging call
+	// fails and is retried. 0 leaves requests unbounded.
+	HTTPTimeout int `mapstructure:"http_timeout"`
+}
+
+// FIPSEndpointState returns the SDK endpoint setting for UseFIPS. Unset
+// leaves the choice to AWS_USE_FIPS_ENDPOINT and the shared config.
+func (c AWSConfig) FIPSEndpointState() aws.FIPSEndpointState {
+	if c.UseFIPS {
+		return aws.FIPSEndpointStateEnabled
+	}
+	return aws.FIPSEndpointStateUnset
+}
+
+// HTTPClient returns the SDK HTTP client with HTTPTimeout applied
+func (c AWSConfig) HTTPClient() *awshttp.BuildableClient {
+	return awshttp.NewBuildableClient().WithTimeout(time.Duration(c.HTTPTimeout) * time.Second)
+}
+
+// RiskConfig contains the confidence thresholds used to classify risks.
+// An evaluation below RiskConfidenceThreshold (or with no selected choices)
+// is reported as a risk; the summary counts evaluations below
+// HighConfidenceThreshold as high risks and those below
+// MediumConfidenceThreshold as medium risks.
+type RiskConfig struct {
+	RiskConfidenceThreshold   float64 `mapstructure:"risk_confidence_threshold"`
+	HighConfidenceThreshold   float64 `mapstructure:"high_confidence_threshold"`
+	MediumConfidenceThreshold float64 `mapstructure:"medium_confidence_threshold"`
+	// QuestionSeverityOverrides maps question IDs to the severity (high,
+	// medium or none) their risks get in the improvement plan instead of
+	// the Well-Architected Tool's rating
+	QuestionSeverityOverrides map[string]string `mapstructure:"question_severity_overrides"`
+}
+
+// SeverityOverrides returns QuestionSeverityOverrides as risk levels. The
+// names are checked by Validate; one that is not valid is left out.
+func (c RiskConfig) SeverityOverrides() map[string]core.RiskLevel {
+	overrides := make(map[string]core.RiskLevel, len(c.QuestionSeverityOverrides))
+	for questionID, name := range c.QuestionSeverityOverrides {
+		if severity, err := core.ParseRiskSeverity(name); err == nil {
+			overrides[questionID] = severity
+		}
+	}
+	return overrides
+}
+
+// MetricsConfig contains operational metrics configuration
+type MetricsConfig struct {
+	// Enabled turns on Prometheus metrics collection
+	Enabled bool `mapstructure:"enabled"`
+	// ListenAddress is where `waffle serve` exposes /metrics
+	ListenAddress string `mapstructure:"listen_address"`
+	// StateDir holds the metrics each review and resume saves for
+	// `waffle serve` to add up
+	StateDir string `mapstructure:"state_dir"`
+}
+
+// TerraformCloudConfig contains the Terraform Cloud or Enterprise API settings
+type TerraformCloudConfig struct {
+	Hostname string `mapstructure:"hostname"`
+	// Token is the API token. Empty falls back to the TF_TOKEN_<hostname>
+	// variable Terraform itself reads, then to TFE_TOKEN.
+	Token string `mapstructure:"token"`
+}
+
+// ResolveToken returns the configured API token or the token set in the
+// environment for Hostname
+func (c TerraformCloudConfig) ResolveToken() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	hostVar := "TF_TOKEN_" + strings.NewReplacer(".", "_", "-", "__").Replace(c.Hostname)
+	if token := os.Getenv(hostVar); token != "" {
+		return token
+	}
+	return os.Getenv("TFE_TOKEN")
+}
+
+// IssuesConfig contains the issue trackers improvement items are exported to
+type IssuesConfig struct {
+	// MinPriority is the lowest priority of the items exported. High risks
+	// start at 100 and medium risks at 50.
+	MinPriority int                `mapstructure:"min_priority"`
+	GitHub      GitHubIssuesConfig `mapstructure:"github"`
+	Jira        JiraIssuesConfig   `mapstructure:"jira"`
+}
+
+// GitHubIssuesConfig contains the GitHub repository issues are created in
+type GitHubIssuesConfig struct {
+	// Repository is owner/name
+	Repository string `mapstructure:"repository"`
+	// APIURL is the REST API root, to be changed for GitHub Enterprise
+	APIURL string   `mapstructure:"api_url"`
+	Labels []string `mapstructure:"labels"`
+	// Token needs permission to create issues. Empty falls back to
+	// GITHUB_TOKEN.
+	Token string `mapstructure:"token"`
+}
+
+// ResolveToken returns the configured token or GITHUB_TOKEN
+func (c GitHubIssuesConfig) ResolveToken() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// JiraIssuesConfig contains the Jira project issues are created in
+type JiraIssuesConfig struct {
+	// BaseURL is the site, e.g. https://acme.atlassian.net
+	BaseURL   string   `mapstructure:"base_url"`
+	Project   string   `mapstructure:"project"`
+	IssueType string   `mapstructure:"issue_type"`
+	Labels    []string `mapstructure:"labels"`
+	// Email and Token authenticate with an API token. An empty token falls
+	// back to JIRA_API_TOKEN.
+	Email string `mapstructure:"email"`
+	Token string `mapstructure:"token"`
+}
+
+// ResolveToken returns the configured token or JIRA_API_TOKEN
+func (c JiraIssuesConfig) ResolveToken() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	return os.Getenv("JIRA_API_TOKEN")
 }
 
 // DefaultConfig returns a Config with default values
@@ -76,13 +306,20 @@ func DefaultConfig() *Config {
 	waffleDir := filepath.Join(homeDir, ".waffle")
 
 	return &Config{
+		Model: ModelConfig{
+			Provider: ModelProviderBedrock,
+		},
 		Bedrock: BedrockConfig{
-			Region:      "eu-west-1",
-			ModelID:     "eu.anthropic.claude-sonnet-4-20250514-v1:0",
-			MaxRetries:  3,
-			Timeout:     60,
-			MaxTokens:   4096,
-			Temperature: 0.7,
+			Region:              "eu-west-1",
+			ModelID:             "eu.anthropic.claude-sonnet-4-20250514-v1:0",
+			MaxRetries:          3,
+			Timeout:             60,
+			MaxTokens:           4096,
+			Temperature:         0.7,
+			TopP:                0.9,
+			MaxParseRetries:     1,
+			PerQuestionTimeout:  300,
+			ResourceTokenBudget: 150000,
 		},
 		Storage: StorageConfig{
 			SessionDir:    filepath.Join(waffleDir, "sessions"),
@@ -97,8 +334,12 @@ func DefaultConfig() *Config {
 			PlanFilePath:     "", // Empty by default - only use when explicitly specified
 		},
 		WAFR: WAFRConfig{
-			DefaultScope: "workload",
-			DefaultLens:  "wellarchitected",
+			DefaultScope:          "workload",
+			DefaultLens:           "wellarchitected",
+			CreateMilestone:       true,
+			QuestionCacheTTLHours: 24,
+			SubmitConcurrency:     4,
+			SubmitRateLimit:       5.0,
 		},
 		Logging: LoggingConfig{
 			Level:  "ERROR",
@@ -112,40 +353,75 @@ func DefaultConfig() *Config {
 			Profile: "",
 			Region:  "",
 		},
+		Risk: RiskConfig{
+			RiskConfidenceThreshold:   0.5,
+			HighConfidenceThreshold:   0.3,
+			MediumConfidenceThreshold: 0.7,
+		},
+		Metrics: MetricsConfig{
+			Enabled:       false,
+			ListenAddress: ":9090",
+			StateDir:      filepath.Join(waffleDir, "metrics"),
+		},
+		TerraformCloud: TerraformCloudConfig{
+			Hostname: "app.terraform.io",
+		},
+		Issues: IssuesConfig{
+			MinPriority: 100,
+			GitHub: GitHubIssuesConfig{
+				APIURL: "https://api.github.com",
+			},
+			Jira: JiraIssuesConfig{
+				IssueType: "Task",
+			},
+		},
 	}
 }
 
-// Load loads configuration from file and environment variables
+// configFileName is the name of both the global and the repository config
+// file
+const configFileName = "config.yaml"
+
+// Load loads configuration as LoadFromDir does for the working directory
 func Load() (*Config, error) {
+	return LoadFromDir(".")
+}
+
+// LoadFromDir loads configuration from file and environment variables. The
+// global config file in the user's home directory applies everywhere; a
+// config.yaml in dir, the analyzed directory, is merged over it, so its
+// settings win. Environment variables override both, and command-line flags
+// are applied by the caller.
+func LoadFromDir(dir string) (*Config, error) {
 	// Start with defaults
 	cfg := DefaultConfig()
 
 	// Set up viper
 	v := viper.New()
-	v.SetConfigName("config")
 	v.SetConfigType("yaml")
 
-	// Add config paths
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	waffleDir := filepath.Join(homeDir, ".waffle")
-	v.AddConfigPath(waffleDir)
-	v.AddConfigPath(".")
-
 	// Set environment variable prefix
 	v.SetEnvPrefix("WAFFLE")
 	v.AutomaticEnv()
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
-	// Read config file if it exists
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+	// Read the config files that exist, global first
+	for _, path := range []string{globalConfigPath(homeDir), filepath.Join(dir, configFileName)} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		v.SetConfigFile(path)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 		}
-		// Config file not found is OK, we'll use defaults
 	}
 
 	// Unmarshal into config struct
@@ -158,11 +434,8 @@ func Load() (*Config, error) {
 		cfg.AWS.Profile = awsProfile
 	}
 	if awsRegion := os.Getenv("AWS_REGION"); awsRegion != "" {
+		// Only the workload region; bedrock.region is set on its own
 		cfg.AWS.Region = awsRegion
-		// Also set Bedrock region if AWS_REGION is set and Bedrock region is still default
-		if cfg.Bedrock.Region == "eu-west-1" || cfg.Bedrock.Region == "us-east-1" {
-			cfg.Bedrock.Region = awsRegion
-		}
 	}
 	if logLevel := os.Getenv("WAFFLE_LOG_LEVEL"); logLevel != "" {
 		cfg.Logging.Level = logLevel
@@ -171,35 +444,66 @@ func Load() (*Config, error) {
 	// Expand home directory in paths
 	cfg.Storage.SessionDir = expandPath(cfg.Storage.SessionDir)
 	cfg.Storage.LogDir = expandPath(cfg.Storage.LogDir)
+	cfg.Metrics.StateDir = expandPath(cfg.Metrics.StateDir)
 
 	return cfg, nil
 }
 
-// Save saves the configuration to the config file
+// globalConfigPath returns the global config file: waffle/config.yaml under
+// $XDG_CONFIG_HOME (~/.config when unset), or else ~/.waffle/config.yaml, the
+// file Save writes. It returns "" when neither exists.
+func globalConfigPath(homeDir string) string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	for _, path := range []string{
+		filepath.Join(configHome, "waffle", configFileName),
+		filepath.Join(homeDir, ".waffle", configFileName),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Save saves the configuration to the global config file Load reads, or to
+// ~/.waffle/config.yaml when there is none yet
 func Save(cfg *Config) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	waffleDir := filepath.Join(homeDir, ".waffle")
-	if err := os.MkdirAll(waffleDir, 0755); err != nil {
+	configPath := globalConfigPath(homeDir)
+	if configPath == "" {
+		configPath = filepath.Join(homeDir, ".waffle", configFileName)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create waffle directory: %w", err)
 	}
 
-	configPath := filepath.Join(waffleDir, "config.yaml")
-
 	v := viper.New()
 	v.SetConfigFile(configPath)
 	v.SetConfigType("yaml")
 
 	// Set all config values with proper structure
+	v.Set("model.provider", cfg.Model.Provider)
+	v.Set("model.base_url", cfg.Model.BaseURL)
+	v.Set("model.api_key", cfg.Model.APIKey)
+	v.Set("model.name", cfg.Model.Name)
 	v.Set("bedrock.region", cfg.Bedrock.Region)
 	v.Set("bedrock.model_id", cfg.Bedrock.ModelID)
 	v.Set("bedrock.max_retries", cfg.Bedrock.MaxRetries)
 	v.Set("bedrock.timeout", cfg.Bedrock.Timeout)
 	v.Set("bedrock.max_tokens", cfg.Bedrock.MaxTokens)
 	v.Set("bedrock.temperature", cfg.Bedrock.Temperature)
+	v.Set("bedrock.top_p", cfg.Bedrock.TopP)
+	v.Set("bedrock.max_parse_retries", cfg.Bedrock.MaxParseRetries)
+	v.Set("bedrock.per_question_timeout", cfg.Bedrock.PerQuestionTimeout)
+	v.Set("bedrock.resource_token_budget", cfg.Bedrock.ResourceTokenBudget)
 
 	v.Set("storage.session_dir", cfg.Storage.SessionDir)
 	v.Set("storage.log_dir", cfg.Storage.LogDir)
@@ -213,6 +517,19 @@ func Save(cfg *Config) error {
 
 	v.Set("wafr.default_scope", cfg.WAFR.DefaultScope)
 	v.Set("wafr.default_lens", cfg.WAFR.DefaultLens)
+	v.Set("wafr.workload_description_template", cfg.WAFR.WorkloadDescriptionTemplate)
+	v.Set("wafr.update_workload_description", cfg.WAFR.UpdateWorkloadDescription)
+	v.Set("wafr.exclude_data_sources", cfg.WAFR.ExcludeDataSources)
+	v.Set("wafr.submit_choice_notes", cfg.WAFR.SubmitChoiceNotes)
+	v.Set("wafr.continue_on_pillar_error", cfg.WAFR.ContinueOnPillarError)
+	v.Set("wafr.lens_version", cfg.WAFR.LensVersion)
+	v.Set("wafr.create_milestone", cfg.WAFR.CreateMilestone)
+	v.Set("wafr.milestone_name_template", cfg.WAFR.MilestoneNameTemplate)
+	v.Set("wafr.question_cache_ttl_hours", cfg.WAFR.QuestionCacheTTLHours)
+	v.Set("wafr.submit_concurrency", cfg.WAFR.SubmitConcurrency)
+	v.Set("wafr.submit_rate_limit", cfg.WAFR.SubmitRateLimit)
+	v.Set("wafr.resource_type_mapping_path", cfg.WAFR.ResourceTypeMappingPath)
+	v.Set("wafr.disable_builtin_requirements", cfg.WAFR.DisableBuiltInRequirements)
 
 	v.Set("logging.level", cfg.Logging.Level)
 	v.Set("logging.format", cfg.Logging.Format)
@@ -222,6 +539,32 @@ func Save(cfg *Config) error {
 
 	v.Set("aws.profile", cfg.AWS.Profile)
 	v.Set("aws.region", cfg.AWS.Region)
+	v.Set("aws.use_fips", cfg.AWS.UseFIPS)
+	v.Set("aws.http_timeout", cfg.AWS.HTTPTimeout)
+
+	v.Set("risk.risk_confidence_threshold", cfg.Risk.RiskConfidenceThreshold)
+	v.Set("risk.high_confidence_threshold", cfg.Risk.HighConfidenceThreshold)
+	v.Set("risk.medium_confidence_threshold", cfg.Risk.MediumConfidenceThreshold)
+	if len(cfg.Risk.QuestionSeverityOverrides) > 0 {
+		v.Set("risk.question_severity_overrides", cfg.Risk.QuestionSeverityOverrides)
+	}
+	v.Set("metrics.enabled", cfg.Metrics.Enabled)
+	v.Set("metrics.listen_address", cfg.Metrics.ListenAddress)
+	v.Set("metrics.state_dir", cfg.Metrics.StateDir)
+	v.Set("terraform_cloud.hostname", cfg.TerraformCloud.Hostname)
+	v.Set("terraform_cloud.token", cfg.TerraformCloud.Token)
+
+	v.Set("issues.min_priority", cfg.Issues.MinPriority)
+	v.Set("issues.github.repository", cfg.Issues.GitHub.Repository)
+	v.Set("issues.github.api_url", cfg.Issues.GitHub.APIURL)
+	v.Set("issues.github.labels", cfg.Issues.GitHub.Labels)
+	v.Set("issues.github.token", cfg.Issues.GitHub.Token)
+	v.Set("issues.jira.base_url", cfg.Issues.Jira.BaseURL)
+	v.Set("issues.jira.project", cfg.Issues.Jira.Project)
+	v.Set("issues.jira.issue_type", cfg.Issues.Jira.IssueType)
+	v.Set("issues.jira.labels", cfg.Issues.Jira.Labels)
+	v.Set("issues.jira.email", cfg.Issues.Jira.Email)
+	v.Set("issues.jira.token", cfg.Issues.Jira.Token)
 
 	if err := v.WriteConfigAs(configPath); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
@@ -251,6 +594,32 @@ func (c *Config) Validate() error {
 	if c.Bedrock.Temperature < 0 || c.Bedrock.Temperature > 1 {
 		return fmt.Errorf("bedrock.temperature must be between 0 and 1")
 	}
+	if c.Bedrock.TopP < 0 || c.Bedrock.TopP > 1 {
+		return fmt.Errorf("bedrock.top_p must be between 0 and 1")
+	}
+	if c.Bedrock.MaxParseRetries < 0 {
+		return fmt.Errorf("bedrock.max_parse_retries must be non-negative")
+	}
+	if c.Bedrock.PerQuestionTimeout < 0 {
+		return fmt.Errorf("bedrock.per_question_timeout must be non-negative")
+	}
+	if c.Bedrock.ResourceTokenBudget < 0 {
+		return fmt.Errorf("bedrock.resource_token_budget must be non-negative")
+	}
+
+	// Validate Model config
+	switch c.Model.Provider {
+	case ModelProviderBedrock:
+	case ModelProviderOpenAICompatible:
+		if c.Model.BaseURL == "" {
+			return fmt.Errorf("model.base_url is required for the %s provider", ModelProviderOpenAICompatible)
+		}
+		if c.Model.Name == "" {
+			return fmt.Errorf("model.name is required for the %s provider", ModelProviderOpenAICompatible)
+		}
+	default:
+		return fmt.Errorf("model.provider must be %s or %s", ModelProviderBedrock, ModelProviderOpenAICompatible)
+	}
 
 	// Validate Storage config
 	if c.Storage.SessionDir == "" {
@@ -291,6 +660,15 @@ func (c *Config) Validate() error {
 	if c.WAFR.DefaultLens == "" {
 		return fmt.Errorf("wafr.default_lens is required")
 	}
+	if c.WAFR.QuestionCacheTTLHours < 0 {
+		return fmt.Errorf("wafr.question_cache_ttl_hours must be non-negative")
+	}
+	if c.WAFR.SubmitConcurrency < 1 {
+		return fmt.Errorf("wafr.submit_concurrency must be at least 1")
+	}
+	if c.WAFR.SubmitRateLimit < 0 {
+		return fmt.Errorf("wafr.submit_rate_limit must be non-negative")
+	}
 
 	// Validate Logging config
 	validLevels := map[string]bool{
@@ -307,6 +685,34 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("logging.format must be one of: json, text")
 	}
 
+	if c.AWS.HTTPTimeout < 0 {
+		return fmt.Errorf("aws.http_timeout must not be negative")
+	}
+
+	// Validate Risk config
+	if c.Risk.RiskConfidenceThreshold < 0 || c.Risk.RiskConfidenceThreshold > 1 {
+		return fmt.Errorf("risk.risk_confidence_threshold must be between 0 and 1")
+	}
+	if c.Risk.HighConfidenceThreshold < 0 || c.Risk.HighConfidenceThreshold > 1 {
+		return fmt.Errorf("risk.high_confidence_threshold must be between 0 and 1")
+	}
+	if c.Risk.MediumConfidenceThreshold < 0 || c.Risk.MediumConfidenceThreshold > 1 {
+		return fmt.Errorf("risk.medium_confidence_threshold must be between 0 and 1")
+	}
+	if c.Risk.HighConfidenceThreshold > c.Risk.MediumConfidenceThreshold {
+		return fmt.Errorf("risk.high_confidence_threshold must not exceed risk.medium_confidence_threshold")
+	}
+	for questionID, severity := range c.Risk.QuestionSeverityOverrides {
+		if _, err := core.ParseRiskSeverity(severity); err != nil {
+			return fmt.Errorf("risk.question_severity_overrides.%s must be one of: high, medium, none", questionID)
+		}
+	}
+
+	// Validate Metrics config
+	if c.Metrics.Enabled && c.Metrics.ListenAddress == "" {
+		return fmt.Errorf("metrics.listen_address is required when metrics are enabled")
+	}
+
 	return nil
 }
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -95,6 +97,29 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "bedrock.temperature must be between 0 and 1",
 		},
+		{
+			name: "invalid top_p",
+			modify: func(c *Config) {
+				c.Bedrock.TopP = -0.5
+			},
+			wantErr: true,
+			errMsg:  "bedrock.top_p must be between 0 and 1",
+		},
+		{
+			name: "negative per_question_timeout",
+			modify: func(c *Config) {
+				c.Bedrock.PerQuestionTimeout = -1
+			},
+			wantErr: true,
+			errMsg:  "bedrock.per_question_timeout must be non-negative",
+		},
+		{
+			name: "disabled per_question_timeout",
+			modify: func(c *Config) {
+				c.Bedrock.PerQuestionTimeout = 0
+			},
+			wantErr: false,
+		},
 		{
 			name: "missing session_dir",
 			modify: func(c *Config) {
@@ -119,6 +144,30 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "iac.framework is required",
 		},
+		{
+			name: "negative question_cache_ttl_hours",
+			modify: func(c *Config) {
+				c.WAFR.QuestionCacheTTLHours = -1
+			},
+			wantErr: true,
+			errMsg:  "wafr.question_cache_ttl_hours must be non-negative",
+		},
+		{
+			name: "zero submit_concurrency",
+			modify: func(c *Config) {
+				c.WAFR.SubmitConcurrency = 0
+			},
+			wantErr: true,
+			errMsg:  "wafr.submit_concurrency must be at least 1",
+		},
+		{
+			name: "negative submit_rate_limit",
+			modify: func(c *Config) {
+				c.WAFR.SubmitRateLimit = -1
+			},
+			wantErr: true,
+			errMsg:  "wafr.submit_rate_limit must be non-negative",
+		},
 		{
 			name: "invalid log level",
 			modify: func(c *Config) {
@@ -135,6 +184,69 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "logging.format must be one of",
 		},
+		{
+			name: "risk threshold out of range",
+			modify: func(c *Config) {
+				c.Risk.RiskConfidenceThreshold = 1.2
+			},
+			wantErr: true,
+			errMsg:  "risk.risk_confidence_threshold must be between 0 and 1",
+		},
+		{
+			name: "high threshold above medium threshold",
+			modify: func(c *Config) {
+				c.Risk.HighConfidenceThreshold = 0.8
+				c.Risk.MediumConfidenceThreshold = 0.6
+			},
+			wantErr: true,
+			errMsg:  "risk.high_confidence_threshold must not exceed",
+		},
+		{
+			name: "negative HTTP timeout",
+			modify: func(c *Config) {
+				c.AWS.HTTPTimeout = -1
+			},
+			wantErr: true,
+			errMsg:  "aws.http_timeout must not be negative",
+		},
+		{
+			name: "question severity overrides",
+			modify: func(c *Config) {
+				c.Risk.QuestionSeverityOverrides = map[string]string{"data-classification": "high", "cost-tags": "None"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid question severity override",
+			modify: func(c *Config) {
+				c.Risk.QuestionSeverityOverrides = map[string]string{"data-classification": "critical"}
+			},
+			wantErr: true,
+			errMsg:  "risk.question_severity_overrides.data-classification must be one of: high, medium, none",
+		},
+		{
+			name: "openai-compatible provider",
+			modify: func(c *Config) {
+				c.Model = ModelConfig{Provider: ModelProviderOpenAICompatible, BaseURL: "https://llm.example.com/v1", Name: "llama"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "openai-compatible provider without base_url",
+			modify: func(c *Config) {
+				c.Model = ModelConfig{Provider: ModelProviderOpenAICompatible, Name: "llama"}
+			},
+			wantErr: true,
+			errMsg:  "model.base_url is required",
+		},
+		{
+			name: "unknown model provider",
+			modify: func(c *Config) {
+				c.Model.Provider = "vertex"
+			},
+			wantErr: true,
+			errMsg:  "model.provider must be bedrock or openai-compatible",
+		},
 	}
 
 	for _, tt := range tests {
@@ -273,8 +385,23 @@ func TestLoadConfigWithEnvVars(t *testing.T) {
 	assert.Equal(t, "env-profile", cfg.AWS.Profile)
 	assert.Equal(t, "ap-southeast-1", cfg.AWS.Region)
 	assert.Equal(t, "ERROR", cfg.Logging.Level)
-	// Bedrock region should also be overridden by AWS_REGION
-	assert.Equal(t, "ap-southeast-1", cfg.Bedrock.Region)
+	// Bedrock region is independent of AWS_REGION
+	assert.Equal(t, "eu-west-1", cfg.Bedrock.Region)
+}
+
+func TestLoadFromDir_AWSRegionKeepsBedrockRegion(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(homeDir, "xdg"))
+	t.Setenv("AWS_REGION", "eu-west-1")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "config.yaml"), []byte("bedrock:\n  region: us-east-1\n"), 0644))
+
+	cfg, err := LoadFromDir(repoDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "eu-west-1", cfg.AWS.Region)
+	assert.Equal(t, "us-east-1", cfg.Bedrock.Region)
 }
 
 func TestSaveConfig(t *testing.T) {
@@ -341,3 +468,198 @@ func TestExpandPath(t *testing.T) {
 		})
 	}
 }
+
+func TestRiskConfigSeverityOverrides(t *testing.T) {
+	risk := RiskConfig{QuestionSeverityOverrides: map[string]string{
+		"data-classification": "high",
+		"cost-tags":           "None",
+		"sec-logging":         "critical",
+	}}
+
+	assert.Equal(t, map[string]core.RiskLevel{
+		"data-classification": core.RiskLevelHigh,
+		"cost-tags":           core.RiskLevelNone,
+	}, risk.SeverityOverrides())
+}
+
+func TestTerraformCloudConfigResolveToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      TerraformCloudConfig
+		hostVar  string
+		tfeToken string
+		want     string
+	}{
+		{
+			name:     "configured token wins",
+			cfg:      TerraformCloudConfig{Hostname: "app.terraform.io", Token: "config-token"},
+			hostVar:  "host-token",
+			tfeToken: "tfe-token",
+			want:     "config-token",
+		},
+		{
+			name:     "hostname variable",
+			cfg:      TerraformCloudConfig{Hostname: "app.terraform.io"},
+			hostVar:  "host-token",
+			tfeToken: "tfe-token",
+			want:     "host-token",
+		},
+		{
+			name:     "TFE_TOKEN fallback",
+			cfg:      TerraformCloudConfig{Hostname: "app.terraform.io"},
+			tfeToken: "tfe-token",
+			want:     "tfe-token",
+		},
+		{
+			name: "no token",
+			cfg:  TerraformCloudConfig{Hostname: "app.terraform.io"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TF_TOKEN_app_terraform_io", tt.hostVar)
+			t.Setenv("TFE_TOKEN", tt.tfeToken)
+
+			assert.Equal(t, tt.want, tt.cfg.ResolveToken())
+		})
+	}
+}
+
+func TestTerraformCloudConfigResolveToken_HyphenatedHostname(t *testing.T) {
+	t.Setenv("TF_TOKEN_tfe__internal_example_com", "host-token")
+	t.Setenv("TFE_TOKEN", "")
+
+	cfg := TerraformCloudConfig{Hostname: "tfe-internal.example.com"}
+
+	assert.Equal(t, "host-token", cfg.ResolveToken())
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	const homeConfig = "bedrock:\n  region: us-west-2\n  max_retries: 5\nlogging:\n  level: WARN\n"
+	const repoConfig = "bedrock:\n  region: eu-central-1\nlogging:\n  level: DEBUG\n"
+
+	tests := []struct {
+		name         string
+		xdgConfig    string
+		legacyConfig string
+		repoConfig   string
+		logLevelEnv  string
+		wantRegion   string
+		wantRetries  int
+		wantLogLevel string
+	}{
+		{
+			name:         "defaults",
+			wantRegion:   "eu-west-1",
+			wantRetries:  3,
+			wantLogLevel: "ERROR",
+		},
+		{
+			name:         "global config under XDG_CONFIG_HOME",
+			xdgConfig:    homeConfig,
+			wantRegion:   "us-west-2",
+			wantRetries:  5,
+			wantLogLevel: "WARN",
+		},
+		{
+			name:         "legacy home config",
+			legacyConfig: homeConfig,
+			wantRegion:   "us-west-2",
+			wantRetries:  5,
+			wantLogLevel: "WARN",
+		},
+		{
+			name:         "XDG config preferred over legacy home config",
+			xdgConfig:    homeConfig,
+			legacyConfig: "bedrock:\n  max_retries: 9\n",
+			wantRegion:   "us-west-2",
+			wantRetries:  5,
+			wantLogLevel: "WARN",
+		},
+		{
+			name:         "repo config merged over global config",
+			xdgConfig:    homeConfig,
+			repoConfig:   repoConfig,
+			wantRegion:   "eu-central-1",
+			wantRetries:  5,
+			wantLogLevel: "DEBUG",
+		},
+		{
+			name:         "environment over repo config",
+			xdgConfig:    homeConfig,
+			repoConfig:   repoConfig,
+			logLevelEnv:  "INFO",
+			wantRegion:   "eu-central-1",
+			wantRetries:  5,
+			wantLogLevel: "INFO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir := t.TempDir()
+			repoDir := t.TempDir()
+			configHome := filepath.Join(homeDir, "xdg")
+			t.Setenv("HOME", homeDir)
+			t.Setenv("XDG_CONFIG_HOME", configHome)
+			t.Setenv("AWS_REGION", "")
+			t.Setenv("WAFFLE_LOG_LEVEL", tt.logLevelEnv)
+
+			// A config in the process working directory is not the repository's
+			cwd := t.TempDir()
+			t.Chdir(cwd)
+			require.NoError(t, os.WriteFile(filepath.Join(cwd, "config.yaml"), []byte("bedrock:\n  max_retries: 7\n"), 0644))
+
+			writeConfig := func(path, content string) {
+				if content == "" {
+					return
+				}
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+			writeConfig(filepath.Join(configHome, "waffle", "config.yaml"), tt.xdgConfig)
+			writeConfig(filepath.Join(homeDir, ".waffle", "config.yaml"), tt.legacyConfig)
+			writeConfig(filepath.Join(repoDir, "config.yaml"), tt.repoConfig)
+
+			cfg, err := LoadFromDir(repoDir)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantRegion, cfg.Bedrock.Region)
+			assert.Equal(t, tt.wantRetries, cfg.Bedrock.MaxRetries)
+			assert.Equal(t, tt.wantLogLevel, cfg.Logging.Level)
+		})
+	}
+}
+
+func TestSave_WritesLoadedGlobalConfig(t *testing.T) {
+	homeDir := t.TempDir()
+	configHome := filepath.Join(homeDir, "xdg")
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("AWS_REGION", "")
+	xdgPath := filepath.Join(configHome, "waffle", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(xdgPath), 0755))
+	require.NoError(t, os.WriteFile(xdgPath, []byte("bedrock:\n  max_retries: 5\n"), 0644))
+
+	cfg, err := LoadFromDir(t.TempDir())
+	require.NoError(t, err)
+	cfg.Bedrock.MaxRetries = 8
+	require.NoError(t, Save(cfg))
+
+	assert.NoFileExists(t, filepath.Join(homeDir, ".waffle", "config.yaml"))
+	reloaded, err := LoadFromDir(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, 8, reloaded.Bedrock.MaxRetries)
+}
+
+func TestGlobalConfigPath_DefaultsToDotConfig(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	assert.Empty(t, globalConfigPath(homeDir))
+
+	path := filepath.Join(homeDir, ".config", "waffle", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("logging:\n  level: WARN\n"), 0644))
+	assert.Equal(t, path, globalConfigPath(homeDir))
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+)
+
+// simulatePrincipalPolicy returns the decision of the identity policies of
+// principalARN for each of actions on any resource, reading every page of
+// the simulation
+func simulatePrincipalPolicy(ctx context.Context, client iam.SimulatePrincipalPolicyAPIClient, principalARN string, actions []string) (map[string]iamtypes.PolicyEvaluationDecisionType, error) {
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     actions,
+	})
+
+	decisions := make(map[string]iamtypes.PolicyEvaluationDecisionType, len(actions))
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) {
+				return nil, fmt.Errorf("iam SimulatePrincipalPolicy failed [%s]: %s", apiErr.ErrorCode(), apiErr.ErrorMessage())
+			}
+			return nil, fmt.Errorf("iam SimulatePrincipalPolicy failed: %w", err)
+		}
+		for _, result := range page.EvaluationResults {
+			decisions[aws.ToString(result.EvalActionName)] = result.EvalDecision
+		}
+	}
+	return decisions, nil
+}
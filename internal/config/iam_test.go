@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIAMClient returns an IAM client that sends its requests to server
+func newTestIAMClient(server *httptest.Server) *iam.Client {
+	return iam.New(iam.Options{
+		BaseEndpoint: aws.String(server.URL),
+		Region:       "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, nil
+		}),
+		HTTPClient:       server.Client(),
+		RetryMaxAttempts: 1,
+	})
+}
+
+func TestSimulatePrincipalPolicy(t *testing.T) {
+	var pages int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "SimulatePrincipalPolicy", r.PostForm.Get("Action"))
+		assert.Equal(t, "2010-05-08", r.PostForm.Get("Version"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/WaffleCI", r.PostForm.Get("PolicySourceArn"))
+		assert.Equal(t, "wellarchitected:ListAnswers", r.PostForm.Get("ActionNames.member.1"))
+		assert.Equal(t, "bedrock:InvokeModel", r.PostForm.Get("ActionNames.member.2"))
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/")
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/iam/aws4_request")
+
+		pages++
+		if r.PostForm.Get("Marker") == "" {
+			w.Write([]byte(`<SimulatePrincipalPolicyResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <SimulatePrincipalPolicyResult>
+    <IsTruncated>true</IsTruncated>
+    <Marker>page-2</Marker>
+    <EvaluationResults>
+      <member>
+        <EvalActionName>wellarchitected:ListAnswers</EvalActionName>
+        <EvalResourceName>*</EvalResourceName>
+        <EvalDecision>allowed</EvalDecision>
+      </member>
+    </EvaluationResults>
+  </SimulatePrincipalPolicyResult>
+</SimulatePrincipalPolicyResponse>`))
+			return
+		}
+		assert.Equal(t, "page-2", r.PostForm.Get("Marker"))
+		w.Write([]byte(`<SimulatePrincipalPolicyResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <SimulatePrincipalPolicyResult>
+    <IsTruncated>false</IsTruncated>
+    <EvaluationResults>
+      <member>
+        <EvalActionName>bedrock:InvokeModel</EvalActionName>
+        <EvalResourceName>*</EvalResourceName>
+        <EvalDecision>implicitDeny</EvalDecision>
+      </member>
+    </EvaluationResults>
+  </SimulatePrincipalPolicyResult>
+</SimulatePrincipalPolicyResponse>`))
+	}))
+	defer server.Close()
+
+	decisions, err := simulatePrincipalPolicy(context.Background(), newTestIAMClient(server), "arn:aws:iam::123456789012:role/WaffleCI",
+		[]string{"wellarchitected:ListAnswers", "bedrock:InvokeModel"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, map[string]iamtypes.PolicyEvaluationDecisionType{
+		"wellarchitected:ListAnswers": iamtypes.PolicyEvaluationDecisionTypeAllowed,
+		"bedrock:InvokeModel":         iamtypes.PolicyEvaluationDecisionTypeImplicitDeny,
+	}, decisions)
+}
+
+func TestSimulatePrincipalPolicy_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<ErrorResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>AccessDenied</Code>
+    <Message>User is not authorized to perform: iam:SimulatePrincipalPolicy</Message>
+  </Error>
+  <RequestId>4d2f8a4e-example</RequestId>
+</ErrorResponse>`))
+	}))
+	defer server.Close()
+
+	_, err := simulatePrincipalPolicy(context.Background(), newTestIAMClient(server), "arn:aws:iam::123456789012:user/dev", []string{"bedrock:InvokeModel"})
+	require.Error(t, err)
+	assert.Equal(t, "iam SimulatePrincipalPolicy failed [AccessDenied]: User is not authorized to perform: iam:SimulatePrincipalPolicy", err.Error())
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/waffle/waffle/internal/cassette"
+)
+
+// RequiredActions are the IAM actions every review calls. A review stops
+// part way through without them.
+var RequiredActions = []string{
+	"wellarchitected:CreateWorkload",
+	"wellarchitected:GetWorkload",
+	"wellarchitected:ListWorkloads",
+	"wellarchitected:GetLensReview",
+	"wellarchitected:ListAnswers",
+	"wellarchitected:UpdateAnswer",
+	"wellarchitected:CreateMilestone",
+	"bedrock:InvokeModel",
+}
+
+// resourceScopedActions are the required actions a policy may allow on
+// specific workloads or models only. Policies are simulated against any
+// resource, so an implicit deny of one of them does not mean a review fails.
+var resourceScopedActions = map[string]bool{
+	"wellarchitected:GetWorkload":     true,
+	"wellarchitected:GetLensReview":   true,
+	"wellarchitected:ListAnswers":     true,
+	"wellarchitected:UpdateAnswer":    true,
+	"wellarchitected:CreateMilestone": true,
+	"bedrock:InvokeModel":             true,
+}
+
+// RequiredActions returns the IAM actions a review with this configuration
+// calls. Bedrock is not called when another model provider is configured.
+func (c *Config) RequiredActions() []string {
+	if c.Model.UsesBedrock() {
+		return RequiredActions
+	}
+	actions := make([]string, 0, len(RequiredActions))
+	for _, action := range RequiredActions {
+		if !strings.HasPrefix(action, "bedrock:") {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// OptionalActions maps the IAM actions only some features call to the
+// feature that needs them
+var OptionalActions = map[string]string{
+	"wellarchitected:GetConsolidatedReport": "AWS reports (waffle results --format pdf or consolidated-json)",
+	"wellarchitected:UpdateWorkload":        "wafr.update_workload_description",
+	"wellarchitected:DeleteWorkload":        "--cleanup",
+	"wellarchitected:GetLens":               "--lens-version",
+	"wellarchitected:GetAnswer":             "--preserve-answers and --overwrite",
+}
+
+// PolicySimulator checks the IAM policies of the caller
+type PolicySimulator interface {
+	// CallerARN returns the ARN of the principal making requests
+	CallerARN(ctx context.Context) (string, error)
+
+	// SimulatePrincipalPolicy returns the decision of the identity policies
+	// of principalARN for each of actions on any resource
+	SimulatePrincipalPolicy(ctx context.Context, principalARN string, actions []string) (map[string]iamtypes.PolicyEvaluationDecisionType, error)
+}
+
+// PermissionReport lists the IAM actions Waffle needs that the caller is not
+// allowed
+type PermissionReport struct {
+	// Principal is the IAM user or role whose policies were simulated
+	Principal string
+	// Simulated is false when the policies could not be simulated, e.g.
+	// because the caller may not call iam:SimulatePrincipalPolicy. Error
+	// then says why and nothing is known to be missing.
+	Simulated bool
+	Error     error
+	// MissingRequired lists required actions that are not allowed, sorted
+	MissingRequired []string
+	// UnscopedRequired lists resource-scoped required actions that are not
+	// allowed on every resource, sorted. A policy allowing them on the
+	// workloads and models Waffle uses is enough, so they are not missing.
+	UnscopedRequired []string
+	// MissingOptional lists optional actions that are not allowed, sorted
+	MissingOptional []string
+}
+
+// OK reports whether no required action is known to be missing
+func (r *PermissionReport) OK() bool {
+	return len(r.MissingRequired) == 0
+}
+
+// CheckPermissions simulates the caller's policies for the required actions
+// given and the optional actions. A simulation that fails leaves the report unsimulated
+// rather than failing, since the caller may well have the permissions a
+// review needs without being allowed to simulate them.
+func CheckPermissions(ctx context.Context, simulator PolicySimulator, required []string) *PermissionReport {
+	report := &PermissionReport{}
+
+	callerARN, err := simulator.CallerARN(ctx)
+	if err != nil {
+		report.Error = fmt.Errorf("failed to identify the caller: %w", err)
+		return report
+	}
+	report.Principal = simulationPrincipal(callerARN)
+
+	optional := make([]string, 0, len(OptionalActions))
+	for action := range OptionalActions {
+		optional = append(optional, action)
+	}
+	sort.Strings(optional)
+	actions := append(append([]string(nil), required...), optional...)
+	decisions, err := simulator.SimulatePrincipalPolicy(ctx, report.Principal, actions)
+	if err != nil {
+		report.Error = err
+		return report
+	}
+	report.Simulated = true
+
+	for _, action := range actions {
+		decision := decisions[action]
+		if decision == iamtypes.PolicyEvaluationDecisionTypeAllowed {
+			continue
+		}
+		if _, optional := OptionalActions[action]; optional {
+			report.MissingOptional = append(report.MissingOptional, action)
+		} else if resourceScopedActions[action] && decision == iamtypes.PolicyEvaluationDecisionTypeImplicitDeny {
+			report.UnscopedRequired = append(report.UnscopedRequired, action)
+		} else {
+			report.MissingRequired = append(report.MissingRequired, action)
+		}
+	}
+	sort.Strings(report.MissingRequired)
+	sort.Strings(report.UnscopedRequired)
+	sort.Strings(report.MissingOptional)
+	return report
+}
+
+// simulationPrincipal returns the principal whose policies apply to callerARN.
+// Assumed-role sessions are simulated as their role; the role path is not in
+// the session ARN, so roles with a path cannot be simulated.
+func simulationPrincipal(callerARN string) string {
+	parts := strings.SplitN(callerARN, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return callerARN
+	}
+	role := strings.SplitN(strings.TrimPrefix(parts[5], "assumed-role/"), "/", 2)[0]
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], role)
+}
+
+// awsPolicySimulator identifies the caller with STS and simulates its
+// policies with IAM
+type awsPolicySimulator struct {
+	sts *sts.Client
+	iam *iam.Client
+}
+
+// NewPolicySimulator returns a PolicySimulator using the configured AWS
+// credentials
+func NewPolicySimulator(ctx context.Context, cfg *Config) (PolicySimulator, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithUseFIPSEndpoint(cfg.AWS.FIPSEndpointState()),
+	}
+	if cfg.AWS.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.AWS.Region))
+	} else if cfg.Bedrock.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Bedrock.Region))
+	}
+	if cfg.AWS.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(cfg.AWS.Profile))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err == nil {
+		err = cassette.Apply(&awsCfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &awsPolicySimulator{
+		sts: sts.NewFromConfig(awsCfg),
+		iam: iam.NewFromConfig(awsCfg),
+	}, nil
+}
+
+func (s *awsPolicySimulator) CallerARN(ctx context.Context) (string, error) {
+	output, err := s.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.Arn), nil
+}
+
+func (s *awsPolicySimulator) SimulatePrincipalPolicy(ctx context.Context, principalARN string, actions []string) (map[string]iamtypes.PolicyEvaluationDecisionType, error) {
+	return simulatePrincipalPolicy(ctx, s.iam, principalARN, actions)
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPolicySimulator allows every action not listed in denied, which are
+// denied explicitly, or in notAllowed, which no statement allows
+type mockPolicySimulator struct {
+	callerARN   string
+	callerErr   error
+	denied      map[string]bool
+	notAllowed  map[string]bool
+	simulateErr error
+
+	principal string
+	actions   []string
+}
+
+func (m *mockPolicySimulator) CallerARN(ctx context.Context) (string, error) {
+	return m.callerARN, m.callerErr
+}
+
+func (m *mockPolicySimulator) SimulatePrincipalPolicy(ctx context.Context, principalARN string, actions []string) (map[string]iamtypes.PolicyEvaluationDecisionType, error) {
+	m.principal = principalARN
+	m.actions = actions
+	if m.simulateErr != nil {
+		return nil, m.simulateErr
+	}
+	decisions := make(map[string]iamtypes.PolicyEvaluationDecisionType, len(actions))
+	for _, action := range actions {
+		switch {
+		case m.denied[action]:
+			decisions[action] = iamtypes.PolicyEvaluationDecisionTypeExplicitDeny
+		case m.notAllowed[action]:
+			decisions[action] = iamtypes.PolicyEvaluationDecisionTypeImplicitDeny
+		default:
+			decisions[action] = iamtypes.PolicyEvaluationDecisionTypeAllowed
+		}
+	}
+	return decisions, nil
+}
+
+func TestCheckPermissions(t *testing.T) {
+	tests := []struct {
+		name         string
+		denied       map[string]bool
+		notAllowed   map[string]bool
+		wantOK       bool
+		wantRequired []string
+		wantUnscoped []string
+		wantOptional []string
+	}{
+		{
+			name:   "all allowed",
+			wantOK: true,
+		},
+		{
+			name: "required actions denied",
+			denied: map[string]bool{
+				"wellarchitected:UpdateAnswer": true,
+				"bedrock:InvokeModel":          true,
+			},
+			wantRequired: []string{"bedrock:InvokeModel", "wellarchitected:UpdateAnswer"},
+		},
+		{
+			name:         "required action allowed on specific resources only",
+			notAllowed:   map[string]bool{"bedrock:InvokeModel": true, "wellarchitected:UpdateAnswer": true},
+			wantOK:       true,
+			wantUnscoped: []string{"bedrock:InvokeModel", "wellarchitected:UpdateAnswer"},
+		},
+		{
+			name:         "required action without resources not allowed",
+			notAllowed:   map[string]bool{"wellarchitected:CreateWorkload": true},
+			wantRequired: []string{"wellarchitected:CreateWorkload"},
+		},
+		{
+			name:         "optional action denied",
+			denied:       map[string]bool{"wellarchitected:GetConsolidatedReport": true},
+			wantOK:       true,
+			wantOptional: []string{"wellarchitected:GetConsolidatedReport"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulator := &mockPolicySimulator{
+				callerARN:  "arn:aws:sts::123456789012:assumed-role/WaffleCI/session-1",
+				denied:     tt.denied,
+				notAllowed: tt.notAllowed,
+			}
+			report := CheckPermissions(context.Background(), simulator, RequiredActions)
+
+			assert.True(t, report.Simulated)
+			assert.NoError(t, report.Error)
+			assert.Equal(t, tt.wantOK, report.OK())
+			assert.Equal(t, tt.wantRequired, report.MissingRequired)
+			assert.Equal(t, tt.wantUnscoped, report.UnscopedRequired)
+			assert.Equal(t, tt.wantOptional, report.MissingOptional)
+
+			// The role behind the session is simulated for every action
+			assert.Equal(t, "arn:aws:iam::123456789012:role/WaffleCI", report.Principal)
+			assert.Equal(t, report.Principal, simulator.principal)
+			assert.Subset(t, simulator.actions, RequiredActions)
+			assert.Len(t, simulator.actions, len(RequiredActions)+len(OptionalActions))
+		})
+	}
+}
+
+func TestConfigRequiredActions(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Equal(t, RequiredActions, cfg.RequiredActions())
+
+	cfg.Model.Provider = ModelProviderOpenAICompatible
+	actions := cfg.RequiredActions()
+	assert.NotContains(t, actions, "bedrock:InvokeModel")
+	assert.Len(t, actions, len(RequiredActions)-1)
+	assert.Contains(t, RequiredActions, "bedrock:InvokeModel", "the package list is left unchanged")
+}
+
+func TestCheckPermissions_SimulationUnavailable(t *testing.T) {
+	tests := []struct {
+		name      string
+		simulator *mockPolicySimulator
+		wantErr   string
+	}{
+		{
+			name: "simulation not allowed",
+			simulator: &mockPolicySimulator{
+				callerARN:   "arn:aws:iam::123456789012:user/dev",
+				simulateErr: errors.New("iam SimulatePrincipalPolicy failed [AccessDenied]: not authorized"),
+			},
+			wantErr: "AccessDenied",
+		},
+		{
+			name:      "caller unknown",
+			simulator: &mockPolicySimulator{callerErr: errors.New("no credentials")},
+			wantErr:   "failed to identify the caller: no credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckPermissions(context.Background(), tt.simulator, RequiredActions)
+
+			assert.False(t, report.Simulated)
+			require.Error(t, report.Error)
+			assert.Contains(t, report.Error.Error(), tt.wantErr)
+			// Nothing is known to be missing, so a review may go ahead
+			assert.True(t, report.OK())
+			assert.Contains(t, FormatPermissionReport(report), "Skipped: could not simulate IAM policies")
+		})
+	}
+}
+
+func TestSimulationPrincipal(t *testing.T) {
+	tests := []struct {
+		callerARN string
+		want      string
+	}{
+		{
+			callerARN: "arn:aws:sts::123456789012:assumed-role/WaffleCI/session-1",
+			want:      "arn:aws:iam::123456789012:role/WaffleCI",
+		},
+		{
+			callerARN: "arn:aws-us-gov:sts::123456789012:assumed-role/Reviewer/jane",
+			want:      "arn:aws-us-gov:iam::123456789012:role/Reviewer",
+		},
+		{
+			callerARN: "arn:aws:iam::123456789012:user/dev",
+			want:      "arn:aws:iam::123456789012:user/dev",
+		},
+		{
+			callerARN: "not-an-arn",
+			want:      "not-an-arn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.callerARN, func(t *testing.T) {
+			assert.Equal(t, tt.want, simulationPrincipal(tt.callerARN))
+		})
+	}
+}
+
+func TestFormatPermissionReport(t *testing.T) {
+	report := &PermissionReport{
+		Principal:       "arn:aws:iam::123456789012:user/dev",
+		Simulated:       true,
+		MissingRequired:  []string{"wellarchitected:UpdateAnswer"},
+		UnscopedRequired: []string{"bedrock:InvokeModel"},
+		MissingOptional:  []string{"wellarchitected:DeleteWorkload"},
+	}
+
+	assert.Equal(t, "Missing required actions for arn:aws:iam::123456789012:user/dev: wellarchitected:UpdateAnswer\n"+
+		"  Not allowed on every resource: bedrock:InvokeModel, check that it is allowed on the workloads and models Waffle uses\n"+
+		"  Not allowed: wellarchitected:DeleteWorkload, needed for --cleanup", FormatPermissionReport(report))
+}
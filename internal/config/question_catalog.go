@@ -0,0 +1,41 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// LoadQuestionCatalog reads a JSON file of questions to evaluate instead of
+// retrieving them from the Well-Architected Tool, e.g.
+//
+//	{
+//	  "lens_version": "2024-06-27",
+//	  "questions": [
+//	    {
+//	      "ID": "data-rest",
+//	      "Pillar": "security",
+//	      "Title": "How do you protect your data at rest?",
+//	      "Choices": [{"ID": "sec_data_rest_encrypt_at_rest", "Title": "Enforce encryption at rest"}]
+//	    }
+//	  ]
+//	}
+//
+// Question cache entries have this form, so one can be used as a catalog.
+func LoadQuestionCatalog(path string) (*core.QuestionCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read question catalog: %w", err)
+	}
+
+	var catalog core.QuestionCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := catalog.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid question catalog in %s: %w", path, err)
+	}
+	return &catalog, nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestLoadQuestionCatalog(t *testing.T) {
+	dataRest := &core.WAFRQuestion{
+		ID:      "data-rest",
+		Pillar:  core.PillarSecurity,
+		Title:   "How do you protect your data at rest?",
+		Choices: []core.Choice{{ID: "sec_data_rest_encrypt_at_rest", Title: "Enforce encryption at rest"}},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    *core.QuestionCatalog
+		wantErr string
+	}{
+		{
+			name: "catalog",
+			content: `{
+  "lens_version": "2024-06-27",
+  "questions": [
+    {
+      "ID": "data-rest",
+      "Pillar": "security",
+      "Title": "How do you protect your data at rest?",
+      "Choices": [{"ID": "sec_data_rest_encrypt_at_rest", "Title": "Enforce encryption at rest"}]
+    }
+  ]
+}`,
+			want: &core.QuestionCatalog{LensVersion: "2024-06-27", Questions: []*core.WAFRQuestion{dataRest}},
+		},
+		{
+			name: "question cache entry",
+			content: `{
+  "key": "my-app|security",
+  "lens_version": "2024-06-27",
+  "fetched_at": "2026-10-01T09:00:00Z",
+  "questions": [{"ID": "data-rest", "Pillar": "security", "Title": "How do you protect your data at rest?", "Choices": [{"ID": "sec_data_rest_encrypt_at_rest", "Title": "Enforce encryption at rest"}]}]
+}`,
+			want: &core.QuestionCatalog{LensVersion: "2024-06-27", Questions: []*core.WAFRQuestion{dataRest}},
+		},
+		{
+			name:    "no questions",
+			content: `{"questions": []}`,
+			wantErr: "catalog has no questions",
+		},
+		{
+			name:    "unknown pillar",
+			content: `{"questions": [{"ID": "data-rest", "Pillar": "safety", "Choices": [{"ID": "a"}]}]}`,
+			wantErr: `question data-rest: unknown pillar "safety"`,
+		},
+		{
+			name:    "no choices",
+			content: `{"questions": [{"ID": "data-rest", "Pillar": "security"}]}`,
+			wantErr: "question data-rest has no choices",
+		},
+		{
+			name:    "duplicate question",
+			content: `{"questions": [{"ID": "data-rest", "Pillar": "security", "Choices": [{"ID": "a"}]}, {"ID": "data-rest", "Pillar": "security", "Choices": [{"ID": "a"}]}]}`,
+			wantErr: "question data-rest is listed more than once",
+		},
+		{
+			name:    "malformed",
+			content: `{"questions": [`,
+			wantErr: "failed to parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "catalog.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			catalog, err := LoadQuestionCatalog(path)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, catalog)
+		})
+	}
+}
+
+func TestLoadQuestionCatalog_Missing(t *testing.T) {
+	_, err := LoadQuestionCatalog(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.ErrorContains(t, err, "failed to read question catalog")
+}
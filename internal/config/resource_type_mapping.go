@@ -0,0 +1,40 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// LoadResourceTypeMapping reads a file of resource types to add to the
+// built-in types of pillars and questions, e.g.
+//
+//	pillars:
+//	  security: [aws_wafv2_web_acl]
+//	questions:
+//	  data-rest: [aws_opensearch_domain]
+//	requires:
+//	  container-workloads: [aws_ecs, aws_eks]
+func LoadResourceTypeMapping(path string) (*core.ResourceTypeMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource type mapping: %w", err)
+	}
+
+	var mapping core.ResourceTypeMapping
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&mapping); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resource type mapping in %s: %w", path, err)
+	}
+	return &mapping, nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestLoadResourceTypeMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *core.ResourceTypeMapping
+		wantErr string
+	}{
+		{
+			name: "pillars and questions",
+			content: `pillars:
+  security: [aws_wafv2_web_acl]
+  operationalExcellence: [aws_ssm_document]
+questions:
+  data-rest: [aws_opensearch_domain]
+`,
+			want: &core.ResourceTypeMapping{
+				Pillars: map[core.Pillar][]string{
+					core.PillarSecurity:              {"aws_wafv2_web_acl"},
+					core.PillarOperationalExcellence: {"aws_ssm_document"},
+				},
+				Questions: map[string][]string{"data-rest": {"aws_opensearch_domain"}},
+			},
+		},
+		{
+			name: "requires",
+			content: `requires:
+  serverless-functions: [aws_lambda_function]
+  container-workloads: [aws_ecs, aws_eks]
+`,
+			want: &core.ResourceTypeMapping{
+				Requires: map[string][]string{
+					"serverless-functions": {"aws_lambda_function"},
+					"container-workloads":  {"aws_ecs", "aws_eks"},
+				},
+			},
+		},
+		{
+			name:    "requires no types",
+			content: "requires:\n  serverless-functions: []\n",
+			wantErr: "requires: question serverless-functions: no resource types",
+		},
+		{
+			name:    "empty file",
+			content: "",
+			want:    &core.ResourceTypeMapping{},
+		},
+		{
+			name:    "unknown field",
+			content: "pillar:\n  security: [aws_wafv2_web_acl]\n",
+			wantErr: "field pillar not found",
+		},
+		{
+			name:    "unknown pillar",
+			content: "pillars:\n  safety: [aws_wafv2_web_acl]\n",
+			wantErr: `unknown pillar "safety"`,
+		},
+		{
+			name:    "no types",
+			content: "questions:\n  data-rest: []\n",
+			wantErr: "question data-rest: no resource types",
+		},
+		{
+			name:    "malformed type",
+			content: "pillars:\n  security: [\"aws_wafv2 web_acl\"]\n",
+			wantErr: `pillar security: invalid resource type "aws_wafv2 web_acl"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resource-types.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			mapping, err := LoadResourceTypeMapping(path)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mapping)
+		})
+	}
+}
+
+func TestLoadResourceTypeMapping_Missing(t *testing.T) {
+	_, err := LoadResourceTypeMapping(filepath.Join(t.TempDir(), "resource-types.yaml"))
+
+	assert.ErrorContains(t, err, "failed to read resource type mapping")
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/waffle/waffle/internal/cassette"
+)
+
+// SecretReferencePrefix marks a configuration value that is read from
+// AWS Secrets Manager, e.g. secretsmanager://<secret-arn>#<json-key>
+const SecretReferencePrefix = "secretsmanager://"
+
+// SecretsManagerAPI is the subset of the Secrets Manager client used to
+// resolve secret references
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SecretReference identifies a value stored in AWS Secrets Manager
+type SecretReference struct {
+	// SecretID is the secret ARN or name
+	SecretID string
+	// Key selects a field of a JSON secret. Empty uses the whole secret string.
+	Key string
+}
+
+// SecretResolutionError reports a configuration value that could not be
+// read from Secrets Manager
+type SecretResolutionError struct {
+	Field     string
+	Reference string
+	Err       error
+}
+
+func (e *SecretResolutionError) Error() string {
+	return fmt.Sprintf("failed to resolve %s from %s: %v", e.Field, e.Reference, e.Err)
+}
+
+func (e *SecretResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// IsSecretReference reports whether a configuration value references a secret
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, SecretReferencePrefix)
+}
+
+// ParseSecretReference parses a secretsmanager://<secret-id>#<json-key> value
+func ParseSecretReference(value string) (SecretReference, error) {
+	if !IsSecretReference(value) {
+		return SecretReference{}, fmt.Errorf("value does not start with %s", SecretReferencePrefix)
+	}
+
+	secretID, key, _ := strings.Cut(strings.TrimPrefix(value, SecretReferencePrefix), "#")
+	if secretID == "" {
+		return SecretReference{}, fmt.Errorf("secret ID is missing")
+	}
+	return SecretReference{SecretID: secretID, Key: key}, nil
+}
+
+// SecretResolver reads secret references from AWS Secrets Manager.
+// Each secret is fetched once per resolver.
+type SecretResolver struct {
+	client  SecretsManagerAPI
+	secrets map[string]string
+}
+
+// NewSecretResolver creates a resolver using the given Secrets Manager client
+func NewSecretResolver(client SecretsManagerAPI) *SecretResolver {
+	return &SecretResolver{
+		client:  client,
+		secrets: make(map[string]string),
+	}
+}
+
+// NewSecretsManagerClient creates a Secrets Manager client with the AWS
+// region and profile from cfg. Settings that are themselves secret references
+// are skipped in favour of the SDK defaults.
+func NewSecretsManagerClient(ctx context.Context, cfg *Config) (*secretsmanager.Client, error) {
+	usable := func(value string) bool {
+		return value != "" && !IsSecretReference(value)
+	}
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithUseFIPSEndpoint(cfg.AWS.FIPSEndpointState()),
+	}
+	if usable(cfg.AWS.Region) {
+		opts = append(opts, config.WithRegion(cfg.AWS.Region))
+	} else if usable(cfg.Bedrock.Region) {
+		opts = append(opts, config.WithRegion(cfg.Bedrock.Region))
+	}
+	if usable(cfg.AWS.Profile) {
+		opts = append(opts, config.WithSharedConfigProfile(cfg.AWS.Profile))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err == nil {
+		err = cassette.Apply(&awsCfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return secretsmanager.NewFromConfig(awsCfg), nil
+}
+
+// Resolve returns the value referenced by a secretsmanager:// string
+func (r *SecretResolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, err := ParseSecretReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := r.secretString(ctx, ref.SecretID)
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return secret, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select key %q", ref.Key)
+	}
+	field, ok := fields[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret", ref.Key)
+	}
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(field), nil
+}
+
+// secretString fetches the string value of a secret
+func (r *SecretResolver) secretString(ctx context.Context, secretID string) (string, error) {
+	if secret, ok := r.secrets[secretID]; ok {
+		return secret, nil
+	}
+
+	var optFns []func(*secretsmanager.Options)
+	// Secrets referenced by ARN are read from the ARN's region
+	if parsed, err := arn.Parse(secretID); err == nil && parsed.Region != "" {
+		optFns = append(optFns, func(o *secretsmanager.Options) {
+			o.Region = parsed.Region
+		})
+	}
+
+	output, err := r.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	}, optFns...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret value: %w", err)
+	}
+	if output.SecretString == nil {
+		return "", fmt.Errorf("secret has no string value")
+	}
+
+	r.secrets[secretID] = *output.SecretString
+	return *output.SecretString, nil
+}
+
+// HasSecretReferences reports whether any configuration value references a secret
+func HasSecretReferences(cfg *Config) bool {
+	found := false
+	walkStringFields(reflect.ValueOf(cfg).Elem(), "", func(field string, v reflect.Value) error {
+		if IsSecretReference(v.String()) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// ResolveSecrets replaces every secretsmanager:// value in cfg with the
+// referenced secret
+func ResolveSecrets(ctx context.Context, cfg *Config, resolver *SecretResolver) error {
+	return walkStringFields(reflect.ValueOf(cfg).Elem(), "", func(field string, v reflect.Value) error {
+		reference := v.String()
+		if !IsSecretReference(reference) {
+			return nil
+		}
+
+		resolved, err := resolver.Resolve(ctx, reference)
+		if err != nil {
+			return &SecretResolutionError{Field: field, Reference: reference, Err: err}
+		}
+		v.SetString(resolved)
+		return nil
+	})
+}
+
+// walkStringFields calls fn for each string field of a config struct, naming
+// fields by their mapstructure path (e.g. "aws.profile")
+func walkStringFields(v reflect.Value, prefix string, fn func(field string, v reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("mapstructure")
+		if name == "" {
+			name = strings.ToLower(t.Field(i).Name)
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			if err := walkStringFields(field, name, fn); err != nil {
+				return err
+			}
+		case reflect.String:
+			if err := fn(name, field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecretARN = "arn:aws:secretsmanager:us-east-1:123456789012:secret:waffle-AbCdEf"
+
+// mockSecretsManagerClient serves secrets from a map and counts calls
+type mockSecretsManagerClient struct {
+	secrets map[string]string
+	calls   int
+	regions []string
+}
+
+func (m *mockSecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	m.calls++
+	opts := secretsmanager.Options{}
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+	m.regions = append(m.regions, opts.Region)
+
+	secret, ok := m.secrets[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, errors.New("ResourceNotFoundException: secret not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(secret)}, nil
+}
+
+func TestParseSecretReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    SecretReference
+		wantErr bool
+	}{
+		{
+			name:  "arn with key",
+			value: "secretsmanager://" + testSecretARN + "#api_key",
+			want:  SecretReference{SecretID: testSecretARN, Key: "api_key"},
+		},
+		{
+			name:  "name without key",
+			value: "secretsmanager://waffle/ca-bundle",
+			want:  SecretReference{SecretID: "waffle/ca-bundle"},
+		},
+		{
+			name:    "missing secret id",
+			value:   "secretsmanager://#api_key",
+			wantErr: true,
+		},
+		{
+			name:    "not a reference",
+			value:   "plain-value",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSecretReference(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	client := &mockSecretsManagerClient{
+		secrets: map[string]string{
+			testSecretARN:      `{"profile": "security-audit", "model_id": "eu.anthropic.claude-sonnet-4-20250514-v1:0"}`,
+			"waffle/log-level": "DEBUG",
+		},
+	}
+
+	cfg := DefaultConfig()
+	cfg.AWS.Profile = "secretsmanager://" + testSecretARN + "#profile"
+	cfg.Bedrock.ModelID = "secretsmanager://" + testSecretARN + "#model_id"
+	cfg.Logging.Level = "secretsmanager://waffle/log-level"
+	require.True(t, HasSecretReferences(cfg))
+
+	err := ResolveSecrets(context.Background(), cfg, NewSecretResolver(client))
+
+	require.NoError(t, err)
+	assert.Equal(t, "security-audit", cfg.AWS.Profile)
+	assert.Equal(t, "eu.anthropic.claude-sonnet-4-20250514-v1:0", cfg.Bedrock.ModelID)
+	assert.Equal(t, "DEBUG", cfg.Logging.Level)
+	assert.False(t, HasSecretReferences(cfg))
+
+	// Each secret is fetched once, from the region in its ARN
+	assert.Equal(t, 2, client.calls)
+	assert.Contains(t, client.regions, "us-east-1")
+}
+
+func TestResolveSecrets_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		wantErr   string
+	}{
+		{
+			name:      "secret not found",
+			reference: "secretsmanager://missing",
+			wantErr:   "failed to resolve aws.profile from secretsmanager://missing: failed to get secret value",
+		},
+		{
+			name:      "key not found",
+			reference: "secretsmanager://" + testSecretARN + "#missing",
+			wantErr:   `key "missing" not found in secret`,
+		},
+		{
+			name:      "key on plain secret",
+			reference: "secretsmanager://plain#profile",
+			wantErr:   "secret is not a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockSecretsManagerClient{
+				secrets: map[string]string{
+					testSecretARN: `{"profile": "security-audit"}`,
+					"plain":       "not-json",
+				},
+			}
+			cfg := DefaultConfig()
+			cfg.AWS.Profile = tt.reference
+
+			err := ResolveSecrets(context.Background(), cfg, NewSecretResolver(client))
+
+			require.Error(t, err)
+			var resolutionErr *SecretResolutionError
+			require.ErrorAs(t, err, &resolutionErr)
+			assert.Equal(t, "aws.profile", resolutionErr.Field)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestHasSecretReferences(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.False(t, HasSecretReferences(cfg))
+
+	cfg.WAFR.WorkloadDescriptionTemplate = "secretsmanager://waffle/description"
+	assert.True(t, HasSecretReferences(cfg))
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,6 +12,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
 	"github.com/aws/smithy-go"
+
+	"github.com/waffle/waffle/internal/cassette"
 )
 
 // ValidationResult represents the result of a validation check
@@ -34,6 +38,13 @@ func NewValidator(cfg *Config) *Validator {
 func (v *Validator) ValidateAll(ctx context.Context) ([]ValidationResult, error) {
 	results := []ValidationResult{}
 
+	// Regions are checked first since every other check depends on them
+	regionResult := v.validateRegions(ctx)
+	results = append(results, regionResult)
+	if !regionResult.Success {
+		return results, nil
+	}
+
 	// 1. Validate AWS credentials
 	credResult := v.validateCredentials(ctx)
 	results = append(results, credResult)
@@ -42,17 +53,118 @@ func (v *Validator) ValidateAll(ctx context.Context) ([]ValidationResult, error)
 		return results, nil
 	}
 
-	// 2. Validate Bedrock access
-	bedrockResult := v.validateBedrockAccess(ctx)
-	results = append(results, bedrockResult)
+	// 2. Validate Bedrock access, unless another provider serves the model
+	if v.cfg.Model.UsesBedrock() {
+		results = append(results, v.validateBedrockAccess(ctx))
+	}
 
 	// 3. Validate WAFR permissions
 	wafrResult := v.validateWAFRPermissions(ctx)
 	results = append(results, wafrResult)
 
+	// 4. Simulate the IAM policies for every action a review calls
+	results = append(results, v.validateIAMPermissions(ctx))
+
 	return results, nil
 }
 
+// regionPattern matches AWS region names such as eu-west-1 or us-gov-west-1
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+// ValidateRegion checks that region looks like an AWS region name
+func ValidateRegion(region string) error {
+	if !regionPattern.MatchString(region) {
+		return fmt.Errorf("%q is not a valid AWS region", region)
+	}
+	return nil
+}
+
+// ValidateFIPSRegion checks that FIPS endpoints are offered in region. AWS
+// only provides them in US, AWS GovCloud (US) and Canada regions.
+func ValidateFIPSRegion(region string) error {
+	if !strings.HasPrefix(region, "us-") && !strings.HasPrefix(region, "ca-") {
+		return fmt.Errorf("FIPS endpoints are not available in %s, only in US, AWS GovCloud (US) and Canada regions", region)
+	}
+	return nil
+}
+
+// wafrRegion returns the region of the Well-Architected Tool: aws.region, or
+// the SDK default (AWS_REGION or the profile's region) when it is not set
+func (v *Validator) wafrRegion(ctx context.Context) (string, error) {
+	if v.cfg.AWS.Region != "" {
+		return v.cfg.AWS.Region, nil
+	}
+
+	opts := []func(*config.LoadOptions) error{}
+	if v.cfg.AWS.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(v.cfg.AWS.Profile))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err == nil {
+		err = cassette.Apply(&awsCfg)
+	}
+	if err != nil {
+		return "", err
+	}
+	return awsCfg.Region, nil
+}
+
+// validateRegions checks the Bedrock and Well-Architected Tool regions,
+// which are configured independently. The Bedrock region is not checked
+// when another provider serves the model.
+func (v *Validator) validateRegions(ctx context.Context) ValidationResult {
+	result := ValidationResult{
+		Name: "AWS Regions",
+	}
+
+	usesBedrock := v.cfg.Model.UsesBedrock()
+	if err := ValidateRegion(v.cfg.Bedrock.Region); usesBedrock && err != nil {
+		result.Message = "Invalid Bedrock region (bedrock.region or --bedrock-region)"
+		result.Error = err
+		return result
+	}
+
+	wafrRegion, err := v.wafrRegion(ctx)
+	if err != nil {
+		result.Message = "Failed to load AWS config to resolve the Well-Architected Tool region"
+		result.Error = err
+		return result
+	}
+	if wafrRegion == "" {
+		result.Message = "No region for the Well-Architected Tool. Set aws.region, --region or AWS_REGION"
+		return result
+	}
+	if err := ValidateRegion(wafrRegion); err != nil {
+		result.Message = "Invalid Well-Architected Tool region (aws.region or --region)"
+		result.Error = err
+		return result
+	}
+
+	regions := []string{wafrRegion}
+	if usesBedrock {
+		regions = append(regions, v.cfg.Bedrock.Region)
+	}
+	if v.cfg.AWS.UseFIPS {
+		for _, region := range regions {
+			if err := ValidateFIPSRegion(region); err != nil {
+				result.Message = "Region does not support FIPS endpoints (aws.use_fips or --fips)"
+				result.Error = err
+				return result
+			}
+		}
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Well-Architected Tool region: %s", wafrRegion)
+	if usesBedrock {
+		result.Message = fmt.Sprintf("Bedrock region: %s, %s", v.cfg.Bedrock.Region, result.Message)
+	}
+	if v.cfg.AWS.UseFIPS {
+		result.Message += " (FIPS endpoints)"
+	}
+	return result
+}
+
 // validateCredentials checks if AWS credentials are configured
 func (v *Validator) validateCredentials(ctx context.Context) ValidationResult {
 	result := ValidationResult{
@@ -60,7 +172,9 @@ func (v *Validator) validateCredentials(ctx context.Context) ValidationResult {
 	}
 
 	// Try to load AWS config
-	opts := []func(*config.LoadOptions) error{}
+	opts := []func(*config.LoadOptions) error{
+		config.WithUseFIPSEndpoint(v.cfg.AWS.FIPSEndpointState()),
+	}
 
 	if v.cfg.AWS.Region != "" {
 		opts = append(opts, config.WithRegion(v.cfg.AWS.Region))
@@ -73,6 +187,9 @@ func (v *Validator) validateCredentials(ctx context.Context) ValidationResult {
 	}
 
 	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err == nil {
+		err = cassette.Apply(&awsCfg)
+	}
 	if err != nil {
 		result.Success = false
 		result.Message = "Failed to load AWS credentials"
@@ -108,6 +225,7 @@ func (v *Validator) validateBedrockAccess(ctx context.Context) ValidationResult
 	// Load AWS config
 	opts := []func(*config.LoadOptions) error{
 		config.WithRegion(v.cfg.Bedrock.Region),
+		config.WithUseFIPSEndpoint(v.cfg.AWS.FIPSEndpointState()),
 	}
 
 	if v.cfg.AWS.Profile != "" {
@@ -115,6 +233,9 @@ func (v *Validator) validateBedrockAccess(ctx context.Context) ValidationResult
 	}
 
 	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err == nil {
+		err = cassette.Apply(&awsCfg)
+	}
 	if err != nil {
 		result.Success = false
 		result.Message = "Failed to load AWS config for Bedrock"
@@ -193,20 +314,27 @@ func (v *Validator) validateWAFRPermissions(ctx context.Context) ValidationResul
 		Name: "Well-Architected Tool Permissions",
 	}
 
-	// Load AWS config
+	// Load AWS config. The workload lives in the AWS region, which may
+	// differ from the Bedrock region.
 	opts := []func(*config.LoadOptions) error{}
 
-	region := v.cfg.AWS.Region
-	if region == "" {
-		region = v.cfg.Bedrock.Region
+	region, err := v.wafrRegion(ctx)
+	if err != nil {
+		result.Success = false
+		result.Message = "Failed to load AWS config for WAFR"
+		result.Error = err
+		return result
 	}
-	opts = append(opts, config.WithRegion(region))
+	opts = append(opts, config.WithRegion(region), config.WithUseFIPSEndpoint(v.cfg.AWS.FIPSEndpointState()))
 
 	if v.cfg.AWS.Profile != "" {
 		opts = append(opts, config.WithSharedConfigProfile(v.cfg.AWS.Profile))
 	}
 
 	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err == nil {
+		err = cassette.Apply(&awsCfg)
+	}
 	if err != nil {
 		result.Success = false
 		result.Message = "Failed to load AWS config for WAFR"
@@ -252,6 +380,47 @@ func (v *Validator) validateWAFRPermissions(ctx context.Context) ValidationResul
 	return result
 }
 
+// validateIAMPermissions simulates the caller's IAM policies for the actions
+// Waffle calls. It only fails when a required action is known to be missing.
+func (v *Validator) validateIAMPermissions(ctx context.Context) ValidationResult {
+	result := ValidationResult{
+		Name: "IAM Permissions",
+	}
+
+	simulator, err := NewPolicySimulator(ctx, v.cfg)
+	if err != nil {
+		result.Message = "Failed to load AWS config for the IAM policy simulation"
+		result.Error = err
+		return result
+	}
+
+	report := CheckPermissions(ctx, simulator, v.cfg.RequiredActions())
+	result.Success = report.OK()
+	result.Message = FormatPermissionReport(report)
+	return result
+}
+
+// FormatPermissionReport describes a permission report in a line per finding
+func FormatPermissionReport(report *PermissionReport) string {
+	if !report.Simulated {
+		return fmt.Sprintf("Skipped: could not simulate IAM policies (%v)", report.Error)
+	}
+
+	lines := []string{}
+	if report.OK() {
+		lines = append(lines, fmt.Sprintf("All required actions allowed for %s", report.Principal))
+	} else {
+		lines = append(lines, fmt.Sprintf("Missing required actions for %s: %s", report.Principal, strings.Join(report.MissingRequired, ", ")))
+	}
+	for _, action := range report.UnscopedRequired {
+		lines = append(lines, fmt.Sprintf("Not allowed on every resource: %s, check that it is allowed on the workloads and models Waffle uses", action))
+	}
+	for _, action := range report.MissingOptional {
+		lines = append(lines, fmt.Sprintf("Not allowed: %s, needed for %s", action, OptionalActions[action]))
+	}
+	return strings.Join(lines, "\n  ")
+}
+
 // AllSuccess returns true if all validation results are successful
 func AllSuccess(results []ValidationResult) bool {
 	for _, r := range results {
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRegion(t *testing.T) {
+	tests := []struct {
+		region  string
+		wantErr bool
+	}{
+		{region: "us-east-1"},
+		{region: "eu-central-2"},
+		{region: "us-gov-west-1"},
+		{region: "", wantErr: true},
+		{region: "us-east", wantErr: true},
+		{region: "US-EAST-1", wantErr: true},
+		{region: "useast1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			err := ValidateRegion(tt.region)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateFIPSRegion(t *testing.T) {
+	for _, region := range []string{"us-east-1", "us-gov-west-1", "ca-central-1"} {
+		assert.NoError(t, ValidateFIPSRegion(region), region)
+	}
+	for _, region := range []string{"eu-west-1", "ap-southeast-2"} {
+		assert.Error(t, ValidateFIPSRegion(region), region)
+	}
+}
+
+func TestValidateRegions(t *testing.T) {
+	tests := []struct {
+		name          string
+		bedrockRegion string
+		awsRegion     string
+		useFIPS       bool
+		provider      string
+		wantSuccess   bool
+		wantMessage   string
+	}{
+		{
+			name:          "different regions",
+			bedrockRegion: "us-east-1",
+			awsRegion:     "eu-west-1",
+			wantSuccess:   true,
+			wantMessage:   "Bedrock region: us-east-1, Well-Architected Tool region: eu-west-1",
+		},
+		{
+			name:          "invalid bedrock region",
+			bedrockRegion: "not-a-region",
+			awsRegion:     "eu-west-1",
+			wantMessage:   "Invalid Bedrock region",
+		},
+		{
+			name:          "invalid workload region",
+			bedrockRegion: "us-east-1",
+			awsRegion:     "eu_west_1",
+			wantMessage:   "Invalid Well-Architected Tool region",
+		},
+		{
+			name:          "fips in govcloud",
+			bedrockRegion: "us-gov-west-1",
+			awsRegion:     "us-gov-west-1",
+			useFIPS:       true,
+			wantSuccess:   true,
+			wantMessage:   "Well-Architected Tool region: us-gov-west-1 (FIPS endpoints)",
+		},
+		{
+			name:          "fips outside us and canada",
+			bedrockRegion: "us-east-1",
+			awsRegion:     "eu-west-1",
+			useFIPS:       true,
+			wantMessage:   "Region does not support FIPS endpoints",
+		},
+		{
+			name:          "bedrock region unused by openai-compatible provider",
+			bedrockRegion: "not-a-region",
+			awsRegion:     "eu-west-1",
+			provider:      ModelProviderOpenAICompatible,
+			wantSuccess:   true,
+			wantMessage:   "Well-Architected Tool region: eu-west-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Bedrock.Region = tt.bedrockRegion
+			cfg.AWS.Region = tt.awsRegion
+			cfg.AWS.UseFIPS = tt.useFIPS
+			if tt.provider != "" {
+				cfg.Model = ModelConfig{Provider: tt.provider, BaseURL: "https://llm.example.com/v1", Name: "llama"}
+			}
+
+			result := NewValidator(cfg).validateRegions(context.Background())
+
+			assert.Equal(t, tt.wantSuccess, result.Success)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			if tt.provider != "" {
+				assert.NotContains(t, result.Message, "Bedrock")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// LoadWorkloadMetadata reads the workload metadata file from dir.
+// It returns nil without an error when the file does not exist.
+func LoadWorkloadMetadata(dir string) (*core.WorkloadMetadata, error) {
+	path := filepath.Join(dir, core.WorkloadMetadataFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read workload metadata: %w", err)
+	}
+
+	var metadata core.WorkloadMetadata
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestLoadWorkloadMetadata(t *testing.T) {
+	dir := t.TempDir()
+	content := `owner: platform-team@example.com
+business_unit: payments
+criticality: high
+description: Payments API
+tags:
+  CostCenter: "1234"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, core.WorkloadMetadataFileName), []byte(content), 0644))
+
+	metadata, err := LoadWorkloadMetadata(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, &core.WorkloadMetadata{
+		Owner:        "platform-team@example.com",
+		BusinessUnit: "payments",
+		Criticality:  "high",
+		Description:  "Payments API",
+		Tags:         map[string]string{"CostCenter": "1234"},
+	}, metadata)
+}
+
+func TestLoadWorkloadMetadata_Missing(t *testing.T) {
+	metadata, err := LoadWorkloadMetadata(t.TempDir())
+
+	require.NoError(t, err)
+	assert.Nil(t, metadata)
+}
+
+func TestLoadWorkloadMetadata_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, core.WorkloadMetadataFileName), []byte("owner: [unclosed"), 0644))
+
+	_, err := LoadWorkloadMetadata(dir)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), core.WorkloadMetadataFileName)
+}
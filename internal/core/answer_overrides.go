@@ -0,0 +1,86 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnswerOverride forces the choices selected for a question, such as a
+// practice that policy mandates whatever the IaC shows
+type AnswerOverride struct {
+	Choices []string `yaml:"choices"`
+	// Reason is recorded in the answer notes
+	Reason string `yaml:"reason"`
+}
+
+// AnswerOverrides maps question IDs to forced answers. Overridden questions
+// are not sent to Bedrock.
+type AnswerOverrides map[string]AnswerOverride
+
+// Validate checks that every override names a question and selects at least
+// one non-empty choice
+func (o AnswerOverrides) Validate() error {
+	for _, questionID := range o.QuestionIDs() {
+		override := o[questionID]
+		if strings.TrimSpace(questionID) == "" {
+			return fmt.Errorf("answer override with an empty question ID")
+		}
+		if len(override.Choices) == 0 {
+			return fmt.Errorf("answer override for %s selects no choices", questionID)
+		}
+		for _, choiceID := range override.Choices {
+			if strings.TrimSpace(choiceID) == "" {
+				return fmt.Errorf("answer override for %s has an empty choice ID", questionID)
+			}
+		}
+	}
+	return nil
+}
+
+// QuestionIDs returns the overridden question IDs in sorted order
+func (o AnswerOverrides) QuestionIDs() []string {
+	ids := make([]string, 0, len(o))
+	for id := range o {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Evaluation builds the evaluation that replaces the model's answer to
+// question. Choice IDs must be among the question's choices when it lists
+// any. source names the overrides file in the notes.
+func (o AnswerOverride) Evaluation(question *WAFRQuestion, source string) (*QuestionEvaluation, error) {
+	known := make(map[string]Choice, len(question.Choices))
+	for _, choice := range question.Choices {
+		known[choice.ID] = choice
+	}
+
+	selected := make([]Choice, 0, len(o.Choices))
+	for _, choiceID := range o.Choices {
+		choice, ok := known[choiceID]
+		if !ok {
+			if len(question.Choices) > 0 {
+				return nil, fmt.Errorf("answer override for %s: %q is not a choice of the question", question.ID, choiceID)
+			}
+			choice = Choice{ID: choiceID}
+		}
+		selected = append(selected, choice)
+	}
+
+	notes := "Answer overridden by " + source
+	if o.Reason != "" {
+		notes += ": " + o.Reason
+	}
+
+	return &QuestionEvaluation{
+		Question:        question,
+		SelectedChoices: selected,
+		Evidence:        []Evidence{},
+		ConfidenceScore: 1.0,
+		Notes:           notes,
+		Overridden:      true,
+		Source:          SourceOverride,
+	}, nil
+}
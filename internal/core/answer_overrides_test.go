@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswerOverride_Evaluation(t *testing.T) {
+	question := &WAFRQuestion{
+		ID:     "sec_data_1",
+		Pillar: PillarSecurity,
+		Choices: []Choice{
+			{ID: "sec_data_1_a", Title: "Encrypt at rest"},
+			{ID: "sec_data_1_b", Title: "Rotate keys"},
+		},
+	}
+
+	t.Run("known choices", func(t *testing.T) {
+		evaluation, err := AnswerOverride{Choices: []string{"sec_data_1_b"}, Reason: "policy"}.Evaluation(question, "answers.yaml")
+
+		require.NoError(t, err)
+		assert.Same(t, question, evaluation.Question)
+		assert.Equal(t, []Choice{{ID: "sec_data_1_b", Title: "Rotate keys"}}, evaluation.SelectedChoices)
+		assert.Equal(t, 1.0, evaluation.ConfidenceScore)
+		assert.Equal(t, "Answer overridden by answers.yaml: policy", evaluation.Notes)
+		assert.True(t, evaluation.Overridden)
+		assert.Equal(t, SourceOverride, evaluation.Source)
+	})
+
+	t.Run("unknown choice", func(t *testing.T) {
+		_, err := AnswerOverride{Choices: []string{"sec_data_1_z"}}.Evaluation(question, "answers.yaml")
+
+		assert.ErrorContains(t, err, `answer override for sec_data_1: "sec_data_1_z" is not a choice of the question`)
+	})
+
+	t.Run("question without choices", func(t *testing.T) {
+		evaluation, err := AnswerOverride{Choices: []string{"sec_data_1_a"}}.Evaluation(&WAFRQuestion{ID: "sec_data_1"}, "answers.yaml")
+
+		require.NoError(t, err)
+		assert.Equal(t, []Choice{{ID: "sec_data_1_a"}}, evaluation.SelectedChoices)
+		assert.Equal(t, "Answer overridden by answers.yaml", evaluation.Notes)
+	})
+}
+
+func TestExecuteReview_AnswerOverrides(t *testing.T) {
+	var evaluated []string
+	submitted := make(map[string]*QuestionEvaluation)
+	wafrEval := &mockWAFREvaluator{
+		getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+			return []*WAFRQuestion{
+				{ID: "sec_data_1", Pillar: PillarSecurity, Choices: []Choice{{ID: "sec_data_1_a"}, {ID: "sec_data_1_b"}}},
+				{ID: "rel_1", Pillar: PillarReliability, Choices: []Choice{{ID: "rel_1_a"}}},
+			}, nil
+		},
+		evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+			evaluated = append(evaluated, question.ID)
+			return &QuestionEvaluation{Question: question, SelectedChoices: []Choice{{ID: "rel_1_a"}}, ConfidenceScore: 0.8, Source: SourceModel}, nil
+		},
+		submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+			submitted[questionID] = evaluation
+			return nil
+		},
+	}
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetAnswerOverrides(AnswerOverrides{
+		"sec_data_1": {Choices: []string{"sec_data_1_b"}, Reason: "policy"},
+		"ops_9":      {Choices: []string{"ops_9_a"}},
+	}, "answers.yaml")
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+		WorkloadModel: &WorkloadModel{Framework: "terraform", Resources: []Resource{{ID: "test-resource"}}},
+	}
+
+	results, err := engine.ExecuteReview(context.Background(), session)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rel_1"}, evaluated, "overridden questions bypass evaluation")
+	require.Contains(t, submitted, "sec_data_1")
+	assert.Equal(t, []Choice{{ID: "sec_data_1_b"}}, submitted["sec_data_1"].SelectedChoices)
+	assert.Equal(t, "Answer overridden by answers.yaml: policy", submitted["sec_data_1"].Notes)
+	assert.True(t, submitted["sec_data_1"].Overridden)
+	assert.False(t, submitted["rel_1"].Overridden)
+	assert.Equal(t, SourceOverride, submitted["sec_data_1"].Source)
+	assert.Equal(t, SourceModel, submitted["rel_1"].Source)
+	assert.Len(t, results.Evaluations, 2)
+
+	output := ConvertEvaluationsToOutput(results.Evaluations)
+	sources := map[string]string{}
+	for _, evaluation := range output {
+		sources[evaluation.QuestionID] = evaluation.Source
+	}
+	assert.Equal(t, map[string]string{"sec_data_1": "override", "rel_1": "model"}, sources)
+}
+
+func TestExecuteReview_AnswerOverrideUnknownChoice(t *testing.T) {
+	wafrEval := &mockWAFREvaluator{
+		getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+			return []*WAFRQuestion{{ID: "sec_data_1", Pillar: PillarSecurity, Choices: []Choice{{ID: "sec_data_1_a"}}}}, nil
+		},
+		submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+			t.Fatal("no answer is submitted when an override is invalid")
+			return nil
+		},
+	}
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetAnswerOverrides(AnswerOverrides{"sec_data_1": {Choices: []string{"sec_data_1_typo"}}}, "answers.yaml")
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+		WorkloadModel: &WorkloadModel{Framework: "terraform", Resources: []Resource{{ID: "test-resource"}}},
+	}
+
+	_, err := engine.ExecuteReview(context.Background(), session)
+
+	assert.ErrorContains(t, err, `"sec_data_1_typo" is not a choice of the question`)
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Baseline is an organizational limit on acceptable risk counts per pillar.
+// Pillars without an entry, and limits left unset, are not checked.
+type Baseline struct {
+	Pillars map[Pillar]PillarBaseline `json:"pillars"`
+}
+
+// PillarBaseline holds the maximum acceptable risk counts for a pillar
+type PillarBaseline struct {
+	MaxHighRisks   *int `json:"max_high_risks,omitempty"`
+	MaxMediumRisks *int `json:"max_medium_risks,omitempty"`
+}
+
+// Baseline metrics compared against a review
+const (
+	BaselineMetricHighRisks   = "high_risks"
+	BaselineMetricMediumRisks = "medium_risks"
+)
+
+// BaselineRegression is a pillar metric that exceeds its baseline
+type BaselineRegression struct {
+	Pillar   Pillar
+	Metric   string
+	Baseline int
+	Actual   int
+}
+
+// BaselineComparison is the result of comparing a review against a baseline
+type BaselineComparison struct {
+	Passed      bool
+	Regressions []BaselineRegression
+	// Advisory holds the regressions of advisory pillars. They are reported
+	// but do not fail the comparison.
+	Advisory []BaselineRegression
+}
+
+// ReadBaseline reads and validates a baseline JSON document
+func ReadBaseline(r io.Reader) (*Baseline, error) {
+	var baseline Baseline
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("failed to decode baseline JSON: %w", err)
+	}
+
+	for pillar, limits := range baseline.Pillars {
+		if !pillar.IsValid() {
+			return nil, fmt.Errorf("%w in baseline: %q (valid: %s)", ErrInvalidPillar, pillar, pillarList())
+		}
+		if (limits.MaxHighRisks != nil && *limits.MaxHighRisks < 0) ||
+			(limits.MaxMediumRisks != nil && *limits.MaxMediumRisks < 0) {
+			return nil, fmt.Errorf("baseline limits for %s must not be negative", pillar)
+		}
+	}
+	return &baseline, nil
+}
+
+// Compare checks the pillar summaries of a review against the baseline.
+// Regressions are ordered by pillar in framework order, then by metric.
+// Pillars whose summary is marked advisory never fail the comparison.
+func (b *Baseline) Compare(summary *ResultsSummary) *BaselineComparison {
+	comparison := &BaselineComparison{Passed: true}
+	if b == nil {
+		return comparison
+	}
+
+	for _, pillar := range AllPillars() {
+		limits, ok := b.Pillars[pillar]
+		if !ok {
+			continue
+		}
+
+		// A pillar without results has no risks
+		var actual PillarSummary
+		if summary != nil {
+			actual = summary.PillarSummaries[pillar]
+		}
+
+		regressions := &comparison.Regressions
+		if actual.Advisory {
+			regressions = &comparison.Advisory
+		}
+		if limits.MaxHighRisks != nil && actual.HighRisks > *limits.MaxHighRisks {
+			*regressions = append(*regressions, BaselineRegression{
+				Pillar:   pillar,
+				Metric:   BaselineMetricHighRisks,
+				Baseline: *limits.MaxHighRisks,
+				Actual:   actual.HighRisks,
+			})
+		}
+		if limits.MaxMediumRisks != nil && actual.MediumRisks > *limits.MaxMediumRisks {
+			*regressions = append(*regressions, BaselineRegression{
+				Pillar:   pillar,
+				Metric:   BaselineMetricMediumRisks,
+				Baseline: *limits.MaxMediumRisks,
+				Actual:   actual.MediumRisks,
+			})
+		}
+	}
+
+	comparison.Passed = len(comparison.Regressions) == 0
+	return comparison
+}
+
+// RegressedPillars returns the pillars with at least one regression
+func (c *BaselineComparison) RegressedPillars() []Pillar {
+	var pillars []Pillar
+	for _, regression := range c.Regressions {
+		if len(pillars) == 0 || pillars[len(pillars)-1] != regression.Pillar {
+			pillars = append(pillars, regression.Pillar)
+		}
+	}
+	return pillars
+}
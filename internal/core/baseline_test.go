@@ -0,0 +1,122 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBaselineJSON = `{
+  "pillars": {
+    "security": {"max_high_risks": 0, "max_medium_risks": 2},
+    "reliability": {"max_high_risks": 1}
+  }
+}`
+
+func TestReadBaseline(t *testing.T) {
+	baseline, err := ReadBaseline(strings.NewReader(testBaselineJSON))
+	require.NoError(t, err)
+
+	require.Len(t, baseline.Pillars, 2)
+	assert.Equal(t, 0, *baseline.Pillars[PillarSecurity].MaxHighRisks)
+	assert.Equal(t, 2, *baseline.Pillars[PillarSecurity].MaxMediumRisks)
+	assert.Equal(t, 1, *baseline.Pillars[PillarReliability].MaxHighRisks)
+	assert.Nil(t, baseline.Pillars[PillarReliability].MaxMediumRisks)
+}
+
+func TestReadBaseline_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "not json", input: "pillars: {}", wantErr: "failed to decode baseline JSON"},
+		{name: "unknown pillar", input: `{"pillars": {"securty": {"max_high_risks": 0}}}`, wantErr: "invalid pillar"},
+		{name: "unknown field", input: `{"pillars": {"security": {"max_high": 0}}}`, wantErr: "unknown field"},
+		{name: "negative limit", input: `{"pillars": {"security": {"max_medium_risks": -1}}}`, wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadBaseline(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBaselineCompare(t *testing.T) {
+	baseline, err := ReadBaseline(strings.NewReader(testBaselineJSON))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		summaries       map[Pillar]PillarSummary
+		wantRegressions []BaselineRegression
+		wantAdvisory    []BaselineRegression
+	}{
+		{
+			name: "within baseline",
+			summaries: map[Pillar]PillarSummary{
+				PillarSecurity:    {HighRisks: 0, MediumRisks: 2},
+				PillarReliability: {HighRisks: 1, MediumRisks: 9},
+				// Pillars without a baseline entry are not checked
+				PillarCostOptimization: {HighRisks: 5},
+			},
+		},
+		{
+			name:      "pillars without results have no risks",
+			summaries: map[Pillar]PillarSummary{},
+		},
+		{
+			name: "regression",
+			summaries: map[Pillar]PillarSummary{
+				PillarSecurity:    {HighRisks: 1, MediumRisks: 3},
+				PillarReliability: {HighRisks: 2},
+			},
+			wantRegressions: []BaselineRegression{
+				{Pillar: PillarSecurity, Metric: BaselineMetricHighRisks, Baseline: 0, Actual: 1},
+				{Pillar: PillarSecurity, Metric: BaselineMetricMediumRisks, Baseline: 2, Actual: 3},
+				{Pillar: PillarReliability, Metric: BaselineMetricHighRisks, Baseline: 1, Actual: 2},
+			},
+		},
+		{
+			name: "advisory pillar regression does not fail",
+			summaries: map[Pillar]PillarSummary{
+				PillarSecurity:    {HighRisks: 1, MediumRisks: 3, Advisory: true},
+				PillarReliability: {HighRisks: 1},
+			},
+			wantAdvisory: []BaselineRegression{
+				{Pillar: PillarSecurity, Metric: BaselineMetricHighRisks, Baseline: 0, Actual: 1},
+				{Pillar: PillarSecurity, Metric: BaselineMetricMediumRisks, Baseline: 2, Actual: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := baseline.Compare(&ResultsSummary{PillarSummaries: tt.summaries})
+
+			assert.Equal(t, len(tt.wantRegressions) == 0, comparison.Passed)
+			assert.Equal(t, tt.wantRegressions, comparison.Regressions)
+			assert.Equal(t, tt.wantAdvisory, comparison.Advisory)
+		})
+	}
+}
+
+func TestBaselineComparison_RegressedPillars(t *testing.T) {
+	comparison := &BaselineComparison{
+		Regressions: []BaselineRegression{
+			{Pillar: PillarSecurity, Metric: BaselineMetricHighRisks},
+			{Pillar: PillarSecurity, Metric: BaselineMetricMediumRisks},
+			{Pillar: PillarReliability, Metric: BaselineMetricHighRisks},
+		},
+	}
+
+	pillars := comparison.RegressedPillars()
+
+	assert.Equal(t, []Pillar{PillarSecurity, PillarReliability}, pillars)
+	assert.Equal(t, "review exceeds baseline for pillars: security, reliability", (&BaselineExceededError{Pillars: pillars}).Error())
+}
@@ -0,0 +1,84 @@
+package core
+
+import "math"
+
+// CalibrationBands is the number of equal-width bands the 0 to 1 confidence
+// range is split into
+const CalibrationBands = 10
+
+// Calibration is the distribution of confidence scores across the questions
+// of a review, for judging whether the model is over- or under-confident and
+// tuning the risk thresholds accordingly
+type Calibration struct {
+	Questions         int
+	AverageConfidence float64
+	// Bands always holds CalibrationBands bands in ascending order
+	Bands   []CalibrationBand
+	Pillars map[Pillar]PillarCalibration
+}
+
+// CalibrationBand counts the evaluations whose confidence score is at least
+// Min and below Max. The top band also counts scores of exactly 1.
+type CalibrationBand struct {
+	Min   float64
+	Max   float64
+	Count int
+}
+
+// PillarCalibration holds the confidence statistics of one pillar
+type PillarCalibration struct {
+	Questions         int
+	AverageConfidence float64
+}
+
+// BuildCalibration buckets the confidence scores of evaluations into
+// CalibrationBands bands and averages them per pillar. Scores outside 0 to 1
+// are counted in the nearest band.
+func BuildCalibration(evaluations []*QuestionEvaluation) *Calibration {
+	calibration := &Calibration{
+		Bands:   make([]CalibrationBand, CalibrationBands),
+		Pillars: make(map[Pillar]PillarCalibration),
+	}
+	for i := range calibration.Bands {
+		calibration.Bands[i].Min = float64(i) / CalibrationBands
+		calibration.Bands[i].Max = float64(i+1) / CalibrationBands
+	}
+
+	var total float64
+	pillarTotals := make(map[Pillar]float64)
+	for _, eval := range evaluations {
+		if eval == nil {
+			continue
+		}
+		score := eval.ConfidenceScore
+		calibration.Bands[calibrationBand(score)].Count++
+		calibration.Questions++
+		total += score
+
+		if eval.Question == nil {
+			continue
+		}
+		pillar := calibration.Pillars[eval.Question.Pillar]
+		pillar.Questions++
+		calibration.Pillars[eval.Question.Pillar] = pillar
+		pillarTotals[eval.Question.Pillar] += score
+	}
+
+	if calibration.Questions > 0 {
+		calibration.AverageConfidence = total / float64(calibration.Questions)
+	}
+	for p, pillar := range calibration.Pillars {
+		pillar.AverageConfidence = pillarTotals[p] / float64(pillar.Questions)
+		calibration.Pillars[p] = pillar
+	}
+
+	return calibration
+}
+
+// calibrationBand returns the index of the band score falls in. The small
+// epsilon keeps scores such as 0.3 that are not exact in binary out of the
+// band below.
+func calibrationBand(score float64) int {
+	band := int(math.Floor(score*CalibrationBands + 1e-9))
+	return max(0, min(band, CalibrationBands-1))
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func calibrationEvaluation(pillar Pillar, score float64) *QuestionEvaluation {
+	return &QuestionEvaluation{Question: &WAFRQuestion{ID: "q", Pillar: pillar}, ConfidenceScore: score}
+}
+
+func TestBuildCalibration(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		calibrationEvaluation(PillarSecurity, 0.0),
+		calibrationEvaluation(PillarSecurity, 0.05),
+		calibrationEvaluation(PillarSecurity, 0.3),
+		calibrationEvaluation(PillarSecurity, 0.35),
+		calibrationEvaluation(PillarReliability, 0.1+0.2), // 0.30000000000000004
+		calibrationEvaluation(PillarReliability, 0.7),
+		calibrationEvaluation(PillarReliability, 0.99),
+		calibrationEvaluation(PillarCostOptimization, 1.0),
+		nil,
+	}
+
+	calibration := BuildCalibration(evaluations)
+
+	counts := make([]int, 0, len(calibration.Bands))
+	for _, band := range calibration.Bands {
+		counts = append(counts, band.Count)
+	}
+	assert.Equal(t, []int{2, 0, 0, 3, 0, 0, 0, 1, 0, 2}, counts)
+	assert.Equal(t, 8, calibration.Questions)
+	assert.InDelta(t, 3.69/8, calibration.AverageConfidence, 1e-9)
+
+	require.Len(t, calibration.Pillars, 3)
+	assert.Equal(t, 4, calibration.Pillars[PillarSecurity].Questions)
+	assert.InDelta(t, 0.175, calibration.Pillars[PillarSecurity].AverageConfidence, 1e-9)
+	assert.Equal(t, 3, calibration.Pillars[PillarReliability].Questions)
+	assert.InDelta(t, 0.663333, calibration.Pillars[PillarReliability].AverageConfidence, 1e-6)
+	assert.InDelta(t, 1.0, calibration.Pillars[PillarCostOptimization].AverageConfidence, 1e-9)
+}
+
+func TestBuildCalibration_Bands(t *testing.T) {
+	tests := []struct {
+		name  string
+		score float64
+		band  int
+	}{
+		{name: "zero", score: 0, band: 0},
+		{name: "lower bound is inclusive", score: 0.5, band: 5},
+		{name: "upper bound is exclusive", score: 0.4999, band: 4},
+		{name: "one falls in the top band", score: 1, band: 9},
+		{name: "negative scores are clamped", score: -0.2, band: 0},
+		{name: "scores above one are clamped", score: 1.5, band: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calibration := BuildCalibration([]*QuestionEvaluation{calibrationEvaluation(PillarSecurity, tt.score)})
+			assert.Equal(t, 1, calibration.Bands[tt.band].Count)
+		})
+	}
+}
+
+func TestBuildCalibration_NoEvaluations(t *testing.T) {
+	calibration := BuildCalibration(nil)
+
+	assert.Zero(t, calibration.Questions)
+	assert.Zero(t, calibration.AverageConfidence)
+	assert.Empty(t, calibration.Pillars)
+	require.Len(t, calibration.Bands, CalibrationBands)
+	assert.Equal(t, 0.0, calibration.Bands[0].Min)
+	assert.Equal(t, 0.1, calibration.Bands[0].Max)
+	assert.Equal(t, 0.9, calibration.Bands[9].Min)
+	assert.Equal(t, 1.0, calibration.Bands[9].Max)
+}
+
+func TestConvertCalibrationToOutput(t *testing.T) {
+	calibration := BuildCalibration([]*QuestionEvaluation{
+		calibrationEvaluation(PillarSecurity, 0.42),
+		calibrationEvaluation(PillarSecurity, 0.48),
+	})
+
+	output := ConvertCalibrationToOutput(calibration)
+
+	require.Len(t, output.Bands, CalibrationBands)
+	assert.Equal(t, CalibrationBandOutput{Min: 0.4, Max: 0.5, Count: 2}, output.Bands[4])
+	assert.Equal(t, 2, output.Pillars["security"].Questions)
+	assert.InDelta(t, 0.45, output.Pillars["security"].AverageConfidence, 1e-9)
+	assert.Nil(t, ConvertCalibrationToOutput(nil))
+}
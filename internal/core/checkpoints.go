@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resumeCheckpoints are the checkpoints a session can be rewound to, in the
+// order the workflow records them. Each names the last step completed, so
+// resuming from it re-runs every later step. The milestone checkpoints are
+// left out since no step follows them.
+var resumeCheckpoints = []string{
+	"created",
+	"iac_analysis_complete",
+	"questions_retrieved",
+	"questions_evaluated",
+	"answers_submitted",
+	"improvement_plan_retrieved",
+}
+
+// ResumeCheckpoints returns the checkpoints a session can be rewound to, in
+// workflow order
+func ResumeCheckpoints() []string {
+	return append([]string(nil), resumeCheckpoints...)
+}
+
+// checkpointIndex returns the position of checkpoint in the workflow, or -1
+// if it is unknown. A session saved before any step has no checkpoint and
+// counts as created; the milestone checkpoints come after every other one.
+func checkpointIndex(checkpoint string) int {
+	switch checkpoint {
+	case "":
+		return 0
+	case "milestone_created", "milestone_skipped":
+		return len(resumeCheckpoints)
+	}
+	for i, c := range resumeCheckpoints {
+		if c == checkpoint {
+			return i
+		}
+	}
+	return -1
+}
+
+// rewindCheckpoint moves session back to checkpoint. The checkpoint must not
+// come after the one the session reached, and the saved session must hold
+// what the steps after it start from: the workload model, and the
+// evaluations and improvement plan of an earlier run, which are only saved
+// once a review completes.
+func rewindCheckpoint(session *ReviewSession, checkpoint string) error {
+	target := checkpointIndex(checkpoint)
+	if checkpoint == "" || target == len(resumeCheckpoints) || target < 0 {
+		return &ValidationError{
+			Field:   "checkpoint",
+			Value:   checkpoint,
+			Message: fmt.Sprintf("must be one of %s", strings.Join(resumeCheckpoints, ", ")),
+		}
+	}
+	if current := checkpointIndex(session.Checkpoint); target > current {
+		return &ValidationError{
+			Field:   "checkpoint",
+			Value:   checkpoint,
+			Message: fmt.Sprintf("cannot skip forward from checkpoint %q", session.Checkpoint),
+		}
+	}
+
+	var missing string
+	switch {
+	case target >= checkpointIndex("iac_analysis_complete") && session.WorkloadModel == nil:
+		missing = "the IaC analysis"
+	case target >= checkpointIndex("questions_retrieved") && (session.Results == nil || len(session.Results.Evaluations) == 0):
+		missing = "the evaluated questions"
+	case target >= checkpointIndex("improvement_plan_retrieved") && session.Results.ImprovementPlan == nil:
+		missing = "the improvement plan"
+	}
+	if missing != "" {
+		return &ValidationError{
+			Field:   "checkpoint",
+			Value:   checkpoint,
+			Message: fmt.Sprintf("session has no saved results of %s to resume from", missing),
+		}
+	}
+
+	session.Checkpoint = checkpoint
+	// The milestone step runs again
+	session.MilestoneID = ""
+	session.MilestoneSkipped = false
+	return nil
+}
+
+// savedQuestions returns the questions of the evaluations saved on session,
+// which a rewound session evaluates again
+func savedQuestions(session *ReviewSession) []*WAFRQuestion {
+	if session.Results == nil {
+		return nil
+	}
+	questions := make([]*WAFRQuestion, 0, len(session.Results.Evaluations))
+	for _, evaluation := range session.Results.Evaluations {
+		questions = append(questions, evaluation.Question)
+	}
+	return questions
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// completedSession returns a session whose review ran to the end
+func completedSession(sessionID string) *ReviewSession {
+	question := &WAFRQuestion{ID: "sec-1", Pillar: PillarSecurity, Title: "Test Question"}
+	return &ReviewSession{
+		SessionID:     sessionID,
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		MilestoneID:   "milestone-old",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCompleted,
+		Checkpoint:    "milestone_created",
+		WorkloadModel: &WorkloadModel{
+			Framework: "terraform",
+			Resources: []Resource{{ID: "test-resource"}},
+		},
+		Results: &ReviewResults{
+			Evaluations: []*QuestionEvaluation{
+				{Question: question, SelectedChoices: []Choice{{ID: "old-choice"}}, ConfidenceScore: 0.5},
+			},
+			ImprovementPlan: &ImprovementPlan{Items: []*ImprovementPlanItem{{ID: "item-1"}}},
+		},
+	}
+}
+
+func TestResumeSessionFrom_Rewind(t *testing.T) {
+	tests := []struct {
+		name          string
+		checkpoint    string
+		wantEvaluated bool
+		wantSubmitted bool
+	}{
+		{name: "re-evaluate questions", checkpoint: "questions_retrieved", wantEvaluated: true, wantSubmitted: true},
+		{name: "resubmit saved answers", checkpoint: "questions_evaluated", wantSubmitted: true},
+		{name: "recreate milestone", checkpoint: "improvement_plan_retrieved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []string
+			sessionMgr := &mockSessionManager{
+				loadSessionFunc: func(ctx context.Context, sessionID string) (*ReviewSession, error) {
+					return completedSession(sessionID), nil
+				},
+				saveSessionFunc: func(ctx context.Context, session *ReviewSession) error {
+					saved = append(saved, session.Checkpoint)
+					return nil
+				},
+			}
+			var evaluated, submitted []string
+			wafrEval := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					t.Fatal("questions should not be retrieved again")
+					return nil, nil
+				},
+				evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+					evaluated = append(evaluated, question.ID)
+					return &QuestionEvaluation{Question: question, SelectedChoices: []Choice{{ID: "new-choice"}}, ConfidenceScore: 0.9}, nil
+				},
+				submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+					submitted = append(submitted, evaluation.SelectedChoices[0].ID)
+					return nil
+				},
+			}
+
+			engine := NewEngine(sessionMgr, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			session, err := engine.ResumeSessionFrom(context.Background(), "test-session", tt.checkpoint)
+			require.NoError(t, err)
+
+			// The rewound checkpoint is saved before any step runs
+			require.NotEmpty(t, saved)
+			assert.Equal(t, tt.checkpoint, saved[0])
+
+			assert.Equal(t, SessionStatusCompleted, session.Status)
+			assert.Equal(t, "milestone_created", session.Checkpoint)
+			assert.Equal(t, "milestone-123", session.MilestoneID)
+			require.NotNil(t, session.Results)
+			require.Len(t, session.Results.Evaluations, 1)
+			assert.NotNil(t, session.Results.ImprovementPlan)
+
+			if tt.wantEvaluated {
+				assert.Equal(t, []string{"sec-1"}, evaluated)
+				assert.Equal(t, []string{"new-choice"}, submitted)
+				assert.Equal(t, "new-choice", session.Results.Evaluations[0].SelectedChoices[0].ID)
+			} else {
+				assert.Empty(t, evaluated)
+				assert.Equal(t, "old-choice", session.Results.Evaluations[0].SelectedChoices[0].ID)
+			}
+			if tt.wantSubmitted && !tt.wantEvaluated {
+				assert.Equal(t, []string{"old-choice"}, submitted)
+			}
+			if !tt.wantSubmitted {
+				assert.Empty(t, submitted)
+			}
+		})
+	}
+}
+
+func TestResumeSessionFrom_InvalidCheckpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		session    func() *ReviewSession
+		checkpoint string
+		wantErr    string
+	}{
+		{
+			name: "skip forward",
+			session: func() *ReviewSession {
+				session := completedSession("test-session")
+				session.Status = SessionStatusFailed
+				session.Checkpoint = "iac_analysis_complete"
+				return session
+			},
+			checkpoint: "questions_evaluated",
+			wantErr:    `cannot skip forward from checkpoint "iac_analysis_complete"`,
+		},
+		{
+			name: "unknown checkpoint",
+			session: func() *ReviewSession {
+				return completedSession("test-session")
+			},
+			checkpoint: "bogus",
+			wantErr:    "must be one of created, iac_analysis_complete",
+		},
+		{
+			name: "final checkpoint",
+			session: func() *ReviewSession {
+				return completedSession("test-session")
+			},
+			checkpoint: "milestone_created",
+			wantErr:    "must be one of",
+		},
+		{
+			name: "no saved evaluations",
+			session: func() *ReviewSession {
+				session := completedSession("test-session")
+				session.Status = SessionStatusFailed
+				session.Checkpoint = "answers_submitted"
+				session.Results = nil
+				return session
+			},
+			checkpoint: "questions_evaluated",
+			wantErr:    "no saved results of the evaluated questions",
+		},
+		{
+			name: "no workload model",
+			session: func() *ReviewSession {
+				session := completedSession("test-session")
+				session.WorkloadModel = nil
+				return session
+			},
+			checkpoint: "iac_analysis_complete",
+			wantErr:    "no saved results of the IaC analysis",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionMgr := &mockSessionManager{
+				loadSessionFunc: func(ctx context.Context, sessionID string) (*ReviewSession, error) {
+					return tt.session(), nil
+				},
+				saveSessionFunc: func(ctx context.Context, session *ReviewSession) error {
+					t.Fatal("an invalid rewind should not save the session")
+					return nil
+				},
+			}
+			wafrEval := &mockWAFREvaluator{
+				evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+					t.Fatal("an invalid rewind should not evaluate questions")
+					return nil, nil
+				},
+			}
+
+			engine := NewEngine(sessionMgr, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			_, err := engine.ResumeSessionFrom(context.Background(), "test-session", tt.checkpoint)
+			require.Error(t, err)
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, "checkpoint", validationErr.Field)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestResumeCheckpoints(t *testing.T) {
+	checkpoints := ResumeCheckpoints()
+	assert.Equal(t, "created", checkpoints[0])
+	assert.Equal(t, "improvement_plan_retrieved", checkpoints[len(checkpoints)-1])
+
+	// The returned slice is a copy
+	checkpoints[0] = "changed"
+	assert.Equal(t, "created", ResumeCheckpoints()[0])
+}
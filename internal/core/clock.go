@@ -0,0 +1,17 @@
+package core
+
+import "time"
+
+// Clock tells the time. The engine and evaluator read timestamps from a
+// Clock so tests can pin them.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by the system time
+type SystemClock struct{}
+
+// Now returns the current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
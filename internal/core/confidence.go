@@ -0,0 +1,60 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNoConfidenceBreakdown is returned when an evaluation was stored without
+// the factors used to derive its confidence score
+var ErrNoConfidenceBreakdown = errors.New("evaluation has no confidence breakdown")
+
+// ConfidenceFactor is one data-completeness factor applied to the model's
+// confidence
+type ConfidenceFactor struct {
+	// Name identifies the factor, e.g. "resource_availability"
+	Name string
+	// Multiplier is the factor value between 0.0 and 1.0
+	Multiplier float64
+	// Reason describes the condition that selected the multiplier
+	Reason string
+}
+
+// ConfidenceBreakdown records how a final confidence score was derived.
+// The final score is BaseConfidence multiplied by the average of the factor
+// multipliers, clamped to [0.0, 1.0].
+type ConfidenceBreakdown struct {
+	BaseConfidence    float64
+	Factors           []ConfidenceFactor
+	AverageMultiplier float64
+	FinalConfidence   float64
+}
+
+// WriteConfidenceExplanation writes a human-readable breakdown of how the
+// confidence score of an evaluation was derived
+func WriteConfidenceExplanation(w io.Writer, evaluation *QuestionEvaluation) error {
+	if evaluation == nil || evaluation.ConfidenceBreakdown == nil {
+		return ErrNoConfidenceBreakdown
+	}
+	b := evaluation.ConfidenceBreakdown
+
+	if evaluation.Question != nil {
+		if _, err := fmt.Fprintf(w, "Question: %s - %s\n\n", evaluation.Question.ID, evaluation.Question.Title); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "Model confidence:        %.2f\n\nData completeness factors:\n", b.BaseConfidence); err != nil {
+		return err
+	}
+	for _, f := range b.Factors {
+		if _, err := fmt.Fprintf(w, "  %-24s x%.2f  (%s)\n", f.Name, f.Multiplier, f.Reason); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\nAverage multiplier:      %.2f\nFinal confidence:        %.2f x %.2f = %.2f\n",
+		b.AverageMultiplier, b.BaseConfidence, b.AverageMultiplier, b.FinalConfidence)
+	return err
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteConfidenceExplanation(t *testing.T) {
+	evaluation := &QuestionEvaluation{
+		Question:        &WAFRQuestion{ID: "sec_1", Title: "How do you protect your data?"},
+		ConfidenceScore: 0.68,
+		ConfidenceBreakdown: &ConfidenceBreakdown{
+			BaseConfidence: 0.9,
+			Factors: []ConfidenceFactor{
+				{Name: "resource_availability", Multiplier: 0.7, Reason: "only 3 resources analyzed, fewer than 5"},
+				{Name: "evidence_quality", Multiplier: 1.0, Reason: "evidence references specific resources"},
+				{Name: "source_type", Multiplier: 0.85, Reason: "HCL source without computed values"},
+			},
+			AverageMultiplier: 0.85,
+			FinalConfidence:   0.765,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteConfidenceExplanation(&buf, evaluation))
+
+	out := buf.String()
+	assert.Contains(t, out, "Question: sec_1 - How do you protect your data?")
+	assert.Contains(t, out, "Model confidence:        0.90")
+	assert.Regexp(t, `resource_availability\s+x0\.70\s+\(only 3 resources analyzed, fewer than 5\)`, out)
+	assert.Regexp(t, `evidence_quality\s+x1\.00`, out)
+	assert.Regexp(t, `source_type\s+x0\.85\s+\(HCL source without computed values\)`, out)
+	assert.Contains(t, out, "Final confidence:        0.90 x 0.85 = 0.77")
+}
+
+func TestWriteConfidenceExplanation_NoBreakdown(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteConfidenceExplanation(&buf, &QuestionEvaluation{ConfidenceScore: 0.5})
+
+	assert.ErrorIs(t, err, ErrNoConfidenceBreakdown)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,43 @@
+package core
+
+import "time"
+
+// ConsolidatedReport is the consolidated report metrics of one workload as
+// returned by the Well-Architected Tool. Risk counts are keyed by risk level,
+// e.g. HIGH, MEDIUM, NONE, UNANSWERED and NOT_APPLICABLE.
+type ConsolidatedReport struct {
+	AWSWorkloadID string             `json:"aws_workload_id"`
+	WorkloadName  string             `json:"workload_name,omitempty"`
+	WorkloadARN   string             `json:"workload_arn,omitempty"`
+	UpdatedAt     *time.Time         `json:"updated_at,omitempty"`
+	RiskCounts    map[string]int32   `json:"risk_counts"`
+	Lenses        []ConsolidatedLens `json:"lenses"`
+}
+
+// ConsolidatedLens holds the metrics of one lens applied to the workload
+type ConsolidatedLens struct {
+	LensARN    string               `json:"lens_arn"`
+	RiskCounts map[string]int32     `json:"risk_counts"`
+	Pillars    []ConsolidatedPillar `json:"pillars"`
+}
+
+// ConsolidatedPillar holds the metrics of one pillar of a lens
+type ConsolidatedPillar struct {
+	PillarID   string                 `json:"pillar_id"`
+	RiskCounts map[string]int32       `json:"risk_counts"`
+	Questions  []ConsolidatedQuestion `json:"questions,omitempty"`
+}
+
+// ConsolidatedQuestion is the risk of one question and the best practices
+// that contribute to it
+type ConsolidatedQuestion struct {
+	QuestionID    string                     `json:"question_id"`
+	Risk          string                     `json:"risk"`
+	BestPractices []ConsolidatedBestPractice `json:"best_practices,omitempty"`
+}
+
+// ConsolidatedBestPractice is a best practice named in a consolidated report
+type ConsolidatedBestPractice struct {
+	ChoiceID    string `json:"choice_id"`
+	ChoiceTitle string `json:"choice_title"`
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Coverage output formats
+const (
+	CoverageFormatText = "text"
+	CoverageFormatJSON = "json"
+)
+
+// CoverageReport measures how much of a workload a review can reason about.
+// Resources with unresolved properties, no source location or no pillar
+// mapping give the evaluation little to go on.
+type CoverageReport struct {
+	TotalResources int `json:"total_resources"`
+	// Resolved counts resources with at least one property and no property
+	// left as an unevaluated expression such as "${var.env}"
+	Resolved int `json:"resolved"`
+	// WithSourceLocation counts resources with a source file and line
+	WithSourceLocation int `json:"with_source_location"`
+	// Mapped counts resources whose type is relevant to at least one pillar
+	Mapped   int `json:"mapped"`
+	Unmapped int `json:"unmapped"`
+	// ByPillar counts the resources relevant to each pillar. A resource may
+	// be relevant to several.
+	ByPillar map[Pillar]int `json:"by_pillar"`
+
+	// UnresolvedResources and MissingSource list resource addresses, and
+	// UnmappedTypes resource types, all sorted
+	UnresolvedResources []string `json:"unresolved_resources,omitempty"`
+	MissingSource       []string `json:"missing_source,omitempty"`
+	UnmappedTypes       []string `json:"unmapped_types,omitempty"`
+}
+
+// ComputeCoverage measures the coverage of the managed resources. Data
+// sources are left out as in the inventory. pillarsFor returns the pillars
+// a resource type is relevant to.
+func ComputeCoverage(resources []Resource, pillarsFor func(resourceType string) []Pillar) *CoverageReport {
+	report := &CoverageReport{ByPillar: make(map[Pillar]int)}
+	unmappedTypes := make(map[string]bool)
+
+	for _, resource := range resources {
+		if resource.IsDataSource() {
+			continue
+		}
+		report.TotalResources++
+
+		if len(resource.Properties) > 0 && !hasPlaceholder(resource.Properties) {
+			report.Resolved++
+		} else {
+			report.UnresolvedResources = append(report.UnresolvedResources, resource.Address)
+		}
+
+		if resource.SourceFile != "" && resource.SourceLine > 0 {
+			report.WithSourceLocation++
+		} else {
+			report.MissingSource = append(report.MissingSource, resource.Address)
+		}
+
+		pillars := pillarsFor(resource.Type)
+		if len(pillars) == 0 {
+			report.Unmapped++
+			unmappedTypes[resource.Type] = true
+			continue
+		}
+		report.Mapped++
+		for _, pillar := range pillars {
+			report.ByPillar[pillar]++
+		}
+	}
+
+	for resourceType := range unmappedTypes {
+		report.UnmappedTypes = append(report.UnmappedTypes, resourceType)
+	}
+	sort.Strings(report.UnresolvedResources)
+	sort.Strings(report.MissingSource)
+	sort.Strings(report.UnmappedTypes)
+	return report
+}
+
+// hasPlaceholder reports whether any value in v, including nested blocks, is
+// an expression the parser could not evaluate
+func hasPlaceholder(v interface{}) bool {
+	switch val := v.(type) {
+	case string:
+		return strings.Contains(val, "${")
+	case map[string]interface{}:
+		for _, nested := range val {
+			if hasPlaceholder(nested) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, nested := range val {
+			if hasPlaceholder(nested) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteCoverageText writes the report as a human-readable summary followed
+// by the resources and types behind each gap
+func WriteCoverageText(w io.Writer, report *CoverageReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Coverage of %d resources:\n", report.TotalResources)
+	fmt.Fprintf(&b, "  Resolved properties: %s\n", coverageRatio(report.Resolved, report.TotalResources))
+	fmt.Fprintf(&b, "  Source locations:    %s\n", coverageRatio(report.WithSourceLocation, report.TotalResources))
+	fmt.Fprintf(&b, "  Mapped to a pillar:  %s\n", coverageRatio(report.Mapped, report.TotalResources))
+	fmt.Fprintf(&b, "  Unmapped:            %s\n", coverageRatio(report.Unmapped, report.TotalResources))
+
+	if len(report.ByPillar) > 0 {
+		b.WriteString("\nResources by pillar:\n")
+		for _, pillar := range AllPillars() {
+			if count := report.ByPillar[pillar]; count > 0 {
+				fmt.Fprintf(&b, "  %s: %d\n", pillar, count)
+			}
+		}
+	}
+
+	writeCoverageList(&b, "Unresolved properties", report.UnresolvedResources)
+	writeCoverageList(&b, "No source location", report.MissingSource)
+	writeCoverageList(&b, "Unmapped resource types", report.UnmappedTypes)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// coverageRatio formats count as a share of total, e.g. "3/4 (75%)"
+func coverageRatio(count, total int) string {
+	percent := 0
+	if total > 0 {
+		percent = count * 100 / total
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", count, total, percent)
+}
+
+// writeCoverageList writes a titled list of entries, or nothing when empty
+func writeCoverageList(b *strings.Builder, title string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	for _, entry := range entries {
+		fmt.Fprintf(b, "  %s\n", entry)
+	}
+}
+
+// WriteCoverageJSON writes the report as indented JSON
+func WriteCoverageJSON(w io.Writer, report *CoverageReport) error {
+	return WriteJSON(w, report)
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"slices"
+	"sort"
+)
+
+// CoverageMatrix links each resource to the questions whose evidence cited
+// it. Resources that influenced no answer are often misparsed or irrelevant
+// to the review.
+type CoverageMatrix struct {
+	// Resources holds every resource of the workload model and every
+	// resource cited as evidence, sorted by address
+	Resources []ResourceCoverage
+	// Uncited lists the addresses of model resources no evidence cited,
+	// sorted. Data sources are left out.
+	Uncited []string
+}
+
+// ResourceCoverage holds the questions that cited one resource, sorted
+type ResourceCoverage struct {
+	Address   string
+	Questions []string
+}
+
+// BuildCoverageMatrix builds the matrix from the evidence of evaluations.
+// model may be nil, in which case only cited resources are listed.
+func BuildCoverageMatrix(evaluations []*QuestionEvaluation, model *WorkloadModel) *CoverageMatrix {
+	cited := make(map[string][]string)
+	if model != nil {
+		for _, resource := range model.Resources {
+			if !resource.IsDataSource() {
+				cited[resource.Address] = nil
+			}
+		}
+	}
+
+	for _, eval := range evaluations {
+		if eval == nil || eval.Question == nil {
+			continue
+		}
+		for _, evidence := range eval.Evidence {
+			for _, address := range evidence.Resources {
+				if !slices.Contains(cited[address], eval.Question.ID) {
+					cited[address] = append(cited[address], eval.Question.ID)
+				}
+			}
+		}
+	}
+
+	matrix := &CoverageMatrix{Resources: make([]ResourceCoverage, 0, len(cited))}
+	for address, questions := range cited {
+		sort.Strings(questions)
+		matrix.Resources = append(matrix.Resources, ResourceCoverage{Address: address, Questions: questions})
+		if len(questions) == 0 {
+			matrix.Uncited = append(matrix.Uncited, address)
+		}
+	}
+	sort.Slice(matrix.Resources, func(i, j int) bool {
+		return matrix.Resources[i].Address < matrix.Resources[j].Address
+	})
+	sort.Strings(matrix.Uncited)
+	return matrix
+}
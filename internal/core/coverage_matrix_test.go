@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCoverageMatrix(t *testing.T) {
+	model := &WorkloadModel{
+		Resources: []Resource{
+			{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+			{Address: "aws_kms_key.logs", Type: "aws_kms_key"},
+			{Address: "aws_sqs_queue.jobs", Type: "aws_sqs_queue"},
+			{Address: "data.aws_caller_identity.current", Type: "aws_caller_identity"},
+		},
+	}
+	evaluations := []*QuestionEvaluation{
+		{
+			Question: &WAFRQuestion{ID: "data-rest"},
+			Evidence: []Evidence{
+				{ChoiceID: "c1", Resources: []string{"aws_s3_bucket.logs", "aws_kms_key.logs"}},
+				// A resource cited twice by one question is listed once
+				{ChoiceID: "c2", Resources: []string{"aws_s3_bucket.logs"}},
+			},
+		},
+		{
+			Question: &WAFRQuestion{ID: "backing-up-data"},
+			Evidence: []Evidence{{Resources: []string{"aws_s3_bucket.logs", "module.backup.aws_backup_plan.main"}}},
+		},
+		{Question: &WAFRQuestion{ID: "network-protection"}},
+		nil,
+	}
+
+	matrix := BuildCoverageMatrix(evaluations, model)
+
+	assert.Equal(t, []ResourceCoverage{
+		{Address: "aws_kms_key.logs", Questions: []string{"data-rest"}},
+		{Address: "aws_s3_bucket.logs", Questions: []string{"backing-up-data", "data-rest"}},
+		{Address: "aws_sqs_queue.jobs"},
+		// Cited resources outside the model are still listed
+		{Address: "module.backup.aws_backup_plan.main", Questions: []string{"backing-up-data"}},
+	}, matrix.Resources)
+	assert.Equal(t, []string{"aws_sqs_queue.jobs"}, matrix.Uncited)
+}
+
+func TestBuildCoverageMatrix_NoModel(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		{Question: &WAFRQuestion{ID: "data-rest"}, Evidence: []Evidence{{Resources: []string{"aws_s3_bucket.logs"}}}},
+	}
+
+	matrix := BuildCoverageMatrix(evaluations, nil)
+
+	assert.Equal(t, []ResourceCoverage{{Address: "aws_s3_bucket.logs", Questions: []string{"data-rest"}}}, matrix.Resources)
+	assert.Empty(t, matrix.Uncited)
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCoveragePillars maps a few resource types to pillars
+func testCoveragePillars(resourceType string) []Pillar {
+	switch resourceType {
+	case "aws_s3_bucket":
+		return []Pillar{PillarSecurity, PillarCostOptimization}
+	case "aws_instance":
+		return []Pillar{PillarPerformanceEfficiency}
+	default:
+		return nil
+	}
+}
+
+func TestComputeCoverage(t *testing.T) {
+	resources := []Resource{
+		{
+			Address:    "aws_s3_bucket.logs",
+			Type:       "aws_s3_bucket",
+			Properties: map[string]interface{}{"bucket": "acme-logs", "password": "[REDACTED]"},
+			SourceFile: "main.tf",
+			SourceLine: 1,
+		},
+		{
+			Address: "aws_instance.web",
+			Type:    "aws_instance",
+			Properties: map[string]interface{}{
+				"instance_type": "t3.micro",
+				"root_block_device": []interface{}{
+					map[string]interface{}{"kms_key_id": "${aws_kms_key.main.arn}"},
+				},
+			},
+			SourceFile: "compute.tf",
+			SourceLine: 4,
+		},
+		{
+			Address:    "aws_db_instance.main",
+			Type:       "aws_db_instance",
+			Properties: map[string]interface{}{"identifier": "${var.env}-db"},
+			IsFromPlan: true,
+		},
+		{
+			Address:    "aws_sqs_queue.jobs",
+			Type:       "aws_sqs_queue",
+			SourceFile: "queues.tf",
+			SourceLine: 2,
+		},
+		{
+			Address:    "data.aws_ami.ubuntu",
+			Type:       "aws_ami",
+			Properties: map[string]interface{}{"most_recent": true},
+		},
+	}
+
+	report := ComputeCoverage(resources, testCoveragePillars)
+
+	assert.Equal(t, 4, report.TotalResources, "data sources are left out")
+	assert.Equal(t, 1, report.Resolved)
+	assert.Equal(t, 3, report.WithSourceLocation)
+	assert.Equal(t, 2, report.Mapped)
+	assert.Equal(t, 2, report.Unmapped)
+	assert.Equal(t, map[Pillar]int{
+		PillarSecurity:              1,
+		PillarCostOptimization:      1,
+		PillarPerformanceEfficiency: 1,
+	}, report.ByPillar)
+
+	// A nested placeholder and a resource without properties both count as
+	// unresolved; redacted values do not
+	assert.Equal(t, []string{"aws_db_instance.main", "aws_instance.web", "aws_sqs_queue.jobs"}, report.UnresolvedResources)
+	assert.Equal(t, []string{"aws_db_instance.main"}, report.MissingSource)
+	assert.Equal(t, []string{"aws_db_instance", "aws_sqs_queue"}, report.UnmappedTypes)
+}
+
+func TestComputeCoverage_Empty(t *testing.T) {
+	report := ComputeCoverage(nil, testCoveragePillars)
+
+	assert.Equal(t, 0, report.TotalResources)
+	assert.Empty(t, report.UnresolvedResources)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCoverageText(&buf, report))
+	assert.Equal(t, `Coverage of 0 resources:
+  Resolved properties: 0/0 (0%)
+  Source locations:    0/0 (0%)
+  Mapped to a pillar:  0/0 (0%)
+  Unmapped:            0/0 (0%)
+`, buf.String())
+}
+
+func TestWriteCoverageText(t *testing.T) {
+	report := &CoverageReport{
+		TotalResources:      3,
+		Resolved:            2,
+		WithSourceLocation:  3,
+		Mapped:              2,
+		Unmapped:            1,
+		ByPillar:            map[Pillar]int{PillarSecurity: 2, PillarReliability: 1},
+		UnresolvedResources: []string{"aws_instance.web"},
+		UnmappedTypes:       []string{"aws_sqs_queue"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCoverageText(&buf, report))
+	assert.Equal(t, `Coverage of 3 resources:
+  Resolved properties: 2/3 (66%)
+  Source locations:    3/3 (100%)
+  Mapped to a pillar:  2/3 (66%)
+  Unmapped:            1/3 (33%)
+
+Resources by pillar:
+  security: 2
+  reliability: 1
+
+Unresolved properties:
+  aws_instance.web
+
+Unmapped resource types:
+  aws_sqs_queue
+`, buf.String())
+}
+
+func TestWriteCoverageJSON(t *testing.T) {
+	report := ComputeCoverage([]Resource{{
+		Address:    "aws_s3_bucket.logs",
+		Type:       "aws_s3_bucket",
+		Properties: map[string]interface{}{"bucket": "acme-logs"},
+		SourceFile: "main.tf",
+		SourceLine: 1,
+	}}, testCoveragePillars)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCoverageJSON(&buf, report))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, float64(1), decoded["total_resources"])
+	assert.Equal(t, float64(1), decoded["resolved"])
+	assert.Equal(t, map[string]interface{}{"security": float64(1), "costOptimization": float64(1)}, decoded["by_pillar"])
+	assert.NotContains(t, decoded, "unresolved_resources")
+}
@@ -2,11 +2,17 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/metrics"
 )
 
 // Engine implements the CoreEngine interface
@@ -16,6 +22,43 @@ type Engine struct {
 	wafrEvaluator  WAFREvaluator
 	bedrockClient  BedrockClient
 	reportGen      ReportGenerator
+	riskThresholds RiskThresholds
+	description    WorkloadDescriptionOptions
+	lensVersion    string
+	allowEmpty     bool
+	cleanup        bool
+	maxQuestions   int
+	context        []ContextDocument
+	strict         bool
+	noMilestone    bool
+	milestoneName  MilestoneNameOptions
+	clock          Clock
+	sessionID      string
+
+	answerOverrides       AnswerOverrides
+	answerOverridesSource string
+
+	answerReviewer  AnswerReviewer
+	reviewThreshold float64
+
+	submissionConfirmer SubmissionConfirmer
+
+	questionCache    QuestionCache
+	questionCacheTTL time.Duration
+
+	questionCatalog       *QuestionCatalog
+	questionCatalogSource string
+
+	submitConcurrency int
+
+	fixedResourceOrder bool
+
+	preserveAnswers  bool
+	overwriteAnswers bool
+
+	advisoryPillars []Pillar
+
+	metrics *metrics.Metrics
 }
 
 // NewEngine creates a new core engine
@@ -29,12 +72,134 @@ func NewEngine(
 	return &Engine{
 		sessionManager: sessionManager,
 		iacAnalyzer:    iacAnalyzer,
+		clock:          SystemClock{},
 		wafrEvaluator:  wafrEvaluator,
 		bedrockClient:  bedrockClient,
 		reportGen:      reportGen,
+		riskThresholds: DefaultRiskThresholds(),
+		metrics:        metrics.Disabled(),
 	}
 }
 
+// SetClock replaces the clock that session timestamps, milestone names and
+// step timings are read from
+func (e *Engine) SetClock(clock Clock) {
+	e.clock = clock
+}
+
+// SetRiskThresholds overrides the confidence thresholds used to flag risks
+// and to bucket them into high and medium counts in the summary
+func (e *Engine) SetRiskThresholds(thresholds RiskThresholds) {
+	e.riskThresholds = thresholds
+}
+
+// SetWorkloadDescriptionOptions configures the template and provenance used to
+// build the AWS workload description
+func (e *Engine) SetWorkloadDescriptionOptions(options WorkloadDescriptionOptions) {
+	e.description = options
+}
+
+// SetPinnedLensVersion requires reviews to run against the given lens version.
+// An empty version accepts whatever version the workload uses.
+func (e *Engine) SetPinnedLensVersion(version string) {
+	e.lensVersion = version
+}
+
+// SetAllowEmptyWorkload lets reviews continue when IaC analysis finds no
+// resources. By default such reviews fail before any question is evaluated.
+func (e *Engine) SetAllowEmptyWorkload(allow bool) {
+	e.allowEmpty = allow
+}
+
+// SetCleanupWorkload deletes the AWS workload once a review completes, for
+// ephemeral workloads such as one per pull request. Only workloads created by
+// Waffle are deleted.
+func (e *Engine) SetCleanupWorkload(cleanup bool) {
+	e.cleanup = cleanup
+}
+
+// SetMaxQuestions limits evaluation to the first max questions in pillar
+// order. Reviews that skip questions are marked partial. Zero evaluates all
+// questions.
+func (e *Engine) SetMaxQuestions(max int) {
+	e.maxQuestions = max
+}
+
+// SetPreserveAnswers makes reviews read the answers the workload already has
+// before evaluating, so answers given by people are not replaced. Answered
+// questions are left out unless overwrite is set, in which case they are
+// evaluated again and their notes kept ahead of Waffle's.
+func (e *Engine) SetPreserveAnswers(overwrite bool) {
+	e.preserveAnswers = true
+	e.overwriteAnswers = overwrite
+}
+
+// SetSubmitConcurrency sets how many answers are submitted to AWS at once.
+// Values below one submit them one at a time.
+func (e *Engine) SetSubmitConcurrency(n int) {
+	e.submitConcurrency = n
+}
+
+// SetFixedResourceOrder sorts the resources of the workload model and the
+// dependencies of each by address, so the model is shown the same workload
+// however files and modules are laid out
+func (e *Engine) SetFixedResourceOrder(fixed bool) {
+	e.fixedResourceOrder = fixed
+}
+
+// SetAdvisoryPillars marks the summaries of pillars whose findings are
+// reported but not held to a baseline
+func (e *Engine) SetAdvisoryPillars(pillars []Pillar) {
+	e.advisoryPillars = pillars
+}
+
+// SetContextDocuments attaches supplementary documents to the workload model
+// of each review. Callers redact them before they are set.
+func (e *Engine) SetContextDocuments(documents []ContextDocument) {
+	e.context = documents
+}
+
+// SetStrict fails reviews whose IaC analysis recorded any warning, such as
+// redacted secrets or unparsed file content, before any question is evaluated
+func (e *Engine) SetStrict(strict bool) {
+	e.strict = strict
+}
+
+// SetCreateMilestone controls whether a review ends by creating a milestone
+// in the workload. Milestones are created by default.
+func (e *Engine) SetCreateMilestone(create bool) {
+	e.noMilestone = !create
+}
+
+// SetMilestoneNameOptions configures how the milestone created after a review
+// is named. The zero value uses DefaultMilestoneNameTemplate.
+func (e *Engine) SetMilestoneNameOptions(options MilestoneNameOptions) {
+	e.milestoneName = options
+}
+
+// SetAnswerOverrides forces the answers of the listed questions. Their
+// evaluations are built from the overrides without calling Bedrock, and the
+// notes name source as the origin of the answer.
+func (e *Engine) SetAnswerOverrides(overrides AnswerOverrides, source string) {
+	e.answerOverrides = overrides
+	e.answerOverridesSource = source
+}
+
+// SetSessionID creates the review session with a caller-supplied ID instead
+// of a generated one. The ID must not belong to an existing session.
+func (e *Engine) SetSessionID(sessionID string) {
+	e.sessionID = sessionID
+}
+
+// SetQuestionCatalog evaluates the questions of catalog instead of
+// retrieving them from AWS. source names the catalog, typically its file,
+// and is recorded on sessions that use it. The question cache is not used,
+// and the AWS workload is only created once answers are submitted.
+func (e *Engine) SetQuestionCatalog(catalog *QuestionCatalog, source string) {
+	e.questionCatalog = catalog
+	e.questionCatalogSource = source
+}
+
 // InitiateReview starts a new WAFR review session
 func (e *Engine) InitiateReview(
 	ctx context.Context,
@@ -55,21 +220,41 @@ func (e *Engine) InitiateReview(
 		return nil, fmt.Errorf("invalid scope: %w", err)
 	}
 
-	// Create AWS workload
-	slog.InfoContext(ctx, "creating AWS workload")
-	awsWorkloadID, err := e.wafrEvaluator.CreateWorkload(ctx, workloadID, "Automated WAFR review")
+	// Build the workload description; the resource count is not known yet
+	provenance := e.description.Provenance
+	provenance.WorkloadID = workloadID
+	description, err := RenderWorkloadDescription(e.description.Template, provenance)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AWS workload: %w", err)
+		return nil, err
+	}
+
+	// Check the milestone name now rather than after the review has run
+	if !e.noMilestone {
+		preflight := &ReviewSession{WorkloadID: workloadID}
+		if _, err := e.renderMilestoneName(preflight); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reviews of a question catalog create the workload once they need it,
+	// so questions are evaluated without reaching AWS
+	var awsWorkloadID, lensVersion string
+	if e.questionCatalog == nil {
+		awsWorkloadID, lensVersion, err = e.createWorkload(ctx, workloadID, description)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Create session
 	slog.InfoContext(ctx, "creating review session",
 		"aws_workload_id", awsWorkloadID,
 	)
-	session, err := e.sessionManager.CreateSession(ctx, workloadID, scope, awsWorkloadID)
+	session, err := e.sessionManager.CreateSession(ctx, workloadID, scope, awsWorkloadID, e.sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	session.LensVersion = lensVersion
 
 	// Save initial session state
 	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
@@ -84,6 +269,159 @@ func (e *Engine) InitiateReview(
 	return session, nil
 }
 
+// createWorkload creates, or reuses, the AWS workload of a review and returns
+// its ID and lens version
+func (e *Engine) createWorkload(ctx context.Context, workloadID, description string) (string, string, error) {
+	// A workload is created with the current lens version, so a pin it does
+	// not match fails before the workload is left behind in AWS
+	if err := e.checkCurrentLensVersion(ctx); err != nil {
+		return "", "", err
+	}
+
+	slog.InfoContext(ctx, "creating AWS workload")
+	awsWorkloadID, err := e.wafrEvaluator.CreateWorkload(ctx, workloadID, description)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create AWS workload: %w", err)
+	}
+
+	lensVersion, err := e.resolveLensVersion(ctx, awsWorkloadID)
+	if err != nil {
+		return "", "", err
+	}
+	return awsWorkloadID, lensVersion, nil
+}
+
+// ensureWorkload creates the AWS workload of a session that has none yet, a
+// review of a question catalog about to call the Well-Architected Tool
+func (e *Engine) ensureWorkload(ctx context.Context, session *ReviewSession) error {
+	if session.AWSWorkloadID != "" {
+		return nil
+	}
+
+	provenance := e.description.Provenance
+	provenance.WorkloadID = session.WorkloadID
+	if session.WorkloadModel != nil {
+		provenance.ResourceCount = len(session.WorkloadModel.Resources)
+	}
+	description, err := RenderWorkloadDescription(e.description.Template, provenance)
+	if err != nil {
+		return err
+	}
+
+	awsWorkloadID, lensVersion, err := e.createWorkload(ctx, session.WorkloadID, description)
+	if err != nil {
+		return err
+	}
+	session.AWSWorkloadID = awsWorkloadID
+	session.LensVersion = lensVersion
+	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// checkCatalogLensVersion fails when the question catalog the session was
+// evaluated from is for another lens version than the workload, since its
+// choice IDs are submitted to the workload's lens. The catalog's version is
+// read from the session, as a resumed review has no catalog set. A version
+// that is not known is only warned about.
+func (e *Engine) checkCatalogLensVersion(ctx context.Context, session *ReviewSession) error {
+	if session.QuestionCatalog == "" {
+		return nil
+	}
+
+	catalogVersion := session.QuestionCatalogLensVersion
+	if catalogVersion == "" || session.LensVersion == "" {
+		slog.WarnContext(ctx, "cannot check that the question catalog matches the workload's lens version",
+			"source", session.QuestionCatalog,
+			"catalog_lens_version", catalogVersion,
+			"lens_version", session.LensVersion,
+		)
+		return nil
+	}
+	if catalogVersion != session.LensVersion {
+		return fmt.Errorf("%w: question catalog %s is for %s, workload uses %s", ErrLensVersionMismatch, session.QuestionCatalog, catalogVersion, session.LensVersion)
+	}
+	return nil
+}
+
+// checkCurrentLensVersion checks the pinned lens version against the version
+// new workloads are created with. A reused workload may still be on another
+// version, which resolveLensVersion reports once it is known.
+func (e *Engine) checkCurrentLensVersion(ctx context.Context) error {
+	if e.lensVersion == "" {
+		return nil
+	}
+	provider, ok := e.wafrEvaluator.(LensVersionProvider)
+	if !ok {
+		return fmt.Errorf("%w: lens version %s is pinned but cannot be verified", ErrLensVersionMismatch, e.lensVersion)
+	}
+
+	version, err := provider.GetCurrentLensVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify pinned lens version: %w", err)
+	}
+	if version != e.lensVersion {
+		return fmt.Errorf("%w: new workloads use %s, pinned %s", ErrLensVersionMismatch, version, e.lensVersion)
+	}
+	return nil
+}
+
+// resolveLensVersion captures the lens version of the workload and checks it
+// against the pinned version. Without a pinned version, failing to read the
+// version is not fatal.
+func (e *Engine) resolveLensVersion(ctx context.Context, awsWorkloadID string) (string, error) {
+	// checkCurrentLensVersion has failed a pin that cannot be verified
+	provider, ok := e.wafrEvaluator.(LensVersionProvider)
+	if !ok {
+		return "", nil
+	}
+
+	version, err := provider.GetLensVersion(ctx, awsWorkloadID)
+	if err != nil {
+		if e.lensVersion != "" {
+			return "", fmt.Errorf("failed to verify pinned lens version: %w", err)
+		}
+		slog.WarnContext(ctx, "failed to capture lens version",
+			"aws_workload_id", awsWorkloadID,
+			"error", err,
+		)
+		return "", nil
+	}
+
+	if e.lensVersion != "" && version != e.lensVersion {
+		return "", fmt.Errorf("%w: workload uses %s, pinned %s", ErrLensVersionMismatch, version, e.lensVersion)
+	}
+
+	slog.InfoContext(ctx, "lens version captured",
+		"aws_workload_id", awsWorkloadID,
+		"lens_version", version,
+	)
+	return version, nil
+}
+
+// SetAnswerReviewer routes evaluations with a confidence score below
+// threshold through reviewer before their answers are submitted
+func (e *Engine) SetAnswerReviewer(reviewer AnswerReviewer, threshold float64) {
+	e.answerReviewer = reviewer
+	e.reviewThreshold = threshold
+}
+
+// SetSubmissionConfirmer has confirmer approve all proposed answers, after
+// any interactive review, before the first one is submitted
+func (e *Engine) SetSubmissionConfirmer(confirmer SubmissionConfirmer) {
+	e.submissionConfirmer = confirmer
+}
+
+// SetMetrics records review durations and evaluated questions on m.
+// Passing nil disables engine metrics.
+func (e *Engine) SetMetrics(m *metrics.Metrics) {
+	if m == nil {
+		m = metrics.Disabled()
+	}
+	e.metrics = m
+}
+
 // ExecuteReview executes the review workflow
 func (e *Engine) ExecuteReview(ctx context.Context, session *ReviewSession) (*ReviewResults, error) {
 	return e.ExecuteReviewWithProgress(ctx, session, nil)
@@ -95,10 +433,11 @@ func (e *Engine) ExecuteReviewWithProgress(ctx context.Context, session *ReviewS
 		"session_id", session.SessionID,
 		"workload_id", session.WorkloadID,
 	)
+	startedAt := e.clock.Now()
 
 	// Update session status to in progress
 	session.Status = SessionStatusInProgress
-	session.UpdatedAt = time.Now()
+	session.UpdatedAt = e.clock.Now()
 	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 		return nil, fmt.Errorf("failed to update session status: %w", err)
 	}
@@ -107,7 +446,7 @@ func (e *Engine) ExecuteReviewWithProgress(ctx context.Context, session *ReviewS
 	results, err := e.executeWorkflowWithProgress(ctx, session, progress)
 	if err != nil {
 		session.Status = SessionStatusFailed
-		session.UpdatedAt = time.Now()
+		session.UpdatedAt = e.clock.Now()
 		if saveErr := e.sessionManager.SaveSession(ctx, session); saveErr != nil {
 			slog.ErrorContext(ctx, "failed to save failed session state",
 				"session_id", session.SessionID,
@@ -120,11 +459,17 @@ func (e *Engine) ExecuteReviewWithProgress(ctx context.Context, session *ReviewS
 	// Update session with results
 	session.Results = results
 	session.Status = SessionStatusCompleted
-	session.UpdatedAt = time.Now()
+	session.UpdatedAt = e.clock.Now()
 	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 		return nil, fmt.Errorf("failed to save completed session: %w", err)
 	}
 
+	e.metrics.ReviewDuration.Observe(e.clock.Now().Sub(startedAt).Seconds())
+
+	if e.cleanup {
+		e.deleteWorkload(ctx, session)
+	}
+
 	slog.InfoContext(ctx, "review execution completed",
 		"session_id", session.SessionID,
 		"questions_evaluated", len(results.Evaluations),
@@ -139,6 +484,34 @@ func (e *Engine) ExecuteReviewWithProgress(ctx context.Context, session *ReviewS
 	return results, nil
 }
 
+// deleteWorkload removes the AWS workload of a completed review. Failures are
+// logged and do not fail the review.
+func (e *Engine) deleteWorkload(ctx context.Context, session *ReviewSession) {
+	deleter, ok := e.wafrEvaluator.(WorkloadDeleter)
+	if !ok {
+		slog.WarnContext(ctx, "workload cleanup is not supported by the WAFR evaluator")
+		return
+	}
+
+	if err := deleter.DeleteWorkload(ctx, session.AWSWorkloadID); err != nil {
+		slog.WarnContext(ctx, "failed to delete workload after review",
+			"aws_workload_id", session.AWSWorkloadID,
+			"error", err,
+		)
+		return
+	}
+
+	session.WorkloadDeleted = true
+	session.UpdatedAt = e.clock.Now()
+	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
+		slog.WarnContext(ctx, "failed to save session after workload cleanup",
+			"session_id", session.SessionID,
+			"error", err,
+		)
+	}
+	slog.InfoContext(ctx, "workload deleted after review", "aws_workload_id", session.AWSWorkloadID)
+}
+
 // executeWorkflow executes the main workflow with checkpoint support
 func (e *Engine) executeWorkflow(ctx context.Context, session *ReviewSession) (*ReviewResults, error) {
 	return e.executeWorkflowWithProgress(ctx, session, nil)
@@ -146,15 +519,20 @@ func (e *Engine) executeWorkflow(ctx context.Context, session *ReviewSession) (*
 
 // executeWorkflowWithProgress executes the main workflow with checkpoint support and progress reporting
 func (e *Engine) executeWorkflowWithProgress(ctx context.Context, session *ReviewSession, progress ProgressReporter) (*ReviewResults, error) {
+	timer := newStepTimer(e.clock.Now)
+
 	// Step 1: IaC Analysis (checkpoint: iac_analysis_complete)
 	if session.Checkpoint == "" || session.Checkpoint == "created" {
 		slog.InfoContext(ctx, "step 1: analyzing IaC")
 		if progress != nil {
-			progress.ReportStep("iac_analysis", "Analyzing infrastructure-as-code files...")
+			progress.ReportStep(StepIaCAnalysis, "Analyzing infrastructure-as-code files...")
 		}
+		done := timer.begin(StepIaCAnalysis)
 		if err := e.analyzeIaC(ctx, session); err != nil {
 			return nil, fmt.Errorf("IaC analysis failed: %w", err)
 		}
+		e.refreshWorkloadDescription(ctx, session)
+		done()
 		session.Checkpoint = "iac_analysis_complete"
 		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
@@ -166,14 +544,67 @@ func (e *Engine) executeWorkflowWithProgress(ctx context.Context, session *Revie
 	if session.Checkpoint == "iac_analysis_complete" {
 		slog.InfoContext(ctx, "step 2: retrieving WAFR questions")
 		if progress != nil {
-			progress.ReportStep("retrieve_questions", "Retrieving WAFR questions from AWS...")
+			message := "Retrieving WAFR questions from AWS..."
+			if e.questionCatalog != nil {
+				message = "Reading WAFR questions from the question catalog..."
+			}
+			progress.ReportStep(StepRetrieveQuestions, message)
 		}
-		var err error
-		questions, err = e.wafrEvaluator.GetQuestions(ctx, session.AWSWorkloadID, session.Scope)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get questions: %w", err)
+		done := timer.begin(StepRetrieveQuestions)
+		if e.questionCatalog != nil {
+			questions = e.questionCatalog.QuestionsInScope(session.Scope)
+			if len(questions) == 0 {
+				return nil, fmt.Errorf("question catalog %s has no questions in the review scope", e.questionCatalogSource)
+			}
+			session.QuestionCatalog = e.questionCatalogSource
+			session.QuestionCatalogLensVersion = e.questionCatalog.LensVersion
+			slog.InfoContext(ctx, "using static question catalog",
+				"source", e.questionCatalogSource,
+				"catalog_lens_version", e.questionCatalog.LensVersion,
+			)
+		} else {
+			questions = e.cachedQuestions(ctx, session)
 		}
+		if questions == nil {
+			var err error
+			questions, err = e.wafrEvaluator.GetQuestions(ctx, session.AWSWorkloadID, session.Scope)
+			var pillarErr *PillarRetrievalError
+			if errors.As(err, &pillarErr) && len(questions) > 0 {
+				// Continue with the pillars that were retrieved
+				slog.WarnContext(ctx, "continuing review without some pillars", "error", err)
+				session.FailedPillars = make(map[Pillar]string, len(pillarErr.Failures))
+				for pillar, pErr := range pillarErr.Failures {
+					session.FailedPillars[pillar] = pErr.Error()
+				}
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to get questions: %w", err)
+			} else {
+				e.cacheQuestions(ctx, session, questions)
+			}
+		}
+		// Pagination decides the order questions are retrieved in; evaluate
+		// them in one that does not change from run to run
+		questions = sortQuestions(questions)
 		slog.InfoContext(ctx, "retrieved questions", "count", len(questions))
+		if e.preserveAnswers {
+			if err := e.ensureWorkload(ctx, session); err != nil {
+				return nil, err
+			}
+			var err error
+			questions, err = e.applyExistingAnswers(ctx, session, questions)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if e.maxQuestions > 0 && len(questions) > e.maxQuestions {
+			session.QuestionsSkipped = len(questions) - e.maxQuestions
+			questions = limitQuestions(questions, e.maxQuestions)
+			slog.WarnContext(ctx, "question cap reached, review will be partial",
+				"max_questions", e.maxQuestions,
+				"questions_skipped", session.QuestionsSkipped,
+			)
+		}
+		done()
 		if progress != nil {
 			progress.ReportProgress(len(questions), len(questions), fmt.Sprintf("Retrieved %d questions", len(questions)))
 		}
@@ -185,31 +616,76 @@ func (e *Engine) executeWorkflowWithProgress(ctx context.Context, session *Revie
 
 	// Step 3: Evaluate questions (checkpoint: questions_evaluated)
 	var evaluations []*QuestionEvaluation
+	// A rewound session evaluates the questions of its earlier run again
+	if session.Checkpoint == "questions_retrieved" && questions == nil {
+		questions = savedQuestions(session)
+	}
+	// When every question keeps its existing answer there is nothing to
+	// evaluate or submit, and the review goes on to the improvement plan
+	if session.Checkpoint == "questions_retrieved" && len(questions) == 0 && len(session.PreservedQuestions) > 0 {
+		slog.InfoContext(ctx, "all questions already answered, skipping evaluation and submission",
+			"preserved", len(session.PreservedQuestions),
+		)
+		evaluations = []*QuestionEvaluation{}
+		if session.Results == nil {
+			session.Results = &ReviewResults{}
+		}
+		session.Results.Evaluations = evaluations
+		session.Checkpoint = "answers_submitted"
+		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
+			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
 	if session.Checkpoint == "questions_retrieved" {
 		slog.InfoContext(ctx, "step 3: evaluating questions")
 		if progress != nil {
-			progress.ReportStep("evaluate_questions", "Evaluating questions using Bedrock...")
+			progress.ReportStep(StepEvaluateQuestions, "Evaluating questions using Bedrock...")
 		}
+		done := timer.begin(StepEvaluateQuestions)
 		var err error
 		evaluations, err = e.evaluateQuestionsWithProgress(ctx, session, questions, progress)
 		if err != nil {
 			return nil, fmt.Errorf("question evaluation failed: %w", err)
 		}
+		done()
+		// Save the evaluations with the checkpoint, so a session whose
+		// submission was aborted or failed submits them when resumed
+		if session.Results == nil {
+			session.Results = &ReviewResults{}
+		}
+		session.Results.Evaluations = evaluations
 		session.Checkpoint = "questions_evaluated"
 		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
 		}
 	}
 
+	// Steps after a rewind use the saved results of the earlier run
+	if evaluations == nil && session.Results != nil {
+		evaluations = session.Results.Evaluations
+	}
+
 	// Step 4: Submit answers (checkpoint: answers_submitted)
 	if session.Checkpoint == "questions_evaluated" {
+		// Sessions checkpointed before evaluations were saved cannot submit
+		if evaluations == nil {
+			return nil, fmt.Errorf("answer submission failed: session %s has no saved evaluations to submit", session.SessionID)
+		}
+		if err := e.ensureWorkload(ctx, session); err != nil {
+			return nil, fmt.Errorf("answer submission failed: %w", err)
+		}
+		if err := e.checkCatalogLensVersion(ctx, session); err != nil {
+			return nil, fmt.Errorf("answer submission failed: %w", err)
+		}
 		slog.InfoContext(ctx, "step 4: submitting answers to AWS")
 		if progress != nil {
-			progress.ReportStep("submit_answers", "Submitting answers to AWS Well-Architected Tool...")
+			progress.ReportStep(StepSubmitAnswers, "Submitting answers to AWS Well-Architected Tool...")
 		}
+		done := timer.begin(StepSubmitAnswers)
 		if err := e.submitAnswersWithProgress(ctx, session, evaluations, progress); err != nil {
 			return nil, fmt.Errorf("answer submission failed: %w", err)
 		}
+		done()
 		session.Checkpoint = "answers_submitted"
 		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
@@ -221,8 +697,9 @@ func (e *Engine) executeWorkflowWithProgress(ctx context.Context, session *Revie
 	if session.Checkpoint == "answers_submitted" {
 		slog.InfoContext(ctx, "step 5: retrieving improvement plan")
 		if progress != nil {
-			progress.ReportStep("improvement_plan", "Retrieving improvement plan from AWS...")
+			progress.ReportStep(StepImprovementPlan, "Retrieving improvement plan from AWS...")
 		}
+		done := timer.begin(StepImprovementPlan)
 		var err error
 		improvementPlan, err = e.wafrEvaluator.GetImprovementPlan(ctx, session.AWSWorkloadID)
 		if err != nil {
@@ -231,21 +708,46 @@ func (e *Engine) executeWorkflowWithProgress(ctx context.Context, session *Revie
 			)
 			// Continue with empty improvement plan
 			improvementPlan = &ImprovementPlan{Items: []*ImprovementPlanItem{}}
+			session.ImprovementPlanError = err.Error()
+		} else {
+			session.ImprovementPlanError = ""
+		}
+		done()
+		if session.Results == nil {
+			session.Results = &ReviewResults{}
 		}
+		session.Results.ImprovementPlan = improvementPlan
 		session.Checkpoint = "improvement_plan_retrieved"
 		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
 		}
 	}
 
-	// Step 6: Create milestone (checkpoint: milestone_created)
+	if improvementPlan == nil && session.Results != nil {
+		improvementPlan = session.Results.ImprovementPlan
+	}
+
+	// Step 6: Create milestone (checkpoint: milestone_created, or
+	// milestone_skipped when milestones are turned off)
+	if session.Checkpoint == "improvement_plan_retrieved" && e.noMilestone {
+		slog.InfoContext(ctx, "step 6: skipping milestone creation")
+		session.MilestoneSkipped = true
+		session.Checkpoint = "milestone_skipped"
+		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
+			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
 	if session.Checkpoint == "improvement_plan_retrieved" {
 		slog.InfoContext(ctx, "step 6: creating milestone")
 		if progress != nil {
-			progress.ReportStep("create_milestone", "Creating milestone in AWS...")
+			progress.ReportStep(StepCreateMilestone, "Creating milestone in AWS...")
+		}
+		done := timer.begin(StepCreateMilestone)
+		milestoneName, err := e.renderMilestoneName(session)
+		var milestoneID string
+		if err == nil {
+			milestoneID, err = e.wafrEvaluator.CreateMilestone(ctx, session.AWSWorkloadID, milestoneName)
 		}
-		milestoneName := fmt.Sprintf("waffle-%s", time.Now().Format("2006-01-02-15-04-05"))
-		milestoneID, err := e.wafrEvaluator.CreateMilestone(ctx, session.AWSWorkloadID, milestoneName)
 		if err != nil {
 			slog.WarnContext(ctx, "failed to create milestone, continuing",
 				"error", err,
@@ -254,23 +756,94 @@ func (e *Engine) executeWorkflowWithProgress(ctx context.Context, session *Revie
 			session.MilestoneID = milestoneID
 			slog.InfoContext(ctx, "milestone created", "milestone_id", milestoneID)
 		}
+		done()
 		session.Checkpoint = "milestone_created"
 		if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
 		}
 	}
 
-	// Build results
+	// Build results. Suppressions are applied first so suppressed
+	// evaluations are left out of the summary's risk counts.
+	risks := mergeResourceHints(e.extractRisks(evaluations), evaluations, session.WorkloadModel)
+	risks, suppressions := applySuppressions(evaluations, risks, session.WorkloadModel)
+	for _, suppression := range suppressions {
+		slog.InfoContext(ctx, "risk suppressed by resource annotation",
+			"question_id", suppression.QuestionID,
+			"resource", suppression.Resource,
+			"reason", suppression.Reason,
+		)
+	}
 	results := &ReviewResults{
 		Evaluations:     evaluations,
-		Risks:           e.extractRisks(evaluations),
+		Risks:           risks,
 		ImprovementPlan: improvementPlan,
 		Summary:         e.buildSummary(evaluations, improvementPlan),
+		Timings:         timer.finish(),
+		Suppressions:    suppressions,
+	}
+	if session.QuestionsSkipped > 0 {
+		results.Summary.Partial = true
+		results.Summary.QuestionsSkipped = session.QuestionsSkipped
+		results.Summary.TotalQuestions += session.QuestionsSkipped
+	}
+	if len(session.PreservedQuestions) > 0 {
+		results.Summary.PreservedQuestions = session.PreservedQuestions
+		results.Summary.TotalQuestions += len(session.PreservedQuestions)
 	}
 
 	return results, nil
 }
 
+// renderMilestoneName returns the name of the milestone recorded for session:
+// the explicit name when one is set, otherwise the rendered template
+func (e *Engine) renderMilestoneName(session *ReviewSession) (string, error) {
+	if e.milestoneName.Name != "" {
+		if err := ValidateMilestoneName(e.milestoneName.Name); err != nil {
+			return "", err
+		}
+		return e.milestoneName.Name, nil
+	}
+
+	now := e.clock.Now()
+	return RenderMilestoneName(e.milestoneName.Template, MilestoneNameData{
+		WorkloadID: session.WorkloadID,
+		SessionID:  session.SessionID,
+		GitRef:     e.description.Provenance.GitRef,
+		GitSHA:     e.description.Provenance.GitSHA,
+		Timestamp:  now.Format(MilestoneTimestampFormat),
+		Time:       now,
+	})
+}
+
+// sortQuestions returns the questions ordered by pillar, in framework order,
+// and then by question ID
+func sortQuestions(questions []*WAFRQuestion) []*WAFRQuestion {
+	pillarOrder := make(map[Pillar]int)
+	for i, pillar := range AllPillars() {
+		pillarOrder[pillar] = i
+	}
+
+	ordered := make([]*WAFRQuestion, len(questions))
+	copy(ordered, questions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Pillar != ordered[j].Pillar {
+			return pillarOrder[ordered[i].Pillar] < pillarOrder[ordered[j].Pillar]
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+	return ordered
+}
+
+// limitQuestions returns the first max questions in sortQuestions order
+func limitQuestions(questions []*WAFRQuestion, max int) []*WAFRQuestion {
+	ordered := sortQuestions(questions)
+	if len(ordered) > max {
+		ordered = ordered[:max]
+	}
+	return ordered
+}
+
 // analyzeIaC performs IaC analysis
 func (e *Engine) analyzeIaC(ctx context.Context, session *ReviewSession) error {
 	// Retrieve IaC files
@@ -293,6 +866,19 @@ func (e *Engine) analyzeIaC(ctx context.Context, session *ReviewSession) error {
 		if err != nil {
 			return fmt.Errorf("failed to parse terraform JSON file: %w", err)
 		}
+
+		if session.RequireFreshPlan {
+			if err := e.checkPlanFreshness(ctx, files, workloadModel, session.PlanFilePath); err != nil {
+				return err
+			}
+		}
+
+		// Drift reporting needs the declared values from the configuration.
+		// Only the drift is kept, so the questions are evaluated on the
+		// same plan model with or without it.
+		if session.ReportDrift {
+			workloadModel.Drift = e.configurationDrift(ctx, files, workloadModel)
+		}
 	} else {
 		// Default: analyze Terraform configuration files
 		slog.InfoContext(ctx, "analyzing terraform configuration files")
@@ -302,20 +888,59 @@ func (e *Engine) analyzeIaC(ctx context.Context, session *ReviewSession) error {
 		}
 	}
 
+	if err := e.checkStrict(ctx); err != nil {
+		return err
+	}
+
 	// Extract resources
 	resources, err := e.iacAnalyzer.ExtractResources(ctx, workloadModel)
 	if err != nil {
 		return fmt.Errorf("failed to extract resources: %w", err)
 	}
 
+	// Evaluating an empty model spends Bedrock tokens on answers that can
+	// only have zero confidence
+	if len(resources) == 0 {
+		if !e.allowEmpty {
+			source := "terraform configuration"
+			if session.PlanFilePath != "" {
+				source = session.PlanFilePath
+			}
+			return &IaCParsingError{
+				File:    source,
+				Err:     ErrEmptyWorkloadModel,
+				Context: fmt.Sprintf("%d files analyzed", len(files)),
+			}
+		}
+		slog.WarnContext(ctx, "continuing review of workload with no resources")
+	}
+
+	// Sort before relationships are identified, since the graph points
+	// into the resource slice
+	if e.fixedResourceOrder {
+		sort.SliceStable(resources, func(i, j int) bool { return resources[i].Address < resources[j].Address })
+	}
+
 	// Identify relationships
 	relationships, err := e.iacAnalyzer.IdentifyRelationships(ctx, resources)
 	if err != nil {
 		return fmt.Errorf("failed to identify relationships: %w", err)
 	}
+	if e.fixedResourceOrder && relationships != nil {
+		for _, dependencies := range relationships.Edges {
+			sort.Strings(dependencies)
+		}
+	}
 
 	workloadModel.Resources = resources
 	workloadModel.Relationships = relationships
+	workloadModel.Context = e.context
+	if detector, ok := e.iacAnalyzer.(PublicExposureDetector); ok {
+		workloadModel.PublicExposure = detector.DetectPublicExposure(resources, relationships)
+		if len(workloadModel.PublicExposure) > 0 {
+			slog.InfoContext(ctx, "public exposure detected", "exposed_resources", len(workloadModel.PublicExposure))
+		}
+	}
 
 	session.WorkloadModel = workloadModel
 	slog.InfoContext(ctx, "IaC analysis complete",
@@ -332,6 +957,102 @@ func (e *Engine) analyzeIaC(ctx context.Context, session *ReviewSession) error {
 	return nil
 }
 
+// checkStrict returns a StrictModeError in strict mode when the analyzer
+// recorded warnings. Analyzers that do not report warnings always pass.
+func (e *Engine) checkStrict(ctx context.Context) error {
+	if !e.strict {
+		return nil
+	}
+	reporter, ok := e.iacAnalyzer.(AnalysisWarningReporter)
+	if !ok {
+		return nil
+	}
+
+	warnings := reporter.AnalysisWarnings()
+	if len(warnings) == 0 {
+		return nil
+	}
+	slog.WarnContext(ctx, "strict mode rejected IaC analysis", "warnings", len(warnings))
+	return &StrictModeError{Warnings: warnings}
+}
+
+// checkPlanFreshness merges the Terraform configuration with the plan model
+// and returns an IaCParsingError wrapping ErrStalePlan when configuration
+// resources are missing from the plan. The plan model is not changed.
+func (e *Engine) checkPlanFreshness(ctx context.Context, files []IaCFile, planModel *WorkloadModel, planFile string) error {
+	configModel, err := e.iacAnalyzer.ParseTerraform(ctx, files)
+	if err != nil {
+		return fmt.Errorf("failed to parse terraform configuration to check the plan is fresh: %w", err)
+	}
+	merged, err := e.iacAnalyzer.MergeWorkloadModels(ctx, planModel, configModel)
+	if err != nil {
+		return fmt.Errorf("failed to compare terraform configuration with the plan: %w", err)
+	}
+
+	if len(merged.MissingFromPlan) > 0 {
+		slog.WarnContext(ctx, "plan file is stale", "missing_from_plan", merged.MissingFromPlan)
+		return &IaCParsingError{
+			File:    planFile,
+			Err:     ErrStalePlan,
+			Context: fmt.Sprintf("not in the plan: %s", strings.Join(merged.MissingFromPlan, ", ")),
+		}
+	}
+	return nil
+}
+
+// configurationDrift parses the Terraform configuration and merges it with
+// the plan model to find the property drift between the two. The merged model
+// itself is discarded. No drift is returned when the configuration cannot be
+// used.
+func (e *Engine) configurationDrift(ctx context.Context, files []IaCFile, planModel *WorkloadModel) []PropertyDrift {
+	configModel, err := e.iacAnalyzer.ParseTerraform(ctx, files)
+	if err != nil {
+		slog.WarnContext(ctx, "drift detection skipped, failed to parse terraform configuration",
+			"error", err,
+		)
+		return nil
+	}
+
+	merged, err := e.iacAnalyzer.MergeWorkloadModels(ctx, planModel, configModel)
+	if err != nil {
+		slog.WarnContext(ctx, "drift detection skipped, failed to merge workload models",
+			"error", err,
+		)
+		return nil
+	}
+
+	slog.InfoContext(ctx, "drift detection complete", "property_drift", len(merged.Drift))
+	return merged.Drift
+}
+
+// refreshWorkloadDescription updates the AWS workload description with the
+// analyzed resource count. Failures are logged and do not stop the review.
+func (e *Engine) refreshWorkloadDescription(ctx context.Context, session *ReviewSession) {
+	if !e.description.UpdateExisting || session.WorkloadModel == nil || session.AWSWorkloadID == "" {
+		return
+	}
+
+	updater, ok := e.wafrEvaluator.(WorkloadDescriptionUpdater)
+	if !ok {
+		return
+	}
+
+	provenance := e.description.Provenance
+	provenance.WorkloadID = session.WorkloadID
+	provenance.ResourceCount = len(session.WorkloadModel.Resources)
+
+	description, err := RenderWorkloadDescription(e.description.Template, provenance)
+	if err == nil {
+		err = updater.UpdateWorkloadDescription(ctx, session.AWSWorkloadID, description)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to update workload description",
+			"aws_workload_id", session.AWSWorkloadID,
+			"error", err,
+		)
+	}
+}
+
 // evaluateQuestions evaluates all questions
 func (e *Engine) evaluateQuestions(ctx context.Context, session *ReviewSession, questions []*WAFRQuestion) ([]*QuestionEvaluation, error) {
 	return e.evaluateQuestionsWithProgress(ctx, session, questions, nil)
@@ -340,6 +1061,7 @@ func (e *Engine) evaluateQuestions(ctx context.Context, session *ReviewSession,
 // evaluateQuestionsWithProgress evaluates all questions with progress reporting
 func (e *Engine) evaluateQuestionsWithProgress(ctx context.Context, session *ReviewSession, questions []*WAFRQuestion, progress ProgressReporter) ([]*QuestionEvaluation, error) {
 	evaluations := make([]*QuestionEvaluation, 0, len(questions))
+	e.warnUnmatchedOverrides(ctx, questions)
 
 	for i, question := range questions {
 		// Only log detailed progress when no progress reporter is active
@@ -354,6 +1076,19 @@ func (e *Engine) evaluateQuestionsWithProgress(ctx context.Context, session *Rev
 			progress.ReportProgress(i+1, len(questions), fmt.Sprintf("Evaluating question %d of %d", i+1, len(questions)))
 		}
 
+		if override, ok := e.answerOverrides[question.ID]; ok {
+			evaluation, err := override.Evaluation(question, e.answerOverridesSource)
+			if err != nil {
+				return nil, err
+			}
+			slog.InfoContext(ctx, "answer overridden, skipping evaluation",
+				"question_id", question.ID,
+				"choices", override.Choices,
+			)
+			evaluations = append(evaluations, evaluation)
+			continue
+		}
+
 		evaluation, err := e.wafrEvaluator.EvaluateQuestion(ctx, question, session.WorkloadModel)
 		if err != nil {
 			slog.ErrorContext(ctx, "failed to evaluate question, continuing",
@@ -365,6 +1100,7 @@ func (e *Engine) evaluateQuestionsWithProgress(ctx context.Context, session *Rev
 		}
 
 		evaluations = append(evaluations, evaluation)
+		e.metrics.QuestionsEvaluated.Inc(string(question.Pillar))
 
 		// Log successful evaluation at debug level
 		slog.DebugContext(ctx, "question evaluated",
@@ -381,17 +1117,64 @@ func (e *Engine) evaluateQuestionsWithProgress(ctx context.Context, session *Rev
 	return evaluations, nil
 }
 
+// warnUnmatchedOverrides logs answer overrides for questions outside the
+// review, which usually means a mistyped question ID
+func (e *Engine) warnUnmatchedOverrides(ctx context.Context, questions []*WAFRQuestion) {
+	if len(e.answerOverrides) == 0 {
+		return
+	}
+	inReview := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		inReview[question.ID] = true
+	}
+	for _, questionID := range e.answerOverrides.QuestionIDs() {
+		if !inReview[questionID] {
+			slog.WarnContext(ctx, "answer override matches no question in the review", "question_id", questionID)
+		}
+	}
+}
+
 // submitAnswers submits all answers to AWS
 func (e *Engine) submitAnswers(ctx context.Context, session *ReviewSession, evaluations []*QuestionEvaluation) error {
 	return e.submitAnswersWithProgress(ctx, session, evaluations, nil)
 }
 
-// submitAnswersWithProgress submits all answers to AWS with progress reporting
+// submitAnswersWithProgress submits all answers to AWS with progress
+// reporting. Interactive reviews run first, one question at a time; the
+// answers are then submitted by up to submitConcurrency workers.
 func (e *Engine) submitAnswersWithProgress(ctx context.Context, session *ReviewSession, evaluations []*QuestionEvaluation, progress ProgressReporter) error {
-	successCount := 0
-	errorCount := 0
+	for _, evaluation := range evaluations {
+		if evaluation.Question != nil && evaluation.ExistingNotes == "" {
+			evaluation.ExistingNotes = session.ExistingNotes[evaluation.Question.ID]
+		}
+		if err := e.reviewAnswer(ctx, evaluation); err != nil {
+			return err
+		}
+	}
+
+	if e.submissionConfirmer != nil {
+		if err := e.submissionConfirmer.ConfirmSubmission(ctx, evaluations); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "answer submission confirmed", "answers", len(evaluations))
+	}
+
+	workers := max(1, e.submitConcurrency)
+	errs := make([]error, len(evaluations))
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	completed := 0
 
 	for i, evaluation := range evaluations {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
 		// Only log detailed progress when no progress reporter is active
 		if progress == nil {
 			slog.InfoContext(ctx, "submitting answer",
@@ -400,18 +1183,37 @@ func (e *Engine) submitAnswersWithProgress(ctx context.Context, session *ReviewS
 			)
 		}
 
-		if progress != nil {
-			progress.ReportProgress(i+1, len(evaluations), fmt.Sprintf("Submitting answer %d of %d", i+1, len(evaluations)))
-		}
+		wg.Add(1)
+		go func(i int, evaluation *QuestionEvaluation) {
+			defer wg.Done()
+			defer func() { <-slots }()
 
-		err := e.wafrEvaluator.SubmitAnswer(ctx, session.AWSWorkloadID, evaluation.Question.ID, evaluation)
-		if err != nil {
+			errs[i] = e.wafrEvaluator.SubmitAnswer(ctx, session.AWSWorkloadID, evaluation.Question.ID, evaluation)
+
+			if progress != nil {
+				mu.Lock()
+				completed++
+				progress.ReportProgress(completed, len(evaluations), fmt.Sprintf("Submitting answer %d of %d", completed, len(evaluations)))
+				mu.Unlock()
+			}
+		}(i, evaluation)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("answer submission cancelled: %w", err)
+	}
+
+	// Outcomes are reported in question order whatever order they finished in
+	successCount := 0
+	errorCount := 0
+	for i, evaluation := range evaluations {
+		if errs[i] != nil {
 			slog.ErrorContext(ctx, "failed to submit answer, continuing",
 				"question_id", evaluation.Question.ID,
-				"error", err,
+				"error", errs[i],
 			)
 			errorCount++
-			// Continue with remaining answers
 			continue
 		}
 
@@ -438,13 +1240,38 @@ func (e *Engine) submitAnswersWithProgress(ctx context.Context, session *ReviewS
 	return nil
 }
 
+// reviewAnswer asks the answer reviewer, if any, to confirm the choices of a
+// low-confidence evaluation and applies its selection
+func (e *Engine) reviewAnswer(ctx context.Context, evaluation *QuestionEvaluation) error {
+	if e.answerReviewer == nil || evaluation.ConfidenceScore >= e.reviewThreshold {
+		return nil
+	}
+
+	choices, err := e.answerReviewer.ReviewAnswer(ctx, evaluation)
+	if err != nil {
+		return fmt.Errorf("interactive review of question %s failed: %w", evaluation.Question.ID, err)
+	}
+
+	slog.InfoContext(ctx, "answer reviewed interactively",
+		"question_id", evaluation.Question.ID,
+		"suggested_choices", len(evaluation.SelectedChoices),
+		"selected_choices", len(choices),
+	)
+	evaluation.SelectedChoices = choices
+	evaluation.Source = SourceInteractive
+	return nil
+}
+
 // extractRisks extracts risks from evaluations
 func (e *Engine) extractRisks(evaluations []*QuestionEvaluation) []*Risk {
 	risks := make([]*Risk, 0)
 
 	for _, eval := range evaluations {
+		if eval.NotApplicable {
+			continue
+		}
 		// If confidence is low or no choices selected, it might be a risk
-		if eval.ConfidenceScore < 0.5 || len(eval.SelectedChoices) == 0 {
+		if eval.ConfidenceScore < e.riskThresholds.RiskConfidenceThreshold || len(eval.SelectedChoices) == 0 {
 			risk := &Risk{
 				ID:                fmt.Sprintf("risk-%s", eval.Question.ID),
 				Question:          eval.Question,
@@ -452,6 +1279,7 @@ func (e *Engine) extractRisks(evaluations []*QuestionEvaluation) []*Risk {
 				Severity:          RiskLevelMedium,
 				Description:       fmt.Sprintf("Low confidence or incomplete answer for: %s", eval.Question.Title),
 				AffectedResources: []string{},
+				Source:            eval.Source,
 			}
 			risks = append(risks, risk)
 		}
@@ -460,24 +1288,138 @@ func (e *Engine) extractRisks(evaluations []*QuestionEvaluation) []*Risk {
 	return risks
 }
 
+// mergeResourceHints adds resources with static inspection hints to the risks
+// of the same pillar whose question concerns the resource's type. Severities
+// are left unchanged, so the summary's risk counts still match the risks.
+func mergeResourceHints(risks []*Risk, evaluations []*QuestionEvaluation, model *WorkloadModel) []*Risk {
+	if model == nil {
+		return risks
+	}
+
+	relevantTypes := make(map[string][]string, len(evaluations))
+	for _, eval := range evaluations {
+		if eval.Question != nil {
+			relevantTypes[eval.Question.ID] = eval.RelevantResourceTypes
+		}
+	}
+
+	for _, risk := range risks {
+		if risk.Question == nil {
+			continue
+		}
+		types := relevantTypes[risk.Question.ID]
+		for _, resource := range model.Resources {
+			if !slices.ContainsFunc(types, func(prefix string) bool { return strings.HasPrefix(resource.Type, prefix) }) {
+				continue
+			}
+			for _, hint := range resource.Hints {
+				if hint.Pillar == risk.Pillar && !slices.Contains(risk.AffectedResources, resource.Address) {
+					risk.AffectedResources = append(risk.AffectedResources, resource.Address)
+				}
+			}
+		}
+	}
+
+	return risks
+}
+
+// confidenceWeights weights the confidence of a question in the
+// severity-weighted average by the Well-Architected Tool's severity of the
+// question. Questions without a risk, or whose risk is unassessed, weigh 1.
+var confidenceWeights = map[RiskLevel]float64{
+	RiskLevelMedium: 2,
+	RiskLevelHigh:   3,
+}
+
+// questionWeight returns the weight of a question in the severity-weighted
+// average confidence
+func questionWeight(severities map[string]RiskLevel, questionID string) float64 {
+	if weight, ok := confidenceWeights[severities[questionID]]; ok {
+		return weight
+	}
+	return 1
+}
+
 // buildSummary builds a summary of the results
 func (e *Engine) buildSummary(evaluations []*QuestionEvaluation, improvementPlan *ImprovementPlan) *ResultsSummary {
 	totalConfidence := 0.0
+	weightedConfidence := 0.0
+	totalWeight := 0.0
 	highRisks := 0
 	mediumRisks := 0
+	pillarSummaries := make(map[Pillar]PillarSummary)
+	pillarConfidence := make(map[Pillar]float64)
+	pillarApplicable := make(map[Pillar]int)
+	applicable := 0
+	var timedOut []string
+
+	// The plan's risks carry the tool's severity of each question, with the
+	// risk.question_severity_overrides already applied
+	severities := make(map[string]RiskLevel)
+	if improvementPlan != nil {
+		for _, item := range improvementPlan.Items {
+			if item.Risk != nil && item.Risk.Question != nil {
+				severities[item.Risk.Question.ID] = item.Risk.Severity
+			}
+		}
+	}
 
 	for _, eval := range evaluations {
-		totalConfidence += eval.ConfidenceScore
-		if eval.ConfidenceScore < 0.3 {
+		if eval.TimedOut && eval.Question != nil {
+			timedOut = append(timedOut, eval.Question.ID)
+		}
+		counted := !eval.Suppressed && !eval.NotApplicable
+		isHigh := counted && eval.ConfidenceScore < e.riskThresholds.HighConfidenceThreshold
+		isMedium := counted && !isHigh && eval.ConfidenceScore < e.riskThresholds.MediumConfidenceThreshold
+		if isHigh {
 			highRisks++
-		} else if eval.ConfidenceScore < 0.7 {
+		} else if isMedium {
 			mediumRisks++
 		}
+		// Questions that do not apply are not assessed, so their confidence
+		// does not count towards the averages
+		if !eval.NotApplicable {
+			applicable++
+			totalConfidence += eval.ConfidenceScore
+			weight := 1.0
+			if eval.Question != nil {
+				weight = questionWeight(severities, eval.Question.ID)
+			}
+			weightedConfidence += weight * eval.ConfidenceScore
+			totalWeight += weight
+		}
+
+		if eval.Question == nil {
+			continue
+		}
+		pillar := eval.Question.Pillar
+		ps := pillarSummaries[pillar]
+		ps.QuestionsEvaluated++
+		if isHigh {
+			ps.HighRisks++
+		} else if isMedium {
+			ps.MediumRisks++
+		}
+		pillarSummaries[pillar] = ps
+		if !eval.NotApplicable {
+			pillarApplicable[pillar]++
+			pillarConfidence[pillar] += eval.ConfidenceScore
+		}
 	}
 
 	avgConfidence := 0.0
-	if len(evaluations) > 0 {
-		avgConfidence = totalConfidence / float64(len(evaluations))
+	weightedAvgConfidence := 0.0
+	if applicable > 0 {
+		avgConfidence = totalConfidence / float64(applicable)
+		weightedAvgConfidence = weightedConfidence / totalWeight
+	}
+
+	for pillar, ps := range pillarSummaries {
+		if pillarApplicable[pillar] > 0 {
+			ps.AverageConfidence = pillarConfidence[pillar] / float64(pillarApplicable[pillar])
+		}
+		ps.Advisory = slices.Contains(e.advisoryPillars, pillar)
+		pillarSummaries[pillar] = ps
 	}
 
 	improvementPlanSize := 0
@@ -486,12 +1428,15 @@ func (e *Engine) buildSummary(evaluations []*QuestionEvaluation, improvementPlan
 	}
 
 	return &ResultsSummary{
-		TotalQuestions:      len(evaluations),
-		QuestionsEvaluated:  len(evaluations),
-		HighRisks:           highRisks,
-		MediumRisks:         mediumRisks,
-		AverageConfidence:   avgConfidence,
-		ImprovementPlanSize: improvementPlanSize,
+		TotalQuestions:             len(evaluations),
+		QuestionsEvaluated:         len(evaluations),
+		HighRisks:                  highRisks,
+		MediumRisks:                mediumRisks,
+		AverageConfidence:          avgConfidence,
+		SeverityWeightedConfidence: weightedAvgConfidence,
+		ImprovementPlanSize:        improvementPlanSize,
+		PillarSummaries:            pillarSummaries,
+		TimedOutQuestions:          timedOut,
 	}
 }
 
@@ -528,6 +1473,41 @@ func (e *Engine) ResumeSession(ctx context.Context, sessionID string) (*ReviewSe
 		}
 	}
 
+	return e.resumeWorkflow(ctx, session)
+}
+
+// ResumeSessionFrom rewinds a session to checkpoint and resumes it, re-running
+// every step after the checkpoint, e.g. to evaluate the questions again after
+// a prompt change. Completed sessions can be rewound too. See
+// ResumeCheckpoints for the checkpoints accepted.
+func (e *Engine) ResumeSessionFrom(ctx context.Context, sessionID string, checkpoint string) (*ReviewSession, error) {
+	session, err := e.sessionManager.LoadSession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	previous := session.Checkpoint
+	if err := rewindCheckpoint(session, checkpoint); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "rewinding session",
+		"session_id", sessionID,
+		"from_checkpoint", previous,
+		"to_checkpoint", checkpoint,
+	)
+
+	session.Status = SessionStatusInProgress
+	session.UpdatedAt = e.clock.Now()
+	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save rewound session: %w", err)
+	}
+
+	return e.resumeWorkflow(ctx, session)
+}
+
+// resumeWorkflow runs the workflow of a loaded session from its checkpoint
+// and saves the completed session
+func (e *Engine) resumeWorkflow(ctx context.Context, session *ReviewSession) (*ReviewSession, error) {
 	slog.InfoContext(ctx, "resuming from checkpoint",
 		"checkpoint", session.Checkpoint,
 	)
@@ -540,14 +1520,14 @@ func (e *Engine) ResumeSession(ctx context.Context, sessionID string) (*ReviewSe
 
 	session.Results = results
 	session.Status = SessionStatusCompleted
-	session.UpdatedAt = time.Now()
+	session.UpdatedAt = e.clock.Now()
 
 	if err := e.sessionManager.SaveSession(ctx, session); err != nil {
 		return nil, fmt.Errorf("failed to save resumed session: %w", err)
 	}
 
 	slog.InfoContext(ctx, "session resumed successfully",
-		"session_id", sessionID,
+		"session_id", session.SessionID,
 	)
 
 	return session, nil
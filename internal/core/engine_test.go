@@ -2,17 +2,37 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/logging"
+	"github.com/waffle/waffle/internal/metrics"
 )
 
+// TestMain logs to the console only; the engine's global logger would
+// otherwise create a log file under the package directory
+func TestMain(m *testing.M) {
+	if err := logging.InitGlobalLogger(&logging.Config{Level: logging.LevelInfo}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
 // Mock implementations for testing
 
 type mockSessionManager struct {
-	createSessionFunc       func(ctx context.Context, workloadID string, scope ReviewScope, awsWorkloadID string) (*ReviewSession, error)
+	createSessionFunc       func(ctx context.Context, workloadID string, scope ReviewScope, awsWorkloadID string, sessionID string) (*ReviewSession, error)
 	saveSessionFunc         func(ctx context.Context, session *ReviewSession) error
 	loadSessionFunc         func(ctx context.Context, sessionID string) (*ReviewSession, error)
 	updateSessionStatusFunc func(ctx context.Context, sessionID string, status SessionStatus) error
@@ -20,9 +40,9 @@ type mockSessionManager struct {
 	getAWSWorkloadIDFunc    func(ctx context.Context, sessionID string) (string, error)
 }
 
-func (m *mockSessionManager) CreateSession(ctx context.Context, workloadID string, scope ReviewScope, awsWorkloadID string) (*ReviewSession, error) {
+func (m *mockSessionManager) CreateSession(ctx context.Context, workloadID string, scope ReviewScope, awsWorkloadID string, sessionID string) (*ReviewSession, error) {
 	if m.createSessionFunc != nil {
-		return m.createSessionFunc(ctx, workloadID, scope, awsWorkloadID)
+		return m.createSessionFunc(ctx, workloadID, scope, awsWorkloadID, sessionID)
 	}
 	return &ReviewSession{
 		SessionID:     "test-session-id",
@@ -315,6 +335,26 @@ func TestExecuteReview_Success(t *testing.T) {
 	assert.Equal(t, SessionStatusCompleted, session.Status)
 }
 
+func TestExecuteReview_Metrics(t *testing.T) {
+	m := metrics.New(metrics.NewRegistry())
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetMetrics(m)
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	_, err := engine.ExecuteReview(context.Background(), session)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, m.QuestionsEvaluated.Value(string(PillarSecurity)))
+	assert.Equal(t, uint64(1), m.ReviewDuration.Count())
+}
+
 func TestExecuteReview_IaCAnalysisFails(t *testing.T) {
 	sessionMgr := &mockSessionManager{}
 	iacAnalyzer := &mockIaCAnalyzer{
@@ -443,3 +483,1619 @@ func TestExecuteWorkflow_WithCheckpoints(t *testing.T) {
 	assert.Equal(t, "milestone_created", session.Checkpoint)
 	assert.NotEmpty(t, session.MilestoneID)
 }
+
+func TestBuildSummary_RiskThresholds(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		{ConfidenceScore: 0.2},
+		{ConfidenceScore: 0.4},
+		{ConfidenceScore: 0.6},
+		{ConfidenceScore: 0.9},
+	}
+
+	tests := []struct {
+		name       string
+		thresholds *RiskThresholds
+		wantHigh   int
+		wantMedium int
+	}{
+		{
+			name:       "default thresholds",
+			thresholds: nil,
+			wantHigh:   1,
+			wantMedium: 2,
+		},
+		{
+			name: "stricter thresholds",
+			thresholds: &RiskThresholds{
+				RiskConfidenceThreshold:   0.5,
+				HighConfidenceThreshold:   0.5,
+				MediumConfidenceThreshold: 0.95,
+			},
+			wantHigh:   2,
+			wantMedium: 2,
+		},
+		{
+			name: "lenient thresholds",
+			thresholds: &RiskThresholds{
+				RiskConfidenceThreshold:   0.5,
+				HighConfidenceThreshold:   0.1,
+				MediumConfidenceThreshold: 0.3,
+			},
+			wantHigh:   0,
+			wantMedium: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+			if tt.thresholds != nil {
+				engine.SetRiskThresholds(*tt.thresholds)
+			}
+
+			summary := engine.buildSummary(evaluations, nil)
+
+			assert.Equal(t, tt.wantHigh, summary.HighRisks)
+			assert.Equal(t, tt.wantMedium, summary.MediumRisks)
+		})
+	}
+}
+
+func TestExtractRisks_RiskConfidenceThreshold(t *testing.T) {
+	choice := Choice{ID: "choice-1", Title: "Choice 1"}
+	evaluations := []*QuestionEvaluation{
+		{
+			Question:        &WAFRQuestion{ID: "q1", Pillar: PillarSecurity},
+			SelectedChoices: []Choice{choice},
+			ConfidenceScore: 0.45,
+		},
+		{
+			Question:        &WAFRQuestion{ID: "q2", Pillar: PillarReliability},
+			SelectedChoices: []Choice{choice},
+			ConfidenceScore: 0.65,
+		},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	assert.Len(t, engine.extractRisks(evaluations), 1)
+
+	engine.SetRiskThresholds(RiskThresholds{
+		RiskConfidenceThreshold:   0.7,
+		HighConfidenceThreshold:   0.3,
+		MediumConfidenceThreshold: 0.7,
+	})
+	assert.Len(t, engine.extractRisks(evaluations), 2)
+}
+
+func TestExtractRisks_NotApplicable(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		{
+			Question:        &WAFRQuestion{ID: "serverless-functions", Pillar: PillarPerformanceEfficiency},
+			SelectedChoices: []Choice{},
+			ConfidenceScore: 1.0,
+			NotApplicable:   true,
+		},
+		{
+			Question:        &WAFRQuestion{ID: "q2", Pillar: PillarReliability},
+			SelectedChoices: []Choice{},
+			ConfidenceScore: 0.2,
+		},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	evaluations[1].Source = SourceModel
+	risks := engine.extractRisks(evaluations)
+	require.Len(t, risks, 1)
+	assert.Equal(t, "q2", risks[0].Question.ID)
+	assert.Equal(t, SourceModel, risks[0].Source, "a risk takes the source of its answer")
+
+	evaluations[0].ConfidenceScore = 0.0
+	summary := engine.buildSummary(evaluations, nil)
+	assert.Equal(t, 1, summary.HighRisks, "inapplicable questions count toward no risk totals")
+}
+
+func TestMergeResourceHints(t *testing.T) {
+	model := &WorkloadModel{
+		Resources: []Resource{
+			{Address: "aws_s3_bucket.site", Type: "aws_s3_bucket", Hints: []ResourceHint{
+				{Rule: "public-s3-bucket", Pillar: PillarSecurity, Severity: RiskLevelHigh},
+			}},
+			{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+		},
+	}
+	dataRest := &WAFRQuestion{ID: "data-rest", Pillar: PillarSecurity}
+	identities := &WAFRQuestion{ID: "identities", Pillar: PillarSecurity}
+	backup := &WAFRQuestion{ID: "backup", Pillar: PillarReliability}
+	evaluations := []*QuestionEvaluation{
+		{Question: dataRest, RelevantResourceTypes: []string{"aws_s3", "aws_kms_key"}},
+		{Question: identities, RelevantResourceTypes: []string{"aws_iam"}},
+		{Question: backup, RelevantResourceTypes: []string{"aws_s3_bucket"}},
+	}
+	risks := []*Risk{
+		{ID: "risk-data-rest", Question: dataRest, Pillar: PillarSecurity, Severity: RiskLevelMedium, AffectedResources: []string{}},
+		{ID: "risk-identities", Question: identities, Pillar: PillarSecurity, Severity: RiskLevelMedium, AffectedResources: []string{}},
+		{ID: "risk-backup", Question: backup, Pillar: PillarReliability, Severity: RiskLevelMedium, AffectedResources: []string{}},
+	}
+
+	merged := mergeResourceHints(risks, evaluations, model)
+
+	assert.Equal(t, []string{"aws_s3_bucket.site"}, merged[0].AffectedResources)
+	assert.Empty(t, merged[1].AffectedResources, "hints only affect questions concerning the resource's type")
+	assert.Empty(t, merged[2].AffectedResources, "hints only affect risks of their pillar")
+	for _, risk := range merged {
+		assert.Equal(t, RiskLevelMedium, risk.Severity, "hints do not change severity")
+	}
+
+	assert.Equal(t, risks, mergeResourceHints(risks, evaluations, nil))
+}
+
+func TestBuildSummary_PillarSummaries(t *testing.T) {
+	securityQ := &WAFRQuestion{ID: "sec_1", Pillar: PillarSecurity}
+	reliabilityQ := &WAFRQuestion{ID: "rel_1", Pillar: PillarReliability}
+	costQ := &WAFRQuestion{ID: "cost_1", Pillar: PillarCostOptimization}
+
+	evaluations := []*QuestionEvaluation{
+		{Question: securityQ, ConfidenceScore: 0.2},
+		{Question: securityQ, ConfidenceScore: 0.6},
+		{Question: securityQ, ConfidenceScore: 0.9},
+		{Question: reliabilityQ, ConfidenceScore: 0.5},
+		{Question: reliabilityQ, ConfidenceScore: 0.1},
+		{Question: costQ, ConfidenceScore: 0.8},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	summary := engine.buildSummary(evaluations, nil)
+
+	require.Len(t, summary.PillarSummaries, 3)
+
+	security := summary.PillarSummaries[PillarSecurity]
+	assert.Equal(t, 3, security.QuestionsEvaluated)
+	assert.Equal(t, 1, security.HighRisks)
+	assert.Equal(t, 1, security.MediumRisks)
+	assert.InDelta(t, 0.5667, security.AverageConfidence, 0.001)
+
+	reliability := summary.PillarSummaries[PillarReliability]
+	assert.Equal(t, 2, reliability.QuestionsEvaluated)
+	assert.Equal(t, 1, reliability.HighRisks)
+	assert.Equal(t, 1, reliability.MediumRisks)
+	assert.InDelta(t, 0.3, reliability.AverageConfidence, 0.001)
+
+	cost := summary.PillarSummaries[PillarCostOptimization]
+	assert.Equal(t, 1, cost.QuestionsEvaluated)
+	assert.Equal(t, 0, cost.HighRisks)
+	assert.Equal(t, 0, cost.MediumRisks)
+	assert.InDelta(t, 0.8, cost.AverageConfidence, 0.001)
+
+	// Per-pillar counts add up to the aggregate
+	assert.Equal(t, summary.HighRisks, security.HighRisks+reliability.HighRisks+cost.HighRisks)
+	assert.Equal(t, summary.MediumRisks, security.MediumRisks+reliability.MediumRisks+cost.MediumRisks)
+}
+
+func TestBuildSummary_AdvisoryPillars(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		{Question: &WAFRQuestion{ID: "sec_1", Pillar: PillarSecurity}, ConfidenceScore: 0.2},
+		{Question: &WAFRQuestion{ID: "sus_1", Pillar: PillarSustainability}, ConfidenceScore: 0.2},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetAdvisoryPillars([]Pillar{PillarSustainability})
+	summary := engine.buildSummary(evaluations, nil)
+
+	assert.False(t, summary.PillarSummaries[PillarSecurity].Advisory)
+	assert.True(t, summary.PillarSummaries[PillarSustainability].Advisory)
+	// Advisory risks still count toward the totals
+	assert.Equal(t, 1, summary.PillarSummaries[PillarSustainability].HighRisks)
+	assert.Equal(t, 2, summary.HighRisks)
+}
+
+func TestBuildSummary_TimedOutQuestions(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		{Question: &WAFRQuestion{ID: "sec_1", Pillar: PillarSecurity}, ConfidenceScore: 0.8},
+		{Question: &WAFRQuestion{ID: "sec_2", Pillar: PillarSecurity}, TimedOut: true},
+		{Question: &WAFRQuestion{ID: "rel_1", Pillar: PillarReliability}, TimedOut: true},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	summary := engine.buildSummary(evaluations, nil)
+
+	assert.Equal(t, []string{"sec_2", "rel_1"}, summary.TimedOutQuestions)
+	// Timed out questions still count as evaluated, with zero confidence
+	assert.Equal(t, 3, summary.QuestionsEvaluated)
+	assert.Equal(t, 2, summary.HighRisks)
+	assert.Empty(t, engine.buildSummary(evaluations[:1], nil).TimedOutQuestions)
+}
+
+func TestBuildSummary_SeverityWeightedConfidence(t *testing.T) {
+	encryption := &WAFRQuestion{ID: "sec_1", Pillar: PillarSecurity}
+	tagging := &WAFRQuestion{ID: "cost_1", Pillar: PillarCostOptimization}
+	backups := &WAFRQuestion{ID: "rel_1", Pillar: PillarReliability}
+	// The same confidences in every case, so only the questions' severities
+	// change the weighted average
+	evaluations := []*QuestionEvaluation{
+		{Question: encryption, ConfidenceScore: 0.2},
+		{Question: tagging, ConfidenceScore: 0.8},
+		{Question: backups, ConfidenceScore: 0.8},
+	}
+	plan := func(severities map[*WAFRQuestion]RiskLevel) *ImprovementPlan {
+		p := &ImprovementPlan{}
+		for question, severity := range severities {
+			p.Items = append(p.Items, &ImprovementPlanItem{Risk: &Risk{Question: question, Severity: severity}})
+		}
+		return p
+	}
+
+	tests := []struct {
+		name         string
+		plan         *ImprovementPlan
+		wantWeighted float64
+	}{
+		{
+			name:         "no improvement plan",
+			wantWeighted: 0.6,
+		},
+		{
+			// (3*0.2 + 0.8 + 0.8) / (3 + 1 + 1)
+			name:         "uncertain answer to high-risk question",
+			plan:         plan(map[*WAFRQuestion]RiskLevel{encryption: RiskLevelHigh}),
+			wantWeighted: 2.2 / 5,
+		},
+		{
+			// (0.2 + 3*0.8 + 0.8) / (1 + 3 + 1)
+			name:         "confident answer to high-risk question",
+			plan:         plan(map[*WAFRQuestion]RiskLevel{tagging: RiskLevelHigh}),
+			wantWeighted: 3.4 / 5,
+		},
+		{
+			// (0.2 + 2*0.8 + 0.8) / (1 + 2 + 1)
+			name:         "medium risk weighs 2 and unassessed weighs 1",
+			plan:         plan(map[*WAFRQuestion]RiskLevel{tagging: RiskLevelMedium, encryption: RiskLevelUnassessed}),
+			wantWeighted: 2.6 / 4,
+		},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := engine.buildSummary(evaluations, tt.plan)
+
+			assert.InDelta(t, 0.6, summary.AverageConfidence, 0.001)
+			assert.InDelta(t, tt.wantWeighted, summary.SeverityWeightedConfidence, 0.001)
+		})
+	}
+
+	t.Run("no evaluations", func(t *testing.T) {
+		summary := engine.buildSummary(nil, nil)
+
+		assert.Zero(t, summary.AverageConfidence)
+		assert.Zero(t, summary.SeverityWeightedConfidence)
+	})
+}
+
+func TestBuildSummary_NotApplicableConfidence(t *testing.T) {
+	evaluations := []*QuestionEvaluation{
+		{Question: &WAFRQuestion{ID: "sec_1", Pillar: PillarSecurity}, ConfidenceScore: 0.4},
+		{Question: &WAFRQuestion{ID: "sec_2", Pillar: PillarSecurity}, ConfidenceScore: 1.0, NotApplicable: true},
+		{Question: &WAFRQuestion{ID: "rel_1", Pillar: PillarReliability}, ConfidenceScore: 1.0, NotApplicable: true},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	summary := engine.buildSummary(evaluations, nil)
+
+	// Questions that do not apply are counted but not averaged
+	assert.Equal(t, 3, summary.QuestionsEvaluated)
+	assert.InDelta(t, 0.4, summary.AverageConfidence, 0.001)
+	assert.InDelta(t, 0.4, summary.SeverityWeightedConfidence, 0.001)
+	assert.InDelta(t, 0.4, summary.PillarSummaries[PillarSecurity].AverageConfidence, 0.001)
+	assert.Equal(t, 1, summary.PillarSummaries[PillarReliability].QuestionsEvaluated)
+	assert.Zero(t, summary.PillarSummaries[PillarReliability].AverageConfidence)
+}
+
+func TestInitiateReview_WorkloadDescriptionTemplate(t *testing.T) {
+	var gotDescription string
+	wafrEval := &mockWAFREvaluator{
+		createWorkloadFunc: func(ctx context.Context, workloadID string, description string) (string, error) {
+			gotDescription = description
+			return "aws-workload-123", nil
+		},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetWorkloadDescriptionOptions(WorkloadDescriptionOptions{
+		Template: "{{.WorkloadID}} from {{.SourceDir}} @ {{.GitRef}} ({{.WaffleVersion}})",
+		Provenance: WorkloadProvenance{
+			SourceDir:     "/src/app",
+			GitRef:        "main",
+			WaffleVersion: "1.2.0",
+		},
+	})
+
+	_, err := engine.InitiateReview(context.Background(), "test-workload", ReviewScope{Level: ScopeLevelWorkload})
+
+	require.NoError(t, err)
+	assert.Equal(t, "test-workload from /src/app @ main (1.2.0)", gotDescription)
+}
+
+func TestExecuteReview_ReportDrift(t *testing.T) {
+	drift := []PropertyDrift{{Address: "aws_instance.web", Property: "instance_type", ConfigValue: "t3.micro", PlanValue: "t3.large"}}
+
+	merged := false
+	iacAnalyzer := &mockIaCAnalyzer{
+		mergeWorkloadModelsFunc: func(ctx context.Context, planModel, sourceModel *WorkloadModel) (*WorkloadModel, error) {
+			merged = true
+			assert.Equal(t, "plan", planModel.SourceType)
+			assert.Equal(t, "hcl", sourceModel.SourceType)
+			return &WorkloadModel{Framework: "terraform", SourceType: "hcl_enhanced", Drift: drift}, nil
+		},
+	}
+
+	engine := NewEngine(&mockSessionManager{}, iacAnalyzer, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		PlanFilePath:  "plan.json",
+		ReportDrift:   true,
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	_, err := engine.ExecuteReview(context.Background(), session)
+
+	require.NoError(t, err)
+	assert.True(t, merged)
+	require.NotNil(t, session.WorkloadModel)
+	assert.Equal(t, drift, session.WorkloadModel.Drift)
+
+	output := ConvertReviewSessionToOutput(session)
+	require.Len(t, output.Drift, 1)
+	assert.Equal(t, "instance_type", output.Drift[0].Property)
+	assert.Equal(t, "t3.large", output.Drift[0].PlanValue)
+}
+
+func TestExecuteReview_ReportDriftKeepsPlanResources(t *testing.T) {
+	planResources := []Resource{{ID: "aws_instance.web", Address: "aws_instance.web", Type: "aws_instance", Properties: map[string]interface{}{"instance_type": "t3.large"}}}
+
+	evaluatedResources := func(reportDrift bool) []Resource {
+		var evaluated []Resource
+		iacAnalyzer := &mockIaCAnalyzer{
+			parseTerraformPlanFunc: func(ctx context.Context, planFilePath string) (*WorkloadModel, error) {
+				return &WorkloadModel{Framework: "terraform", SourceType: "plan", Resources: planResources}, nil
+			},
+			mergeWorkloadModelsFunc: func(ctx context.Context, planModel, sourceModel *WorkloadModel) (*WorkloadModel, error) {
+				return &WorkloadModel{
+					SourceType: "hcl_enhanced",
+					Resources: []Resource{
+						{ID: "aws_instance.web", Address: "aws_instance.web", Type: "aws_instance", Properties: map[string]interface{}{"instance_type": "t3.micro"}},
+						{ID: "aws_s3_bucket.logs", Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+					},
+					Drift: []PropertyDrift{{Address: "aws_instance.web", Property: "instance_type", ConfigValue: "t3.micro", PlanValue: "t3.large"}},
+				}, nil
+			},
+			extractResourcesFunc: func(ctx context.Context, model *WorkloadModel) ([]Resource, error) {
+				return model.Resources, nil
+			},
+		}
+		wafrEval := &mockWAFREvaluator{
+			evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+				evaluated = workloadModel.Resources
+				return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+			},
+		}
+		engine := NewEngine(&mockSessionManager{}, iacAnalyzer, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+		engine.SetCreateMilestone(false)
+
+		session := &ReviewSession{
+			SessionID:     "test-session",
+			WorkloadID:    "test-workload",
+			AWSWorkloadID: "aws-workload-123",
+			PlanFilePath:  "plan.json",
+			ReportDrift:   reportDrift,
+			Scope:         ReviewScope{Level: ScopeLevelWorkload},
+			Status:        SessionStatusCreated,
+		}
+		_, err := engine.ExecuteReview(context.Background(), session)
+		require.NoError(t, err)
+		if reportDrift {
+			assert.Len(t, session.WorkloadModel.Drift, 1)
+		}
+		return evaluated
+	}
+
+	withoutDrift := evaluatedResources(false)
+	withDrift := evaluatedResources(true)
+
+	require.NotEmpty(t, withoutDrift)
+	assert.Equal(t, withoutDrift, withDrift, "reporting drift does not change what the questions are evaluated on")
+	assert.Equal(t, "t3.large", withDrift[0].Properties["instance_type"])
+}
+
+func TestExecuteReview_RequireFreshPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing []string
+		wantErr bool
+	}{
+		{
+			name: "fresh plan",
+		},
+		{
+			name:    "stale plan",
+			missing: []string{"aws_kms_key.logs", "aws_sqs_queue.jobs"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iacAnalyzer := &mockIaCAnalyzer{
+				mergeWorkloadModelsFunc: func(ctx context.Context, planModel, sourceModel *WorkloadModel) (*WorkloadModel, error) {
+					return &WorkloadModel{SourceType: "hcl_enhanced", MissingFromPlan: tt.missing}, nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, iacAnalyzer, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+
+			session := &ReviewSession{
+				SessionID:        "test-session",
+				WorkloadID:       "test-workload",
+				AWSWorkloadID:    "aws-workload-123",
+				PlanFilePath:     "plan.json",
+				RequireFreshPlan: true,
+				Scope:            ReviewScope{Level: ScopeLevelWorkload},
+				Status:           SessionStatusCreated,
+			}
+
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				// The check does not replace the plan model
+				assert.Equal(t, "plan", session.WorkloadModel.SourceType)
+				return
+			}
+			require.ErrorIs(t, err, ErrStalePlan)
+			var iacErr *IaCParsingError
+			require.ErrorAs(t, err, &iacErr)
+			assert.Equal(t, "plan.json", iacErr.File)
+			assert.Contains(t, err.Error(), "aws_kms_key.logs, aws_sqs_queue.jobs")
+		})
+	}
+}
+
+func TestExecuteReview_PartialPillarFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		questions  []*WAFRQuestion
+		wantErr    bool
+		wantFailed map[Pillar]string
+	}{
+		{
+			name: "continues with remaining pillars",
+			questions: []*WAFRQuestion{
+				{ID: "q1", Pillar: PillarReliability, Title: "Reliability question"},
+			},
+			wantFailed: map[Pillar]string{PillarSecurity: "throttled"},
+		},
+		{
+			name:    "fails without any questions",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wafrEvaluator := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					return tt.questions, &PillarRetrievalError{
+						Failures: map[Pillar]error{PillarSecurity: errors.New("throttled")},
+					}
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFailed, session.FailedPillars)
+		})
+	}
+}
+
+func TestExecuteReview_EmptyWorkloadModel(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowEmpty bool
+		wantErr    bool
+	}{
+		{
+			name:    "fails before evaluation by default",
+			wantErr: true,
+		},
+		{
+			name:       "continues when empty workloads are allowed",
+			allowEmpty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iacAnalyzer := &mockIaCAnalyzer{
+				extractResourcesFunc: func(ctx context.Context, model *WorkloadModel) ([]Resource, error) {
+					return []Resource{}, nil
+				},
+			}
+			questionsRequested := false
+			wafrEvaluator := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					questionsRequested = true
+					return []*WAFRQuestion{{ID: "q1", Pillar: PillarSecurity, Title: "Security question"}}, nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, iacAnalyzer, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetAllowEmptyWorkload(tt.allowEmpty)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrEmptyWorkloadModel)
+				var iacErr *IaCParsingError
+				assert.ErrorAs(t, err, &iacErr)
+				assert.False(t, questionsRequested, "questions should not be retrieved for an empty workload")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, questionsRequested)
+		})
+	}
+}
+
+// warningIaCAnalyzer is a mockIaCAnalyzer that reports analysis warnings
+type warningIaCAnalyzer struct {
+	mockIaCAnalyzer
+	warnings []AnalysisWarning
+}
+
+func (m *warningIaCAnalyzer) AnalysisWarnings() []AnalysisWarning {
+	return m.warnings
+}
+
+func TestExecuteReview_Strict(t *testing.T) {
+	redacted := AnalysisWarning{Kind: AnalysisWarningRedaction, Source: "main.tf", Message: "redacted AWS Access Key"}
+	unparsed := AnalysisWarning{Kind: AnalysisWarningParse, Source: "aws_instance.web", Message: "properties not extracted: unsupported block"}
+
+	tests := []struct {
+		name     string
+		strict   bool
+		warnings []AnalysisWarning
+		wantErr  string
+	}{
+		{
+			name:     "redaction fails strict review",
+			strict:   true,
+			warnings: []AnalysisWarning{redacted},
+			wantErr:  "strict mode: IaC analysis recorded 1 redaction and 0 parse warnings",
+		},
+		{
+			name:     "parse warning fails strict review",
+			strict:   true,
+			warnings: []AnalysisWarning{unparsed},
+			wantErr:  "strict mode: IaC analysis recorded 0 redaction and 1 parse warnings",
+		},
+		{
+			name:   "strict review without warnings",
+			strict: true,
+		},
+		{
+			name:     "warnings are ignored without strict",
+			warnings: []AnalysisWarning{redacted, unparsed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questionsRequested := false
+			wafrEvaluator := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					questionsRequested = true
+					return []*WAFRQuestion{{ID: "q1", Pillar: PillarSecurity, Title: "Security question"}}, nil
+				},
+			}
+			iacAnalyzer := &warningIaCAnalyzer{warnings: tt.warnings}
+			engine := NewEngine(&mockSessionManager{}, iacAnalyzer, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetStrict(tt.strict)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			if tt.wantErr != "" {
+				var strictErr *StrictModeError
+				require.ErrorAs(t, err, &strictErr)
+				assert.Equal(t, tt.warnings, strictErr.Warnings)
+				assert.EqualError(t, strictErr, tt.wantErr)
+				assert.False(t, questionsRequested, "questions should not be retrieved after a strict failure")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, questionsRequested)
+		})
+	}
+}
+
+func TestExecuteReview_MaxQuestions(t *testing.T) {
+	questions := []*WAFRQuestion{
+		{ID: "rel_1", Pillar: PillarReliability},
+		{ID: "sec_1", Pillar: PillarSecurity},
+		{ID: "ops_1", Pillar: PillarOperationalExcellence},
+		{ID: "sec_2", Pillar: PillarSecurity},
+	}
+
+	tests := []struct {
+		name          string
+		maxQuestions  int
+		wantEvaluated []string
+		wantPartial   bool
+	}{
+		{
+			name:          "no cap evaluates every question",
+			wantEvaluated: []string{"ops_1", "sec_1", "sec_2", "rel_1"},
+		},
+		{
+			name:          "cap evaluates the first questions in pillar order",
+			maxQuestions:  2,
+			wantEvaluated: []string{"ops_1", "sec_1"},
+			wantPartial:   true,
+		},
+		{
+			name:          "cap above the question count is not partial",
+			maxQuestions:  10,
+			wantEvaluated: []string{"ops_1", "sec_1", "sec_2", "rel_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evaluated, submitted []string
+			wafrEvaluator := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					return questions, nil
+				},
+				evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+					evaluated = append(evaluated, question.ID)
+					return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+				},
+				submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+					submitted = append(submitted, questionID)
+					return nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetMaxQuestions(tt.maxQuestions)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			results, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEvaluated, evaluated)
+			assert.Equal(t, tt.wantEvaluated, submitted, "only evaluated questions are submitted")
+			assert.Equal(t, tt.wantPartial, results.Summary.Partial)
+			assert.Equal(t, len(tt.wantEvaluated), results.Summary.QuestionsEvaluated)
+			assert.Equal(t, len(questions), results.Summary.TotalQuestions)
+			assert.Equal(t, len(questions)-len(tt.wantEvaluated), results.Summary.QuestionsSkipped)
+		})
+	}
+}
+
+func TestExecuteReview_StableQuestionOrder(t *testing.T) {
+	questions := []*WAFRQuestion{
+		{ID: "sec_data_2", Pillar: PillarSecurity},
+		{ID: "cost_tags_1", Pillar: PillarCostOptimization},
+		{ID: "sec_data_1", Pillar: PillarSecurity},
+		{ID: "rel_backup_1", Pillar: PillarReliability},
+		{ID: "ops_runbooks_1", Pillar: PillarOperationalExcellence},
+		{ID: "sec_access_1", Pillar: PillarSecurity},
+	}
+	want := []string{"ops_runbooks_1", "sec_access_1", "sec_data_1", "sec_data_2", "rel_backup_1", "cost_tags_1"}
+
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 5; run++ {
+		shuffled := slices.Clone(questions)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		var evaluated []string
+		wafrEvaluator := &mockWAFREvaluator{
+			getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+				return shuffled, nil
+			},
+			evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+				evaluated = append(evaluated, question.ID)
+				return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+			},
+		}
+		engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+
+		session := &ReviewSession{
+			SessionID:     "test-session",
+			WorkloadID:    "test-workload",
+			AWSWorkloadID: "aws-workload-123",
+			Scope:         ReviewScope{Level: ScopeLevelWorkload},
+			Status:        SessionStatusCreated,
+		}
+
+		results, err := engine.ExecuteReview(context.Background(), session)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, evaluated, "run %d", run)
+		resultOrder := make([]string, 0, len(results.Evaluations))
+		for _, evaluation := range results.Evaluations {
+			resultOrder = append(resultOrder, evaluation.Question.ID)
+		}
+		assert.Equal(t, want, resultOrder, "run %d", run)
+	}
+}
+
+func TestExecuteReview_ImprovementPlanStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		plan       *ImprovementPlan
+		planErr    error
+		wantStatus interface{}
+		wantError  interface{}
+	}{
+		{
+			name:       "retrieval failed",
+			planErr:    errors.New("ListAnswers: throttled"),
+			wantStatus: ImprovementPlanStatusError,
+			wantError:  "ListAnswers: throttled",
+		},
+		{
+			name:       "no risks",
+			plan:       &ImprovementPlan{Items: []*ImprovementPlanItem{}},
+			wantStatus: ImprovementPlanStatusEmpty,
+		},
+		{
+			name:       "items",
+			plan:       &ImprovementPlan{Items: []*ImprovementPlanItem{{ID: "security/sec_1"}}},
+			wantStatus: ImprovementPlanStatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wafrEvaluator := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					return []*WAFRQuestion{{ID: "sec_1", Pillar: PillarSecurity}}, nil
+				},
+				evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+					return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+				},
+				getImprovementPlanFunc: func(ctx context.Context, awsWorkloadID string) (*ImprovementPlan, error) {
+					return tt.plan, tt.planErr
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			results, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err, "a failed improvement plan does not fail the review")
+			require.NotNil(t, results.ImprovementPlan)
+			output := ConvertReviewSessionToOutput(session)
+			assert.Equal(t, tt.wantStatus, output.Metadata["improvement_plan_status"])
+			assert.Equal(t, tt.wantError, output.Metadata["improvement_plan_error"])
+		})
+	}
+}
+
+func TestExecuteReview_ContextDocuments(t *testing.T) {
+	documents := []ContextDocument{{Path: "runbook.md", Content: "Fail over to us-west-2."}}
+
+	var seen []ContextDocument
+	wafrEvaluator := &mockWAFREvaluator{
+		getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+			return []*WAFRQuestion{{ID: "rel_1", Pillar: PillarReliability}}, nil
+		},
+		evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+			seen = workloadModel.Context
+			return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+		},
+	}
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetContextDocuments(documents)
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	_, err := engine.ExecuteReview(context.Background(), session)
+
+	require.NoError(t, err)
+	assert.Equal(t, documents, seen)
+	assert.Equal(t, documents, session.WorkloadModel.Context)
+}
+
+func TestExecuteReview_FixedResourceOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixed     bool
+		wantOrder []string
+		wantEdges []string
+	}{
+		{
+			name:      "file order",
+			wantOrder: []string{"aws_lb.web", "aws_instance.web", "aws_db_instance.main"},
+			wantEdges: []string{"aws_lb.web", "aws_db_instance.main"},
+		},
+		{
+			name:      "fixed order",
+			fixed:     true,
+			wantOrder: []string{"aws_db_instance.main", "aws_instance.web", "aws_lb.web"},
+			wantEdges: []string{"aws_db_instance.main", "aws_lb.web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iacAnalyzer := &mockIaCAnalyzer{
+				extractResourcesFunc: func(ctx context.Context, model *WorkloadModel) ([]Resource, error) {
+					return []Resource{{Address: "aws_lb.web"}, {Address: "aws_instance.web"}, {Address: "aws_db_instance.main"}}, nil
+				},
+				identifyRelationshipsFunc: func(ctx context.Context, resources []Resource) (*ResourceGraph, error) {
+					return &ResourceGraph{Edges: map[string][]string{"aws_instance.web": {"aws_lb.web", "aws_db_instance.main"}}}, nil
+				},
+			}
+			wafrEvaluator := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					return []*WAFRQuestion{{ID: "rel_1", Pillar: PillarReliability}}, nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, iacAnalyzer, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetFixedResourceOrder(tt.fixed)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err)
+			var order []string
+			for _, resource := range session.WorkloadModel.Resources {
+				order = append(order, resource.Address)
+			}
+			assert.Equal(t, tt.wantOrder, order)
+			assert.Equal(t, tt.wantEdges, session.WorkloadModel.Relationships.Edges["aws_instance.web"])
+		})
+	}
+}
+
+// deletingWAFREvaluator is a mockWAFREvaluator that can delete workloads
+type deletingWAFREvaluator struct {
+	*mockWAFREvaluator
+	deleted   []string
+	deleteErr error
+}
+
+func (m *deletingWAFREvaluator) DeleteWorkload(ctx context.Context, awsWorkloadID string) error {
+	m.deleted = append(m.deleted, awsWorkloadID)
+	return m.deleteErr
+}
+
+func TestExecuteReview_CleanupWorkload(t *testing.T) {
+	tests := []struct {
+		name        string
+		cleanup     bool
+		deleteErr   error
+		wantDeleted bool
+	}{
+		{
+			name: "keeps workload by default",
+		},
+		{
+			name:        "deletes workload when cleanup is set",
+			cleanup:     true,
+			wantDeleted: true,
+		},
+		{
+			name:      "unmanaged workload does not fail the review",
+			cleanup:   true,
+			deleteErr: ErrWorkloadNotManaged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wafrEvaluator := &deletingWAFREvaluator{
+				mockWAFREvaluator: &mockWAFREvaluator{
+					getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+						return []*WAFRQuestion{{ID: "q1", Pillar: PillarSecurity, Title: "Security question"}}, nil
+					},
+				},
+				deleteErr: tt.deleteErr,
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetCleanupWorkload(tt.cleanup)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			results, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err)
+			assert.NotNil(t, results)
+			if tt.cleanup {
+				assert.Equal(t, []string{"aws-workload-123"}, wafrEvaluator.deleted)
+			} else {
+				assert.Empty(t, wafrEvaluator.deleted)
+			}
+			assert.Equal(t, tt.wantDeleted, session.WorkloadDeleted)
+		})
+	}
+}
+
+func TestExecuteReview_NoMilestone(t *testing.T) {
+	tests := []struct {
+		name           string
+		checkpoint     string
+		wantCheckpoint string
+	}{
+		{
+			name:           "new review",
+			wantCheckpoint: "milestone_skipped",
+		},
+		{
+			name:           "resumed after improvement plan",
+			checkpoint:     "improvement_plan_retrieved",
+			wantCheckpoint: "milestone_skipped",
+		},
+		{
+			name:           "resumed after skipped milestone",
+			checkpoint:     "milestone_skipped",
+			wantCheckpoint: "milestone_skipped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			milestones := 0
+			wafrEval := &mockWAFREvaluator{
+				createMilestoneFunc: func(ctx context.Context, awsWorkloadID string, milestoneName string) (string, error) {
+					milestones++
+					return "1", nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetCreateMilestone(false)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+				Checkpoint:    tt.checkpoint,
+				WorkloadModel: &WorkloadModel{Framework: "terraform", Resources: []Resource{{ID: "test-resource"}}},
+				// A session saved after skipping the milestone carries the flag
+				MilestoneSkipped: tt.checkpoint == "milestone_skipped",
+			}
+
+			results, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err)
+			assert.NotNil(t, results)
+			assert.Zero(t, milestones, "CreateMilestone must not be called")
+			assert.Equal(t, tt.wantCheckpoint, session.Checkpoint)
+			assert.Empty(t, session.MilestoneID)
+			assert.True(t, session.MilestoneSkipped)
+		})
+	}
+}
+
+// stubAnswerReviewer records the evaluations it is asked to review
+type stubAnswerReviewer struct {
+	reviewed []string
+	choices  []Choice
+	err      error
+}
+
+func (s *stubAnswerReviewer) ReviewAnswer(ctx context.Context, evaluation *QuestionEvaluation) ([]Choice, error) {
+	s.reviewed = append(s.reviewed, evaluation.Question.ID)
+	return s.choices, s.err
+}
+
+func TestSubmitAnswers_AnswerReviewer(t *testing.T) {
+	override := []Choice{{ID: "q1_c", Title: "Overridden"}}
+	newEvaluations := func() []*QuestionEvaluation {
+		return []*QuestionEvaluation{
+			{Question: &WAFRQuestion{ID: "q1"}, SelectedChoices: []Choice{{ID: "q1_a"}}, ConfidenceScore: 0.4},
+			{Question: &WAFRQuestion{ID: "q2"}, SelectedChoices: []Choice{{ID: "q2_a"}}, ConfidenceScore: 0.9},
+		}
+	}
+
+	t.Run("overrides low-confidence choices", func(t *testing.T) {
+		submitted := make(map[string][]Choice)
+		sources := make(map[string]Source)
+		wafrEvaluator := &mockWAFREvaluator{
+			submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+				submitted[questionID] = evaluation.SelectedChoices
+				sources[questionID] = evaluation.Source
+				return nil
+			},
+		}
+		reviewer := &stubAnswerReviewer{choices: override}
+		engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+		engine.SetAnswerReviewer(reviewer, 0.7)
+
+		err := engine.submitAnswers(context.Background(), &ReviewSession{AWSWorkloadID: "aws-workload-123"}, newEvaluations())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"q1"}, reviewer.reviewed)
+		assert.Equal(t, override, submitted["q1"])
+		assert.Equal(t, []Choice{{ID: "q2_a"}}, submitted["q2"])
+		assert.Equal(t, SourceInteractive, sources["q1"])
+		assert.Empty(t, sources["q2"], "answers left alone keep their source")
+	})
+
+	t.Run("aborts when review fails", func(t *testing.T) {
+		submitCalls := 0
+		wafrEvaluator := &mockWAFREvaluator{
+			submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+				submitCalls++
+				return nil
+			},
+		}
+		engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+		engine.SetAnswerReviewer(&stubAnswerReviewer{err: errors.New("input closed")}, 0.7)
+
+		err := engine.submitAnswers(context.Background(), &ReviewSession{AWSWorkloadID: "aws-workload-123"}, newEvaluations())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "q1")
+		assert.Zero(t, submitCalls)
+	})
+}
+
+// stubSubmissionConfirmer records the evaluations it was asked to confirm
+type stubSubmissionConfirmer struct {
+	confirmed []string
+	err       error
+}
+
+func (s *stubSubmissionConfirmer) ConfirmSubmission(ctx context.Context, evaluations []*QuestionEvaluation) error {
+	for _, evaluation := range evaluations {
+		s.confirmed = append(s.confirmed, evaluation.Question.ID)
+	}
+	return s.err
+}
+
+func TestSubmitAnswers_SubmissionConfirmer(t *testing.T) {
+	newEvaluations := func() []*QuestionEvaluation {
+		return []*QuestionEvaluation{
+			{Question: &WAFRQuestion{ID: "q1"}, SelectedChoices: []Choice{{ID: "q1_a"}}, ConfidenceScore: 0.4},
+			{Question: &WAFRQuestion{ID: "q2"}, SelectedChoices: []Choice{{ID: "q2_a"}}, ConfidenceScore: 0.9},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		confirmErr      error
+		wantSubmissions int
+	}{
+		{name: "submits once confirmed", wantSubmissions: 2},
+		{name: "submits nothing when aborted", confirmErr: fmt.Errorf("%w by user", ErrSubmissionAborted)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			submitCalls := 0
+			wafrEvaluator := &mockWAFREvaluator{
+				submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+					mu.Lock()
+					submitCalls++
+					mu.Unlock()
+					return nil
+				},
+			}
+			confirmer := &stubSubmissionConfirmer{err: tt.confirmErr}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetSubmissionConfirmer(confirmer)
+
+			err := engine.submitAnswers(context.Background(), &ReviewSession{AWSWorkloadID: "aws-workload-123"}, newEvaluations())
+
+			if tt.confirmErr != nil {
+				assert.ErrorIs(t, err, ErrSubmissionAborted)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, []string{"q1", "q2"}, confirmer.confirmed)
+			assert.Equal(t, tt.wantSubmissions, submitCalls)
+		})
+	}
+}
+
+func TestResumeSession_AfterAbortedSubmission(t *testing.T) {
+	// Sessions are saved as JSON, so resuming reads back what was encoded
+	var saved []byte
+	sessionMgr := &mockSessionManager{
+		saveSessionFunc: func(ctx context.Context, session *ReviewSession) error {
+			var err error
+			saved, err = json.Marshal(session)
+			return err
+		},
+		loadSessionFunc: func(ctx context.Context, sessionID string) (*ReviewSession, error) {
+			var session ReviewSession
+			err := json.Unmarshal(saved, &session)
+			return &session, err
+		},
+	}
+	var submitted []string
+	wafrEvaluator := &mockWAFREvaluator{
+		submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+			submitted = append(submitted, questionID)
+			return nil
+		},
+	}
+
+	engine := NewEngine(sessionMgr, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetSubmissionConfirmer(&stubSubmissionConfirmer{err: ErrSubmissionAborted})
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	_, err := engine.ExecuteReview(context.Background(), session)
+	require.ErrorIs(t, err, ErrSubmissionAborted)
+	assert.Empty(t, submitted)
+
+	engine.SetSubmissionConfirmer(nil)
+	resumed, err := engine.ResumeSession(context.Background(), "test-session")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sec-1"}, submitted)
+	assert.Equal(t, "milestone_created", resumed.Checkpoint)
+	require.NotNil(t, resumed.Results)
+	assert.Len(t, resumed.Results.Evaluations, 1)
+}
+
+func TestResumeSession_NoSavedEvaluations(t *testing.T) {
+	sessionMgr := &mockSessionManager{
+		loadSessionFunc: func(ctx context.Context, sessionID string) (*ReviewSession, error) {
+			return &ReviewSession{
+				SessionID:     sessionID,
+				AWSWorkloadID: "aws-workload-123",
+				Status:        SessionStatusFailed,
+				Checkpoint:    "questions_evaluated",
+				WorkloadModel: &WorkloadModel{Framework: "terraform"},
+			}, nil
+		},
+	}
+	milestones := 0
+	wafrEvaluator := &mockWAFREvaluator{
+		createMilestoneFunc: func(ctx context.Context, awsWorkloadID string, milestoneName string) (string, error) {
+			milestones++
+			return "milestone-123", nil
+		},
+	}
+	engine := NewEngine(sessionMgr, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+
+	_, err := engine.ResumeSession(context.Background(), "test-session")
+
+	assert.ErrorContains(t, err, "no saved evaluations to submit")
+	assert.Zero(t, milestones)
+}
+
+// recordingProgressReporter records the current count of each progress report
+type recordingProgressReporter struct {
+	current []int
+}
+
+func (r *recordingProgressReporter) ReportStep(step string, message string) {}
+
+func (r *recordingProgressReporter) ReportProgress(current, total int, message string) {
+	r.current = append(r.current, current)
+}
+
+func (r *recordingProgressReporter) ReportCompletion(summary *ResultsSummary) {}
+
+func TestSubmitAnswers_Concurrency(t *testing.T) {
+	newEvaluations := func(n int) []*QuestionEvaluation {
+		evaluations := make([]*QuestionEvaluation, n)
+		for i := range evaluations {
+			evaluations[i] = &QuestionEvaluation{Question: &WAFRQuestion{ID: fmt.Sprintf("q%d", i+1)}}
+		}
+		return evaluations
+	}
+	session := &ReviewSession{AWSWorkloadID: "aws-workload-123"}
+
+	t.Run("submits every answer within the concurrency cap", func(t *testing.T) {
+		var mu sync.Mutex
+		inFlight, peak := 0, 0
+		submitted := make(map[string]bool)
+		wafrEvaluator := &mockWAFREvaluator{
+			submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+				mu.Lock()
+				inFlight++
+				peak = max(peak, inFlight)
+				submitted[questionID] = true
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				if questionID == "q3" || questionID == "q7" {
+					return errors.New("throttled")
+				}
+				return nil
+			},
+		}
+		engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+		engine.SetSubmitConcurrency(3)
+		progress := &recordingProgressReporter{}
+
+		err := engine.submitAnswersWithProgress(context.Background(), session, newEvaluations(10), progress)
+
+		require.NoError(t, err)
+		assert.Len(t, submitted, 10)
+		assert.LessOrEqual(t, peak, 3)
+		assert.Greater(t, peak, 1)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, progress.current)
+	})
+
+	t.Run("fails when no answer is submitted", func(t *testing.T) {
+		wafrEvaluator := &mockWAFREvaluator{
+			submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+				return errors.New("access denied")
+			},
+		}
+		engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+		engine.SetSubmitConcurrency(4)
+
+		err := engine.submitAnswers(context.Background(), session, newEvaluations(5))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to submit any answers")
+	})
+
+	t.Run("cancellation stops in-flight and pending submissions", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var calls atomic.Int32
+		wafrEvaluator := &mockWAFREvaluator{
+			submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+				if calls.Add(1) == 2 {
+					cancel()
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}
+		engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+		engine.SetSubmitConcurrency(2)
+
+		err := engine.submitAnswers(ctx, session, newEvaluations(10))
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+// lensVersionEvaluator adds lens version reporting to mockWAFREvaluator
+type lensVersionEvaluator struct {
+	*mockWAFREvaluator
+	version    string
+	err        error
+	current    string
+	currentErr error
+}
+
+func (m *lensVersionEvaluator) GetLensVersion(ctx context.Context, awsWorkloadID string) (string, error) {
+	return m.version, m.err
+}
+
+func (m *lensVersionEvaluator) GetCurrentLensVersion(ctx context.Context) (string, error) {
+	return m.current, m.currentErr
+}
+
+func TestInitiateReview_LensVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		// lens reports lens versions; nil for an evaluator that cannot
+		lens        *lensVersionEvaluator
+		pinned      string
+		wantVersion string
+		wantErr     error
+		wantCreated bool
+	}{
+		{
+			name:        "captures current version",
+			lens:        &lensVersionEvaluator{version: "2024-06-27"},
+			wantVersion: "2024-06-27",
+			wantCreated: true,
+		},
+		{
+			name:        "pinned version matches",
+			lens:        &lensVersionEvaluator{version: "2024-06-27", current: "2024-06-27"},
+			pinned:      "2024-06-27",
+			wantVersion: "2024-06-27",
+			wantCreated: true,
+		},
+		{
+			name:    "pinned version differs from new workloads",
+			lens:    &lensVersionEvaluator{version: "2025-02-25", current: "2025-02-25"},
+			pinned:  "2024-06-27",
+			wantErr: ErrLensVersionMismatch,
+		},
+		{
+			name:        "reused workload on another version",
+			lens:        &lensVersionEvaluator{version: "2023-10-03", current: "2024-06-27"},
+			pinned:      "2024-06-27",
+			wantErr:     ErrLensVersionMismatch,
+			wantCreated: true,
+		},
+		{
+			name:    "current version cannot be read",
+			lens:    &lensVersionEvaluator{currentErr: errors.New("access denied")},
+			pinned:  "2024-06-27",
+			wantErr: errors.New("failed to verify pinned lens version: access denied"),
+		},
+		{
+			name:        "capture failure is tolerated without a pin",
+			lens:        &lensVersionEvaluator{err: errors.New("access denied")},
+			wantCreated: true,
+		},
+		{
+			name:    "pin cannot be verified",
+			pinned:  "2024-06-27",
+			wantErr: ErrLensVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mock := &mockWAFREvaluator{
+				createWorkloadFunc: func(ctx context.Context, workloadID string, description string) (string, error) {
+					created = true
+					return "aws-workload-123", nil
+				},
+			}
+			var evaluator WAFREvaluator = mock
+			if tt.lens != nil {
+				tt.lens.mockWAFREvaluator = mock
+				evaluator = tt.lens
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, evaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetPinnedLensVersion(tt.pinned)
+
+			session, err := engine.InitiateReview(context.Background(), "test-workload", ReviewScope{Level: ScopeLevelWorkload})
+
+			// A pin that new workloads do not match fails before a
+			// workload is created
+			assert.Equal(t, tt.wantCreated, created)
+			if tt.wantErr != nil {
+				if errors.Is(tt.wantErr, ErrLensVersionMismatch) {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.EqualError(t, err, tt.wantErr.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, session.LensVersion)
+
+			output := ConvertReviewSessionToOutput(session)
+			assert.Equal(t, tt.wantVersion, output.LensVersion)
+		})
+	}
+}
+
+// answerReadingEvaluator adds existing answer reading to mockWAFREvaluator
+type answerReadingEvaluator struct {
+	*mockWAFREvaluator
+	existing map[string]ExistingAnswer
+}
+
+func (m *answerReadingEvaluator) GetExistingAnswers(ctx context.Context, awsWorkloadID string, questions []*WAFRQuestion) (map[string]ExistingAnswer, error) {
+	return m.existing, nil
+}
+
+func TestExecuteReview_PreserveAnswers(t *testing.T) {
+	questions := []*WAFRQuestion{
+		{ID: "sec_1", Pillar: PillarSecurity},
+		{ID: "sec_2", Pillar: PillarSecurity},
+		{ID: "rel_1", Pillar: PillarReliability},
+	}
+	existing := map[string]ExistingAnswer{
+		"sec_1": {SelectedChoices: []string{"sec_1_a"}, Notes: "Reviewed with the security team"},
+		"rel_1": {SelectedChoices: []string{"rel_1_a"}},
+	}
+
+	tests := []struct {
+		name          string
+		overwrite     bool
+		wantEvaluated []string
+		wantPreserved []string
+		wantNotes     map[string]string
+	}{
+		{
+			name:          "answered questions are skipped",
+			wantEvaluated: []string{"sec_2"},
+			wantPreserved: []string{"sec_1", "rel_1"},
+			wantNotes:     map[string]string{"sec_2": ""},
+		},
+		{
+			name:          "overwrite evaluates every question and keeps notes",
+			overwrite:     true,
+			wantEvaluated: []string{"sec_1", "sec_2", "rel_1"},
+			wantNotes:     map[string]string{"sec_1": "Reviewed with the security team", "sec_2": "", "rel_1": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evaluated []string
+			submittedNotes := make(map[string]string)
+			wafrEvaluator := &answerReadingEvaluator{
+				mockWAFREvaluator: &mockWAFREvaluator{
+					getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+						return questions, nil
+					},
+					evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+						evaluated = append(evaluated, question.ID)
+						return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+					},
+					submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+						submittedNotes[questionID] = evaluation.ExistingNotes
+						return nil
+					},
+				},
+				existing: existing,
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetPreserveAnswers(tt.overwrite)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			results, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEvaluated, evaluated)
+			assert.Equal(t, tt.wantNotes, submittedNotes)
+			assert.Equal(t, tt.wantPreserved, results.Summary.PreservedQuestions)
+			assert.Equal(t, len(questions), results.Summary.TotalQuestions)
+			assert.False(t, results.Summary.Partial)
+		})
+	}
+}
+
+func TestExecuteReview_AllAnswersPreserved(t *testing.T) {
+	questions := []*WAFRQuestion{
+		{ID: "sec_1", Pillar: PillarSecurity},
+		{ID: "rel_1", Pillar: PillarReliability},
+	}
+	planRetrieved := false
+	wafrEvaluator := &answerReadingEvaluator{
+		mockWAFREvaluator: &mockWAFREvaluator{
+			getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+				return questions, nil
+			},
+			evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+				t.Errorf("unexpected evaluation of %s", question.ID)
+				return nil, errors.New("unexpected evaluation")
+			},
+			submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+				t.Errorf("unexpected submission of %s", questionID)
+				return errors.New("unexpected submission")
+			},
+			getImprovementPlanFunc: func(ctx context.Context, awsWorkloadID string) (*ImprovementPlan, error) {
+				planRetrieved = true
+				return &ImprovementPlan{Items: []*ImprovementPlanItem{}}, nil
+			},
+		},
+		existing: map[string]ExistingAnswer{
+			"sec_1": {SelectedChoices: []string{"sec_1_a"}},
+			"rel_1": {SelectedChoices: []string{"rel_1_a"}},
+		},
+	}
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEvaluator, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetPreserveAnswers(false)
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	results, err := engine.ExecuteReview(context.Background(), session)
+
+	require.NoError(t, err)
+	assert.True(t, planRetrieved)
+	assert.Empty(t, results.Evaluations)
+	assert.Equal(t, []string{"sec_1", "rel_1"}, results.Summary.PreservedQuestions)
+	assert.Equal(t, len(questions), results.Summary.TotalQuestions)
+}
+
+func TestExecuteReview_PreserveAnswersUnsupported(t *testing.T) {
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetPreserveAnswers(false)
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	_, err := engine.ExecuteReview(context.Background(), session)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read existing answers")
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 var (
@@ -12,6 +14,18 @@ var (
 	// ErrQuestionIDRequired is returned when question scope is selected but no question ID is specified
 	ErrQuestionIDRequired = errors.New("question ID is required when scope level is question")
 
+	// ErrInvalidScopeLevel is returned when the scope level is not workload, pillar or question
+	ErrInvalidScopeLevel = errors.New("invalid scope level")
+
+	// ErrInvalidPillar is returned when a pillar is not one of the Well-Architected pillars
+	ErrInvalidPillar = errors.New("invalid pillar")
+
+	// ErrUnexpectedPillar is returned when a pillar is specified for a scope other than pillar
+	ErrUnexpectedPillar = errors.New("pillar is only valid when scope level is pillar")
+
+	// ErrUnexpectedQuestionID is returned when a question ID is specified for a scope other than question
+	ErrUnexpectedQuestionID = errors.New("question ID is only valid when scope level is question")
+
 	// ErrInvalidWorkloadID is returned when the workload ID is invalid
 	ErrInvalidWorkloadID = errors.New("invalid workload ID")
 
@@ -33,6 +47,16 @@ var (
 	// ErrMaxFilesExceeded is returned when the maximum file limit is exceeded
 	ErrMaxFilesExceeded = errors.New("exceeded maximum file limit")
 
+	// ErrEmptyWorkloadModel is returned when IaC analysis finds no resources to review
+	ErrEmptyWorkloadModel = errors.New("no resources found in workload")
+
+	// ErrStalePlan is returned when the plan file predates resources declared
+	// in the configuration
+	ErrStalePlan = errors.New("plan file is missing resources declared in the configuration")
+
+	// ErrWorkloadNotManaged is returned when deleting a workload that Waffle did not create
+	ErrWorkloadNotManaged = errors.New("workload is not managed by Waffle")
+
 	// ErrInvalidPlanFile is returned when the plan file is invalid
 	ErrInvalidPlanFile = errors.New("invalid plan file")
 
@@ -47,6 +71,21 @@ var (
 
 	// ErrInvalidSessionStatus is returned when session status is invalid for the operation
 	ErrInvalidSessionStatus = errors.New("invalid session status for operation")
+
+	// ErrSessionAlreadyExists is returned when a caller-supplied session ID is already in use
+	ErrSessionAlreadyExists = errors.New("session already exists")
+
+	// ErrInvalidSessionID is returned when a caller-supplied session ID has an invalid format
+	ErrInvalidSessionID = errors.New("invalid session ID")
+
+	// ErrSchemaVersionMismatch is returned when a JSON document does not match the expected schema version
+	ErrSchemaVersionMismatch = errors.New("schema version mismatch")
+
+	// ErrLensVersionMismatch is returned when the workload's lens version differs from the pinned version
+	ErrLensVersionMismatch = errors.New("lens version mismatch")
+
+	// ErrSubmissionAborted is returned when the proposed answers are not confirmed for submission
+	ErrSubmissionAborted = errors.New("answer submission aborted")
 )
 
 // DirectoryAccessError represents an error accessing the directory
@@ -157,6 +196,45 @@ func (e *WAFRAPIError) Unwrap() error {
 	return e.Err
 }
 
+// WAFRErrorKind classifies a WAFR API error by what the caller can do about it
+type WAFRErrorKind string
+
+const (
+	// WAFRErrorAccessDenied means the credentials were rejected or lack a
+	// wellarchitected permission
+	WAFRErrorAccessDenied WAFRErrorKind = "access_denied"
+	// WAFRErrorNotFound means the workload, lens or answer does not exist
+	WAFRErrorNotFound WAFRErrorKind = "not_found"
+	// WAFRErrorThrottled means requests were throttled beyond the retries
+	WAFRErrorThrottled WAFRErrorKind = "throttled"
+	// WAFRErrorInvalidRequest means the request was rejected as invalid
+	WAFRErrorInvalidRequest WAFRErrorKind = "invalid_request"
+	// WAFRErrorConflict means the request conflicts with the current state,
+	// such as a workload name already in use
+	WAFRErrorConflict WAFRErrorKind = "conflict"
+	// WAFRErrorUnknown covers every other error, including failures without
+	// an AWS error code such as network errors
+	WAFRErrorUnknown WAFRErrorKind = "unknown"
+)
+
+// Kind classifies the error by its AWS error code
+func (e *WAFRAPIError) Kind() WAFRErrorKind {
+	switch e.ErrorCode {
+	case "AccessDeniedException", "AccessDenied", "UnrecognizedClientException", "InvalidClientTokenId", "ExpiredTokenException":
+		return WAFRErrorAccessDenied
+	case "ResourceNotFoundException":
+		return WAFRErrorNotFound
+	case "ThrottlingException":
+		return WAFRErrorThrottled
+	case "ValidationException":
+		return WAFRErrorInvalidRequest
+	case "ConflictException":
+		return WAFRErrorConflict
+	default:
+		return WAFRErrorUnknown
+	}
+}
+
 // FileAccessError represents an error accessing a file
 type FileAccessError struct {
 	Path      string
@@ -185,3 +263,48 @@ func (e *ValidationError) Error() string {
 	}
 	return fmt.Sprintf("validation failed for %s: %s", e.Field, e.Message)
 }
+
+// PillarRetrievalError reports pillars whose questions could not be retrieved.
+// It is returned alongside the questions of the remaining pillars when a
+// review continues past pillar failures.
+type PillarRetrievalError struct {
+	Failures map[Pillar]error
+}
+
+func (e *PillarRetrievalError) Error() string {
+	pillars := make([]string, 0, len(e.Failures))
+	for pillar := range e.Failures {
+		pillars = append(pillars, string(pillar))
+	}
+	sort.Strings(pillars)
+	return fmt.Sprintf("failed to get questions for pillars: %s", strings.Join(pillars, ", "))
+}
+
+// BaselineExceededError is returned when a review has more risks than its
+// baseline allows. Pillars are in framework order.
+type BaselineExceededError struct {
+	Pillars []Pillar
+}
+
+func (e *BaselineExceededError) Error() string {
+	pillars := make([]string, 0, len(e.Pillars))
+	for _, pillar := range e.Pillars {
+		pillars = append(pillars, string(pillar))
+	}
+	return fmt.Sprintf("review exceeds baseline for pillars: %s", strings.Join(pillars, ", "))
+}
+
+// StrictModeError is returned in strict mode when IaC analysis recorded
+// warnings, such as a redacted secret or a file that was only partly parsed
+type StrictModeError struct {
+	Warnings []AnalysisWarning
+}
+
+func (e *StrictModeError) Error() string {
+	counts := make(map[AnalysisWarningKind]int)
+	for _, warning := range e.Warnings {
+		counts[warning.Kind]++
+	}
+	return fmt.Sprintf("strict mode: IaC analysis recorded %d redaction and %d parse warnings",
+		counts[AnalysisWarningRedaction], counts[AnalysisWarningParse])
+}
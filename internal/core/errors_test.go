@@ -140,6 +140,29 @@ func TestWAFRAPIError(t *testing.T) {
 	})
 }
 
+func TestWAFRAPIError_Kind(t *testing.T) {
+	tests := []struct {
+		errorCode string
+		want      WAFRErrorKind
+	}{
+		{errorCode: "AccessDeniedException", want: WAFRErrorAccessDenied},
+		{errorCode: "ExpiredTokenException", want: WAFRErrorAccessDenied},
+		{errorCode: "ResourceNotFoundException", want: WAFRErrorNotFound},
+		{errorCode: "ThrottlingException", want: WAFRErrorThrottled},
+		{errorCode: "ValidationException", want: WAFRErrorInvalidRequest},
+		{errorCode: "ConflictException", want: WAFRErrorConflict},
+		{errorCode: "InternalServerException", want: WAFRErrorUnknown},
+		{errorCode: "", want: WAFRErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.errorCode, func(t *testing.T) {
+			err := &WAFRAPIError{Operation: "ListAnswers", ErrorCode: tt.errorCode}
+			assert.Equal(t, tt.want, err.Kind())
+		})
+	}
+}
+
 func TestFileAccessError(t *testing.T) {
 	baseErr := errors.New("file not found")
 	err := &FileAccessError{
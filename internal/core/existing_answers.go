@@ -0,0 +1,52 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// applyExistingAnswers reads the answers the workload already has for
+// questions. Unless answers are overwritten, the answered questions are left
+// out and recorded on session as preserved. Otherwise every question is kept
+// and the notes of answered ones are recorded for submission.
+func (e *Engine) applyExistingAnswers(ctx context.Context, session *ReviewSession, questions []*WAFRQuestion) ([]*WAFRQuestion, error) {
+	reader, ok := e.wafrEvaluator.(ExistingAnswerReader)
+	if !ok {
+		return nil, errors.New("WAFR evaluator cannot read existing answers")
+	}
+
+	existing, err := reader.GetExistingAnswers(ctx, session.AWSWorkloadID, questions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing answers: %w", err)
+	}
+
+	if e.overwriteAnswers {
+		for questionID, answer := range existing {
+			if answer.Notes == "" {
+				continue
+			}
+			if session.ExistingNotes == nil {
+				session.ExistingNotes = make(map[string]string)
+			}
+			session.ExistingNotes[questionID] = answer.Notes
+		}
+		slog.InfoContext(ctx, "overwriting existing answers", "answered", len(existing))
+		return questions, nil
+	}
+
+	unanswered := make([]*WAFRQuestion, 0, len(questions))
+	for _, question := range questions {
+		if _, answered := existing[question.ID]; answered {
+			session.PreservedQuestions = append(session.PreservedQuestions, question.ID)
+			continue
+		}
+		unanswered = append(unanswered, question)
+	}
+	slog.InfoContext(ctx, "preserving existing answers",
+		"preserved", len(session.PreservedQuestions),
+		"to_evaluate", len(unanswered),
+	)
+	return unanswered, nil
+}
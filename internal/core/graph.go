@@ -0,0 +1,192 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Graph export formats
+const (
+	GraphFormatJSON = "json"
+	GraphFormatDOT  = "dot"
+)
+
+// GraphNode is a resource in an exported dependency graph
+type GraphNode struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+}
+
+// GraphEdge is a dependency in an exported graph: From depends on To
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GraphExport is the JSON form of a ResourceGraph. Nodes are sorted by
+// address and edges by source then target so exports are deterministic.
+type GraphExport struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// ValidateGraphFormat checks that format is a supported graph export format
+func ValidateGraphFormat(format string) error {
+	switch format {
+	case GraphFormatJSON, GraphFormatDOT:
+		return nil
+	default:
+		return fmt.Errorf("invalid graph format %q: must be %s or %s", format, GraphFormatJSON, GraphFormatDOT)
+	}
+}
+
+// Export converts the graph to its sorted export form
+func (g *ResourceGraph) Export() *GraphExport {
+	export := &GraphExport{
+		Nodes: []GraphNode{},
+		Edges: []GraphEdge{},
+	}
+	if g == nil {
+		return export
+	}
+
+	for address, resource := range g.Nodes {
+		node := GraphNode{Address: address}
+		if resource != nil {
+			node.Type = resource.Type
+		}
+		export.Nodes = append(export.Nodes, node)
+	}
+	sort.Slice(export.Nodes, func(i, j int) bool {
+		return export.Nodes[i].Address < export.Nodes[j].Address
+	})
+
+	for from, targets := range g.Edges {
+		for _, to := range targets {
+			export.Edges = append(export.Edges, GraphEdge{From: from, To: to})
+		}
+	}
+	sort.Slice(export.Edges, func(i, j int) bool {
+		if export.Edges[i].From != export.Edges[j].From {
+			return export.Edges[i].From < export.Edges[j].From
+		}
+		return export.Edges[i].To < export.Edges[j].To
+	})
+
+	return export
+}
+
+// Dependents returns the sorted addresses of the resources that depend,
+// directly or transitively, on any of the given resources. The given
+// resources themselves are not included.
+func (g *ResourceGraph) Dependents(addresses []string) []string {
+	if g == nil || len(addresses) == 0 {
+		return nil
+	}
+
+	// Edges point from a resource to its dependencies; walk them backwards
+	dependents := make(map[string][]string)
+	for from, targets := range g.Edges {
+		for _, to := range targets {
+			dependents[to] = append(dependents[to], from)
+		}
+	}
+
+	visited := make(map[string]bool, len(addresses))
+	queue := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if !visited[address] {
+			visited[address] = true
+			queue = append(queue, address)
+		}
+	}
+
+	var result []string
+	for len(queue) > 0 {
+		address := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[address] {
+			if visited[dependent] {
+				continue
+			}
+			visited[dependent] = true
+			result = append(result, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ResourceGraph rebuilds a graph from its export form. Nodes carry only the
+// address and type.
+func (e *GraphExport) ResourceGraph() *ResourceGraph {
+	graph := &ResourceGraph{
+		Nodes: make(map[string]*Resource, len(e.Nodes)),
+		Edges: make(map[string][]string),
+	}
+	for _, node := range e.Nodes {
+		graph.Nodes[node.Address] = &Resource{
+			ID:      node.Address,
+			Type:    node.Type,
+			Address: node.Address,
+		}
+	}
+	for _, edge := range e.Edges {
+		graph.Edges[edge.From] = append(graph.Edges[edge.From], edge.To)
+	}
+	return graph
+}
+
+// WriteGraph writes the graph in the given export format
+func WriteGraph(w io.Writer, graph *ResourceGraph, format string) error {
+	switch format {
+	case GraphFormatJSON:
+		return WriteGraphJSON(w, graph)
+	case GraphFormatDOT:
+		return WriteGraphDOT(w, graph)
+	default:
+		return ValidateGraphFormat(format)
+	}
+}
+
+// WriteGraphJSON writes the graph as JSON nodes and from/to edge pairs
+func WriteGraphJSON(w io.Writer, graph *ResourceGraph) error {
+	return WriteJSON(w, graph.Export())
+}
+
+// ReadGraphJSON reads a graph written by WriteGraphJSON
+func ReadGraphJSON(r io.Reader) (*ResourceGraph, error) {
+	var export GraphExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to decode graph JSON: %w", err)
+	}
+	return export.ResourceGraph(), nil
+}
+
+// WriteGraphDOT writes the graph in Graphviz DOT format
+func WriteGraphDOT(w io.Writer, graph *ResourceGraph) error {
+	export := graph.Export()
+
+	var b strings.Builder
+	b.WriteString("digraph resources {\n")
+	for _, node := range export.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotID(node.Address), dotID(node.Address+"\n"+node.Type))
+	}
+	for _, edge := range export.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotID(edge.From), dotID(edge.To))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotID quotes a string as a DOT identifier
+func dotID(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
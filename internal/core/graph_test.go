@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResourceGraph() *ResourceGraph {
+	return &ResourceGraph{
+		Nodes: map[string]*Resource{
+			"aws_s3_bucket.logs":            {ID: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Address: "aws_s3_bucket.logs"},
+			"aws_s3_bucket.data":            {ID: "aws_s3_bucket.data", Type: "aws_s3_bucket", Address: "aws_s3_bucket.data"},
+			"aws_kms_key.data":              {ID: "aws_kms_key.data", Type: "aws_kms_key", Address: "aws_kms_key.data"},
+			"aws_s3_bucket_logging.data":    {ID: "aws_s3_bucket_logging.data", Type: "aws_s3_bucket_logging", Address: "aws_s3_bucket_logging.data"},
+			"aws_s3_bucket_encryption.data": {ID: "aws_s3_bucket_encryption.data", Type: "aws_s3_bucket_encryption", Address: "aws_s3_bucket_encryption.data"},
+		},
+		Edges: map[string][]string{
+			"aws_s3_bucket_logging.data":    {"aws_s3_bucket.logs", "aws_s3_bucket.data"},
+			"aws_s3_bucket_encryption.data": {"aws_s3_bucket.data", "aws_kms_key.data"},
+		},
+	}
+}
+
+func TestResourceGraphExport_Sorted(t *testing.T) {
+	export := testResourceGraph().Export()
+
+	assert.Equal(t, []GraphNode{
+		{Address: "aws_kms_key.data", Type: "aws_kms_key"},
+		{Address: "aws_s3_bucket.data", Type: "aws_s3_bucket"},
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+		{Address: "aws_s3_bucket_encryption.data", Type: "aws_s3_bucket_encryption"},
+		{Address: "aws_s3_bucket_logging.data", Type: "aws_s3_bucket_logging"},
+	}, export.Nodes)
+	assert.Equal(t, []GraphEdge{
+		{From: "aws_s3_bucket_encryption.data", To: "aws_kms_key.data"},
+		{From: "aws_s3_bucket_encryption.data", To: "aws_s3_bucket.data"},
+		{From: "aws_s3_bucket_logging.data", To: "aws_s3_bucket.data"},
+		{From: "aws_s3_bucket_logging.data", To: "aws_s3_bucket.logs"},
+	}, export.Edges)
+}
+
+func TestWriteGraphJSON_RoundTrip(t *testing.T) {
+	graph := testResourceGraph()
+
+	var first bytes.Buffer
+	require.NoError(t, WriteGraphJSON(&first, graph))
+
+	decoded, err := ReadGraphJSON(bytes.NewReader(first.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, graph.Export(), decoded.Export())
+	assert.Len(t, decoded.Nodes, len(graph.Nodes))
+	assert.Equal(t, "aws_kms_key", decoded.Nodes["aws_kms_key.data"].Type)
+
+	// Re-encoding the decoded graph yields identical bytes
+	var second bytes.Buffer
+	require.NoError(t, WriteGraphJSON(&second, decoded))
+	assert.Equal(t, first.String(), second.String())
+}
+
+func TestWriteGraphJSON_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteGraphJSON(&buf, nil))
+	assert.JSONEq(t, `{"nodes": [], "edges": []}`, buf.String())
+
+	decoded, err := ReadGraphJSON(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, decoded.Nodes)
+	assert.Empty(t, decoded.Edges)
+}
+
+func TestReadGraphJSON_Invalid(t *testing.T) {
+	_, err := ReadGraphJSON(bytes.NewBufferString("not json"))
+	assert.Error(t, err)
+}
+
+func TestWriteGraphDOT(t *testing.T) {
+	graph := &ResourceGraph{
+		Nodes: map[string]*Resource{
+			"aws_s3_bucket.data":         {Type: "aws_s3_bucket"},
+			"aws_s3_bucket_logging.data": {Type: "aws_s3_bucket_logging"},
+		},
+		Edges: map[string][]string{
+			"aws_s3_bucket_logging.data": {"aws_s3_bucket.data"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGraphDOT(&buf, graph))
+
+	assert.Equal(t, `digraph resources {
+  "aws_s3_bucket.data" [label="aws_s3_bucket.data\naws_s3_bucket"];
+  "aws_s3_bucket_logging.data" [label="aws_s3_bucket_logging.data\naws_s3_bucket_logging"];
+  "aws_s3_bucket_logging.data" -> "aws_s3_bucket.data";
+}
+`, buf.String())
+}
+
+func TestWriteGraph_Format(t *testing.T) {
+	tests := []struct {
+		format  string
+		prefix  string
+		wantErr bool
+	}{
+		{format: GraphFormatJSON, prefix: "{"},
+		{format: GraphFormatDOT, prefix: "digraph"},
+		{format: "svg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteGraph(&buf, testResourceGraph(), tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, ValidateGraphFormat(tt.format))
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, ValidateGraphFormat(tt.format))
+			assert.Contains(t, buf.String(), tt.prefix)
+		})
+	}
+}
+
+func TestResourceGraphDependents(t *testing.T) {
+	graph := testResourceGraph()
+	graph.Edges["aws_cloudtrail.main"] = []string{"aws_s3_bucket_logging.data"}
+
+	tests := []struct {
+		name      string
+		addresses []string
+		want      []string
+	}{
+		{
+			name:      "transitive dependents",
+			addresses: []string{"aws_s3_bucket.logs"},
+			want:      []string{"aws_cloudtrail.main", "aws_s3_bucket_logging.data"},
+		},
+		{
+			name:      "dependents of several resources are counted once",
+			addresses: []string{"aws_s3_bucket.data", "aws_kms_key.data"},
+			want:      []string{"aws_cloudtrail.main", "aws_s3_bucket_encryption.data", "aws_s3_bucket_logging.data"},
+		},
+		{
+			name:      "given resources are not their own dependents",
+			addresses: []string{"aws_s3_bucket.data", "aws_s3_bucket_logging.data"},
+			want:      []string{"aws_cloudtrail.main", "aws_s3_bucket_encryption.data"},
+		},
+		{
+			name:      "nothing depends on it",
+			addresses: []string{"aws_cloudtrail.main"},
+		},
+		{
+			name: "no resources",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, graph.Dependents(tt.addresses))
+		})
+	}
+}
+
+func TestResourceGraphDependents_NilGraph(t *testing.T) {
+	var graph *ResourceGraph
+	assert.Empty(t, graph.Dependents([]string{"aws_s3_bucket.logs"}))
+}
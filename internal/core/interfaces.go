@@ -62,14 +62,33 @@ type IaCAnalyzer interface {
 	IdentifyRelationships(ctx context.Context, resources []Resource) (*ResourceGraph, error)
 }
 
+// AnalysisWarningReporter is optionally implemented by an IaCAnalyzer that
+// records the problems it worked around, such as redacted secrets
+type AnalysisWarningReporter interface {
+	// AnalysisWarnings returns the warnings recorded so far, in order
+	AnalysisWarnings() []AnalysisWarning
+}
+
+// PublicExposureDetector is optionally implemented by an IaCAnalyzer that
+// can tell which resources are reachable from the public internet
+type PublicExposureDetector interface {
+	// DetectPublicExposure returns the exposed resources, directly exposed
+	// ones first
+	DetectPublicExposure(resources []Resource, graph *ResourceGraph) []ExposedResource
+}
+
 // SessionManager manages review session lifecycle and persistence
 type SessionManager interface {
-	// CreateSession creates a new review session
+	// CreateSession creates a new review session. A non-empty sessionID is
+	// used instead of a generated ID and fails if that session already exists.
+	// A correlation ID attached to ctx with logging.WithCorrelationID is
+	// recorded on the session.
 	CreateSession(
 		ctx context.Context,
 		workloadID string,
 		scope ReviewScope,
 		awsWorkloadID string,
+		sessionID string,
 	) (*ReviewSession, error)
 
 	// SaveSession persists a session to storage
@@ -137,6 +156,49 @@ type WAFREvaluator interface {
 	) (string, error)
 }
 
+// LensVersionProvider is optionally implemented by a WAFREvaluator that can
+// report which version of the lens a workload is reviewed against
+type LensVersionProvider interface {
+	// GetLensVersion returns the lens version applied to a workload
+	GetLensVersion(ctx context.Context, awsWorkloadID string) (string, error)
+
+	// GetCurrentLensVersion returns the lens version new workloads are
+	// created with
+	GetCurrentLensVersion(ctx context.Context) (string, error)
+}
+
+// WorkloadDeleter is optionally implemented by a WAFREvaluator that can remove
+// workloads it created
+type WorkloadDeleter interface {
+	// DeleteWorkload deletes a Waffle-managed workload. Workloads that Waffle
+	// did not create are left in place and ErrWorkloadNotManaged is returned.
+	DeleteWorkload(ctx context.Context, awsWorkloadID string) error
+}
+
+// WorkloadDescriptionUpdater is optionally implemented by a WAFREvaluator that
+// can change the description of an existing workload
+type WorkloadDescriptionUpdater interface {
+	// UpdateWorkloadDescription replaces the description of a workload in AWS
+	UpdateWorkloadDescription(
+		ctx context.Context,
+		awsWorkloadID string,
+		description string,
+	) error
+}
+
+// ExistingAnswerReader is optionally implemented by a WAFREvaluator that can
+// read the answers a workload already has, so a review can leave answers
+// given by people in place
+type ExistingAnswerReader interface {
+	// GetExistingAnswers returns the current answer of each of questions
+	// that is answered, keyed by question ID
+	GetExistingAnswers(
+		ctx context.Context,
+		awsWorkloadID string,
+		questions []*WAFRQuestion,
+	) (map[string]ExistingAnswer, error)
+}
+
 // BedrockClient provides access to Amazon Bedrock foundation models
 type BedrockClient interface {
 	// AnalyzeIaCSemantics analyzes IaC resources for semantic understanding
@@ -204,4 +266,42 @@ type ReportFormat string
 const (
 	ReportFormatPDF  ReportFormat = "pdf"
 	ReportFormatJSON ReportFormat = "json"
+	// ReportFormatConsolidatedJSON is the metrics of the AWS consolidated
+	// report as JSON
+	ReportFormatConsolidatedJSON ReportFormat = "consolidated-json"
 )
+
+// ConsolidatedReportJSONGenerator is optionally implemented by a
+// ReportGenerator that can retrieve the consolidated report metrics of a
+// workload
+type ConsolidatedReportJSONGenerator interface {
+	// GetConsolidatedReportJSON returns the risk counts of a workload per
+	// lens, pillar and question from the consolidated report
+	GetConsolidatedReportJSON(ctx context.Context, awsWorkloadID string) (*ConsolidatedReport, error)
+}
+
+// AnswerReviewer lets a person confirm or override the choices selected for a
+// low-confidence evaluation before it is submitted
+type AnswerReviewer interface {
+	// ReviewAnswer returns the choices to submit for the evaluation
+	ReviewAnswer(ctx context.Context, evaluation *QuestionEvaluation) ([]Choice, error)
+}
+
+// SubmissionConfirmer lets a person approve the full set of proposed answers
+// before any of them is submitted
+type SubmissionConfirmer interface {
+	// ConfirmSubmission returns nil to submit the evaluations, or an error
+	// wrapping ErrSubmissionAborted to submit none of them
+	ConfirmSubmission(ctx context.Context, evaluations []*QuestionEvaluation) error
+}
+
+// QuestionCache persists the questions retrieved for a review so later
+// reviews of the same workload can skip retrieving them
+type QuestionCache interface {
+	// LoadQuestions returns the questions stored under key, or nil when
+	// there are none
+	LoadQuestions(ctx context.Context, key QuestionCacheKey) (*CachedQuestions, error)
+
+	// StoreQuestions replaces the questions stored under key
+	StoreQuestions(ctx context.Context, key QuestionCacheKey, entry *CachedQuestions) error
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Inventory output formats
+const (
+	InventoryFormatCSV  = "csv"
+	InventoryFormatJSON = "json"
+)
+
+// inventoryCSVHeader is the header row of the CSV inventory
+var inventoryCSVHeader = []string{"address", "type", "provider", "region", "module", "source_file", "properties", "kind"}
+
+// InventoryItem is one resource in a resource inventory
+type InventoryItem struct {
+	Address    string
+	Type       string
+	Kind       ResourceKind
+	Provider   string
+	Region     string
+	Module     string
+	SourceFile string
+	// Properties holds the scalar top-level properties of the resource as
+	// left by redaction. Nested blocks are left out.
+	Properties map[string]interface{}
+}
+
+// InventoryOutput is the JSON form of a resource inventory
+type InventoryOutput struct {
+	ResourceCount int                   `json:"resource_count"`
+	Resources     []InventoryItemOutput `json:"resources"`
+}
+
+// InventoryItemOutput is the JSON form of an inventory item
+type InventoryItemOutput struct {
+	Address    string                 `json:"address"`
+	Type       string                 `json:"type"`
+	Kind       string                 `json:"kind,omitempty"`
+	Provider   string                 `json:"provider"`
+	Region     string                 `json:"region,omitempty"`
+	Module     string                 `json:"module,omitempty"`
+	SourceFile string                 `json:"source_file,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// InventoryRegions resolves the region of resources that do not set one
+type InventoryRegions struct {
+	// Providers maps provider configurations, such as aws or aws.west, to
+	// their region
+	Providers map[string]string
+	// Default is the configured AWS region, used when the provider
+	// configuration of a resource has none
+	Default string
+}
+
+// region returns the region of resource: its own region property, else the
+// region of its provider configuration, else the default
+func (r InventoryRegions) region(resource Resource) string {
+	if region, ok := resource.Properties["region"].(string); ok && region != "" {
+		return region
+	}
+	if region := r.Providers[resourceProviderConfig(resource)]; region != "" {
+		return region
+	}
+	return r.Default
+}
+
+// BuildInventory lists the managed resources sorted by address. Data sources
+// only read existing infrastructure and are left out. Resources without a
+// region of their own take it from regions.
+func BuildInventory(resources []Resource, regions InventoryRegions) []InventoryItem {
+	items := make([]InventoryItem, 0, len(resources))
+	for _, resource := range resources {
+		if resource.IsDataSource() {
+			continue
+		}
+
+		item := InventoryItem{
+			Address:    resource.Address,
+			Type:       resource.Type,
+			Kind:       resource.Kind,
+			Provider:   resourceProvider(resource.Type),
+			Region:     regions.region(resource),
+			Module:     resource.ModulePath,
+			SourceFile: resource.SourceFile,
+			Properties: make(map[string]interface{}),
+		}
+		for key, value := range resource.Properties {
+			switch value.(type) {
+			case string, bool, int, int64, float64:
+				item.Properties[key] = value
+			}
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Address < items[j].Address
+	})
+	return items
+}
+
+// resourceProvider returns the provider of a resource type, which Terraform
+// takes from the type name up to the first underscore
+func resourceProvider(resourceType string) string {
+	provider, _, _ := strings.Cut(resourceType, "_")
+	return provider
+}
+
+// resourceProviderConfig returns the provider configuration a resource uses:
+// the one named by its provider argument, such as aws.west, or the default
+// configuration of its provider
+func resourceProviderConfig(resource Resource) string {
+	if provider, ok := resource.Properties["provider"].(string); ok && provider != "" {
+		return strings.TrimSuffix(strings.TrimPrefix(provider, "${"), "}")
+	}
+	return resourceProvider(resource.Type)
+}
+
+// WriteInventoryCSV writes the inventory as CSV with a header row. The
+// properties column holds key=value pairs sorted by key and separated by "; ".
+func WriteInventoryCSV(w io.Writer, items []InventoryItem) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(inventoryCSVHeader); err != nil {
+		return err
+	}
+	for _, item := range items {
+		record := []string{
+			item.Address,
+			item.Type,
+			item.Provider,
+			item.Region,
+			item.Module,
+			item.SourceFile,
+			formatInventoryProperties(item.Properties),
+			string(item.Kind),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatInventoryProperties renders properties as sorted key=value pairs
+func formatInventoryProperties(properties map[string]interface{}) string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, properties[key]))
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// WriteInventoryJSON writes the inventory as indented JSON
+func WriteInventoryJSON(w io.Writer, items []InventoryItem) error {
+	output := InventoryOutput{
+		ResourceCount: len(items),
+		Resources:     make([]InventoryItemOutput, 0, len(items)),
+	}
+	for _, item := range items {
+		resource := InventoryItemOutput{
+			Address:    item.Address,
+			Type:       item.Type,
+			Kind:       string(item.Kind),
+			Provider:   item.Provider,
+			Region:     item.Region,
+			Module:     item.Module,
+			SourceFile: item.SourceFile,
+		}
+		if len(item.Properties) > 0 {
+			resource.Properties = item.Properties
+		}
+		output.Resources = append(output.Resources, resource)
+	}
+	return WriteJSON(w, output)
+}
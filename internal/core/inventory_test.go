@@ -0,0 +1,150 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func inventoryResources() []Resource {
+	return []Resource{
+		{
+			Address:    "module.data.aws_s3_bucket.logs",
+			Type:       "aws_s3_bucket",
+			ModulePath: "module.data",
+			Kind:       ResourceKindManaged,
+			SourceFile: "modules/data/main.tf",
+			Properties: map[string]interface{}{
+				"bucket":        "acme-logs",
+				"region":        "eu-west-1",
+				"force_destroy": false,
+				"tags":          map[string]interface{}{"Team": "sec"},
+			},
+		},
+		{
+			Address:    "aws_db_instance.main",
+			Type:       "aws_db_instance",
+			Kind:       ResourceKindManaged,
+			SourceFile: "main.tf",
+			Properties: map[string]interface{}{
+				"engine":            "postgres",
+				"password":          "[REDACTED]",
+				"allocated_storage": float64(20),
+			},
+		},
+		{Address: "data.aws_ami.ubuntu", Type: "aws_ami", Kind: ResourceKindData},
+		{Address: "google_storage_bucket.assets", Type: "google_storage_bucket"},
+	}
+}
+
+func TestBuildInventory(t *testing.T) {
+	items := BuildInventory(inventoryResources(), InventoryRegions{})
+
+	require.Len(t, items, 3, "data sources are left out")
+	assert.Equal(t, []InventoryItem{
+		{
+			Address:    "aws_db_instance.main",
+			Type:       "aws_db_instance",
+			Kind:       ResourceKindManaged,
+			Provider:   "aws",
+			SourceFile: "main.tf",
+			Properties: map[string]interface{}{"engine": "postgres", "password": "[REDACTED]", "allocated_storage": float64(20)},
+		},
+		{
+			Address:    "google_storage_bucket.assets",
+			Type:       "google_storage_bucket",
+			Provider:   "google",
+			Properties: map[string]interface{}{},
+		},
+		{
+			Address:    "module.data.aws_s3_bucket.logs",
+			Type:       "aws_s3_bucket",
+			Kind:       ResourceKindManaged,
+			Provider:   "aws",
+			Region:     "eu-west-1",
+			Module:     "module.data",
+			SourceFile: "modules/data/main.tf",
+			Properties: map[string]interface{}{"bucket": "acme-logs", "region": "eu-west-1", "force_destroy": false},
+		},
+	}, items)
+}
+
+func TestBuildInventory_RegionFallback(t *testing.T) {
+	regions := InventoryRegions{
+		Providers: map[string]string{"aws": "eu-west-1", "aws.west": "us-west-2"},
+		Default:   "eu-central-1",
+	}
+
+	tests := []struct {
+		name     string
+		resource Resource
+		want     string
+	}{
+		{
+			name:     "own region",
+			resource: Resource{Address: "aws_s3_bucket.a", Type: "aws_s3_bucket", Properties: map[string]interface{}{"region": "ap-south-1"}},
+			want:     "ap-south-1",
+		},
+		{
+			name:     "aliased provider",
+			resource: Resource{Address: "aws_s3_bucket.b", Type: "aws_s3_bucket", Properties: map[string]interface{}{"provider": "${aws.west}"}},
+			want:     "us-west-2",
+		},
+		{
+			name:     "default provider",
+			resource: Resource{Address: "aws_s3_bucket.c", Type: "aws_s3_bucket"},
+			want:     "eu-west-1",
+		},
+		{
+			name:     "configured region",
+			resource: Resource{Address: "awscc_s3_bucket.d", Type: "awscc_s3_bucket"},
+			want:     "eu-central-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := BuildInventory([]Resource{tt.resource}, regions)
+
+			require.Len(t, items, 1)
+			assert.Equal(t, tt.want, items[0].Region)
+		})
+	}
+}
+
+func TestWriteInventoryCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteInventoryCSV(&buf, BuildInventory(inventoryResources(), InventoryRegions{})))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"address", "type", "provider", "region", "module", "source_file", "properties", "kind"},
+		{"aws_db_instance.main", "aws_db_instance", "aws", "", "", "main.tf", "allocated_storage=20; engine=postgres; password=[REDACTED]", "managed"},
+		{"google_storage_bucket.assets", "google_storage_bucket", "google", "", "", "", "", ""},
+		{"module.data.aws_s3_bucket.logs", "aws_s3_bucket", "aws", "eu-west-1", "module.data", "modules/data/main.tf", "bucket=acme-logs; force_destroy=false; region=eu-west-1", "managed"},
+	}, records)
+}
+
+func TestWriteInventoryJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteInventoryJSON(&buf, BuildInventory(inventoryResources(), InventoryRegions{})))
+
+	var output InventoryOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+	assert.Equal(t, 3, output.ResourceCount)
+	require.Len(t, output.Resources, 3)
+	assert.Equal(t, "aws_db_instance.main", output.Resources[0].Address)
+	assert.Equal(t, "managed", output.Resources[0].Kind)
+	assert.Empty(t, output.Resources[1].Kind)
+	assert.Nil(t, output.Resources[1].Properties)
+	assert.Equal(t, "eu-west-1", output.Resources[2].Region)
+
+	buf.Reset()
+	require.NoError(t, WriteInventoryJSON(&buf, nil))
+	assert.JSONEq(t, `{"resource_count": 0, "resources": []}`, buf.String())
+}
@@ -4,9 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 )
 
+// SchemaVersion is the version of the JSON output contract. It must be bumped
+// whenever a field is removed, renamed or changes type in ReviewOutput,
+// StatusOutput or the results JSON.
+const SchemaVersion = "1.0"
+
 // JSONOutput represents the standard JSON output structure for all commands
 type JSONOutput struct {
 	Success bool        `json:"success"`
@@ -22,32 +28,161 @@ type JSONError struct {
 
 // ReviewOutput represents the JSON output for the review command
 type ReviewOutput struct {
-	SessionID  string                 `json:"session_id"`
-	WorkloadID string                 `json:"workload_id"`
-	Status     string                 `json:"status"`
-	CreatedAt  time.Time              `json:"created_at"`
-	Summary    *ReviewSummaryOutput   `json:"summary,omitempty"`
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	SchemaVersion  string                  `json:"schema_version"`
+	SessionID      string                  `json:"session_id"`
+	CorrelationID  string                  `json:"correlation_id,omitempty"`
+	WorkloadID     string                  `json:"workload_id"`
+	LensVersion    string                  `json:"lens_version,omitempty"`
+	Status         string                  `json:"status"`
+	CreatedAt      time.Time               `json:"created_at"`
+	Summary        *ReviewSummaryOutput    `json:"summary,omitempty"`
+	Drift          []PropertyDriftOutput   `json:"drift,omitempty"`
+	PublicExposure []ExposedResourceOutput `json:"public_exposure,omitempty"`
+	Baseline       *BaselineOutput         `json:"baseline,omitempty"`
+	Calibration    *CalibrationOutput      `json:"calibration,omitempty"`
+	CoverageMatrix *CoverageMatrixOutput   `json:"coverage_matrix,omitempty"`
+	Suppressions   []SuppressionOutput     `json:"suppressions,omitempty"`
+	Metadata       map[string]interface{}  `json:"metadata,omitempty"`
+}
+
+// CoverageMatrixOutput links resources to the questions that cited them
+type CoverageMatrixOutput struct {
+	Resources []ResourceCoverageOutput `json:"resources"`
+	// Uncited lists resources that influenced no answer
+	Uncited []string `json:"uncited"`
+}
+
+// ResourceCoverageOutput lists the questions whose evidence cited a resource
+type ResourceCoverageOutput struct {
+	Address   string   `json:"address"`
+	Questions []string `json:"questions"`
+}
+
+// CalibrationOutput is the distribution of confidence scores across a review
+type CalibrationOutput struct {
+	Questions         int                                `json:"questions"`
+	AverageConfidence float64                            `json:"average_confidence"`
+	Bands             []CalibrationBandOutput            `json:"bands"`
+	Pillars           map[string]PillarCalibrationOutput `json:"pillars"`
+}
+
+// CalibrationBandOutput counts the questions with a confidence score in
+// [min, max)
+type CalibrationBandOutput struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// PillarCalibrationOutput holds the confidence statistics of one pillar
+type PillarCalibrationOutput struct {
+	Questions         int     `json:"questions"`
+	AverageConfidence float64 `json:"average_confidence"`
+}
+
+// BaselineOutput represents a comparison against a risk baseline in JSON format
+type BaselineOutput struct {
+	File        string                     `json:"file"`
+	Passed      bool                       `json:"passed"`
+	Regressions []BaselineRegressionOutput `json:"regressions"`
+	// Advisory lists regressions in advisory pillars, which do not fail
+	Advisory []BaselineRegressionOutput `json:"advisory,omitempty"`
+}
+
+// BaselineRegressionOutput represents a pillar metric over its baseline in JSON format
+type BaselineRegressionOutput struct {
+	Pillar   string `json:"pillar"`
+	Metric   string `json:"metric"`
+	Baseline int    `json:"baseline"`
+	Actual   int    `json:"actual"`
+}
+
+// TimingsOutput represents the step durations of a review in JSON format
+type TimingsOutput struct {
+	Steps   []StepTimingOutput `json:"steps"`
+	TotalMS int64              `json:"total_ms"`
+}
+
+// StepTimingOutput represents one review step in JSON format. OffsetMS is
+// when the step started, relative to the start of the review.
+type StepTimingOutput struct {
+	Step       string `json:"step"`
+	OffsetMS   int64  `json:"offset_ms"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// PropertyDriftOutput represents a configuration/plan property mismatch in JSON format
+type PropertyDriftOutput struct {
+	Address     string      `json:"address"`
+	Property    string      `json:"property"`
+	ConfigValue interface{} `json:"config_value"`
+	PlanValue   interface{} `json:"plan_value"`
+}
+
+// ExposedResourceOutput is a resource reachable from the public internet
+type ExposedResourceOutput struct {
+	Address string   `json:"address"`
+	Type    string   `json:"type"`
+	Reason  string   `json:"reason"`
+	Path    []string `json:"path,omitempty"`
+}
+
+// DestructiveChangeOutput is a planned delete or replacement of a stateful
+// resource, listed under metadata.destructive_changes
+type DestructiveChangeOutput struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Action  string `json:"action"`
+}
+
+// AdvisoryOutput is a workload-level finding, listed under metadata.advisories
+type AdvisoryOutput struct {
+	Rule    string `json:"rule"`
+	Pillar  string `json:"pillar"`
+	Source  string `json:"source"`
+	Message string `json:"message"`
 }
 
 // ReviewSummaryOutput represents a summary of the review for JSON output
 type ReviewSummaryOutput struct {
-	QuestionsEvaluated  int     `json:"questions_evaluated"`
-	HighRisks           int     `json:"high_risks"`
-	MediumRisks         int     `json:"medium_risks"`
-	AverageConfidence   float64 `json:"average_confidence"`
-	ImprovementPlanSize int     `json:"improvement_plan_size"`
+	QuestionsEvaluated int     `json:"questions_evaluated"`
+	HighRisks          int     `json:"high_risks"`
+	MediumRisks        int     `json:"medium_risks"`
+	AverageConfidence  float64 `json:"average_confidence"`
+	// SeverityWeightedConfidence weights each question's confidence by its
+	// Well-Architected Tool severity
+	SeverityWeightedConfidence float64 `json:"severity_weighted_confidence"`
+	ImprovementPlanSize        int     `json:"improvement_plan_size"`
+	Partial                    bool    `json:"partial,omitempty"`
+	QuestionsSkipped           int     `json:"questions_skipped,omitempty"`
+	// TimedOutQuestions lists questions whose evaluation timed out
+	TimedOutQuestions []string `json:"timed_out_questions,omitempty"`
+	// PreservedQuestions lists questions left unevaluated because they
+	// were already answered
+	PreservedQuestions []string `json:"preserved_questions,omitempty"`
+
+	PillarSummaries map[string]*PillarSummaryOutput `json:"pillar_summaries,omitempty"`
+}
+
+// PillarSummaryOutput represents the per-pillar summary in JSON format
+type PillarSummaryOutput struct {
+	QuestionsEvaluated int     `json:"questions_evaluated"`
+	HighRisks          int     `json:"high_risks"`
+	MediumRisks        int     `json:"medium_risks"`
+	AverageConfidence  float64 `json:"average_confidence"`
+	Advisory           bool    `json:"advisory,omitempty"`
 }
 
 // StatusOutput represents the JSON output for the status command
 type StatusOutput struct {
-	SessionID  string                 `json:"session_id"`
-	WorkloadID string                 `json:"workload_id"`
-	Status     string                 `json:"status"`
-	CreatedAt  time.Time              `json:"created_at"`
-	UpdatedAt  time.Time              `json:"updated_at"`
-	Progress   *ProgressOutput        `json:"progress,omitempty"`
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	SchemaVersion string                 `json:"schema_version"`
+	SessionID     string                 `json:"session_id"`
+	WorkloadID    string                 `json:"workload_id"`
+	Status        string                 `json:"status"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	Progress      *ProgressOutput        `json:"progress,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // ProgressOutput represents progress information for JSON output
@@ -60,20 +195,23 @@ type ProgressOutput struct {
 
 // ResultsOutput represents the JSON output for the results command
 type ResultsOutput struct {
-	SessionID     string                  `json:"session_id"`
-	WorkloadID    string                  `json:"workload_id"`
-	AWSWorkloadID string                  `json:"aws_workload_id,omitempty"`
-	MilestoneID   string                  `json:"milestone_id,omitempty"`
-	Status        string                  `json:"status"`
-	CreatedAt     time.Time               `json:"created_at"`
-	CompletedAt   time.Time               `json:"completed_at,omitempty"`
-	Scope         *ScopeOutput            `json:"scope"`
-	Summary       *ReviewSummaryOutput    `json:"summary"`
-	Evaluations   []*EvaluationOutput     `json:"evaluations,omitempty"`
-	Risks         []*RiskOutput           `json:"risks,omitempty"`
-	Improvements  []*ImprovementOutput    `json:"improvements,omitempty"`
-	Resources     []*ResourceOutput       `json:"resources,omitempty"`
-	Links         map[string]string       `json:"links,omitempty"`
+	SchemaVersion string               `json:"schema_version"`
+	SessionID     string               `json:"session_id"`
+	WorkloadID    string               `json:"workload_id"`
+	AWSWorkloadID string               `json:"aws_workload_id,omitempty"`
+	MilestoneID   string               `json:"milestone_id,omitempty"`
+	LensVersion   string               `json:"lens_version,omitempty"`
+	Status        string               `json:"status"`
+	CreatedAt     time.Time            `json:"created_at"`
+	CompletedAt   time.Time            `json:"completed_at,omitempty"`
+	Scope         *ScopeOutput         `json:"scope"`
+	Summary       *ReviewSummaryOutput `json:"summary"`
+	Evaluations   []*EvaluationOutput  `json:"evaluations,omitempty"`
+	Risks         []*RiskOutput        `json:"risks,omitempty"`
+	Suppressions  []SuppressionOutput  `json:"suppressions,omitempty"`
+	Improvements  []*ImprovementOutput `json:"improvements,omitempty"`
+	Resources     []*ResourceOutput    `json:"resources,omitempty"`
+	Links         map[string]string    `json:"links,omitempty"`
 }
 
 // ScopeOutput represents the review scope for JSON output
@@ -92,6 +230,14 @@ type EvaluationOutput struct {
 	Evidence        []*EvidenceOutput `json:"evidence,omitempty"`
 	ConfidenceScore float64           `json:"confidence_score"`
 	Notes           string            `json:"notes,omitempty"`
+	ParseRetries    int               `json:"parse_retries,omitempty"`
+	TimedOut        bool              `json:"timed_out,omitempty"`
+	Overridden      bool              `json:"overridden,omitempty"`
+	NotApplicable   bool              `json:"not_applicable,omitempty"`
+	Source          string            `json:"source,omitempty"`
+	// ConsideredResources lists the resources the question was evaluated
+	// against when it was not evaluated against the whole workload
+	ConsideredResources []string `json:"considered_resources,omitempty"`
 }
 
 // EvidenceOutput represents evidence for JSON output
@@ -108,9 +254,18 @@ type RiskOutput struct {
 	QuestionID           string   `json:"question_id"`
 	Pillar               string   `json:"pillar"`
 	Severity             string   `json:"severity"`
+	OriginalSeverity     string   `json:"original_severity,omitempty"`
 	Description          string   `json:"description"`
 	AffectedResources    []string `json:"affected_resources"`
 	MissingBestPractices []string `json:"missing_best_practices"`
+	Source               string   `json:"source,omitempty"`
+}
+
+// SuppressionOutput records a risk ignored by a resource annotation
+type SuppressionOutput struct {
+	QuestionID string `json:"question_id"`
+	Resource   string `json:"resource"`
+	Reason     string `json:"reason,omitempty"`
 }
 
 // ImprovementOutput represents an improvement plan item for JSON output
@@ -122,6 +277,8 @@ type ImprovementOutput struct {
 	AffectedResources []string `json:"affected_resources"`
 	Priority          int      `json:"priority"`
 	EstimatedEffort   string   `json:"estimated_effort"`
+	BlastRadius       int      `json:"blast_radius"`
+	Remediation       string   `json:"remediation,omitempty"`
 }
 
 // ResourceOutput represents a resource for JSON output
@@ -132,7 +289,19 @@ type ResourceOutput struct {
 	SourceFile string                 `json:"source_file,omitempty"`
 	IsFromPlan bool                   `json:"is_from_plan"`
 	ModulePath string                 `json:"module_path,omitempty"`
+	Kind       string                 `json:"kind,omitempty"`
 	Properties map[string]interface{} `json:"properties,omitempty"`
+	Hints      []ResourceHintOutput   `json:"hints,omitempty"`
+	// Fingerprint changes when the type or properties of the resource do
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// ResourceHintOutput represents a static inspection hint for JSON output
+type ResourceHintOutput struct {
+	Rule     string `json:"rule"`
+	Pillar   string `json:"pillar"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
 }
 
 // WriteJSON writes a JSON output to the specified writer
@@ -142,6 +311,26 @@ func WriteJSON(w io.Writer, data interface{}) error {
 	return encoder.Encode(data)
 }
 
+// ValidateSchemaVersion reads a JSON document and checks that its
+// schema_version field matches SchemaVersion
+func ValidateSchemaVersion(r io.Reader) error {
+	var doc struct {
+		SchemaVersion *string `json:"schema_version"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	if doc.SchemaVersion == nil {
+		return fmt.Errorf("%w: schema_version field is missing", ErrSchemaVersionMismatch)
+	}
+	if *doc.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("%w: expected %s, got %s", ErrSchemaVersionMismatch, SchemaVersion, *doc.SchemaVersion)
+	}
+
+	return nil
+}
+
 // WriteJSONSuccess writes a successful JSON response
 func WriteJSONSuccess(w io.Writer, data interface{}) error {
 	output := JSONOutput{
@@ -163,26 +352,297 @@ func WriteJSONError(w io.Writer, code, message string) error {
 	return WriteJSON(w, output)
 }
 
+// Improvement plan statuses recorded as improvement_plan_status in output
+// metadata
+const (
+	// ImprovementPlanStatusOK means the plan was retrieved and has items
+	ImprovementPlanStatusOK = "ok"
+	// ImprovementPlanStatusEmpty means the plan was retrieved and has no
+	// items, as there were no risks to improve on
+	ImprovementPlanStatusEmpty = "empty"
+	// ImprovementPlanStatusError means the plan could not be retrieved and
+	// is empty for that reason
+	ImprovementPlanStatusError = "error"
+)
+
+// AddImprovementPlanMetadata records in metadata whether the improvement
+// plan was retrieved, so that an empty plan from a failed retrieval is not
+// read as nothing to improve. planErr is the session's ImprovementPlanError.
+// Nothing is recorded before the plan was retrieved.
+func AddImprovementPlanMetadata(metadata map[string]interface{}, plan *ImprovementPlan, planErr string) {
+	switch {
+	case planErr != "":
+		metadata["improvement_plan_status"] = ImprovementPlanStatusError
+		metadata["improvement_plan_error"] = planErr
+	case plan == nil:
+		return
+	case len(plan.Items) == 0:
+		metadata["improvement_plan_status"] = ImprovementPlanStatusEmpty
+	default:
+		metadata["improvement_plan_status"] = ImprovementPlanStatusOK
+	}
+}
+
 // ConvertReviewSessionToOutput converts a ReviewSession to ReviewOutput
 func ConvertReviewSessionToOutput(session *ReviewSession) *ReviewOutput {
 	output := &ReviewOutput{
-		SessionID:  session.SessionID,
-		WorkloadID: session.WorkloadID,
-		Status:     string(session.Status),
-		CreatedAt:  session.CreatedAt,
+		SchemaVersion: SchemaVersion,
+		SessionID:     session.SessionID,
+		CorrelationID: session.CorrelationID,
+		WorkloadID:    session.WorkloadID,
+		LensVersion:   session.LensVersion,
+		Status:        string(session.Status),
+		CreatedAt:     session.CreatedAt,
 		Metadata: map[string]interface{}{
 			"aws_workload_id": session.AWSWorkloadID,
 			"milestone_id":    session.MilestoneID,
 		},
 	}
 
+	if session.MilestoneSkipped {
+		output.Metadata["milestone_skipped"] = true
+	}
+
+	if session.QuestionCatalog != "" {
+		output.Metadata["question_catalog"] = session.QuestionCatalog
+	}
+
+	if session.Results != nil {
+		AddImprovementPlanMetadata(output.Metadata, session.Results.ImprovementPlan, session.ImprovementPlanError)
+	}
+
 	if session.Results != nil && session.Results.Summary != nil {
-		output.Summary = &ReviewSummaryOutput{
-			QuestionsEvaluated:  session.Results.Summary.QuestionsEvaluated,
-			HighRisks:           session.Results.Summary.HighRisks,
-			MediumRisks:         session.Results.Summary.MediumRisks,
-			AverageConfidence:   session.Results.Summary.AverageConfidence,
-			ImprovementPlanSize: session.Results.Summary.ImprovementPlanSize,
+		output.Summary = ConvertResultsSummaryToOutput(session.Results.Summary)
+	}
+
+	if session.ReportDrift && session.WorkloadModel != nil {
+		output.Drift = ConvertPropertyDriftToOutput(session.WorkloadModel.Drift)
+	}
+
+	if session.WorkloadModel != nil {
+		output.PublicExposure = ConvertPublicExposureToOutput(session.WorkloadModel.PublicExposure)
+	}
+
+	if session.WorkloadModel != nil && len(session.WorkloadModel.DestructiveChanges) > 0 {
+		output.Metadata["destructive_changes"] = ConvertDestructiveChangesToOutput(session.WorkloadModel.DestructiveChanges)
+	}
+
+	if session.WorkloadModel != nil && len(session.WorkloadModel.Advisories) > 0 {
+		output.Metadata["advisories"] = ConvertAdvisoriesToOutput(session.WorkloadModel.Advisories)
+	}
+
+	return output
+}
+
+// ConvertDestructiveChangesToOutput converts planned destructive changes to
+// their JSON form
+func ConvertDestructiveChangesToOutput(changes []DestructiveChange) []DestructiveChangeOutput {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	output := make([]DestructiveChangeOutput, 0, len(changes))
+	for _, c := range changes {
+		output = append(output, DestructiveChangeOutput{Address: c.Address, Type: c.Type, Action: c.Action})
+	}
+	return output
+}
+
+// ConvertPublicExposureToOutput converts publicly exposed resources to their
+// JSON form
+func ConvertPublicExposureToOutput(exposed []ExposedResource) []ExposedResourceOutput {
+	if len(exposed) == 0 {
+		return nil
+	}
+
+	output := make([]ExposedResourceOutput, 0, len(exposed))
+	for _, e := range exposed {
+		output = append(output, ExposedResourceOutput{Address: e.Address, Type: e.Type, Reason: e.Reason, Path: e.Path})
+	}
+	return output
+}
+
+// ConvertAdvisoriesToOutput converts workload-level advisories to their JSON
+// form
+func ConvertAdvisoriesToOutput(advisories []Advisory) []AdvisoryOutput {
+	if len(advisories) == 0 {
+		return nil
+	}
+
+	output := make([]AdvisoryOutput, 0, len(advisories))
+	for _, a := range advisories {
+		output = append(output, AdvisoryOutput{Rule: a.Rule, Pillar: string(a.Pillar), Source: a.Source, Message: a.Message})
+	}
+	return output
+}
+
+// ConvertEvaluationsToOutput converts question evaluations to their JSON form
+func ConvertEvaluationsToOutput(evaluations []*QuestionEvaluation) []*EvaluationOutput {
+	output := make([]*EvaluationOutput, 0, len(evaluations))
+	for _, evaluation := range evaluations {
+		output = append(output, convertEvaluationToOutput(evaluation))
+	}
+	return output
+}
+
+// ConvertPropertyDriftToOutput converts recorded property drift to its JSON form
+func ConvertPropertyDriftToOutput(drift []PropertyDrift) []PropertyDriftOutput {
+	if len(drift) == 0 {
+		return nil
+	}
+
+	output := make([]PropertyDriftOutput, 0, len(drift))
+	for _, d := range drift {
+		output = append(output, PropertyDriftOutput{
+			Address:     d.Address,
+			Property:    d.Property,
+			ConfigValue: d.ConfigValue,
+			PlanValue:   d.PlanValue,
+		})
+	}
+	return output
+}
+
+// ConvertBaselineComparisonToOutput converts a BaselineComparison against the
+// baseline read from file to BaselineOutput
+func ConvertBaselineComparisonToOutput(file string, comparison *BaselineComparison) *BaselineOutput {
+	if comparison == nil {
+		return nil
+	}
+
+	output := &BaselineOutput{
+		File:        file,
+		Passed:      comparison.Passed,
+		Regressions: make([]BaselineRegressionOutput, 0, len(comparison.Regressions)),
+	}
+	for _, r := range comparison.Regressions {
+		output.Regressions = append(output.Regressions, convertBaselineRegression(r))
+	}
+	for _, r := range comparison.Advisory {
+		output.Advisory = append(output.Advisory, convertBaselineRegression(r))
+	}
+	return output
+}
+
+// convertBaselineRegression converts a BaselineRegression to BaselineRegressionOutput
+func convertBaselineRegression(r BaselineRegression) BaselineRegressionOutput {
+	return BaselineRegressionOutput{
+		Pillar:   string(r.Pillar),
+		Metric:   r.Metric,
+		Baseline: r.Baseline,
+		Actual:   r.Actual,
+	}
+}
+
+// ConvertSuppressionsToOutput converts suppressions to their JSON output.
+// It returns nil when there are none.
+func ConvertSuppressionsToOutput(suppressions []Suppression) []SuppressionOutput {
+	if len(suppressions) == 0 {
+		return nil
+	}
+	output := make([]SuppressionOutput, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		output = append(output, SuppressionOutput(suppression))
+	}
+	return output
+}
+
+// ConvertCoverageMatrixToOutput converts a CoverageMatrix to CoverageMatrixOutput
+func ConvertCoverageMatrixToOutput(matrix *CoverageMatrix) *CoverageMatrixOutput {
+	if matrix == nil {
+		return nil
+	}
+
+	output := &CoverageMatrixOutput{
+		Resources: make([]ResourceCoverageOutput, 0, len(matrix.Resources)),
+		Uncited:   append([]string{}, matrix.Uncited...),
+	}
+	for _, resource := range matrix.Resources {
+		output.Resources = append(output.Resources, ResourceCoverageOutput{
+			Address:   resource.Address,
+			Questions: append([]string{}, resource.Questions...),
+		})
+	}
+	return output
+}
+
+// ConvertCalibrationToOutput converts a Calibration to CalibrationOutput
+func ConvertCalibrationToOutput(calibration *Calibration) *CalibrationOutput {
+	if calibration == nil {
+		return nil
+	}
+
+	output := &CalibrationOutput{
+		Questions:         calibration.Questions,
+		AverageConfidence: calibration.AverageConfidence,
+		Bands:             make([]CalibrationBandOutput, 0, len(calibration.Bands)),
+		Pillars:           make(map[string]PillarCalibrationOutput, len(calibration.Pillars)),
+	}
+	for _, band := range calibration.Bands {
+		output.Bands = append(output.Bands, CalibrationBandOutput{
+			Min:   band.Min,
+			Max:   band.Max,
+			Count: band.Count,
+		})
+	}
+	for pillar, stats := range calibration.Pillars {
+		output.Pillars[string(pillar)] = PillarCalibrationOutput{
+			Questions:         stats.Questions,
+			AverageConfidence: stats.AverageConfidence,
+		}
+	}
+	return output
+}
+
+// ConvertReviewTimingsToOutput converts ReviewTimings to TimingsOutput
+func ConvertReviewTimingsToOutput(timings *ReviewTimings) *TimingsOutput {
+	if timings == nil {
+		return nil
+	}
+
+	output := &TimingsOutput{
+		Steps:   make([]StepTimingOutput, 0, len(timings.Steps)),
+		TotalMS: timings.Total.Milliseconds(),
+	}
+	for _, step := range timings.Steps {
+		output.Steps = append(output.Steps, StepTimingOutput{
+			Step:       step.Step,
+			OffsetMS:   step.Offset.Milliseconds(),
+			DurationMS: step.Duration.Milliseconds(),
+		})
+	}
+	return output
+}
+
+// ConvertResultsSummaryToOutput converts a ResultsSummary to ReviewSummaryOutput
+func ConvertResultsSummaryToOutput(summary *ResultsSummary) *ReviewSummaryOutput {
+	if summary == nil {
+		return nil
+	}
+
+	output := &ReviewSummaryOutput{
+		QuestionsEvaluated:         summary.QuestionsEvaluated,
+		HighRisks:                  summary.HighRisks,
+		MediumRisks:                summary.MediumRisks,
+		AverageConfidence:          summary.AverageConfidence,
+		SeverityWeightedConfidence: summary.SeverityWeightedConfidence,
+		ImprovementPlanSize:        summary.ImprovementPlanSize,
+		Partial:                    summary.Partial,
+		QuestionsSkipped:           summary.QuestionsSkipped,
+		TimedOutQuestions:          summary.TimedOutQuestions,
+		PreservedQuestions:         summary.PreservedQuestions,
+	}
+
+	if len(summary.PillarSummaries) > 0 {
+		output.PillarSummaries = make(map[string]*PillarSummaryOutput, len(summary.PillarSummaries))
+		for pillar, ps := range summary.PillarSummaries {
+			output.PillarSummaries[string(pillar)] = &PillarSummaryOutput{
+				QuestionsEvaluated: ps.QuestionsEvaluated,
+				HighRisks:          ps.HighRisks,
+				MediumRisks:        ps.MediumRisks,
+				AverageConfidence:  ps.AverageConfidence,
+				Advisory:           ps.Advisory,
+			}
 		}
 	}
 
@@ -192,11 +652,12 @@ func ConvertReviewSessionToOutput(session *ReviewSession) *ReviewOutput {
 // ConvertReviewSessionToStatusOutput converts a ReviewSession to StatusOutput
 func ConvertReviewSessionToStatusOutput(session *ReviewSession) *StatusOutput {
 	output := &StatusOutput{
-		SessionID:  session.SessionID,
-		WorkloadID: session.WorkloadID,
-		Status:     string(session.Status),
-		CreatedAt:  session.CreatedAt,
-		UpdatedAt:  session.UpdatedAt,
+		SchemaVersion: SchemaVersion,
+		SessionID:     session.SessionID,
+		WorkloadID:    session.WorkloadID,
+		Status:        string(session.Status),
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
 		Metadata: map[string]interface{}{
 			"aws_workload_id": session.AWSWorkloadID,
 			"checkpoint":      session.Checkpoint,
@@ -209,10 +670,12 @@ func ConvertReviewSessionToStatusOutput(session *ReviewSession) *StatusOutput {
 // ConvertReviewSessionToResultsOutput converts a ReviewSession to ResultsOutput
 func ConvertReviewSessionToResultsOutput(session *ReviewSession) *ResultsOutput {
 	output := &ResultsOutput{
+		SchemaVersion: SchemaVersion,
 		SessionID:     session.SessionID,
 		WorkloadID:    session.WorkloadID,
 		AWSWorkloadID: session.AWSWorkloadID,
 		MilestoneID:   session.MilestoneID,
+		LensVersion:   session.LensVersion,
 		Status:        string(session.Status),
 		CreatedAt:     session.CreatedAt,
 		CompletedAt:   session.UpdatedAt,
@@ -220,14 +683,8 @@ func ConvertReviewSessionToResultsOutput(session *ReviewSession) *ResultsOutput
 	}
 
 	// Add summary
-	if session.Results != nil && session.Results.Summary != nil {
-		output.Summary = &ReviewSummaryOutput{
-			QuestionsEvaluated:  session.Results.Summary.QuestionsEvaluated,
-			HighRisks:           session.Results.Summary.HighRisks,
-			MediumRisks:         session.Results.Summary.MediumRisks,
-			AverageConfidence:   session.Results.Summary.AverageConfidence,
-			ImprovementPlanSize: session.Results.Summary.ImprovementPlanSize,
-		}
+	if session.Results != nil {
+		output.Summary = ConvertResultsSummaryToOutput(session.Results.Summary)
 	}
 
 	// Add evaluations
@@ -246,6 +703,11 @@ func ConvertReviewSessionToResultsOutput(session *ReviewSession) *ResultsOutput
 		}
 	}
 
+	// Add suppressions
+	if session.Results != nil {
+		output.Suppressions = ConvertSuppressionsToOutput(session.Results.Suppressions)
+	}
+
 	// Add improvements
 	if session.Results != nil && session.Results.ImprovementPlan != nil && len(session.Results.ImprovementPlan.Items) > 0 {
 		output.Improvements = make([]*ImprovementOutput, 0, len(session.Results.ImprovementPlan.Items))
@@ -299,6 +761,13 @@ func convertEvaluationToOutput(eval *QuestionEvaluation) *EvaluationOutput {
 		Title:           eval.Question.Title,
 		ConfidenceScore: eval.ConfidenceScore,
 		Notes:           eval.Notes,
+		ParseRetries:    eval.ParseRetries,
+		TimedOut:        eval.TimedOut,
+		Overridden:      eval.Overridden,
+		NotApplicable:   eval.NotApplicable,
+		Source:          string(eval.Source),
+
+		ConsideredResources: eval.ConsideredResources,
 	}
 
 	// Convert selected choices
@@ -330,16 +799,11 @@ func convertRiskToOutput(risk *Risk) *RiskOutput {
 		Pillar:            string(risk.Pillar),
 		Description:       risk.Description,
 		AffectedResources: risk.AffectedResources,
+		Severity:          riskLevelName(risk.Severity),
+		Source:            string(risk.Source),
 	}
-
-	// Convert severity
-	switch risk.Severity {
-	case RiskLevelHigh:
-		output.Severity = "high"
-	case RiskLevelMedium:
-		output.Severity = "medium"
-	case RiskLevelNone:
-		output.Severity = "none"
+	if risk.OriginalSeverity != nil {
+		output.OriginalSeverity = riskLevelName(*risk.OriginalSeverity)
 	}
 
 	// Convert missing best practices
@@ -351,6 +815,36 @@ func convertRiskToOutput(risk *Risk) *RiskOutput {
 	return output
 }
 
+// ParseRiskSeverity returns the risk level named high, medium or none
+func ParseRiskSeverity(name string) (RiskLevel, error) {
+	switch strings.ToLower(name) {
+	case "high":
+		return RiskLevelHigh, nil
+	case "medium":
+		return RiskLevelMedium, nil
+	case "none":
+		return RiskLevelNone, nil
+	default:
+		return RiskLevelNone, fmt.Errorf("invalid risk severity %q: must be high, medium or none", name)
+	}
+}
+
+// riskLevelName returns the JSON name of a risk level
+func riskLevelName(level RiskLevel) string {
+	switch level {
+	case RiskLevelHigh:
+		return "high"
+	case RiskLevelMedium:
+		return "medium"
+	case RiskLevelNone:
+		return "none"
+	case RiskLevelUnassessed:
+		return "unassessed"
+	default:
+		return ""
+	}
+}
+
 func convertImprovementToOutput(item *ImprovementPlanItem) *ImprovementOutput {
 	output := &ImprovementOutput{
 		ID:                item.ID,
@@ -359,6 +853,8 @@ func convertImprovementToOutput(item *ImprovementPlanItem) *ImprovementOutput {
 		AffectedResources: item.AffectedResources,
 		Priority:          item.Priority,
 		EstimatedEffort:   item.EstimatedEffort,
+		BlastRadius:       item.BlastRadius,
+		Remediation:       item.Remediation,
 	}
 
 	if item.Risk != nil {
@@ -369,13 +865,26 @@ func convertImprovementToOutput(item *ImprovementPlanItem) *ImprovementOutput {
 }
 
 func convertResourceToOutput(resource *Resource) *ResourceOutput {
-	return &ResourceOutput{
-		ID:         resource.ID,
-		Type:       resource.Type,
-		Address:    resource.Address,
-		SourceFile: resource.SourceFile,
-		IsFromPlan: resource.IsFromPlan,
-		ModulePath: resource.ModulePath,
-		Properties: resource.Properties,
+	output := &ResourceOutput{
+		ID:          resource.ID,
+		Type:        resource.Type,
+		Address:     resource.Address,
+		SourceFile:  resource.SourceFile,
+		IsFromPlan:  resource.IsFromPlan,
+		ModulePath:  resource.ModulePath,
+		Kind:        string(resource.Kind),
+		Properties:  resource.Properties,
+		Fingerprint: resource.Fingerprint,
+	}
+
+	for _, hint := range resource.Hints {
+		output.Hints = append(output.Hints, ResourceHintOutput{
+			Rule:     hint.Rule,
+			Pillar:   string(hint.Pillar),
+			Severity: riskLevelName(hint.Severity),
+			Message:  hint.Message,
+		})
 	}
+
+	return output
 }
@@ -128,6 +128,120 @@ func TestConvertReviewSessionToOutput(t *testing.T) {
 	assert.Equal(t, "milestone-001", output.Metadata["milestone_id"])
 }
 
+func TestConvertReviewSessionToOutput_PublicExposure(t *testing.T) {
+	session := &ReviewSession{
+		SessionID: "session-123",
+		WorkloadModel: &WorkloadModel{
+			PublicExposure: []ExposedResource{
+				{Address: "aws_lb.web", Type: "aws_lb", Reason: "internet-facing load balancer"},
+				{Address: "aws_instance.web", Type: "aws_instance", Reason: "reachable through aws_lb.web (internet-facing load balancer)", Path: []string{"aws_lb.web", "aws_lb_listener.http"}},
+			},
+		},
+	}
+
+	output := ConvertReviewSessionToOutput(session)
+
+	require.Len(t, output.PublicExposure, 2)
+	assert.Equal(t, ExposedResourceOutput{Address: "aws_lb.web", Type: "aws_lb", Reason: "internet-facing load balancer"}, output.PublicExposure[0])
+	assert.Equal(t, []string{"aws_lb.web", "aws_lb_listener.http"}, output.PublicExposure[1].Path)
+
+	data, err := json.Marshal(ConvertReviewSessionToOutput(&ReviewSession{WorkloadModel: &WorkloadModel{}}))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "public_exposure")
+}
+
+func TestSchemaVersionPresent(t *testing.T) {
+	session := &ReviewSession{
+		SessionID:  "session-123",
+		WorkloadID: "workload-456",
+		Status:     SessionStatusCompleted,
+		Scope:      ReviewScope{Level: ScopeLevelWorkload},
+	}
+
+	outputs := map[string]interface{}{
+		"review":  ConvertReviewSessionToOutput(session),
+		"status":  ConvertReviewSessionToStatusOutput(session),
+		"results": ConvertReviewSessionToResultsOutput(session),
+	}
+
+	for name, output := range outputs {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteJSON(&buf, output))
+
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+			assert.Equal(t, SchemaVersion, decoded["schema_version"])
+		})
+	}
+}
+
+func TestValidateSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:  "matching version",
+			input: `{"schema_version": "` + SchemaVersion + `", "session_id": "abc"}`,
+		},
+		{
+			name:    "mismatched version",
+			input:   `{"schema_version": "0.1", "session_id": "abc"}`,
+			wantErr: ErrSchemaVersionMismatch,
+		},
+		{
+			name:    "missing version",
+			input:   `{"session_id": "abc"}`,
+			wantErr: ErrSchemaVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchemaVersion(bytes.NewBufferString(tt.input))
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		err := ValidateSchemaVersion(bytes.NewBufferString("not json"))
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrSchemaVersionMismatch)
+	})
+}
+
+func TestConvertResultsSummaryToOutput_PillarSummaries(t *testing.T) {
+	summary := &ResultsSummary{
+		QuestionsEvaluated: 3,
+		HighRisks:          1,
+		MediumRisks:        1,
+		AverageConfidence:  0.5,
+		PillarSummaries: map[Pillar]PillarSummary{
+			PillarSecurity:    {QuestionsEvaluated: 2, HighRisks: 1, AverageConfidence: 0.4},
+			PillarReliability: {QuestionsEvaluated: 1, MediumRisks: 1, AverageConfidence: 0.6},
+		},
+	}
+
+	output := ConvertResultsSummaryToOutput(summary)
+	require.NotNil(t, output)
+	require.Len(t, output.PillarSummaries, 2)
+	assert.Equal(t, 1, output.PillarSummaries["security"].HighRisks)
+	assert.Equal(t, 1, output.PillarSummaries["reliability"].MediumRisks)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, output))
+	assert.Contains(t, buf.String(), `"pillar_summaries"`)
+
+	assert.Nil(t, ConvertResultsSummaryToOutput(nil))
+}
+
 func TestConvertReviewSessionToStatusOutput(t *testing.T) {
 	now := time.Now()
 	session := &ReviewSession{
@@ -187,6 +301,9 @@ func TestConvertReviewSessionToResultsOutput(t *testing.T) {
 				MediumRisks:         2,
 				AverageConfidence:   0.90,
 				ImprovementPlanSize: 3,
+				PillarSummaries: map[Pillar]PillarSummary{
+					PillarSecurity: {QuestionsEvaluated: 5, HighRisks: 1, MediumRisks: 2, AverageConfidence: 0.90, Advisory: true},
+				},
 			},
 			Evaluations: []*QuestionEvaluation{
 				{
@@ -261,6 +378,13 @@ func TestConvertReviewSessionToResultsOutput(t *testing.T) {
 	assert.Equal(t, 5, output.Summary.QuestionsEvaluated)
 	assert.Equal(t, 1, output.Summary.HighRisks)
 	assert.Equal(t, 2, output.Summary.MediumRisks)
+	require.Contains(t, output.Summary.PillarSummaries, "security")
+	assert.Equal(t, 1, output.Summary.PillarSummaries["security"].HighRisks)
+	assert.True(t, output.Summary.PillarSummaries["security"].Advisory)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, output))
+	assert.Contains(t, buf.String(), `"pillar_summaries"`)
 
 	// Verify evaluations
 	require.Len(t, output.Evaluations, 1)
@@ -416,3 +540,46 @@ func TestJSONOutputValidity(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRiskSeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    RiskLevel
+		wantErr bool
+	}{
+		{name: "high", want: RiskLevelHigh},
+		{name: "Medium", want: RiskLevelMedium},
+		{name: "none", want: RiskLevelNone},
+		{name: "unassessed", wantErr: true},
+		{name: "critical", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRiskSeverity(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertRiskToOutput_OriginalSeverity(t *testing.T) {
+	original := RiskLevelMedium
+	risk := &Risk{
+		ID:               "data-classification",
+		Question:         &WAFRQuestion{ID: "data-classification"},
+		Severity:         RiskLevelHigh,
+		OriginalSeverity: &original,
+	}
+
+	output := convertRiskToOutput(risk)
+	assert.Equal(t, "high", output.Severity)
+	assert.Equal(t, "medium", output.OriginalSeverity)
+
+	risk.OriginalSeverity = nil
+	assert.Empty(t, convertRiskToOutput(risk).OriginalSeverity)
+}
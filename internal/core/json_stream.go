@@ -0,0 +1,240 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// jsonIndent is the indentation WriteJSON uses per nesting level
+const jsonIndent = "  "
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// WriteJSONStream writes data exactly as WriteJSON does, but encodes
+// structs and string-keyed maps member by member and their slices one
+// element at a time, at any depth. Only one element is held in encoded form
+// at once, so memory stays bounded however many evaluations or resources a
+// result has. Values that are neither, or whose JSON encoding cannot be
+// reproduced member by member, are written with WriteJSON.
+func WriteJSONStream(w io.Writer, data interface{}) error {
+	v := indirectJSONValue(reflect.ValueOf(data))
+	if _, ok := streamableMembers(v); !ok {
+		return WriteJSON(w, data)
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := writeJSONValue(bw, v, ""); err != nil {
+		return err
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+// writeJSONValue writes v, whose first line is indented by indent
+func writeJSONValue(bw *bufio.Writer, v reflect.Value, indent string) error {
+	v = indirectJSONValue(v)
+	if members, ok := streamableMembers(v); ok {
+		return writeJSONObject(bw, members, indent)
+	}
+	if isStreamableSlice(v) {
+		return writeJSONArray(bw, v, indent)
+	}
+	if !v.IsValid() {
+		return newIndentEncoder(indent).write(bw, nil)
+	}
+	return newIndentEncoder(indent).write(bw, v.Interface())
+}
+
+// writeJSONObject writes the members of a struct or map
+func writeJSONObject(bw *bufio.Writer, members []streamField, indent string) error {
+	memberIndent := indent + jsonIndent
+	enc := newIndentEncoder(memberIndent)
+
+	bw.WriteString("{")
+	for i, member := range members {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n" + memberIndent)
+		if err := enc.write(bw, member.name); err != nil {
+			return err
+		}
+		bw.WriteString(": ")
+		if err := writeJSONValue(bw, member.value, memberIndent); err != nil {
+			return err
+		}
+	}
+	if len(members) > 0 {
+		bw.WriteString("\n" + indent)
+	}
+	bw.WriteString("}")
+	return nil
+}
+
+// writeJSONArray writes the elements of a non-empty slice
+func writeJSONArray(bw *bufio.Writer, slice reflect.Value, indent string) error {
+	elementIndent := indent + jsonIndent
+	enc := newIndentEncoder(elementIndent)
+
+	bw.WriteString("[")
+	for i := 0; i < slice.Len(); i++ {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n" + elementIndent)
+		if err := enc.write(bw, slice.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	bw.WriteString("\n" + indent + "]")
+	return nil
+}
+
+// indirectJSONValue follows the non-nil pointers and interfaces around v
+func indirectJSONValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if v.Kind() == reflect.Pointer && implementsJSONMarshaler(v.Type()) {
+			break
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// indentEncoder encodes values nested under prefix, reusing one buffer
+type indentEncoder struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+func newIndentEncoder(prefix string) *indentEncoder {
+	e := &indentEncoder{}
+	e.enc = json.NewEncoder(&e.buf)
+	e.enc.SetIndent(prefix, jsonIndent)
+	return e
+}
+
+// write encodes value to bw without the newline json.Encoder appends
+func (e *indentEncoder) write(bw *bufio.Writer, value interface{}) error {
+	e.buf.Reset()
+	if err := e.enc.Encode(value); err != nil {
+		return err
+	}
+	_, err := bw.Write(bytes.TrimSuffix(e.buf.Bytes(), []byte("\n")))
+	return err
+}
+
+// streamField is a struct field or map entry as encoding/json would write it
+type streamField struct {
+	name  string
+	value reflect.Value
+}
+
+// streamableMembers returns the members encoding/json would write for a
+// struct or string-keyed map v, in order. It reports false for values it
+// cannot mirror exactly: other kinds, custom marshalers, embedded fields and
+// tag options other than omitempty.
+func streamableMembers(v reflect.Value) ([]streamField, bool) {
+	if !v.IsValid() || implementsJSONMarshaler(v.Type()) {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return streamableFields(v)
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String || implementsJSONMarshaler(v.Type().Key()) {
+			return nil, false
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		members := make([]streamField, 0, len(keys))
+		for _, key := range keys {
+			members = append(members, streamField{name: key.String(), value: v.MapIndex(key)})
+		}
+		return members, true
+	}
+	return nil, false
+}
+
+// implementsJSONMarshaler reports whether values of t, or pointers to them,
+// choose their own JSON encoding
+func implementsJSONMarshaler(t reflect.Type) bool {
+	for _, marshaler := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if t.Implements(marshaler) || (t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(marshaler)) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamableFields returns the fields encoding/json would write for struct
+// v, in order
+func streamableFields(v reflect.Value) ([]streamField, bool) {
+	var fields []streamField
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.Anonymous {
+			return nil, false
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if options != "" && options != "omitempty" {
+			return nil, false
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		value := v.Field(i)
+		if options == "omitempty" && isEmptyJSONValue(value) {
+			continue
+		}
+		fields = append(fields, streamField{name: name, value: value})
+	}
+	return fields, true
+}
+
+// isStreamableSlice reports whether v is a non-empty slice encoding/json
+// writes as an array of its elements. Byte slices are base64 strings and
+// marshalers pick their own encoding.
+func isStreamableSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice || v.Len() == 0 || v.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	return !v.Type().Implements(jsonMarshalerType) && !v.Type().Implements(textMarshalerType)
+}
+
+// isEmptyJSONValue reports whether omitempty drops v, following encoding/json
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
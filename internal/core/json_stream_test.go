@@ -0,0 +1,151 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeResultsSession builds a completed session with thousands of
+// evaluations and resources, including text encoding/json escapes
+func largeResultsSession(evaluations, resources int) *ReviewSession {
+	session := &ReviewSession{
+		SessionID:     "session-large",
+		WorkloadID:    "payments",
+		AWSWorkloadID: "wl-123",
+		MilestoneID:   "7",
+		Status:        SessionStatusCompleted,
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		CreatedAt:     time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		UpdatedAt:     time.Date(2026, 3, 4, 6, 7, 8, 0, time.UTC),
+		Results: &ReviewResults{
+			Summary:         &ResultsSummary{QuestionsEvaluated: evaluations, HighRisks: 3, AverageConfidence: 0.61},
+			ImprovementPlan: &ImprovementPlan{},
+		},
+		WorkloadModel: &WorkloadModel{},
+	}
+
+	for i := 0; i < evaluations; i++ {
+		question := &WAFRQuestion{ID: fmt.Sprintf("q%d", i), Pillar: AllPillars()[i%len(AllPillars())], Title: "Question <&> \"quoted\" ✓"}
+		session.Results.Evaluations = append(session.Results.Evaluations, &QuestionEvaluation{
+			Question:        question,
+			SelectedChoices: []Choice{{ID: question.ID + "_a"}, {ID: question.ID + "_b"}},
+			Evidence: []Evidence{{
+				ChoiceID:    question.ID + "_a",
+				Explanation: "Buckets\nare encrypted\twith KMS",
+				Resources:   []string{fmt.Sprintf("aws_s3_bucket.b%d", i)},
+				Confidence:  float64(i%100) / 100,
+			}},
+			ConfidenceScore: float64(i%100) / 100,
+		})
+		if i%10 == 0 {
+			risk := &Risk{ID: "risk-" + question.ID, Question: question, Pillar: question.Pillar, Severity: RiskLevelHigh}
+			session.Results.Risks = append(session.Results.Risks, risk)
+			session.Results.ImprovementPlan.Items = append(session.Results.ImprovementPlan.Items, &ImprovementPlanItem{
+				ID: "imp-" + question.ID, Risk: risk, Priority: i % 5, Remediation: "set `versioning { enabled = true }`",
+			})
+		}
+	}
+
+	for i := 0; i < resources; i++ {
+		session.WorkloadModel.Resources = append(session.WorkloadModel.Resources, Resource{
+			ID:      fmt.Sprintf("aws_s3_bucket.b%d", i),
+			Type:    "aws_s3_bucket",
+			Address: fmt.Sprintf("aws_s3_bucket.b%d", i),
+			Properties: map[string]interface{}{
+				"bucket": fmt.Sprintf("bucket-%d", i),
+				"tags":   map[string]interface{}{"team": "payments", "index": i},
+				"rules":  []interface{}{map[string]interface{}{"days": 30}},
+			},
+			Hints: []ResourceHint{{Rule: "public-acl", Pillar: PillarSecurity, Severity: RiskLevelHigh}},
+		})
+	}
+
+	return session
+}
+
+func TestWriteJSONStream_MatchesWriteJSON(t *testing.T) {
+	type withBytes struct {
+		Name    string   `json:"name"`
+		Payload []byte   `json:"payload"`
+		Tags    []string `json:"tags"`
+	}
+	type withStringOption struct {
+		Count int `json:"count,string"`
+	}
+
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "large results", data: ConvertReviewSessionToResultsOutput(largeResultsSession(5000, 2000))},
+		{name: "results without evaluations", data: ConvertReviewSessionToResultsOutput(largeResultsSession(0, 0))},
+		{
+			name: "review output",
+			data: &ReviewOutput{
+				SchemaVersion: SchemaVersion,
+				SessionID:     "session-1",
+				Drift:         []PropertyDriftOutput{{Address: "aws_s3_bucket.b", Property: "acl", ConfigValue: "private", PlanValue: nil}},
+				Metadata:      map[string]interface{}{"partial": true, "failed_pillars": []string{"security"}},
+			},
+		},
+		{name: "empty struct", data: struct{}{}},
+		{name: "empty and nil slices", data: withBytes{Tags: []string{}}},
+		{name: "byte slices stay base64", data: withBytes{Payload: []byte("payload"), Tags: []string{"a"}}},
+		{name: "tag options fall back", data: withStringOption{Count: 3}},
+		{name: "map", data: map[string]interface{}{"b": []int{1, 2}, "a": "x"}},
+		{
+			name: "nested results map",
+			data: map[string]interface{}{
+				"session_id": "session-1",
+				"summary":    map[string]interface{}{"high_risks": 3, "pillars": map[string]interface{}{}},
+				"improvement_plan": map[string]interface{}{
+					"items": []map[string]interface{}{{"id": "imp-1", "risk": map[string]string{"severity": "HIGH"}}},
+					"total": 1,
+				},
+				"evaluations": []map[string]interface{}{{"question_id": "q1", "choices": []string{"a", "<b>"}}},
+				"metadata":    map[string]interface{}(nil),
+				"missing":     nil,
+				"<escaped>":   &ImprovementPlan{},
+			},
+		},
+		{
+			name: "nested structs",
+			data: struct {
+				Results *ReviewResults  `json:"results"`
+				Nil     *ReviewResults  `json:"nil"`
+				Plan    ImprovementPlan `json:"plan"`
+			}{Results: largeResultsSession(20, 0).Results},
+		},
+		{name: "nil pointer", data: (*ResultsOutput)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffered, streamed bytes.Buffer
+			require.NoError(t, WriteJSON(&buffered, tt.data))
+			require.NoError(t, WriteJSONStream(&streamed, tt.data))
+
+			// Compared as strings so a mismatch shows a readable diff
+			assert.Equal(t, buffered.String(), streamed.String())
+			assert.True(t, json.Valid(streamed.Bytes()))
+		})
+	}
+}
+
+func TestWriteJSONStream_EncodingError(t *testing.T) {
+	data := struct {
+		Items []interface{} `json:"items"`
+	}{Items: []interface{}{"ok", make(chan int)}}
+
+	var buf bytes.Buffer
+	err := WriteJSONStream(&buf, data)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported type")
+}
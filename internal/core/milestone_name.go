@@ -0,0 +1,105 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// Milestone name length limits enforced by the AWS Well-Architected Tool.
+// Names outside them are rejected with a ValidationException.
+const (
+	MinMilestoneNameLength = 3
+	MaxMilestoneNameLength = 100
+)
+
+// MilestoneTimestampFormat is the layout of MilestoneNameData.Timestamp
+const MilestoneTimestampFormat = "2006-01-02-15-04-05"
+
+// DefaultMilestoneNameTemplate is used when no milestone name template is configured
+const DefaultMilestoneNameTemplate = "waffle-{{.Timestamp}}"
+
+// MilestoneNameData holds the values available to the milestone name template
+type MilestoneNameData struct {
+	WorkloadID string
+	SessionID  string
+	GitRef     string
+	// GitSHA is the commit checked out in the reviewed directory,
+	// abbreviated to 12 characters
+	GitSHA string
+	// Timestamp is Time formatted with MilestoneTimestampFormat
+	Timestamp string
+	Time      time.Time
+}
+
+// MilestoneNameOptions controls how the milestone created after a review is named
+type MilestoneNameOptions struct {
+	// Template is a text/template rendered with MilestoneNameData
+	Template string
+	// Name is used verbatim instead of the template when set
+	Name string
+}
+
+// RenderMilestoneName renders a milestone name template and validates the
+// result with ValidateMilestoneName. An empty template falls back to
+// DefaultMilestoneNameTemplate.
+func RenderMilestoneName(tmpl string, data MilestoneNameData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultMilestoneNameTemplate
+	}
+
+	t, err := template.New("milestone_name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", &ValidationError{
+			Field:   "milestone_name_template",
+			Value:   tmpl,
+			Message: fmt.Sprintf("invalid template: %v", err),
+		}
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", &ValidationError{
+			Field:   "milestone_name_template",
+			Value:   tmpl,
+			Message: fmt.Sprintf("failed to render template: %v", err),
+		}
+	}
+
+	name := strings.TrimSpace(sb.String())
+	if err := ValidateMilestoneName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// ValidateMilestoneName checks a milestone name against the constraints of
+// the AWS Well-Architected Tool, so that a bad name is reported before a
+// review runs rather than as a ValidationException at its end
+func ValidateMilestoneName(name string) error {
+	length := len([]rune(name))
+	if length < MinMilestoneNameLength || length > MaxMilestoneNameLength {
+		return &ValidationError{
+			Field:   "milestone_name",
+			Value:   name,
+			Message: fmt.Sprintf("must be %d to %d characters, got %d", MinMilestoneNameLength, MaxMilestoneNameLength, length),
+		}
+	}
+	if strings.TrimSpace(name) != name {
+		return &ValidationError{
+			Field:   "milestone_name",
+			Value:   name,
+			Message: "must not start or end with whitespace",
+		}
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return &ValidationError{
+			Field:   "milestone_name",
+			Value:   name,
+			Message: "must not contain control characters such as newlines",
+		}
+	}
+	return nil
+}
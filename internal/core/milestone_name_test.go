@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMilestoneName(t *testing.T) {
+	reviewedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	data := MilestoneNameData{
+		WorkloadID: "payments",
+		SessionID:  "session-1",
+		GitRef:     "main",
+		GitSHA:     "0123456789ab",
+		Timestamp:  reviewedAt.Format(MilestoneTimestampFormat),
+		Time:       reviewedAt,
+	}
+
+	tests := []struct {
+		name      string
+		tmpl      string
+		data      MilestoneNameData
+		want      string
+		wantField string
+	}{
+		{
+			name: "default template",
+			data: data,
+			want: "waffle-2026-03-04-05-06-07",
+		},
+		{
+			name: "custom template",
+			tmpl: "{{.WorkloadID}}-{{.GitRef}}@{{.GitSHA}}",
+			data: data,
+			want: "payments-main@0123456789ab",
+		},
+		{
+			name: "custom time layout",
+			tmpl: `release {{.Time.Format "2006.01.02"}}`,
+			data: data,
+			want: "release 2026.03.04",
+		},
+		{
+			name:      "invalid template syntax",
+			tmpl:      "{{.WorkloadID",
+			data:      data,
+			wantField: "milestone_name_template",
+		},
+		{
+			name:      "unknown field",
+			tmpl:      "{{.Owner}}",
+			data:      data,
+			wantField: "milestone_name_template",
+		},
+		{
+			name:      "rendered name too short",
+			tmpl:      "{{.GitSHA}}",
+			data:      MilestoneNameData{},
+			wantField: "milestone_name",
+		},
+		{
+			name:      "rendered name too long",
+			tmpl:      strings.Repeat("x", 90) + "-{{.Timestamp}}",
+			data:      data,
+			wantField: "milestone_name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMilestoneName(tt.tmpl, tt.data)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateMilestoneName(t *testing.T) {
+	tests := []struct {
+		name      string
+		milestone string
+		wantMsg   string
+	}{
+		{name: "minimum length", milestone: "v10"},
+		{name: "maximum length", milestone: strings.Repeat("m", MaxMilestoneNameLength)},
+		{name: "spaces and punctuation", milestone: "Release 2.0 (pre-launch)"},
+		{name: "empty", milestone: "", wantMsg: "must be 3 to 100 characters, got 0"},
+		{name: "too short", milestone: "v1", wantMsg: "must be 3 to 100 characters, got 2"},
+		{name: "too long", milestone: strings.Repeat("m", 101), wantMsg: "must be 3 to 100 characters, got 101"},
+		{name: "multi-byte characters count once", milestone: strings.Repeat("é", MaxMilestoneNameLength)},
+		{name: "leading whitespace", milestone: " release", wantMsg: "must not start or end with whitespace"},
+		{name: "newline", milestone: "release\nnotes", wantMsg: "must not contain control characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMilestoneName(tt.milestone)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestExecuteReview_MilestoneName(t *testing.T) {
+	reviewedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name    string
+		options MilestoneNameOptions
+		want    string
+	}{
+		{
+			name: "default template",
+			want: "waffle-2026-03-04-05-06-07",
+		},
+		{
+			name:    "custom template",
+			options: MilestoneNameOptions{Template: "{{.WorkloadID}} {{.GitSHA}} {{.Timestamp}}"},
+			want:    "test-workload 0123456789ab 2026-03-04-05-06-07",
+		},
+		{
+			name:    "explicit name wins over the template",
+			options: MilestoneNameOptions{Template: "{{.WorkloadID}}", Name: "Release 2.0"},
+			want:    "Release 2.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			wafrEval := &mockWAFREvaluator{
+				createMilestoneFunc: func(ctx context.Context, awsWorkloadID string, milestoneName string) (string, error) {
+					gotName = milestoneName
+					return "1", nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetClock(&fakeClock{now: reviewedAt})
+			engine.SetWorkloadDescriptionOptions(WorkloadDescriptionOptions{
+				Provenance: WorkloadProvenance{GitRef: "main", GitSHA: "0123456789ab"},
+			})
+			engine.SetMilestoneNameOptions(tt.options)
+
+			session := &ReviewSession{
+				SessionID:     "test-session",
+				WorkloadID:    "test-workload",
+				AWSWorkloadID: "aws-workload-123",
+				Scope:         ReviewScope{Level: ScopeLevelWorkload},
+				Status:        SessionStatusCreated,
+			}
+
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gotName)
+			assert.Equal(t, "1", session.MilestoneID)
+			// Session timestamps come from the same clock
+			assert.Equal(t, reviewedAt, session.UpdatedAt)
+		})
+	}
+}
+
+func TestInitiateReview_InvalidMilestoneName(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     MilestoneNameOptions
+		noMilestone bool
+		wantErr     bool
+	}{
+		{name: "template renders too short a name", options: MilestoneNameOptions{Template: "{{.GitSHA}}"}, wantErr: true},
+		{name: "explicit name too long", options: MilestoneNameOptions{Name: strings.Repeat("m", 101)}, wantErr: true},
+		{name: "not checked without milestones", options: MilestoneNameOptions{Name: "v1"}, noMilestone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workloadCreated := false
+			wafrEval := &mockWAFREvaluator{
+				createWorkloadFunc: func(ctx context.Context, workloadID string, description string) (string, error) {
+					workloadCreated = true
+					return "aws-workload-123", nil
+				},
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetMilestoneNameOptions(tt.options)
+			engine.SetCreateMilestone(!tt.noMilestone)
+
+			_, err := engine.InitiateReview(context.Background(), "test-workload", ReviewScope{Level: ScopeLevelWorkload})
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "milestone_name", validationErr.Field)
+			assert.False(t, workloadCreated, "no workload is created for a bad milestone name")
+		})
+	}
+}
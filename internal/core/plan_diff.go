@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Plan diff output formats
+const (
+	PlanDiffFormatText = "text"
+	PlanDiffFormatJSON = "json"
+)
+
+// PlanDiff compares the improvement plans of two reviews. Items are matched
+// by ID, which is derived from the question a risk belongs to.
+type PlanDiff struct {
+	BeforeSession string `json:"before_session"`
+	AfterSession  string `json:"after_session"`
+	// Resolved items are in the earlier plan only, Introduced items in the
+	// later plan only
+	Resolved      []PlanDiffItem       `json:"resolved"`
+	Introduced    []PlanDiffItem       `json:"introduced"`
+	Reprioritized []PlanPriorityChange `json:"reprioritized"`
+	// Unchanged counts items in both plans with the same priority
+	Unchanged int `json:"unchanged"`
+}
+
+// PlanDiffItem is an improvement item that is in only one of the plans
+type PlanDiffItem struct {
+	ID          string `json:"id"`
+	QuestionID  string `json:"question_id,omitempty"`
+	Pillar      string `json:"pillar,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+}
+
+// PlanPriorityChange is an improvement item in both plans whose priority
+// changed
+type PlanPriorityChange struct {
+	ID          string `json:"id"`
+	QuestionID  string `json:"question_id,omitempty"`
+	Description string `json:"description"`
+	Before      int    `json:"before"`
+	After       int    `json:"after"`
+}
+
+// DiffImprovementPlans compares the plans of the sessions before and after.
+// Each list is sorted by item ID. A session without results has an empty
+// plan.
+func DiffImprovementPlans(before, after *ReviewSession) *PlanDiff {
+	diff := &PlanDiff{
+		BeforeSession: before.SessionID,
+		AfterSession:  after.SessionID,
+		Resolved:      []PlanDiffItem{},
+		Introduced:    []PlanDiffItem{},
+		Reprioritized: []PlanPriorityChange{},
+	}
+
+	beforeItems := planItemsByID(before)
+	afterItems := planItemsByID(after)
+
+	for id, item := range beforeItems {
+		if _, ok := afterItems[id]; !ok {
+			diff.Resolved = append(diff.Resolved, newPlanDiffItem(item))
+		}
+	}
+	for id, item := range afterItems {
+		previous, ok := beforeItems[id]
+		switch {
+		case !ok:
+			diff.Introduced = append(diff.Introduced, newPlanDiffItem(item))
+		case previous.Priority != item.Priority:
+			change := PlanPriorityChange{ID: id, Description: item.Description, Before: previous.Priority, After: item.Priority}
+			if item.Risk != nil && item.Risk.Question != nil {
+				change.QuestionID = item.Risk.Question.ID
+			}
+			diff.Reprioritized = append(diff.Reprioritized, change)
+		default:
+			diff.Unchanged++
+		}
+	}
+
+	sort.Slice(diff.Resolved, func(i, j int) bool { return diff.Resolved[i].ID < diff.Resolved[j].ID })
+	sort.Slice(diff.Introduced, func(i, j int) bool { return diff.Introduced[i].ID < diff.Introduced[j].ID })
+	sort.Slice(diff.Reprioritized, func(i, j int) bool { return diff.Reprioritized[i].ID < diff.Reprioritized[j].ID })
+	return diff
+}
+
+// planItemsByID indexes the improvement items of a session by ID
+func planItemsByID(session *ReviewSession) map[string]*ImprovementPlanItem {
+	items := make(map[string]*ImprovementPlanItem)
+	if session.Results == nil || session.Results.ImprovementPlan == nil {
+		return items
+	}
+	for _, item := range session.Results.ImprovementPlan.Items {
+		items[item.ID] = item
+	}
+	return items
+}
+
+// newPlanDiffItem describes an item that is in one plan only
+func newPlanDiffItem(item *ImprovementPlanItem) PlanDiffItem {
+	diffItem := PlanDiffItem{ID: item.ID, Description: item.Description, Priority: item.Priority}
+	if item.Risk != nil {
+		diffItem.Pillar = string(item.Risk.Pillar)
+		diffItem.Severity = riskLevelName(item.Risk.Severity)
+		if item.Risk.Question != nil {
+			diffItem.QuestionID = item.Risk.Question.ID
+		}
+	}
+	return diffItem
+}
+
+// WritePlanDiffText writes a plan diff as human-readable text
+func WritePlanDiffText(w io.Writer, diff *PlanDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Improvement plan changes from %s to %s:\n", diff.BeforeSession, diff.AfterSession)
+	fmt.Fprintf(&b, "  Resolved:      %d\n", len(diff.Resolved))
+	fmt.Fprintf(&b, "  Introduced:    %d\n", len(diff.Introduced))
+	fmt.Fprintf(&b, "  Reprioritized: %d\n", len(diff.Reprioritized))
+	fmt.Fprintf(&b, "  Unchanged:     %d\n", diff.Unchanged)
+
+	writePlanDiffItems(&b, "Resolved", diff.Resolved)
+	writePlanDiffItems(&b, "Introduced", diff.Introduced)
+	if len(diff.Reprioritized) > 0 {
+		b.WriteString("\nReprioritized:\n")
+		for _, change := range diff.Reprioritized {
+			fmt.Fprintf(&b, "  %s: %d -> %d  %s\n", change.ID, change.Before, change.After, change.Description)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writePlanDiffItems writes a titled list of items, or nothing when empty
+func writePlanDiffItems(b *strings.Builder, title string, items []PlanDiffItem) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "  %s [%s, priority %d]  %s\n", item.ID, item.Severity, item.Priority, item.Description)
+	}
+}
+
+// WritePlanDiffJSON writes a plan diff as indented JSON
+func WritePlanDiffJSON(w io.Writer, diff *PlanDiff) error {
+	return WriteJSON(w, diff)
+}
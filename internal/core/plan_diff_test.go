@@ -0,0 +1,97 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planSession(sessionID string, items ...*ImprovementPlanItem) *ReviewSession {
+	return &ReviewSession{
+		SessionID: sessionID,
+		Results:   &ReviewResults{ImprovementPlan: &ImprovementPlan{Items: items}},
+	}
+}
+
+func planItem(questionID string, severity RiskLevel, priority int) *ImprovementPlanItem {
+	return &ImprovementPlanItem{
+		ID:          "security/" + questionID,
+		Risk:        &Risk{ID: questionID, Pillar: PillarSecurity, Severity: severity, Question: &WAFRQuestion{ID: questionID}},
+		Description: "Address " + questionID,
+		Priority:    priority,
+	}
+}
+
+func TestDiffImprovementPlans(t *testing.T) {
+	before := planSession("session-a",
+		planItem("sec_1", RiskLevelHigh, 100),
+		planItem("sec_2", RiskLevelMedium, 50),
+		planItem("sec_3", RiskLevelMedium, 50),
+	)
+	after := planSession("session-b",
+		planItem("sec_4", RiskLevelHigh, 105),
+		planItem("sec_3", RiskLevelMedium, 50),
+		planItem("sec_2", RiskLevelHigh, 100),
+	)
+
+	diff := DiffImprovementPlans(before, after)
+
+	assert.Equal(t, "session-a", diff.BeforeSession)
+	assert.Equal(t, "session-b", diff.AfterSession)
+	assert.Equal(t, []PlanDiffItem{
+		{ID: "security/sec_1", QuestionID: "sec_1", Pillar: "security", Severity: "high", Description: "Address sec_1", Priority: 100},
+	}, diff.Resolved)
+	assert.Equal(t, []PlanDiffItem{
+		{ID: "security/sec_4", QuestionID: "sec_4", Pillar: "security", Severity: "high", Description: "Address sec_4", Priority: 105},
+	}, diff.Introduced)
+	assert.Equal(t, []PlanPriorityChange{
+		{ID: "security/sec_2", QuestionID: "sec_2", Description: "Address sec_2", Before: 50, After: 100},
+	}, diff.Reprioritized)
+	assert.Equal(t, 1, diff.Unchanged)
+}
+
+func TestDiffImprovementPlans_NoResults(t *testing.T) {
+	diff := DiffImprovementPlans(&ReviewSession{SessionID: "session-a"}, planSession("session-b", planItem("sec_1", RiskLevelHigh, 100)))
+
+	assert.Empty(t, diff.Resolved)
+	require.Len(t, diff.Introduced, 1)
+	assert.Equal(t, "security/sec_1", diff.Introduced[0].ID)
+}
+
+func TestWritePlanDiffText(t *testing.T) {
+	diff := DiffImprovementPlans(
+		planSession("session-a", planItem("sec_1", RiskLevelHigh, 100), planItem("sec_2", RiskLevelMedium, 50)),
+		planSession("session-b", planItem("sec_2", RiskLevelHigh, 100)),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePlanDiffText(&buf, diff))
+
+	assert.Equal(t, `Improvement plan changes from session-a to session-b:
+  Resolved:      1
+  Introduced:    0
+  Reprioritized: 1
+  Unchanged:     0
+
+Resolved:
+  security/sec_1 [high, priority 100]  Address sec_1
+
+Reprioritized:
+  security/sec_2: 50 -> 100  Address sec_2
+`, buf.String())
+}
+
+func TestWritePlanDiffJSON(t *testing.T) {
+	diff := DiffImprovementPlans(planSession("session-a"), planSession("session-b"))
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePlanDiffJSON(&buf, diff))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, []interface{}{}, decoded["resolved"], "empty lists are written as []")
+	assert.Equal(t, float64(0), decoded["unchanged"])
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Improvement plan output formats
+const (
+	PlanFormatText = "text"
+	PlanFormatJSON = "json"
+)
+
+// PlanOutput is the improvement plan of a workload retrieved on its own,
+// without evaluating or submitting anything
+type PlanOutput struct {
+	SessionID     string `json:"session_id,omitempty"`
+	WorkloadID    string `json:"workload_id,omitempty"`
+	AWSWorkloadID string `json:"aws_workload_id"`
+	// ResourcesLinked is set when a workload model was available to link
+	// risks to the resources they affect
+	ResourcesLinked bool                 `json:"resources_linked"`
+	Improvements    []*ImprovementOutput `json:"improvements"`
+}
+
+// NewPlanOutput converts plan for output. A nil plan has no items.
+func NewPlanOutput(awsWorkloadID string, plan *ImprovementPlan) *PlanOutput {
+	output := &PlanOutput{
+		AWSWorkloadID: awsWorkloadID,
+		Improvements:  []*ImprovementOutput{},
+	}
+	if plan != nil {
+		for _, item := range plan.Items {
+			output.Improvements = append(output.Improvements, convertImprovementToOutput(item))
+		}
+	}
+	return output
+}
+
+// WriteImprovementPlanText writes an improvement plan as human-readable text
+func WriteImprovementPlanText(w io.Writer, output *PlanOutput) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Improvement plan of workload %s: %d items\n", output.AWSWorkloadID, len(output.Improvements))
+	for _, item := range output.Improvements {
+		fmt.Fprintf(&b, "\n  %s [priority %d, %s effort]  %s\n", item.ID, item.Priority, item.EstimatedEffort, item.Description)
+		if len(item.AffectedResources) > 0 {
+			fmt.Fprintf(&b, "    Affected resources: %s\n", strings.Join(item.AffectedResources, ", "))
+		}
+		for _, ref := range item.BestPracticeRefs {
+			fmt.Fprintf(&b, "    %s\n", ref)
+		}
+	}
+	if !output.ResourcesLinked && len(output.Improvements) > 0 {
+		b.WriteString("\nNo workload model was available, so items are not linked to resources.\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteImprovementPlanJSON writes an improvement plan as indented JSON
+func WriteImprovementPlanJSON(w io.Writer, output *PlanOutput) error {
+	return WriteJSON(w, output)
+}
@@ -4,22 +4,65 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"time"
 )
 
+// etaWindow is how many of the latest item durations the ETA averages over
+const etaWindow = 10
+
+// spinnerInterval is how often the spinner of a terminal progress line turns
+const spinnerInterval = 100 * time.Millisecond
+
+// spinnerFrames are drawn in turn ahead of the progress bar on a terminal
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
 // CLIProgressReporter implements ProgressReporter for CLI output
 type CLIProgressReporter struct {
-	writer io.Writer
+	writer      io.Writer
+	clock       Clock
+	interactive bool
+
+	mu  sync.Mutex
+	eta etaEstimator
+	// line is the progress line being redrawn on a terminal, without its
+	// spinner; empty when no step is in progress
+	line    string
+	frame   int
+	spinner chan struct{}
 }
 
-// NewCLIProgressReporter creates a new CLI progress reporter
+// NewCLIProgressReporter creates a new CLI progress reporter. It writes one
+// line per progress update until SetInteractive is called.
 func NewCLIProgressReporter(writer io.Writer) *CLIProgressReporter {
 	return &CLIProgressReporter{
 		writer: writer,
+		clock:  SystemClock{},
 	}
 }
 
+// SetInteractive sets whether the writer is a terminal. On a terminal the
+// progress line is redrawn in place with a spinner.
+func (p *CLIProgressReporter) SetInteractive(interactive bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interactive = interactive
+}
+
+// SetClock replaces the clock the ETA is measured with
+func (p *CLIProgressReporter) SetClock(clock Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = clock
+}
+
 // ReportStep reports the current step being executed
 func (p *CLIProgressReporter) ReportStep(step string, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endLine()
+	p.eta = etaEstimator{}
+
 	stepName := formatStepName(step)
 	fmt.Fprintf(p.writer, "\n▶ %s\n", stepName)
 	if message != "" {
@@ -27,30 +70,123 @@ func (p *CLIProgressReporter) ReportStep(step string, message string) {
 	}
 }
 
-// ReportProgress reports progress within a step
+// ReportProgress reports progress within a step, with the time left
+// estimated from the average duration of the latest items
 func (p *CLIProgressReporter) ReportProgress(current, total int, message string) {
-	if total > 0 {
-		percentage := (current * 100) / total
-		bar := progressBar(current, total, 30)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if total <= 0 {
+		p.endLine()
+		fmt.Fprintf(p.writer, "  %s\n", message)
+		return
+	}
+
+	p.eta.observe(current, p.clock.Now())
+	percentage := (current * 100) / total
+	line := fmt.Sprintf("[%s] %d%% - %s", progressBar(current, total, 30), percentage, message)
+	if remaining, ok := p.eta.remaining(total); ok {
+		line += " - ETA " + formatETA(remaining)
+	}
+
+	if !p.interactive {
+		fmt.Fprintf(p.writer, "  %s\n", line)
+		return
+	}
+
+	if current >= total {
+		p.stopSpinner()
+		p.line = ""
 		// Clear the line first to handle any log output that may have appeared
-		fmt.Fprintf(p.writer, "\r\033[K  [%s] %d%% - %s", bar, percentage, message)
-		if current == total {
-			fmt.Fprintf(p.writer, "\n")
+		fmt.Fprintf(p.writer, "\r\033[K  %s\n", line)
+		return
+	}
+	p.line = line
+	p.draw()
+	if p.spinner == nil {
+		p.spinner = make(chan struct{})
+		go p.spin(p.spinner)
+	}
+}
+
+// Statusf writes a free-form status line, such as the review parameters
+// shown before the first step
+func (p *CLIProgressReporter) Statusf(format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line != "" {
+		// The progress line is drawn again below the status on the next turn
+		fmt.Fprint(p.writer, "\r\033[K")
+	}
+	fmt.Fprintf(p.writer, format, args...)
+}
+
+// Stop ends a progress line a failed step left unfinished
+func (p *CLIProgressReporter) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endLine()
+}
+
+// endLine stops the spinner and moves past an unfinished progress line
+func (p *CLIProgressReporter) endLine() {
+	p.stopSpinner()
+	if p.line != "" {
+		fmt.Fprintln(p.writer)
+		p.line = ""
+	}
+}
+
+// stopSpinner stops the goroutine turning the spinner, if it runs
+func (p *CLIProgressReporter) stopSpinner() {
+	if p.spinner != nil {
+		close(p.spinner)
+		p.spinner = nil
+	}
+}
+
+// draw redraws the progress line with the current spinner frame
+func (p *CLIProgressReporter) draw() {
+	fmt.Fprintf(p.writer, "\r\033[K  %s %s", spinnerFrames[p.frame%len(spinnerFrames)], p.line)
+}
+
+// spin turns the spinner until stop is closed
+func (p *CLIProgressReporter) spin(stop chan struct{}) {
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			if p.spinner == stop {
+				p.frame++
+				p.draw()
+			}
+			p.mu.Unlock()
 		}
-	} else {
-		fmt.Fprintf(p.writer, "  %s\n", message)
 	}
 }
 
 // ReportCompletion reports completion of the review
 func (p *CLIProgressReporter) ReportCompletion(summary *ResultsSummary) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endLine()
+
 	fmt.Fprintf(p.writer, "\n✓ Review completed successfully!\n\n")
 	fmt.Fprintf(p.writer, "Summary:\n")
 	fmt.Fprintf(p.writer, "  Questions evaluated: %d\n", summary.QuestionsEvaluated)
 	fmt.Fprintf(p.writer, "  High risks: %d\n", summary.HighRisks)
 	fmt.Fprintf(p.writer, "  Medium risks: %d\n", summary.MediumRisks)
-	fmt.Fprintf(p.writer, "  Average confidence: %.2f\n", summary.AverageConfidence)
+	fmt.Fprintf(p.writer, "  Average confidence: %.2f (severity-weighted %.2f)\n", summary.AverageConfidence, summary.SeverityWeightedConfidence)
 	fmt.Fprintf(p.writer, "  Improvement plan items: %d\n", summary.ImprovementPlanSize)
+	for _, pillar := range AllPillars() {
+		if ps, ok := summary.PillarSummaries[pillar]; ok && ps.Advisory {
+			fmt.Fprintf(p.writer, "  %s (advisory): %d high, %d medium risks\n", pillar, ps.HighRisks, ps.MediumRisks)
+		}
+	}
 	fmt.Fprintf(p.writer, "\n")
 }
 
@@ -80,3 +216,52 @@ func progressBar(current, total, width int) string {
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
 	return bar
 }
+
+// etaEstimator estimates the time left in a step from a rolling average of
+// how long each of the latest items took
+type etaEstimator struct {
+	current   int
+	last      time.Time
+	durations []time.Duration
+}
+
+// observe records that current items were done at the given time. Items
+// completed together share the time since the previous observation. A count
+// that goes back starts a new estimate.
+func (e *etaEstimator) observe(current int, at time.Time) {
+	if e.last.IsZero() || current < e.current {
+		*e = etaEstimator{current: current, last: at}
+		return
+	}
+	if current == e.current {
+		return
+	}
+
+	perItem := at.Sub(e.last) / time.Duration(current-e.current)
+	for i := e.current; i < current; i++ {
+		e.durations = append(e.durations, perItem)
+	}
+	if len(e.durations) > etaWindow {
+		e.durations = e.durations[len(e.durations)-etaWindow:]
+	}
+	e.current, e.last = current, at
+}
+
+// remaining returns the estimated time to finish total items, or false
+// before any item duration is known or once all items are done
+func (e *etaEstimator) remaining(total int) (time.Duration, bool) {
+	if len(e.durations) == 0 || e.current >= total {
+		return 0, false
+	}
+	var sum time.Duration
+	for _, d := range e.durations {
+		sum += d
+	}
+	average := sum / time.Duration(len(e.durations))
+	return average * time.Duration(total-e.current), true
+}
+
+// formatETA formats a remaining duration to the second, such as 1m30s
+func formatETA(d time.Duration) string {
+	return max(d.Round(time.Second), time.Second).String()
+}
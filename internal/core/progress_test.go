@@ -2,8 +2,10 @@ package core
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -136,6 +138,25 @@ func TestCLIProgressReporter_ReportCompletion(t *testing.T) {
 	assert.Contains(t, output, "Medium risks: 3")
 	assert.Contains(t, output, "Average confidence: 0.85")
 	assert.Contains(t, output, "Improvement plan items: 5")
+	assert.NotContains(t, output, "advisory")
+}
+
+func TestCLIProgressReporter_ReportCompletion_AdvisoryPillars(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewCLIProgressReporter(buf)
+
+	reporter.ReportCompletion(&ResultsSummary{
+		HighRisks: 3,
+		PillarSummaries: map[Pillar]PillarSummary{
+			PillarSecurity:       {HighRisks: 1},
+			PillarSustainability: {HighRisks: 2, MediumRisks: 1, Advisory: true},
+		},
+	})
+
+	output := buf.String()
+	assert.Contains(t, output, "High risks: 3")
+	assert.Contains(t, output, "sustainability (advisory): 2 high, 1 medium risks")
+	assert.NotContains(t, output, "security (advisory)")
 }
 
 func TestFormatStepName(t *testing.T) {
@@ -271,3 +292,105 @@ func TestCLIProgressReporter_Integration(t *testing.T) {
 	require.Contains(t, output, "Questions evaluated: 3")
 	require.Contains(t, output, "High risks: 1")
 }
+
+func TestCLIProgressReporter_Statusf(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewCLIProgressReporter(buf)
+
+	reporter.Statusf("Workload ID: %s\n", "my-app")
+
+	assert.Equal(t, "Workload ID: my-app\n", buf.String())
+}
+
+func TestETAEstimator(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		// seconds is the time of each observation since start; the i-th
+		// observation reports i+1 items done
+		seconds []int
+		total   int
+		want    time.Duration
+		wantOK  bool
+	}{
+		{name: "first item only", seconds: []int{0}, total: 10},
+		{name: "steady items", seconds: []int{0, 2, 4, 6}, total: 10, want: 12 * time.Second, wantOK: true},
+		{name: "uneven items average", seconds: []int{0, 1, 4}, total: 5, want: 4 * time.Second, wantOK: true},
+		{name: "all done", seconds: []int{0, 5, 10}, total: 3},
+		{
+			name: "only the latest items count",
+			// Ten slow items followed by ten fast ones
+			seconds: []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 101, 102, 103, 104, 105, 106, 107, 108, 109, 110},
+			total:   31,
+			want:    10 * time.Second,
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var eta etaEstimator
+			for i, second := range tt.seconds {
+				eta.observe(i+1, start.Add(time.Duration(second)*time.Second))
+			}
+
+			remaining, ok := eta.remaining(tt.total)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, remaining)
+		})
+	}
+}
+
+func TestETAEstimator_SkippedAndRestartedCounts(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var eta etaEstimator
+
+	eta.observe(1, start)
+	// Three items completed together share the six seconds
+	eta.observe(4, start.Add(6*time.Second))
+	remaining, ok := eta.remaining(10)
+	require.True(t, ok)
+	assert.Equal(t, 12*time.Second, remaining)
+
+	// A count going back is a new run of items
+	eta.observe(1, start.Add(7*time.Second))
+	_, ok = eta.remaining(10)
+	assert.False(t, ok)
+}
+
+func TestCLIProgressReporter_ReportProgressETA(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewCLIProgressReporter(buf)
+	reporter.SetClock(&fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), step: 30 * time.Second})
+
+	for i := 1; i <= 4; i++ {
+		reporter.ReportProgress(i, 4, fmt.Sprintf("Evaluating question %d of 4", i))
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 4, "one line per update without a terminal")
+	assert.NotContains(t, lines[0], "ETA")
+	assert.True(t, strings.HasSuffix(lines[1], "Evaluating question 2 of 4 - ETA 1m0s"), lines[1])
+	assert.True(t, strings.HasSuffix(lines[2], "Evaluating question 3 of 4 - ETA 30s"), lines[2])
+	assert.NotContains(t, lines[3], "ETA")
+	assert.NotContains(t, buf.String(), "\r")
+}
+
+func TestCLIProgressReporter_Interactive(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewCLIProgressReporter(buf)
+	reporter.SetInteractive(true)
+
+	reporter.ReportProgress(1, 2, "Evaluating question 1 of 2")
+	reporter.ReportProgress(2, 2, "Evaluating question 2 of 2")
+
+	// The buffer is only read once the spinner has stopped
+	assert.Contains(t, buf.String(), "\r\033[K  "+spinnerFrames[0]+" [")
+	assert.True(t, strings.HasSuffix(buf.String(), "Evaluating question 2 of 2\n"))
+
+	// A step left unfinished is ended on a new line
+	reporter.ReportProgress(1, 3, "Submitting answer 1 of 3")
+	reporter.Stop()
+	assert.True(t, strings.HasSuffix(buf.String(), "Submitting answer 1 of 3\n"))
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// QuestionCacheKey identifies the questions of one workload for a set of
+// pillars
+type QuestionCacheKey struct {
+	WorkloadID string
+	Pillars    []Pillar
+}
+
+// String returns the key as "workload:pillar,pillar"
+func (k QuestionCacheKey) String() string {
+	pillars := make([]string, len(k.Pillars))
+	for i, pillar := range k.Pillars {
+		pillars[i] = string(pillar)
+	}
+	return k.WorkloadID + ":" + strings.Join(pillars, ",")
+}
+
+// CachedQuestions are questions retrieved for a lens version at a point in
+// time
+type CachedQuestions struct {
+	LensVersion string          `json:"lens_version"`
+	FetchedAt   time.Time       `json:"fetched_at"`
+	Questions   []*WAFRQuestion `json:"questions"`
+}
+
+// SetQuestionCache reuses the questions of earlier reviews of a workload for
+// up to ttl instead of retrieving them again. Entries retrieved for another
+// lens version are never reused. A zero ttl or nil cache disables it.
+func (e *Engine) SetQuestionCache(cache QuestionCache, ttl time.Duration) {
+	e.questionCache = cache
+	e.questionCacheTTL = ttl
+}
+
+// questionCacheKey returns the cache key for the questions of session and
+// whether they may be cached at all. Question scope retrieves a single
+// question, and without a lens version a lens update could not be noticed.
+func (e *Engine) questionCacheKey(session *ReviewSession) (QuestionCacheKey, bool) {
+	if e.questionCache == nil || e.questionCacheTTL <= 0 || session.LensVersion == "" {
+		return QuestionCacheKey{}, false
+	}
+
+	key := QuestionCacheKey{WorkloadID: session.WorkloadID}
+	switch session.Scope.Level {
+	case ScopeLevelWorkload:
+		key.Pillars = AllPillars()
+	case ScopeLevelPillar:
+		if session.Scope.Pillar == nil {
+			return QuestionCacheKey{}, false
+		}
+		key.Pillars = []Pillar{*session.Scope.Pillar}
+	default:
+		return QuestionCacheKey{}, false
+	}
+	return key, true
+}
+
+// cachedQuestions returns the cached questions for session when there are
+// fresh ones for its lens version. Cache failures are logged and treated as
+// a miss.
+func (e *Engine) cachedQuestions(ctx context.Context, session *ReviewSession) []*WAFRQuestion {
+	key, ok := e.questionCacheKey(session)
+	if !ok {
+		return nil
+	}
+
+	entry, err := e.questionCache.LoadQuestions(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load cached questions", "key", key.String(), "error", err)
+		return nil
+	}
+	switch {
+	case entry == nil || len(entry.Questions) == 0:
+		slog.DebugContext(ctx, "question cache miss", "key", key.String())
+		return nil
+	case entry.LensVersion != session.LensVersion:
+		slog.InfoContext(ctx, "cached questions are for another lens version",
+			"key", key.String(),
+			"cached_lens_version", entry.LensVersion,
+			"lens_version", session.LensVersion,
+		)
+		return nil
+	case e.clock.Now().Sub(entry.FetchedAt) > e.questionCacheTTL:
+		slog.DebugContext(ctx, "cached questions expired", "key", key.String(), "fetched_at", entry.FetchedAt)
+		return nil
+	}
+
+	slog.InfoContext(ctx, "using cached questions",
+		"key", key.String(),
+		"fetched_at", entry.FetchedAt,
+		"count", len(entry.Questions),
+	)
+	return entry.Questions
+}
+
+// cacheQuestions stores the questions retrieved for session. Failures are
+// logged but not returned.
+func (e *Engine) cacheQuestions(ctx context.Context, session *ReviewSession, questions []*WAFRQuestion) {
+	key, ok := e.questionCacheKey(session)
+	if !ok || len(questions) == 0 {
+		return
+	}
+
+	entry := &CachedQuestions{
+		LensVersion: session.LensVersion,
+		FetchedAt:   e.clock.Now(),
+		Questions:   questions,
+	}
+	if err := e.questionCache.StoreQuestions(ctx, key, entry); err != nil {
+		slog.WarnContext(ctx, "failed to cache questions", "key", key.String(), "error", err)
+	}
+}
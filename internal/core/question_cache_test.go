@@ -0,0 +1,213 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryQuestionCache is a QuestionCache held in memory
+type memoryQuestionCache struct {
+	entries map[string]*CachedQuestions
+	loadErr error
+}
+
+func newMemoryQuestionCache() *memoryQuestionCache {
+	return &memoryQuestionCache{entries: make(map[string]*CachedQuestions)}
+}
+
+func (c *memoryQuestionCache) LoadQuestions(ctx context.Context, key QuestionCacheKey) (*CachedQuestions, error) {
+	if c.loadErr != nil {
+		return nil, c.loadErr
+	}
+	return c.entries[key.String()], nil
+}
+
+func (c *memoryQuestionCache) StoreQuestions(ctx context.Context, key QuestionCacheKey, entry *CachedQuestions) error {
+	c.entries[key.String()] = entry
+	return nil
+}
+
+// questionCacheSession returns a session about to retrieve its questions
+func questionCacheSession(lensVersion string) *ReviewSession {
+	return &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusInProgress,
+		Checkpoint:    "iac_analysis_complete",
+		LensVersion:   lensVersion,
+		WorkloadModel: &WorkloadModel{
+			Framework: "terraform",
+			Resources: []Resource{{ID: "test-resource"}},
+		},
+	}
+}
+
+func TestQuestionCache(t *testing.T) {
+	fetchedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cachedQuestion := &WAFRQuestion{ID: "cached-1", Pillar: PillarSecurity, Title: "Cached Question"}
+	workloadKey := QuestionCacheKey{WorkloadID: "test-workload", Pillars: AllPillars()}
+
+	tests := []struct {
+		name        string
+		entry       *CachedQuestions
+		lensVersion string
+		age         time.Duration
+		wantFetch   bool
+		wantStored  string
+	}{
+		{
+			name:       "miss",
+			age:        time.Hour,
+			wantFetch:  true,
+			wantStored: "2024-06-27",
+		},
+		{
+			name:  "hit",
+			entry: &CachedQuestions{LensVersion: "2024-06-27", FetchedAt: fetchedAt, Questions: []*WAFRQuestion{cachedQuestion}},
+			age:   23 * time.Hour,
+		},
+		{
+			name:       "expired",
+			entry:      &CachedQuestions{LensVersion: "2024-06-27", FetchedAt: fetchedAt, Questions: []*WAFRQuestion{cachedQuestion}},
+			age:        25 * time.Hour,
+			wantFetch:  true,
+			wantStored: "2024-06-27",
+		},
+		{
+			name:       "lens version changed",
+			entry:      &CachedQuestions{LensVersion: "2023-10-03", FetchedAt: fetchedAt, Questions: []*WAFRQuestion{cachedQuestion}},
+			age:        time.Hour,
+			wantFetch:  true,
+			wantStored: "2024-06-27",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newMemoryQuestionCache()
+			if tt.entry != nil {
+				cache.entries[workloadKey.String()] = tt.entry
+			}
+			var fetches int
+			wafrEval := &mockWAFREvaluator{}
+			wafrEval.getQuestionsFunc = func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+				fetches++
+				return []*WAFRQuestion{{ID: "sec-1", Pillar: PillarSecurity, Title: "Test Question"}}, nil
+			}
+
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetCreateMilestone(false)
+			engine.SetQuestionCache(cache, 24*time.Hour)
+			engine.SetClock(&fakeClock{now: fetchedAt.Add(tt.age)})
+
+			results, err := engine.ExecuteReview(context.Background(), questionCacheSession("2024-06-27"))
+			require.NoError(t, err)
+			require.Len(t, results.Evaluations, 1)
+
+			if !tt.wantFetch {
+				assert.Zero(t, fetches)
+				assert.Equal(t, "cached-1", results.Evaluations[0].Question.ID)
+				return
+			}
+			assert.Equal(t, 1, fetches)
+			assert.Equal(t, "sec-1", results.Evaluations[0].Question.ID)
+
+			stored := cache.entries[workloadKey.String()]
+			require.NotNil(t, stored)
+			assert.Equal(t, tt.wantStored, stored.LensVersion)
+			assert.Equal(t, fetchedAt.Add(tt.age), stored.FetchedAt)
+			assert.Equal(t, "sec-1", stored.Questions[0].ID)
+		})
+	}
+}
+
+func TestQuestionCache_NotUsed(t *testing.T) {
+	pillar := PillarSecurity
+	tests := []struct {
+		name    string
+		session func() *ReviewSession
+		cache   *memoryQuestionCache
+		wantKey string
+	}{
+		{
+			name:    "unknown lens version",
+			session: func() *ReviewSession { return questionCacheSession("") },
+			cache:   newMemoryQuestionCache(),
+		},
+		{
+			name: "question scope",
+			session: func() *ReviewSession {
+				session := questionCacheSession("2024-06-27")
+				session.Scope = ReviewScope{Level: ScopeLevelQuestion, QuestionID: "sec-1"}
+				return session
+			},
+			cache: newMemoryQuestionCache(),
+		},
+		{
+			name: "pillar scope is cached separately",
+			session: func() *ReviewSession {
+				session := questionCacheSession("2024-06-27")
+				session.Scope = ReviewScope{Level: ScopeLevelPillar, Pillar: &pillar}
+				return session
+			},
+			cache:   newMemoryQuestionCache(),
+			wantKey: "test-workload:security",
+		},
+		{
+			name:    "unreadable cache",
+			session: func() *ReviewSession { return questionCacheSession("2024-06-27") },
+			cache:   &memoryQuestionCache{entries: make(map[string]*CachedQuestions), loadErr: errors.New("corrupt entry")},
+			wantKey: "test-workload:operationalExcellence,security,reliability,performance,costOptimization,sustainability",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches int
+			wafrEval := &mockWAFREvaluator{}
+			wafrEval.getQuestionsFunc = func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+				fetches++
+				return []*WAFRQuestion{{ID: "sec-1", Pillar: PillarSecurity, Title: "Test Question"}}, nil
+			}
+
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetCreateMilestone(false)
+			engine.SetQuestionCache(tt.cache, 24*time.Hour)
+
+			_, err := engine.ExecuteReview(context.Background(), tt.session())
+			require.NoError(t, err)
+			assert.Equal(t, 1, fetches)
+
+			if tt.wantKey == "" {
+				assert.Empty(t, tt.cache.entries)
+				return
+			}
+			require.Len(t, tt.cache.entries, 1)
+			assert.Contains(t, tt.cache.entries, tt.wantKey)
+		})
+	}
+}
+
+func TestQuestionCache_PartialRetrievalNotCached(t *testing.T) {
+	cache := newMemoryQuestionCache()
+	wafrEval := &mockWAFREvaluator{}
+	wafrEval.getQuestionsFunc = func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+		return []*WAFRQuestion{{ID: "sec-1", Pillar: PillarSecurity, Title: "Test Question"}},
+			&PillarRetrievalError{Failures: map[Pillar]error{PillarReliability: errors.New("throttled")}}
+	}
+
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetCreateMilestone(false)
+	engine.SetQuestionCache(cache, 24*time.Hour)
+
+	_, err := engine.ExecuteReview(context.Background(), questionCacheSession("2024-06-27"))
+	require.NoError(t, err)
+	assert.Empty(t, cache.entries)
+}
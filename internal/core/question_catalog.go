@@ -0,0 +1,55 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// QuestionCatalog is a static set of WAFR questions evaluated in place of
+// those retrieved from the Well-Architected Tool. Its questions have the
+// form of question cache entries, so a cached entry can serve as a catalog.
+type QuestionCatalog struct {
+	LensVersion string          `json:"lens_version,omitempty"`
+	Questions   []*WAFRQuestion `json:"questions"`
+}
+
+// Validate checks that the catalog has questions, each with a unique ID, a
+// known pillar and choices to answer with
+func (c *QuestionCatalog) Validate() error {
+	if len(c.Questions) == 0 {
+		return errors.New("catalog has no questions")
+	}
+
+	seen := make(map[string]bool, len(c.Questions))
+	for i, question := range c.Questions {
+		switch {
+		case question == nil:
+			return fmt.Errorf("question %d is empty", i+1)
+		case question.ID == "":
+			return fmt.Errorf("question %d has no ID", i+1)
+		case seen[question.ID]:
+			return fmt.Errorf("question %s is listed more than once", question.ID)
+		case !question.Pillar.IsValid():
+			return fmt.Errorf("question %s: unknown pillar %q, must be one of: %s", question.ID, question.Pillar, pillarList())
+		case len(question.Choices) == 0:
+			return fmt.Errorf("question %s has no choices", question.ID)
+		}
+		seen[question.ID] = true
+	}
+	return nil
+}
+
+// QuestionsInScope returns the catalog questions within scope
+func (c *QuestionCatalog) QuestionsInScope(scope ReviewScope) []*WAFRQuestion {
+	var questions []*WAFRQuestion
+	for _, question := range c.Questions {
+		switch {
+		case scope.Level == ScopeLevelPillar && scope.Pillar != nil && question.Pillar != *scope.Pillar:
+			continue
+		case scope.Level == ScopeLevelQuestion && question.ID != scope.QuestionID:
+			continue
+		}
+		questions = append(questions, question)
+	}
+	return questions
+}
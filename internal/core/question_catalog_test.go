@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testQuestionCatalog is a catalog in the form it is read from a file
+const testQuestionCatalog = `{
+  "lens_version": "2024-06-27",
+  "questions": [
+    {"ID": "data-rest", "Pillar": "security", "Title": "How do you protect your data at rest?", "Choices": [{"ID": "sec_data_rest_encrypt_at_rest"}]},
+    {"ID": "identities", "Pillar": "security", "Title": "How do you manage identities?", "Choices": [{"ID": "sec_identities_mfa"}]},
+    {"ID": "backing-up-data", "Pillar": "reliability", "Title": "How do you back up data?", "Choices": [{"ID": "rel_backing_up_data_automated"}]}
+  ]
+}`
+
+func TestQuestionCatalog(t *testing.T) {
+	security := PillarSecurity
+
+	tests := []struct {
+		name          string
+		scope         ReviewScope
+		wantEvaluated []string
+		wantErr       string
+	}{
+		{
+			name:          "workload",
+			scope:         ReviewScope{Level: ScopeLevelWorkload},
+			wantEvaluated: []string{"data-rest", "identities", "backing-up-data"},
+		},
+		{
+			name:          "pillar",
+			scope:         ReviewScope{Level: ScopeLevelPillar, Pillar: &security},
+			wantEvaluated: []string{"data-rest", "identities"},
+		},
+		{
+			name:          "question",
+			scope:         ReviewScope{Level: ScopeLevelQuestion, QuestionID: "identities"},
+			wantEvaluated: []string{"identities"},
+		},
+		{
+			name:    "question not in catalog",
+			scope:   ReviewScope{Level: ScopeLevelQuestion, QuestionID: "permissions"},
+			wantErr: "question catalog catalog.json has no questions in the review scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var catalog QuestionCatalog
+			require.NoError(t, json.Unmarshal([]byte(testQuestionCatalog), &catalog))
+			require.NoError(t, catalog.Validate())
+
+			var evaluated []string
+			wafrEval := &mockWAFREvaluator{
+				getQuestionsFunc: func(ctx context.Context, awsWorkloadID string, scope ReviewScope) ([]*WAFRQuestion, error) {
+					t.Fatal("questions must not be retrieved from AWS")
+					return nil, nil
+				},
+				evaluateQuestionFunc: func(ctx context.Context, question *WAFRQuestion, workloadModel *WorkloadModel) (*QuestionEvaluation, error) {
+					evaluated = append(evaluated, question.ID)
+					return &QuestionEvaluation{Question: question, ConfidenceScore: 0.9}, nil
+				},
+			}
+			cache := newMemoryQuestionCache()
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetCreateMilestone(false)
+			engine.SetQuestionCache(cache, 24*time.Hour)
+			engine.SetQuestionCatalog(&catalog, "catalog.json")
+
+			session := questionCacheSession("2024-06-27")
+			session.Scope = tt.scope
+			_, err := engine.ExecuteReview(context.Background(), session)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantEvaluated, evaluated)
+			assert.Empty(t, cache.entries, "catalog questions are not cached")
+
+			// Results are marked as evaluated against the catalog
+			assert.Equal(t, "catalog.json", session.QuestionCatalog)
+			output := ConvertReviewSessionToOutput(session)
+			assert.Equal(t, "catalog.json", output.Metadata["question_catalog"])
+		})
+	}
+}
+
+func TestQuestionCatalog_WorkloadCreatedForSubmission(t *testing.T) {
+	tests := []struct {
+		name string
+		// createErr fails workload creation, as when AWS cannot be reached
+		createErr     error
+		lensVersion   string
+		wantErr       string
+		wantSubmitted []string
+	}{
+		{
+			name:          "catalog matches the workload's lens",
+			lensVersion:   "2024-06-27",
+			wantSubmitted: []string{"backing-up-data", "data-rest", "identities"},
+		},
+		{
+			name:        "catalog is for another lens version",
+			lensVersion: "2025-02-25",
+			wantErr:     "answer submission failed: lens version mismatch: question catalog catalog.json is for 2024-06-27, workload uses 2025-02-25",
+		},
+		{
+			name:      "AWS cannot be reached",
+			createErr: errors.New("no route to host"),
+			wantErr:   "answer submission failed: failed to create AWS workload: no route to host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var catalog QuestionCatalog
+			require.NoError(t, json.Unmarshal([]byte(testQuestionCatalog), &catalog))
+
+			created := 0
+			var submitted []string
+			wafrEval := &lensVersionEvaluator{
+				mockWAFREvaluator: &mockWAFREvaluator{
+					createWorkloadFunc: func(ctx context.Context, workloadID string, description string) (string, error) {
+						created++
+						return "aws-workload-123", tt.createErr
+					},
+					submitAnswerFunc: func(ctx context.Context, awsWorkloadID string, questionID string, evaluation *QuestionEvaluation) error {
+						assert.Equal(t, "aws-workload-123", awsWorkloadID)
+						submitted = append(submitted, questionID)
+						return nil
+					},
+				},
+				version: tt.lensVersion,
+			}
+			engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+			engine.SetCreateMilestone(false)
+			engine.SetQuestionCatalog(&catalog, "catalog.json")
+
+			session, err := engine.InitiateReview(context.Background(), "test-workload", ReviewScope{Level: ScopeLevelWorkload})
+			require.NoError(t, err)
+			// Nothing is called in AWS until answers are submitted
+			assert.Zero(t, created)
+			assert.Empty(t, session.AWSWorkloadID)
+
+			_, err = engine.ExecuteReview(context.Background(), session)
+
+			assert.Equal(t, 1, created)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, submitted)
+				// The evaluations are kept for a resumed review to submit
+				assert.Equal(t, "questions_evaluated", session.Checkpoint)
+				assert.Len(t, session.Results.Evaluations, 3)
+
+				// A resumed review has no catalog set and checks the lens
+				// version recorded on the session
+				resumed := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, wafrEval, &mockBedrockClient{}, &mockReportGenerator{})
+				resumed.SetCreateMilestone(false)
+				_, err = resumed.ExecuteReview(context.Background(), session)
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, submitted)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "aws-workload-123", session.AWSWorkloadID)
+			assert.Equal(t, tt.lensVersion, session.LensVersion)
+			assert.ElementsMatch(t, tt.wantSubmitted, submitted)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResourceTypeMapping extends the built-in resource types that are relevant
+// to each pillar and question. Types match resource types by prefix, so
+// aws_s3 matches aws_s3_bucket.
+type ResourceTypeMapping struct {
+	Pillars   map[Pillar][]string `yaml:"pillars"`
+	Questions map[string][]string `yaml:"questions"`
+	// Requires lists, per question, the resource types of which at least one
+	// must be present for the question to apply to the workload
+	Requires map[string][]string `yaml:"requires"`
+}
+
+// Validate checks that every pillar is a Well-Architected pillar, every
+// question ID is set and every resource type is a single non-empty word
+func (m *ResourceTypeMapping) Validate() error {
+	pillars := make([]string, 0, len(m.Pillars))
+	for pillar := range m.Pillars {
+		pillars = append(pillars, string(pillar))
+	}
+	sort.Strings(pillars)
+	for _, pillar := range pillars {
+		if !Pillar(pillar).IsValid() {
+			return fmt.Errorf("unknown pillar %q, must be one of: %s", pillar, pillarList())
+		}
+		if err := validateResourceTypes(m.Pillars[Pillar(pillar)]); err != nil {
+			return fmt.Errorf("pillar %s: %w", pillar, err)
+		}
+	}
+
+	if err := validateQuestionResourceTypes(m.Questions); err != nil {
+		return err
+	}
+	if err := validateQuestionResourceTypes(m.Requires); err != nil {
+		return fmt.Errorf("requires: %w", err)
+	}
+	return nil
+}
+
+// ResourceTypes returns the types the mapping adds for a question and its
+// pillar. A nil mapping adds none.
+func (m *ResourceTypeMapping) ResourceTypes(questionID string, pillar Pillar) []string {
+	if m == nil {
+		return nil
+	}
+	types := append([]string{}, m.Pillars[pillar]...)
+	return append(types, m.Questions[questionID]...)
+}
+
+// RequiredResourceTypes returns the types of which at least one must be
+// present for a question to apply. Nil means the mapping sets no
+// requirement, leaving any built-in one in place.
+func (m *ResourceTypeMapping) RequiredResourceTypes(questionID string) []string {
+	if m == nil {
+		return nil
+	}
+	return m.Requires[questionID]
+}
+
+// validateQuestionResourceTypes checks resource types keyed by question ID
+func validateQuestionResourceTypes(questions map[string][]string) error {
+	questionIDs := make([]string, 0, len(questions))
+	for questionID := range questions {
+		questionIDs = append(questionIDs, questionID)
+	}
+	sort.Strings(questionIDs)
+	for _, questionID := range questionIDs {
+		if strings.TrimSpace(questionID) == "" {
+			return fmt.Errorf("resource types for an empty question ID")
+		}
+		if err := validateResourceTypes(questions[questionID]); err != nil {
+			return fmt.Errorf("question %s: %w", questionID, err)
+		}
+	}
+	return nil
+}
+
+// validateResourceTypes checks a list of resource types
+func validateResourceTypes(types []string) error {
+	if len(types) == 0 {
+		return fmt.Errorf("no resource types")
+	}
+	for _, resourceType := range types {
+		if resourceType == "" || strings.ContainsAny(resourceType, " \t\r\n") {
+			return fmt.Errorf("invalid resource type %q", resourceType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DefaultConfidenceTolerance is how far a confidence score may drop below
+// the expected score before VerifyResults reports it
+const DefaultConfidenceTolerance = 0.1
+
+// ResultsSnapshot is the part of a results JSON file that VerifyResults
+// compares. Identifiers and timestamps of the run are not read.
+type ResultsSnapshot struct {
+	SchemaVersion string `json:"schema_version"`
+	Summary       struct {
+		HighRisks         int     `json:"high_risks"`
+		MediumRisks       int     `json:"medium_risks"`
+		AverageConfidence float64 `json:"average_confidence"`
+	} `json:"summary"`
+	ImprovementPlan struct {
+		Items []struct {
+			Risk struct {
+				Severity RiskLevel `json:"severity"`
+			} `json:"risk"`
+			Question struct {
+				ID string `json:"id"`
+			} `json:"question"`
+		} `json:"items"`
+	} `json:"improvement_plan"`
+	Evaluations []struct {
+		QuestionID      string  `json:"question_id"`
+		ConfidenceScore float64 `json:"confidence_score"`
+	} `json:"evaluations"`
+}
+
+// ReadResultsSnapshot decodes a results JSON document
+func ReadResultsSnapshot(r io.Reader) (*ResultsSnapshot, error) {
+	var snapshot ResultsSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// ResultsVerification lists how actual results differ from expected ones.
+// Regressions fail a verification; Changes, such as a risk that went away,
+// do not.
+type ResultsVerification struct {
+	Regressions []string
+	Changes     []string
+}
+
+// Passed reports whether the actual results have no regression
+func (v *ResultsVerification) Passed() bool {
+	return len(v.Regressions) == 0
+}
+
+// VerifyResults compares actual results with expected ones. A regression is
+// a question answered in expected but not in actual, a question whose risk
+// got more severe, more high or medium risks in the summary, or a
+// confidence score, per question or on average, more than tolerance below
+// the expected score.
+func VerifyResults(expected, actual *ResultsSnapshot, tolerance float64) *ResultsVerification {
+	v := &ResultsVerification{}
+
+	if expected.SchemaVersion != actual.SchemaVersion {
+		v.Changes = append(v.Changes, fmt.Sprintf("schema version changed from %s to %s", expected.SchemaVersion, actual.SchemaVersion))
+	}
+
+	v.compareCount("high risks", expected.Summary.HighRisks, actual.Summary.HighRisks)
+	v.compareCount("medium risks", expected.Summary.MediumRisks, actual.Summary.MediumRisks)
+	if drop := expected.Summary.AverageConfidence - actual.Summary.AverageConfidence; drop > tolerance {
+		v.Regressions = append(v.Regressions, fmt.Sprintf("average confidence dropped from %.2f to %.2f", expected.Summary.AverageConfidence, actual.Summary.AverageConfidence))
+	}
+
+	expectedConfidence := snapshotConfidence(expected)
+	actualConfidence := snapshotConfidence(actual)
+	for _, questionID := range slices.Sorted(maps.Keys(expectedConfidence)) {
+		score, ok := actualConfidence[questionID]
+		switch {
+		case !ok:
+			v.Regressions = append(v.Regressions, fmt.Sprintf("%s: no longer answered", questionID))
+		case expectedConfidence[questionID]-score > tolerance:
+			v.Regressions = append(v.Regressions, fmt.Sprintf("%s: confidence dropped from %.2f to %.2f", questionID, expectedConfidence[questionID], score))
+		}
+	}
+	for _, questionID := range slices.Sorted(maps.Keys(actualConfidence)) {
+		if _, ok := expectedConfidence[questionID]; !ok {
+			v.Changes = append(v.Changes, fmt.Sprintf("%s: newly answered", questionID))
+		}
+	}
+
+	expectedRisks := snapshotRisks(expected)
+	actualRisks := snapshotRisks(actual)
+	questions := slices.Concat(slices.Collect(maps.Keys(expectedRisks)), slices.Collect(maps.Keys(actualRisks)))
+	slices.Sort(questions)
+	for _, questionID := range slices.Compact(questions) {
+		before, after := expectedRisks[questionID], actualRisks[questionID]
+		switch {
+		case riskRank(after) > riskRank(before):
+			v.Regressions = append(v.Regressions, fmt.Sprintf("%s: risk increased from %s to %s", questionID, riskLevelName(before), riskLevelName(after)))
+		case riskRank(after) < riskRank(before):
+			v.Changes = append(v.Changes, fmt.Sprintf("%s: risk decreased from %s to %s", questionID, riskLevelName(before), riskLevelName(after)))
+		}
+	}
+
+	return v
+}
+
+// compareCount records a summary count that grew as a regression and one
+// that shrank as a change
+func (v *ResultsVerification) compareCount(name string, expected, actual int) {
+	switch {
+	case actual > expected:
+		v.Regressions = append(v.Regressions, fmt.Sprintf("%s increased from %d to %d", name, expected, actual))
+	case actual < expected:
+		v.Changes = append(v.Changes, fmt.Sprintf("%s decreased from %d to %d", name, expected, actual))
+	}
+}
+
+// snapshotConfidence maps the questions evaluated to their confidence score
+func snapshotConfidence(snapshot *ResultsSnapshot) map[string]float64 {
+	scores := make(map[string]float64, len(snapshot.Evaluations))
+	for _, evaluation := range snapshot.Evaluations {
+		scores[evaluation.QuestionID] = evaluation.ConfidenceScore
+	}
+	return scores
+}
+
+// snapshotRisks maps the questions of the improvement plan to their most
+// severe risk
+func snapshotRisks(snapshot *ResultsSnapshot) map[string]RiskLevel {
+	risks := make(map[string]RiskLevel)
+	for _, item := range snapshot.ImprovementPlan.Items {
+		if current, ok := risks[item.Question.ID]; !ok || riskRank(item.Risk.Severity) > riskRank(current) {
+			risks[item.Question.ID] = item.Risk.Severity
+		}
+	}
+	return risks
+}
+
+// riskRank orders risk levels by severity. An unassessed question ranks
+// with one that has no risk, so answering it is not a change.
+func riskRank(level RiskLevel) int {
+	switch level {
+	case RiskLevelHigh:
+		return 2
+	case RiskLevelMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// WriteResultsVerificationText writes a verification as human-readable text
+func WriteResultsVerificationText(w io.Writer, v *ResultsVerification) error {
+	var b strings.Builder
+	if v.Passed() {
+		b.WriteString("Results match the expected results\n")
+	} else {
+		fmt.Fprintf(&b, "%d regressions:\n", len(v.Regressions))
+		for _, regression := range v.Regressions {
+			fmt.Fprintf(&b, "  - %s\n", regression)
+		}
+	}
+	if len(v.Changes) > 0 {
+		b.WriteString("Other changes:\n")
+		for _, change := range v.Changes {
+			fmt.Fprintf(&b, "  - %s\n", change)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
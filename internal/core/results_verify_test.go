@@ -0,0 +1,127 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const expectedResultsJSON = `{
+  "schema_version": "1.0",
+  "session_id": "session-1",
+  "workload_id": "my-app",
+  "created_at": "2026-01-05T10:00:00Z",
+  "summary": {"questions_evaluated": 3, "high_risks": 1, "medium_risks": 1, "average_confidence": 0.8, "improvement_plan_size": 2},
+  "improvement_plan": {
+    "items": [
+      {"id": "security/sec_data_1", "priority": 110, "risk": {"pillar": "security", "severity": 2}, "question": {"id": "sec_data_1"}},
+      {"id": "reliability/rel_backup_1", "priority": 60, "risk": {"pillar": "reliability", "severity": 1}, "question": {"id": "rel_backup_1"}}
+    ],
+    "total": 2
+  },
+  "evaluations": [
+    {"question_id": "sec_data_1", "confidence_score": 0.8},
+    {"question_id": "rel_backup_1", "confidence_score": 0.75},
+    {"question_id": "ops_tags_1", "confidence_score": 0.85}
+  ]
+}`
+
+func readSnapshot(t *testing.T, document string) *ResultsSnapshot {
+	t.Helper()
+	snapshot, err := ReadResultsSnapshot(strings.NewReader(document))
+	require.NoError(t, err)
+	return snapshot
+}
+
+func TestVerifyResults_Match(t *testing.T) {
+	// A later run of the same workload: new IDs and timestamps, slightly
+	// different confidence scores
+	actual := strings.NewReplacer(
+		`"session_id": "session-1"`, `"session_id": "session-2"`,
+		"2026-01-05T10:00:00Z", "2026-02-01T08:30:00Z",
+		`"confidence_score": 0.8}`, `"confidence_score": 0.74}`,
+		`"average_confidence": 0.8`, `"average_confidence": 0.76`,
+	).Replace(expectedResultsJSON)
+
+	v := VerifyResults(readSnapshot(t, expectedResultsJSON), readSnapshot(t, actual), DefaultConfidenceTolerance)
+
+	assert.True(t, v.Passed())
+	assert.Empty(t, v.Regressions)
+	assert.Empty(t, v.Changes)
+}
+
+func TestVerifyResults_Regression(t *testing.T) {
+	actual := `{
+  "schema_version": "1.0",
+  "summary": {"questions_evaluated": 2, "high_risks": 2, "medium_risks": 0, "average_confidence": 0.6},
+  "improvement_plan": {
+    "items": [
+      {"risk": {"severity": 2}, "question": {"id": "sec_data_1"}},
+      {"risk": {"severity": 2}, "question": {"id": "rel_backup_1"}}
+    ]
+  },
+  "evaluations": [
+    {"question_id": "sec_data_1", "confidence_score": 0.5},
+    {"question_id": "rel_backup_1", "confidence_score": 0.7},
+    {"question_id": "cost_tags_1", "confidence_score": 0.9}
+  ]
+}`
+
+	v := VerifyResults(readSnapshot(t, expectedResultsJSON), readSnapshot(t, actual), DefaultConfidenceTolerance)
+
+	assert.False(t, v.Passed())
+	assert.Equal(t, []string{
+		"high risks increased from 1 to 2",
+		"average confidence dropped from 0.80 to 0.60",
+		"ops_tags_1: no longer answered",
+		"sec_data_1: confidence dropped from 0.80 to 0.50",
+		"rel_backup_1: risk increased from medium to high",
+	}, v.Regressions)
+	assert.Equal(t, []string{
+		"medium risks decreased from 1 to 0",
+		"cost_tags_1: newly answered",
+	}, v.Changes)
+}
+
+func TestVerifyResults_ResolvedRisk(t *testing.T) {
+	actual := strings.Replace(expectedResultsJSON,
+		`{"id": "reliability/rel_backup_1", "priority": 60, "risk": {"pillar": "reliability", "severity": 1}, "question": {"id": "rel_backup_1"}}`,
+		`{"id": "reliability/rel_backup_1", "priority": 0, "risk": {"pillar": "reliability", "severity": 0}, "question": {"id": "rel_backup_1"}}`, 1)
+	actual = strings.Replace(actual, `"medium_risks": 1`, `"medium_risks": 0`, 1)
+
+	v := VerifyResults(readSnapshot(t, expectedResultsJSON), readSnapshot(t, actual), DefaultConfidenceTolerance)
+
+	assert.True(t, v.Passed(), "fewer risks are not a regression")
+	assert.Equal(t, []string{
+		"medium risks decreased from 1 to 0",
+		"rel_backup_1: risk decreased from medium to none",
+	}, v.Changes)
+}
+
+func TestVerifyResults_Tolerance(t *testing.T) {
+	actual := strings.Replace(expectedResultsJSON, `"confidence_score": 0.85}`, `"confidence_score": 0.7}`, 1)
+
+	assert.False(t, VerifyResults(readSnapshot(t, expectedResultsJSON), readSnapshot(t, actual), DefaultConfidenceTolerance).Passed())
+	assert.True(t, VerifyResults(readSnapshot(t, expectedResultsJSON), readSnapshot(t, actual), 0.2).Passed())
+}
+
+func TestReadResultsSnapshot_Invalid(t *testing.T) {
+	_, err := ReadResultsSnapshot(strings.NewReader("not json"))
+	assert.Error(t, err)
+}
+
+func TestWriteResultsVerificationText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteResultsVerificationText(&buf, &ResultsVerification{
+		Regressions: []string{"high risks increased from 1 to 2"},
+		Changes:     []string{"cost_tags_1: newly answered"},
+	}))
+	assert.Equal(t, "1 regressions:\n  - high risks increased from 1 to 2\nOther changes:\n  - cost_tags_1: newly answered\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteResultsVerificationText(&buf, &ResultsVerification{}))
+	assert.Equal(t, "Results match the expected results\n", buf.String())
+}
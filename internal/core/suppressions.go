@@ -0,0 +1,92 @@
+package core
+
+import "slices"
+
+// Suppression records that a resource's annotation ignored the risk of a
+// question for it
+type Suppression struct {
+	QuestionID string
+	Resource   string
+	Reason     string
+}
+
+// applySuppressions removes the resources that ignore a question from the
+// question's risk. A question concerns the resources its evidence cites and
+// those its risk already lists. When every one of them ignores the question
+// its risk is dropped and its evaluation marked suppressed.
+func applySuppressions(evaluations []*QuestionEvaluation, risks []*Risk, model *WorkloadModel) ([]*Risk, []Suppression) {
+	if model == nil {
+		return risks, nil
+	}
+
+	ignores := make(map[string]map[string]string)
+	for _, resource := range model.Resources {
+		for _, ignore := range resource.Ignores {
+			if ignores[ignore.QuestionID] == nil {
+				ignores[ignore.QuestionID] = make(map[string]string)
+			}
+			ignores[ignore.QuestionID][resource.Address] = ignore.Reason
+		}
+	}
+	if len(ignores) == 0 {
+		return risks, nil
+	}
+
+	risksByQuestion := make(map[string]*Risk)
+	for _, risk := range risks {
+		if risk.Question != nil {
+			risksByQuestion[risk.Question.ID] = risk
+		}
+	}
+
+	var suppressions []Suppression
+	suppressedQuestions := make(map[string]bool)
+	for _, eval := range evaluations {
+		if eval == nil || eval.Question == nil || ignores[eval.Question.ID] == nil {
+			continue
+		}
+		questionID := eval.Question.ID
+		risk := risksByQuestion[questionID]
+
+		var concerned []string
+		for _, evidence := range eval.Evidence {
+			for _, address := range evidence.Resources {
+				if !slices.Contains(concerned, address) {
+					concerned = append(concerned, address)
+				}
+			}
+		}
+		if risk != nil {
+			for _, address := range risk.AffectedResources {
+				if !slices.Contains(concerned, address) {
+					concerned = append(concerned, address)
+				}
+			}
+		}
+
+		ignored := 0
+		for _, address := range concerned {
+			reason, ok := ignores[questionID][address]
+			if !ok {
+				continue
+			}
+			ignored++
+			suppressions = append(suppressions, Suppression{QuestionID: questionID, Resource: address, Reason: reason})
+			if risk != nil {
+				risk.AffectedResources = slices.DeleteFunc(risk.AffectedResources, func(a string) bool { return a == address })
+			}
+		}
+		if ignored > 0 && ignored == len(concerned) {
+			eval.Suppressed = true
+			suppressedQuestions[questionID] = true
+		}
+	}
+
+	kept := risks[:0]
+	for _, risk := range risks {
+		if risk.Question == nil || !suppressedQuestions[risk.Question.ID] {
+			kept = append(kept, risk)
+		}
+	}
+	return kept, suppressions
+}
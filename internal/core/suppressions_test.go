@@ -0,0 +1,72 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySuppressions(t *testing.T) {
+	model := &WorkloadModel{
+		Resources: []Resource{
+			{
+				Address: "aws_s3_bucket.logs",
+				Ignores: []ResourceIgnore{{QuestionID: "sec_data_1", Reason: "compensating control X"}},
+			},
+			{
+				Address: "aws_s3_bucket.assets",
+				Ignores: []ResourceIgnore{{QuestionID: "sec_data_2"}},
+			},
+			{Address: "aws_s3_bucket.uploads"},
+		},
+	}
+	dataRest := &WAFRQuestion{ID: "sec_data_1", Pillar: PillarSecurity}
+	dataTransit := &WAFRQuestion{ID: "sec_data_2", Pillar: PillarSecurity}
+	evaluations := []*QuestionEvaluation{
+		{Question: dataRest, Evidence: []Evidence{{Resources: []string{"aws_s3_bucket.logs"}}}, ConfidenceScore: 0.1},
+		{Question: dataTransit, Evidence: []Evidence{{Resources: []string{"aws_s3_bucket.assets", "aws_s3_bucket.uploads"}}}, ConfidenceScore: 0.1},
+	}
+	risks := []*Risk{
+		{ID: "risk-sec_data_1", Question: dataRest, AffectedResources: []string{"aws_s3_bucket.logs"}},
+		{ID: "risk-sec_data_2", Question: dataTransit, AffectedResources: []string{"aws_s3_bucket.assets", "aws_s3_bucket.uploads"}},
+	}
+
+	kept, suppressions := applySuppressions(evaluations, risks, model)
+
+	// The only resource sec_data_1 concerns ignores it, so its risk is dropped
+	require.Len(t, kept, 1)
+	assert.Equal(t, "risk-sec_data_2", kept[0].ID)
+	assert.Equal(t, []string{"aws_s3_bucket.uploads"}, kept[0].AffectedResources)
+	assert.True(t, evaluations[0].Suppressed)
+	assert.False(t, evaluations[1].Suppressed)
+	assert.Equal(t, []Suppression{
+		{QuestionID: "sec_data_1", Resource: "aws_s3_bucket.logs", Reason: "compensating control X"},
+		{QuestionID: "sec_data_2", Resource: "aws_s3_bucket.assets"},
+	}, suppressions)
+
+	// Suppressed evaluations count toward no risk totals
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	summary := engine.buildSummary(evaluations, nil)
+	assert.Equal(t, 1, summary.HighRisks)
+	assert.Equal(t, 1, summary.PillarSummaries[PillarSecurity].HighRisks)
+}
+
+func TestApplySuppressions_NoIgnores(t *testing.T) {
+	question := &WAFRQuestion{ID: "sec_data_1"}
+	evaluations := []*QuestionEvaluation{{Question: question, Evidence: []Evidence{{Resources: []string{"aws_s3_bucket.logs"}}}}}
+	risks := []*Risk{{ID: "risk-sec_data_1", Question: question}}
+	model := &WorkloadModel{Resources: []Resource{
+		// An annotation for another question leaves this one alone
+		{Address: "aws_s3_bucket.logs", Ignores: []ResourceIgnore{{QuestionID: "sec_data_2"}}},
+	}}
+
+	kept, suppressions := applySuppressions(evaluations, risks, model)
+	assert.Equal(t, risks, kept)
+	assert.Empty(t, suppressions)
+	assert.False(t, evaluations[0].Suppressed)
+
+	kept, suppressions = applySuppressions(evaluations, risks, nil)
+	assert.Equal(t, risks, kept)
+	assert.Empty(t, suppressions)
+}
@@ -0,0 +1,64 @@
+package core
+
+import "time"
+
+// Review step names used in timings, matching the steps reported to a
+// ProgressReporter
+const (
+	StepIaCAnalysis       = "iac_analysis"
+	StepRetrieveQuestions = "retrieve_questions"
+	StepEvaluateQuestions = "evaluate_questions"
+	StepSubmitAnswers     = "submit_answers"
+	StepImprovementPlan   = "improvement_plan"
+	StepCreateMilestone   = "create_milestone"
+)
+
+// StepTiming is how long one review step took
+type StepTiming struct {
+	Step string
+	// Offset is when the step started, relative to the start of the workflow
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+// ReviewTimings records where the time of a review execution went. Steps
+// completed by an earlier, interrupted execution of a resumed session are
+// not included.
+type ReviewTimings struct {
+	Steps []StepTiming
+	Total time.Duration
+}
+
+// stepTimer measures workflow steps against a clock
+type stepTimer struct {
+	now     func() time.Time
+	start   time.Time
+	timings *ReviewTimings
+}
+
+func newStepTimer(now func() time.Time) *stepTimer {
+	return &stepTimer{
+		now:     now,
+		start:   now(),
+		timings: &ReviewTimings{Steps: []StepTiming{}},
+	}
+}
+
+// begin starts timing a step. Calling the returned function records it.
+func (t *stepTimer) begin(step string) func() {
+	started := t.now()
+	return func() {
+		t.timings.Steps = append(t.timings.Steps, StepTiming{
+			Step:     step,
+			Offset:   started.Sub(t.start),
+			Duration: t.now().Sub(started),
+		})
+	}
+}
+
+// finish returns the recorded steps and the time elapsed since the timer was
+// created
+func (t *stepTimer) finish() *ReviewTimings {
+	t.timings.Total = t.now().Sub(t.start)
+	return t.timings
+}
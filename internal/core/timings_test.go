@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances by step each time it is read
+type fakeClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestExecuteReview_Timings(t *testing.T) {
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+	engine.SetClock(&fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), step: time.Second})
+
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusCreated,
+	}
+
+	results, err := engine.ExecuteReview(context.Background(), session)
+	require.NoError(t, err)
+	require.NotNil(t, results.Timings)
+
+	var steps []string
+	for _, step := range results.Timings.Steps {
+		steps = append(steps, step.Step)
+	}
+	assert.Equal(t, []string{
+		StepIaCAnalysis,
+		StepRetrieveQuestions,
+		StepEvaluateQuestions,
+		StepSubmitAnswers,
+		StepImprovementPlan,
+		StepCreateMilestone,
+	}, steps)
+
+	// Each step starts after the previous one ends, and the total covers all
+	var end time.Duration
+	for _, step := range results.Timings.Steps {
+		assert.Positive(t, step.Duration, step.Step)
+		assert.GreaterOrEqual(t, step.Offset, end, step.Step)
+		end = step.Offset + step.Duration
+	}
+	assert.GreaterOrEqual(t, results.Timings.Total, end)
+}
+
+func TestExecuteReview_TimingsSkipCompletedSteps(t *testing.T) {
+	engine := NewEngine(&mockSessionManager{}, &mockIaCAnalyzer{}, &mockWAFREvaluator{}, &mockBedrockClient{}, &mockReportGenerator{})
+
+	// A session resumed after answers were submitted only retrieves the
+	// improvement plan and creates the milestone
+	session := &ReviewSession{
+		SessionID:     "test-session",
+		WorkloadID:    "test-workload",
+		AWSWorkloadID: "aws-workload-123",
+		Scope:         ReviewScope{Level: ScopeLevelWorkload},
+		Status:        SessionStatusInProgress,
+		Checkpoint:    "answers_submitted",
+	}
+
+	results, err := engine.ExecuteReview(context.Background(), session)
+	require.NoError(t, err)
+
+	require.Len(t, results.Timings.Steps, 2)
+	assert.Equal(t, StepImprovementPlan, results.Timings.Steps[0].Step)
+	assert.Equal(t, StepCreateMilestone, results.Timings.Steps[1].Step)
+}
+
+func TestConvertReviewTimingsToOutput(t *testing.T) {
+	assert.Nil(t, ConvertReviewTimingsToOutput(nil))
+
+	output := ConvertReviewTimingsToOutput(&ReviewTimings{
+		Steps: []StepTiming{
+			{Step: StepIaCAnalysis, Offset: 0, Duration: 1500 * time.Millisecond},
+			{Step: StepRetrieveQuestions, Offset: 2 * time.Second, Duration: 250 * time.Millisecond},
+		},
+		Total: 3 * time.Second,
+	})
+
+	assert.Equal(t, &TimingsOutput{
+		Steps: []StepTimingOutput{
+			{Step: StepIaCAnalysis, OffsetMS: 0, DurationMS: 1500},
+			{Step: StepRetrieveQuestions, OffsetMS: 2000, DurationMS: 250},
+		},
+		TotalMS: 3000,
+	}, output)
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -13,6 +16,20 @@ const (
 	ScopeLevelQuestion
 )
 
+// String returns the scope level name accepted by the --scope flag
+func (l ScopeLevel) String() string {
+	switch l {
+	case ScopeLevelWorkload:
+		return "workload"
+	case ScopeLevelPillar:
+		return "pillar"
+	case ScopeLevelQuestion:
+		return "question"
+	default:
+		return fmt.Sprintf("ScopeLevel(%d)", int(l))
+	}
+}
+
 // Pillar represents a Well-Architected Framework pillar
 type Pillar string
 
@@ -25,6 +42,37 @@ const (
 	PillarSustainability        Pillar = "sustainability"
 )
 
+// AllPillars returns the Well-Architected pillars in framework order
+func AllPillars() []Pillar {
+	return []Pillar{
+		PillarOperationalExcellence,
+		PillarSecurity,
+		PillarReliability,
+		PillarPerformanceEfficiency,
+		PillarCostOptimization,
+		PillarSustainability,
+	}
+}
+
+// pillarList returns the pillar names as a comma-separated list
+func pillarList() string {
+	names := make([]string, 0, len(AllPillars()))
+	for _, pillar := range AllPillars() {
+		names = append(names, string(pillar))
+	}
+	return strings.Join(names, ", ")
+}
+
+// IsValid reports whether p is one of the Well-Architected pillars
+func (p Pillar) IsValid() bool {
+	for _, pillar := range AllPillars() {
+		if p == pillar {
+			return true
+		}
+	}
+	return false
+}
+
 // SessionStatus represents the status of a review session
 type SessionStatus string
 
@@ -42,8 +90,34 @@ const (
 	RiskLevelNone RiskLevel = iota
 	RiskLevelMedium
 	RiskLevelHigh
+	// RiskLevelUnassessed marks a question nobody has answered yet. Its risk
+	// is unknown rather than none, so it is not ordered with the levels above.
+	RiskLevelUnassessed
 )
 
+// RiskThresholds controls how evaluation confidence scores map to risks.
+// Scores are compared with a strict less-than against each threshold.
+type RiskThresholds struct {
+	// RiskConfidenceThreshold flags an evaluation as a risk when its
+	// confidence falls below it (default 0.5)
+	RiskConfidenceThreshold float64
+	// HighConfidenceThreshold counts an evaluation as a high risk in the
+	// summary when its confidence falls below it (default 0.3)
+	HighConfidenceThreshold float64
+	// MediumConfidenceThreshold counts an evaluation as a medium risk in the
+	// summary when its confidence falls below it (default 0.7)
+	MediumConfidenceThreshold float64
+}
+
+// DefaultRiskThresholds returns the built-in confidence-to-risk mapping
+func DefaultRiskThresholds() RiskThresholds {
+	return RiskThresholds{
+		RiskConfidenceThreshold:   0.5,
+		HighConfidenceThreshold:   0.3,
+		MediumConfidenceThreshold: 0.7,
+	}
+}
+
 // ReviewScope defines the scope of a WAFR review
 type ReviewScope struct {
 	Level      ScopeLevel
@@ -51,28 +125,53 @@ type ReviewScope struct {
 	QuestionID string
 }
 
-// Validate checks if the review scope is valid
+// Validate checks if the review scope is valid. Every problem found is
+// reported, joined into a single error.
 func (r *ReviewScope) Validate() error {
+	var errs []error
+
 	switch r.Level {
+	case ScopeLevelWorkload:
 	case ScopeLevelPillar:
 		if r.Pillar == nil {
-			return ErrPillarRequired
+			errs = append(errs, ErrPillarRequired)
 		}
 	case ScopeLevelQuestion:
 		if r.QuestionID == "" {
-			return ErrQuestionIDRequired
+			errs = append(errs, ErrQuestionIDRequired)
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%w: %d", ErrInvalidScopeLevel, r.Level))
+	}
+
+	if r.Pillar != nil {
+		if !r.Pillar.IsValid() {
+			errs = append(errs, fmt.Errorf("%w '%s', must be one of: %s", ErrInvalidPillar, *r.Pillar, pillarList()))
+		}
+		if r.Level != ScopeLevelPillar {
+			errs = append(errs, fmt.Errorf("%w, got pillar '%s' with %s scope", ErrUnexpectedPillar, *r.Pillar, r.Level))
 		}
 	}
-	return nil
+
+	if r.QuestionID != "" && r.Level != ScopeLevelQuestion {
+		errs = append(errs, fmt.Errorf("%w, got question ID '%s' with %s scope", ErrUnexpectedQuestionID, r.QuestionID, r.Level))
+	}
+
+	return errors.Join(errs...)
 }
 
 // ReviewSession represents a WAFR review session
 type ReviewSession struct {
 	SessionID     string
+	CorrelationID string
 	WorkloadID    string
 	AWSWorkloadID string
 	MilestoneID   string
+	LensVersion   string
 	PlanFilePath  string
+	// FailedPillars maps pillars skipped during question retrieval to the error
+	FailedPillars map[Pillar]string
+	ReportDrift   bool
 	Scope         ReviewScope
 	Status        SessionStatus
 	CreatedAt     time.Time
@@ -80,6 +179,41 @@ type ReviewSession struct {
 	WorkloadModel *WorkloadModel
 	Results       *ReviewResults
 	Checkpoint    string
+	// WorkloadDeleted is set when the AWS workload was removed after the review
+	WorkloadDeleted bool
+	// QuestionsSkipped counts questions left out by a question cap
+	QuestionsSkipped int
+	// PreservedQuestions lists the questions left out because they were
+	// already answered in the Well-Architected Tool
+	PreservedQuestions []string
+	// ExistingNotes holds the notes of the answered questions an
+	// overwriting review evaluates again, keyed by question ID
+	ExistingNotes map[string]string
+	// MilestoneSkipped is set when milestone creation was turned off
+	MilestoneSkipped bool
+	// ImprovementPlanError is why the improvement plan could not be
+	// retrieved, in which case the review went on with an empty plan
+	ImprovementPlanError string
+	// QuestionCatalog is the static catalog the questions were read from
+	// instead of the Well-Architected Tool, empty when they were retrieved
+	QuestionCatalog string
+	// QuestionCatalogLensVersion is the lens version the question catalog
+	// declares, kept so a resumed review can check it before submitting
+	QuestionCatalogLensVersion string
+	// RequireFreshPlan fails the review when the plan file is missing
+	// resources declared in the configuration
+	RequireFreshPlan bool
+	// ExportedIssues records the issues created for improvement items, so
+	// exporting again does not duplicate them
+	ExportedIssues []ExportedIssue
+}
+
+// ExportedIssue is an issue created in a tracker for an improvement item
+type ExportedIssue struct {
+	// Target is the tracker, such as github or jira
+	Target string
+	ItemID string
+	URL    string
 }
 
 // WorkloadModel represents the parsed IaC workload
@@ -89,8 +223,43 @@ type WorkloadModel struct {
 	Framework     string
 	SourceType    string
 	Metadata      map[string]interface{}
+	// Drift lists declared properties whose plan values differ. It is only
+	// populated when configuration and plan models are merged.
+	Drift []PropertyDrift
+	// DestructiveChanges lists stateful resources a plan deletes or replaces
+	DestructiveChanges []DestructiveChange
+	// Advisories are findings about the configuration as a whole rather than
+	// any one resource, such as loosely pinned provider versions
+	Advisories []Advisory
+	// PublicExposure lists the resources reachable from the public internet
+	PublicExposure []ExposedResource
+	// MissingFromPlan lists the managed configuration resources absent from
+	// the plan, which predates them. It is only populated when configuration
+	// and plan models are merged.
+	MissingFromPlan []string
+	// Context holds redacted non-IaC documents attached to the review
+	Context []ContextDocument
 }
 
+// ContextDocument is a text file, such as a runbook or architecture notes,
+// given to the model alongside the IaC
+type ContextDocument struct {
+	Path    string
+	Content string
+}
+
+// ResourceKind is how a resource came to be in the workload model
+type ResourceKind string
+
+const (
+	// ResourceKindManaged is a resource Terraform creates and manages.
+	// Resources of modules are managed too; their ModulePath tells them
+	// apart from those of the root configuration.
+	ResourceKindManaged ResourceKind = "managed"
+	// ResourceKindData is a data source, a read-only lookup
+	ResourceKindData ResourceKind = "data"
+)
+
 // Resource represents an infrastructure resource
 type Resource struct {
 	ID           string
@@ -102,6 +271,115 @@ type Resource struct {
 	SourceLine   int
 	IsFromPlan   bool
 	ModulePath   string
+	Kind         ResourceKind
+	// Hints are risks spotted by static inspection of the properties
+	Hints []ResourceHint
+	// Ignores are the waffle:ignore annotations found in comments next to
+	// the resource in its source
+	Ignores []ResourceIgnore
+	// Fingerprint is a hash of the type and normalized properties, set by
+	// resource extraction. It changes when the resource's content does.
+	Fingerprint string
+}
+
+// ResourceIgnore suppresses the risk of one question for a resource
+type ResourceIgnore struct {
+	QuestionID string
+	Reason     string
+}
+
+// ResourceHint is a high-signal anti-pattern found in a resource's
+// properties, such as a publicly readable bucket
+type ResourceHint struct {
+	Rule     string
+	Pillar   Pillar
+	Severity RiskLevel
+	Message  string
+}
+
+// IsDataSource reports whether the resource is a Terraform data source.
+// Data sources are read-only lookups rather than deployed infrastructure.
+func (r Resource) IsDataSource() bool {
+	parts := strings.Split(r.Address, ".")
+	// Skip module path prefixes such as module.vpc.module.subnets
+	for len(parts) >= 2 && parts[0] == "module" {
+		parts = parts[2:]
+	}
+	return len(parts) > 0 && parts[0] == "data"
+}
+
+// PropertyDrift records a resource property whose declared configuration value
+// differs from the value in the Terraform plan
+type PropertyDrift struct {
+	Address     string
+	Property    string
+	ConfigValue interface{}
+	PlanValue   interface{}
+}
+
+// DestructiveChange is a planned delete or replacement of a stateful
+// resource, which loses the data it holds unless it was backed up
+type DestructiveChange struct {
+	Address string
+	Type    string
+	// Action is "delete" or "replace"
+	Action string
+}
+
+// ProviderRequirement is a provider declared in the required_providers of a
+// terraform block
+type ProviderRequirement struct {
+	Name   string
+	Source string
+	// Version is the version constraint, empty when none is given
+	Version string
+}
+
+// DocumentationCoverage counts the variables and outputs that carry a
+// description and the module directories that have a README
+type DocumentationCoverage struct {
+	Variables          int
+	DescribedVariables int
+	Outputs            int
+	DescribedOutputs   int
+	Modules            int
+	ModulesWithReadme  int
+	// UndocumentedVariables and UndocumentedOutputs are addresses such as
+	// var.env, ModulesWithoutReadme are directories
+	UndocumentedVariables []string
+	UndocumentedOutputs   []string
+	ModulesWithoutReadme  []string
+}
+
+// Ratio returns the documented share of variables, outputs and modules
+// together, or 0 when there are none
+func (d DocumentationCoverage) Ratio() float64 {
+	total := d.Variables + d.Outputs + d.Modules
+	if total == 0 {
+		return 0
+	}
+	return float64(d.DescribedVariables+d.DescribedOutputs+d.ModulesWithReadme) / float64(total)
+}
+
+// Advisory is a workload-level finding of static inspection
+type Advisory struct {
+	Rule   string
+	Pillar Pillar
+	// Source is the file or provider the advisory is about
+	Source  string
+	Message string
+}
+
+// ExposedResource is a resource reachable from the public internet
+type ExposedResource struct {
+	Address string
+	Type    string
+	// Reason says why the resource is exposed
+	Reason string
+	// Path lists the resources traffic from the internet passes through to
+	// reach it, starting at the exposed entry point. It is empty when the
+	// resource is exposed directly.
+	Path []string
 }
 
 // ResourceGraph represents relationships between resources
@@ -135,6 +413,29 @@ type Choice struct {
 	Description string
 }
 
+// Source identifies the subsystem that produced an evaluation or a risk
+type Source string
+
+// Sources of evaluations and risks
+const (
+	// SourceModel is an answer of the configured model provider, Bedrock or
+	// an OpenAI-compatible API
+	SourceModel Source = "model"
+	// SourceOverride is an answer from an answer overrides file
+	SourceOverride Source = "override"
+	// SourceInteractive is an answer whose choices were confirmed or
+	// changed in interactive review
+	SourceInteractive Source = "interactive"
+	// SourceStaticCheck is produced by inspecting resources without a model
+	SourceStaticCheck Source = "static-check"
+	// SourceCustomRule is produced by a user-defined rule, such as the
+	// resource types a question requires
+	SourceCustomRule Source = "custom-rule"
+	// SourceWAFR is a risk rated by the Well-Architected Tool from the
+	// submitted answers
+	SourceWAFR Source = "wafr"
+)
+
 // QuestionEvaluation represents the evaluation of a WAFR question
 type QuestionEvaluation struct {
 	Question        *WAFRQuestion
@@ -142,6 +443,44 @@ type QuestionEvaluation struct {
 	Evidence        []Evidence
 	ConfidenceScore float64
 	Notes           string
+	// ParseRetries is the number of times the model was re-prompted because
+	// its response could not be parsed
+	ParseRetries int
+	// ConfidenceBreakdown records the factors behind ConfidenceScore
+	ConfidenceBreakdown *ConfidenceBreakdown
+	// TimedOut is set when the evaluation exceeded the per-question timeout.
+	// The evaluation then selects no choices and has zero confidence.
+	TimedOut bool
+	// Suppressed is set when every resource the question concerns ignores
+	// it. The evaluation then counts toward no risk totals.
+	Suppressed bool
+	// NotApplicable is set when the workload has none of the resource types
+	// the question requires. The question is answered as not applicable and
+	// counts toward no risk totals.
+	NotApplicable bool
+	// Overridden is set when the answer came from an answer override
+	// instead of the model
+	Overridden bool
+	// Source is the subsystem that produced the answer
+	Source Source
+	// ConsideredResources lists the addresses of the resources the model was
+	// given for this question. It is empty when it was given all of them.
+	ConsideredResources []string
+	// RelevantResourceTypes are the resource type prefixes the question
+	// concerns, nil when the answer did not come from the model
+	RelevantResourceTypes []string
+	// ExistingNotes are the notes of an answer the review overwrites. They
+	// are kept ahead of Waffle's notes when the answer is submitted.
+	ExistingNotes string
+}
+
+// ExistingAnswer is the answer a question had in the Well-Architected Tool
+// before the review
+type ExistingAnswer struct {
+	SelectedChoices []string
+	// Notes are the question notes, without any notes an earlier Waffle
+	// review appended
+	Notes string
 }
 
 // Evidence represents evidence for a choice selection
@@ -154,13 +493,18 @@ type Evidence struct {
 
 // Risk represents an identified risk
 type Risk struct {
-	ID                   string
-	Question             *WAFRQuestion
-	Pillar               Pillar
-	Severity             RiskLevel
+	ID       string
+	Question *WAFRQuestion
+	Pillar   Pillar
+	Severity RiskLevel
+	// OriginalSeverity is the Well-Architected Tool's rating when a question
+	// severity override replaced it, nil otherwise
+	OriginalSeverity     *RiskLevel
 	Description          string
 	AffectedResources    []string
 	MissingBestPractices []BestPractice
+	// Source is the subsystem that produced the risk
+	Source Source
 }
 
 // ImprovementPlanItem represents an improvement recommendation
@@ -172,6 +516,25 @@ type ImprovementPlanItem struct {
 	AffectedResources []string
 	Priority          int
 	EstimatedEffort   string
+	// BlastRadius is the number of resources that depend, directly or
+	// transitively, on the affected resources
+	BlastRadius int
+	// Remediation describes the concrete IaC changes that address the risk,
+	// e.g. the Terraform attributes to add or change. Empty when no guidance
+	// was generated.
+	Remediation string
+}
+
+// ImprovementItemID returns the ID of the improvement item that addresses a
+// risk, <pillar>/<question_id>. It depends only on the question, so the item
+// keeps its ID whatever order risks are retrieved in and from one review to
+// the next.
+func ImprovementItemID(risk *Risk) string {
+	questionID := risk.ID
+	if risk.Question != nil && risk.Question.ID != "" {
+		questionID = risk.Question.ID
+	}
+	return string(risk.Pillar) + "/" + questionID
 }
 
 // ImprovementPlan represents the complete improvement plan
@@ -185,16 +548,46 @@ type ReviewResults struct {
 	Risks           []*Risk
 	ImprovementPlan *ImprovementPlan
 	Summary         *ResultsSummary
+	// Timings is how long each step of the execution that produced the
+	// results took
+	Timings *ReviewTimings
+	// Suppressions records the risks ignored by resource annotations
+	Suppressions []Suppression
 }
 
 // ResultsSummary provides a summary of review results
 type ResultsSummary struct {
-	TotalQuestions      int
-	QuestionsEvaluated  int
-	HighRisks           int
-	MediumRisks         int
-	AverageConfidence   float64
-	ImprovementPlanSize int
+	TotalQuestions     int
+	QuestionsEvaluated int
+	HighRisks          int
+	MediumRisks        int
+	AverageConfidence  float64
+	// SeverityWeightedConfidence averages confidence weighting each
+	// question by its severity in the Well-Architected Tool, so an uncertain
+	// answer to a high-risk question lowers it more than AverageConfidence
+	SeverityWeightedConfidence float64
+	ImprovementPlanSize        int
+	PillarSummaries            map[Pillar]PillarSummary
+	// Partial is set when a question cap left questions unevaluated.
+	// TotalQuestions then includes the skipped questions.
+	Partial          bool
+	QuestionsSkipped int
+	// TimedOutQuestions lists the IDs of questions whose evaluation exceeded
+	// the per-question timeout
+	TimedOutQuestions []string
+	// PreservedQuestions lists the IDs of questions left unevaluated because
+	// they were already answered
+	PreservedQuestions []string
+}
+
+// PillarSummary contains the summary statistics for a single pillar
+type PillarSummary struct {
+	QuestionsEvaluated int
+	HighRisks          int
+	MediumRisks        int
+	AverageConfidence  float64
+	// Advisory pillars are reported but not held to a baseline
+	Advisory bool
 }
 
 // IaCFile represents an infrastructure-as-code file
@@ -202,3 +595,27 @@ type IaCFile struct {
 	Path    string
 	Content string
 }
+
+// AnalysisWarningKind classifies a problem IaC analysis worked around
+type AnalysisWarningKind string
+
+// Analysis warning kinds
+const (
+	// AnalysisWarningRedaction means sensitive data was found and redacted
+	AnalysisWarningRedaction AnalysisWarningKind = "redaction"
+	// AnalysisWarningParse means part of a file could not be parsed and was
+	// skipped or left incomplete
+	AnalysisWarningParse AnalysisWarningKind = "parse"
+)
+
+// AnalysisWarning is a problem IaC analysis worked around instead of failing
+type AnalysisWarning struct {
+	Kind AnalysisWarningKind
+	// Source is the file, resource or module the warning is about
+	Source  string
+	Message string
+}
+
+func (w AnalysisWarning) String() string {
+	return fmt.Sprintf("%s: %s: %s", w.Kind, w.Source, w.Message)
+}
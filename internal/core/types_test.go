@@ -53,6 +53,30 @@ func TestReviewScope_Validate(t *testing.T) {
 			wantErr: true,
 			errType: ErrQuestionIDRequired,
 		},
+		{
+			name: "unknown pillar",
+			scope: ReviewScope{
+				Level:  ScopeLevelPillar,
+				Pillar: ptrToPillar(Pillar("securty")),
+			},
+			wantErr: true,
+			errType: ErrInvalidPillar,
+		},
+		{
+			name:    "unknown scope level",
+			scope:   ReviewScope{Level: ScopeLevel(7)},
+			wantErr: true,
+			errType: ErrInvalidScopeLevel,
+		},
+		{
+			name: "workload scope with question ID",
+			scope: ReviewScope{
+				Level:      ScopeLevelWorkload,
+				QuestionID: "sec_data_classification_1",
+			},
+			wantErr: true,
+			errType: ErrUnexpectedQuestionID,
+		},
 	}
 
 	for _, tt := range tests {
@@ -71,6 +95,116 @@ func TestReviewScope_Validate(t *testing.T) {
 	}
 }
 
+func TestReviewScope_ValidateReportsAllErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    ReviewScope
+		wantErrs []error
+	}{
+		{
+			name: "invalid pillar and stray question ID",
+			scope: ReviewScope{
+				Level:      ScopeLevelPillar,
+				Pillar:     ptrToPillar(Pillar("securty")),
+				QuestionID: "sec_data_classification_1",
+			},
+			wantErrs: []error{ErrInvalidPillar, ErrUnexpectedQuestionID},
+		},
+		{
+			name: "missing question ID and stray pillar",
+			scope: ReviewScope{
+				Level:  ScopeLevelQuestion,
+				Pillar: ptrToPillar(PillarSecurity),
+			},
+			wantErrs: []error{ErrQuestionIDRequired, ErrUnexpectedPillar},
+		},
+		{
+			name: "workload scope with invalid pillar and question ID",
+			scope: ReviewScope{
+				Level:      ScopeLevelWorkload,
+				Pillar:     ptrToPillar(Pillar("cost")),
+				QuestionID: "cost_1",
+			},
+			wantErrs: []error{ErrInvalidPillar, ErrUnexpectedPillar, ErrUnexpectedQuestionID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scope.Validate()
+
+			require.Error(t, err)
+			for _, want := range tt.wantErrs {
+				assert.ErrorIs(t, err, want)
+			}
+			joined, ok := err.(interface{ Unwrap() []error })
+			require.True(t, ok, "errors should be joined")
+			assert.Len(t, joined.Unwrap(), len(tt.wantErrs))
+		})
+	}
+}
+
+func TestPillar_IsValid(t *testing.T) {
+	for _, pillar := range AllPillars() {
+		assert.True(t, pillar.IsValid(), pillar)
+	}
+	assert.False(t, Pillar("cost").IsValid())
+	assert.False(t, Pillar("").IsValid())
+}
+
 func ptrToPillar(p Pillar) *Pillar {
 	return &p
 }
+
+func TestResource_IsDataSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    bool
+	}{
+		{name: "managed resource", address: "aws_s3_bucket.logs", want: false},
+		{name: "root data source", address: "data.aws_ami.ubuntu", want: true},
+		{name: "module data source", address: "module.vpc.data.aws_availability_zones.available", want: true},
+		{name: "nested module resource", address: "module.vpc.module.subnets.aws_subnet.private", want: false},
+		{name: "resource named data", address: "aws_s3_bucket.data", want: false},
+		{name: "empty address", address: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Resource{Address: tt.address}.IsDataSource())
+		})
+	}
+}
+
+func TestScopeLevel_String(t *testing.T) {
+	assert.Equal(t, "workload", ScopeLevelWorkload.String())
+	assert.Equal(t, "pillar", ScopeLevelPillar.String())
+	assert.Equal(t, "question", ScopeLevelQuestion.String())
+	assert.Equal(t, "ScopeLevel(7)", ScopeLevel(7).String())
+}
+
+func TestImprovementItemID(t *testing.T) {
+	tests := []struct {
+		name string
+		risk *Risk
+		want string
+	}{
+		{
+			name: "question ID",
+			risk: &Risk{ID: "risk-1", Pillar: PillarSecurity, Question: &WAFRQuestion{ID: "sec_data_1"}},
+			want: "security/sec_data_1",
+		},
+		{
+			name: "risk without a question",
+			risk: &Risk{ID: "rel_backup_1", Pillar: PillarReliability},
+			want: "reliability/rel_backup_1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ImprovementItemID(tt.risk))
+		})
+	}
+}
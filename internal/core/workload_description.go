@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// MaxWorkloadDescriptionLength is the maximum description length accepted by
+// the AWS Well-Architected Tool
+const MaxWorkloadDescriptionLength = 250
+
+// DefaultWorkloadDescriptionTemplate is used when no description template is configured
+const DefaultWorkloadDescriptionTemplate = "{{if .Description}}{{.Description}} | {{end}}Automated WAFR review by Waffle{{if .WaffleVersion}} {{.WaffleVersion}}{{end}}" +
+	"{{if .SourceDir}} | source: {{.SourceDir}}{{if .GitRef}} @ {{.GitRef}}{{end}}{{end}}" +
+	"{{if .ResourceCount}} | resources: {{.ResourceCount}}{{end}}"
+
+// WorkloadProvenance describes where a reviewed workload came from.
+// Its fields are available to the workload description template.
+type WorkloadProvenance struct {
+	WorkloadID    string
+	SourceDir     string
+	GitRef        string
+	GitSHA        string
+	WaffleVersion string
+	ResourceCount int
+	// Description comes from the workload metadata file, if any
+	Description string
+}
+
+// WorkloadDescriptionOptions controls how the AWS workload description is built
+type WorkloadDescriptionOptions struct {
+	// Template is a text/template rendered with WorkloadProvenance
+	Template string
+	// Provenance holds the values available to the template
+	Provenance WorkloadProvenance
+	// UpdateExisting refreshes the description of the workload once the
+	// resource count is known, including workloads that were reused
+	UpdateExisting bool
+}
+
+// RenderWorkloadDescription renders a workload description template.
+// An empty template falls back to DefaultWorkloadDescriptionTemplate and the
+// result is truncated to MaxWorkloadDescriptionLength.
+func RenderWorkloadDescription(tmpl string, provenance WorkloadProvenance) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultWorkloadDescriptionTemplate
+	}
+
+	t, err := template.New("workload_description").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", &ValidationError{
+			Field:   "workload_description_template",
+			Value:   tmpl,
+			Message: fmt.Sprintf("invalid template: %v", err),
+		}
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, provenance); err != nil {
+		return "", &ValidationError{
+			Field:   "workload_description_template",
+			Value:   tmpl,
+			Message: fmt.Sprintf("failed to render template: %v", err),
+		}
+	}
+
+	description := strings.TrimSpace(sb.String())
+	if len(description) > MaxWorkloadDescriptionLength {
+		cut := MaxWorkloadDescriptionLength
+		// Do not split a multi-byte character
+		for cut > 0 && !utf8.RuneStart(description[cut]) {
+			cut--
+		}
+		description = description[:cut]
+	}
+
+	return description, nil
+}
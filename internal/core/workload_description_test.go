@@ -0,0 +1,94 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWorkloadDescription(t *testing.T) {
+	tests := []struct {
+		name       string
+		tmpl       string
+		provenance WorkloadProvenance
+		want       string
+		wantErr    bool
+	}{
+		{
+			name: "default template with full provenance",
+			provenance: WorkloadProvenance{
+				SourceDir:     "/src/app",
+				GitRef:        "main",
+				WaffleVersion: "1.2.0",
+				ResourceCount: 12,
+			},
+			want: "Automated WAFR review by Waffle 1.2.0 | source: /src/app @ main | resources: 12",
+		},
+		{
+			name: "default template with metadata description",
+			provenance: WorkloadProvenance{
+				WaffleVersion: "1.2.0",
+				Description:   "Payments API",
+			},
+			want: "Payments API | Automated WAFR review by Waffle 1.2.0",
+		},
+		{
+			name:       "default template without provenance",
+			provenance: WorkloadProvenance{},
+			want:       "Automated WAFR review by Waffle",
+		},
+		{
+			name:       "custom template",
+			tmpl:       "{{.WorkloadID}} reviewed at {{.GitRef}}",
+			provenance: WorkloadProvenance{WorkloadID: "payments", GitRef: "abc123"},
+			want:       "payments reviewed at abc123",
+		},
+		{
+			name:    "invalid template syntax",
+			tmpl:    "{{.WorkloadID",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			tmpl:    "{{.Owner}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderWorkloadDescription(tt.tmpl, tt.provenance)
+			if tt.wantErr {
+				require.Error(t, err)
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderWorkloadDescription_Truncates(t *testing.T) {
+	provenance := WorkloadProvenance{SourceDir: "/" + strings.Repeat("a", 400)}
+
+	got, err := RenderWorkloadDescription("", provenance)
+
+	require.NoError(t, err)
+	assert.Len(t, got, MaxWorkloadDescriptionLength)
+}
+
+func TestRenderWorkloadDescription_TruncatesOnRuneBoundary(t *testing.T) {
+	// 'é' is two bytes, so byte 250 falls in the middle of a character
+	provenance := WorkloadProvenance{SourceDir: "/" + strings.Repeat("é", 200)}
+
+	got, err := RenderWorkloadDescription("{{.SourceDir}}", provenance)
+
+	require.NoError(t, err)
+	assert.True(t, utf8.ValidString(got))
+	assert.Equal(t, "/"+strings.Repeat("é", 124), got)
+}
@@ -0,0 +1,43 @@
+package core
+
+// WorkloadMetadataFileName is the optional file in the source directory that
+// describes the reviewed workload
+const WorkloadMetadataFileName = "waffle-workload.yaml"
+
+// WorkloadMetadata describes a workload for governance purposes. It is read
+// from WorkloadMetadataFileName and applied when the AWS workload is created.
+type WorkloadMetadata struct {
+	// Owner is recorded as the review owner of the AWS workload
+	Owner        string            `yaml:"owner" json:"owner,omitempty"`
+	BusinessUnit string            `yaml:"business_unit" json:"business_unit,omitempty"`
+	Criticality  string            `yaml:"criticality" json:"criticality,omitempty"`
+	Description  string            `yaml:"description" json:"description,omitempty"`
+	Tags         map[string]string `yaml:"tags" json:"tags,omitempty"`
+}
+
+// WorkloadTags returns the tags for the AWS workload: the owner, business
+// unit and criticality, overridden by any explicitly listed tags
+func (m *WorkloadMetadata) WorkloadTags() map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	tags := make(map[string]string, len(m.Tags)+3)
+	if m.Owner != "" {
+		tags["owner"] = m.Owner
+	}
+	if m.BusinessUnit != "" {
+		tags["business-unit"] = m.BusinessUnit
+	}
+	if m.Criticality != "" {
+		tags["criticality"] = m.Criticality
+	}
+	for key, value := range m.Tags {
+		tags[key] = value
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
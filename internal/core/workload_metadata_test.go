@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadMetadata_WorkloadTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata *WorkloadMetadata
+		want     map[string]string
+	}{
+		{
+			name: "nil metadata",
+		},
+		{
+			name:     "no tagged fields",
+			metadata: &WorkloadMetadata{Description: "Payments API"},
+		},
+		{
+			name: "fields and explicit tags",
+			metadata: &WorkloadMetadata{
+				Owner:        "platform-team",
+				BusinessUnit: "payments",
+				Criticality:  "high",
+				Tags:         map[string]string{"cost-center": "1234"},
+			},
+			want: map[string]string{
+				"owner":         "platform-team",
+				"business-unit": "payments",
+				"criticality":   "high",
+				"cost-center":   "1234",
+			},
+		},
+		{
+			name: "explicit tags override fields",
+			metadata: &WorkloadMetadata{
+				Owner: "platform-team",
+				Tags:  map[string]string{"owner": "sre"},
+			},
+			want: map[string]string{"owner": "sre"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.metadata.WorkloadTags())
+		})
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/waffle/waffle/internal/core"
 	"github.com/waffle/waffle/internal/redaction"
 	"github.com/zclconf/go-cty/cty"
@@ -26,8 +31,10 @@ const (
 
 // Analyzer implements the IaCAnalyzer interface
 type Analyzer struct {
-	workingDir string
-	redactor   *redaction.Redactor
+	workingDir   string
+	redactor     *redaction.Redactor
+	warnings     []core.AnalysisWarning
+	stateFetcher StateFetcher
 }
 
 // NewAnalyzer creates a new IaC analyzer
@@ -46,6 +53,40 @@ func NewAnalyzerWithDir(workingDir string) *Analyzer {
 	}
 }
 
+// SetStateFetcher lets ParseTerraformPlan read the current state of a
+// Terraform Cloud workspace given as a tfc:// reference
+func (a *Analyzer) SetStateFetcher(fetcher StateFetcher) {
+	a.stateFetcher = fetcher
+}
+
+// WorkingDir returns the directory the analyzer reads IaC files from
+func (a *Analyzer) WorkingDir() string {
+	return a.workingDir
+}
+
+// AnalysisWarnings returns the secrets redacted and the parse problems
+// skipped over so far
+func (a *Analyzer) AnalysisWarnings() []core.AnalysisWarning {
+	return a.warnings
+}
+
+// warn records an analysis warning
+func (a *Analyzer) warn(kind core.AnalysisWarningKind, source, format string, args ...interface{}) {
+	a.warnings = append(a.warnings, core.AnalysisWarning{
+		Kind:    kind,
+		Source:  source,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// warnRedacted records a redaction warning for the secret findings among
+// findings
+func (a *Analyzer) warnRedacted(source string, findings []string) {
+	if secrets := redaction.SecretFindings(findings); len(secrets) > 0 {
+		a.warn(core.AnalysisWarningRedaction, source, "redacted %s", strings.Join(secrets, ", "))
+	}
+}
+
 // RetrieveIaCFiles retrieves IaC files from the current directory
 func (a *Analyzer) RetrieveIaCFiles(ctx context.Context) ([]core.IaCFile, error) {
 	// Validate directory access
@@ -174,6 +215,7 @@ func (a *Analyzer) RetrieveIaCFiles(ctx context.Context) ([]core.IaCFile, error)
 				"findings", findings,
 			)
 		}
+		a.warnRedacted(relPath, findings)
 
 		files = append(files, core.IaCFile{
 			Path:    relPath,
@@ -212,7 +254,28 @@ func (a *Analyzer) RetrieveIaCFiles(ctx context.Context) ([]core.IaCFile, error)
 // isTerraformFile checks if a file is a Terraform file
 func isTerraformFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
-	return ext == ".tf" || ext == ".tfvars"
+	return ext == ".tf" || ext == ".tfvars" || isTerraformJSONFile(path) || isTfvarsJSONFile(path)
+}
+
+// isTerraformJSONFile checks if a file uses Terraform's JSON configuration
+// syntax (.tf.json). These are configuration files, not plan or state JSON.
+func isTerraformJSONFile(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".tf.json")
+}
+
+// isTfvarsJSONFile checks if a file is a variable definitions file in JSON
+// syntax (.tfvars.json)
+func isTfvarsJSONFile(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".tfvars.json")
+}
+
+// parseTerraformFile parses a Terraform file with the JSON or native HCL
+// parser depending on its extension
+func parseTerraformFile(parser *hclparse.Parser, file core.IaCFile) (*hcl.File, hcl.Diagnostics) {
+	if isTerraformJSONFile(file.Path) || isTfvarsJSONFile(file.Path) {
+		return parser.ParseJSON([]byte(file.Content), file.Path)
+	}
+	return parser.ParseHCL([]byte(file.Content), file.Path)
 }
 
 // ValidateTerraformFiles validates Terraform file syntax
@@ -245,7 +308,7 @@ func (a *Analyzer) ValidateTerraformFiles(ctx context.Context, files []core.IaCF
 		}
 
 		// Parse the HCL file
-		_, diags := parser.ParseHCL([]byte(file.Content), file.Path)
+		_, diags := parseTerraformFile(parser, file)
 		
 		// Collect diagnostics
 		if diags.HasErrors() {
@@ -297,34 +360,17 @@ func (a *Analyzer) ValidateTerraformFiles(ctx context.Context, files []core.IaCF
 	return nil
 }
 
-// ParseTerraformPlan parses a Terraform JSON file (plan or state)
+// ParseTerraformPlan parses a Terraform JSON file (plan or state). A
+// tfc://<organization>/<workspace> reference is read with the state fetcher
+// instead of from disk.
 func (a *Analyzer) ParseTerraformPlan(ctx context.Context, jsonFilePath string) (*core.WorkloadModel, error) {
 	slog.InfoContext(ctx, "parsing terraform JSON file",
 		"json_file", jsonFilePath,
 	)
 
-	// Read the JSON file
-	jsonData, err := os.ReadFile(jsonFilePath)
+	jsonData, err := a.readTerraformJSON(ctx, jsonFilePath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, &core.FileAccessError{
-				Path:      jsonFilePath,
-				Operation: "read",
-				Err:       err,
-			}
-		}
-		if os.IsPermission(err) {
-			return nil, &core.FileAccessError{
-				Path:      jsonFilePath,
-				Operation: "access",
-				Err:       err,
-			}
-		}
-		return nil, &core.FileAccessError{
-			Path:      jsonFilePath,
-			Operation: "read",
-			Err:       err,
-		}
+		return nil, err
 	}
 
 	// Parse the JSON
@@ -342,17 +388,37 @@ func (a *Analyzer) ParseTerraformPlan(ctx context.Context, jsonFilePath string)
 		"terraform_version", plan.TerraformVersion,
 	)
 
+	if err := checkFormatVersion(plan.FormatVersion); err != nil {
+		return nil, &core.IaCParsingError{
+			File:    jsonFilePath,
+			Err:     err,
+			Context: "unsupported Terraform JSON format",
+		}
+	}
+
+	// Sensitivity markers may only be present on resource_changes
+	applyAfterSensitiveMarkers(&plan)
+
+	// A plan holds resources under planned_values, a state under values
+	jsonKind := "plan"
+	rootModule := plan.PlannedValues.RootModule
+	if rootModule == nil && plan.Values.RootModule != nil {
+		jsonKind = "state"
+		rootModule = plan.Values.RootModule
+	}
+
 	// Extract resources from the plan
 	resources := []core.Resource{}
-	
+
 	// Extract resources from root module
-	if plan.PlannedValues.RootModule != nil {
-		rootResources := a.extractResourcesFromModuleWithRedaction(ctx, plan.PlannedValues.RootModule, "")
+	if rootModule != nil {
+		rootResources := a.extractResourcesFromModuleWithRedaction(ctx, rootModule, "")
 		resources = append(resources, rootResources...)
 	}
 
 	slog.InfoContext(ctx, "terraform JSON parsing complete",
 		"total_resources", len(resources),
+		"json_kind", jsonKind,
 	)
 
 	// Build workload model
@@ -364,12 +430,55 @@ func (a *Analyzer) ParseTerraformPlan(ctx context.Context, jsonFilePath string)
 			"format_version":    plan.FormatVersion,
 			"terraform_version": plan.TerraformVersion,
 			"json_file":         jsonFilePath,
+			"json_kind":         jsonKind,
 		},
+		DestructiveChanges: findDestructiveChanges(ctx, plan.ResourceChanges),
 	}
 
 	return model, nil
 }
 
+// readTerraformJSON reads a Terraform JSON file, or fetches the state of a
+// Terraform Cloud workspace for a tfc:// reference
+func (a *Analyzer) readTerraformJSON(ctx context.Context, jsonFilePath string) ([]byte, error) {
+	ref, isRef, err := ParseStateReference(jsonFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if isRef {
+		if a.stateFetcher == nil {
+			return nil, fmt.Errorf("%s: %w", ref, ErrStateFetcherNotConfigured)
+		}
+		return a.stateFetcher.FetchState(ctx, ref)
+	}
+
+	// Read the JSON file
+	jsonData, err := os.ReadFile(jsonFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &core.FileAccessError{
+				Path:      jsonFilePath,
+				Operation: "read",
+				Err:       err,
+			}
+		}
+		if os.IsPermission(err) {
+			return nil, &core.FileAccessError{
+				Path:      jsonFilePath,
+				Operation: "access",
+				Err:       err,
+			}
+		}
+		return nil, &core.FileAccessError{
+			Path:      jsonFilePath,
+			Operation: "read",
+			Err:       err,
+		}
+	}
+
+	return jsonData, nil
+}
+
 // ParseTerraform parses Terraform HCL files
 func (a *Analyzer) ParseTerraform(ctx context.Context, files []core.IaCFile) (*core.WorkloadModel, error) {
 	slog.InfoContext(ctx, "parsing terraform HCL files",
@@ -383,6 +492,9 @@ func (a *Analyzer) ParseTerraform(ctx context.Context, files []core.IaCFile) (*c
 	parser := hclparse.NewParser()
 	var resources []core.Resource
 	var allDiags hcl.Diagnostics
+	var order []string
+	hclFiles := make(map[string]*hcl.File)
+	parsed := make(map[string]*hcl.File)
 
 	// Parse each file
 	for _, file := range files {
@@ -398,11 +510,12 @@ func (a *Analyzer) ParseTerraform(ctx context.Context, files []core.IaCFile) (*c
 			slog.WarnContext(ctx, "skipping non-terraform file",
 				"path", file.Path,
 			)
+			a.warn(core.AnalysisWarningParse, file.Path, "skipped, not a Terraform file")
 			continue
 		}
 
 		// Parse the HCL file
-		hclFile, diags := parser.ParseHCL([]byte(file.Content), file.Path)
+		hclFile, diags := parseTerraformFile(parser, file)
 		allDiags = append(allDiags, diags...)
 
 		if diags.HasErrors() {
@@ -413,17 +526,27 @@ func (a *Analyzer) ParseTerraform(ctx context.Context, files []core.IaCFile) (*c
 			continue
 		}
 
+		order = append(order, file.Path)
+		hclFiles[file.Path] = hclFile
+	}
+
+	// Variable values are known once every file is parsed
+	vars := variableContexts(ctx, hclFiles)
+
+	for _, path := range order {
 		// Extract resources from the parsed file
-		fileResources, err := a.extractResourcesFromHCLWithRedaction(ctx, hclFile, file.Path)
+		fileResources, err := a.extractResourcesFromHCLWithRedaction(ctx, hclFiles[path], path, vars[filepath.Dir(path)])
 		if err != nil {
 			slog.WarnContext(ctx, "failed to extract resources from HCL",
-				"file", file.Path,
+				"file", path,
 				"error", err,
 			)
+			a.warn(core.AnalysisWarningParse, path, "resources not extracted: %v", err)
 			continue
 		}
 
 		resources = append(resources, fileResources...)
+		parsed[path] = hclFiles[path]
 	}
 
 	// If we have critical parsing errors, return them
@@ -447,8 +570,24 @@ func (a *Analyzer) ParseTerraform(ctx context.Context, files []core.IaCFile) (*c
 		}
 	}
 
+	// Warning diagnostics do not stop parsing but may mean content was missed
+	for _, diag := range allDiags {
+		if diag.Severity != hcl.DiagWarning {
+			continue
+		}
+		source := "unknown"
+		if diag.Subject != nil {
+			source = diag.Subject.Filename
+		}
+		a.warn(core.AnalysisWarningParse, source, "%s: %s", diag.Summary, diag.Detail)
+	}
+
+	resources, conditionalModules := a.resolveLocalModules(ctx, parser, parsed, resources, vars)
+	settings := readTerraformSettings(order, hclFiles)
+
 	slog.InfoContext(ctx, "terraform HCL parsing complete",
 		"total_resources", len(resources),
+		"required_providers", len(settings.providers),
 	)
 
 	// Build workload model
@@ -459,17 +598,36 @@ func (a *Analyzer) ParseTerraform(ctx context.Context, files []core.IaCFile) (*c
 		Metadata: map[string]interface{}{
 			"file_count": len(files),
 		},
+		Advisories: settings.advisories,
 	}
+	if settings.requiredVersion != "" {
+		model.Metadata["required_version"] = settings.requiredVersion
+	}
+	if len(settings.providers) > 0 {
+		model.Metadata["required_providers"] = settings.providers
+	}
+	if regions := readProviderRegions(parsed); len(regions) > 0 {
+		model.Metadata["provider_regions"] = regions
+	}
+	if len(conditionalModules) > 0 {
+		model.Metadata["conditional_modules"] = conditionalModules
+	}
+	if conditional := conditionalResources(parsed, resources); len(conditional) > 0 {
+		model.Metadata["conditional_resources"] = conditional
+	}
+	model.Metadata["documentation_coverage"] = a.documentationCoverage(order, hclFiles)
 
 	return model, nil
 }
 
-// extractResourcesFromHCLWithRedaction extracts resources from a parsed HCL file with redaction
-func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, file *hcl.File, filePath string) ([]core.Resource, error) {
+// extractResourcesFromHCLWithRedaction extracts resources from a parsed HCL file with redaction.
+// vars is the variable context of the file's directory and may be nil.
+func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, file *hcl.File, filePath string, vars *hcl.EvalContext) ([]core.Resource, error) {
 	var resources []core.Resource
 
-	// Get the body content
-	content, diags := file.Body.Content(&hcl.BodySchema{
+	// Get the body content. Other top-level blocks (provider, terraform,
+	// variable, ...) are ignored rather than failing the whole file.
+	content, _, diags := file.Body.PartialContent(&hcl.BodySchema{
 		Blocks: []hcl.BlockHeaderSchema{
 			{Type: "resource", LabelNames: []string{"type", "name"}},
 			{Type: "data", LabelNames: []string{"type", "name"}},
@@ -481,6 +639,8 @@ func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, fil
 		return nil, fmt.Errorf("failed to get body content: %s", diags.Error())
 	}
 
+	annotations := lexIgnoreAnnotations(file.Bytes, filePath)
+
 	// Extract resource blocks
 	for _, block := range content.Blocks {
 		switch block.Type {
@@ -491,12 +651,13 @@ func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, fil
 				address := fmt.Sprintf("%s.%s", resourceType, resourceName)
 
 				// Extract properties from the block
-				properties, err := extractPropertiesFromBlock(block.Body)
+				properties, err := extractPropertiesFromBlock(block.Body, file.Bytes, vars)
 				if err != nil {
 					slog.WarnContext(ctx, "failed to extract properties",
 						"resource", address,
 						"error", err,
 					)
+					a.warn(core.AnalysisWarningParse, address, "properties not extracted: %v", err)
 					properties = make(map[string]interface{})
 				}
 
@@ -521,6 +682,8 @@ func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, fil
 					SourceLine:   block.DefRange.Start.Line,
 					IsFromPlan:   false,
 					ModulePath:   "",
+					Kind:         core.ResourceKindManaged,
+					Ignores:      annotations.forBlock(block),
 				}
 
 				resources = append(resources, resource)
@@ -540,12 +703,13 @@ func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, fil
 				dataName := block.Labels[1]
 				address := fmt.Sprintf("data.%s.%s", dataType, dataName)
 
-				properties, err := extractPropertiesFromBlock(block.Body)
+				properties, err := extractPropertiesFromBlock(block.Body, file.Bytes, vars)
 				if err != nil {
 					slog.WarnContext(ctx, "failed to extract properties",
 						"data", address,
 						"error", err,
 					)
+					a.warn(core.AnalysisWarningParse, address, "properties not extracted: %v", err)
 					properties = make(map[string]interface{})
 				}
 
@@ -570,6 +734,7 @@ func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, fil
 					SourceLine:   block.DefRange.Start.Line,
 					IsFromPlan:   false,
 					ModulePath:   "",
+					Kind:         core.ResourceKindData,
 				}
 
 				resources = append(resources, resource)
@@ -588,12 +753,13 @@ func (a *Analyzer) extractResourcesFromHCLWithRedaction(ctx context.Context, fil
 				moduleName := block.Labels[0]
 				address := fmt.Sprintf("module.%s", moduleName)
 
-				_, err := extractPropertiesFromBlock(block.Body)
+				_, err := extractPropertiesFromBlock(block.Body, file.Bytes, vars)
 				if err != nil {
 					slog.WarnContext(ctx, "failed to extract properties",
 						"module", address,
 						"error", err,
 					)
+					a.warn(core.AnalysisWarningParse, address, "properties not extracted: %v", err)
 				}
 
 				// Module calls are tracked but not treated as resources
@@ -637,7 +803,7 @@ func extractResourcesFromHCL(ctx context.Context, file *hcl.File, filePath strin
 				address := fmt.Sprintf("%s.%s", resourceType, resourceName)
 
 				// Extract properties from the block
-				properties, err := extractPropertiesFromBlock(block.Body)
+				properties, err := extractPropertiesFromBlock(block.Body, file.Bytes, nil)
 				if err != nil {
 					slog.WarnContext(ctx, "failed to extract properties",
 						"resource", address,
@@ -656,6 +822,7 @@ func extractResourcesFromHCL(ctx context.Context, file *hcl.File, filePath strin
 					SourceLine:   block.DefRange.Start.Line,
 					IsFromPlan:   false,
 					ModulePath:   "",
+					Kind:         core.ResourceKindManaged,
 				}
 
 				resources = append(resources, resource)
@@ -674,7 +841,7 @@ func extractResourcesFromHCL(ctx context.Context, file *hcl.File, filePath strin
 				dataName := block.Labels[1]
 				address := fmt.Sprintf("data.%s.%s", dataType, dataName)
 
-				properties, err := extractPropertiesFromBlock(block.Body)
+				properties, err := extractPropertiesFromBlock(block.Body, file.Bytes, nil)
 				if err != nil {
 					slog.WarnContext(ctx, "failed to extract properties",
 						"data", address,
@@ -693,6 +860,7 @@ func extractResourcesFromHCL(ctx context.Context, file *hcl.File, filePath strin
 					SourceLine:   block.DefRange.Start.Line,
 					IsFromPlan:   false,
 					ModulePath:   "",
+					Kind:         core.ResourceKindData,
 				}
 
 				resources = append(resources, resource)
@@ -710,7 +878,7 @@ func extractResourcesFromHCL(ctx context.Context, file *hcl.File, filePath strin
 				moduleName := block.Labels[0]
 				address := fmt.Sprintf("module.%s", moduleName)
 
-				_, err := extractPropertiesFromBlock(block.Body)
+				_, err := extractPropertiesFromBlock(block.Body, file.Bytes, nil)
 				if err != nil {
 					slog.WarnContext(ctx, "failed to extract properties",
 						"module", address,
@@ -732,8 +900,10 @@ func extractResourcesFromHCL(ctx context.Context, file *hcl.File, filePath strin
 	return resources, nil
 }
 
-// extractPropertiesFromBlock extracts properties from an HCL block body
-func extractPropertiesFromBlock(body hcl.Body) (map[string]interface{}, error) {
+// extractPropertiesFromBlock extracts properties from an HCL block body.
+// src is the content of the file the block was parsed from. vars resolves
+// var.* references and may be nil, leaving them as expressions.
+func extractPropertiesFromBlock(body hcl.Body, src []byte, vars *hcl.EvalContext) (map[string]interface{}, error) {
 	properties := make(map[string]interface{})
 
 	// Get all attributes
@@ -748,10 +918,10 @@ func extractPropertiesFromBlock(body hcl.Body) (map[string]interface{}, error) {
 	// Extract attribute values
 	for name, attr := range attrs {
 		// Try to evaluate the attribute
-		val, diags := attr.Expr.Value(nil)
+		val, diags := attr.Expr.Value(vars)
 		if diags.HasErrors() {
-			// If we can't evaluate, store the expression as a string
-			properties[name] = fmt.Sprintf("${%s}", string(attr.Expr.Range().SliceBytes(attr.Expr.Range().SliceBytes([]byte{}))))
+			// If we can't evaluate, store the expression source as a string
+			properties[name] = expressionString(attr.Expr, src)
 			continue
 		}
 
@@ -776,9 +946,16 @@ func extractPropertiesFromBlock(body hcl.Body) (map[string]interface{}, error) {
 		"ebs_block_device", "root_block_device", "lifecycle", "timeouts",
 		"versioning_configuration", "logging", "cors_rule", "website",
 		"filter", "tags", "ingress", "egress", "rule",
+		"endpoint_configuration", "default_action", "action",
 	}
 
 	for _, blockType := range commonBlockTypes {
+		// JSON syntax has no separate block form, so nested objects were
+		// already read as attributes
+		if _, isAttr := attrs[blockType]; isAttr {
+			continue
+		}
+
 		content, _, diags := body.PartialContent(&hcl.BodySchema{
 			Blocks: []hcl.BlockHeaderSchema{
 				{Type: blockType},
@@ -788,7 +965,7 @@ func extractPropertiesFromBlock(body hcl.Body) (map[string]interface{}, error) {
 		if !diags.HasErrors() && content != nil && len(content.Blocks) > 0 {
 			for _, block := range content.Blocks {
 				// Recursively extract nested block properties
-				nestedProps, err := extractPropertiesFromBlock(block.Body)
+				nestedProps, err := extractPropertiesFromBlock(block.Body, src, vars)
 				if err != nil {
 					continue
 				}
@@ -811,6 +988,62 @@ func extractPropertiesFromBlock(body hcl.Body) (map[string]interface{}, error) {
 	return properties, nil
 }
 
+// expressionString returns the source of an expression that cannot be
+// evaluated statically. Quoted templates keep their interpolations, e.g.
+// "${var.env}-logs"; other expressions are wrapped as "${aws_vpc.main.id}".
+func expressionString(expr hcl.Expression, src []byte) string {
+	text := string(expr.Range().SliceBytes(src))
+	if _, ok := expr.(*hclsyntax.TemplateExpr); ok && len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		return text[1 : len(text)-1]
+	}
+	return fmt.Sprintf("${%s}", text)
+}
+
+// readProviderRegions returns the literal region of each provider
+// configuration in files, keyed by the provider name or, for an alias, by
+// name.alias as resources refer to it
+func readProviderRegions(files map[string]*hcl.File) map[string]string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	regions := make(map[string]string)
+	for _, path := range paths {
+		content, _, _ := files[path].Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "provider", LabelNames: []string{"name"}}},
+		})
+		for _, block := range content.Blocks {
+			attrs, _ := block.Body.JustAttributes()
+			key := block.Labels[0]
+			if alias := stringExpression(attrs["alias"]); alias != "" {
+				key += "." + alias
+			}
+			if _, seen := regions[key]; seen {
+				continue
+			}
+			if region := stringExpression(attrs["region"]); region != "" {
+				regions[key] = region
+			}
+		}
+	}
+	return regions
+}
+
+// stringExpression returns the value of a literal string attribute, or an
+// empty string when it is missing or not a known string
+func stringExpression(attr *hcl.Attribute) string {
+	if attr == nil {
+		return ""
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || !value.IsKnown() || value.Type() != cty.String {
+		return ""
+	}
+	return value.AsString()
+}
+
 // ctyToGo converts a cty.Value to a Go value
 func ctyToGo(val cty.Value) (interface{}, error) {
 	if val.IsNull() {
@@ -893,6 +1126,7 @@ func (a *Analyzer) MergeWorkloadModels(ctx context.Context, planModel, configMod
 
 	// Start with configuration resources (they provide the foundation)
 	mergedResources := make([]core.Resource, 0, len(configModel.Resources))
+	var drift []core.PropertyDrift
 
 	for _, configRes := range configModel.Resources {
 		// Check context cancellation
@@ -906,6 +1140,9 @@ func (a *Analyzer) MergeWorkloadModels(ctx context.Context, planModel, configMod
 
 		// If we have a corresponding plan resource, enhance with plan data
 		if planRes, exists := planResourceMap[configRes.Address]; exists {
+			// Record declared values that the plan disagrees with
+			drift = append(drift, detectPropertyDrift(configRes.Address, configRes.Properties, planRes.Properties)...)
+
 			// Enhance with computed values from plan
 			if len(planRes.Properties) > len(configRes.Properties) {
 				// Merge properties, keeping configuration properties and adding plan-computed ones
@@ -948,6 +1185,17 @@ func (a *Analyzer) MergeWorkloadModels(ctx context.Context, planModel, configMod
 		mergedResources = append(mergedResources, mergedRes)
 	}
 
+	// Managed resources declared in the configuration but absent from the
+	// plan were added after the plan was generated
+	conditionalModules, _ := configModel.Metadata["conditional_modules"].([]string)
+	conditionalResources, _ := configModel.Metadata["conditional_resources"].([]string)
+	missing := missingFromPlan(configModel.Resources, planModel.Resources, conditionalModules, conditionalResources)
+	if len(missing) > 0 {
+		slog.WarnContext(ctx, "configuration resources missing from plan, the plan may be stale",
+			"missing", len(missing),
+		)
+	}
+
 	// Add any plan resources that weren't in the configuration
 	// This can happen with computed resources or modules that expand at plan time
 	for _, planRes := range planResourceMap {
@@ -973,12 +1221,15 @@ func (a *Analyzer) MergeWorkloadModels(ctx context.Context, planModel, configMod
 	mergedMetadata["config_resource_count"] = len(configModel.Resources)
 	mergedMetadata["plan_resource_count"] = len(planModel.Resources)
 	mergedMetadata["plan_only_resources"] = len(planResourceMap)
+	mergedMetadata["property_drift_count"] = len(drift)
+	mergedMetadata["missing_from_plan"] = len(missing)
 
 	slog.InfoContext(ctx, "workload model merge complete (configuration-first)",
 		"total_resources", len(mergedResources),
 		"config_base", len(configModel.Resources),
 		"plan_enhanced", len(configModel.Resources)-len(planResourceMap),
 		"plan_only", len(planResourceMap),
+		"property_drift", len(drift),
 	)
 
 	// Build merged model with configuration as the foundation
@@ -987,11 +1238,140 @@ func (a *Analyzer) MergeWorkloadModels(ctx context.Context, planModel, configMod
 		Framework:  configModel.Framework,
 		SourceType: "hcl_enhanced", // Indicates HCL with plan enhancement
 		Metadata:   mergedMetadata,
+		Drift:      drift,
+		// Only the plan knows which resources are deleted
+		DestructiveChanges: planModel.DestructiveChanges,
+		Advisories:         configModel.Advisories,
+		MissingFromPlan:    missing,
 	}
 
 	return mergedModel, nil
 }
 
+// missingFromPlan returns the sorted addresses of the managed configuration
+// resources with no instance in the plan. A resource with count or for_each
+// is in the plan when any of its indexed instances is, also when the index
+// belongs to an enclosing module call. Resources that may legitimately have
+// no instances are never reported, nor are conditionalResources, whose count
+// or for_each depends on values the plan may set differently, or the
+// resources of conditional modules, whose call's count or for_each may be
+// zero.
+func missingFromPlan(configResources, planResources []core.Resource, conditionalModules, conditionalResources []string) []string {
+	planned := make(map[string]bool, len(planResources))
+	for _, resource := range planResources {
+		planned[instanceIndexPattern.ReplaceAllString(resource.Address, "")] = true
+	}
+
+	var missing []string
+	for _, resource := range configResources {
+		if resource.IsDataSource() || planned[resource.Address] || mayHaveNoInstances(resource) ||
+			slices.Contains(conditionalResources, resource.Address) || slices.Contains(conditionalModules, resource.ModulePath) {
+			continue
+		}
+		missing = append(missing, resource.Address)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// instanceIndexPattern matches the [0] and ["key"] instance keys of an
+// address, including quoted keys containing a closing bracket
+var instanceIndexPattern = regexp.MustCompile(`\[(?:"(?:[^"\\]|\\.)*"|[^"\]]*)\]`)
+
+// mayHaveNoInstances reports whether a configuration resource declares a
+// count or for_each that is zero, empty, or could not be evaluated
+// statically. Such a resource is absent from a plan that is up to date.
+func mayHaveNoInstances(resource core.Resource) bool {
+	for _, key := range []string{"count", "for_each"} {
+		if value, ok := resource.Properties[key]; ok && noInstances(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// noInstances reports whether a count or for_each value is zero, empty or
+// an unresolved expression
+func noInstances(value interface{}) bool {
+	switch v := value.(type) {
+	case int64:
+		return v <= 0
+	case float64:
+		return v <= 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case string:
+		return strings.Contains(v, "${")
+	case nil:
+		return true
+	}
+	return false
+}
+
+// detectPropertyDrift compares declared configuration values with the plan.
+// Only scalar values the HCL parser could evaluate are compared; unresolved
+// expressions and redacted values are skipped.
+func detectPropertyDrift(address string, configProps, planProps map[string]interface{}) []core.PropertyDrift {
+	var drift []core.PropertyDrift
+
+	for name, configVal := range configProps {
+		planVal, exists := planProps[name]
+		if !exists {
+			continue
+		}
+
+		configScalar, ok := comparableScalar(configVal)
+		if !ok {
+			continue
+		}
+		planScalar, ok := comparableScalar(planVal)
+		if !ok {
+			continue
+		}
+
+		if configScalar != planScalar {
+			drift = append(drift, core.PropertyDrift{
+				Address:     address,
+				Property:    name,
+				ConfigValue: configVal,
+				PlanValue:   planVal,
+			})
+		}
+	}
+
+	// Map iteration order is random, keep the output stable
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Property < drift[j].Property
+	})
+
+	return drift
+}
+
+// comparableScalar normalizes a property value for drift comparison.
+// Numbers are converted to float64 because HCL yields integers while plan
+// JSON yields floats.
+func comparableScalar(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case string:
+		if strings.Contains(val, "${") || strings.Contains(val, "[REDACTED") {
+			return nil, false
+		}
+		return val, true
+	case bool:
+		return val, true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	default:
+		return nil, false
+	}
+}
+
 // ExtractResources extracts resources from a workload model
 func (a *Analyzer) ExtractResources(ctx context.Context, model *core.WorkloadModel) ([]core.Resource, error) {
 	if model == nil {
@@ -1004,10 +1384,105 @@ func (a *Analyzer) ExtractResources(ctx context.Context, model *core.WorkloadMod
 		"source_type", model.SourceType,
 	)
 
-	// Simply return the resources from the model
-	// This method exists to provide a consistent interface
-	// and could be extended in the future to filter or transform resources
-	return model.Resources, nil
+	// Normalize properties so HCL and plan sources present the same values
+	resources := make([]core.Resource, len(model.Resources))
+	for i, resource := range model.Resources {
+		resource.Properties = normalizeProperties(resource.Properties)
+		resource.Hints = InspectResource(resource)
+		resource.Fingerprint = fingerprintResource(resource)
+		resources[i] = resource
+	}
+
+	return resources, nil
+}
+
+// userKeyedAttributes hold maps whose keys are user data rather than
+// attribute names, so their key casing is preserved
+var userKeyedAttributes = map[string]bool{
+	"tags":      true,
+	"tags_all":  true,
+	"variables": true,
+}
+
+// normalizeProperties canonicalizes resource properties.
+// Attribute names are lowercased, integral numbers become int64 and other
+// numbers float64. HCL yields int64 where plan JSON yields float64, so both
+// sources normalize to the same map. The strings "true" and "false" become
+// bools, as HCL often quotes booleans that plan JSON reports as bools. Other
+// strings are kept as written, since values such as account IDs or ports are
+// quoted on purpose.
+func normalizeProperties(properties map[string]interface{}) map[string]interface{} {
+	if properties == nil {
+		return nil
+	}
+	return normalizeMap(properties, true)
+}
+
+// normalizeMap normalizes a map, lowercasing its keys when lowerKeys is set
+func normalizeMap(m map[string]interface{}, lowerKeys bool) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		key := k
+		if lowerKeys {
+			key = strings.ToLower(k)
+			// An already lowercase key wins over a differently cased duplicate
+			if _, exists := result[key]; exists && key != k {
+				continue
+			}
+		}
+		result[key] = normalizeValue(v, !userKeyedAttributes[key])
+	}
+	return result
+}
+
+// normalizeValue normalizes a single property value
+func normalizeValue(v interface{}, lowerKeys bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return normalizeMap(val, lowerKeys)
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, elem := range val {
+			result[i] = normalizeValue(elem, true)
+		}
+		return result
+	case int:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case int64:
+		return val
+	case float32:
+		return normalizeFloat(float64(val))
+	case float64:
+		return normalizeFloat(val)
+	case string:
+		switch val {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+		return val
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return normalizeFloat(f)
+		}
+		return val.String()
+	default:
+		return v
+	}
+}
+
+// normalizeFloat returns integral values that are exactly representable as int64
+func normalizeFloat(f float64) interface{} {
+	if f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+		return int64(f)
+	}
+	return f
 }
 
 // IdentifyRelationships identifies relationships between resources
@@ -1201,10 +1676,26 @@ func contains(slice []string, item string) bool {
 
 // TerraformPlan represents the structure of a Terraform plan JSON
 type TerraformPlan struct {
-	FormatVersion    string        `json:"format_version"`
-	TerraformVersion string        `json:"terraform_version"`
-	PlannedValues    PlannedValues `json:"planned_values"`
-	Configuration    Configuration `json:"configuration"`
+	FormatVersion    string           `json:"format_version"`
+	TerraformVersion string           `json:"terraform_version"`
+	PlannedValues    PlannedValues    `json:"planned_values"`
+	// Values holds the resources of a state file, which has no planned values
+	Values           PlannedValues    `json:"values"`
+	ResourceChanges  []ResourceChange `json:"resource_changes"`
+	Configuration    Configuration    `json:"configuration"`
+}
+
+// ResourceChange represents a planned change to a resource
+type ResourceChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Change  struct {
+		// Actions is the planned action, e.g. ["update"] or ["delete", "create"]
+		Actions []string `json:"actions"`
+		// AfterSensitive mirrors the planned values with true at sensitive leaves
+		AfterSensitive interface{} `json:"after_sensitive"`
+	} `json:"change"`
 }
 
 // PlannedValues contains the planned state
@@ -1233,6 +1724,8 @@ type PlanResource struct {
 	Name         string                 `json:"name"`
 	ProviderName string                 `json:"provider_name"`
 	Values       map[string]interface{} `json:"values"`
+	// SensitiveValues mirrors Values with true where Terraform marks a value sensitive
+	SensitiveValues map[string]interface{} `json:"sensitive_values"`
 }
 
 // Configuration represents the Terraform configuration
@@ -1250,6 +1743,15 @@ type ModuleCall struct {
 	Source string `json:"source"`
 }
 
+// planResourceKind returns the kind of a plan or state resource from its
+// mode
+func planResourceKind(mode string) core.ResourceKind {
+	if mode == "data" {
+		return core.ResourceKindData
+	}
+	return core.ResourceKindManaged
+}
+
 // extractResourcesFromModuleWithRedaction recursively extracts resources from a module with redaction
 func (a *Analyzer) extractResourcesFromModuleWithRedaction(ctx context.Context, module *Module, parentPath string) []core.Resource {
 	var resources []core.Resource
@@ -1277,8 +1779,13 @@ func (a *Analyzer) extractResourcesFromModuleWithRedaction(ctx context.Context,
 			}
 		}
 
-		// Redact sensitive data from properties
-		redactedProperties, findings := a.redactor.RedactProperties(planRes.Values)
+		// Redact values Terraform marked sensitive, then apply pattern-based redaction
+		markedValues, sensitiveCount := redactTerraformSensitiveValues(planRes)
+		redactedProperties, findings := a.redactor.RedactProperties(markedValues)
+		a.warnRedacted(address, findings)
+		if sensitiveCount > 0 {
+			findings = append(findings, redaction.TerraformSensitiveFinding)
+		}
 		
 		if len(findings) > 0 {
 			slog.WarnContext(ctx, "sensitive data redacted from resource properties",
@@ -1295,6 +1802,7 @@ func (a *Analyzer) extractResourcesFromModuleWithRedaction(ctx context.Context,
 			Dependencies: []string{}, // Will be populated in relationship identification
 			IsFromPlan:   true,
 			ModulePath:   modulePath,
+			Kind:         planResourceKind(planRes.Mode),
 		}
 
 		resources = append(resources, resource)
@@ -1324,8 +1832,13 @@ func (a *Analyzer) extractResourcesFromChildModuleWithRedaction(ctx context.Cont
 	for _, planRes := range childModule.Resources {
 		address := planRes.Address
 
-		// Redact sensitive data from properties
-		redactedProperties, findings := a.redactor.RedactProperties(planRes.Values)
+		// Redact values Terraform marked sensitive, then apply pattern-based redaction
+		markedValues, sensitiveCount := redactTerraformSensitiveValues(planRes)
+		redactedProperties, findings := a.redactor.RedactProperties(markedValues)
+		a.warnRedacted(address, findings)
+		if sensitiveCount > 0 {
+			findings = append(findings, redaction.TerraformSensitiveFinding)
+		}
 		
 		if len(findings) > 0 {
 			slog.WarnContext(ctx, "sensitive data redacted from resource properties",
@@ -1342,6 +1855,7 @@ func (a *Analyzer) extractResourcesFromChildModuleWithRedaction(ctx context.Cont
 			Dependencies: []string{},
 			IsFromPlan:   true,
 			ModulePath:   modulePath,
+			Kind:         planResourceKind(planRes.Mode),
 		}
 
 		resources = append(resources, resource)
@@ -1363,6 +1877,55 @@ func (a *Analyzer) extractResourcesFromChildModuleWithRedaction(ctx context.Cont
 	return resources
 }
 
+// redactTerraformSensitiveValues redacts the values Terraform marked as sensitive.
+// It returns the resulting values and how many were replaced.
+func redactTerraformSensitiveValues(planRes PlanResource) (map[string]interface{}, int) {
+	if len(planRes.SensitiveValues) == 0 || planRes.Values == nil {
+		return planRes.Values, 0
+	}
+
+	redacted, count := redaction.RedactMarkedValues(planRes.Values, planRes.SensitiveValues)
+	values, _ := redacted.(map[string]interface{})
+	return values, count
+}
+
+// applyAfterSensitiveMarkers copies after_sensitive markers from
+// resource_changes onto planned resources that carry no sensitive_values
+func applyAfterSensitiveMarkers(plan *TerraformPlan) {
+	if plan.PlannedValues.RootModule == nil || len(plan.ResourceChanges) == 0 {
+		return
+	}
+
+	markers := make(map[string]map[string]interface{})
+	for _, change := range plan.ResourceChanges {
+		if m, ok := change.Change.AfterSensitive.(map[string]interface{}); ok && len(m) > 0 {
+			markers[change.Address] = m
+		}
+	}
+	if len(markers) == 0 {
+		return
+	}
+
+	applyMarkers := func(resources []PlanResource) {
+		for i := range resources {
+			if len(resources[i].SensitiveValues) == 0 {
+				resources[i].SensitiveValues = markers[resources[i].Address]
+			}
+		}
+	}
+
+	var walkChildren func(children []ChildModule)
+	walkChildren = func(children []ChildModule) {
+		for i := range children {
+			applyMarkers(children[i].Resources)
+			walkChildren(children[i].ChildModules)
+		}
+	}
+
+	applyMarkers(plan.PlannedValues.RootModule.Resources)
+	walkChildren(plan.PlannedValues.RootModule.ChildModules)
+}
+
 // extractResourcesFromModule recursively extracts resources from a module (kept for backward compatibility)
 func extractResourcesFromModule(ctx context.Context, module *Module, parentPath string) []core.Resource {
 	var resources []core.Resource
@@ -1398,6 +1961,7 @@ func extractResourcesFromModule(ctx context.Context, module *Module, parentPath
 			Dependencies: []string{}, // Will be populated in relationship identification
 			IsFromPlan:   true,
 			ModulePath:   modulePath,
+			Kind:         planResourceKind(planRes.Mode),
 		}
 
 		resources = append(resources, resource)
@@ -1434,6 +1998,7 @@ func extractResourcesFromChildModule(ctx context.Context, childModule *ChildModu
 			Dependencies: []string{},
 			IsFromPlan:   true,
 			ModulePath:   modulePath,
+			Kind:         planResourceKind(planRes.Mode),
 		}
 
 		resources = append(resources, resource)
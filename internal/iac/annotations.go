@@ -0,0 +1,103 @@
+package iac
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// ignorePattern matches an inline suppression such as
+// waffle:ignore sec_data_1 reason="compensating control X"
+var ignorePattern = regexp.MustCompile(`^waffle:ignore\s+(\S+)(?:\s+reason="([^"]*)")?\s*$`)
+
+// ignoreAnnotations holds the waffle:ignore comments of one file, keyed by
+// line. Lines holding only a comment are recorded so annotations above a
+// block can be told apart from ones trailing code.
+type ignoreAnnotations struct {
+	byLine       map[int][]core.ResourceIgnore
+	commentLines map[int]bool
+}
+
+// lexIgnoreAnnotations collects the waffle:ignore comments of an HCL source
+func lexIgnoreAnnotations(src []byte, filename string) *ignoreAnnotations {
+	annotations := &ignoreAnnotations{
+		byLine:       make(map[int][]core.ResourceIgnore),
+		commentLines: make(map[int]bool),
+	}
+
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	lineStart := true
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenComment {
+			lineStart = token.Type == hclsyntax.TokenNewline
+			continue
+		}
+
+		if lineStart {
+			for line := token.Range.Start.Line; line <= commentEndLine(token); line++ {
+				annotations.commentLines[line] = true
+			}
+		}
+		if ignore, ok := parseIgnoreComment(string(token.Bytes)); ok {
+			annotations.byLine[token.Range.Start.Line] = append(annotations.byLine[token.Range.Start.Line], ignore)
+		}
+		// Line comments consume their newline, so the next token starts a line
+		lineStart = lineStart || strings.HasSuffix(string(token.Bytes), "\n")
+	}
+	return annotations
+}
+
+// commentEndLine returns the last line a comment token covers. Line comments
+// end with their newline, which the token range counts as the next line.
+func commentEndLine(token hclsyntax.Token) int {
+	if strings.HasSuffix(string(token.Bytes), "\n") {
+		return token.Range.Start.Line
+	}
+	return token.Range.End.Line
+}
+
+// parseIgnoreComment parses the text of a comment token as a waffle:ignore
+// annotation
+func parseIgnoreComment(comment string) (core.ResourceIgnore, bool) {
+	text := strings.TrimSpace(comment)
+	switch {
+	case strings.HasPrefix(text, "#"):
+		text = text[1:]
+	case strings.HasPrefix(text, "//"):
+		text = text[2:]
+	case strings.HasPrefix(text, "/*"):
+		text = strings.TrimSuffix(text[2:], "*/")
+	}
+
+	match := ignorePattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return core.ResourceIgnore{}, false
+	}
+	return core.ResourceIgnore{QuestionID: match[1], Reason: match[2]}, true
+}
+
+// forBlock returns the annotations that apply to a block: those in the run of
+// comment lines directly above its header and those within its body
+func (a *ignoreAnnotations) forBlock(block *hcl.Block) []core.ResourceIgnore {
+	if a == nil || len(a.byLine) == 0 {
+		return nil
+	}
+
+	start := block.DefRange.Start.Line
+	end := start
+	if body, ok := block.Body.(*hclsyntax.Body); ok {
+		end = body.SrcRange.End.Line
+	}
+	for a.commentLines[start-1] {
+		start--
+	}
+
+	var ignores []core.ResourceIgnore
+	for line := start; line <= end; line++ {
+		ignores = append(ignores, a.byLine[line]...)
+	}
+	return ignores
+}
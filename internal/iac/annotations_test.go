@@ -0,0 +1,75 @@
+package iac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+const annotatedConfig = `# Log bucket for the audit trail
+# waffle:ignore sec_data_1 reason="compensating control X"
+resource "aws_s3_bucket" "logs" {
+  bucket = "audit-logs"
+}
+
+// waffle:ignore sec_data_2
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets" # waffle:ignore sec_data_3 reason="public by design"
+
+  /* waffle:ignore rel_backup_1 reason="rebuilt from source" */
+}
+
+# waffle:ignore sec_data_1 reason="separated by a blank line"
+
+resource "aws_s3_bucket" "uploads" {
+  bucket = "uploads" # waffle:ignore
+}
+`
+
+func TestParseTerraform_IgnoreAnnotations(t *testing.T) {
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), []core.IaCFile{{Path: "main.tf", Content: annotatedConfig}})
+	require.NoError(t, err)
+
+	ignores := make(map[string][]core.ResourceIgnore)
+	for _, resource := range model.Resources {
+		ignores[resource.Address] = resource.Ignores
+	}
+
+	assert.Equal(t, []core.ResourceIgnore{
+		{QuestionID: "sec_data_1", Reason: "compensating control X"},
+	}, ignores["aws_s3_bucket.logs"])
+	assert.Equal(t, []core.ResourceIgnore{
+		{QuestionID: "sec_data_2"},
+		{QuestionID: "sec_data_3", Reason: "public by design"},
+		{QuestionID: "rel_backup_1", Reason: "rebuilt from source"},
+	}, ignores["aws_s3_bucket.assets"])
+	// Annotations apply only when directly above the block or inside it,
+	// and one without a question ID is not an annotation
+	assert.Empty(t, ignores["aws_s3_bucket.uploads"])
+}
+
+func TestParseIgnoreComment(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    core.ResourceIgnore
+		wantOK  bool
+	}{
+		{comment: "# waffle:ignore sec_data_1\n", want: core.ResourceIgnore{QuestionID: "sec_data_1"}, wantOK: true},
+		{comment: `// waffle:ignore sec_data_1 reason="compensating control X"`, want: core.ResourceIgnore{QuestionID: "sec_data_1", Reason: "compensating control X"}, wantOK: true},
+		{comment: "/* waffle:ignore sec_data_1 */", want: core.ResourceIgnore{QuestionID: "sec_data_1"}, wantOK: true},
+		{comment: "# waffle:ignore\n"},
+		{comment: "# TODO: waffle:ignore sec_data_1\n"},
+		{comment: "# plain comment\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			got, ok := parseIgnoreComment(tt.comment)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
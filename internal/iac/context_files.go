@@ -0,0 +1,54 @@
+package iac
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"unicode/utf8"
+
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/redaction"
+)
+
+// MaxContextBytes is the maximum combined size of context files (64KB). The
+// files are sent with every question, so the cap bounds the prompt cost.
+const MaxContextBytes = 64 * 1024
+
+// ReadContextFiles reads text files attached as supplementary review context,
+// such as runbooks or architecture notes. Contents are redacted like IaC files.
+func ReadContextFiles(ctx context.Context, paths []string) ([]core.ContextDocument, error) {
+	redactor := redaction.NewRedactor()
+	var documents []core.ContextDocument
+	total := 0
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &core.FileAccessError{Path: path, Operation: "read", Err: err}
+		}
+		if !utf8.Valid(content) {
+			return nil, fmt.Errorf("context file %s is not a text file", path)
+		}
+
+		total += len(content)
+		if total > MaxContextBytes {
+			return nil, fmt.Errorf("context files exceed %d bytes in total (at %s)", MaxContextBytes, path)
+		}
+
+		redactedContent, findings := redactor.Redact(string(content))
+		if len(findings) > 0 {
+			slog.WarnContext(ctx, "sensitive data redacted from context file",
+				"file", path,
+				"findings", findings,
+			)
+		}
+
+		documents = append(documents, core.ContextDocument{
+			Path:    path,
+			Content: redactedContent,
+		})
+	}
+
+	return documents, nil
+}
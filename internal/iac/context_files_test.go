@@ -0,0 +1,65 @@
+package iac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestReadContextFiles(t *testing.T) {
+	dir := t.TempDir()
+	runbook := filepath.Join(dir, "runbook.md")
+	require.NoError(t, os.WriteFile(runbook, []byte("# Failover\nPage oncall@example.com, then promote the replica.\npassword: hunter2\n"), 0o644))
+	notes := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(notes, []byte("Backups are restored quarterly."), 0o644))
+
+	documents, err := ReadContextFiles(context.Background(), []string{runbook, notes})
+	require.NoError(t, err)
+
+	require.Len(t, documents, 2)
+	assert.Equal(t, runbook, documents[0].Path)
+	assert.Contains(t, documents[0].Content, "promote the replica")
+	assert.NotContains(t, documents[0].Content, "oncall@example.com")
+	assert.NotContains(t, documents[0].Content, "hunter2")
+	assert.Equal(t, core.ContextDocument{Path: notes, Content: "Backups are restored quarterly."}, documents[1])
+}
+
+func TestReadContextFiles_NoFiles(t *testing.T) {
+	documents, err := ReadContextFiles(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, documents)
+}
+
+func TestReadContextFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	large := filepath.Join(dir, "large.md")
+	require.NoError(t, os.WriteFile(large, []byte(strings.Repeat("a\n", MaxContextBytes/2)), 0o644))
+	small := filepath.Join(dir, "small.md")
+	require.NoError(t, os.WriteFile(small, []byte("one more byte"), 0o644))
+	binary := filepath.Join(dir, "diagram.png")
+	require.NoError(t, os.WriteFile(binary, []byte{0x89, 'P', 'N', 'G', 0xff, 0xfe}, 0o644))
+
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr string
+	}{
+		{name: "missing file", paths: []string{filepath.Join(dir, "missing.md")}, wantErr: "failed to read file"},
+		{name: "binary file", paths: []string{binary}, wantErr: "is not a text file"},
+		{name: "total size over cap", paths: []string{large, small}, wantErr: "exceed 65536 bytes in total (at " + small},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadContextFiles(context.Background(), tt.paths)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
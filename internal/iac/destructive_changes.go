@@ -0,0 +1,59 @@
+package iac
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// statefulResourceTypes are resource types whose data is lost when Terraform
+// deletes or replaces them
+var statefulResourceTypes = map[string]bool{
+	"aws_db_instance":           true,
+	"aws_rds_cluster":           true,
+	"aws_s3_bucket":             true,
+	"aws_dynamodb_table":        true,
+	"aws_dynamodb_global_table": true,
+}
+
+// findDestructiveChanges returns the stateful resources whose planned actions
+// include a delete. Terraform plans a replacement as a delete paired with a
+// create, in either order.
+func findDestructiveChanges(ctx context.Context, changes []ResourceChange) []core.DestructiveChange {
+	var destructive []core.DestructiveChange
+	for _, change := range changes {
+		if change.Mode == "data" || !statefulResourceTypes[change.Type] {
+			continue
+		}
+
+		var deletes, creates bool
+		for _, action := range change.Change.Actions {
+			switch action {
+			case "delete":
+				deletes = true
+			case "create":
+				creates = true
+			}
+		}
+		if !deletes {
+			continue
+		}
+
+		action := "delete"
+		if creates {
+			action = "replace"
+		}
+		slog.WarnContext(ctx, "plan destroys stateful resource",
+			"address", change.Address,
+			"type", change.Type,
+			"action", action,
+		)
+		destructive = append(destructive, core.DestructiveChange{
+			Address: change.Address,
+			Type:    change.Type,
+			Action:  action,
+		})
+	}
+	return destructive
+}
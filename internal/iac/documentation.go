@@ -0,0 +1,102 @@
+package iac
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// documentationCoverage counts the variable and output blocks of files,
+// visited in order, that have a non-empty description, and the directories
+// of the files that hold a README. Relative paths are relative to the
+// working directory.
+func (a *Analyzer) documentationCoverage(order []string, files map[string]*hcl.File) core.DocumentationCoverage {
+	var coverage core.DocumentationCoverage
+	dirs := make(map[string]bool)
+
+	for _, path := range order {
+		dirs[filepath.Dir(path)] = true
+
+		content, _, _ := files[path].Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{
+				{Type: "variable", LabelNames: []string{"name"}},
+				{Type: "output", LabelNames: []string{"name"}},
+			},
+		})
+		for _, block := range content.Blocks {
+			described := hasDescription(block)
+			switch block.Type {
+			case "variable":
+				coverage.Variables++
+				if described {
+					coverage.DescribedVariables++
+				} else {
+					coverage.UndocumentedVariables = append(coverage.UndocumentedVariables, "var."+block.Labels[0])
+				}
+			case "output":
+				coverage.Outputs++
+				if described {
+					coverage.DescribedOutputs++
+				} else {
+					coverage.UndocumentedOutputs = append(coverage.UndocumentedOutputs, "output."+block.Labels[0])
+				}
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+	for _, dir := range sorted {
+		coverage.Modules++
+		if a.hasReadme(dir) {
+			coverage.ModulesWithReadme++
+		} else {
+			coverage.ModulesWithoutReadme = append(coverage.ModulesWithoutReadme, dir)
+		}
+	}
+
+	return coverage
+}
+
+// hasDescription reports whether a block sets description to a non-empty
+// string literal
+func hasDescription(block *hcl.Block) bool {
+	content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "description"}},
+	})
+	attr, ok := content.Attributes["description"]
+	if !ok {
+		return false
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || !value.IsKnown() || value.Type() != cty.String {
+		return false
+	}
+	return strings.TrimSpace(value.AsString()) != ""
+}
+
+// hasReadme reports whether dir holds a README file, such as README.md or
+// readme.txt. A relative dir is relative to the working directory.
+func (a *Analyzer) hasReadme(dir string) bool {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(a.workingDir, dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(strings.ToLower(entry.Name()), "readme") {
+			return true
+		}
+	}
+	return false
+}
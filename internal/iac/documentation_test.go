@@ -0,0 +1,110 @@
+package iac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestParseTerraform_DocumentationCoverage(t *testing.T) {
+	root := t.TempDir()
+	documented := filepath.Join(root, "network")
+	undocumented := filepath.Join(root, "compute")
+	require.NoError(t, os.Mkdir(documented, 0o755))
+	require.NoError(t, os.Mkdir(undocumented, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(documented, "README.md"), []byte("# Network\n"), 0o644))
+
+	files := []core.IaCFile{
+		{Path: filepath.Join(documented, "variables.tf"), Content: `variable "cidr" {
+  description = "CIDR block of the VPC"
+  type        = string
+}
+
+output "vpc_id" {
+  description = "ID of the VPC"
+  value       = "vpc-123"
+}
+`},
+		{Path: filepath.Join(undocumented, "variables.tf"), Content: `variable "instance_type" {
+  type = string
+}
+
+variable "ami" {
+  description = "  "
+}
+
+output "instance_id" {
+  value = "i-123"
+}
+`},
+	}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+
+	require.NoError(t, err)
+	assert.Equal(t, core.DocumentationCoverage{
+		Variables:             3,
+		DescribedVariables:    1,
+		Outputs:               2,
+		DescribedOutputs:      1,
+		Modules:               2,
+		ModulesWithReadme:     1,
+		UndocumentedVariables: []string{"var.instance_type", "var.ami"},
+		UndocumentedOutputs:   []string{"output.instance_id"},
+		ModulesWithoutReadme:  []string{undocumented},
+	}, model.Metadata["documentation_coverage"])
+}
+
+func TestParseTerraform_DocumentationCoverageRelativePaths(t *testing.T) {
+	// File paths are relative to the analyzed directory, not to the
+	// directory waffle runs in
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "network"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "network", "README.md"), []byte("# Network\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "readme.txt"), []byte("Root module\n"), 0o644))
+
+	files := []core.IaCFile{
+		{Path: "main.tf", Content: "variable \"region\" {\n  description = \"AWS region\"\n}\n"},
+		{Path: filepath.Join("network", "main.tf"), Content: "variable \"cidr\" {\n  description = \"CIDR block\"\n}\n"},
+		{Path: filepath.Join("compute", "main.tf"), Content: "variable \"ami\" {\n  description = \"AMI ID\"\n}\n"},
+	}
+
+	model, err := NewAnalyzerWithDir(root).ParseTerraform(context.Background(), files)
+
+	require.NoError(t, err)
+	coverage := model.Metadata["documentation_coverage"].(core.DocumentationCoverage)
+	assert.Equal(t, 3, coverage.Modules)
+	assert.Equal(t, 2, coverage.ModulesWithReadme)
+	assert.Equal(t, []string{"compute"}, coverage.ModulesWithoutReadme)
+}
+
+func TestDocumentationCoverage_Ratio(t *testing.T) {
+	tests := []struct {
+		name     string
+		coverage core.DocumentationCoverage
+		want     float64
+	}{
+		{name: "nothing to document", want: 0},
+		{
+			name:     "fully documented",
+			coverage: core.DocumentationCoverage{Variables: 2, DescribedVariables: 2, Modules: 1, ModulesWithReadme: 1},
+			want:     1,
+		},
+		{
+			name:     "partly documented",
+			coverage: core.DocumentationCoverage{Variables: 2, DescribedVariables: 1, Outputs: 1, Modules: 1},
+			want:     0.25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.coverage.Ratio(), 0.001)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package iac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// fingerprintResource hashes the type and properties of a resource, which
+// must already be normalized. encoding/json sorts map keys, so equal
+// properties serialize to the same bytes whatever order they were read in.
+// The address is left out: a resource that is only renamed or moved into a
+// module keeps its fingerprint.
+func fingerprintResource(resource core.Resource) string {
+	data, err := json.Marshal(struct {
+		Type       string                 `json:"type"`
+		Properties map[string]interface{} `json:"properties"`
+	}{resource.Type, resource.Properties})
+	if err != nil {
+		// Properties come from HCL or JSON and always marshal
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
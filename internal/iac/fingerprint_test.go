@@ -0,0 +1,99 @@
+package iac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestExtractResources_Fingerprint(t *testing.T) {
+	base := core.Resource{
+		Address: "aws_s3_bucket.logs",
+		Type:    "aws_s3_bucket",
+		Properties: map[string]interface{}{
+			"bucket":        "acme-logs",
+			"force_destroy": false,
+			"tags":          map[string]interface{}{"Team": "platform"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resource core.Resource
+		wantSame bool
+	}{
+		{
+			name:     "identical resource",
+			resource: base,
+			wantSame: true,
+		},
+		{
+			name: "same content from another address and source",
+			resource: core.Resource{
+				Address:    "module.logs.aws_s3_bucket.this",
+				Type:       "aws_s3_bucket",
+				IsFromPlan: true,
+				Properties: map[string]interface{}{
+					"tags":          map[string]interface{}{"Team": "platform"},
+					"Force_Destroy": false,
+					"bucket":        "acme-logs",
+				},
+			},
+			wantSame: true,
+		},
+		{
+			name: "changed property",
+			resource: core.Resource{
+				Address: "aws_s3_bucket.logs",
+				Type:    "aws_s3_bucket",
+				Properties: map[string]interface{}{
+					"bucket":        "acme-logs",
+					"force_destroy": true,
+					"tags":          map[string]interface{}{"Team": "platform"},
+				},
+			},
+		},
+		{
+			name: "changed tag value",
+			resource: core.Resource{
+				Address: "aws_s3_bucket.logs",
+				Type:    "aws_s3_bucket",
+				Properties: map[string]interface{}{
+					"bucket":        "acme-logs",
+					"force_destroy": false,
+					"tags":          map[string]interface{}{"Team": "data"},
+				},
+			},
+		},
+		{
+			name: "changed type",
+			resource: core.Resource{
+				Address:    "aws_s3_bucket.logs",
+				Type:       "aws_s3_directory_bucket",
+				Properties: base.Properties,
+			},
+		},
+	}
+
+	analyzer := NewAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &core.WorkloadModel{Resources: []core.Resource{base, tt.resource}}
+
+			resources, err := analyzer.ExtractResources(context.Background(), model)
+
+			require.NoError(t, err)
+			require.Len(t, resources, 2)
+			assert.Len(t, resources[0].Fingerprint, 64)
+			if tt.wantSame {
+				assert.Equal(t, resources[0].Fingerprint, resources[1].Fingerprint)
+			} else {
+				assert.NotEqual(t, resources[0].Fingerprint, resources[1].Fingerprint)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package iac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxSupportedFormatMajor is the newest major format_version of Terraform
+// JSON output that Waffle understands. Terraform only bumps the minor
+// version for backward compatible changes.
+const maxSupportedFormatMajor = 1
+
+// checkFormatVersion returns an error when format_version has a major
+// version Waffle does not support. Files without a format_version are
+// accepted as before.
+func checkFormatVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+
+	majorPart, _, _ := strings.Cut(version, ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil || major < 0 {
+		return fmt.Errorf("invalid format_version %q", version)
+	}
+	if major > maxSupportedFormatMajor {
+		return fmt.Errorf("format_version %s is not supported, this version of Waffle reads format versions up to %d.x; upgrade Waffle to review this file",
+			version, maxSupportedFormatMajor)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package iac
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestCheckFormatVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: ""},
+		{version: "0.1"},
+		{version: "0.2"},
+		{version: "1.0"},
+		{version: "1.2"},
+		{version: "1.9"},
+		{version: "2.0", wantErr: true},
+		{version: "99.0", wantErr: true},
+		{version: "latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			err := checkFormatVersion(tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseTerraformPlan_FormatVersion(t *testing.T) {
+	planContent := func(version string) string {
+		return `{
+  "format_version": "` + version + `",
+  "terraform_version": "1.5.0",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.example",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "example",
+          "values": {"bucket": "my-test-bucket"}
+        }
+      ]
+    }
+  }
+}`
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		planFile := filepath.Join(t.TempDir(), "plan.json")
+		require.NoError(t, os.WriteFile(planFile, []byte(planContent("1.2")), 0644))
+
+		model, err := NewAnalyzer().ParseTerraformPlan(context.Background(), planFile)
+
+		require.NoError(t, err)
+		assert.Len(t, model.Resources, 1)
+	})
+
+	t.Run("far future", func(t *testing.T) {
+		planFile := filepath.Join(t.TempDir(), "plan.json")
+		require.NoError(t, os.WriteFile(planFile, []byte(planContent("99.0")), 0644))
+
+		model, err := NewAnalyzer().ParseTerraformPlan(context.Background(), planFile)
+
+		require.Error(t, err)
+		assert.Nil(t, model)
+		var parsingErr *core.IaCParsingError
+		require.True(t, errors.As(err, &parsingErr))
+		assert.Equal(t, planFile, parsingErr.File)
+		assert.Equal(t, "unsupported Terraform JSON format", parsingErr.Context)
+		assert.Contains(t, err.Error(), "format_version 99.0 is not supported")
+		assert.Contains(t, err.Error(), "upgrade Waffle")
+	})
+}
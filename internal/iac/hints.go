@@ -0,0 +1,160 @@
+package iac
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// Hint rules reported by InspectResource
+const (
+	HintRulePublicS3Bucket           = "public-s3-bucket"
+	HintRuleOpenSecurityGroupIngress = "open-security-group-ingress"
+)
+
+// hintDetector reports anti-patterns in the normalized properties of a resource
+type hintDetector func(resource core.Resource) []core.ResourceHint
+
+// hintDetectors run against every resource. They look only at properties and
+// favour obvious, high-signal patterns over completeness.
+var hintDetectors = []hintDetector{
+	detectPublicS3Bucket,
+	detectOpenSecurityGroupIngress,
+}
+
+// publicACLs are canned ACLs that grant access to everyone
+var publicACLs = map[string]bool{
+	"public-read":       true,
+	"public-read-write": true,
+}
+
+// openCIDRs match every IPv4 or IPv6 address
+var openCIDRs = map[string]bool{
+	"0.0.0.0/0": true,
+	"::/0":      true,
+}
+
+// InspectResource returns the hints found in a resource's properties
+func InspectResource(resource core.Resource) []core.ResourceHint {
+	var hints []core.ResourceHint
+	for _, detect := range hintDetectors {
+		hints = append(hints, detect(resource)...)
+	}
+	return hints
+}
+
+// detectPublicS3Bucket flags buckets with a public canned ACL
+func detectPublicS3Bucket(resource core.Resource) []core.ResourceHint {
+	if resource.Type != "aws_s3_bucket" && resource.Type != "aws_s3_bucket_acl" {
+		return nil
+	}
+
+	acl, _ := resource.Properties["acl"].(string)
+	if !publicACLs[acl] {
+		return nil
+	}
+
+	return []core.ResourceHint{{
+		Rule:     HintRulePublicS3Bucket,
+		Pillar:   core.PillarSecurity,
+		Severity: core.RiskLevelHigh,
+		Message:  fmt.Sprintf("S3 bucket ACL %q makes objects readable by anyone", acl),
+	}}
+}
+
+// detectOpenSecurityGroupIngress flags ingress rules open to the internet,
+// whether declared inline, as aws_security_group_rule or as
+// aws_vpc_security_group_ingress_rule
+func detectOpenSecurityGroupIngress(resource core.Resource) []core.ResourceHint {
+	var rules []map[string]interface{}
+	switch resource.Type {
+	case "aws_security_group":
+		rules = propertyObjects(resource.Properties["ingress"])
+	case "aws_security_group_rule":
+		if resource.Properties["type"] == "ingress" {
+			rules = []map[string]interface{}{resource.Properties}
+		}
+	case "aws_vpc_security_group_ingress_rule":
+		rules = []map[string]interface{}{resource.Properties}
+	default:
+		return nil
+	}
+
+	var hints []core.ResourceHint
+	for _, rule := range rules {
+		cidr := openCIDR(rule)
+		if cidr == "" {
+			continue
+		}
+		hints = append(hints, core.ResourceHint{
+			Rule:     HintRuleOpenSecurityGroupIngress,
+			Pillar:   core.PillarSecurity,
+			Severity: core.RiskLevelHigh,
+			Message:  fmt.Sprintf("ingress %s allows traffic from %s", portRange(rule), cidr),
+		})
+	}
+	return hints
+}
+
+// openCIDR returns the first CIDR of an ingress rule that matches every address
+func openCIDR(rule map[string]interface{}) string {
+	for _, key := range []string{"cidr_blocks", "ipv6_cidr_blocks", "cidr_ipv4", "cidr_ipv6"} {
+		for _, cidr := range propertyStrings(rule[key]) {
+			if openCIDRs[cidr] {
+				return cidr
+			}
+		}
+	}
+	return ""
+}
+
+// portRange describes the ports of an ingress rule
+func portRange(rule map[string]interface{}) string {
+	from, to := fmt.Sprint(rule["from_port"]), fmt.Sprint(rule["to_port"])
+	switch {
+	case rule["from_port"] == nil, rule["protocol"] == "-1", rule["ip_protocol"] == "-1":
+		return "on all ports"
+	case from == to:
+		return "on port " + from
+	default:
+		return fmt.Sprintf("on ports %s-%s", from, to)
+	}
+}
+
+// propertyObjects returns a nested block property as a list of objects. HCL
+// stores a single block as a map and repeated blocks as a list.
+func propertyObjects(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		objects := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if object, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+		return objects
+	default:
+		return nil
+	}
+}
+
+// propertyStrings returns a string or list-of-strings property as a list
+func propertyStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{strings.TrimSpace(v)}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package iac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestInspectResource_PublicS3Bucket(t *testing.T) {
+	tests := []struct {
+		name      string
+		resource  core.Resource
+		wantHints int
+	}{
+		{
+			name:      "public-read bucket",
+			resource:  core.Resource{Type: "aws_s3_bucket", Properties: map[string]interface{}{"acl": "public-read"}},
+			wantHints: 1,
+		},
+		{
+			name:      "public-read-write bucket acl resource",
+			resource:  core.Resource{Type: "aws_s3_bucket_acl", Properties: map[string]interface{}{"acl": "public-read-write"}},
+			wantHints: 1,
+		},
+		{
+			name:     "private bucket",
+			resource: core.Resource{Type: "aws_s3_bucket", Properties: map[string]interface{}{"acl": "private"}},
+		},
+		{
+			name:     "bucket without acl",
+			resource: core.Resource{Type: "aws_s3_bucket", Properties: map[string]interface{}{"bucket": "logs"}},
+		},
+		{
+			name:     "acl on another resource type",
+			resource: core.Resource{Type: "aws_instance", Properties: map[string]interface{}{"acl": "public-read"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hints := InspectResource(tt.resource)
+
+			require.Len(t, hints, tt.wantHints)
+			for _, hint := range hints {
+				assert.Equal(t, HintRulePublicS3Bucket, hint.Rule)
+				assert.Equal(t, core.PillarSecurity, hint.Pillar)
+				assert.Equal(t, core.RiskLevelHigh, hint.Severity)
+			}
+		})
+	}
+}
+
+func TestInspectResource_OpenSecurityGroupIngress(t *testing.T) {
+	tests := []struct {
+		name         string
+		resource     core.Resource
+		wantMessages []string
+	}{
+		{
+			name: "single inline ingress block",
+			resource: core.Resource{Type: "aws_security_group", Properties: map[string]interface{}{
+				"ingress": map[string]interface{}{
+					"from_port":   int64(22),
+					"to_port":     int64(22),
+					"protocol":    "tcp",
+					"cidr_blocks": []interface{}{"0.0.0.0/0"},
+				},
+			}},
+			wantMessages: []string{"ingress on port 22 allows traffic from 0.0.0.0/0"},
+		},
+		{
+			name: "repeated inline ingress blocks",
+			resource: core.Resource{Type: "aws_security_group", Properties: map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{"from_port": int64(443), "to_port": int64(443), "cidr_blocks": []interface{}{"10.0.0.0/8"}},
+					map[string]interface{}{"from_port": int64(8000), "to_port": int64(8080), "ipv6_cidr_blocks": []interface{}{"::/0"}},
+				},
+			}},
+			wantMessages: []string{"ingress on ports 8000-8080 allows traffic from ::/0"},
+		},
+		{
+			name: "ingress security group rule",
+			resource: core.Resource{Type: "aws_security_group_rule", Properties: map[string]interface{}{
+				"type":        "ingress",
+				"from_port":   int64(0),
+				"to_port":     int64(0),
+				"protocol":    "-1",
+				"cidr_blocks": []interface{}{"0.0.0.0/0"},
+			}},
+			wantMessages: []string{"ingress on all ports allows traffic from 0.0.0.0/0"},
+		},
+		{
+			name: "egress security group rule",
+			resource: core.Resource{Type: "aws_security_group_rule", Properties: map[string]interface{}{
+				"type":        "egress",
+				"cidr_blocks": []interface{}{"0.0.0.0/0"},
+			}},
+		},
+		{
+			name: "vpc ingress rule",
+			resource: core.Resource{Type: "aws_vpc_security_group_ingress_rule", Properties: map[string]interface{}{
+				"from_port": int64(3389),
+				"to_port":   int64(3389),
+				"cidr_ipv4": "0.0.0.0/0",
+			}},
+			wantMessages: []string{"ingress on port 3389 allows traffic from 0.0.0.0/0"},
+		},
+		{
+			name: "egress only security group",
+			resource: core.Resource{Type: "aws_security_group", Properties: map[string]interface{}{
+				"egress": map[string]interface{}{"cidr_blocks": []interface{}{"0.0.0.0/0"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hints := InspectResource(tt.resource)
+
+			var messages []string
+			for _, hint := range hints {
+				assert.Equal(t, HintRuleOpenSecurityGroupIngress, hint.Rule)
+				assert.Equal(t, core.RiskLevelHigh, hint.Severity)
+				messages = append(messages, hint.Message)
+			}
+			assert.Equal(t, tt.wantMessages, messages)
+		})
+	}
+}
+
+func TestExtractResources_AttachesHints(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := `resource "aws_s3_bucket" "site" {
+  bucket = "public-site"
+  acl    = "public-read"
+}
+
+resource "aws_security_group" "web" {
+  name = "web"
+
+  ingress {
+    from_port   = 22
+    to_port     = 22
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte(config), 0644))
+
+	ctx := context.Background()
+	analyzer := NewAnalyzerWithDir(tmpDir)
+	files, err := analyzer.RetrieveIaCFiles(ctx)
+	require.NoError(t, err)
+	model, err := analyzer.ParseTerraform(ctx, files)
+	require.NoError(t, err)
+
+	resources, err := analyzer.ExtractResources(ctx, model)
+	require.NoError(t, err)
+
+	rules := make(map[string][]string)
+	for _, resource := range resources {
+		for _, hint := range resource.Hints {
+			rules[resource.Address] = append(rules[resource.Address], hint.Rule)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"aws_s3_bucket.site":     {HintRulePublicS3Bucket},
+		"aws_security_group.web": {HintRuleOpenSecurityGroupIngress},
+	}, rules)
+}
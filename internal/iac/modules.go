@@ -0,0 +1,464 @@
+package iac
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// maxModuleDepth bounds module nesting so a module that calls itself cannot
+// recurse forever
+const maxModuleDepth = 16
+
+// moduleDir holds the declarations of the Terraform files in one directory
+type moduleDir struct {
+	resources []core.Resource
+	// refs holds the references made in each resource body, by address
+	refs    map[string][]hcl.Traversal
+	calls   []moduleCall
+	outputs map[string][]hcl.Traversal
+}
+
+// moduleCall is a module block
+type moduleCall struct {
+	name   string
+	source string
+	// args holds the references made in each module argument
+	args map[string][]hcl.Traversal
+	// instances is the count or for_each expression, nil when the call has
+	// neither
+	instances hcl.Expression
+}
+
+// mayHaveNoInstances reports whether the call's count or for_each is zero,
+// empty, could not be evaluated with vars, the variables of the calling
+// configuration, or refers to any value. Variables are only known by their
+// defaults and tfvars files here, and the plan may have been made with
+// others.
+func (c *moduleCall) mayHaveNoInstances(vars *hcl.EvalContext) bool {
+	if c.instances == nil {
+		return false
+	}
+	if len(c.instances.Variables()) > 0 {
+		return true
+	}
+	value, diags := c.instances.Value(vars)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return true
+	}
+	goValue, err := ctyToGo(value)
+	return err != nil || noInstances(goValue)
+}
+
+// conditionalResources returns the sorted addresses of the resources whose
+// count or for_each refers to variables, locals or other resources. Their
+// properties hold the values those had in the configuration, which need not
+// be the ones the plan was made with. files are the parsed files the
+// resources were extracted from.
+func conditionalResources(files map[string]*hcl.File, resources []core.Resource) []string {
+	conditional := make(map[string]map[string]bool)
+	for path, file := range files {
+		content, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "resource", LabelNames: []string{"type", "name"}}},
+		})
+		if content == nil {
+			continue
+		}
+		for _, block := range content.Blocks {
+			attrs, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+				Attributes: []hcl.AttributeSchema{{Name: "count"}, {Name: "for_each"}},
+			})
+			if attrs == nil {
+				continue
+			}
+			for _, attr := range attrs.Attributes {
+				if len(attr.Expr.Variables()) > 0 {
+					if conditional[path] == nil {
+						conditional[path] = make(map[string]bool)
+					}
+					conditional[path][block.Labels[0]+"."+block.Labels[1]] = true
+				}
+			}
+		}
+	}
+
+	var addresses []string
+	for _, resource := range resources {
+		// Called modules prefix the addresses of their resources
+		address := resource.Address
+		if resource.ModulePath != "" {
+			address = strings.TrimPrefix(address, resource.ModulePath+".")
+		}
+		if conditional[resource.SourceFile][address] {
+			addresses = append(addresses, resource.Address)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// moduleInstance is a module call resolved to a local directory. The root
+// instance of a configuration has no call and an empty prefix.
+type moduleInstance struct {
+	dir      string
+	prefix   string
+	call     *moduleCall
+	parent   *moduleInstance
+	children map[string]*moduleInstance
+	// conditional is set when the call, or a call enclosing it, may have no
+	// instances
+	conditional bool
+}
+
+// isLocalModuleSource reports whether a module source is a local path.
+// Registry, git and other remote sources are not resolved.
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// resolveLocalModules expands calls to local modules. Resources of a called
+// module get module-prefixed addresses (module.vpc.aws_vpc.main) and a
+// ModulePath, once per call. References to module outputs, input variables
+// and resources of the same module are recorded as dependencies so that
+// relationships cross module boundaries. Module directories outside the
+// analyzed files are read from disk. Directories no local module call points
+// at are roots and keep their addresses. The sorted paths of the modules
+// whose count or for_each may leave them without instances are returned
+// alongside the resources.
+func (a *Analyzer) resolveLocalModules(ctx context.Context, parser *hclparse.Parser, files map[string]*hcl.File, resources []core.Resource, vars map[string]*hcl.EvalContext) ([]core.Resource, []string) {
+	dirs := make(map[string]*moduleDir)
+	dirOf := func(dir string) *moduleDir {
+		if dirs[dir] == nil {
+			dirs[dir] = &moduleDir{refs: make(map[string][]hcl.Traversal), outputs: make(map[string][]hcl.Traversal)}
+		}
+		return dirs[dir]
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		collectModuleDeclarations(dirOf(filepath.Dir(path)), files[path])
+	}
+	for _, resource := range resources {
+		dir := dirOf(filepath.Dir(resource.SourceFile))
+		dir.resources = append(dir.resources, resource)
+	}
+
+	// Without module calls there is nothing to resolve
+	hasLocalCalls := false
+	for _, dir := range dirs {
+		for _, call := range dir.calls {
+			hasLocalCalls = hasLocalCalls || isLocalModuleSource(call.source)
+		}
+	}
+	if !hasLocalCalls {
+		return resources, nil
+	}
+
+	// Module directories are found from the calls, reading any that were not
+	// among the analyzed files
+	called := make(map[string]bool)
+	pending := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		pending = append(pending, dir)
+	}
+	sort.Strings(pending)
+	for len(pending) > 0 {
+		dir := pending[0]
+		pending = pending[1:]
+		for _, call := range dirs[dir].calls {
+			if !isLocalModuleSource(call.source) {
+				slog.DebugContext(ctx, "leaving remote module unresolved", "module", call.name, "source", call.source)
+				continue
+			}
+			target := filepath.Join(dir, call.source)
+			if called[target] {
+				continue
+			}
+			called[target] = true
+			if _, ok := dirs[target]; !ok {
+				loaded, err := a.loadModuleDir(ctx, parser, target)
+				if err != nil {
+					slog.WarnContext(ctx, "failed to read local module", "module", call.name, "source", call.source, "error", err)
+					a.warn(core.AnalysisWarningParse, call.source, "module %s not read: %v", call.name, err)
+					continue
+				}
+				dirs[target] = loaded
+				pending = append(pending, target)
+			}
+		}
+	}
+
+	// Every directory that no module call points at is a root configuration
+	roots := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		if !called[dir] {
+			roots = append(roots, dir)
+		}
+	}
+	sort.Strings(roots)
+
+	var instances []*moduleInstance
+	for _, dir := range roots {
+		instances = append(instances, instantiateModule(dirs, vars, &moduleInstance{dir: dir}, 0)...)
+	}
+
+	var resolved []core.Resource
+	var conditional []string
+	for _, instance := range instances {
+		if instance.conditional {
+			conditional = append(conditional, strings.TrimSuffix(instance.prefix, "."))
+		}
+		for _, resource := range dirs[instance.dir].resources {
+			refs := dirs[instance.dir].refs[resource.Address]
+			resource.Address = instance.prefix + resource.Address
+			resource.ID = resource.Address
+			resource.ModulePath = strings.TrimSuffix(instance.prefix, ".")
+
+			var dependencies []string
+			for _, traversal := range refs {
+				for _, dep := range resolveReference(dirs, instance, traversal, 0) {
+					if dep != resource.Address && !contains(dependencies, dep) {
+						dependencies = append(dependencies, dep)
+					}
+				}
+			}
+			resource.Dependencies = append(append([]string{}, resource.Dependencies...), dependencies...)
+			resolved = append(resolved, resource)
+		}
+	}
+
+	slog.InfoContext(ctx, "resolved local modules",
+		"module_dirs", len(called),
+		"resources", len(resolved),
+	)
+
+	sort.Strings(conditional)
+	return resolved, conditional
+}
+
+// instantiateModule returns instance and the instances of the local modules
+// it calls, recursively. vars holds the variables of root configurations, by
+// directory, for evaluating the count and for_each of their calls.
+func instantiateModule(dirs map[string]*moduleDir, vars map[string]*hcl.EvalContext, instance *moduleInstance, depth int) []*moduleInstance {
+	instances := []*moduleInstance{instance}
+	if depth >= maxModuleDepth {
+		return instances
+	}
+
+	instance.children = make(map[string]*moduleInstance)
+	for i := range dirs[instance.dir].calls {
+		call := &dirs[instance.dir].calls[i]
+		target := filepath.Join(instance.dir, call.source)
+		if !isLocalModuleSource(call.source) || dirs[target] == nil {
+			continue
+		}
+
+		child := &moduleInstance{
+			dir:         target,
+			prefix:      instance.prefix + "module." + call.name + ".",
+			call:        call,
+			parent:      instance,
+			conditional: instance.conditional || call.mayHaveNoInstances(vars[instance.dir]),
+		}
+		instance.children[call.name] = child
+		instances = append(instances, instantiateModule(dirs, vars, child, depth+1)...)
+	}
+	return instances
+}
+
+// resolveReference returns the resource addresses a reference made within a
+// module instance points at
+func resolveReference(dirs map[string]*moduleDir, instance *moduleInstance, traversal hcl.Traversal, depth int) []string {
+	if depth >= maxModuleDepth {
+		return nil
+	}
+
+	names := traversalNames(traversal)
+	if len(names) < 2 {
+		return nil
+	}
+
+	switch names[0] {
+	case "module":
+		// module.<name>.<output> resolves to what the output refers to
+		child := instance.children[names[1]]
+		if child == nil || len(names) < 3 {
+			return nil
+		}
+		var addresses []string
+		for _, ref := range dirs[child.dir].outputs[names[2]] {
+			addresses = append(addresses, resolveReference(dirs, child, ref, depth+1)...)
+		}
+		return addresses
+
+	case "var":
+		// var.<name> resolves to what the caller passed in
+		if instance.call == nil {
+			return nil
+		}
+		var addresses []string
+		for _, ref := range instance.call.args[names[1]] {
+			addresses = append(addresses, resolveReference(dirs, instance.parent, ref, depth+1)...)
+		}
+		return addresses
+
+	case "data":
+		if len(names) < 3 {
+			return nil
+		}
+		return localAddress(dirs[instance.dir], instance.prefix, "data."+names[1]+"."+names[2])
+
+	case "local", "each", "count", "path", "self", "terraform":
+		return nil
+
+	default:
+		return localAddress(dirs[instance.dir], instance.prefix, names[0]+"."+names[1])
+	}
+}
+
+// localAddress returns the prefixed address when the module declares it
+func localAddress(dir *moduleDir, prefix, address string) []string {
+	for _, resource := range dir.resources {
+		if resource.Address == address {
+			return []string{prefix + address}
+		}
+	}
+	return nil
+}
+
+// traversalNames returns the root name and the attribute names of a
+// traversal, stopping at the first index step
+func traversalNames(traversal hcl.Traversal) []string {
+	var names []string
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, s.Name)
+		case hcl.TraverseAttr:
+			names = append(names, s.Name)
+		default:
+			return names
+		}
+	}
+	return names
+}
+
+// collectModuleDeclarations records the references, module calls and outputs
+// declared in a parsed file
+func collectModuleDeclarations(dir *moduleDir, file *hcl.File) {
+	content, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "resource", LabelNames: []string{"type", "name"}},
+			{Type: "data", LabelNames: []string{"type", "name"}},
+			{Type: "module", LabelNames: []string{"name"}},
+			{Type: "output", LabelNames: []string{"name"}},
+		},
+	})
+	if content == nil {
+		return
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "resource":
+			address := block.Labels[0] + "." + block.Labels[1]
+			dir.refs[address] = append(dir.refs[address], bodyTraversals(block.Body)...)
+		case "data":
+			address := "data." + block.Labels[0] + "." + block.Labels[1]
+			dir.refs[address] = append(dir.refs[address], bodyTraversals(block.Body)...)
+		case "module":
+			call := moduleCall{name: block.Labels[0], args: make(map[string][]hcl.Traversal)}
+			attrs, _ := block.Body.JustAttributes()
+			for name, attr := range attrs {
+				switch name {
+				case "source":
+					if value, diags := attr.Expr.Value(nil); !diags.HasErrors() && value.Type().FriendlyName() == "string" {
+						call.source = value.AsString()
+					}
+					continue
+				case "count", "for_each":
+					call.instances = attr.Expr
+				}
+				call.args[name] = attr.Expr.Variables()
+			}
+			dir.calls = append(dir.calls, call)
+		case "output":
+			attrs, _ := block.Body.JustAttributes()
+			if value, ok := attrs["value"]; ok {
+				dir.outputs[block.Labels[0]] = value.Expr.Variables()
+			}
+		}
+	}
+}
+
+// bodyTraversals returns the references made anywhere in a block body,
+// including nested blocks
+func bodyTraversals(body hcl.Body) []hcl.Traversal {
+	var traversals []hcl.Traversal
+	if syntaxBody, ok := body.(*hclsyntax.Body); ok {
+		for _, attr := range syntaxBody.Attributes {
+			traversals = append(traversals, attr.Expr.Variables()...)
+		}
+		for _, block := range syntaxBody.Blocks {
+			traversals = append(traversals, bodyTraversals(block.Body)...)
+		}
+		return traversals
+	}
+
+	// JSON bodies hold nested blocks as attributes
+	attrs, _ := body.JustAttributes()
+	for _, attr := range attrs {
+		traversals = append(traversals, attr.Expr.Variables()...)
+	}
+	return traversals
+}
+
+// loadModuleDir reads and parses the Terraform files of a local module that
+// is outside the analyzed files. dir is relative to the working directory.
+func (a *Analyzer) loadModuleDir(ctx context.Context, parser *hclparse.Parser, dir string) (*moduleDir, error) {
+	entries, err := os.ReadDir(filepath.Join(a.workingDir, dir))
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &moduleDir{refs: make(map[string][]hcl.Traversal), outputs: make(map[string][]hcl.Traversal)}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !isTerraformFile(path) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(a.workingDir, path))
+		if err != nil {
+			return nil, err
+		}
+		redacted, findings := a.redactor.Redact(string(content))
+		a.warnRedacted(path, findings)
+
+		file, diags := parseTerraformFile(parser, core.IaCFile{Path: path, Content: redacted})
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+		}
+
+		resources, err := a.extractResourcesFromHCLWithRedaction(ctx, file, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		loaded.resources = append(loaded.resources, resources...)
+		collectModuleDeclarations(loaded, file)
+	}
+	return loaded, nil
+}
@@ -0,0 +1,298 @@
+package iac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+const rootModuleConfig = `module "vpc" {
+  source     = "./modules/vpc"
+  cidr_block = "10.0.0.0/16"
+  flow_logs  = aws_s3_bucket.logs.arn
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "~> 20.0"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "flow-logs"
+}
+
+resource "aws_instance" "web" {
+  ami       = "ami-12345"
+  subnet_id = module.vpc.private_subnet_id
+}
+`
+
+const vpcModuleConfig = `variable "cidr_block" {}
+variable "flow_logs" {}
+
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr_block
+}
+
+resource "aws_subnet" "private" {
+  vpc_id     = aws_vpc.main.id
+  cidr_block = cidrsubnet(var.cidr_block, 8, 1)
+}
+
+resource "aws_flow_log" "main" {
+  vpc_id          = aws_vpc.main.id
+  log_destination = var.flow_logs
+}
+
+output "private_subnet_id" {
+  value = aws_subnet.private.id
+}
+`
+
+func TestParseTerraform_ResolvesLocalModules(t *testing.T) {
+	files := []core.IaCFile{
+		{Path: "main.tf", Content: rootModuleConfig},
+		{Path: filepath.Join("modules", "vpc", "main.tf"), Content: vpcModuleConfig},
+	}
+
+	analyzer := NewAnalyzer()
+	model, err := analyzer.ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+
+	resources := make(map[string]core.Resource)
+	for _, resource := range model.Resources {
+		resources[resource.Address] = resource
+	}
+	require.Len(t, resources, 5)
+
+	subnet, ok := resources["module.vpc.aws_subnet.private"]
+	require.True(t, ok, "module resources get module-prefixed addresses")
+	assert.Equal(t, "module.vpc", subnet.ModulePath)
+	assert.Equal(t, []string{"module.vpc.aws_vpc.main"}, subnet.Dependencies)
+	assert.Empty(t, resources["aws_instance.web"].ModulePath)
+
+	// Resources instantiated from a module's source stay managed, and their
+	// ModulePath tells them apart from those of the root configuration
+	assert.Equal(t, core.ResourceKindManaged, subnet.Kind)
+	assert.Equal(t, core.ResourceKindManaged, resources["aws_instance.web"].Kind)
+
+	graph, err := analyzer.IdentifyRelationships(context.Background(), model.Resources)
+	require.NoError(t, err)
+
+	// A module output reference leads into the module, a module argument
+	// leads back out of it
+	assert.Contains(t, graph.Edges["aws_instance.web"], "module.vpc.aws_subnet.private")
+	assert.Contains(t, graph.Edges["module.vpc.aws_flow_log.main"], "aws_s3_bucket.logs")
+	assert.Contains(t, graph.Edges["module.vpc.aws_flow_log.main"], "module.vpc.aws_vpc.main")
+}
+
+func TestParseTerraform_ReadsModulesOutsideAnalyzedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	moduleDir := filepath.Join(tmpDir, "modules", "vpc")
+	require.NoError(t, os.MkdirAll(moduleDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(vpcModuleConfig), 0644))
+
+	files := []core.IaCFile{{Path: "main.tf", Content: rootModuleConfig}}
+
+	analyzer := NewAnalyzerWithDir(tmpDir)
+	model, err := analyzer.ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+
+	var addresses []string
+	for _, resource := range model.Resources {
+		addresses = append(addresses, resource.Address)
+	}
+	assert.ElementsMatch(t, []string{
+		"aws_s3_bucket.logs",
+		"aws_instance.web",
+		"module.vpc.aws_vpc.main",
+		"module.vpc.aws_subnet.private",
+		"module.vpc.aws_flow_log.main",
+	}, addresses)
+}
+
+func TestParseTerraform_ModuleCalledTwice(t *testing.T) {
+	files := []core.IaCFile{
+		{
+			Path: "main.tf",
+			Content: `module "primary" {
+  source     = "./modules/vpc"
+  cidr_block = "10.0.0.0/16"
+}
+
+module "secondary" {
+  source     = "./modules/vpc"
+  cidr_block = "10.1.0.0/16"
+}
+`,
+		},
+		{Path: filepath.Join("modules", "vpc", "main.tf"), Content: vpcModuleConfig},
+	}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+
+	modulePaths := make(map[string]int)
+	for _, resource := range model.Resources {
+		modulePaths[resource.ModulePath]++
+	}
+	assert.Equal(t, map[string]int{"module.primary": 3, "module.secondary": 3}, modulePaths)
+}
+
+func TestParseTerraform_UnresolvedModulesKeepReferences(t *testing.T) {
+	files := []core.IaCFile{{Path: "main.tf", Content: rootModuleConfig}}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+
+	var web *core.Resource
+	for i := range model.Resources {
+		if model.Resources[i].Address == "aws_instance.web" {
+			web = &model.Resources[i]
+		}
+	}
+	require.NotNil(t, web)
+	assert.Empty(t, web.Dependencies)
+	assert.Equal(t, "${module.vpc.private_subnet_id}", web.Properties["subnet_id"])
+}
+
+func TestMergeWorkloadModels_DisabledModules(t *testing.T) {
+	files := []core.IaCFile{
+		{
+			Path: "main.tf",
+			Content: `variable "enabled" {
+  default = false
+}
+
+variable "replicas" {}
+
+module "disabled" {
+  source     = "./modules/vpc"
+  count      = 0
+  cidr_block = "10.0.0.0/16"
+}
+
+module "optional" {
+  source     = "./modules/vpc"
+  count      = var.enabled ? 1 : 0
+  cidr_block = "10.1.0.0/16"
+}
+
+module "replicated" {
+  source     = "./modules/vpc"
+  for_each   = var.replicas
+  cidr_block = "10.2.0.0/16"
+}
+
+module "enabled" {
+  source     = "./modules/vpc"
+  count      = 1
+  cidr_block = "10.3.0.0/16"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "flow-logs"
+}
+`,
+		},
+		{Path: filepath.Join("modules", "vpc", "main.tf"), Content: vpcModuleConfig},
+	}
+
+	analyzer := NewAnalyzer()
+	configModel, err := analyzer.ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"module.disabled", "module.optional", "module.replicated"}, configModel.Metadata["conditional_modules"])
+
+	planModel := &core.WorkloadModel{
+		Resources:  []core.Resource{{Address: "aws_s3_bucket.logs"}},
+		SourceType: "plan",
+	}
+	merged, err := analyzer.MergeWorkloadModels(context.Background(), planModel, configModel)
+	require.NoError(t, err)
+
+	// Modules whose count or for_each is zero or unknown may be absent from
+	// a fresh plan; a module that is always instantiated may not
+	assert.Equal(t, []string{
+		"module.enabled.aws_flow_log.main",
+		"module.enabled.aws_subnet.private",
+		"module.enabled.aws_vpc.main",
+	}, merged.MissingFromPlan)
+}
+
+func TestMergeWorkloadModels_VariableInstances(t *testing.T) {
+	files := []core.IaCFile{
+		{
+			Path: "main.tf",
+			Content: `variable "create" {
+  default = true
+}
+
+variable "queues" {
+  default = ["jobs"]
+}
+
+resource "aws_sqs_queue" "optional" {
+  count = var.create ? 1 : 0
+  name  = "optional"
+}
+
+resource "aws_sqs_queue" "named" {
+  for_each = toset(var.queues)
+  name     = each.key
+}
+
+resource "aws_sqs_queue" "always" {
+  count = 1
+  name  = "always"
+}
+
+module "alarms" {
+  source = "./modules/alarms"
+  count  = var.create ? 1 : 0
+}
+
+module "logs" {
+  source = "./modules/logs"
+  create = var.create
+}
+`,
+		},
+		{Path: filepath.Join("modules", "alarms", "main.tf"), Content: `resource "aws_sns_topic" "alarms" {
+  name = "alarms"
+}
+`},
+		{Path: filepath.Join("modules", "logs", "main.tf"), Content: `variable "create" {
+  default = true
+}
+
+resource "aws_cloudwatch_log_group" "app" {
+  count = var.create ? 1 : 0
+  name  = "app"
+}
+`},
+	}
+
+	analyzer := NewAnalyzer()
+	configModel, err := analyzer.ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"module.alarms"}, configModel.Metadata["conditional_modules"])
+	assert.Equal(t, []string{
+		"aws_sqs_queue.named",
+		"aws_sqs_queue.optional",
+		"module.logs.aws_cloudwatch_log_group.app",
+	}, configModel.Metadata["conditional_resources"])
+
+	// The plan was made with create = false and no queues
+	planModel := &core.WorkloadModel{SourceType: "plan"}
+	merged, err := analyzer.MergeWorkloadModels(context.Background(), planModel, configModel)
+	require.NoError(t, err)
+
+	// Counts that only the variable defaults make non-zero are not taken
+	// as a stale plan; a literal count is
+	assert.Equal(t, []string{"aws_sqs_queue.always"}, merged.MissingFromPlan)
+}
@@ -0,0 +1,105 @@
+package iac
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// exportedFormatVersion is the format_version written by WritePlanJSON
+const exportedFormatVersion = "1.2"
+
+// exportedPlan is the plan JSON document written by WritePlanJSON. It holds
+// only the planned values, the part other plan-consuming tools read.
+type exportedPlan struct {
+	FormatVersion string        `json:"format_version"`
+	PlannedValues PlannedValues `json:"planned_values"`
+}
+
+// WritePlanJSON writes the resources of a workload model as Terraform plan
+// JSON: resources with their properties as values under planned_values,
+// resources of a module in a child module of that address. Only what Waffle
+// analyzed is written, so redacted values stay redacted and there are no
+// resource changes or configuration. ParseTerraformPlan reads it back.
+func WritePlanJSON(w io.Writer, model *core.WorkloadModel) error {
+	root := &Module{
+		Resources:    []PlanResource{},
+		ChildModules: []ChildModule{},
+	}
+	children := make(map[string]*ChildModule)
+
+	if model != nil {
+		for _, resource := range model.Resources {
+			planResource := exportPlanResource(resource)
+			if resource.ModulePath == "" {
+				root.Resources = append(root.Resources, planResource)
+				continue
+			}
+			child, ok := children[resource.ModulePath]
+			if !ok {
+				child = &ChildModule{
+					Address:      resource.ModulePath,
+					Resources:    []PlanResource{},
+					ChildModules: []ChildModule{},
+				}
+				children[resource.ModulePath] = child
+			}
+			child.Resources = append(child.Resources, planResource)
+		}
+	}
+
+	addresses := make([]string, 0, len(children))
+	for address := range children {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	for _, address := range addresses {
+		root.ChildModules = append(root.ChildModules, *children[address])
+	}
+
+	plan := exportedPlan{
+		FormatVersion: exportedFormatVersion,
+		PlannedValues: PlannedValues{RootModule: root},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		return fmt.Errorf("failed to encode plan JSON: %w", err)
+	}
+	return nil
+}
+
+// exportPlanResource converts a resource to its planned values entry. The
+// mode and name are derived from the address.
+func exportPlanResource(resource core.Resource) PlanResource {
+	local := strings.TrimPrefix(resource.Address, resource.ModulePath+".")
+
+	mode := "managed"
+	if strings.HasPrefix(local, "data.") {
+		mode = "data"
+		local = strings.TrimPrefix(local, "data.")
+	}
+	name := strings.TrimPrefix(local, resource.Type+".")
+	// The name of a counted or for_each resource has no index
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+
+	values := resource.Properties
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	return PlanResource{
+		Address:         resource.Address,
+		Mode:            mode,
+		Type:            resource.Type,
+		Name:            name,
+		Values:          values,
+		SensitiveValues: map[string]interface{}{},
+	}
+}
@@ -0,0 +1,151 @@
+package iac
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+const exportPlanFixture = `{
+  "format_version": "1.2",
+  "terraform_version": "1.5.0",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.logs",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "logs",
+          "values": {"bucket": "logs", "tags": {"Environment": "prod"}}
+        },
+        {
+          "address": "data.aws_iam_policy_document.logs",
+          "mode": "data",
+          "type": "aws_iam_policy_document",
+          "name": "logs",
+          "values": {"json": "{}"}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.network",
+          "resources": [
+            {
+              "address": "module.network.aws_vpc.main",
+              "mode": "managed",
+              "type": "aws_vpc",
+              "name": "main",
+              "values": {"cidr_block": "10.0.0.0/16", "enable_dns_support": true}
+            }
+          ],
+          "child_modules": [
+            {
+              "address": "module.network.module.subnets",
+              "resources": [
+                {
+                  "address": "module.network.module.subnets.aws_subnet.private[0]",
+                  "mode": "managed",
+                  "type": "aws_subnet",
+                  "name": "private",
+                  "index": 0,
+                  "values": {"cidr_block": "10.0.1.0/24"}
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+// parsePlanJSON writes a plan document to a file and parses it
+func parsePlanJSON(t *testing.T, document []byte) *core.WorkloadModel {
+	t.Helper()
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(planFile, document, 0644))
+	model, err := NewAnalyzer().ParseTerraformPlan(context.Background(), planFile)
+	require.NoError(t, err)
+	return model
+}
+
+// planResourceSet keys the plan-derived fields of resources by address
+func planResourceSet(resources []core.Resource) map[string]core.Resource {
+	set := make(map[string]core.Resource, len(resources))
+	for _, r := range resources {
+		set[r.Address] = core.Resource{
+			ID:         r.ID,
+			Type:       r.Type,
+			Address:    r.Address,
+			Properties: r.Properties,
+			IsFromPlan: r.IsFromPlan,
+			ModulePath: r.ModulePath,
+		}
+	}
+	return set
+}
+
+func TestWritePlanJSON_RoundTrip(t *testing.T) {
+	analyzed := parsePlanJSON(t, []byte(exportPlanFixture))
+	require.Len(t, analyzed.Resources, 4)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePlanJSON(&buf, analyzed))
+	reparsed := parsePlanJSON(t, buf.Bytes())
+
+	assert.Equal(t, planResourceSet(analyzed.Resources), planResourceSet(reparsed.Resources))
+	assert.Equal(t, "plan", reparsed.SourceType)
+}
+
+func TestWritePlanJSON_Structure(t *testing.T) {
+	model := &core.WorkloadModel{
+		Resources: []core.Resource{
+			{Type: "aws_vpc", Address: "module.network.aws_vpc.main", ModulePath: "module.network", Properties: map[string]interface{}{"cidr_block": "10.0.0.0/16"}},
+			{Type: "aws_iam_policy_document", Address: "data.aws_iam_policy_document.logs"},
+			{Type: "aws_s3_bucket", Address: "aws_s3_bucket.logs", Properties: map[string]interface{}{"bucket": "logs"}},
+			{Type: "aws_subnet", Address: "module.network.aws_subnet.private[\"a\"]", ModulePath: "module.network"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePlanJSON(&buf, model))
+
+	var plan TerraformPlan
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &plan))
+	assert.Equal(t, "1.2", plan.FormatVersion)
+	root := plan.PlannedValues.RootModule
+	require.NotNil(t, root)
+
+	require.Len(t, root.Resources, 2)
+	assert.Equal(t, PlanResource{
+		Address:         "data.aws_iam_policy_document.logs",
+		Mode:            "data",
+		Type:            "aws_iam_policy_document",
+		Name:            "logs",
+		Values:          map[string]interface{}{},
+		SensitiveValues: map[string]interface{}{},
+	}, root.Resources[0])
+	assert.Equal(t, "managed", root.Resources[1].Mode)
+	assert.Equal(t, "logs", root.Resources[1].Name)
+
+	require.Len(t, root.ChildModules, 1)
+	assert.Equal(t, "module.network", root.ChildModules[0].Address)
+	require.Len(t, root.ChildModules[0].Resources, 2)
+	assert.Equal(t, "main", root.ChildModules[0].Resources[0].Name)
+	assert.Equal(t, "private", root.ChildModules[0].Resources[1].Name)
+	assert.Equal(t, "10.0.0.0/16", root.ChildModules[0].Resources[0].Values["cidr_block"])
+}
+
+func TestWritePlanJSON_NilModel(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePlanJSON(&buf, nil))
+	assert.JSONEq(t, `{"format_version": "1.2", "planned_values": {"root_module": {"resources": [], "child_modules": []}}}`, buf.String())
+}
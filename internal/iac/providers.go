@@ -0,0 +1,137 @@
+package iac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AdvisoryRuleUnpinnedProvider flags a provider whose version constraint has
+// no upper bound
+const AdvisoryRuleUnpinnedProvider = "unpinned-provider"
+
+// terraformSettings are the version requirements declared in the terraform
+// blocks of a configuration
+type terraformSettings struct {
+	requiredVersion string
+	providers       []core.ProviderRequirement
+	advisories      []core.Advisory
+}
+
+// readTerraformSettings reads required_version and required_providers from
+// the terraform blocks of files, visited in order, and flags providers that
+// are not pinned
+func readTerraformSettings(order []string, files map[string]*hcl.File) terraformSettings {
+	var settings terraformSettings
+	for _, path := range order {
+		content, _, _ := files[path].Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}},
+		})
+		for _, block := range content.Blocks {
+			settings.readBlock(path, block)
+		}
+	}
+	return settings
+}
+
+// readBlock adds the settings of one terraform block
+func (s *terraformSettings) readBlock(path string, block *hcl.Block) {
+	content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "required_version"}},
+		Blocks:     []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	})
+
+	if attr, ok := content.Attributes["required_version"]; ok {
+		if value, diags := attr.Expr.Value(nil); !diags.HasErrors() && value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+			s.requiredVersion = value.AsString()
+		}
+	}
+
+	for _, required := range content.Blocks {
+		attrs, diags := required.Body.JustAttributes()
+		if diags.HasErrors() {
+			continue
+		}
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			provider := providerRequirement(name, attrs[name])
+			s.providers = append(s.providers, provider)
+			if unpinnedConstraint(provider.Version) {
+				s.advisories = append(s.advisories, unpinnedProviderAdvisory(path, provider))
+			}
+		}
+	}
+}
+
+// providerRequirement reads one required_providers entry, either an object
+// with source and version or the legacy bare version string
+func providerRequirement(name string, attr *hcl.Attribute) core.ProviderRequirement {
+	provider := core.ProviderRequirement{Name: name}
+
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() {
+		return provider
+	}
+
+	switch {
+	case value.Type() == cty.String:
+		provider.Version = value.AsString()
+	case value.Type().IsObjectType():
+		provider.Source = stringAttribute(value, "source")
+		provider.Version = stringAttribute(value, "version")
+	}
+	return provider
+}
+
+// stringAttribute returns a string attribute of an object value, or an empty
+// string when it is missing or not a string
+func stringAttribute(value cty.Value, name string) string {
+	if !value.Type().HasAttribute(name) {
+		return ""
+	}
+	attr := value.GetAttr(name)
+	if attr.IsNull() || attr.Type() != cty.String {
+		return ""
+	}
+	return attr.AsString()
+}
+
+// unpinnedConstraint reports whether a version constraint lets any newer
+// version in, including a new major version: it is empty, or none of its
+// parts is an upper bound ("<", "<=", "~>") or an exact version
+func unpinnedConstraint(constraint string) bool {
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, "!="), strings.HasPrefix(part, ">"):
+		default:
+			// "<", "<=", "~>", "=" and bare versions all bound the range
+			return false
+		}
+	}
+	return true
+}
+
+// unpinnedProviderAdvisory describes a provider that is not pinned
+func unpinnedProviderAdvisory(path string, provider core.ProviderRequirement) core.Advisory {
+	message := fmt.Sprintf("provider %q has no version constraint", provider.Name)
+	if provider.Version != "" {
+		message = fmt.Sprintf("provider %q constraint %q has no upper bound", provider.Name, provider.Version)
+	}
+	return core.Advisory{
+		Rule:    AdvisoryRuleUnpinnedProvider,
+		Pillar:  core.PillarOperationalExcellence,
+		Source:  path,
+		Message: message + ", so a new major version can be installed without review",
+	}
+}
@@ -0,0 +1,92 @@
+package iac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+const providersConfig = `terraform {
+  required_version = ">= 1.5.0, < 2.0.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 5.0"
+    }
+    random = {
+      source  = "hashicorp/random"
+      version = "~> 3.6"
+    }
+    tls = {
+      source = "hashicorp/tls"
+    }
+    null = "3.2.2"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`
+
+func TestParseTerraform_RequiredProviders(t *testing.T) {
+	files := []core.IaCFile{{Path: "versions.tf", Content: providersConfig}}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+
+	require.NoError(t, err)
+	assert.Len(t, model.Resources, 1)
+	assert.Equal(t, ">= 1.5.0, < 2.0.0", model.Metadata["required_version"])
+	assert.Equal(t, []core.ProviderRequirement{
+		{Name: "aws", Source: "hashicorp/aws", Version: ">= 5.0"},
+		{Name: "null", Version: "3.2.2"},
+		{Name: "random", Source: "hashicorp/random", Version: "~> 3.6"},
+		{Name: "tls", Source: "hashicorp/tls"},
+	}, model.Metadata["required_providers"])
+
+	require.Len(t, model.Advisories, 2)
+	for _, advisory := range model.Advisories {
+		assert.Equal(t, AdvisoryRuleUnpinnedProvider, advisory.Rule)
+		assert.Equal(t, core.PillarOperationalExcellence, advisory.Pillar)
+		assert.Equal(t, "versions.tf", advisory.Source)
+	}
+	assert.Contains(t, model.Advisories[0].Message, `provider "aws" constraint ">= 5.0" has no upper bound`)
+	assert.Contains(t, model.Advisories[1].Message, `provider "tls" has no version constraint`)
+}
+
+func TestParseTerraform_NoTerraformBlock(t *testing.T) {
+	files := []core.IaCFile{{Path: "main.tf", Content: `resource "aws_s3_bucket" "logs" {}`}}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+
+	require.NoError(t, err)
+	assert.NotContains(t, model.Metadata, "required_version")
+	assert.NotContains(t, model.Metadata, "required_providers")
+	assert.Empty(t, model.Advisories)
+}
+
+func TestUnpinnedConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       bool
+	}{
+		{constraint: "", want: true},
+		{constraint: ">= 5.0", want: true},
+		{constraint: "> 4.0, != 4.5.0", want: true},
+		{constraint: "~> 5.0", want: false},
+		{constraint: ">= 5.0, < 6.0", want: false},
+		{constraint: "<= 5.10", want: false},
+		{constraint: "= 5.1.0", want: false},
+		{constraint: "5.1.0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			assert.Equal(t, tt.want, unpinnedConstraint(tt.constraint))
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package iac
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// Public exposure is found in two steps. Entry points are resources whose
+// own properties expose them to the internet, such as an internet-facing
+// load balancer. Traffic hops then carry the exposure along the resource
+// graph to the resources an entry point forwards traffic to, such as the
+// instances registered with the load balancer's target groups.
+
+// exposureDetector returns why a resource is exposed to the internet, or an
+// empty string when its properties do not expose it
+type exposureDetector func(resource core.Resource) string
+
+// exposureDetectors find entry points by resource type
+var exposureDetectors = map[string]exposureDetector{
+	"aws_lb":                              detectInternetFacingLoadBalancer,
+	"aws_alb":                             detectInternetFacingLoadBalancer,
+	"aws_elb":                             detectInternetFacingLoadBalancer,
+	"aws_instance":                        detectPublicIP,
+	"aws_subnet":                          detectPublicSubnet,
+	"aws_security_group":                  detectExposureHint,
+	"aws_security_group_rule":             detectExposureHint,
+	"aws_vpc_security_group_ingress_rule": detectExposureHint,
+	"aws_s3_bucket":                       detectExposureHint,
+	"aws_s3_bucket_acl":                   detectExposureHint,
+	"aws_db_instance":                     detectPubliclyAccessible,
+	"aws_rds_cluster_instance":            detectPubliclyAccessible,
+	"aws_eip":                             detectElasticIP,
+	"aws_lambda_function_url":             detectUnauthenticatedFunctionURL,
+	"aws_cloudfront_distribution":         detectCloudFrontDistribution,
+	"aws_apigatewayv2_api":                detectPublicAPI,
+	"aws_api_gateway_rest_api":            detectPublicAPI,
+}
+
+// exposureHintRules are the hints whose resources are exposed
+var exposureHintRules = []string{HintRulePublicS3Bucket, HintRuleOpenSecurityGroupIngress}
+
+// trafficHop carries exposure from a resource of one of the from types to
+// the resources of the to types it references or, for inbound hops, that
+// reference it
+type trafficHop struct {
+	from    []string
+	to      []string
+	inbound bool
+	// when, if set, must hold for the resource the hop reaches
+	when func(resource core.Resource) bool
+}
+
+var (
+	loadBalancerTypes = []string{"aws_lb", "aws_alb"}
+	listenerTypes     = []string{"aws_lb_listener", "aws_alb_listener"}
+	targetGroupTypes  = []string{"aws_lb_target_group", "aws_alb_target_group"}
+	// securityGroupUserTypes accept traffic through the security groups
+	// they reference
+	securityGroupUserTypes = []string{
+		"aws_instance", "aws_lb", "aws_alb", "aws_elb", "aws_network_interface",
+		"aws_launch_template", "aws_db_instance", "aws_rds_cluster", "aws_lambda_function", "aws_ecs_service",
+	}
+)
+
+// trafficHops are the ways traffic from an exposed resource moves on
+var trafficHops = []trafficHop{
+	{from: loadBalancerTypes, to: listenerTypes, inbound: true},
+	{from: listenerTypes, to: targetGroupTypes},
+	{from: listenerTypes, to: []string{"aws_lb_listener_rule", "aws_alb_listener_rule"}, inbound: true},
+	{from: []string{"aws_lb_listener_rule", "aws_alb_listener_rule"}, to: targetGroupTypes},
+	{from: targetGroupTypes, to: []string{"aws_lb_target_group_attachment", "aws_alb_target_group_attachment", "aws_autoscaling_attachment", "aws_autoscaling_group", "aws_ecs_service"}, inbound: true},
+	{from: []string{"aws_lb_target_group_attachment", "aws_alb_target_group_attachment"}, to: []string{"aws_instance", "aws_lambda_function"}},
+	{from: []string{"aws_elb"}, to: []string{"aws_instance"}},
+	{from: []string{"aws_elb"}, to: []string{"aws_autoscaling_attachment", "aws_autoscaling_group"}, inbound: true},
+	{from: []string{"aws_autoscaling_attachment"}, to: []string{"aws_autoscaling_group"}},
+	{from: []string{"aws_eip"}, to: []string{"aws_instance", "aws_network_interface"}},
+	{from: []string{"aws_eip"}, to: []string{"aws_eip_association"}, inbound: true},
+	{from: []string{"aws_eip_association"}, to: []string{"aws_instance", "aws_network_interface"}},
+	{from: []string{"aws_s3_bucket_acl"}, to: []string{"aws_s3_bucket"}},
+	{from: []string{"aws_lambda_function_url"}, to: []string{"aws_lambda_function"}},
+	{from: []string{"aws_apigatewayv2_api"}, to: []string{"aws_apigatewayv2_integration"}, inbound: true},
+	{from: []string{"aws_apigatewayv2_integration"}, to: []string{"aws_lambda_function"}},
+	{from: []string{"aws_api_gateway_rest_api"}, to: []string{"aws_api_gateway_integration"}, inbound: true},
+	{from: []string{"aws_api_gateway_integration"}, to: []string{"aws_lambda_function"}},
+	{from: []string{"aws_subnet"}, to: []string{"aws_instance"}, inbound: true, when: func(resource core.Resource) bool {
+		return resource.Properties["associate_public_ip_address"] != false
+	}},
+	{from: []string{"aws_subnet"}, to: []string{"aws_autoscaling_group"}, inbound: true},
+	{from: []string{"aws_security_group_rule", "aws_vpc_security_group_ingress_rule"}, to: []string{"aws_security_group"}},
+	{from: []string{"aws_security_group"}, to: securityGroupUserTypes, inbound: true, when: func(resource core.Resource) bool {
+		// Internal load balancers are not reachable from the internet
+		return resource.Properties["internal"] != true
+	}},
+	{from: []string{"aws_launch_template"}, to: []string{"aws_autoscaling_group"}, inbound: true},
+}
+
+// exposureConnectors only wire traffic to other resources. They carry
+// exposure but are not reported as exposed themselves.
+var exposureConnectors = map[string]bool{
+	"aws_lb_listener":                 true,
+	"aws_alb_listener":                true,
+	"aws_lb_listener_rule":            true,
+	"aws_alb_listener_rule":           true,
+	"aws_lb_target_group":             true,
+	"aws_alb_target_group":            true,
+	"aws_lb_target_group_attachment":  true,
+	"aws_alb_target_group_attachment": true,
+	"aws_autoscaling_attachment":      true,
+	"aws_eip":                         true,
+	"aws_eip_association":             true,
+	"aws_s3_bucket_acl":               true,
+	"aws_lambda_function_url":         true,
+	"aws_apigatewayv2_integration":    true,
+	"aws_api_gateway_integration":     true,
+	"aws_launch_template":             true,
+}
+
+// DetectPublicExposure returns the resources reachable from the public
+// internet: the entry points exposed by their own properties, sorted by
+// address, followed by the resources they forward traffic to, sorted by
+// address. graph may be nil, in which case only entry points are found.
+func (a *Analyzer) DetectPublicExposure(resources []core.Resource, graph *core.ResourceGraph) []core.ExposedResource {
+	byAddress := make(map[string]core.Resource, len(resources))
+	for _, resource := range resources {
+		byAddress[resource.Address] = resource
+	}
+	var edges map[string][]string
+	if graph != nil {
+		edges = graph.Edges
+	}
+	dependents := make(map[string][]string)
+	for from, targets := range edges {
+		for _, to := range targets {
+			dependents[to] = append(dependents[to], from)
+		}
+	}
+
+	type reached struct {
+		address string
+		path    []string
+		// entry is the entry point the resource is reached from
+		entry string
+	}
+	reasons := make(map[string]string)
+	var queue []reached
+	for _, resource := range resources {
+		detect, ok := exposureDetectors[resource.Type]
+		if !ok || resource.IsDataSource() {
+			continue
+		}
+		if reason := detect(resource); reason != "" {
+			reasons[resource.Address] = reason
+			queue = append(queue, reached{address: resource.Address, entry: resource.Address})
+		}
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].address < queue[j].address })
+
+	var direct, indirect []core.ExposedResource
+	visited := make(map[string]bool, len(queue))
+	for _, r := range queue {
+		visited[r.address] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		resource := byAddress[current.address]
+
+		if !exposureConnectors[resource.Type] {
+			exposed := core.ExposedResource{Address: resource.Address, Type: resource.Type, Reason: reasons[current.address]}
+			if len(current.path) == 0 {
+				direct = append(direct, exposed)
+			} else {
+				exposed.Path = current.path
+				exposed.Reason = fmt.Sprintf("reachable through %s (%s)", current.entry, reasons[current.entry])
+				indirect = append(indirect, exposed)
+			}
+		}
+
+		path := append(slices.Clone(current.path), current.address)
+		for _, hop := range trafficHops {
+			if !slices.Contains(hop.from, resource.Type) {
+				continue
+			}
+			next := edges[current.address]
+			if hop.inbound {
+				next = dependents[current.address]
+			}
+			for _, address := range next {
+				target, ok := byAddress[address]
+				if !ok || visited[address] || !slices.Contains(hop.to, target.Type) || (hop.when != nil && !hop.when(target)) {
+					continue
+				}
+				visited[address] = true
+				queue = append(queue, reached{address: address, path: path, entry: current.entry})
+			}
+		}
+	}
+
+	sort.Slice(indirect, func(i, j int) bool { return indirect[i].Address < indirect[j].Address })
+	return append(direct, indirect...)
+}
+
+// detectInternetFacingLoadBalancer flags load balancers that are not
+// internal, which is the default
+func detectInternetFacingLoadBalancer(resource core.Resource) string {
+	if resource.Properties["internal"] == true {
+		return ""
+	}
+	return "internet-facing load balancer"
+}
+
+// detectPublicIP flags instances given a public IP address
+func detectPublicIP(resource core.Resource) string {
+	if resource.Properties["associate_public_ip_address"] != true {
+		return ""
+	}
+	return "instance has a public IP address"
+}
+
+// detectPublicSubnet flags subnets that give instances public IP addresses
+func detectPublicSubnet(resource core.Resource) string {
+	if resource.Properties["map_public_ip_on_launch"] != true {
+		return ""
+	}
+	return "public subnet assigns public IP addresses on launch"
+}
+
+// detectExposureHint flags resources with a public S3 ACL or an ingress rule
+// open to the internet, as reported by their hints
+func detectExposureHint(resource core.Resource) string {
+	for _, hint := range resource.Hints {
+		if slices.Contains(exposureHintRules, hint.Rule) {
+			return hint.Message
+		}
+	}
+	return ""
+}
+
+// detectPubliclyAccessible flags databases with a public endpoint
+func detectPubliclyAccessible(resource core.Resource) string {
+	if resource.Properties["publicly_accessible"] != true {
+		return ""
+	}
+	return "database is publicly accessible"
+}
+
+// detectElasticIP flags Elastic IP addresses, which are reachable from the
+// internet by definition
+func detectElasticIP(resource core.Resource) string {
+	return "Elastic IP address"
+}
+
+// detectUnauthenticatedFunctionURL flags Lambda function URLs anyone can call
+func detectUnauthenticatedFunctionURL(resource core.Resource) string {
+	if resource.Properties["authorization_type"] != "NONE" {
+		return ""
+	}
+	return "function URL without authorization"
+}
+
+// detectCloudFrontDistribution flags CloudFront distributions, which serve
+// the internet by definition
+func detectCloudFrontDistribution(resource core.Resource) string {
+	return "CloudFront distribution"
+}
+
+// detectPublicAPI flags API Gateway APIs that are not private
+func detectPublicAPI(resource core.Resource) string {
+	for _, configuration := range propertyObjects(resource.Properties["endpoint_configuration"]) {
+		if slices.Contains(propertyStrings(configuration["types"]), "PRIVATE") {
+			return ""
+		}
+	}
+	return "public API endpoint"
+}
@@ -0,0 +1,209 @@
+package iac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestDetectPublicExposure_LoadBalancerToInstance(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+resource "aws_lb" "web" {
+  name     = "web"
+  internal = false
+}
+
+resource "aws_lb_listener" "http" {
+  load_balancer_arn = aws_lb.web.arn
+  port              = 80
+
+  default_action {
+    type             = "forward"
+    target_group_arn = aws_lb_target_group.web.arn
+  }
+}
+
+resource "aws_lb_target_group" "web" {
+  name = "web"
+  port = 80
+}
+
+resource "aws_lb_target_group_attachment" "web" {
+  target_group_arn = aws_lb_target_group.web.arn
+  target_id        = aws_instance.web.id
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-12345678"
+  instance_type = "t3.micro"
+}
+
+resource "aws_instance" "batch" {
+  ami           = "ami-12345678"
+  instance_type = "t3.micro"
+}
+
+resource "aws_lb" "internal" {
+  name     = "internal"
+  internal = true
+}
+`), 0644))
+
+	ctx := context.Background()
+	analyzer := NewAnalyzerWithDir(dir)
+	files, err := analyzer.RetrieveIaCFiles(ctx)
+	require.NoError(t, err)
+	model, err := analyzer.ParseTerraform(ctx, files)
+	require.NoError(t, err)
+	resources, err := analyzer.ExtractResources(ctx, model)
+	require.NoError(t, err)
+	graph, err := analyzer.IdentifyRelationships(ctx, resources)
+	require.NoError(t, err)
+
+	exposed := analyzer.DetectPublicExposure(resources, graph)
+
+	require.Len(t, exposed, 2, "listeners, target groups and attachments are not reported")
+	assert.Equal(t, core.ExposedResource{Address: "aws_lb.web", Type: "aws_lb", Reason: "internet-facing load balancer"}, exposed[0])
+	assert.Equal(t, "aws_instance.web", exposed[1].Address)
+	assert.Equal(t, "reachable through aws_lb.web (internet-facing load balancer)", exposed[1].Reason)
+	assert.Equal(t, []string{"aws_lb.web", "aws_lb_listener.http", "aws_lb_target_group.web", "aws_lb_target_group_attachment.web"}, exposed[1].Path)
+}
+
+func TestDetectPublicExposure_SecurityGroupUsers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+resource "aws_security_group" "web" {
+  name = "web"
+
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_security_group" "ssh" {
+  name = "ssh"
+}
+
+resource "aws_vpc_security_group_ingress_rule" "ssh" {
+  security_group_id = aws_security_group.ssh.id
+  from_port         = 22
+  to_port           = 22
+  ip_protocol       = "tcp"
+  cidr_ipv4         = "0.0.0.0/0"
+}
+
+resource "aws_instance" "web" {
+  ami                    = "ami-12345678"
+  instance_type          = "t3.micro"
+  vpc_security_group_ids = [aws_security_group.web.id]
+}
+
+resource "aws_network_interface" "bastion" {
+  subnet_id       = "subnet-12345678"
+  security_groups = [aws_security_group.ssh.id]
+}
+
+resource "aws_lb" "internal" {
+  name            = "internal"
+  internal        = true
+  security_groups = [aws_security_group.web.id]
+}
+
+resource "aws_instance" "batch" {
+  ami           = "ami-12345678"
+  instance_type = "t3.micro"
+}
+`), 0644))
+
+	ctx := context.Background()
+	analyzer := NewAnalyzerWithDir(dir)
+	files, err := analyzer.RetrieveIaCFiles(ctx)
+	require.NoError(t, err)
+	model, err := analyzer.ParseTerraform(ctx, files)
+	require.NoError(t, err)
+	resources, err := analyzer.ExtractResources(ctx, model)
+	require.NoError(t, err)
+	graph, err := analyzer.IdentifyRelationships(ctx, resources)
+	require.NoError(t, err)
+
+	exposed := analyzer.DetectPublicExposure(resources, graph)
+
+	addresses := make(map[string]core.ExposedResource, len(exposed))
+	for _, resource := range exposed {
+		addresses[resource.Address] = resource
+	}
+	assert.Contains(t, addresses, "aws_security_group.web")
+	assert.Contains(t, addresses, "aws_vpc_security_group_ingress_rule.ssh")
+	assert.NotContains(t, addresses, "aws_lb.internal", "internal load balancers are not reachable")
+	assert.NotContains(t, addresses, "aws_instance.batch")
+
+	require.Contains(t, addresses, "aws_instance.web")
+	assert.Equal(t, []string{"aws_security_group.web"}, addresses["aws_instance.web"].Path)
+	require.Contains(t, addresses, "aws_network_interface.bastion")
+	assert.Equal(t, []string{"aws_vpc_security_group_ingress_rule.ssh", "aws_security_group.ssh"}, addresses["aws_network_interface.bastion"].Path)
+}
+
+func TestDetectPublicExposure_EntryPoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		resource  core.Resource
+		wantFound bool
+	}{
+		{
+			name:      "public subnet",
+			resource:  core.Resource{Address: "aws_subnet.public", Type: "aws_subnet", Properties: map[string]interface{}{"map_public_ip_on_launch": true}},
+			wantFound: true,
+		},
+		{
+			name:     "private subnet",
+			resource: core.Resource{Address: "aws_subnet.private", Type: "aws_subnet", Properties: map[string]interface{}{}},
+		},
+		{
+			name: "open security group",
+			resource: core.Resource{Address: "aws_security_group.web", Type: "aws_security_group", Hints: []core.ResourceHint{
+				{Rule: HintRuleOpenSecurityGroupIngress, Message: "ingress open to 0.0.0.0/0"},
+			}},
+			wantFound: true,
+		},
+		{
+			name:      "publicly accessible database",
+			resource:  core.Resource{Address: "aws_db_instance.main", Type: "aws_db_instance", Properties: map[string]interface{}{"publicly_accessible": true}},
+			wantFound: true,
+		},
+		{
+			name: "private API",
+			resource: core.Resource{Address: "aws_api_gateway_rest_api.internal", Type: "aws_api_gateway_rest_api", Properties: map[string]interface{}{
+				"endpoint_configuration": []interface{}{map[string]interface{}{"types": []interface{}{"PRIVATE"}}},
+			}},
+		},
+		{
+			name:     "data source",
+			resource: core.Resource{Address: "data.aws_lb.shared", Type: "aws_lb", Properties: map[string]interface{}{}},
+		},
+	}
+
+	analyzer := NewAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exposed := analyzer.DetectPublicExposure([]core.Resource{tt.resource}, nil)
+
+			if !tt.wantFound {
+				assert.Empty(t, exposed)
+				return
+			}
+			require.Len(t, exposed, 1)
+			assert.Equal(t, tt.resource.Address, exposed[0].Address)
+			assert.NotEmpty(t, exposed[0].Reason)
+			assert.Empty(t, exposed[0].Path)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package iac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TFCStateScheme prefixes references to the current state of a Terraform
+// Cloud workspace, e.g. tfc://acme/payments-prod
+const TFCStateScheme = "tfc://"
+
+// DefaultTFCHostname is the Terraform Cloud API host
+const DefaultTFCHostname = "app.terraform.io"
+
+// tfcNamePattern matches Terraform Cloud organization and workspace names
+var tfcNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ErrStateFetcherNotConfigured is returned for workspace references when the
+// analyzer has no StateFetcher
+var ErrStateFetcherNotConfigured = errors.New("no Terraform Cloud client configured to fetch workspace state")
+
+// StateReference identifies the Terraform Cloud workspace whose current
+// state is reviewed
+type StateReference struct {
+	Organization string
+	Workspace    string
+}
+
+func (r StateReference) String() string {
+	return TFCStateScheme + r.Organization + "/" + r.Workspace
+}
+
+// ParseStateReference parses a tfc://<organization>/<workspace> reference.
+// ok is false when source is not a reference, such as a file path.
+func ParseStateReference(source string) (ref StateReference, ok bool, err error) {
+	rest, found := strings.CutPrefix(source, TFCStateScheme)
+	if !found {
+		return StateReference{}, false, nil
+	}
+
+	organization, workspace, _ := strings.Cut(rest, "/")
+	if !tfcNamePattern.MatchString(organization) || !tfcNamePattern.MatchString(workspace) {
+		return StateReference{}, true, fmt.Errorf("invalid state reference %q: expected %s<organization>/<workspace>", source, TFCStateScheme)
+	}
+	return StateReference{Organization: organization, Workspace: workspace}, true, nil
+}
+
+// StateFetcher downloads the current state of a workspace in the format of
+// `terraform show -json`
+type StateFetcher interface {
+	FetchState(ctx context.Context, ref StateReference) ([]byte, error)
+}
+
+// TFCClient fetches workspace state from the Terraform Cloud or Terraform
+// Enterprise API
+type TFCClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewTFCClient creates a client for the API at hostname. An empty hostname
+// uses Terraform Cloud.
+func NewTFCClient(hostname, token string) *TFCClient {
+	if hostname == "" {
+		hostname = DefaultTFCHostname
+	}
+	return &TFCClient{
+		baseURL:    "https://" + hostname,
+		token:      token,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// FetchState looks up the workspace, then downloads the JSON form of its
+// current state version. The JSON form is only recorded for states written
+// by Terraform 1.3 or later.
+func (c *TFCClient) FetchState(ctx context.Context, ref StateReference) ([]byte, error) {
+	if c.token == "" {
+		return nil, fmt.Errorf("fetching %s: no Terraform Cloud API token configured", ref)
+	}
+
+	var workspace struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	workspacePath := fmt.Sprintf("/api/v2/organizations/%s/workspaces/%s", url.PathEscape(ref.Organization), url.PathEscape(ref.Workspace))
+	if err := c.getJSON(ctx, c.baseURL+workspacePath, &workspace); err != nil {
+		return nil, fmt.Errorf("fetching workspace %s: %w", ref, err)
+	}
+
+	var stateVersion struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				HostedJSONStateDownloadURL string `json:"hosted-json-state-download-url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	stateVersionPath := fmt.Sprintf("/api/v2/workspaces/%s/current-state-version", url.PathEscape(workspace.Data.ID))
+	if err := c.getJSON(ctx, c.baseURL+stateVersionPath, &stateVersion); err != nil {
+		return nil, fmt.Errorf("fetching current state version of %s: %w", ref, err)
+	}
+
+	downloadURL := stateVersion.Data.Attributes.HostedJSONStateDownloadURL
+	if downloadURL == "" {
+		return nil, fmt.Errorf("state version %s of %s has no JSON state; it must be written by Terraform 1.3 or later", stateVersion.Data.ID, ref)
+	}
+
+	state, err := c.get(ctx, downloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("downloading state of %s: %w", ref, err)
+	}
+
+	slog.InfoContext(ctx, "fetched workspace state",
+		"workspace", ref.String(),
+		"state_version", stateVersion.Data.ID,
+		"bytes", len(state),
+	)
+	return state, nil
+}
+
+// getJSON fetches an API document into v
+func (c *TFCClient) getJSON(ctx context.Context, target string, v interface{}) error {
+	body, err := c.get(ctx, target)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid API response: %w", err)
+	}
+	return nil
+}
+
+// get fetches target. The API token is only sent to the API host, not to
+// storage hosts a download URL may point at.
+func (c *TFCClient) get(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(target, c.baseURL+"/") {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return body, nil
+}
@@ -0,0 +1,258 @@
+package iac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workspaceStateJSON is `terraform show -json` output for a state, which
+// holds resources under values rather than planned_values
+const workspaceStateJSON = `{
+  "format_version": "1.0",
+  "terraform_version": "1.9.5",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_db_instance.main",
+          "mode": "managed",
+          "type": "aws_db_instance",
+          "name": "main",
+          "values": {"engine": "postgres", "password": "hunter2-hunter2"},
+          "sensitive_values": {"password": true}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.network",
+          "resources": [
+            {
+              "address": "module.network.aws_vpc.main",
+              "mode": "managed",
+              "type": "aws_vpc",
+              "name": "main",
+              "values": {"cidr_block": "10.0.0.0/16"}
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+func TestParseStateReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    StateReference
+		wantRef bool
+		wantErr bool
+	}{
+		{name: "workspace reference", source: "tfc://acme/payments-prod", want: StateReference{Organization: "acme", Workspace: "payments-prod"}, wantRef: true},
+		{name: "underscores", source: "tfc://acme_corp/payments_prod", want: StateReference{Organization: "acme_corp", Workspace: "payments_prod"}, wantRef: true},
+		{name: "file path", source: "state.json"},
+		{name: "absolute file path", source: "/tmp/tfc/state.json"},
+		{name: "missing workspace", source: "tfc://acme", wantRef: true, wantErr: true},
+		{name: "empty organization", source: "tfc:///payments", wantRef: true, wantErr: true},
+		{name: "extra path segment", source: "tfc://acme/payments/prod", wantRef: true, wantErr: true},
+		{name: "invalid characters", source: "tfc://acme/pay ments", wantRef: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, isRef, err := ParseStateReference(tt.source)
+			assert.Equal(t, tt.wantRef, isRef)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "tfc://<organization>/<workspace>")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ref)
+			if isRef {
+				assert.Equal(t, tt.source, ref.String())
+			}
+		})
+	}
+}
+
+// stubStateFetcher returns a fixed state and records the references fetched
+type stubStateFetcher struct {
+	state   string
+	err     error
+	fetched []StateReference
+}
+
+func (s *stubStateFetcher) FetchState(ctx context.Context, ref StateReference) ([]byte, error) {
+	s.fetched = append(s.fetched, ref)
+	return []byte(s.state), s.err
+}
+
+func TestParseTerraformPlan_StateSources(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte(workspaceStateJSON), 0644))
+
+	tests := []struct {
+		name    string
+		source  string
+		fetcher *stubStateFetcher
+	}{
+		{name: "state file", source: stateFile},
+		{name: "workspace reference", source: "tfc://acme/payments-prod", fetcher: &stubStateFetcher{state: workspaceStateJSON}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewAnalyzer()
+			if tt.fetcher != nil {
+				analyzer.SetStateFetcher(tt.fetcher)
+			}
+
+			model, err := analyzer.ParseTerraformPlan(context.Background(), tt.source)
+			require.NoError(t, err)
+
+			addresses := make(map[string]map[string]interface{})
+			for _, resource := range model.Resources {
+				addresses[resource.Address] = resource.Properties
+			}
+			require.Contains(t, addresses, "aws_db_instance.main")
+			require.Contains(t, addresses, "module.network.aws_vpc.main")
+			assert.NotEqual(t, "hunter2-hunter2", addresses["aws_db_instance.main"]["password"], "sensitive state values are redacted")
+			assert.Equal(t, "state", model.Metadata["json_kind"])
+			assert.Equal(t, tt.source, model.Metadata["json_file"])
+
+			if tt.fetcher != nil {
+				assert.Equal(t, []StateReference{{Organization: "acme", Workspace: "payments-prod"}}, tt.fetcher.fetched)
+			}
+		})
+	}
+}
+
+func TestParseTerraformPlan_StateReferenceErrors(t *testing.T) {
+	t.Run("no fetcher", func(t *testing.T) {
+		_, err := NewAnalyzer().ParseTerraformPlan(context.Background(), "tfc://acme/payments-prod")
+		assert.ErrorIs(t, err, ErrStateFetcherNotConfigured)
+	})
+
+	t.Run("fetch fails", func(t *testing.T) {
+		analyzer := NewAnalyzer()
+		analyzer.SetStateFetcher(&stubStateFetcher{err: errors.New("unexpected status 404 Not Found")})
+
+		_, err := analyzer.ParseTerraformPlan(context.Background(), "tfc://acme/payments-prod")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		analyzer := NewAnalyzer()
+		fetcher := &stubStateFetcher{}
+		analyzer.SetStateFetcher(fetcher)
+
+		_, err := analyzer.ParseTerraformPlan(context.Background(), "tfc://acme")
+		require.Error(t, err)
+		assert.Empty(t, fetcher.fetched)
+	})
+}
+
+// newTFCServer serves the workspace and state version endpoints of the
+// Terraform Cloud API. The state is downloaded from a separate storage server.
+func newTFCServer(t *testing.T, downloadURL func(storage string) string) (*TFCClient, *[]string) {
+	t.Helper()
+	var storageAuth []string
+
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storageAuth = append(storageAuth, r.Header.Get("Authorization"))
+		fmt.Fprint(w, workspaceStateJSON)
+	}))
+	t.Cleanup(storage.Close)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/organizations/acme/workspaces/payments-prod":
+			fmt.Fprint(w, `{"data": {"id": "ws-123"}}`)
+		case "/api/v2/workspaces/ws-123/current-state-version":
+			fmt.Fprintf(w, `{"data": {"id": "sv-456", "attributes": {"hosted-json-state-download-url": %q}}}`, downloadURL(storage.URL))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.Close)
+
+	return &TFCClient{baseURL: api.URL, token: "secret-token", httpClient: api.Client()}, &storageAuth
+}
+
+func TestTFCClient_FetchState(t *testing.T) {
+	client, storageAuth := newTFCServer(t, func(storage string) string {
+		return storage + "/state-versions/sv-456/hosted_json_state"
+	})
+
+	state, err := client.FetchState(context.Background(), StateReference{Organization: "acme", Workspace: "payments-prod"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, workspaceStateJSON, string(state))
+	assert.Equal(t, []string{""}, *storageAuth, "the API token is not sent to the storage host")
+}
+
+func TestTFCClient_FetchStateErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		ref         StateReference
+		token       string
+		downloadURL string
+		wantMsg     string
+	}{
+		{
+			name:    "missing token",
+			ref:     StateReference{Organization: "acme", Workspace: "payments-prod"},
+			wantMsg: "no Terraform Cloud API token configured",
+		},
+		{
+			name:    "unknown workspace",
+			ref:     StateReference{Organization: "acme", Workspace: "missing"},
+			token:   "secret-token",
+			wantMsg: "fetching workspace tfc://acme/missing: unexpected status 404",
+		},
+		{
+			name:    "rejected token",
+			ref:     StateReference{Organization: "acme", Workspace: "payments-prod"},
+			token:   "wrong-token",
+			wantMsg: "unexpected status 401",
+		},
+		{
+			name:    "state written by an old Terraform",
+			ref:     StateReference{Organization: "acme", Workspace: "payments-prod"},
+			token:   "secret-token",
+			wantMsg: "state version sv-456 of tfc://acme/payments-prod has no JSON state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTFCServer(t, func(string) string { return tt.downloadURL })
+			client.token = tt.token
+
+			_, err := client.FetchState(context.Background(), tt.ref)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestNewTFCClient(t *testing.T) {
+	assert.Equal(t, "https://app.terraform.io", NewTFCClient("", "token").baseURL)
+	assert.Equal(t, "https://tfe.example.com", NewTFCClient("tfe.example.com", "token").baseURL)
+}
@@ -0,0 +1,151 @@
+package iac
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// variableContexts returns the evaluation context for var.* references in
+// each root directory of the parsed files. Values are layered the way
+// Terraform loads them: variable defaults, then terraform.tfvars, then
+// terraform.tfvars.json and finally *.auto.tfvars and *.auto.tfvars.json in
+// lexical order, so later files win. Other variable definitions files are
+// only loaded with -var-file, so their values are not used. Variables
+// declared sensitive are never resolved.
+// Directories a local module call points at get no context, as their
+// variables are set by the caller.
+func variableContexts(ctx context.Context, files map[string]*hcl.File) map[string]*hcl.EvalContext {
+	byDir := make(map[string][]string)
+	for path := range files {
+		dir := filepath.Dir(path)
+		byDir[dir] = append(byDir[dir], path)
+	}
+	called := calledModuleDirs(files)
+
+	contexts := make(map[string]*hcl.EvalContext)
+	for dir, paths := range byDir {
+		if called[dir] {
+			continue
+		}
+		sort.Slice(paths, func(i, j int) bool {
+			if ri, rj := tfvarsRank(paths[i]), tfvarsRank(paths[j]); ri != rj {
+				return ri < rj
+			}
+			return paths[i] < paths[j]
+		})
+
+		values := make(map[string]cty.Value)
+		sensitive := make(map[string]bool)
+		for _, path := range paths {
+			switch tfvarsRank(path) {
+			case 0:
+				collectVariableDefaults(values, sensitive, files[path])
+			case tfvarsNotLoaded:
+				slog.DebugContext(ctx, "skipping variable definitions file that Terraform does not load automatically", "file", path)
+			default:
+				collectTfvars(ctx, values, files[path], path)
+			}
+		}
+		for name := range sensitive {
+			delete(values, name)
+		}
+		if len(values) > 0 {
+			contexts[dir] = &hcl.EvalContext{
+				Variables: map[string]cty.Value{"var": cty.ObjectVal(values)},
+			}
+		}
+	}
+	return contexts
+}
+
+// tfvarsNotLoaded is the rank of variable definitions files Terraform does
+// not load automatically
+const tfvarsNotLoaded = 4
+
+// tfvarsRank orders files by Terraform's variable precedence. Configuration
+// files rank 0.
+func tfvarsRank(path string) int {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case !strings.HasSuffix(name, ".tfvars") && !strings.HasSuffix(name, ".tfvars.json"):
+		return 0
+	case name == "terraform.tfvars":
+		return 1
+	case name == "terraform.tfvars.json":
+		return 2
+	case strings.HasSuffix(name, ".auto.tfvars") || strings.HasSuffix(name, ".auto.tfvars.json"):
+		return 3
+	default:
+		return tfvarsNotLoaded
+	}
+}
+
+// collectVariableDefaults records the default of each variable block that
+// has one that can be evaluated statically, and the variables marked
+// sensitive
+func collectVariableDefaults(values map[string]cty.Value, sensitive map[string]bool, file *hcl.File) {
+	content, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "variable", LabelNames: []string{"name"}},
+		},
+	})
+	if content == nil {
+		return
+	}
+
+	for _, block := range content.Blocks {
+		attrs, _ := block.Body.JustAttributes()
+		if attr, ok := attrs["sensitive"]; ok {
+			if value, diags := attr.Expr.Value(nil); !diags.HasErrors() && value.Type() == cty.Bool && value.True() {
+				sensitive[block.Labels[0]] = true
+			}
+		}
+		attr, ok := attrs["default"]
+		if !ok {
+			continue
+		}
+		if value, diags := attr.Expr.Value(nil); !diags.HasErrors() {
+			values[block.Labels[0]] = value
+		}
+	}
+}
+
+// collectTfvars records the assignments of a variable definitions file,
+// replacing any value set before it
+func collectTfvars(ctx context.Context, values map[string]cty.Value, file *hcl.File, path string) {
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		slog.DebugContext(ctx, "partial variable definitions", "file", path, "errors", diags.Error())
+	}
+
+	for name, attr := range attrs {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			slog.DebugContext(ctx, "skipping variable that cannot be evaluated", "file", path, "variable", name)
+			continue
+		}
+		values[name] = value
+	}
+}
+
+// calledModuleDirs returns the directories local module calls in the parsed
+// files point at
+func calledModuleDirs(files map[string]*hcl.File) map[string]bool {
+	called := make(map[string]bool)
+	for path, file := range files {
+		dir := &moduleDir{refs: make(map[string][]hcl.Traversal), outputs: make(map[string][]hcl.Traversal)}
+		collectModuleDeclarations(dir, file)
+		for _, call := range dir.calls {
+			if isLocalModuleSource(call.source) {
+				called[filepath.Join(filepath.Dir(path), call.source)] = true
+			}
+		}
+	}
+	return called
+}
@@ -0,0 +1,159 @@
+package iac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+const variablesConfig = `variable "env" {
+  type    = string
+  default = "dev"
+}
+
+variable "instance_type" {
+  type = string
+}
+
+variable "db_username" {
+  type      = string
+  sensitive = true
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "${var.env}-logs"
+}
+
+resource "aws_instance" "web" {
+  instance_type = var.instance_type
+  ami           = "ami-12345"
+
+  root_block_device {
+    encrypted = var.encrypt_volumes
+  }
+}
+
+resource "aws_db_instance" "main" {
+  username = var.db_username
+}
+`
+
+func TestParseTerraform_Variables(t *testing.T) {
+	tests := []struct {
+		name             string
+		tfvars           map[string]string
+		wantBucket       interface{}
+		wantInstanceType interface{}
+		wantEncrypted    interface{}
+	}{
+		{
+			name:             "defaults only",
+			wantBucket:       "dev-logs",
+			wantInstanceType: "${var.instance_type}",
+			wantEncrypted:    "${var.encrypt_volumes}",
+		},
+		{
+			name: "terraform.tfvars over defaults",
+			tfvars: map[string]string{
+				"terraform.tfvars": "env = \"prod\"\ninstance_type = \"m5.large\"\nencrypt_volumes = true\n",
+			},
+			wantBucket:       "prod-logs",
+			wantInstanceType: "m5.large",
+			wantEncrypted:    true,
+		},
+		{
+			name: "auto.tfvars in lexical order",
+			tfvars: map[string]string{
+				"terraform.tfvars": "env = \"prod\"\ninstance_type = \"m5.large\"\n",
+				"b.auto.tfvars":    "env = \"staging\"\n",
+				"a.auto.tfvars":    "env = \"qa\"\ninstance_type = \"t3.micro\"\n",
+			},
+			wantBucket:       "staging-logs",
+			wantInstanceType: "t3.micro",
+			wantEncrypted:    "${var.encrypt_volumes}",
+		},
+		{
+			name: "JSON variable definitions",
+			tfvars: map[string]string{
+				"terraform.tfvars":      "env = \"prod\"\ninstance_type = \"m5.large\"\n",
+				"terraform.tfvars.json": `{"env": "qa", "encrypt_volumes": true}`,
+				"a.auto.tfvars.json":    `{"instance_type": "t3.micro"}`,
+				"b.auto.tfvars":         "env = \"staging\"\n",
+			},
+			wantBucket:       "staging-logs",
+			wantInstanceType: "t3.micro",
+			wantEncrypted:    true,
+		},
+		{
+			// Only -var-file loads these
+			name: "other tfvars are not loaded",
+			tfvars: map[string]string{
+				"terraform.tfvars": "env = \"prod\"\ninstance_type = \"m5.large\"\n",
+				"override.tfvars":  "instance_type = \"c5.xlarge\"\n",
+				"prod.tfvars.json": `{"env": "staging"}`,
+			},
+			wantBucket:       "prod-logs",
+			wantInstanceType: "m5.large",
+			wantEncrypted:    "${var.encrypt_volumes}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := []core.IaCFile{{Path: "main.tf", Content: variablesConfig}}
+			for path, content := range tt.tfvars {
+				files = append(files, core.IaCFile{Path: path, Content: content})
+			}
+
+			model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+			require.NoError(t, err)
+
+			resources := make(map[string]core.Resource)
+			for _, resource := range model.Resources {
+				resources[resource.Address] = resource
+			}
+			assert.Equal(t, tt.wantBucket, resources["aws_s3_bucket.logs"].Properties["bucket"])
+			assert.Equal(t, tt.wantInstanceType, resources["aws_instance.web"].Properties["instance_type"])
+			rootBlockDevice := resources["aws_instance.web"].Properties["root_block_device"].(map[string]interface{})
+			assert.Equal(t, tt.wantEncrypted, rootBlockDevice["encrypted"])
+		})
+	}
+}
+
+func TestParseTerraform_SensitiveVariableNotResolved(t *testing.T) {
+	files := []core.IaCFile{
+		{Path: "main.tf", Content: variablesConfig},
+		{Path: "terraform.tfvars", Content: "db_username = \"admin\"\n"},
+	}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+
+	for _, resource := range model.Resources {
+		if resource.Address == "aws_db_instance.main" {
+			assert.Equal(t, "${var.db_username}", resource.Properties["username"])
+			return
+		}
+	}
+	t.Fatal("aws_db_instance.main not found")
+}
+
+func TestParseTerraform_ModuleVariablesNotResolved(t *testing.T) {
+	files := []core.IaCFile{
+		{Path: "main.tf", Content: "module \"vpc\" {\n  source     = \"./modules/vpc\"\n  cidr_block = \"10.0.0.0/16\"\n}\n"},
+		{Path: "terraform.tfvars", Content: "cidr_block = \"192.168.0.0/16\"\n"},
+		{Path: "modules/vpc/main.tf", Content: "variable \"cidr_block\" {\n  default = \"172.16.0.0/16\"\n}\n\nresource \"aws_vpc\" \"main\" {\n  cidr_block = var.cidr_block\n}\n"},
+	}
+
+	model, err := NewAnalyzer().ParseTerraform(context.Background(), files)
+	require.NoError(t, err)
+
+	// The caller sets module variables, so neither the module default nor
+	// the root tfvars apply
+	require.Len(t, model.Resources, 1)
+	assert.Equal(t, "module.vpc.aws_vpc.main", model.Resources[0].Address)
+	assert.Equal(t, "${var.cidr_block}", model.Resources[0].Properties["cidr_block"])
+}
@@ -0,0 +1,168 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GitHubClient creates issues in a GitHub repository through the REST API
+type GitHubClient struct {
+	apiURL     string
+	repository string
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitHubClient creates a client for repository, given as owner/name
+func NewGitHubClient(apiURL, repository, token string) (*GitHubClient, error) {
+	owner, name, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid GitHub repository %q: expected owner/name", repository)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token configured")
+	}
+	return &GitHubClient{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		repository: repository,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// CreateIssue creates an issue and returns its web URL
+func (c *GitHubClient) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	request := struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels,omitempty"`
+	}{issue.Title, issue.Body, issue.Labels}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	headers := map[string]string{
+		"Authorization":        "Bearer " + c.token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if err := postJSON(ctx, c.httpClient, c.apiURL+"/repos/"+c.repository+"/issues", headers, request, &created); err != nil {
+		return "", err
+	}
+	return created.HTMLURL, nil
+}
+
+// JiraClient creates issues in a Jira project through the REST API
+type JiraClient struct {
+	baseURL    string
+	project    string
+	issueType  string
+	email      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewJiraClient creates a client for project on the Jira site at baseURL,
+// authenticating with an account email and API token
+func NewJiraClient(baseURL, project, issueType, email, token string) (*JiraClient, error) {
+	if baseURL == "" || project == "" {
+		return nil, fmt.Errorf("Jira base URL and project are required")
+	}
+	if email == "" || token == "" {
+		return nil, fmt.Errorf("no Jira email and API token configured")
+	}
+	return &JiraClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		project:    project,
+		issueType:  issueType,
+		email:      email,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// CreateIssue creates an issue and returns its browse URL. Version 2 of the
+// API is used as it takes the description as plain text.
+func (c *JiraClient) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	type named struct {
+		Key  string `json:"key,omitempty"`
+		Name string `json:"name,omitempty"`
+	}
+	request := struct {
+		Fields struct {
+			Project     named    `json:"project"`
+			IssueType   named    `json:"issuetype"`
+			Summary     string   `json:"summary"`
+			Description string   `json:"description"`
+			Labels      []string `json:"labels,omitempty"`
+		} `json:"fields"`
+	}{}
+	request.Fields.Project = named{Key: c.project}
+	request.Fields.IssueType = named{Name: c.issueType}
+	request.Fields.Summary = issue.Title
+	request.Fields.Description = issue.Body
+	request.Fields.Labels = issue.Labels
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	headers := map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(c.email+":"+c.token)),
+		"Accept":        "application/json",
+	}
+	if err := postJSON(ctx, c.httpClient, c.baseURL+"/rest/api/2/issue", headers, request, &created); err != nil {
+		return "", err
+	}
+	return c.baseURL + "/browse/" + created.Key, nil
+}
+
+// postJSON posts body as JSON to target and decodes a 2xx response into out.
+// Error responses include the start of their body, which carries the API's
+// reason.
+func postJSON(ctx context.Context, client *http.Client, target string, headers map[string]string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, truncate(strings.TrimSpace(string(respBody)), 200))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("invalid API response: %w", err)
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
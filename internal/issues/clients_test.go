@@ -0,0 +1,101 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubClient_CreateIssue(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/repos/acme/infra/issues", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"html_url": "https://github.com/acme/infra/issues/7"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewGitHubClient(server.URL+"/", "acme/infra", "secret")
+	require.NoError(t, err)
+
+	url, err := client.CreateIssue(context.Background(), Issue{Title: "title", Body: "body", Labels: []string{"waffle"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/infra/issues/7", url)
+	assert.Equal(t, "title", received["title"])
+	assert.Equal(t, "body", received["body"])
+	assert.Equal(t, []interface{}{"waffle"}, received["labels"])
+}
+
+func TestGitHubClient_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewGitHubClient(server.URL, "acme/infra", "secret")
+	require.NoError(t, err)
+
+	_, err = client.CreateIssue(context.Background(), Issue{Title: "title"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "Not Found")
+}
+
+func TestNewGitHubClient_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		token      string
+	}{
+		{name: "no owner", repository: "infra", token: "secret"},
+		{name: "nested path", repository: "acme/infra/extra", token: "secret"},
+		{name: "no token", repository: "acme/infra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGitHubClient("https://api.github.com", tt.repository, tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJiraClient_CreateIssue(t *testing.T) {
+	var received struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue", r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "dev@example.com", user)
+		assert.Equal(t, "secret", pass)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key": "OPS-12"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewJiraClient(server.URL, "OPS", "Task", "dev@example.com", "secret")
+	require.NoError(t, err)
+
+	url, err := client.CreateIssue(context.Background(), Issue{Title: "title", Body: "body"})
+
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/browse/OPS-12", url)
+	assert.Equal(t, map[string]interface{}{"key": "OPS"}, received.Fields["project"])
+	assert.Equal(t, map[string]interface{}{"name": "Task"}, received.Fields["issuetype"])
+	assert.Equal(t, "title", received.Fields["summary"])
+	assert.Equal(t, "body", received.Fields["description"])
+}
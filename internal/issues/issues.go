@@ -0,0 +1,134 @@
+// Package issues exports improvement plan items to issue trackers
+package issues
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// Export targets
+const (
+	TargetGitHub = "github"
+	TargetJira   = "jira"
+)
+
+// Issue is a tracker issue to create
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Client creates issues in a tracker
+type Client interface {
+	// CreateIssue creates issue and returns its URL
+	CreateIssue(ctx context.Context, issue Issue) (string, error)
+}
+
+// Export creates an issue with client for each improvement item of session
+// whose priority is at least minPriority, highest priority first. Items
+// already exported to target are skipped. Each created issue is recorded in
+// session.ExportedIssues as soon as it exists, so a failed export can be run
+// again without duplicating the issues it did create. The newly created
+// issues are returned.
+func Export(ctx context.Context, client Client, target string, session *core.ReviewSession, minPriority int, labels []string) ([]core.ExportedIssue, error) {
+	if session.Results == nil || session.Results.ImprovementPlan == nil {
+		return nil, fmt.Errorf("session %s has no improvement plan", session.SessionID)
+	}
+
+	exported := make(map[string]bool)
+	for _, issue := range session.ExportedIssues {
+		if issue.Target == target {
+			exported[issue.ItemID] = true
+		}
+	}
+
+	var created []core.ExportedIssue
+	for _, item := range sortedByPriority(session.Results.ImprovementPlan.Items) {
+		if item.Priority < minPriority {
+			continue
+		}
+		if exported[item.ID] {
+			slog.DebugContext(ctx, "improvement item already exported", "item_id", item.ID, "target", target)
+			continue
+		}
+
+		url, err := client.CreateIssue(ctx, Issue{
+			Title:  issueTitle(item),
+			Body:   issueBody(session, item),
+			Labels: labels,
+		})
+		if err != nil {
+			return created, fmt.Errorf("failed to create issue for %s: %w", item.ID, err)
+		}
+
+		issue := core.ExportedIssue{Target: target, ItemID: item.ID, URL: url}
+		session.ExportedIssues = append(session.ExportedIssues, issue)
+		created = append(created, issue)
+		exported[item.ID] = true
+		slog.InfoContext(ctx, "issue created", "item_id", item.ID, "target", target, "url", url)
+	}
+	return created, nil
+}
+
+// sortedByPriority returns a copy of items, highest priority first and in
+// ID order within a priority
+func sortedByPriority(items []*core.ImprovementPlanItem) []*core.ImprovementPlanItem {
+	sorted := make([]*core.ImprovementPlanItem, len(items))
+	copy(sorted, items)
+	slices.SortStableFunc(sorted, func(a, b *core.ImprovementPlanItem) int {
+		if a.Priority != b.Priority {
+			return b.Priority - a.Priority
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sorted
+}
+
+// issueTitle names the question an item addresses
+func issueTitle(item *core.ImprovementPlanItem) string {
+	if item.Risk != nil && item.Risk.Question != nil && item.Risk.Question.Title != "" {
+		return "[waffle] " + item.Risk.Question.Title
+	}
+	return "[waffle] " + item.ID
+}
+
+// issueBody describes an item in Markdown, which both GitHub and the Jira
+// plain text renderer show legibly
+func issueBody(session *core.ReviewSession, item *core.ImprovementPlanItem) string {
+	var b strings.Builder
+	b.WriteString(item.Description)
+	b.WriteString("\n\n")
+
+	if item.Risk != nil {
+		fmt.Fprintf(&b, "Pillar: %s\n", item.Risk.Pillar)
+	}
+	fmt.Fprintf(&b, "Priority: %d\n", item.Priority)
+	if item.EstimatedEffort != "" {
+		fmt.Fprintf(&b, "Estimated effort: %s\n", item.EstimatedEffort)
+	}
+
+	if len(item.AffectedResources) > 0 {
+		b.WriteString("\nAffected resources:\n")
+		for _, resource := range item.AffectedResources {
+			fmt.Fprintf(&b, "- `%s`\n", resource)
+		}
+	}
+	if len(item.BestPracticeRefs) > 0 {
+		b.WriteString("\nBest practices:\n")
+		for _, ref := range item.BestPracticeRefs {
+			fmt.Fprintf(&b, "- %s\n", ref)
+		}
+	}
+	if item.Remediation != "" {
+		fmt.Fprintf(&b, "\nRemediation:\n%s\n", item.Remediation)
+	}
+
+	fmt.Fprintf(&b, "\nCreated by waffle from review session %s of workload %s (item %s).\n", session.SessionID, session.WorkloadID, item.ID)
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// mockClient records the issues it is asked to create
+type mockClient struct {
+	issues []Issue
+	// failAfter makes every call after that many issues fail, when positive
+	failAfter int
+}
+
+func (m *mockClient) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	if m.failAfter > 0 && len(m.issues) >= m.failAfter {
+		return "", errors.New("rate limited")
+	}
+	m.issues = append(m.issues, issue)
+	return fmt.Sprintf("https://tracker.example.com/issues/%d", len(m.issues)), nil
+}
+
+func newExportSession() *core.ReviewSession {
+	encryption := &core.Risk{
+		Pillar:   core.PillarSecurity,
+		Question: &core.WAFRQuestion{ID: "sec_data_1", Title: "How do you protect your data at rest?"},
+	}
+	backups := &core.Risk{
+		Pillar:   core.PillarReliability,
+		Question: &core.WAFRQuestion{ID: "rel_backup_1", Title: "How do you back up data?"},
+	}
+	tagging := &core.Risk{
+		Pillar:   core.PillarOperationalExcellence,
+		Question: &core.WAFRQuestion{ID: "ops_tags_1"},
+	}
+	return &core.ReviewSession{
+		SessionID:  "session-123",
+		WorkloadID: "my-app",
+		Results: &core.ReviewResults{
+			ImprovementPlan: &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{
+				{ID: "reliability/rel_backup_1", Risk: backups, Description: "Back up the database", Priority: 110},
+				{ID: "operational_excellence/ops_tags_1", Risk: tagging, Description: "Tag resources", Priority: 20},
+				{
+					ID:                "security/sec_data_1",
+					Risk:              encryption,
+					Description:       "Encrypt the log bucket",
+					Priority:          130,
+					EstimatedEffort:   "low",
+					AffectedResources: []string{"aws_s3_bucket.logs"},
+					BestPracticeRefs:  []string{"sec_data_1_bp_1"},
+					Remediation:       "Add a server_side_encryption_configuration block.",
+				},
+			}},
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	client := &mockClient{}
+	session := newExportSession()
+
+	created, err := Export(context.Background(), client, TargetGitHub, session, 100, []string{"waffle"})
+
+	require.NoError(t, err)
+	require.Len(t, client.issues, 2, "one issue per item at or above the threshold")
+	assert.Equal(t, "[waffle] How do you protect your data at rest?", client.issues[0].Title)
+	assert.Equal(t, "[waffle] How do you back up data?", client.issues[1].Title)
+	assert.Equal(t, []string{"waffle"}, client.issues[0].Labels)
+
+	body := client.issues[0].Body
+	assert.Contains(t, body, "Encrypt the log bucket")
+	assert.Contains(t, body, "Pillar: security")
+	assert.Contains(t, body, "Priority: 130")
+	assert.Contains(t, body, "- `aws_s3_bucket.logs`")
+	assert.Contains(t, body, "- sec_data_1_bp_1")
+	assert.Contains(t, body, "Add a server_side_encryption_configuration block.")
+	assert.Contains(t, body, "review session session-123 of workload my-app")
+
+	want := []core.ExportedIssue{
+		{Target: TargetGitHub, ItemID: "security/sec_data_1", URL: "https://tracker.example.com/issues/1"},
+		{Target: TargetGitHub, ItemID: "reliability/rel_backup_1", URL: "https://tracker.example.com/issues/2"},
+	}
+	assert.Equal(t, want, created)
+	assert.Equal(t, want, session.ExportedIssues)
+}
+
+func TestExport_SkipsExportedItems(t *testing.T) {
+	session := newExportSession()
+	_, err := Export(context.Background(), &mockClient{}, TargetGitHub, session, 100, nil)
+	require.NoError(t, err)
+
+	client := &mockClient{}
+	created, err := Export(context.Background(), client, TargetGitHub, session, 100, nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, client.issues, "a re-run creates no duplicate issues")
+	assert.Empty(t, created)
+	assert.Len(t, session.ExportedIssues, 2)
+
+	// Lowering the threshold exports only the items not yet exported
+	created, err = Export(context.Background(), client, TargetGitHub, session, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "operational_excellence/ops_tags_1", created[0].ItemID)
+	assert.Equal(t, "[waffle] operational_excellence/ops_tags_1", client.issues[0].Title)
+}
+
+func TestExport_TargetsAreIndependent(t *testing.T) {
+	session := newExportSession()
+	_, err := Export(context.Background(), &mockClient{}, TargetGitHub, session, 100, nil)
+	require.NoError(t, err)
+
+	client := &mockClient{}
+	created, err := Export(context.Background(), client, TargetJira, session, 100, nil)
+
+	require.NoError(t, err)
+	assert.Len(t, client.issues, 2)
+	assert.Len(t, created, 2)
+	assert.Len(t, session.ExportedIssues, 4)
+}
+
+func TestExport_PartialFailure(t *testing.T) {
+	session := newExportSession()
+
+	created, err := Export(context.Background(), &mockClient{failAfter: 1}, TargetGitHub, session, 100, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reliability/rel_backup_1")
+	require.Len(t, created, 1)
+	assert.Equal(t, created, session.ExportedIssues, "issues created before the failure are recorded")
+
+	client := &mockClient{}
+	created, err = Export(context.Background(), client, TargetGitHub, session, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "reliability/rel_backup_1", created[0].ItemID)
+}
+
+func TestExport_NoImprovementPlan(t *testing.T) {
+	client := &mockClient{}
+
+	_, err := Export(context.Background(), client, TargetGitHub, &core.ReviewSession{SessionID: "session-123"}, 0, nil)
+
+	assert.Error(t, err)
+	assert.Empty(t, client.issues)
+}
@@ -0,0 +1,207 @@
+// Package llm provides model backends other than Bedrock
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/waffle/waffle/internal/bedrock"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// Config holds configuration for an OpenAI-compatible client
+type Config struct {
+	// BaseURL is the API root the chat completions path is appended to,
+	// e.g. https://llm.internal.example.com/v1
+	BaseURL string
+	APIKey  string
+	Model   string
+
+	MaxTokens      int
+	Temperature    float64
+	TopP           float64
+	MaxRetries     int
+	TimeoutSeconds int
+	// MaxParseRetries is how many times an evaluation is re-prompted when
+	// the model response cannot be parsed
+	MaxParseRetries int
+	// ResourceTokenBudget is the estimated tokens the resources of a prompt
+	// may take before bulky property values are trimmed. 0 disables
+	// trimming.
+	ResourceTokenBudget int
+}
+
+// OpenAICompatibleClient implements the BedrockClient interface against an
+// OpenAI-compatible chat completions API. Prompts and response parsing are
+// shared with the Bedrock client, so evaluations do not depend on the
+// backend.
+type OpenAICompatibleClient struct {
+	*bedrock.Client
+	config     Config
+	httpClient *http.Client
+	// backoff is the delay before the first retry, doubled for each retry
+	backoff time.Duration
+}
+
+// NewOpenAICompatibleClient creates a client for the endpoint at
+// config.BaseURL
+func NewOpenAICompatibleClient(config Config) (*OpenAICompatibleClient, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("base URL is required")
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("model name is required")
+	}
+	if config.MaxRetries < 1 {
+		config.MaxRetries = 1
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	c := &OpenAICompatibleClient{
+		config:     config,
+		httpClient: &http.Client{Timeout: time.Duration(config.TimeoutSeconds) * time.Second},
+		backoff:    time.Second,
+	}
+	c.Client = bedrock.NewClientWithInvoker(c, &bedrock.Config{
+		MaxParseRetries:     config.MaxParseRetries,
+		ResourceTokenBudget: config.ResourceTokenBudget,
+	})
+	return c, nil
+}
+
+// chatRequest is a chat completions request
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+	TopP        float64       `json:"top_p,omitempty"`
+}
+
+// chatMessage is a message of a chat completions request or response
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatResponse is a chat completions response
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// statusError is an error response from the API
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("chat completions returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether the request may succeed when sent again
+func (e *statusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// InvokeModel sends prompt as a user message and returns the content of the
+// first choice. Rate limiting and server errors are retried with
+// exponential backoff.
+func (c *OpenAICompatibleClient) InvokeModel(ctx context.Context, prompt string) (string, error) {
+	backoff := c.backoff
+	var lastErr error
+	for attempt := 0; attempt < c.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		response, err := c.invokeOnce(ctx, prompt)
+		if err == nil {
+			return response, nil
+		}
+		statusErr, ok := err.(*statusError)
+		if !ok || !statusErr.retryable() {
+			return "", err
+		}
+		slog.WarnContext(ctx, "model request failed, retrying",
+			"status", statusErr.StatusCode,
+			"attempt", attempt+1,
+		)
+		lastErr = err
+	}
+	return "", fmt.Errorf("%w after %d attempts: %v", core.ErrMaxRetriesExceeded, c.config.MaxRetries, lastErr)
+}
+
+// invokeOnce sends a single chat completions request
+func (c *OpenAICompatibleClient) invokeOnce(ctx context.Context, prompt string) (string, error) {
+	payload, err := json.Marshal(chatRequest{
+		Model:       c.config.Model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   c.config.MaxTokens,
+		Temperature: c.config.Temperature,
+		TopP:        c.config.TopP,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to invoke model: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(body))
+		if len(message) > 200 {
+			message = message[:200] + "..."
+		}
+		return "", &statusError{StatusCode: resp.StatusCode, Body: message}
+	}
+
+	var response chatResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("model returned empty content")
+	}
+
+	slog.DebugContext(ctx, "model invocation succeeded",
+		"model", c.config.Model,
+		"input_tokens", response.Usage.PromptTokens,
+		"output_tokens", response.Usage.CompletionTokens,
+	)
+	return response.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,209 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
+)
+
+// TestMain keeps the global logger off the file system while tests run
+func TestMain(m *testing.M) {
+	if err := logging.InitGlobalLogger(&logging.Config{Level: logging.LevelInfo}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// chatCompletionsServer mimics the chat completions API. Each request is
+// answered by the next of responses, given as a status code and body.
+type chatCompletionsServer struct {
+	*httptest.Server
+	requests  []chatRequest
+	auth      []string
+	responses []fakeResponse
+}
+
+type fakeResponse struct {
+	status int
+	body   string
+}
+
+func newChatCompletionsServer(t *testing.T, responses ...fakeResponse) *chatCompletionsServer {
+	s := &chatCompletionsServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+
+		var request chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		s.requests = append(s.requests, request)
+		s.auth = append(s.auth, r.Header.Get("Authorization"))
+
+		require.NotEmpty(t, s.responses, "unexpected request")
+		response := s.responses[0]
+		s.responses = s.responses[1:]
+		w.WriteHeader(response.status)
+		_, _ = w.Write([]byte(response.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// completion is a successful chat completions response with content
+func completion(content string) fakeResponse {
+	body, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"index": 0, "message": map[string]string{"role": "assistant", "content": content}},
+		},
+		"usage": map[string]int{"prompt_tokens": 120, "completion_tokens": 40},
+	})
+	return fakeResponse{status: http.StatusOK, body: string(body)}
+}
+
+func newTestClient(t *testing.T, server *chatCompletionsServer) *OpenAICompatibleClient {
+	client, err := NewOpenAICompatibleClient(Config{
+		BaseURL:        server.URL + "/v1/",
+		APIKey:         "secret",
+		Model:          "llama-3.1-70b-instruct",
+		MaxTokens:      1024,
+		Temperature:    0.2,
+		TopP:           0.9,
+		MaxRetries:     3,
+		TimeoutSeconds: 5,
+	})
+	require.NoError(t, err)
+	client.backoff = time.Millisecond
+	return client
+}
+
+func TestInvokeModel(t *testing.T) {
+	server := newChatCompletionsServer(t, completion("hello"))
+	client := newTestClient(t, server)
+
+	response, err := client.InvokeModel(context.Background(), "say hello")
+
+	require.NoError(t, err)
+	assert.Equal(t, "hello", response)
+	require.Len(t, server.requests, 1)
+	assert.Equal(t, chatRequest{
+		Model:       "llama-3.1-70b-instruct",
+		Messages:    []chatMessage{{Role: "user", Content: "say hello"}},
+		MaxTokens:   1024,
+		Temperature: 0.2,
+		TopP:        0.9,
+	}, server.requests[0])
+	assert.Equal(t, "Bearer secret", server.auth[0])
+}
+
+func TestInvokeModel_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []fakeResponse
+		want      string
+		wantErr   string
+		requests  int
+	}{
+		{
+			name:      "retries rate limiting",
+			responses: []fakeResponse{{status: http.StatusTooManyRequests, body: "slow down"}, completion("ok")},
+			want:      "ok",
+			requests:  2,
+		},
+		{
+			name:      "retries server errors",
+			responses: []fakeResponse{{status: http.StatusBadGateway}, {status: http.StatusServiceUnavailable}, completion("ok")},
+			want:      "ok",
+			requests:  3,
+		},
+		{
+			name: "gives up after max retries",
+			responses: []fakeResponse{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusServiceUnavailable},
+			},
+			wantErr:  core.ErrMaxRetriesExceeded.Error(),
+			requests: 3,
+		},
+		{
+			name:      "does not retry client errors",
+			responses: []fakeResponse{{status: http.StatusBadRequest, body: `{"error": "unknown model"}`}},
+			wantErr:   "status 400: {\"error\": \"unknown model\"}",
+			requests:  1,
+		},
+		{
+			name:      "no choices",
+			responses: []fakeResponse{{status: http.StatusOK, body: `{"choices": []}`}},
+			wantErr:   "empty content",
+			requests:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newChatCompletionsServer(t, tt.responses...)
+			client := newTestClient(t, server)
+
+			response, err := client.InvokeModel(context.Background(), "prompt")
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, response)
+			}
+			assert.Len(t, server.requests, tt.requests)
+		})
+	}
+}
+
+func TestEvaluateWAFRQuestion(t *testing.T) {
+	server := newChatCompletionsServer(t, completion("```json\n"+`{
+		"selected_choices": ["sec_data_1_encrypt"],
+		"evidence": [{"choice_id": "sec_data_1_encrypt", "explanation": "Bucket is encrypted", "resources": ["aws_s3_bucket.logs"], "confidence": 0.9}],
+		"overall_confidence": 0.85,
+		"notes": "Encryption configured"
+	}`+"\n```"))
+	client := newTestClient(t, server)
+	question := &core.WAFRQuestion{
+		ID:      "sec_data_1",
+		Pillar:  core.PillarSecurity,
+		Title:   "How do you protect your data at rest?",
+		Choices: []core.Choice{{ID: "sec_data_1_encrypt", Title: "Encrypt data at rest"}},
+	}
+	model := &core.WorkloadModel{Resources: []core.Resource{{Type: "aws_s3_bucket", Address: "aws_s3_bucket.logs"}}}
+
+	evaluation, err := client.EvaluateWAFRQuestion(context.Background(), question, model)
+
+	require.NoError(t, err)
+	require.Len(t, evaluation.SelectedChoices, 1)
+	assert.Equal(t, "sec_data_1_encrypt", evaluation.SelectedChoices[0].ID)
+	assert.Equal(t, 0.85, evaluation.ConfidenceScore)
+	require.Len(t, server.requests, 1)
+	assert.Contains(t, server.requests[0].Messages[0].Content, "How do you protect your data at rest?")
+}
+
+func TestNewOpenAICompatibleClient_Invalid(t *testing.T) {
+	tests := []Config{
+		{Model: "llama"},
+		{BaseURL: "https://llm.example.com/v1"},
+	}
+
+	for i, config := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			_, err := NewOpenAICompatibleClient(config)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -28,7 +28,10 @@ type Logger struct {
 
 // Config holds logger configuration
 type Config struct {
-	Level      LogLevel
+	Level LogLevel
+	// ConsoleLevel, when above Level, raises the minimum level written to
+	// stderr. The log file still receives everything at Level.
+	ConsoleLevel LogLevel
 	LogDir     string
 	MaxSizeMB  int64
 	EnableFile bool
@@ -81,12 +84,12 @@ func NewLogger(config *Config) (*Logger, error) {
 		}
 
 		// Create file handler
-		fileHandler := createHandler(logFile, config)
+		fileHandler := createHandler(logFile, config, mapLogLevel(config.Level))
 		handlers = append(handlers, fileHandler)
 	}
 
 	// Create console handler (always enabled)
-	consoleHandler := createHandler(os.Stderr, config)
+	consoleHandler := createHandler(os.Stderr, config, consoleLevel(config))
 	handlers = append(handlers, consoleHandler)
 
 	// Use multi-handler if we have both file and console
@@ -105,10 +108,8 @@ func NewLogger(config *Config) (*Logger, error) {
 	}, nil
 }
 
-// createHandler creates a slog handler based on configuration
-func createHandler(w io.Writer, config *Config) slog.Handler {
-	level := mapLogLevel(config.Level)
-
+// createHandler creates a slog handler writing records at level and above
+func createHandler(w io.Writer, config *Config, level slog.Level) slog.Handler {
 	opts := &slog.HandlerOptions{
 		Level: level,
 		AddSource: level == slog.LevelDebug,
@@ -120,6 +121,15 @@ func createHandler(w io.Writer, config *Config) slog.Handler {
 	return slog.NewTextHandler(w, opts)
 }
 
+// consoleLevel returns the minimum level written to stderr
+func consoleLevel(config *Config) slog.Level {
+	level := mapLogLevel(config.Level)
+	if config.ConsoleLevel != "" {
+		level = max(level, mapLogLevel(config.ConsoleLevel))
+	}
+	return level
+}
+
 // mapLogLevel converts LogLevel to slog.Level
 func mapLogLevel(level LogLevel) slog.Level {
 	switch level {
@@ -3,6 +3,7 @@ package logging
 import (
 	"bytes"
 	"context"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -195,6 +196,9 @@ func TestGlobalLogger(t *testing.T) {
 }
 
 func TestGetLoggerAutoInit(t *testing.T) {
+	// The default config logs under the working directory
+	t.Chdir(t.TempDir())
+
 	// Reset global logger
 	globalLogger = nil
 
@@ -259,6 +263,41 @@ func TestLogFileCreation(t *testing.T) {
 	assert.True(t, strings.HasSuffix(fileName, ".log"))
 }
 
+func TestConsoleLevel(t *testing.T) {
+	tempDir := t.TempDir()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	original := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = original }()
+
+	logger, err := NewLogger(&Config{
+		Level:        LevelInfo,
+		ConsoleLevel: LevelWarning,
+		LogDir:       tempDir,
+		EnableFile:   true,
+	})
+	require.NoError(t, err)
+	logger.Info("info message")
+	logger.Warn("warn message")
+	require.NoError(t, logger.Close())
+	require.NoError(t, w.Close())
+
+	stderr, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.NotContains(t, string(stderr), "info message")
+	assert.Contains(t, string(stderr), "warn message")
+
+	// The log file still receives INFO
+	files, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	logData, err := os.ReadFile(filepath.Join(tempDir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "info message")
+	assert.Contains(t, string(logData), "warn message")
+}
+
 func TestLogFilePermissions(t *testing.T) {
 	tempDir := t.TempDir()
 	config := &Config{
@@ -0,0 +1,70 @@
+package metrics
+
+import "sync"
+
+// Client label values identifying the instrumented AWS client
+const (
+	ClientBedrock = "bedrock"
+	ClientWAFR    = "wafr"
+)
+
+// DefaultDurationBuckets are the review duration histogram bounds in seconds
+var DefaultDurationBuckets = []float64{30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// Metrics holds the operational metrics recorded by Waffle.
+// The zero value has no collectors and records nothing, which is how metrics
+// are disabled.
+type Metrics struct {
+	// QuestionsEvaluated counts evaluated questions by pillar
+	QuestionsEvaluated *CounterVec
+	// BedrockCalls counts model invocations by outcome (success or error)
+	BedrockCalls *CounterVec
+	// Retries counts retried AWS calls by client and operation
+	Retries *CounterVec
+	// Throttles counts throttling responses by client
+	Throttles *CounterVec
+	// TokensUsed counts Bedrock tokens by direction (input or output)
+	TokensUsed *CounterVec
+	// ReviewDuration observes review execution time in seconds
+	ReviewDuration *Histogram
+}
+
+// New creates the Waffle metrics and registers them on r
+func New(r *Registry) *Metrics {
+	return &Metrics{
+		QuestionsEvaluated: r.NewCounterVec("waffle_questions_evaluated_total",
+			"Number of WAFR questions evaluated.", "pillar"),
+		BedrockCalls: r.NewCounterVec("waffle_bedrock_calls_total",
+			"Number of Bedrock model invocations.", "outcome"),
+		Retries: r.NewCounterVec("waffle_retries_total",
+			"Number of retried AWS API calls.", "client", "operation"),
+		Throttles: r.NewCounterVec("waffle_throttling_events_total",
+			"Number of throttling responses from AWS.", "client"),
+		TokensUsed: r.NewCounterVec("waffle_bedrock_tokens_total",
+			"Number of Bedrock tokens used.", "direction"),
+		ReviewDuration: r.NewHistogram("waffle_review_duration_seconds",
+			"Duration of review executions in seconds.", DefaultDurationBuckets),
+	}
+}
+
+// Disabled returns metrics that record nothing
+func Disabled() *Metrics {
+	return &Metrics{}
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultMetrics      *Metrics
+	defaultRegistryOnce sync.Once
+)
+
+// Default returns the process-wide registry and the metrics registered on it.
+// The registry is created on first use so that processes with metrics
+// disabled never allocate it.
+func Default() (*Registry, *Metrics) {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		defaultMetrics = New(defaultRegistry)
+	})
+	return defaultRegistry, defaultMetrics
+}
@@ -0,0 +1,299 @@
+// Package metrics provides optional operational metrics in the Prometheus text
+// exposition format.
+//
+// Collectors are nil-safe: calling Inc, Add or Observe on a nil collector does
+// nothing, so instrumented code does not need to check whether metrics are
+// enabled.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collector is a metric family that can write itself in text format and be
+// saved to and added from snapshots
+type collector interface {
+	metricName() string
+	writeText(w io.Writer) error
+	addTo(s *snapshot)
+	addFrom(s *snapshot)
+}
+
+// Registry holds a set of metric families
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		names: make(map[string]bool),
+	}
+}
+
+// register adds a collector, panicking on duplicate names since metric
+// definitions are fixed at build time
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[c.metricName()] {
+		panic(fmt.Sprintf("metrics: duplicate metric name %q", c.metricName()))
+	}
+	r.names[c.metricName()] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// NewCounterVec creates and registers a counter partitioned by labelNames
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]*counterValue),
+	}
+	r.register(c)
+	return c
+}
+
+// NewHistogram creates and registers a histogram with the given upper bounds
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &Histogram{
+		name:    name,
+		help:    help,
+		buckets: sorted,
+		counts:  make([]uint64, len(sorted)),
+	}
+	r.register(h)
+	return h
+}
+
+// WriteText writes every registered metric in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].metricName() < collectors[j].metricName()
+	})
+
+	for _, c := range collectors {
+		if err := c.writeText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns an HTTP handler serving the registry
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+// CounterVec is a monotonically increasing counter partitioned by labels
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+// Inc increments the counter for the given label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter for the given label values. Negative deltas are
+// ignored because counters never decrease.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if c == nil || delta < 0 {
+		return
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.values[key]
+	if !ok {
+		v = &counterValue{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = v
+	}
+	v.value += delta
+}
+
+// Value returns the current value for the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, ok := c.values[strings.Join(labelValues, "\xff")]; ok {
+		return v.value
+	}
+	return 0
+}
+
+func (c *CounterVec) metricName() string {
+	return c.name
+}
+
+func (c *CounterVec) writeText(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, helpEscaper.Replace(c.help), c.name); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := c.values[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, v.labelValues), formatValue(v.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Histogram counts observations into cumulative buckets
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Observe records a single observation
+func (h *Histogram) Observe(v float64) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	if h == nil {
+		return 0
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Sum returns the total of all observations
+func (h *Histogram) Sum() float64 {
+	if h == nil {
+		return 0
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+func (h *Histogram) metricName() string {
+	return h.name
+}
+
+func (h *Histogram) writeText(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, helpEscaper.Replace(h.help), h.name); err != nil {
+		return err
+	}
+	for i, upper := range h.buckets {
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatValue(upper), h.counts[i]); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n",
+		h.name, h.count, h.name, formatValue(h.sum), h.name, h.count); err != nil {
+		return err
+	}
+	return nil
+}
+
+// formatLabels renders a label set such as {client="wafr",operation="ListAnswers"}
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, labelValueEscaper.Replace(value)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelValueEscaper escapes a label value as the text format requires:
+// only backslash, double quote and line feed are escaped
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escapes HELP text, where only backslash and line feed are
+// escaped
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// formatValue renders a sample value the way Prometheus expects
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterVec(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_calls_total", "Test calls.", "client", "operation")
+
+	c.Inc("wafr", "ListAnswers")
+	c.Inc("wafr", "ListAnswers")
+	c.Add(3, "bedrock", "InvokeModel")
+	c.Add(-1, "bedrock", "InvokeModel")
+
+	assert.Equal(t, 2.0, c.Value("wafr", "ListAnswers"))
+	assert.Equal(t, 3.0, c.Value("bedrock", "InvokeModel"))
+	assert.Equal(t, 0.0, c.Value("wafr", "UpdateAnswer"))
+}
+
+func TestWriteText_Escaping(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_escaped_total", "Calls to C:\\tmp\nper operation.", "operation")
+	c.Inc("say \"héllo\"\n\\ \t")
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	assert.Contains(t, buf.String(), `# HELP test_escaped_total Calls to C:\\tmp\nper operation.`+"\n")
+	assert.Contains(t, buf.String(), `test_escaped_total{operation="say \"héllo\"\n\\ `+"\t"+`"} 1`)
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("test_duration_seconds", "Test duration.", []float64{10, 1})
+
+	h.Observe(0.5)
+	h.Observe(5)
+	h.Observe(50)
+
+	assert.Equal(t, uint64(3), h.Count())
+	assert.Equal(t, 55.5, h.Sum())
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	assert.Contains(t, buf.String(), `test_duration_seconds_bucket{le="1"} 1`)
+	assert.Contains(t, buf.String(), `test_duration_seconds_bucket{le="10"} 2`)
+	assert.Contains(t, buf.String(), `test_duration_seconds_bucket{le="+Inf"} 3`)
+	assert.Contains(t, buf.String(), "test_duration_seconds_sum 55.5")
+}
+
+func TestNilCollectorsAreNoOps(t *testing.T) {
+	m := Disabled()
+
+	assert.NotPanics(t, func() {
+		m.Retries.Inc(ClientWAFR, "ListAnswers")
+		m.TokensUsed.Add(10, "input")
+		m.ReviewDuration.Observe(1)
+	})
+	assert.Zero(t, m.Retries.Value(ClientWAFR, "ListAnswers"))
+	assert.Zero(t, m.ReviewDuration.Count())
+}
+
+func TestRegistry_DuplicateName(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("test_total", "Test.")
+
+	assert.Panics(t, func() {
+		r.NewCounterVec("test_total", "Test again.")
+	})
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	m := New(r)
+	m.Retries.Inc(ClientWAFR, "ListAnswers")
+	m.TokensUsed.Add(150, "input")
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE waffle_retries_total counter")
+	assert.Contains(t, body, `waffle_retries_total{client="wafr",operation="ListAnswers"} 1`)
+	assert.Contains(t, body, `waffle_bedrock_tokens_total{direction="input"} 150`)
+	assert.Contains(t, body, "# TYPE waffle_review_duration_seconds histogram")
+}
+
+func TestSnapshotHandler(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"review-1.json", "review-2.json"} {
+		r := NewRegistry()
+		m := New(r)
+		m.QuestionsEvaluated.Inc("security")
+		m.ReviewDuration.Observe(45)
+		require.NoError(t, r.WriteSnapshotFile(filepath.Join(dir, name)))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a snapshot"), 0644))
+
+	rec := httptest.NewRecorder()
+	SnapshotHandler(dir).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `waffle_questions_evaluated_total{pillar="security"} 2`)
+	assert.Contains(t, body, `waffle_review_duration_seconds_bucket{le="60"} 2`)
+	assert.Contains(t, body, "waffle_review_duration_seconds_sum 90")
+
+	// A missing directory serves zero values
+	rec = httptest.NewRecorder()
+	SnapshotHandler(filepath.Join(dir, "missing")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "waffle_questions_evaluated_total{")
+}
+
+func TestCompactSnapshotDir(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"review-1.json", "review-2.json", "review-3.json"} {
+		r := NewRegistry()
+		m := New(r)
+		m.QuestionsEvaluated.Inc("security")
+		path := filepath.Join(dir, name)
+		require.NoError(t, r.WriteSnapshotFile(path))
+		if name != "review-3.json" {
+			require.NoError(t, os.Chtimes(path, old, old))
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "broken.json"), old, old))
+
+	err := CompactSnapshotDir(dir, time.Now().Add(-time.Minute))
+
+	require.Error(t, err, "unreadable snapshots are reported")
+	var names []string
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"broken.json", "cumulative.json", "review-3.json"}, names,
+		"old snapshots are merged, recent and unreadable ones are kept")
+
+	// A second compaction adds to the cumulative totals
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "review-3.json"), old, old))
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.json")))
+	require.NoError(t, CompactSnapshotDir(dir, time.Now().Add(-time.Minute)))
+
+	r := NewRegistry()
+	m := New(r)
+	require.NoError(t, r.AddSnapshotDir(dir))
+	assert.Equal(t, 3.0, m.QuestionsEvaluated.Value("security"))
+	_, err = os.Stat(filepath.Join(dir, "review-3.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
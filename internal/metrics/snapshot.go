@@ -0,0 +1,260 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics are recorded by the process running a review, while `waffle serve`
+// runs in a process of its own. Each recording process writes its totals to
+// a snapshot file of its own in a shared directory, and the server adds up
+// the snapshots of the directory on every scrape. Since no two processes
+// write the same file, concurrent reviews cannot lose each other's counts.
+//
+// So that the directory does not grow with every run, the server merges
+// snapshots older than compactAfter into a single cumulative snapshot and
+// removes them. A process saves its snapshot once, when it exits, so an old
+// snapshot is not written again.
+
+// cumulativeSnapshotFile holds the totals of the snapshots merged so far
+const cumulativeSnapshotFile = "cumulative.json"
+
+// compactAfter is how old a snapshot must be before it is merged
+const compactAfter = time.Minute
+
+// snapshot is the JSON form of a registry's values
+type snapshot struct {
+	Counters   map[string][]counterSample `json:"counters,omitempty"`
+	Histograms map[string]histogramSample `json:"histograms,omitempty"`
+}
+
+type counterSample struct {
+	Labels []string `json:"labels"`
+	Value  float64  `json:"value"`
+}
+
+type histogramSample struct {
+	Buckets []float64 `json:"buckets"`
+	Counts  []uint64  `json:"counts"`
+	Count   uint64    `json:"count"`
+	Sum     float64   `json:"sum"`
+}
+
+// WriteSnapshotFile writes the current values of the registry to path,
+// replacing the file atomically so a concurrent scrape never reads it half
+// written
+func (r *Registry) WriteSnapshotFile(path string) error {
+	s := snapshot{
+		Counters:   make(map[string][]counterSample),
+		Histograms: make(map[string]histogramSample),
+	}
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+	for _, c := range collectors {
+		c.addTo(&s)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create metrics state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+// AddSnapshotDir adds the values of every snapshot file in dir to the
+// registry's collectors. A missing directory holds no snapshots. Files that
+// cannot be read are skipped and reported in the returned error.
+func (r *Registry) AddSnapshotDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metrics state directory: %w", err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if err := r.addSnapshotFile(filepath.Join(dir, entry.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CompactSnapshotDir merges the snapshots in dir last written before cutoff
+// into the cumulative snapshot and removes them. Snapshots that cannot be
+// read are left in place and reported in the returned error.
+func CompactSnapshotDir(dir string, cutoff time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metrics state directory: %w", err)
+	}
+
+	r := NewRegistry()
+	New(r)
+	cumulativePath := filepath.Join(dir, cumulativeSnapshotFile)
+	if err := r.addSnapshotFile(cumulativePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var merged []string
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || entry.Name() == cumulativeSnapshotFile {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := r.addSnapshotFile(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		merged = append(merged, path)
+	}
+	if len(merged) == 0 {
+		return errors.Join(errs...)
+	}
+
+	// The merged snapshots are removed only once their totals are saved
+	if err := r.WriteSnapshotFile(cumulativePath); err != nil {
+		return err
+	}
+	for _, path := range merged {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// addSnapshotFile adds the values of the snapshot at path to the registry's
+// collectors
+func (r *Registry) addSnapshotFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid metrics snapshot %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+	for _, c := range collectors {
+		c.addFrom(&s)
+	}
+	return nil
+}
+
+// SnapshotHandler returns an HTTP handler serving the Waffle metrics added
+// up from the snapshots in dir. Each scrape first compacts the snapshots
+// older than compactAfter.
+func SnapshotHandler(dir string) http.Handler {
+	// Scrapes are serialized so a compaction never runs while another
+	// scrape is adding up the directory
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := CompactSnapshotDir(dir, time.Now().Add(-compactAfter)); err != nil {
+			slog.Warn("failed to compact metrics snapshots", "error", err)
+		}
+		r := NewRegistry()
+		New(r)
+		if err := r.AddSnapshotDir(dir); err != nil {
+			slog.Warn("some metrics snapshots were skipped", "error", err)
+		}
+		r.Handler().ServeHTTP(w, req)
+	})
+}
+
+func (c *CounterVec) addTo(s *snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	samples := make([]counterSample, 0, len(c.values))
+	for _, v := range c.values {
+		samples = append(samples, counterSample{Labels: v.labelValues, Value: v.value})
+	}
+	s.Counters[c.name] = samples
+}
+
+func (c *CounterVec) addFrom(s *snapshot) {
+	for _, sample := range s.Counters[c.name] {
+		c.Add(sample.Value, sample.Labels...)
+	}
+}
+
+func (h *Histogram) addTo(s *snapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s.Histograms[h.name] = histogramSample{
+		Buckets: h.buckets,
+		Counts:  append([]uint64(nil), h.counts...),
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+}
+
+// addFrom adds a snapshot of the histogram. Snapshots taken with other
+// bucket bounds cannot be added and are ignored.
+func (h *Histogram) addFrom(s *snapshot) {
+	sample, ok := s.Histograms[h.name]
+	if !ok || len(sample.Buckets) != len(h.buckets) || len(sample.Counts) != len(h.buckets) {
+		return
+	}
+	for i, upper := range h.buckets {
+		if sample.Buckets[i] != upper {
+			return
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, count := range sample.Counts {
+		h.counts[i] += count
+	}
+	h.count += sample.Count
+	h.sum += sample.Sum
+}
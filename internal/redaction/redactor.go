@@ -61,12 +61,12 @@ func DefaultRedactionRules() []RedactionRule {
 			Replacement: `$1 $2 "[REDACTED]"`,
 		},
 		{
-			Name:        "Email Address",
+			Name:        EmailFinding,
 			Pattern:     regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`),
 			Replacement: "[EMAIL]",
 		},
 		{
-			Name:        "Private IP",
+			Name:        PrivateIPFinding,
 			Pattern:     regexp.MustCompile(`\b(10\.\d{1,3}\.\d{1,3}\.\d{1,3}|172\.(1[6-9]|2[0-9]|3[0-1])\.\d{1,3}\.\d{1,3}|192\.168\.\d{1,3}\.\d{1,3})\b`),
 			Replacement: "[PRIVATE_IP]",
 		},
@@ -201,6 +201,81 @@ func (r *Redactor) redactValue(value interface{}) (interface{}, []string) {
 	}
 }
 
+// TerraformSensitiveFinding is the finding reported for values Terraform
+// itself marked as sensitive
+const TerraformSensitiveFinding = "Terraform Sensitive Value"
+
+// Findings for personal and network details, which are redacted from prompts
+// but are not credentials
+const (
+	EmailFinding     = "Email Address"
+	PrivateIPFinding = "Private IP"
+)
+
+// SecretFindings returns the findings that indicate a credential or other
+// secret in the source, leaving out personal and network details and values
+// Terraform itself marked sensitive
+func SecretFindings(findings []string) []string {
+	var secrets []string
+	for _, finding := range findings {
+		switch finding {
+		case EmailFinding, PrivateIPFinding, TerraformSensitiveFinding:
+			continue
+		}
+		secrets = append(secrets, finding)
+	}
+	return secrets
+}
+
+// RedactMarkedValues redacts values flagged by a Terraform sensitivity marker
+// structure (plan sensitive_values or after_sensitive). The marker mirrors the
+// shape of the value with true at sensitive leaves. It returns the redacted
+// value and the number of values replaced.
+func RedactMarkedValues(value interface{}, marker interface{}) (interface{}, int) {
+	switch m := marker.(type) {
+	case bool:
+		if m && value != nil {
+			return "[REDACTED]", 1
+		}
+		return value, 0
+
+	case map[string]interface{}:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return value, 0
+		}
+		redacted := make(map[string]interface{}, len(obj))
+		count := 0
+		for key, item := range obj {
+			redactedItem, n := RedactMarkedValues(item, m[key])
+			redacted[key] = redactedItem
+			count += n
+		}
+		return redacted, count
+
+	case []interface{}:
+		arr, ok := value.([]interface{})
+		if !ok {
+			return value, 0
+		}
+		redacted := make([]interface{}, len(arr))
+		count := 0
+		for i, item := range arr {
+			var itemMarker interface{}
+			if i < len(m) {
+				itemMarker = m[i]
+			}
+			redactedItem, n := RedactMarkedValues(item, itemMarker)
+			redacted[i] = redactedItem
+			count += n
+		}
+		return redacted, count
+
+	default:
+		return value, 0
+	}
+}
+
 // LogRedactionFindings logs redaction findings with appropriate context
 func LogRedactionFindings(findings []string, context string) {
 	if len(findings) > 0 {
@@ -558,3 +558,56 @@ resource "aws_db_instance" "default" {
 	assert.Contains(t, redacted, "mysql")
 	assert.Contains(t, redacted, "admin")
 }
+
+func TestRedactMarkedValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		marker    interface{}
+		want      interface{}
+		wantCount int
+	}{
+		{
+			name:      "sensitive attribute",
+			value:     map[string]interface{}{"value": "s3cr3t", "name": "param"},
+			marker:    map[string]interface{}{"value": true},
+			want:      map[string]interface{}{"value": "[REDACTED]", "name": "param"},
+			wantCount: 1,
+		},
+		{
+			name:      "nested list element",
+			value:     map[string]interface{}{"env": []interface{}{"a", "b"}},
+			marker:    map[string]interface{}{"env": []interface{}{false, true}},
+			want:      map[string]interface{}{"env": []interface{}{"a", "[REDACTED]"}},
+			wantCount: 1,
+		},
+		{
+			name:      "null sensitive value is kept",
+			value:     map[string]interface{}{"password": nil},
+			marker:    map[string]interface{}{"password": true},
+			want:      map[string]interface{}{"password": nil},
+			wantCount: 0,
+		},
+		{
+			name:      "no marker",
+			value:     map[string]interface{}{"bucket": "logs"},
+			marker:    nil,
+			want:      map[string]interface{}{"bucket": "logs"},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := RedactMarkedValues(tt.value, tt.marker)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCount, count)
+		})
+	}
+}
+
+func TestSecretFindings(t *testing.T) {
+	findings := []string{"AWS Access Key", EmailFinding, "Password Field", PrivateIPFinding, TerraformSensitiveFinding}
+	assert.Equal(t, []string{"AWS Access Key", "Password Field"}, SecretFindings(findings))
+	assert.Empty(t, SecretFindings([]string{PrivateIPFinding}))
+}
@@ -0,0 +1,235 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+var (
+	// ErrUnknownFormat is returned when a report format is not registered
+	ErrUnknownFormat = errors.New("unknown report format")
+
+	// ErrFormatAlreadyRegistered is returned when registering a duplicate format name
+	ErrFormatAlreadyRegistered = errors.New("report format already registered")
+
+	// ErrAWSRequired is returned when a report generated by AWS is requested
+	// from a generator without a WAFR client
+	ErrAWSRequired = errors.New("report format requires AWS")
+)
+
+// GenerateFunc renders a report for a review session
+type GenerateFunc func(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession) ([]byte, error)
+
+// WriteFunc renders a report for a review session directly to w
+type WriteFunc func(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession, w io.Writer) error
+
+// Format describes a named report format
+type Format struct {
+	// Name is the value accepted by the --format flag
+	Name string
+	// Extension is the file extension used for the report, without a dot
+	Extension string
+	// Binary formats must be written to a file rather than stdout
+	Binary bool
+	// RequiresAWS formats are generated by AWS rather than from the stored
+	// session, so they cannot be rendered offline
+	RequiresAWS bool
+	// Generate renders the report
+	Generate GenerateFunc
+	// Write optionally streams the report to a writer instead of buffering
+	// it in memory. Formats without it are written from Generate.
+	Write WriteFunc
+}
+
+// Render writes the report for session to w, streaming it when the format
+// supports that
+func (f Format) Render(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession, w io.Writer) error {
+	if f.Write != nil {
+		return f.Write(ctx, gen, session, w)
+	}
+	data, err := f.Generate(ctx, gen, session)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// FormatRegistry maps report format names to their generators.
+// It is safe for concurrent use.
+type FormatRegistry struct {
+	mu      sync.RWMutex
+	formats map[string]Format
+}
+
+// NewFormatRegistry creates an empty format registry
+func NewFormatRegistry() *FormatRegistry {
+	return &FormatRegistry{
+		formats: make(map[string]Format),
+	}
+}
+
+// NewDefaultFormatRegistry creates a registry holding the built-in formats
+func NewDefaultFormatRegistry() *FormatRegistry {
+	r := NewFormatRegistry()
+	for _, f := range builtinFormats() {
+		// Built-in names are unique, so registration cannot fail
+		_ = r.Register(f)
+	}
+	return r
+}
+
+var defaultRegistry = NewDefaultFormatRegistry()
+
+// DefaultRegistry returns the registry used by the CLI. Embedding
+// applications can register custom formats on it.
+func DefaultRegistry() *FormatRegistry {
+	return defaultRegistry
+}
+
+// Register adds a format to the registry
+func (r *FormatRegistry) Register(f Format) error {
+	if f.Name == "" {
+		return errors.New("report format name is required")
+	}
+	if f.Generate == nil {
+		return fmt.Errorf("report format %q has no generator", f.Name)
+	}
+	if f.Extension == "" {
+		f.Extension = f.Name
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.formats[f.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrFormatAlreadyRegistered, f.Name)
+	}
+	r.formats[f.Name] = f
+	return nil
+}
+
+// Lookup returns the format registered under name
+func (r *FormatRegistry) Lookup(name string) (Format, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+	return f, nil
+}
+
+// Names returns the registered format names in sorted order
+func (r *FormatRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.formats))
+	for name := range r.formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Formats returns the registered formats sorted by name
+func (r *FormatRegistry) Formats() []Format {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	formats := make([]Format, 0, len(r.formats))
+	for _, f := range r.formats {
+		formats = append(formats, f)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+	return formats
+}
+
+// RequiresAWS reports whether any of the formats is generated by AWS
+func RequiresAWS(formats []Format) bool {
+	for _, f := range formats {
+		if f.RequiresAWS {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinFormats returns the formats supported out of the box
+func builtinFormats() []Format {
+	return []Format{
+		{
+			Name:      string(core.ReportFormatJSON),
+			Extension: "json",
+			Generate:  generateJSON,
+			Write:     writeJSON,
+		},
+		{
+			Name:        string(core.ReportFormatPDF),
+			Extension:   "pdf",
+			Binary:      true,
+			RequiresAWS: true,
+			Generate:    generatePDF,
+		},
+		{
+			Name:        string(core.ReportFormatConsolidatedJSON),
+			Extension:   "consolidated.json",
+			RequiresAWS: true,
+			Generate:    generateConsolidatedJSON,
+		},
+	}
+}
+
+// generateJSON renders the enhanced JSON results for a session
+func generateJSON(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(ctx, gen, session, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeJSON streams the enhanced JSON results for a session to w, one
+// evaluation and improvement plan item at a time
+func writeJSON(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession, w io.Writer) error {
+	resultsData, err := gen.GetResultsJSON(ctx, session.AWSWorkloadID, session)
+	if err != nil {
+		return err
+	}
+	return core.WriteJSONStream(w, resultsData)
+}
+
+// generatePDF retrieves the consolidated PDF report from AWS
+func generatePDF(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession) ([]byte, error) {
+	return gen.GetConsolidatedReport(ctx, session.AWSWorkloadID, core.ReportFormatPDF)
+}
+
+// generateConsolidatedJSON retrieves the consolidated report metrics of the
+// session's workload from AWS
+func generateConsolidatedJSON(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession) ([]byte, error) {
+	metricsGen, ok := gen.(core.ConsolidatedReportJSONGenerator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAWSRequired, core.ReportFormatConsolidatedJSON)
+	}
+	consolidated, err := metricsGen.GetConsolidatedReportJSON(ctx, session.AWSWorkloadID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := core.WriteJSON(&buf, consolidated); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
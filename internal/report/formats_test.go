@@ -0,0 +1,136 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func csvFormat() Format {
+	return Format{
+		Name:      "csv",
+		Extension: "csv",
+		Generate: func(ctx context.Context, gen core.ReportGenerator, session *core.ReviewSession) ([]byte, error) {
+			return []byte("session_id\n" + session.SessionID + "\n"), nil
+		},
+	}
+}
+
+func TestFormatRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    Format
+		wantErr   bool
+		wantErrIs error
+	}{
+		{name: "custom format", format: csvFormat()},
+		{name: "missing name", format: Format{Generate: csvFormat().Generate}, wantErr: true},
+		{name: "missing generator", format: Format{Name: "csv"}, wantErr: true},
+		{name: "duplicate built-in", format: Format{Name: "json", Generate: csvFormat().Generate}, wantErr: true, wantErrIs: ErrFormatAlreadyRegistered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewDefaultFormatRegistry()
+			err := r.Register(tt.format)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+				assert.Equal(t, []string{"consolidated-json", "json", "pdf"}, r.Names())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"consolidated-json", "csv", "json", "pdf"}, r.Names())
+		})
+	}
+}
+
+func TestFormatRegistry_Lookup(t *testing.T) {
+	r := NewDefaultFormatRegistry()
+	require.NoError(t, r.Register(csvFormat()))
+
+	pdf, err := r.Lookup("pdf")
+	require.NoError(t, err)
+	assert.True(t, pdf.Binary)
+	assert.Equal(t, "pdf", pdf.Extension)
+
+	csv, err := r.Lookup("csv")
+	require.NoError(t, err)
+	data, err := csv.Generate(context.Background(), NewGenerator(), &core.ReviewSession{SessionID: "sess-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "session_id\nsess-1\n", string(data))
+
+	_, err = r.Lookup("docx")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFormatRegistry_ExtensionDefaultsToName(t *testing.T) {
+	r := NewFormatRegistry()
+	require.NoError(t, r.Register(Format{Name: "md", Generate: csvFormat().Generate}))
+
+	f, err := r.Lookup("md")
+	require.NoError(t, err)
+	assert.Equal(t, "md", f.Extension)
+}
+
+func TestRequiresAWS(t *testing.T) {
+	r := NewDefaultFormatRegistry()
+	require.NoError(t, r.Register(csvFormat()))
+
+	jsonFormat, err := r.Lookup("json")
+	require.NoError(t, err)
+	pdf, err := r.Lookup("pdf")
+	require.NoError(t, err)
+	csv, err := r.Lookup("csv")
+	require.NoError(t, err)
+
+	assert.False(t, RequiresAWS([]Format{jsonFormat, csv}))
+	assert.True(t, RequiresAWS([]Format{jsonFormat, pdf}))
+	assert.True(t, RequiresAWS(r.Formats()))
+}
+
+func TestFormatRender(t *testing.T) {
+	ctx := context.Background()
+	session := testSession()
+	for i := 0; i < 50; i++ {
+		question := &core.WAFRQuestion{ID: fmt.Sprintf("q%d", i), Pillar: core.PillarSecurity, Title: "Question <&>"}
+		session.Results.Evaluations = append(session.Results.Evaluations, &core.QuestionEvaluation{
+			Question:        question,
+			SelectedChoices: []core.Choice{{ID: question.ID + "_a"}},
+			ConfidenceScore: 0.5,
+		})
+	}
+	session.Results.ImprovementPlan = &core.ImprovementPlan{Items: []*core.ImprovementPlanItem{{
+		ID:   "imp-1",
+		Risk: &core.Risk{ID: "risk-1", Question: session.Results.Evaluations[0].Question, Severity: core.RiskLevelHigh},
+	}}}
+
+	t.Run("json streams the buffered results", func(t *testing.T) {
+		jsonFormat, err := NewDefaultFormatRegistry().Lookup("json")
+		require.NoError(t, err)
+		require.NotNil(t, jsonFormat.Write)
+
+		resultsData, err := NewGenerator().GetResultsJSON(ctx, session.AWSWorkloadID, session)
+		require.NoError(t, err)
+		var buffered, streamed bytes.Buffer
+		require.NoError(t, core.WriteJSON(&buffered, resultsData))
+		require.NoError(t, jsonFormat.Render(ctx, NewGenerator(), session, &streamed))
+
+		assert.Equal(t, buffered.String(), streamed.String())
+	})
+
+	t.Run("formats without a writer use the generator", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, csvFormat().Render(ctx, NewGenerator(), session, &buf))
+
+		assert.Equal(t, "session_id\nsess-1\n", buf.String())
+	})
+}
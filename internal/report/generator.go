@@ -2,6 +2,7 @@ package report
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/waffle/waffle/internal/core"
 	"github.com/waffle/waffle/internal/wafr"
@@ -19,7 +20,8 @@ func NewGeneratorWithEvaluator(evaluator *wafr.Evaluator) *Generator {
 	}
 }
 
-// NewGenerator creates a new report generator
+// NewGenerator creates a report generator without a WAFR client. It renders
+// reports from stored session data only.
 func NewGenerator() *Generator {
 	return &Generator{}
 }
@@ -30,11 +32,26 @@ func (g *Generator) GetConsolidatedReport(
 	awsWorkloadID string,
 	format core.ReportFormat,
 ) ([]byte, error) {
-	// TODO: Implement in task 19
-	return nil, nil
+	if g.evaluator == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAWSRequired, format)
+	}
+	return g.evaluator.GetConsolidatedReport(ctx, awsWorkloadID, string(format))
+}
+
+// GetConsolidatedReportJSON retrieves the consolidated report metrics of a
+// workload from AWS
+func (g *Generator) GetConsolidatedReportJSON(
+	ctx context.Context,
+	awsWorkloadID string,
+) (*core.ConsolidatedReport, error) {
+	if g.evaluator == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAWSRequired, core.ReportFormatConsolidatedJSON)
+	}
+	return g.evaluator.GetConsolidatedReportMetrics(ctx, awsWorkloadID)
 }
 
-// GetResultsJSON retrieves results in JSON format with IaC evidence
+// GetResultsJSON builds results in JSON format with IaC evidence from the
+// stored session. It makes no AWS calls.
 func (g *Generator) GetResultsJSON(
 	ctx context.Context,
 	awsWorkloadID string,
@@ -50,20 +67,16 @@ func (g *Generator) GetResultsJSON(
 	
 	// Build the results JSON structure
 	results := map[string]interface{}{
+		"schema_version":   core.SchemaVersion,
 		"session_id":       session.SessionID,
 		"workload_id":      session.WorkloadID,
 		"aws_workload_id":  session.AWSWorkloadID,
 		"milestone_id":     session.MilestoneID,
+		"lens_version":     session.LensVersion,
 		"status":           string(session.Status),
 		"created_at":       session.CreatedAt,
 		"updated_at":       session.UpdatedAt,
-		"summary": map[string]interface{}{
-			"questions_evaluated":  session.Results.Summary.QuestionsEvaluated,
-			"high_risks":           session.Results.Summary.HighRisks,
-			"medium_risks":         session.Results.Summary.MediumRisks,
-			"average_confidence":   session.Results.Summary.AverageConfidence,
-			"improvement_plan_size": session.Results.Summary.ImprovementPlanSize,
-		},
+		"summary":          core.ConvertResultsSummaryToOutput(session.Results.Summary),
 	}
 	
 	// Add improvement plan if available
@@ -76,18 +89,29 @@ func (g *Generator) GetResultsJSON(
 				"description":         item.Description,
 				"priority":            item.Priority,
 				"estimated_effort":    item.EstimatedEffort,
+				"blast_radius":        item.BlastRadius,
 				"best_practice_refs":  item.BestPracticeRefs,
 				"affected_resources":  item.AffectedResources,
 			}
+			if item.Remediation != "" {
+				improvementItem["remediation"] = item.Remediation
+			}
 			
 			// Add risk details
 			if item.Risk != nil {
-				improvementItem["risk"] = map[string]interface{}{
+				riskDetails := map[string]interface{}{
 					"id":          item.Risk.ID,
 					"pillar":      item.Risk.Pillar,
 					"severity":    item.Risk.Severity,
 					"description": item.Risk.Description,
 				}
+				if item.Risk.OriginalSeverity != nil {
+					riskDetails["original_severity"] = *item.Risk.OriginalSeverity
+				}
+				if item.Risk.Source != "" {
+					riskDetails["source"] = item.Risk.Source
+				}
+				improvementItem["risk"] = riskDetails
 				
 				// Add question details
 				if item.Risk.Question != nil {
@@ -133,6 +157,9 @@ func (g *Generator) GetResultsJSON(
 				"evidence_count":   len(eval.Evidence),
 				"confidence_score": eval.ConfidenceScore,
 			}
+			if eval.Source != "" {
+				evalSummary["source"] = eval.Source
+			}
 			evaluationsSummary = append(evaluationsSummary, evalSummary)
 		}
 		
@@ -1,11 +1,134 @@
 package report
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/wafr"
 )
 
-// TestPackage ensures the package compiles
-func TestPackage(t *testing.T) {
-	// This test ensures the package compiles correctly
-	// after removing the milestone comparison functionality
+// noCallsWAFRClient fails the test on any WAFR API call: the embedded
+// interface is nil, so calling one of its methods panics
+type noCallsWAFRClient struct {
+	wafr.WAFRClient
+}
+
+func testSession() *core.ReviewSession {
+	return &core.ReviewSession{
+		SessionID:     "sess-1",
+		WorkloadID:    "my-app",
+		AWSWorkloadID: "wl-1",
+		Status:        core.SessionStatusCompleted,
+		Results: &core.ReviewResults{
+			Summary: &core.ResultsSummary{QuestionsEvaluated: 2, HighRisks: 1},
+		},
+	}
+}
+
+func TestGetResultsJSON_NoAWSCalls(t *testing.T) {
+	generators := map[string]*Generator{
+		"offline generator": NewGenerator(),
+		"aws generator":     NewGeneratorWithEvaluator(wafr.NewEvaluator(noCallsWAFRClient{}, nil)),
+	}
+
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			data, err := generateJSON(context.Background(), gen, testSession())
+
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"session_id": "sess-1"`)
+			assert.Contains(t, string(data), `"high_risks": 1`)
+		})
+	}
+}
+
+func TestGetResultsJSON_PillarSummaries(t *testing.T) {
+	session := testSession()
+	session.Results.Summary.PillarSummaries = map[core.Pillar]core.PillarSummary{
+		core.PillarSecurity: {QuestionsEvaluated: 2, HighRisks: 1, Advisory: true},
+	}
+
+	data, err := generateJSON(context.Background(), NewGenerator(), session)
+
+	require.NoError(t, err)
+	var results struct {
+		Summary core.ReviewSummaryOutput `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(data, &results))
+	require.Contains(t, results.Summary.PillarSummaries, "security")
+	assert.Equal(t, 1, results.Summary.PillarSummaries["security"].HighRisks)
+	assert.True(t, results.Summary.PillarSummaries["security"].Advisory)
+}
+
+func TestGetConsolidatedReport_Offline(t *testing.T) {
+	_, err := NewGenerator().GetConsolidatedReport(context.Background(), "wl-1", core.ReportFormatPDF)
+
+	assert.ErrorIs(t, err, ErrAWSRequired)
+}
+
+// consolidatedReportWAFRClient returns a consolidated report in JSON format
+type consolidatedReportWAFRClient struct {
+	wafr.WAFRClient
+}
+
+func (c consolidatedReportWAFRClient) GetConsolidatedReport(ctx context.Context, params *wellarchitected.GetConsolidatedReportInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetConsolidatedReportOutput, error) {
+	return &wellarchitected.GetConsolidatedReportOutput{
+		Metrics: []types.ConsolidatedReportMetric{{
+			WorkloadId:   aws.String("wl-1"),
+			WorkloadName: aws.String("my-app"),
+			MetricType:   types.MetricTypeWorkload,
+			RiskCounts:   map[string]int32{"HIGH": 1, "NONE": 1},
+			Lenses: []types.LensMetric{{
+				LensArn:    aws.String("arn:aws:wellarchitected::aws:lens/wellarchitected"),
+				RiskCounts: map[string]int32{"HIGH": 1, "NONE": 1},
+				Pillars: []types.PillarMetric{{
+					PillarId:   aws.String("security"),
+					RiskCounts: map[string]int32{"HIGH": 1, "NONE": 1},
+					Questions: []types.QuestionMetric{{
+						QuestionId: aws.String("data-rest"),
+						Risk:       types.RiskHigh,
+						BestPractices: []types.BestPractice{{
+							ChoiceId:    aws.String("sec_protect_data_rest_encrypt"),
+							ChoiceTitle: aws.String("Enforce encryption at rest"),
+						}},
+					}},
+				}},
+			}},
+		}},
+	}, nil
+}
+
+func TestGenerateConsolidatedJSON(t *testing.T) {
+	gen := NewGeneratorWithEvaluator(wafr.NewEvaluator(consolidatedReportWAFRClient{}, nil))
+
+	data, err := generateConsolidatedJSON(context.Background(), gen, testSession())
+	require.NoError(t, err)
+
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "wl-1", report["aws_workload_id"])
+	assert.Equal(t, map[string]interface{}{"HIGH": float64(1), "NONE": float64(1)}, report["risk_counts"])
+
+	lenses := report["lenses"].([]interface{})
+	require.Len(t, lenses, 1)
+	pillars := lenses[0].(map[string]interface{})["pillars"].([]interface{})
+	require.Len(t, pillars, 1)
+	pillar := pillars[0].(map[string]interface{})
+	assert.Equal(t, "security", pillar["pillar_id"])
+	question := pillar["questions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "data-rest", question["question_id"])
+	assert.Equal(t, "HIGH", question["risk"])
+}
+
+func TestGenerateConsolidatedJSON_Offline(t *testing.T) {
+	_, err := generateConsolidatedJSON(context.Background(), NewGenerator(), testSession())
+
+	assert.ErrorIs(t, err, ErrAWSRequired)
 }
@@ -3,17 +3,33 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
 )
 
+// sessionIDPattern restricts caller-supplied session IDs to characters that are
+// safe to use as a file name
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// ValidateSessionID checks that a caller-supplied session ID has a valid format
+func ValidateSessionID(sessionID string) error {
+	if !sessionIDPattern.MatchString(sessionID) {
+		return fmt.Errorf("%w: %q must be 1-128 characters of letters, digits, '.', '_' or '-' and start with a letter or digit",
+			core.ErrInvalidSessionID, sessionID)
+	}
+	return nil
+}
+
 // Manager implements the SessionManager interface
 type Manager struct {
 	baseDir string
@@ -40,12 +56,17 @@ func NewManager(baseDir string) (*Manager, error) {
 	}, nil
 }
 
-// CreateSession creates a new review session
+// CreateSession creates a new review session.
+// A non-empty sessionID is validated and used instead of a generated UUID; the
+// session file is created exclusively, so an ID that is already taken fails
+// with core.ErrSessionAlreadyExists. A correlation ID
+// (logging.WithCorrelationID) is recorded on the session.
 func (m *Manager) CreateSession(
 	ctx context.Context,
 	workloadID string,
 	scope core.ReviewScope,
 	awsWorkloadID string,
+	sessionID string,
 ) (*core.ReviewSession, error) {
 	// Validate inputs
 	if workloadID == "" {
@@ -56,12 +77,19 @@ func (m *Manager) CreateSession(
 		return nil, fmt.Errorf("invalid scope: %w", err)
 	}
 
-	// Generate unique session ID
-	sessionID := uuid.New().String()
+	// Use the caller-supplied session ID if present, otherwise generate one
+	if sessionID != "" {
+		if err := ValidateSessionID(sessionID); err != nil {
+			return nil, err
+		}
+	} else {
+		sessionID = uuid.New().String()
+	}
 
 	now := time.Now()
 	session := &core.ReviewSession{
 		SessionID:     sessionID,
+		CorrelationID: logging.GetCorrelationID(ctx),
 		WorkloadID:    workloadID,
 		AWSWorkloadID: awsWorkloadID,
 		Scope:         scope,
@@ -70,13 +98,17 @@ func (m *Manager) CreateSession(
 		UpdatedAt:     now,
 	}
 
-	// Save the session
-	if err := m.SaveSession(ctx, session); err != nil {
+	// Save the session, failing rather than overwriting an existing one
+	if err := m.writeSession(ctx, session, os.O_CREATE|os.O_EXCL|os.O_WRONLY); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("%w: %s", core.ErrSessionAlreadyExists, sessionID)
+		}
 		return nil, fmt.Errorf("failed to save session: %w", err)
 	}
 
 	slog.InfoContext(ctx, "session created",
 		"session_id", sessionID,
+		"correlation_id", session.CorrelationID,
 		"workload_id", workloadID,
 		"aws_workload_id", awsWorkloadID,
 	)
@@ -86,6 +118,11 @@ func (m *Manager) CreateSession(
 
 // SaveSession persists a session to storage
 func (m *Manager) SaveSession(ctx context.Context, session *core.ReviewSession) error {
+	return m.writeSession(ctx, session, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+}
+
+// writeSession encodes a session to its file, opened with flag
+func (m *Manager) writeSession(ctx context.Context, session *core.ReviewSession, flag int) error {
 	if session == nil {
 		return fmt.Errorf("session is nil")
 	}
@@ -100,7 +137,7 @@ func (m *Manager) SaveSession(ctx context.Context, session *core.ReviewSession)
 	sessionPath := m.sessionPath(session.SessionID)
 
 	// Create file with restricted permissions (owner read/write only)
-	f, err := os.OpenFile(sessionPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	f, err := os.OpenFile(sessionPath, flag, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to create session file: %w", err)
 	}
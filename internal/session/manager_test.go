@@ -2,14 +2,18 @@ package session
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/logging"
 )
 
 func TestNewManager(t *testing.T) {
@@ -117,7 +121,7 @@ func TestCreateSession(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			session, err := manager.CreateSession(ctx, tt.workloadID, tt.scope, tt.awsWorkloadID)
+			session, err := manager.CreateSession(ctx, tt.workloadID, tt.scope, tt.awsWorkloadID, "")
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -138,6 +142,90 @@ func TestCreateSession(t *testing.T) {
 	}
 }
 
+func TestCreateSession_CustomSessionID(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+
+	ctx := logging.WithCorrelationID(context.Background(), "pipeline-5678")
+	scope := core.ReviewScope{Level: core.ScopeLevelWorkload}
+
+	session, err := manager.CreateSession(ctx, "test-workload", scope, "aws-wl-123", "ci-run-1234")
+	require.NoError(t, err)
+	assert.Equal(t, "ci-run-1234", session.SessionID)
+	assert.Equal(t, "pipeline-5678", session.CorrelationID)
+
+	loaded, err := manager.LoadSession(context.Background(), "ci-run-1234")
+	require.NoError(t, err)
+	assert.Equal(t, "pipeline-5678", loaded.CorrelationID)
+
+	// Reusing the same ID must not overwrite the existing session
+	_, err = manager.CreateSession(ctx, "other-workload", scope, "aws-wl-456", "ci-run-1234")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, core.ErrSessionAlreadyExists)
+
+	loaded, err = manager.LoadSession(context.Background(), "ci-run-1234")
+	require.NoError(t, err)
+	assert.Equal(t, "test-workload", loaded.WorkloadID)
+}
+
+func TestCreateSession_ConcurrentDuplicateID(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+	scope := core.ReviewScope{Level: core.ScopeLevelWorkload}
+
+	const attempts = 8
+	errs := make(chan error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := manager.CreateSession(context.Background(), fmt.Sprintf("workload-%d", i), scope, "", "ci-run-1234")
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, core.ErrSessionAlreadyExists)
+	}
+	assert.Equal(t, 1, created, "exactly one process may create the session")
+}
+
+func TestValidateSessionID(t *testing.T) {
+	tests := []struct {
+		name      string
+		sessionID string
+		wantErr   bool
+	}{
+		{name: "uuid", sessionID: "3f2b6c1e-8d4a-4b7e-9c2d-1a5e6f7b8c9d", wantErr: false},
+		{name: "pipeline run", sessionID: "gh-actions.run_42", wantErr: false},
+		{name: "path traversal", sessionID: "../escape", wantErr: true},
+		{name: "path separator", sessionID: "a/b", wantErr: true},
+		{name: "leading dot", sessionID: ".hidden", wantErr: true},
+		{name: "whitespace", sessionID: "run 42", wantErr: true},
+		{name: "too long", sessionID: strings.Repeat("a", 129), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSessionID(tt.sessionID)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, core.ErrInvalidSessionID)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestSaveAndLoadSession(t *testing.T) {
 	ctx := context.Background()
 	manager, err := NewManager(t.TempDir())
@@ -149,6 +237,7 @@ func TestSaveAndLoadSession(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -187,6 +276,7 @@ func TestUpdateSessionStatus(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 	assert.Equal(t, core.SessionStatusCreated, session.Status)
@@ -230,6 +320,7 @@ func TestListSessions(t *testing.T) {
 		"workload-1",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-1",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -238,6 +329,7 @@ func TestListSessions(t *testing.T) {
 		"workload-1",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-2",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -246,6 +338,7 @@ func TestListSessions(t *testing.T) {
 		"workload-2",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-3",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -293,6 +386,7 @@ func TestGetAWSWorkloadID(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -322,6 +416,7 @@ func TestDeleteSession(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -360,6 +455,7 @@ func TestListAllSessions(t *testing.T) {
 		"workload-1",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-1",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -368,6 +464,7 @@ func TestListAllSessions(t *testing.T) {
 		"workload-2",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-2",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -376,6 +473,7 @@ func TestListAllSessions(t *testing.T) {
 		"workload-3",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-3",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -405,6 +503,7 @@ func TestSessionPersistence_WithComplexData(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -503,6 +602,7 @@ func TestFilePermissions(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -526,6 +626,7 @@ func TestUpdateMilestoneID(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 	assert.Empty(t, session.MilestoneID)
@@ -563,6 +664,7 @@ func TestUpdateMilestoneID_Multiple(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -596,6 +698,7 @@ func TestSessionPersistence_WithMilestoneID(t *testing.T) {
 		"test-workload",
 		core.ReviewScope{Level: core.ScopeLevelWorkload},
 		"aws-wl-123",
+		"",
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// questionCacheDir is the directory under the session directory that holds
+// cached questions
+const questionCacheDir = "question-cache"
+
+// questionCacheFile is the stored form of a cache entry. The key is kept
+// alongside the questions so a file can be told apart without its name.
+type questionCacheFile struct {
+	Key string `json:"key"`
+	core.CachedQuestions
+}
+
+// QuestionCache implements core.QuestionCache with one JSON file per key
+type QuestionCache struct {
+	dir string
+}
+
+// NewQuestionCache creates a question cache stored alongside the sessions
+// in sessionDir
+func NewQuestionCache(sessionDir string) (*QuestionCache, error) {
+	dir := filepath.Join(sessionDir, questionCacheDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create question cache directory: %w", err)
+	}
+	return &QuestionCache{dir: dir}, nil
+}
+
+// LoadQuestions returns the questions stored under key, or nil when there
+// are none
+func (c *QuestionCache) LoadQuestions(ctx context.Context, key core.QuestionCacheKey) (*core.CachedQuestions, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached questions: %w", err)
+	}
+
+	var file questionCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode cached questions: %w", err)
+	}
+	if file.Key != key.String() {
+		return nil, nil
+	}
+	return &file.CachedQuestions, nil
+}
+
+// StoreQuestions replaces the questions stored under key
+func (c *QuestionCache) StoreQuestions(ctx context.Context, key core.QuestionCacheKey, entry *core.CachedQuestions) error {
+	data, err := json.MarshalIndent(questionCacheFile{Key: key.String(), CachedQuestions: *entry}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cached questions: %w", err)
+	}
+
+	// Write to a temporary file first so a concurrent review never reads a
+	// partial entry
+	path := c.path(key)
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create question cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cached questions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cached questions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cached questions: %w", err)
+	}
+
+	slog.DebugContext(ctx, "questions cached",
+		"key", key.String(),
+		"count", len(entry.Questions),
+	)
+	return nil
+}
+
+// path returns the file of key. Keys are hashed since workload IDs may hold
+// characters that are not safe in file names.
+func (c *QuestionCache) path(key core.QuestionCacheKey) string {
+	sum := sha256.Sum256([]byte(key.String()))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestQuestionCache_StoreAndLoad(t *testing.T) {
+	ctx := context.Background()
+	sessionDir := t.TempDir()
+	cache, err := NewQuestionCache(sessionDir)
+	require.NoError(t, err)
+
+	key := core.QuestionCacheKey{WorkloadID: "payments/api", Pillars: []core.Pillar{core.PillarSecurity}}
+
+	// Nothing is cached yet
+	entry, err := cache.LoadQuestions(ctx, key)
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+
+	fetchedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	stored := &core.CachedQuestions{
+		LensVersion: "2024-06-27",
+		FetchedAt:   fetchedAt,
+		Questions: []*core.WAFRQuestion{{
+			ID:            "securely-operate",
+			Pillar:        core.PillarSecurity,
+			Title:         "How do you securely operate your workload?",
+			BestPractices: []core.BestPractice{{ID: "sec_securely_operate_multi_accounts", Title: "Separate workloads using accounts"}},
+			Choices:       []core.Choice{{ID: "sec_securely_operate_multi_accounts", Title: "Separate workloads using accounts"}},
+		}},
+	}
+	require.NoError(t, cache.StoreQuestions(ctx, key, stored))
+
+	entry, err = cache.LoadQuestions(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "2024-06-27", entry.LensVersion)
+	assert.True(t, fetchedAt.Equal(entry.FetchedAt))
+	assert.Equal(t, stored.Questions, entry.Questions)
+
+	// Other pillar sets of the same workload are separate entries
+	entry, err = cache.LoadQuestions(ctx, core.QuestionCacheKey{WorkloadID: "payments/api", Pillars: core.AllPillars()})
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+
+	// Entries live in their own directory, so sessions are not affected
+	files, err := os.ReadDir(filepath.Join(sessionDir, questionCacheDir))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	manager, err := NewManager(sessionDir)
+	require.NoError(t, err)
+	sessions, err := manager.ListAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestQuestionCache_Replace(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewQuestionCache(t.TempDir())
+	require.NoError(t, err)
+
+	key := core.QuestionCacheKey{WorkloadID: "test-workload", Pillars: core.AllPillars()}
+	require.NoError(t, cache.StoreQuestions(ctx, key, &core.CachedQuestions{
+		LensVersion: "2023-10-03",
+		Questions:   []*core.WAFRQuestion{{ID: "old"}},
+	}))
+	require.NoError(t, cache.StoreQuestions(ctx, key, &core.CachedQuestions{
+		LensVersion: "2024-06-27",
+		Questions:   []*core.WAFRQuestion{{ID: "new"}},
+	}))
+
+	entry, err := cache.LoadQuestions(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "2024-06-27", entry.LensVersion)
+	require.Len(t, entry.Questions, 1)
+	assert.Equal(t, "new", entry.Questions[0].ID)
+}
+
+func TestQuestionCache_Corrupt(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewQuestionCache(t.TempDir())
+	require.NoError(t, err)
+
+	key := core.QuestionCacheKey{WorkloadID: "test-workload", Pillars: core.AllPillars()}
+	require.NoError(t, os.WriteFile(cache.path(key), []byte("{not json"), 0600))
+
+	_, err = cache.LoadQuestions(ctx, key)
+	assert.ErrorContains(t, err, "failed to decode cached questions")
+}
@@ -0,0 +1,52 @@
+package wafr
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// frameworkDocsURL is the base URL of the Well-Architected Framework documentation
+const frameworkDocsURL = "https://docs.aws.amazon.com/wellarchitected/latest/framework"
+
+//go:embed best_practices.txt
+var bestPracticeCatalogData string
+
+// bestPracticeCatalog holds the best-practice IDs that have a framework
+// documentation page
+var bestPracticeCatalog = parseBestPracticeCatalog(bestPracticeCatalogData)
+
+// parseBestPracticeCatalog reads one best-practice ID per line, skipping
+// blank lines and # comments
+func parseBestPracticeCatalog(data string) map[string]bool {
+	catalog := make(map[string]bool)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		catalog[line] = true
+	}
+	return catalog
+}
+
+// IsKnownBestPractice reports whether id is a best practice in the framework catalog
+func IsKnownBestPractice(id string) bool {
+	return bestPracticeCatalog[id]
+}
+
+// bestPracticeURL returns the documentation page of a best practice, or the
+// pillar page when the best practice is not in the catalog
+func bestPracticeURL(pillar core.Pillar, id string) string {
+	if IsKnownBestPractice(id) {
+		return fmt.Sprintf("%s/%s.html", frameworkDocsURL, id)
+	}
+	return pillarURL(pillar)
+}
+
+// pillarURL returns the framework documentation page of a pillar
+func pillarURL(pillar core.Pillar) string {
+	return fmt.Sprintf("%s/%s.html", frameworkDocsURL, getPillarPath(pillar))
+}
@@ -0,0 +1,71 @@
+package wafr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestBestPracticeCatalog(t *testing.T) {
+	assert.Greater(t, len(bestPracticeCatalog), 200)
+	for id := range bestPracticeCatalog {
+		assert.Regexp(t, `^(ops|sec|rel|perf|cost|sus)_[a-z0-9_]+$`, id)
+	}
+}
+
+func TestParseBestPracticeCatalog(t *testing.T) {
+	catalog := parseBestPracticeCatalog("# comment\n\nsec_identities_unique\n  rel_fault_isolation_use_bulkhead  \n")
+
+	assert.Equal(t, map[string]bool{
+		"sec_identities_unique":            true,
+		"rel_fault_isolation_use_bulkhead": true,
+	}, catalog)
+}
+
+func TestExtractBestPracticeRefs(t *testing.T) {
+	tests := []struct {
+		name      string
+		pillar    core.Pillar
+		practices []string
+		want      []string
+	}{
+		{
+			name:      "known best practice links to its page",
+			pillar:    core.PillarSecurity,
+			practices: []string{"sec_protect_data_rest_encrypt"},
+			want:      []string{"https://docs.aws.amazon.com/wellarchitected/latest/framework/sec_protect_data_rest_encrypt.html"},
+		},
+		{
+			name:      "unknown best practice falls back to the pillar page",
+			pillar:    core.PillarReliability,
+			practices: []string{"rel_custom_choice"},
+			want:      []string{"https://docs.aws.amazon.com/wellarchitected/latest/framework/reliability.html"},
+		},
+		{
+			name:      "fallback is listed once",
+			pillar:    core.PillarSecurity,
+			practices: []string{"sec_custom_1", "sec_identities_unique", "sec_custom_2"},
+			want: []string{
+				"https://docs.aws.amazon.com/wellarchitected/latest/framework/security.html",
+				"https://docs.aws.amazon.com/wellarchitected/latest/framework/sec_identities_unique.html",
+			},
+		},
+		{
+			name: "no missing best practices",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk := &core.Risk{Pillar: tt.pillar}
+			for _, id := range tt.practices {
+				risk.MissingBestPractices = append(risk.MissingBestPractices, core.BestPractice{ID: id})
+			}
+
+			assert.Equal(t, tt.want, extractBestPracticeRefs(risk))
+		})
+	}
+}
@@ -3,15 +3,25 @@ package wafr
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
+
+	"github.com/waffle/waffle/internal/cassette"
 )
 
 // ClientConfig holds configuration for AWS client initialization
 type ClientConfig struct {
 	Region  string
 	Profile string
+	// UseFIPS resolves the FIPS endpoint of the Well-Architected Tool
+	UseFIPS bool
+	// HTTPTimeout bounds each HTTP request to the Well-Architected Tool.
+	// Zero leaves requests unbounded.
+	HTTPTimeout time.Duration
 }
 
 // NewWAFRClient creates a new AWS Well-Architected Tool client
@@ -30,15 +40,44 @@ func NewWAFRClient(ctx context.Context, cfg *ClientConfig) (*wellarchitected.Cli
 		configOpts = append(configOpts, config.WithSharedConfigProfile(cfg.Profile))
 	}
 
+	if cfg.UseFIPS {
+		configOpts = append(configOpts, config.WithUseFIPSEndpoint(aws.FIPSEndpointStateEnabled))
+	}
+
+	if cfg.HTTPTimeout > 0 {
+		configOpts = append(configOpts, config.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(cfg.HTTPTimeout)))
+	}
+
 	awsConfig, err := config.LoadDefaultConfig(ctx, configOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
+	if err := cassette.Apply(&awsConfig); err != nil {
+		return nil, err
+	}
 
 	client := wellarchitected.NewFromConfig(awsConfig)
 	return client, nil
 }
 
+// Region returns the AWS region of the Well-Architected Tool client. It is
+// empty for clients that do not expose their options, such as test doubles.
+func (e *Evaluator) Region() string {
+	if client, ok := e.client.(interface{ Options() wellarchitected.Options }); ok {
+		return client.Options().Region
+	}
+	return ""
+}
+
+// UsesFIPSEndpoint reports whether the Well-Architected Tool client resolves
+// FIPS endpoints
+func (e *Evaluator) UsesFIPSEndpoint() bool {
+	if client, ok := e.client.(interface{ Options() wellarchitected.Options }); ok {
+		return client.Options().EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled
+	}
+	return false
+}
+
 // NewEvaluatorWithConfig creates a new WAFR evaluator with AWS client configuration
 func NewEvaluatorWithConfig(ctx context.Context, clientCfg *ClientConfig, evalCfg *EvaluatorConfig) (*Evaluator, error) {
 	client, err := NewWAFRClient(ctx, clientCfg)
@@ -48,3 +87,14 @@ func NewEvaluatorWithConfig(ctx context.Context, clientCfg *ClientConfig, evalCf
 
 	return NewEvaluator(client, evalCfg), nil
 }
+
+// HTTPTimeout returns the timeout of each HTTP request of the
+// Well-Architected Tool client, zero when requests are unbounded
+func (e *Evaluator) HTTPTimeout() time.Duration {
+	if client, ok := e.client.(interface{ Options() wellarchitected.Options }); ok {
+		if httpClient, ok := client.Options().HTTPClient.(interface{ GetTimeout() time.Duration }); ok {
+			return httpClient.GetTimeout()
+		}
+	}
+	return 0
+}
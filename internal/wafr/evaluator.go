@@ -5,27 +5,44 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
 	"github.com/aws/smithy-go"
+	"golang.org/x/time/rate"
 
 	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/metrics"
 )
 
 // WAFRClient defines the interface for AWS Well-Architected Tool operations
 type WAFRClient interface {
 	CreateWorkload(ctx context.Context, params *wellarchitected.CreateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.CreateWorkloadOutput, error)
 	GetWorkload(ctx context.Context, params *wellarchitected.GetWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetWorkloadOutput, error)
+	UpdateWorkload(ctx context.Context, params *wellarchitected.UpdateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateWorkloadOutput, error)
+	DeleteWorkload(ctx context.Context, params *wellarchitected.DeleteWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.DeleteWorkloadOutput, error)
+	GetLens(ctx context.Context, params *wellarchitected.GetLensInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensOutput, error)
+	GetLensReview(ctx context.Context, params *wellarchitected.GetLensReviewInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensReviewOutput, error)
 	ListWorkloads(ctx context.Context, params *wellarchitected.ListWorkloadsInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListWorkloadsOutput, error)
 	ListAnswers(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error)
+	GetAnswer(ctx context.Context, params *wellarchitected.GetAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetAnswerOutput, error)
 	UpdateAnswer(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error)
 	CreateMilestone(ctx context.Context, params *wellarchitected.CreateMilestoneInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.CreateMilestoneOutput, error)
 	GetConsolidatedReport(ctx context.Context, params *wellarchitected.GetConsolidatedReportInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetConsolidatedReportOutput, error)
 }
 
+// Workloads created by Waffle carry this tag so they can be told apart from
+// workloads created by other means
+const (
+	ManagedByTagKey   = "managed-by"
+	ManagedByTagValue = "waffle"
+)
+
 // wrapWAFRError wraps a WAFR API error with additional context
 func wrapWAFRError(operation string, err error) error {
 	if err == nil {
@@ -56,15 +73,66 @@ type BedrockClient interface {
 
 // Evaluator implements the WAFREvaluator interface
 type Evaluator struct {
-	client     WAFRClient
-	maxRetries int
-	baseDelay  time.Duration
+	client                   WAFRClient
+	maxRetries               int
+	baseDelay                time.Duration
+	updateDescriptionOnReuse bool
+	excludeDataSources       bool
+	choiceNotes              bool
+	continueOnPillarError    bool
+	workloadMetadata         *core.WorkloadMetadata
+	questionTimeout          time.Duration
+	metrics                  *metrics.Metrics
+	submitLimiter            *rate.Limiter
+	clock                    core.Clock
+	resourceTypes            *core.ResourceTypeMapping
+	noBuiltInRequirements    bool
+	// severityOverrides is keyed by lowercased question ID, as viper
+	// lowercases the keys of configured maps
+	severityOverrides map[string]core.RiskLevel
 }
 
 // EvaluatorConfig holds configuration for the WAFR evaluator
 type EvaluatorConfig struct {
 	MaxRetries int
 	BaseDelay  time.Duration
+	// UpdateDescriptionOnReuse replaces the description of an existing
+	// workload when CreateWorkload reuses it
+	UpdateDescriptionOnReuse bool
+	// ExcludeDataSources leaves data sources out of the resource count used
+	// for confidence scoring. They are still sent to Bedrock as context.
+	ExcludeDataSources bool
+	// ChoiceNotes attaches the evidence for each selected choice as that
+	// choice's notes instead of folding it all into the question notes
+	ChoiceNotes bool
+	// ContinueOnPillarError skips pillars whose questions cannot be retrieved
+	// during a workload-scope review instead of failing the whole review
+	ContinueOnPillarError bool
+	// WorkloadMetadata sets the review owner and tags of workloads created
+	// by CreateWorkload. Reused workloads keep their existing values.
+	WorkloadMetadata *core.WorkloadMetadata
+	// QuestionTimeout bounds the evaluation of a single question, including
+	// Bedrock retries. A question that exceeds it gets a zero-confidence
+	// evaluation marked TimedOut. Zero disables the limit.
+	QuestionTimeout time.Duration
+	// Metrics records retries and throttling. Nil disables them.
+	Metrics *metrics.Metrics
+	// SubmitRateLimit caps UpdateAnswer calls per second across concurrent
+	// submissions. Zero disables the limit.
+	SubmitRateLimit float64
+	// Clock dates generated milestone names and reports. Nil uses the
+	// system clock.
+	Clock core.Clock
+	// ResourceTypes adds resource types to the built-in types relevant to
+	// each pillar and question. Nil uses the built-in types only.
+	ResourceTypes *core.ResourceTypeMapping
+	// DisableBuiltInRequirements sends the questions with built-in required
+	// resource types to the model even when the workload has none of them.
+	// Requirements from ResourceTypes still apply.
+	DisableBuiltInRequirements bool
+	// SeverityOverrides replaces the severity of the risks of the given
+	// question IDs in the improvement plan
+	SeverityOverrides map[string]core.RiskLevel
 }
 
 // DefaultEvaluatorConfig returns default configuration
@@ -80,13 +148,52 @@ func NewEvaluator(client WAFRClient, config *EvaluatorConfig) *Evaluator {
 	if config == nil {
 		config = DefaultEvaluatorConfig()
 	}
+	m := config.Metrics
+	if m == nil {
+		m = metrics.Disabled()
+	}
+	clock := config.Clock
+	if clock == nil {
+		clock = core.SystemClock{}
+	}
 	return &Evaluator{
-		client:     client,
-		maxRetries: config.MaxRetries,
-		baseDelay:  config.BaseDelay,
+		client:                   client,
+		maxRetries:               config.MaxRetries,
+		baseDelay:                config.BaseDelay,
+		updateDescriptionOnReuse: config.UpdateDescriptionOnReuse,
+		excludeDataSources:       config.ExcludeDataSources,
+		choiceNotes:              config.ChoiceNotes,
+		continueOnPillarError:    config.ContinueOnPillarError,
+		workloadMetadata:         config.WorkloadMetadata,
+		questionTimeout:          config.QuestionTimeout,
+		metrics:                  m,
+		submitLimiter:            newSubmitLimiter(config.SubmitRateLimit),
+		clock:                    clock,
+		resourceTypes:            config.ResourceTypes,
+		noBuiltInRequirements:    config.DisableBuiltInRequirements,
+		severityOverrides:        lowercaseKeys(config.SeverityOverrides),
 	}
 }
 
+// lowercaseKeys returns a copy of severity overrides keyed by lowercased
+// question ID
+func lowercaseKeys(overrides map[string]core.RiskLevel) map[string]core.RiskLevel {
+	lowered := make(map[string]core.RiskLevel, len(overrides))
+	for questionID, severity := range overrides {
+		lowered[strings.ToLower(questionID)] = severity
+	}
+	return lowered
+}
+
+// newSubmitLimiter returns a limiter for UpdateAnswer calls, or nil when
+// perSecond does not set a limit
+func newSubmitLimiter(perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), max(1, int(perSecond)))
+}
+
 // CreateWorkload creates a workload in AWS Well-Architected Tool or returns existing one
 func (e *Evaluator) CreateWorkload(
 	ctx context.Context,
@@ -104,6 +211,7 @@ func (e *Evaluator) CreateWorkload(
 			"workload_id", workloadID,
 			"aws_workload_id", existingWorkloadID,
 		)
+		e.updateReusedWorkloadDescription(ctx, existingWorkloadID, description)
 		return existingWorkloadID, nil
 	}
 
@@ -115,6 +223,10 @@ func (e *Evaluator) CreateWorkload(
 		Lenses:       []string{"wellarchitected"},
 		ReviewOwner:  aws.String("waffle-automated"),
 		AwsRegions:   []string{"us-east-1"}, // Required field
+		Tags:         managedWorkloadTags(e.workloadMetadata.WorkloadTags()),
+	}
+	if e.workloadMetadata != nil && e.workloadMetadata.Owner != "" {
+		input.ReviewOwner = aws.String(e.workloadMetadata.Owner)
 	}
 
 	var output *wellarchitected.CreateWorkloadOutput
@@ -135,6 +247,7 @@ func (e *Evaluator) CreateWorkload(
 					"workload_id", workloadID,
 					"aws_workload_id", existingWorkloadID,
 				)
+				e.updateReusedWorkloadDescription(ctx, existingWorkloadID, description)
 				return existingWorkloadID, nil
 			}
 		}
@@ -150,6 +263,152 @@ func (e *Evaluator) CreateWorkload(
 	return awsWorkloadID, nil
 }
 
+// managedWorkloadTags adds the managed-by tag to the given workload tags
+func managedWorkloadTags(tags map[string]string) map[string]string {
+	managed := make(map[string]string, len(tags)+1)
+	for key, value := range tags {
+		managed[key] = value
+	}
+	managed[ManagedByTagKey] = ManagedByTagValue
+	return managed
+}
+
+// DeleteWorkload deletes a workload created by Waffle. Workloads without the
+// managed-by tag are not deleted and core.ErrWorkloadNotManaged is returned.
+func (e *Evaluator) DeleteWorkload(ctx context.Context, awsWorkloadID string) error {
+	if awsWorkloadID == "" {
+		return errors.New("AWS workload ID is required")
+	}
+
+	var workload *wellarchitected.GetWorkloadOutput
+	err := e.retryWithBackoff(ctx, "GetWorkload", func() error {
+		var err error
+		workload, err = e.client.GetWorkload(ctx, &wellarchitected.GetWorkloadInput{
+			WorkloadId: aws.String(awsWorkloadID),
+		})
+		return err
+	})
+	if err != nil {
+		return wrapWAFRError("GetWorkload", err)
+	}
+	if workload.Workload == nil || workload.Workload.Tags[ManagedByTagKey] != ManagedByTagValue {
+		return fmt.Errorf("%w: %s", core.ErrWorkloadNotManaged, awsWorkloadID)
+	}
+
+	err = e.retryWithBackoff(ctx, "DeleteWorkload", func() error {
+		_, err := e.client.DeleteWorkload(ctx, &wellarchitected.DeleteWorkloadInput{
+			WorkloadId: aws.String(awsWorkloadID),
+		})
+		return err
+	})
+	if err != nil {
+		return wrapWAFRError("DeleteWorkload", err)
+	}
+
+	slog.InfoContext(ctx, "workload deleted",
+		"aws_workload_id", awsWorkloadID,
+	)
+
+	return nil
+}
+
+// UpdateWorkloadDescription replaces the description of an existing workload
+func (e *Evaluator) UpdateWorkloadDescription(
+	ctx context.Context,
+	awsWorkloadID string,
+	description string,
+) error {
+	if awsWorkloadID == "" {
+		return errors.New("AWS workload ID is required")
+	}
+
+	input := &wellarchitected.UpdateWorkloadInput{
+		WorkloadId:  aws.String(awsWorkloadID),
+		Description: aws.String(description),
+	}
+
+	err := e.retryWithBackoff(ctx, "UpdateWorkload", func() error {
+		_, err := e.client.UpdateWorkload(ctx, input)
+		return err
+	})
+	if err != nil {
+		return wrapWAFRError("UpdateWorkload", err)
+	}
+
+	slog.InfoContext(ctx, "workload description updated",
+		"aws_workload_id", awsWorkloadID,
+	)
+
+	return nil
+}
+
+// GetCurrentLensVersion returns the current version of the Well-Architected
+// lens, which new workloads are created with
+func (e *Evaluator) GetCurrentLensVersion(ctx context.Context) (string, error) {
+	input := &wellarchitected.GetLensInput{
+		LensAlias: aws.String("wellarchitected"),
+	}
+
+	var output *wellarchitected.GetLensOutput
+	err := e.retryWithBackoff(ctx, "GetLens", func() error {
+		var err error
+		output, err = e.client.GetLens(ctx, input)
+		return err
+	})
+	if err != nil {
+		return "", wrapWAFRError("GetLens", err)
+	}
+
+	if output.Lens == nil || output.Lens.LensVersion == nil {
+		return "", errors.New("lens has no version")
+	}
+
+	return aws.ToString(output.Lens.LensVersion), nil
+}
+
+// GetLensVersion returns the version of the Well-Architected lens applied to a workload
+func (e *Evaluator) GetLensVersion(ctx context.Context, awsWorkloadID string) (string, error) {
+	if awsWorkloadID == "" {
+		return "", errors.New("AWS workload ID is required")
+	}
+
+	input := &wellarchitected.GetLensReviewInput{
+		WorkloadId: aws.String(awsWorkloadID),
+		LensAlias:  aws.String("wellarchitected"),
+	}
+
+	var output *wellarchitected.GetLensReviewOutput
+	err := e.retryWithBackoff(ctx, "GetLensReview", func() error {
+		var err error
+		output, err = e.client.GetLensReview(ctx, input)
+		return err
+	})
+	if err != nil {
+		return "", wrapWAFRError("GetLensReview", err)
+	}
+
+	if output.LensReview == nil || output.LensReview.LensVersion == nil {
+		return "", errors.New("lens review has no version")
+	}
+
+	return aws.ToString(output.LensReview.LensVersion), nil
+}
+
+// updateReusedWorkloadDescription refreshes the description of a reused
+// workload when configured to. Failures are logged but not returned.
+func (e *Evaluator) updateReusedWorkloadDescription(ctx context.Context, awsWorkloadID, description string) {
+	if !e.updateDescriptionOnReuse || description == "" {
+		return
+	}
+
+	if err := e.UpdateWorkloadDescription(ctx, awsWorkloadID, description); err != nil {
+		slog.WarnContext(ctx, "failed to update description of reused workload",
+			"aws_workload_id", awsWorkloadID,
+			"error", err,
+		)
+	}
+}
+
 // findWorkloadByName searches for a workload by name and returns its ID
 func (e *Evaluator) findWorkloadByName(ctx context.Context, workloadName string) (string, error) {
 	var nextToken *string
@@ -169,7 +428,7 @@ func (e *Evaluator) findWorkloadByName(ctx context.Context, workloadName string)
 		})
 
 		if err != nil {
-			return "", fmt.Errorf("failed to list workloads: %w", err)
+			return "", fmt.Errorf("failed to list workloads: %w", wrapWAFRError("ListWorkloads", err))
 		}
 
 		// Look for exact name match
@@ -188,7 +447,18 @@ func (e *Evaluator) findWorkloadByName(ctx context.Context, workloadName string)
 	return "", fmt.Errorf("workload %s not found", workloadName)
 }
 
-// GetQuestions retrieves WAFR questions based on scope
+// FindWorkload returns the AWS ID of the workload named workloadName
+func (e *Evaluator) FindWorkload(ctx context.Context, workloadName string) (string, error) {
+	if workloadName == "" {
+		return "", errors.New("workload ID is required")
+	}
+	return e.findWorkloadByName(ctx, workloadName)
+}
+
+// GetQuestions retrieves WAFR questions based on scope.
+// When ContinueOnPillarError is set, a workload-scope retrieval that loses some
+// pillars returns the remaining questions together with a
+// *core.PillarRetrievalError naming the failed pillars.
 func (e *Evaluator) GetQuestions(
 	ctx context.Context,
 	awsWorkloadID string,
@@ -203,6 +473,7 @@ func (e *Evaluator) GetQuestions(
 	}
 
 	var questions []*core.WAFRQuestion
+	var pillarErr *core.PillarRetrievalError
 
 	switch scope.Level {
 	case core.ScopeLevelWorkload:
@@ -217,11 +488,27 @@ func (e *Evaluator) GetQuestions(
 		} {
 			pillarQuestions, err := e.getQuestionsForPillar(ctx, awsWorkloadID, pillar)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get questions for pillar %s: %w", pillar, err)
+				if !e.continueOnPillarError {
+					return nil, fmt.Errorf("failed to get questions for pillar %s: %w", pillar, err)
+				}
+				slog.WarnContext(ctx, "skipping pillar after question retrieval failed",
+					"aws_workload_id", awsWorkloadID,
+					"pillar", pillar,
+					"error", err,
+				)
+				if pillarErr == nil {
+					pillarErr = &core.PillarRetrievalError{Failures: make(map[core.Pillar]error)}
+				}
+				pillarErr.Failures[pillar] = err
+				continue
 			}
 			questions = append(questions, pillarQuestions...)
 		}
 
+		if pillarErr != nil && len(questions) == 0 {
+			return nil, pillarErr
+		}
+
 	case core.ScopeLevelPillar:
 		if scope.Pillar == nil {
 			return nil, errors.New("pillar is required for pillar scope")
@@ -249,6 +536,9 @@ func (e *Evaluator) GetQuestions(
 		"question_count", len(questions),
 	)
 
+	if pillarErr != nil {
+		return questions, pillarErr
+	}
 	return questions, nil
 }
 
@@ -280,7 +570,7 @@ func (e *Evaluator) getQuestionsForPillar(
 		})
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list answers: %w", err)
+			return nil, fmt.Errorf("failed to list answers: %w", wrapWAFRError("ListAnswers", err))
 		}
 
 		for _, answer := range output.AnswerSummaries {
@@ -345,17 +635,70 @@ func (e *Evaluator) EvaluateQuestion(
 		return nil, errors.New("bedrock client is required")
 	}
 
+	// A question whose required resource types are all absent does not apply
+	// to the workload and is not sent to the model
+	if required := e.requiredResourceTypes(question.ID); len(required) > 0 && !hasResourceOfTypes(workloadModel, required) {
+		slog.InfoContext(ctx, "question not applicable",
+			"question_id", question.ID,
+			"required_resource_types", required,
+		)
+		return &core.QuestionEvaluation{
+			Question:        question,
+			SelectedChoices: []core.Choice{},
+			Evidence:        []core.Evidence{},
+			ConfidenceScore: 1.0,
+			Notes: fmt.Sprintf("Not applicable: the workload has no resources of type %s",
+				strings.Join(required, ", ")),
+			NotApplicable: true,
+			Source:        core.SourceCustomRule,
+		}, nil
+	}
+
+	// Only resources relevant to the question are sent to the model
+	relevantTypes := e.relevantResourceTypes(question.ID, question.Pillar)
+	scopedModel, considered := questionScopedModel(workloadModel, relevantTypes)
+
 	slog.InfoContext(ctx, "evaluating question",
 		"question_id", question.ID,
 		"pillar", question.Pillar,
 		"resource_count", len(workloadModel.Resources),
+		"deployed_resource_count", countResources(workloadModel, true),
+		"considered_resource_count", len(scopedModel.Resources),
 	)
 
-	// Use Bedrock to evaluate the question
-	evaluation, err := bedrockClient.EvaluateWAFRQuestion(ctx, question, workloadModel)
+	evalCtx := ctx
+	if e.questionTimeout > 0 {
+		var cancel context.CancelFunc
+		evalCtx, cancel = context.WithTimeout(ctx, e.questionTimeout)
+		defer cancel()
+	}
+
+	// Ask the configured model to evaluate the question
+	evaluation, err := bedrockClient.EvaluateWAFRQuestion(evalCtx, question, scopedModel)
 	if err != nil {
+		// Only the question's own deadline counts as a timeout; a cancelled
+		// review is reported as a plain failure
+		if ctx.Err() == nil && errors.Is(evalCtx.Err(), context.DeadlineExceeded) {
+			slog.WarnContext(ctx, "question evaluation timed out",
+				"question_id", question.ID,
+				"timeout", e.questionTimeout,
+			)
+			return &core.QuestionEvaluation{
+				Question:        question,
+				SelectedChoices: []core.Choice{},
+				Evidence:        []core.Evidence{},
+				ConfidenceScore: 0.0,
+				Notes:           fmt.Sprintf("Evaluation timed out after %s", e.questionTimeout),
+				TimedOut:        true,
+				Source:          core.SourceModel,
+
+				ConsideredResources:   considered,
+				RelevantResourceTypes: relevantTypes,
+			}, nil
+		}
+
 		// Handle partial data with low confidence
-		slog.WarnContext(ctx, "bedrock evaluation failed, returning low confidence",
+		slog.WarnContext(ctx, "model evaluation failed, returning low confidence",
 			"question_id", question.ID,
 			"error", err,
 		)
@@ -365,12 +708,24 @@ func (e *Evaluator) EvaluateQuestion(
 			Evidence:        []core.Evidence{},
 			ConfidenceScore: 0.0,
 			Notes:           fmt.Sprintf("Evaluation failed: %v", err),
+			Source:          core.SourceModel,
+
+			ConsideredResources:   considered,
+			RelevantResourceTypes: relevantTypes,
 		}, nil
 	}
-
-	// Calculate confidence score based on data completeness
-	finalConfidence := calculateConfidenceScore(evaluation, workloadModel)
-	evaluation.ConfidenceScore = finalConfidence
+	evaluation.ConsideredResources = considered
+	evaluation.RelevantResourceTypes = relevantTypes
+	evaluation.Source = core.SourceModel
+
+	// Calculate confidence score based on data completeness, keeping the
+	// factors so the score can be explained later
+	breakdown := confidenceBreakdown(evaluation, workloadModel, e.excludeDataSources)
+	evaluation.ConfidenceScore = 0.0
+	if breakdown != nil {
+		evaluation.ConfidenceScore = breakdown.FinalConfidence
+		evaluation.ConfidenceBreakdown = breakdown
+	}
 
 	slog.InfoContext(ctx, "question evaluated",
 		"question_id", question.ID,
@@ -406,7 +761,8 @@ func (e *Evaluator) SubmitAnswer(
 	}
 
 	// Build notes with confidence score and evidence
-	notes := fmt.Sprintf("Automated analysis by Waffle (confidence: %.2f)\n\n%s",
+	notes := fmt.Sprintf("%s (confidence: %.2f)\n\n%s",
+		waffleNotesPrefix,
 		evaluation.ConfidenceScore,
 		evaluation.Notes,
 	)
@@ -416,8 +772,35 @@ func (e *Evaluator) SubmitAnswer(
 		LensAlias:       aws.String("wellarchitected"),
 		QuestionId:      aws.String(questionID),
 		SelectedChoices: selectedChoices,
-		Notes:           aws.String(notes),
-		IsApplicable:    aws.Bool(true),
+		IsApplicable:    aws.Bool(!evaluation.NotApplicable),
+	}
+	if evaluation.NotApplicable {
+		input.Reason = types.AnswerReasonOutOfScope
+	}
+
+	if e.choiceNotes {
+		choiceUpdates, unmatched := buildChoiceUpdates(evaluation)
+		if len(choiceUpdates) > 0 {
+			input.ChoiceUpdates = choiceUpdates
+		}
+		// Evidence that does not belong to a selected choice stays in the question notes
+		if len(unmatched) > 0 {
+			notes += "\n\nAdditional evidence:"
+			for _, ev := range unmatched {
+				notes += "\n- " + formatEvidenceNote(ev)
+			}
+		}
+	}
+	// Notes of an answer being overwritten are kept ahead of Waffle's
+	if evaluation.ExistingNotes != "" {
+		notes = evaluation.ExistingNotes + "\n\n" + notes
+	}
+	input.Notes = aws.String(truncateNote(notes, maxNotesLength))
+
+	if e.submitLimiter != nil {
+		if err := e.submitLimiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit wait failed: %w", err)
+		}
 	}
 
 	err := e.retryWithBackoff(ctx, "UpdateAnswer", func() error {
@@ -439,11 +822,68 @@ func (e *Evaluator) SubmitAnswer(
 	return nil
 }
 
-// GetImprovementPlan retrieves the improvement plan from AWS
+// buildChoiceUpdates pairs evidence with the selected choices via
+// Evidence.ChoiceID. It returns the per-choice updates and the evidence that
+// matched no selected choice.
+func buildChoiceUpdates(evaluation *core.QuestionEvaluation) (map[string]types.ChoiceUpdate, []core.Evidence) {
+	selected := make(map[string]bool, len(evaluation.SelectedChoices))
+	for _, choice := range evaluation.SelectedChoices {
+		selected[choice.ID] = true
+	}
+
+	choiceNotes := make(map[string][]string)
+	var unmatched []core.Evidence
+	for _, ev := range evaluation.Evidence {
+		if !selected[ev.ChoiceID] {
+			unmatched = append(unmatched, ev)
+			continue
+		}
+		choiceNotes[ev.ChoiceID] = append(choiceNotes[ev.ChoiceID], formatEvidenceNote(ev))
+	}
+
+	updates := make(map[string]types.ChoiceUpdate, len(choiceNotes))
+	for choiceID, notes := range choiceNotes {
+		updates[choiceID] = types.ChoiceUpdate{
+			Status: types.ChoiceStatusSelected,
+			Notes:  aws.String(truncateNote(strings.Join(notes, "\n"), maxChoiceNotesLength)),
+		}
+	}
+
+	return updates, unmatched
+}
+
+// maxChoiceNotesLength is the longest choice note the Well-Architected Tool accepts
+const maxChoiceNotesLength = 250
+
+// formatEvidenceNote renders a single piece of evidence for WAFR notes
+func formatEvidenceNote(ev core.Evidence) string {
+	if len(ev.Resources) == 0 {
+		return ev.Explanation
+	}
+	return fmt.Sprintf("%s (%s)", ev.Explanation, strings.Join(ev.Resources, ", "))
+}
+
+// truncateNote shortens a note to at most max bytes, marking the cut with "..."
+func truncateNote(note string, max int) string {
+	if len(note) <= max {
+		return note
+	}
+	cut := max - 3
+	// Do not split a multi-byte character
+	for cut > 0 && !utf8.RuneStart(note[cut]) {
+		cut--
+	}
+	return note[:cut] + "..."
+}
+
+// GetImprovementPlan retrieves the improvement plan from AWS. When a Bedrock
+// client is given, high and medium risks also get remediation guidance for
+// their affected resources.
 func (e *Evaluator) GetImprovementPlan(
 	ctx context.Context,
 	awsWorkloadID string,
 	workloadModel *core.WorkloadModel,
+	bedrockClient BedrockClient,
 ) (*core.ImprovementPlan, error) {
 	if awsWorkloadID == "" {
 		return nil, errors.New("AWS workload ID is required")
@@ -466,15 +906,23 @@ func (e *Evaluator) GetImprovementPlan(
 
 	// Create improvement plan items from risks
 	items := make([]*core.ImprovementPlanItem, 0, len(risks))
-	for i, risk := range risks {
+	for _, risk := range risks {
+		blastRadius := 0
+		if workloadModel != nil {
+			blastRadius = len(workloadModel.Relationships.Dependents(risk.AffectedResources))
+		}
 		item := &core.ImprovementPlanItem{
-			ID:                fmt.Sprintf("improvement-%d", i+1),
+			ID:                core.ImprovementItemID(risk),
 			Risk:              risk,
 			Description:       risk.Description,
 			BestPracticeRefs:  extractBestPracticeRefs(risk),
 			AffectedResources: risk.AffectedResources,
-			Priority:          calculatePriority(risk),
+			Priority:          calculatePriority(risk, blastRadius),
 			EstimatedEffort:   estimateEffort(risk),
+			BlastRadius:       blastRadius,
+		}
+		if bedrockClient != nil && (risk.Severity == core.RiskLevelHigh || risk.Severity == core.RiskLevelMedium) {
+			item.Remediation = e.generateRemediation(ctx, bedrockClient, risk, workloadModel)
 		}
 		items = append(items, item)
 	}
@@ -492,6 +940,51 @@ func (e *Evaluator) GetImprovementPlan(
 	return plan, nil
 }
 
+// generateRemediation asks Bedrock for the changes that address a risk in the
+// affected resources. Failures are logged and leave the item without guidance.
+func (e *Evaluator) generateRemediation(
+	ctx context.Context,
+	bedrockClient BedrockClient,
+	risk *core.Risk,
+	workloadModel *core.WorkloadModel,
+) string {
+	guidance, err := bedrockClient.GenerateImprovementGuidance(ctx, risk, affectedResourceModels(risk, workloadModel))
+	if err != nil {
+		slog.WarnContext(ctx, "failed to generate improvement guidance",
+			"risk_id", risk.ID,
+			"error", err,
+		)
+		return ""
+	}
+	if guidance == nil {
+		return ""
+	}
+	if guidance.Remediation != "" {
+		return guidance.Remediation
+	}
+	return guidance.Description
+}
+
+// affectedResourceModels returns the workload resources listed as affected by a risk
+func affectedResourceModels(risk *core.Risk, workloadModel *core.WorkloadModel) []core.Resource {
+	if workloadModel == nil || len(risk.AffectedResources) == 0 {
+		return nil
+	}
+
+	affected := make(map[string]bool, len(risk.AffectedResources))
+	for _, address := range risk.AffectedResources {
+		affected[address] = true
+	}
+
+	var resources []core.Resource
+	for _, resource := range workloadModel.Resources {
+		if affected[resource.Address] {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
 // getRisksFromAWS retrieves risks identified by AWS Well-Architected Tool
 func (e *Evaluator) getRisksFromAWS(
 	ctx context.Context,
@@ -550,17 +1043,23 @@ func (e *Evaluator) getRisksForPillar(
 		})
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list answers: %w", err)
+			return nil, fmt.Errorf("failed to list answers: %w", wrapWAFRError("ListAnswers", err))
 		}
 
-		// Extract risks from answers
+		// Extract risks from answers. Unanswered questions are kept as
+		// unassessed risks so they are not mistaken for questions without risk.
+		// Overrides apply before risks without severity are dropped, so an
+		// override can raise a question from none or lower one to none.
 		for _, answer := range output.AnswerSummaries {
-			// Only process answers with identified risks
-			if answer.Risk == types.RiskNone || answer.Risk == types.RiskNotApplicable {
+			if answer.Risk == types.RiskNotApplicable {
 				continue
 			}
 
 			risk := convertAnswerToRisk(answer, pillar)
+			e.applySeverityOverride(ctx, risk)
+			if risk.Severity == core.RiskLevelNone {
+				continue
+			}
 			risks = append(risks, risk)
 		}
 
@@ -573,6 +1072,25 @@ func (e *Evaluator) getRisksForPillar(
 	return risks, nil
 }
 
+// applySeverityOverride replaces the severity of a risk whose question has a
+// severity override, keeping the original rating. The risk of an unanswered
+// question is unknown, so it is left unassessed.
+func (e *Evaluator) applySeverityOverride(ctx context.Context, risk *core.Risk) {
+	severity, ok := e.severityOverrides[strings.ToLower(risk.Question.ID)]
+	if !ok || risk.Severity == core.RiskLevelUnassessed || severity == risk.Severity {
+		return
+	}
+
+	slog.InfoContext(ctx, "overriding risk severity",
+		"question_id", risk.Question.ID,
+		"severity", severity,
+		"original_severity", risk.Severity,
+	)
+	original := risk.Severity
+	risk.OriginalSeverity = &original
+	risk.Severity = severity
+}
+
 // convertAnswerToRisk converts AWS answer summary to internal risk format
 func convertAnswerToRisk(answer types.AnswerSummary, pillar core.Pillar) *core.Risk {
 	risk := &core.Risk{
@@ -582,6 +1100,7 @@ func convertAnswerToRisk(answer types.AnswerSummary, pillar core.Pillar) *core.R
 		Description:          buildRiskDescription(answer),
 		AffectedResources:    []string{}, // Will be enhanced later
 		MissingBestPractices: []core.BestPractice{},
+		Source:               core.SourceWAFR,
 	}
 
 	// Create a minimal question reference
@@ -591,6 +1110,12 @@ func convertAnswerToRisk(answer types.AnswerSummary, pillar core.Pillar) *core.R
 		Title:  aws.ToString(answer.QuestionTitle),
 	}
 
+	// An unanswered question has no selected choices, but that says nothing
+	// about which best practices are missing
+	if risk.Severity == core.RiskLevelUnassessed {
+		return risk
+	}
+
 	// Extract missing best practices from choices
 	for _, choice := range answer.Choices {
 		// Choices that are not selected represent missing best practices
@@ -621,7 +1146,9 @@ func mapAWSRiskToRiskLevel(awsRisk types.Risk) core.RiskLevel {
 		return core.RiskLevelHigh
 	case types.RiskMedium:
 		return core.RiskLevelMedium
-	case types.RiskNone, types.RiskNotApplicable, types.RiskUnanswered:
+	case types.RiskUnanswered:
+		return core.RiskLevelUnassessed
+	case types.RiskNone, types.RiskNotApplicable:
 		return core.RiskLevelNone
 	default:
 		return core.RiskLevelNone
@@ -633,6 +1160,10 @@ func buildRiskDescription(answer types.AnswerSummary) string {
 	questionTitle := aws.ToString(answer.QuestionTitle)
 	riskLevel := string(answer.Risk)
 
+	if answer.Risk == types.RiskUnanswered {
+		return fmt.Sprintf("Question not assessed: %s\nAnswer it in the Well-Architected Tool, or rerun the review with this question in scope, to determine its risk.", questionTitle)
+	}
+
 	description := fmt.Sprintf("Risk identified for question: %s (Risk Level: %s)", questionTitle, riskLevel)
 
 	// Add information about missing best practices
@@ -676,7 +1207,7 @@ func (e *Evaluator) findAffectedResources(risk *core.Risk, workloadModel *core.W
 	var affectedResources []string
 
 	// Map question/pillar to relevant resource types
-	relevantTypes := getRelevantResourceTypes(risk.Question.ID, risk.Pillar)
+	relevantTypes := e.relevantResourceTypes(risk.Question.ID, risk.Pillar)
 
 	for _, resource := range workloadModel.Resources {
 		// Check if resource type is relevant to this risk
@@ -691,8 +1222,31 @@ func (e *Evaluator) findAffectedResources(risk *core.Risk, workloadModel *core.W
 	return affectedResources
 }
 
+// relevantResourceTypes returns the built-in resource types relevant to a
+// question/pillar together with those the configured mapping adds
+func (e *Evaluator) relevantResourceTypes(questionID string, pillar core.Pillar) []string {
+	return slices.Concat(getRelevantResourceTypes(questionID, pillar), e.resourceTypes.ResourceTypes(questionID, pillar))
+}
+
+// requiredResourceTypes returns the resource types of which the workload
+// needs one for a question to apply: those the configured mapping requires,
+// or else the built-in ones unless they are disabled
+func (e *Evaluator) requiredResourceTypes(questionID string) []string {
+	if required := e.resourceTypes.RequiredResourceTypes(questionID); required != nil {
+		return required
+	}
+	if e.noBuiltInRequirements {
+		return nil
+	}
+	return questionRequiredResourceTypes[questionID]
+}
+
 // getRelevantResourceTypes returns resource types relevant to a question/pillar
 func getRelevantResourceTypes(questionID string, pillar core.Pillar) []string {
+	if types, ok := questionResourceTypes[questionID]; ok {
+		return types
+	}
+
 	// This is a simplified mapping - in production, this would be more comprehensive
 	switch pillar {
 	case core.PillarSecurity:
@@ -746,6 +1300,21 @@ func getRelevantResourceTypes(questionID string, pillar core.Pillar) []string {
 	}
 }
 
+// ResourceTypePillars returns the pillars whose relevant resource types
+// include resourceType, in framework order
+func ResourceTypePillars(resourceType string) []core.Pillar {
+	var pillars []core.Pillar
+	for _, pillar := range core.AllPillars() {
+		for _, relevantType := range getRelevantResourceTypes("", pillar) {
+			if matchesResourceType(resourceType, relevantType) {
+				pillars = append(pillars, pillar)
+				break
+			}
+		}
+	}
+	return pillars
+}
+
 // matchesResourceType checks if a resource type matches a pattern
 func matchesResourceType(resourceType, pattern string) bool {
 	// Simple prefix matching - could be enhanced with regex
@@ -753,20 +1322,26 @@ func matchesResourceType(resourceType, pattern string) bool {
 		   (len(resourceType) > len(pattern) && resourceType[:len(pattern)] == pattern)
 }
 
-// extractBestPracticeRefs extracts best practice references from a risk
+// extractBestPracticeRefs returns documentation links for the missing best
+// practices of a risk. Best practices outside the catalog link to the pillar
+// page instead, so reports do not contain dead anchors. Unassessed risks link
+// to the pillar page to guide the assessment.
 func extractBestPracticeRefs(risk *core.Risk) []string {
 	refs := make([]string, 0, len(risk.MissingBestPractices))
-	
-	// Generate AWS documentation links for best practices
-	baseURL := "https://docs.aws.amazon.com/wellarchitected/latest/framework"
-	
+	seen := make(map[string]bool, len(risk.MissingBestPractices))
+
 	for _, bp := range risk.MissingBestPractices {
-		// Create a reference URL based on pillar and best practice ID
-		pillarPath := getPillarPath(risk.Pillar)
-		ref := fmt.Sprintf("%s/%s.html#%s", baseURL, pillarPath, bp.ID)
+		ref := bestPracticeURL(risk.Pillar, bp.ID)
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
 		refs = append(refs, ref)
 	}
-	
+	if len(refs) == 0 && risk.Severity == core.RiskLevelUnassessed {
+		refs = append(refs, pillarURL(risk.Pillar))
+	}
+
 	return refs
 }
 
@@ -790,8 +1365,15 @@ func getPillarPath(pillar core.Pillar) string {
 	}
 }
 
-// calculatePriority calculates priority for an improvement item
-func calculatePriority(risk *core.Risk) int {
+// maxBlastRadiusBonus caps the priority a risk gains from its dependents
+// below the smallest gap between severity bases (20), so dependents alone
+// never lift a risk above one of higher severity with as many missing best
+// practices
+const maxBlastRadiusBonus = 18
+
+// calculatePriority calculates priority for an improvement item. blastRadius
+// is the number of resources depending on the affected resources.
+func calculatePriority(risk *core.Risk, blastRadius int) int {
 	// Priority based on severity and number of missing best practices
 	basePriority := 0
 	
@@ -800,6 +1382,8 @@ func calculatePriority(risk *core.Risk) int {
 		basePriority = 100
 	case core.RiskLevelMedium:
 		basePriority = 50
+	case core.RiskLevelUnassessed:
+		basePriority = 30
 	case core.RiskLevelNone:
 		basePriority = 10
 	}
@@ -809,6 +1393,9 @@ func calculatePriority(risk *core.Risk) int {
 	if missingCount > 0 {
 		basePriority += missingCount * 5
 	}
+
+	// Risks on resources much of the workload depends on come first
+	basePriority += min(blastRadius*2, maxBlastRadiusBonus)
 	
 	return basePriority
 }
@@ -841,7 +1428,12 @@ func (e *Evaluator) CreateMilestone(
 	}
 	if milestoneName == "" {
 		// Generate default milestone name with timestamp
-		milestoneName = fmt.Sprintf("waffle-%s", time.Now().Format("2006-01-02-15-04-05"))
+		milestoneName = fmt.Sprintf("waffle-%s", e.clock.Now().Format(core.MilestoneTimestampFormat))
+	}
+	// The API rejects bad names with a ValidationException, which is not
+	// worth a round trip
+	if err := core.ValidateMilestoneName(milestoneName); err != nil {
+		return "", err
 	}
 
 	input := &wellarchitected.CreateMilestoneInput{
@@ -886,7 +1478,11 @@ func (e *Evaluator) retryWithBackoff(ctx context.Context, operation string, fn f
 		if errors.As(err, &apiErr) {
 			switch apiErr.ErrorCode() {
 			case "ThrottlingException", "ServiceUnavailableException", "InternalServerException":
+				if apiErr.ErrorCode() == "ThrottlingException" {
+					e.metrics.Throttles.Inc(metrics.ClientWAFR)
+				}
 				if attempt < e.maxRetries-1 {
+					e.metrics.Retries.Inc(metrics.ClientWAFR, operation)
 					slog.WarnContext(ctx, "retryable error, backing off",
 						"operation", operation,
 						"attempt", attempt+1,
@@ -979,32 +1575,55 @@ func convertChoices(awsChoices []types.Choice) []core.Choice {
 	return choices
 }
 
-// calculateConfidenceScore calculates the final confidence score based on data completeness
-func calculateConfidenceScore(evaluation *core.QuestionEvaluation, workloadModel *core.WorkloadModel) float64 {
-	if evaluation == nil || workloadModel == nil {
+// calculateConfidenceScore calculates the final confidence score based on data completeness.
+// When excludeDataSources is set, data sources do not count as deployed resources.
+func calculateConfidenceScore(evaluation *core.QuestionEvaluation, workloadModel *core.WorkloadModel, excludeDataSources bool) float64 {
+	breakdown := confidenceBreakdown(evaluation, workloadModel, excludeDataSources)
+	if breakdown == nil {
 		return 0.0
 	}
+	return breakdown.FinalConfidence
+}
+
+// confidenceBreakdown derives the final confidence score and records each
+// data completeness factor that went into it
+func confidenceBreakdown(evaluation *core.QuestionEvaluation, workloadModel *core.WorkloadModel, excludeDataSources bool) *core.ConfidenceBreakdown {
+	if evaluation == nil || workloadModel == nil {
+		return nil
+	}
 
 	// Start with the Bedrock-provided confidence
 	baseConfidence := evaluation.ConfidenceScore
 
 	// Adjust based on data completeness factors
-	var adjustments []float64
+	var factors []core.ConfidenceFactor
 
 	// Factor 1: Resource availability (0.0 to 1.0)
-	resourceFactor := 1.0
-	if len(workloadModel.Resources) == 0 {
-		resourceFactor = 0.0
-	} else if len(workloadModel.Resources) < 5 {
+	resourceCount := countResources(workloadModel, excludeDataSources)
+	resourceFactor := core.ConfidenceFactor{
+		Name:       "resource_availability",
+		Multiplier: 1.0,
+		Reason:     fmt.Sprintf("%d resources analyzed", resourceCount),
+	}
+	if resourceCount == 0 {
+		resourceFactor.Multiplier = 0.0
+		resourceFactor.Reason = "no resources found"
+	} else if resourceCount < 5 {
 		// Limited resources may indicate incomplete data
-		resourceFactor = 0.7
+		resourceFactor.Multiplier = 0.7
+		resourceFactor.Reason = fmt.Sprintf("only %d resources analyzed, fewer than 5", resourceCount)
 	}
-	adjustments = append(adjustments, resourceFactor)
+	factors = append(factors, resourceFactor)
 
 	// Factor 2: Evidence quality (0.0 to 1.0)
-	evidenceFactor := 1.0
+	evidenceFactor := core.ConfidenceFactor{
+		Name:       "evidence_quality",
+		Multiplier: 1.0,
+		Reason:     "evidence references specific resources",
+	}
 	if len(evaluation.Evidence) == 0 {
-		evidenceFactor = 0.5 // No evidence reduces confidence
+		evidenceFactor.Multiplier = 0.5 // No evidence reduces confidence
+		evidenceFactor.Reason = "no evidence provided"
 	} else {
 		// Check if evidence has resource references
 		hasResourceRefs := false
@@ -1015,31 +1634,35 @@ func calculateConfidenceScore(evaluation *core.QuestionEvaluation, workloadModel
 			}
 		}
 		if !hasResourceRefs {
-			evidenceFactor = 0.7 // Evidence without resource refs is less reliable
+			evidenceFactor.Multiplier = 0.7 // Evidence without resource refs is less reliable
+			evidenceFactor.Reason = "evidence does not reference resources"
 		}
 	}
-	adjustments = append(adjustments, evidenceFactor)
+	factors = append(factors, evidenceFactor)
 
 	// Factor 3: Source type quality (0.0 to 1.0)
-	sourceFactor := 1.0
+	sourceFactor := core.ConfidenceFactor{Name: "source_type"}
 	if workloadModel.SourceType == "plan" {
 		// Terraform plan has complete data
-		sourceFactor = 1.0
+		sourceFactor.Multiplier = 1.0
+		sourceFactor.Reason = "Terraform plan with computed values"
 	} else if workloadModel.SourceType == "hcl" {
 		// HCL source may have incomplete computed values
-		sourceFactor = 0.85
+		sourceFactor.Multiplier = 0.85
+		sourceFactor.Reason = "HCL source without computed values"
 	} else {
 		// Unknown source type
-		sourceFactor = 0.7
+		sourceFactor.Multiplier = 0.7
+		sourceFactor.Reason = fmt.Sprintf("source type %q", workloadModel.SourceType)
 	}
-	adjustments = append(adjustments, sourceFactor)
+	factors = append(factors, sourceFactor)
 
 	// Calculate weighted average of adjustments
 	totalAdjustment := 0.0
-	for _, adj := range adjustments {
-		totalAdjustment += adj
+	for _, f := range factors {
+		totalAdjustment += f.Multiplier
 	}
-	avgAdjustment := totalAdjustment / float64(len(adjustments))
+	avgAdjustment := totalAdjustment / float64(len(factors))
 
 	// Combine base confidence with adjustments
 	// Use geometric mean to ensure both factors matter
@@ -1053,5 +1676,26 @@ func calculateConfidenceScore(evaluation *core.QuestionEvaluation, workloadModel
 		finalConfidence = 1.0
 	}
 
-	return finalConfidence
+	return &core.ConfidenceBreakdown{
+		BaseConfidence:    baseConfidence,
+		Factors:           factors,
+		AverageMultiplier: avgAdjustment,
+		FinalConfidence:   finalConfidence,
+	}
+}
+
+// countResources returns the number of resources in the workload model,
+// optionally leaving out data sources
+func countResources(workloadModel *core.WorkloadModel, excludeDataSources bool) int {
+	if !excludeDataSources {
+		return len(workloadModel.Resources)
+	}
+
+	count := 0
+	for _, resource := range workloadModel.Resources {
+		if !resource.IsDataSource() {
+			count++
+		}
+	}
+	return count
 }
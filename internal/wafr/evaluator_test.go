@@ -2,6 +2,8 @@ package wafr
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,14 +15,20 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/waffle/waffle/internal/core"
+	"github.com/waffle/waffle/internal/metrics"
 )
 
 // MockWAFRClient implements the WAFRClient interface for testing
 type MockWAFRClient struct {
 	CreateWorkloadFunc         func(ctx context.Context, params *wellarchitected.CreateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.CreateWorkloadOutput, error)
 	GetWorkloadFunc            func(ctx context.Context, params *wellarchitected.GetWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetWorkloadOutput, error)
+	UpdateWorkloadFunc         func(ctx context.Context, params *wellarchitected.UpdateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateWorkloadOutput, error)
+	DeleteWorkloadFunc         func(ctx context.Context, params *wellarchitected.DeleteWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.DeleteWorkloadOutput, error)
+	GetLensFunc                func(ctx context.Context, params *wellarchitected.GetLensInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensOutput, error)
+	GetLensReviewFunc          func(ctx context.Context, params *wellarchitected.GetLensReviewInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensReviewOutput, error)
 	ListWorkloadsFunc          func(ctx context.Context, params *wellarchitected.ListWorkloadsInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListWorkloadsOutput, error)
 	ListAnswersFunc            func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error)
+	GetAnswerFunc              func(ctx context.Context, params *wellarchitected.GetAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetAnswerOutput, error)
 	UpdateAnswerFunc           func(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error)
 	CreateMilestoneFunc        func(ctx context.Context, params *wellarchitected.CreateMilestoneInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.CreateMilestoneOutput, error)
 	GetConsolidatedReportFunc  func(ctx context.Context, params *wellarchitected.GetConsolidatedReportInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetConsolidatedReportOutput, error)
@@ -74,6 +82,38 @@ func (m *MockWAFRClient) GetWorkload(ctx context.Context, params *wellarchitecte
 	}
 }
 
+func (m *MockWAFRClient) UpdateWorkload(ctx context.Context, params *wellarchitected.UpdateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateWorkloadOutput, error) {
+	if m.UpdateWorkloadFunc != nil {
+		return m.UpdateWorkloadFunc(ctx, params, optFns...)
+	}
+	return &wellarchitected.UpdateWorkloadOutput{}, nil
+}
+
+func (m *MockWAFRClient) DeleteWorkload(ctx context.Context, params *wellarchitected.DeleteWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.DeleteWorkloadOutput, error) {
+	if m.DeleteWorkloadFunc != nil {
+		return m.DeleteWorkloadFunc(ctx, params, optFns...)
+	}
+	return &wellarchitected.DeleteWorkloadOutput{}, nil
+}
+
+func (m *MockWAFRClient) GetLens(ctx context.Context, params *wellarchitected.GetLensInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensOutput, error) {
+	if m.GetLensFunc != nil {
+		return m.GetLensFunc(ctx, params, optFns...)
+	}
+	return &wellarchitected.GetLensOutput{
+		Lens: &types.Lens{LensVersion: aws.String("2024-06-27")},
+	}, nil
+}
+
+func (m *MockWAFRClient) GetLensReview(ctx context.Context, params *wellarchitected.GetLensReviewInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensReviewOutput, error) {
+	if m.GetLensReviewFunc != nil {
+		return m.GetLensReviewFunc(ctx, params, optFns...)
+	}
+	return &wellarchitected.GetLensReviewOutput{
+		LensReview: &types.LensReview{LensVersion: aws.String("2024-06-27")},
+	}, nil
+}
+
 func (m *MockWAFRClient) ListWorkloads(ctx context.Context, params *wellarchitected.ListWorkloadsInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListWorkloadsOutput, error) {
 	if m.ListWorkloadsFunc != nil {
 		return m.ListWorkloadsFunc(ctx, params, optFns...)
@@ -92,6 +132,13 @@ func (m *MockWAFRClient) ListAnswers(ctx context.Context, params *wellarchitecte
 	}, nil
 }
 
+func (m *MockWAFRClient) GetAnswer(ctx context.Context, params *wellarchitected.GetAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetAnswerOutput, error) {
+	if m.GetAnswerFunc != nil {
+		return m.GetAnswerFunc(ctx, params, optFns...)
+	}
+	return &wellarchitected.GetAnswerOutput{Answer: &types.Answer{QuestionId: params.QuestionId}}, nil
+}
+
 func (m *MockWAFRClient) UpdateAnswer(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error) {
 	if m.UpdateAnswerFunc != nil {
 		return m.UpdateAnswerFunc(ctx, params, optFns...)
@@ -225,6 +272,290 @@ func TestCreateWorkload(t *testing.T) {
 	}
 }
 
+func TestCreateWorkload_UpdatesDescriptionOnReuse(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateOnReuse bool
+		wantUpdate    bool
+	}{
+		{name: "update enabled", updateOnReuse: true, wantUpdate: true},
+		{name: "update disabled", updateOnReuse: false, wantUpdate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *wellarchitected.UpdateWorkloadInput
+			mockClient := &MockWAFRClient{
+				ListWorkloadsFunc: func(ctx context.Context, params *wellarchitected.ListWorkloadsInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListWorkloadsOutput, error) {
+					return &wellarchitected.ListWorkloadsOutput{
+						WorkloadSummaries: []types.WorkloadSummary{
+							{WorkloadId: aws.String("wl-existing"), WorkloadName: aws.String("test-workload")},
+						},
+					}, nil
+				},
+				UpdateWorkloadFunc: func(ctx context.Context, params *wellarchitected.UpdateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateWorkloadOutput, error) {
+					updated = params
+					return &wellarchitected.UpdateWorkloadOutput{}, nil
+				},
+			}
+
+			evaluator := NewEvaluator(mockClient, &EvaluatorConfig{
+				MaxRetries:               3,
+				BaseDelay:                1 * time.Millisecond,
+				UpdateDescriptionOnReuse: tt.updateOnReuse,
+			})
+
+			awsWorkloadID, err := evaluator.CreateWorkload(context.Background(), "test-workload", "new description")
+
+			require.NoError(t, err)
+			assert.Equal(t, "wl-existing", awsWorkloadID)
+			if tt.wantUpdate {
+				require.NotNil(t, updated)
+				assert.Equal(t, "wl-existing", aws.ToString(updated.WorkloadId))
+				assert.Equal(t, "new description", aws.ToString(updated.Description))
+			} else {
+				assert.Nil(t, updated)
+			}
+		})
+	}
+}
+
+func TestCreateWorkload_WorkloadMetadata(t *testing.T) {
+	tests := []struct {
+		name      string
+		metadata  *core.WorkloadMetadata
+		wantOwner string
+		wantTags  map[string]string
+	}{
+		{
+			name:      "without metadata",
+			wantOwner: "waffle-automated",
+			wantTags:  map[string]string{ManagedByTagKey: ManagedByTagValue},
+		},
+		{
+			name: "with metadata",
+			metadata: &core.WorkloadMetadata{
+				Owner:        "platform-team@example.com",
+				BusinessUnit: "payments",
+				Criticality:  "high",
+				Tags:         map[string]string{"cost-center": "1234"},
+			},
+			wantOwner: "platform-team@example.com",
+			wantTags: map[string]string{
+				"owner":         "platform-team@example.com",
+				"business-unit": "payments",
+				"criticality":   "high",
+				"cost-center":   "1234",
+				ManagedByTagKey: ManagedByTagValue,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input *wellarchitected.CreateWorkloadInput
+			mockClient := &MockWAFRClient{
+				CreateWorkloadFunc: func(ctx context.Context, params *wellarchitected.CreateWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.CreateWorkloadOutput, error) {
+					input = params
+					return &wellarchitected.CreateWorkloadOutput{WorkloadId: aws.String("wl-123")}, nil
+				},
+			}
+
+			evaluator := NewEvaluator(mockClient, &EvaluatorConfig{
+				MaxRetries:       3,
+				BaseDelay:        1 * time.Millisecond,
+				WorkloadMetadata: tt.metadata,
+			})
+
+			_, err := evaluator.CreateWorkload(context.Background(), "test-workload", "test description")
+
+			require.NoError(t, err)
+			require.NotNil(t, input)
+			assert.Equal(t, tt.wantOwner, aws.ToString(input.ReviewOwner))
+			assert.Equal(t, tt.wantTags, input.Tags)
+			assert.Equal(t, "test description", aws.ToString(input.Description))
+		})
+	}
+}
+
+func TestDeleteWorkload(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       map[string]string
+		getErr     error
+		deleteErr  error
+		wantDelete bool
+		wantErr    error
+	}{
+		{
+			name:       "deletes managed workload",
+			tags:       map[string]string{ManagedByTagKey: ManagedByTagValue},
+			wantDelete: true,
+		},
+		{
+			name:    "keeps workload without managed tag",
+			tags:    map[string]string{"owner": "platform-team"},
+			wantErr: core.ErrWorkloadNotManaged,
+		},
+		{
+			name:    "keeps workload managed by another tool",
+			tags:    map[string]string{ManagedByTagKey: "terraform"},
+			wantErr: core.ErrWorkloadNotManaged,
+		},
+		{
+			name:       "delete failure",
+			tags:       map[string]string{ManagedByTagKey: ManagedByTagValue},
+			deleteErr:  &types.AccessDeniedException{Message: aws.String("denied")},
+			wantDelete: true,
+		},
+		{
+			name:   "lookup failure",
+			getErr: &types.ResourceNotFoundException{Message: aws.String("not found")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := ""
+			mockClient := &MockWAFRClient{
+				GetWorkloadFunc: func(ctx context.Context, params *wellarchitected.GetWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetWorkloadOutput, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &wellarchitected.GetWorkloadOutput{
+						Workload: &types.Workload{WorkloadId: params.WorkloadId, Tags: tt.tags},
+					}, nil
+				},
+				DeleteWorkloadFunc: func(ctx context.Context, params *wellarchitected.DeleteWorkloadInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.DeleteWorkloadOutput, error) {
+					deleted = aws.ToString(params.WorkloadId)
+					if tt.deleteErr != nil {
+						return nil, tt.deleteErr
+					}
+					return &wellarchitected.DeleteWorkloadOutput{}, nil
+				},
+			}
+			evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+			err := evaluator.DeleteWorkload(context.Background(), "wl-123")
+
+			if tt.wantDelete {
+				assert.Equal(t, "wl-123", deleted)
+			} else {
+				assert.Empty(t, deleted, "DeleteWorkload should not be called")
+			}
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.getErr != nil || tt.deleteErr != nil:
+				var wafrErr *core.WAFRAPIError
+				assert.ErrorAs(t, err, &wafrErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetCurrentLensVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		mockFunc func(ctx context.Context, params *wellarchitected.GetLensInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensOutput, error)
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "returns current lens version",
+			mockFunc: func(ctx context.Context, params *wellarchitected.GetLensInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensOutput, error) {
+				assert.Equal(t, "wellarchitected", aws.ToString(params.LensAlias))
+				assert.Nil(t, params.LensVersion)
+				return &wellarchitected.GetLensOutput{
+					Lens: &types.Lens{LensVersion: aws.String("2025-02-25")},
+				}, nil
+			},
+			want: "2025-02-25",
+		},
+		{
+			name: "missing version",
+			mockFunc: func(ctx context.Context, params *wellarchitected.GetLensInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensOutput, error) {
+				return &wellarchitected.GetLensOutput{}, nil
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(&MockWAFRClient{GetLensFunc: tt.mockFunc}, &EvaluatorConfig{
+				MaxRetries: 1,
+				BaseDelay:  1 * time.Millisecond,
+			})
+
+			got, err := evaluator.GetCurrentLensVersion(context.Background())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetLensVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		awsWorkloadID string
+		mockFunc      func(ctx context.Context, params *wellarchitected.GetLensReviewInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensReviewOutput, error)
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:          "returns lens review version",
+			awsWorkloadID: "wl-123",
+			mockFunc: func(ctx context.Context, params *wellarchitected.GetLensReviewInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensReviewOutput, error) {
+				assert.Equal(t, "wl-123", aws.ToString(params.WorkloadId))
+				assert.Equal(t, "wellarchitected", aws.ToString(params.LensAlias))
+				return &wellarchitected.GetLensReviewOutput{
+					LensReview: &types.LensReview{LensVersion: aws.String("2025-02-25")},
+				}, nil
+			},
+			want: "2025-02-25",
+		},
+		{
+			name:          "missing version",
+			awsWorkloadID: "wl-123",
+			mockFunc: func(ctx context.Context, params *wellarchitected.GetLensReviewInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetLensReviewOutput, error) {
+				return &wellarchitected.GetLensReviewOutput{}, nil
+			},
+			wantErr: true,
+		},
+		{
+			name:          "empty workload ID",
+			awsWorkloadID: "",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(&MockWAFRClient{GetLensReviewFunc: tt.mockFunc}, &EvaluatorConfig{
+				MaxRetries: 1,
+				BaseDelay:  1 * time.Millisecond,
+			})
+
+			got, err := evaluator.GetLensVersion(context.Background(), tt.awsWorkloadID)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestGetQuestions(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -434,6 +765,80 @@ func TestGetQuestions(t *testing.T) {
 	}
 }
 
+func TestGetQuestions_ContinueOnPillarError(t *testing.T) {
+	listAnswers := func(failing map[string]bool) func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+		return func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+			pillarID := *params.PillarId
+			if failing[pillarID] {
+				return nil, errors.New("AccessDeniedException: not authorized")
+			}
+			return &wellarchitected.ListAnswersOutput{
+				AnswerSummaries: []types.AnswerSummary{
+					{
+						QuestionId:    aws.String(pillarID + "-1"),
+						QuestionTitle: aws.String("Question for " + pillarID),
+					},
+				},
+			}, nil
+		}
+	}
+	scope := core.ReviewScope{Level: core.ScopeLevelWorkload}
+
+	t.Run("disabled aborts on first failure", func(t *testing.T) {
+		evaluator := NewEvaluator(&MockWAFRClient{
+			ListAnswersFunc: listAnswers(map[string]bool{"security": true}),
+		}, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+		questions, err := evaluator.GetQuestions(context.Background(), "workload-123", scope)
+
+		require.Error(t, err)
+		assert.Nil(t, questions)
+		var pillarErr *core.PillarRetrievalError
+		assert.False(t, errors.As(err, &pillarErr))
+	})
+
+	t.Run("enabled skips failing pillar", func(t *testing.T) {
+		evaluator := NewEvaluator(&MockWAFRClient{
+			ListAnswersFunc: listAnswers(map[string]bool{"security": true}),
+		}, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond, ContinueOnPillarError: true})
+
+		questions, err := evaluator.GetQuestions(context.Background(), "workload-123", scope)
+
+		require.Error(t, err)
+		assert.Len(t, questions, 5)
+		for _, q := range questions {
+			assert.NotEqual(t, core.PillarSecurity, q.Pillar)
+		}
+
+		var pillarErr *core.PillarRetrievalError
+		require.True(t, errors.As(err, &pillarErr))
+		require.Len(t, pillarErr.Failures, 1)
+		assert.Contains(t, pillarErr.Failures[core.PillarSecurity].Error(), "AccessDeniedException")
+		assert.Contains(t, err.Error(), string(core.PillarSecurity))
+	})
+
+	t.Run("enabled fails when every pillar fails", func(t *testing.T) {
+		evaluator := NewEvaluator(&MockWAFRClient{
+			ListAnswersFunc: listAnswers(map[string]bool{
+				"operationalExcellence": true,
+				"security":              true,
+				"reliability":           true,
+				"performance":           true,
+				"costOptimization":      true,
+				"sustainability":        true,
+			}),
+		}, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond, ContinueOnPillarError: true})
+
+		questions, err := evaluator.GetQuestions(context.Background(), "workload-123", scope)
+
+		require.Error(t, err)
+		assert.Empty(t, questions)
+		var pillarErr *core.PillarRetrievalError
+		require.True(t, errors.As(err, &pillarErr))
+		assert.Len(t, pillarErr.Failures, 6)
+	})
+}
+
 func TestSubmitAnswer(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -660,6 +1065,154 @@ func TestSubmitAnswer(t *testing.T) {
 	}
 }
 
+func TestSubmitAnswer_ChoiceNotes(t *testing.T) {
+	evaluation := &core.QuestionEvaluation{
+		SelectedChoices: []core.Choice{
+			{ID: "c1", Title: "Choice 1"},
+			{ID: "c2", Title: "Choice 2"},
+		},
+		Evidence: []core.Evidence{
+			{ChoiceID: "c1", Explanation: "Buckets are encrypted", Resources: []string{"aws_s3_bucket.logs"}},
+			{ChoiceID: "c1", Explanation: "KMS key rotation enabled"},
+			{ChoiceID: "c3", Explanation: "No WAF in front of the ALB"},
+		},
+		ConfidenceScore: 0.8,
+		Notes:           "Summary",
+	}
+
+	tests := []struct {
+		name        string
+		choiceNotes bool
+		checkInput  func(t *testing.T, params *wellarchitected.UpdateAnswerInput)
+	}{
+		{
+			name:        "evidence paired with selected choices",
+			choiceNotes: true,
+			checkInput: func(t *testing.T, params *wellarchitected.UpdateAnswerInput) {
+				require.Len(t, params.ChoiceUpdates, 1)
+				update, ok := params.ChoiceUpdates["c1"]
+				require.True(t, ok)
+				assert.Equal(t, types.ChoiceStatusSelected, update.Status)
+				assert.Equal(t, "Buckets are encrypted (aws_s3_bucket.logs)\nKMS key rotation enabled", aws.ToString(update.Notes))
+
+				// Evidence for an unselected choice falls back to the question notes
+				notes := aws.ToString(params.Notes)
+				assert.Contains(t, notes, "Summary")
+				assert.Contains(t, notes, "Additional evidence:\n- No WAF in front of the ALB")
+				assert.NotContains(t, notes, "Buckets are encrypted")
+			},
+		},
+		{
+			name:        "aggregate notes only when disabled",
+			choiceNotes: false,
+			checkInput: func(t *testing.T, params *wellarchitected.UpdateAnswerInput) {
+				assert.Empty(t, params.ChoiceUpdates)
+				assert.NotContains(t, aws.ToString(params.Notes), "Additional evidence")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured *wellarchitected.UpdateAnswerInput
+			mockClient := &MockWAFRClient{
+				UpdateAnswerFunc: func(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error) {
+					captured = params
+					return &wellarchitected.UpdateAnswerOutput{}, nil
+				},
+			}
+
+			evaluator := NewEvaluator(mockClient, &EvaluatorConfig{
+				MaxRetries:  1,
+				BaseDelay:   1 * time.Millisecond,
+				ChoiceNotes: tt.choiceNotes,
+			})
+
+			err := evaluator.SubmitAnswer(context.Background(), "wl-123", "sec-1", evaluation)
+
+			require.NoError(t, err)
+			require.NotNil(t, captured)
+			tt.checkInput(t, captured)
+		})
+	}
+}
+
+// fixedClock always reads the same time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestSubmitAnswer_RateLimit(t *testing.T) {
+	calls := 0
+	mockClient := &MockWAFRClient{
+		UpdateAnswerFunc: func(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error) {
+			calls++
+			return &wellarchitected.UpdateAnswerOutput{}, nil
+		},
+	}
+	evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond, SubmitRateLimit: 1})
+	evaluation := &core.QuestionEvaluation{SelectedChoices: []core.Choice{{ID: "c1"}}}
+
+	require.NoError(t, evaluator.SubmitAnswer(context.Background(), "wl-1", "q1", evaluation))
+
+	// The burst is spent, so the next call waits about a second for a token
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := evaluator.SubmitAnswer(ctx, "wl-1", "q2", evaluation)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit wait failed")
+	assert.Equal(t, 1, calls)
+}
+
+func TestSubmitAnswer_TruncatesAdditionalEvidence(t *testing.T) {
+	evidence := make([]core.Evidence, 0, 40)
+	for i := 0; i < 40; i++ {
+		evidence = append(evidence, core.Evidence{
+			ChoiceID:    "c3",
+			Explanation: strings.Repeat("Load balancer listener accepts plain HTTP ", 2),
+		})
+	}
+
+	var captured *wellarchitected.UpdateAnswerInput
+	mockClient := &MockWAFRClient{
+		UpdateAnswerFunc: func(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error) {
+			captured = params
+			return &wellarchitected.UpdateAnswerOutput{}, nil
+		},
+	}
+	evaluator := NewEvaluator(mockClient, &EvaluatorConfig{
+		MaxRetries:  1,
+		BaseDelay:   time.Millisecond,
+		ChoiceNotes: true,
+	})
+
+	err := evaluator.SubmitAnswer(context.Background(), "wl-123", "sec-1", &core.QuestionEvaluation{
+		SelectedChoices: []core.Choice{{ID: "c1"}},
+		Evidence:        evidence,
+		ConfidenceScore: 0.8,
+		Notes:           "Summary",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, captured)
+	notes := aws.ToString(captured.Notes)
+	assert.Len(t, notes, maxNotesLength)
+	assert.True(t, strings.HasSuffix(notes, "..."))
+	assert.Contains(t, notes, "Additional evidence:")
+}
+
+func TestTruncateNote(t *testing.T) {
+	assert.Equal(t, "short", truncateNote("short", 10))
+	assert.Equal(t, "abcdefg...", truncateNote("abcdefghijklmnop", 10))
+	// A multi-byte character straddling the cut is dropped whole
+	assert.Equal(t, "abcdef...", truncateNote("abcdeféghij", 10))
+}
+
 func TestCreateMilestone(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -709,9 +1262,8 @@ func TestCreateMilestone(t *testing.T) {
 			checkResult: func(t *testing.T, milestoneID string, params *wellarchitected.CreateMilestoneInput) {
 				assert.Equal(t, "2", milestoneID)
 				assert.Contains(t, aws.ToString(params.MilestoneName), "waffle-")
-				// Verify timestamp format (YYYY-MM-DD-HH-MM-SS)
-				name := aws.ToString(params.MilestoneName)
-				assert.Regexp(t, `^waffle-\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}$`, name)
+				// The name is dated by the evaluator's clock
+				assert.Equal(t, "waffle-2026-03-04-05-06-07", aws.ToString(params.MilestoneName))
 			},
 		},
 		{
@@ -781,6 +1333,17 @@ func TestCreateMilestone(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "WAFR CreateMilestone failed",
 		},
+		{
+			name:          "name too long is rejected before calling the API",
+			awsWorkloadID: "wl-123",
+			milestoneName: strings.Repeat("m", 101),
+			mockFunc: func(ctx context.Context, params *wellarchitected.CreateMilestoneInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.CreateMilestoneOutput, error) {
+				t.Error("CreateMilestone must not be called with an invalid name")
+				return nil, &types.ValidationException{Message: aws.String("milestone name too long")}
+			},
+			wantErr:    true,
+			wantErrMsg: "validation failed for milestone_name",
+		},
 		{
 			name:          "service unavailable with retry",
 			awsWorkloadID: "wl-123",
@@ -839,6 +1402,7 @@ func TestCreateMilestone(t *testing.T) {
 			config := &EvaluatorConfig{
 				MaxRetries: 3,
 				BaseDelay:  1 * time.Millisecond,
+				Clock:      fixedClock{now: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)},
 			}
 			evaluator := NewEvaluator(mockClient, config)
 
@@ -941,6 +1505,28 @@ func TestRetryWithBackoff(t *testing.T) {
 	}
 }
 
+func TestRetryWithBackoff_Metrics(t *testing.T) {
+	m := metrics.New(metrics.NewRegistry())
+	evaluator := NewEvaluator(&MockWAFRClient{}, &EvaluatorConfig{
+		MaxRetries: 3,
+		BaseDelay:  time.Millisecond,
+		Metrics:    m,
+	})
+
+	attempts := 0
+	err := evaluator.retryWithBackoff(context.Background(), "ListAnswers", func() error {
+		attempts++
+		if attempts < 3 {
+			return &APIError{code: "ThrottlingException", message: "rate exceeded"}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, m.Retries.Value(metrics.ClientWAFR, "ListAnswers"))
+	assert.Equal(t, 2.0, m.Throttles.Value(metrics.ClientWAFR))
+}
+
 func TestMapPillarToAWSID(t *testing.T) {
 	tests := []struct {
 		pillar core.Pillar
@@ -1019,6 +1605,7 @@ func TestEvaluateQuestion(t *testing.T) {
 			checkResult: func(t *testing.T, eval *core.QuestionEvaluation) {
 				assert.NotNil(t, eval)
 				assert.Equal(t, "sec-1", eval.Question.ID)
+				assert.Equal(t, core.SourceModel, eval.Source)
 				assert.Len(t, eval.SelectedChoices, 1)
 				assert.Len(t, eval.Evidence, 1)
 				assert.Greater(t, eval.ConfidenceScore, 0.0)
@@ -1213,6 +1800,64 @@ func TestEvaluateQuestion(t *testing.T) {
 	}
 }
 
+func TestEvaluateQuestion_Timeout(t *testing.T) {
+	workloadModel := &core.WorkloadModel{
+		Resources: []core.Resource{{ID: "r1", Type: "aws_s3_bucket", Address: "aws_s3_bucket.data"}},
+	}
+	// slow-1 hangs until its context is done, like a stuck Bedrock call
+	bedrockClient := &MockBedrockClient{
+		EvaluateWAFRQuestionFunc: func(ctx context.Context, question *core.WAFRQuestion, workloadModel *core.WorkloadModel) (*core.QuestionEvaluation, error) {
+			if question.ID == "slow-1" {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(10 * time.Second):
+				}
+			}
+			return &core.QuestionEvaluation{
+				Question:        question,
+				SelectedChoices: []core.Choice{{ID: "c1"}},
+				Evidence:        []core.Evidence{{ChoiceID: "c1", Resources: []string{"aws_s3_bucket.data"}, Confidence: 0.9}},
+			}, nil
+		},
+	}
+
+	t.Run("slow question times out without stopping the others", func(t *testing.T) {
+		evaluator := NewEvaluator(&MockWAFRClient{}, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond, QuestionTimeout: 50 * time.Millisecond})
+
+		start := time.Now()
+		var evaluations []*core.QuestionEvaluation
+		for _, id := range []string{"fast-1", "slow-1", "fast-2"} {
+			eval, err := evaluator.EvaluateQuestion(context.Background(), &core.WAFRQuestion{ID: id, Pillar: core.PillarSecurity}, workloadModel, bedrockClient)
+			require.NoError(t, err)
+			evaluations = append(evaluations, eval)
+		}
+
+		assert.Less(t, time.Since(start), 5*time.Second)
+		require.Len(t, evaluations, 3)
+		assert.False(t, evaluations[0].TimedOut)
+		assert.Len(t, evaluations[0].SelectedChoices, 1)
+		assert.True(t, evaluations[1].TimedOut)
+		assert.Equal(t, 0.0, evaluations[1].ConfidenceScore)
+		assert.Empty(t, evaluations[1].SelectedChoices)
+		assert.Equal(t, "Evaluation timed out after 50ms", evaluations[1].Notes)
+		assert.False(t, evaluations[2].TimedOut)
+		assert.Len(t, evaluations[2].SelectedChoices, 1)
+	})
+
+	t.Run("cancelled review is not a timeout", func(t *testing.T) {
+		evaluator := NewEvaluator(&MockWAFRClient{}, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond, QuestionTimeout: time.Minute})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		eval, err := evaluator.EvaluateQuestion(ctx, &core.WAFRQuestion{ID: "slow-1", Pillar: core.PillarSecurity}, workloadModel, bedrockClient)
+
+		require.NoError(t, err)
+		assert.False(t, eval.TimedOut)
+		assert.Contains(t, eval.Notes, "Evaluation failed")
+	})
+}
+
 func TestCalculateConfidenceScore(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -1372,7 +2017,7 @@ func TestCalculateConfidenceScore(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := calculateConfidenceScore(tt.evaluation, tt.workloadModel)
+			got := calculateConfidenceScore(tt.evaluation, tt.workloadModel, false)
 
 			if tt.want != 0.0 {
 				assert.Equal(t, tt.want, got)
@@ -1389,6 +2034,82 @@ func TestCalculateConfidenceScore(t *testing.T) {
 	}
 }
 
+func TestCalculateConfidenceScore_ExcludeDataSources(t *testing.T) {
+	evaluation := &core.QuestionEvaluation{
+		SelectedChoices: []core.Choice{{ID: "c1"}},
+		Evidence: []core.Evidence{
+			{ChoiceID: "c1", Resources: []string{"aws_s3_bucket.logs"}},
+		},
+		ConfidenceScore: 0.9,
+	}
+
+	// Two deployed resources padded out with data sources
+	workloadModel := &core.WorkloadModel{
+		Resources: []core.Resource{
+			{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+			{Address: "aws_kms_key.logs", Type: "aws_kms_key"},
+			{Address: "data.aws_ami.ubuntu", Type: "aws_ami"},
+			{Address: "data.aws_caller_identity.current", Type: "aws_caller_identity"},
+			{Address: "module.vpc.data.aws_availability_zones.available", Type: "aws_availability_zones"},
+		},
+		SourceType: "plan",
+	}
+
+	withDataSources := calculateConfidenceScore(evaluation, workloadModel, false)
+	withoutDataSources := calculateConfidenceScore(evaluation, workloadModel, true)
+
+	// Counting data sources reaches five resources and avoids the penalty
+	assert.InDelta(t, 0.9, withDataSources, 0.0001)
+	// Excluding them leaves two deployed resources, which is penalized
+	assert.InDelta(t, 0.9*(0.7+1.0+1.0)/3, withoutDataSources, 0.0001)
+
+	dataOnly := &core.WorkloadModel{
+		Resources: []core.Resource{
+			{Address: "data.aws_ami.ubuntu", Type: "aws_ami"},
+		},
+		SourceType: "plan",
+	}
+	assert.InDelta(t, 0.9*(0.0+1.0+1.0)/3, calculateConfidenceScore(evaluation, dataOnly, true), 0.0001)
+}
+
+func TestConfidenceBreakdown(t *testing.T) {
+	evaluation := &core.QuestionEvaluation{
+		ConfidenceScore: 0.9,
+		Evidence: []core.Evidence{
+			{ChoiceID: "c1", Explanation: "encrypted"},
+		},
+	}
+	workloadModel := &core.WorkloadModel{
+		SourceType: "hcl",
+		Resources: []core.Resource{
+			{Address: "aws_s3_bucket.a"},
+			{Address: "aws_s3_bucket.b"},
+		},
+	}
+
+	breakdown := confidenceBreakdown(evaluation, workloadModel, false)
+
+	require.NotNil(t, breakdown)
+	assert.Equal(t, 0.9, breakdown.BaseConfidence)
+	require.Len(t, breakdown.Factors, 3)
+
+	assert.Equal(t, "resource_availability", breakdown.Factors[0].Name)
+	assert.Equal(t, 0.7, breakdown.Factors[0].Multiplier)
+	assert.Contains(t, breakdown.Factors[0].Reason, "2 resources")
+
+	assert.Equal(t, "evidence_quality", breakdown.Factors[1].Name)
+	assert.Equal(t, 0.7, breakdown.Factors[1].Multiplier)
+	assert.Equal(t, "evidence does not reference resources", breakdown.Factors[1].Reason)
+
+	assert.Equal(t, "source_type", breakdown.Factors[2].Name)
+	assert.Equal(t, 0.85, breakdown.Factors[2].Multiplier)
+
+	assert.InDelta(t, (0.7+0.7+0.85)/3, breakdown.AverageMultiplier, 0.0001)
+	assert.InDelta(t, 0.9*(0.7+0.7+0.85)/3, breakdown.FinalConfidence, 0.0001)
+	assert.Equal(t, breakdown.FinalConfidence, calculateConfidenceScore(evaluation, workloadModel, false))
+	assert.Nil(t, confidenceBreakdown(evaluation, nil, false))
+}
+
 func TestGetImprovementPlan(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -1452,6 +2173,7 @@ func TestGetImprovementPlan(t *testing.T) {
 				assert.NotNil(t, item.Risk)
 				assert.Equal(t, core.RiskLevelHigh, item.Risk.Severity)
 				assert.Equal(t, core.PillarSecurity, item.Risk.Pillar)
+				assert.Equal(t, core.SourceWAFR, item.Risk.Source)
 				assert.NotEmpty(t, item.Description)
 				assert.NotEmpty(t, item.BestPracticeRefs)
 				assert.Greater(t, item.Priority, 0)
@@ -1497,6 +2219,45 @@ func TestGetImprovementPlan(t *testing.T) {
 				assert.Len(t, plan.Items, 0) // No risks, no improvement items
 			},
 		},
+		{
+			name:          "unanswered questions are unassessed risks",
+			awsWorkloadID: "wl-123",
+			workloadModel: &core.WorkloadModel{},
+			mockFunc: func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+				if aws.ToString(params.PillarId) != "reliability" {
+					return &wellarchitected.ListAnswersOutput{}, nil
+				}
+				return &wellarchitected.ListAnswersOutput{
+					AnswerSummaries: []types.AnswerSummary{
+						{
+							QuestionId:      aws.String("rel-1"),
+							QuestionTitle:   aws.String("How do you back up data?"),
+							Risk:            types.RiskUnanswered,
+							Choices:         []types.Choice{{ChoiceId: aws.String("c1"), Title: aws.String("Back up data")}},
+							SelectedChoices: []string{},
+						},
+						{
+							QuestionId:    aws.String("rel-2"),
+							QuestionTitle: aws.String("How do you test reliability?"),
+							Risk:          types.RiskNotApplicable,
+						},
+					},
+				}, nil
+			},
+			checkResult: func(t *testing.T, plan *core.ImprovementPlan) {
+				require.NotNil(t, plan)
+				require.Len(t, plan.Items, 1)
+
+				item := plan.Items[0]
+				assert.Equal(t, core.RiskLevelUnassessed, item.Risk.Severity)
+				assert.Equal(t, "rel-1", item.Risk.Question.ID)
+				assert.Contains(t, item.Description, "Question not assessed: How do you back up data?")
+				assert.Contains(t, item.Description, "Answer it in the Well-Architected Tool")
+				assert.Empty(t, item.Risk.MissingBestPractices, "unselected choices of an unanswered question are not missing best practices")
+				assert.Equal(t, []string{pillarURL(core.PillarReliability)}, item.BestPracticeRefs)
+				assert.Equal(t, 30, item.Priority)
+			},
+		},
 		{
 			name:          "multiple risks across pillars",
 			awsWorkloadID: "wl-123",
@@ -1807,7 +2568,7 @@ func TestGetImprovementPlan(t *testing.T) {
 			}
 			evaluator := NewEvaluator(mockClient, config)
 
-			plan, err := evaluator.GetImprovementPlan(context.Background(), tt.awsWorkloadID, tt.workloadModel)
+			plan, err := evaluator.GetImprovementPlan(context.Background(), tt.awsWorkloadID, tt.workloadModel, nil)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -1824,6 +2585,89 @@ func TestGetImprovementPlan(t *testing.T) {
 	}
 }
 
+func TestGetImprovementPlan_RemediationGuidance(t *testing.T) {
+	workloadModel := &core.WorkloadModel{
+		Resources: []core.Resource{
+			{ID: "r1", Type: "aws_s3_bucket", Address: "aws_s3_bucket.example"},
+			{ID: "r2", Type: "aws_kms_key", Address: "aws_kms_key.example"},
+			{ID: "r3", Type: "aws_instance", Address: "aws_instance.web"},
+		},
+	}
+	mockClient := &MockWAFRClient{
+		ListAnswersFunc: func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+			if aws.ToString(params.PillarId) != "security" {
+				return &wellarchitected.ListAnswersOutput{}, nil
+			}
+			return &wellarchitected.ListAnswersOutput{
+				AnswerSummaries: []types.AnswerSummary{
+					{
+						QuestionId:    aws.String("sec-1"),
+						QuestionTitle: aws.String("How do you protect your data at rest?"),
+						Risk:          types.RiskHigh,
+					},
+					{
+						QuestionId:    aws.String("sec-2"),
+						QuestionTitle: aws.String("How do you protect your data in transit?"),
+						Risk:          types.RiskMedium,
+					},
+					{
+						QuestionId:    aws.String("sec-3"),
+						QuestionTitle: aws.String("How do you manage identities?"),
+						Risk:          types.RiskNone,
+					},
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name            string
+		guidanceErr     error
+		wantRemediation string
+	}{
+		{
+			name:            "guidance lands on high and medium risks",
+			wantRemediation: "Add encryption to aws_s3_bucket.example",
+		},
+		{
+			name:        "guidance failure leaves items without remediation",
+			guidanceErr: errors.New("throttled"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var guided []string
+			var resources [][]core.Resource
+			bedrockClient := &MockBedrockClient{
+				GenerateImprovementGuidanceFunc: func(ctx context.Context, risk *core.Risk, affected []core.Resource) (*core.ImprovementPlanItem, error) {
+					guided = append(guided, risk.Question.ID)
+					resources = append(resources, affected)
+					if tt.guidanceErr != nil {
+						return nil, tt.guidanceErr
+					}
+					return &core.ImprovementPlanItem{Remediation: "Add encryption to aws_s3_bucket.example"}, nil
+				},
+			}
+			evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+			plan, err := evaluator.GetImprovementPlan(context.Background(), "wl-123", workloadModel, bedrockClient)
+
+			require.NoError(t, err)
+			require.NotNil(t, plan)
+			assert.Equal(t, []string{"sec-1", "sec-2"}, guided)
+			for _, affected := range resources {
+				for _, resource := range affected {
+					assert.NotEqual(t, "aws_instance.web", resource.Address, "only affected resources are sent")
+				}
+			}
+			for _, item := range plan.Items {
+				assert.Equal(t, tt.wantRemediation, item.Remediation, item.Risk.Question.ID)
+			}
+		})
+	}
+}
+
 func TestMapAWSRiskToRiskLevel(t *testing.T) {
 	tests := []struct {
 		awsRisk types.Risk
@@ -1833,7 +2677,7 @@ func TestMapAWSRiskToRiskLevel(t *testing.T) {
 		{types.RiskMedium, core.RiskLevelMedium},
 		{types.RiskNone, core.RiskLevelNone},
 		{types.RiskNotApplicable, core.RiskLevelNone},
-		{types.RiskUnanswered, core.RiskLevelNone},
+		{types.RiskUnanswered, core.RiskLevelUnassessed},
 	}
 
 	for _, tt := range tests {
@@ -1969,6 +2813,14 @@ func TestCalculatePriority(t *testing.T) {
 			wantMin: 50,
 			wantMax: 60,
 		},
+		{
+			name: "unassessed",
+			risk: &core.Risk{
+				Severity: core.RiskLevelUnassessed,
+			},
+			wantMin: 30,
+			wantMax: 30,
+		},
 		{
 			name: "low severity",
 			risk: &core.Risk{
@@ -1982,7 +2834,7 @@ func TestCalculatePriority(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := calculatePriority(tt.risk)
+			got := calculatePriority(tt.risk, 0)
 			assert.GreaterOrEqual(t, got, tt.wantMin)
 			assert.LessOrEqual(t, got, tt.wantMax)
 		})
@@ -2037,3 +2889,536 @@ func TestEstimateEffort(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceTypePillars(t *testing.T) {
+	assert.Equal(t, []core.Pillar{core.PillarSecurity, core.PillarCostOptimization}, ResourceTypePillars("aws_s3_bucket"))
+	assert.Equal(t, []core.Pillar{core.PillarPerformanceEfficiency, core.PillarCostOptimization, core.PillarSustainability}, ResourceTypePillars("aws_instance"))
+	assert.Empty(t, ResourceTypePillars("aws_sqs_queue"))
+}
+
+func TestResourceTypeRelevance(t *testing.T) {
+	mapping := &core.ResourceTypeMapping{
+		Pillars:   map[core.Pillar][]string{core.PillarReliability: {"aws_sqs"}},
+		Questions: map[string][]string{"custom-queues": {"aws_sqs_queue"}},
+	}
+
+	tests := []struct {
+		name         string
+		resourceType string
+		mapping      *core.ResourceTypeMapping
+		want         []ResourceTypeMatch
+	}{
+		{
+			name:         "built-in pillars and questions",
+			resourceType: "aws_s3_bucket",
+			want: []ResourceTypeMatch{
+				{Pillar: core.PillarSecurity, MatchedType: "aws_s3_bucket"},
+				{Pillar: core.PillarCostOptimization, MatchedType: "aws_s3_bucket"},
+				{QuestionID: "data-rest", MatchedType: "aws_s3_bucket"},
+			},
+		},
+		{
+			name:         "prefix match",
+			resourceType: "aws_s3_bucket_policy",
+			want: []ResourceTypeMatch{
+				{Pillar: core.PillarSecurity, MatchedType: "aws_s3_bucket"},
+				{Pillar: core.PillarCostOptimization, MatchedType: "aws_s3_bucket"},
+				{QuestionID: "data-rest", MatchedType: "aws_s3_bucket"},
+				{QuestionID: "data-transit", MatchedType: "aws_s3_bucket_policy"},
+			},
+		},
+		{
+			name:         "unmapped type",
+			resourceType: "aws_sqs_queue",
+			want: []ResourceTypeMatch{
+				{QuestionID: "data-rest", MatchedType: "aws_sqs_queue"},
+			},
+		},
+		{
+			name:         "types from the mapping",
+			resourceType: "aws_sqs_queue",
+			mapping:      mapping,
+			want: []ResourceTypeMatch{
+				{Pillar: core.PillarReliability, MatchedType: "aws_sqs", FromMapping: true},
+				{QuestionID: "custom-queues", MatchedType: "aws_sqs_queue", FromMapping: true},
+				{QuestionID: "data-rest", MatchedType: "aws_sqs_queue"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ResourceTypeRelevance(tt.resourceType, tt.mapping))
+		})
+	}
+}
+
+func TestEvaluateQuestion_ScopedResources(t *testing.T) {
+	resources := []core.Resource{
+		{Address: "aws_s3_bucket.data", Type: "aws_s3_bucket"},
+		{Address: "aws_kms_key.data", Type: "aws_kms_key"},
+		{
+			Address:      "aws_s3_bucket_server_side_encryption_configuration.data",
+			Type:         "aws_s3_bucket_server_side_encryption_configuration",
+			Dependencies: []string{"aws_s3_bucket.data", "aws_kms_key.data"},
+		},
+		{Address: "aws_vpc.main", Type: "aws_vpc"},
+		{Address: "aws_security_group.db", Type: "aws_security_group", Dependencies: []string{"aws_vpc.main"}},
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Dependencies: []string{"aws_security_group.db"}},
+		{Address: "aws_iam_role.app", Type: "aws_iam_role"},
+		{Address: "aws_instance.web", Type: "aws_instance", Dependencies: []string{"aws_iam_role.app"}},
+		{Address: "aws_cloudwatch_log_group.app", Type: "aws_cloudwatch_log_group"},
+	}
+	withGraph := func() *core.WorkloadModel {
+		model := &core.WorkloadModel{
+			Resources:     append([]core.Resource(nil), resources...),
+			Relationships: &core.ResourceGraph{Nodes: make(map[string]*core.Resource), Edges: make(map[string][]string)},
+		}
+		for i := range model.Resources {
+			resource := &model.Resources[i]
+			model.Relationships.Nodes[resource.Address] = resource
+			if len(resource.Dependencies) > 0 {
+				model.Relationships.Edges[resource.Address] = resource.Dependencies
+			}
+		}
+		return model
+	}
+	// Encryption-relevant resources plus their direct neighbors; the VPC is
+	// two hops from the database and left out
+	encryptionResources := []string{
+		"aws_db_instance.main",
+		"aws_kms_key.data",
+		"aws_s3_bucket.data",
+		"aws_s3_bucket_server_side_encryption_configuration.data",
+		"aws_security_group.db",
+	}
+
+	tests := []struct {
+		name           string
+		question       *core.WAFRQuestion
+		model          func() *core.WorkloadModel
+		wantPrompt     []string
+		wantConsidered []string
+	}{
+		{
+			name:           "encryption question",
+			question:       &core.WAFRQuestion{ID: "data-rest", Pillar: core.PillarSecurity, Title: "How do you protect your data at rest?"},
+			model:          withGraph,
+			wantPrompt:     encryptionResources,
+			wantConsidered: encryptionResources,
+		},
+		{
+			name:     "dependencies without a resource graph",
+			question: &core.WAFRQuestion{ID: "data-rest", Pillar: core.PillarSecurity, Title: "How do you protect your data at rest?"},
+			model: func() *core.WorkloadModel {
+				return &core.WorkloadModel{Resources: append([]core.Resource(nil), resources...)}
+			},
+			wantPrompt:     encryptionResources,
+			wantConsidered: encryptionResources,
+		},
+		{
+			name:     "no relevant resources",
+			question: &core.WAFRQuestion{ID: "backing-up-data", Pillar: core.PillarReliability, Title: "How do you back up data?"},
+			model: func() *core.WorkloadModel {
+				return &core.WorkloadModel{Resources: append([]core.Resource(nil), resources[:2]...)}
+			},
+			wantPrompt: []string{"aws_kms_key.data", "aws_s3_bucket.data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompted *core.WorkloadModel
+			bedrockClient := &MockBedrockClient{
+				EvaluateWAFRQuestionFunc: func(ctx context.Context, question *core.WAFRQuestion, workloadModel *core.WorkloadModel) (*core.QuestionEvaluation, error) {
+					prompted = workloadModel
+					return &core.QuestionEvaluation{Question: question, ConfidenceScore: 0.8}, nil
+				},
+			}
+
+			model := tt.model()
+			evaluator := NewEvaluator(&MockWAFRClient{}, nil)
+			evaluation, err := evaluator.EvaluateQuestion(context.Background(), tt.question, model, bedrockClient)
+			require.NoError(t, err)
+			require.NotNil(t, prompted)
+
+			var addresses []string
+			for _, resource := range prompted.Resources {
+				addresses = append(addresses, resource.Address)
+			}
+			assert.ElementsMatch(t, tt.wantPrompt, addresses)
+			assert.Equal(t, tt.wantConsidered, evaluation.ConsideredResources)
+
+			if prompted.Relationships != nil {
+				// The graph only links resources that were sent
+				for from, dependencies := range prompted.Relationships.Edges {
+					assert.Contains(t, addresses, from)
+					assert.Subset(t, addresses, dependencies)
+				}
+			}
+			// The caller's model is left as it was
+			assert.Len(t, model.Resources, len(tt.model().Resources))
+		})
+	}
+}
+
+func TestEvaluateQuestion_RequiredResourceTypes(t *testing.T) {
+	mapping := &core.ResourceTypeMapping{Requires: map[string][]string{
+		"serverless-functions": {"aws_lambda_function"},
+		"container-workloads":  {"aws_ecs", "aws_eks"},
+	}}
+	// A workload without Lambda functions or containers
+	vmWorkload := &core.WorkloadModel{Resources: []core.Resource{
+		{Address: "aws_instance.web", Type: "aws_instance"},
+		{Address: "aws_s3_bucket.data", Type: "aws_s3_bucket"},
+	}}
+	serverless := &core.WAFRQuestion{ID: "serverless-functions", Pillar: core.PillarPerformanceEfficiency, Title: "How do you run functions?"}
+	containers := &core.WAFRQuestion{ID: "container-workloads", Pillar: core.PillarReliability, Title: "How do you run containers?"}
+	general := &core.WAFRQuestion{ID: "data-rest", Pillar: core.PillarSecurity, Title: "How do you protect your data at rest?"}
+
+	tests := []struct {
+		name              string
+		question          *core.WAFRQuestion
+		model             *core.WorkloadModel
+		wantNotApplicable bool
+		wantNotes         string
+	}{
+		{
+			name:              "serverless question without Lambda functions",
+			question:          serverless,
+			model:             vmWorkload,
+			wantNotApplicable: true,
+			wantNotes:         "no resources of type aws_lambda_function",
+		},
+		{
+			name:              "container question without containers",
+			question:          containers,
+			model:             vmWorkload,
+			wantNotApplicable: true,
+			wantNotes:         "no resources of type aws_ecs, aws_eks",
+		},
+		{
+			name:     "question without requirements",
+			question: general,
+			model:    vmWorkload,
+		},
+		{
+			name:     "container question with an EKS cluster",
+			question: containers,
+			model: &core.WorkloadModel{Resources: []core.Resource{
+				{Address: "aws_eks_cluster.main", Type: "aws_eks_cluster"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			bedrockClient := &MockBedrockClient{
+				EvaluateWAFRQuestionFunc: func(ctx context.Context, question *core.WAFRQuestion, workloadModel *core.WorkloadModel) (*core.QuestionEvaluation, error) {
+					called = true
+					return &core.QuestionEvaluation{Question: question, ConfidenceScore: 0.8}, nil
+				},
+			}
+
+			evaluator := NewEvaluator(&MockWAFRClient{}, &EvaluatorConfig{ResourceTypes: mapping})
+			evaluation, err := evaluator.EvaluateQuestion(context.Background(), tt.question, tt.model, bedrockClient)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantNotApplicable, evaluation.NotApplicable)
+			assert.Equal(t, !tt.wantNotApplicable, called, "inapplicable questions are not sent to the model")
+			if tt.wantNotApplicable {
+				assert.Empty(t, evaluation.SelectedChoices)
+				assert.Contains(t, evaluation.Notes, tt.wantNotes)
+				assert.Equal(t, core.SourceCustomRule, evaluation.Source)
+			} else {
+				assert.Equal(t, core.SourceModel, evaluation.Source)
+			}
+		})
+	}
+}
+
+func TestEvaluateQuestion_BuiltInRequiredResourceTypes(t *testing.T) {
+	protectCompute := &core.WAFRQuestion{ID: "protect-compute", Pillar: core.PillarSecurity, Title: "How do you protect your compute resources?"}
+	storageOnly := &core.WorkloadModel{Resources: []core.Resource{
+		{Address: "aws_s3_bucket.site", Type: "aws_s3_bucket"},
+		{Address: "aws_cloudfront_distribution.site", Type: "aws_cloudfront_distribution"},
+	}}
+
+	tests := []struct {
+		name              string
+		mapping           *core.ResourceTypeMapping
+		disableBuiltIn    bool
+		model             *core.WorkloadModel
+		wantNotApplicable bool
+	}{
+		{
+			name:              "no compute resources",
+			model:             storageOnly,
+			wantNotApplicable: true,
+		},
+		{
+			name: "serverless functions",
+			model: &core.WorkloadModel{Resources: []core.Resource{
+				{Address: "aws_lambda_function.api", Type: "aws_lambda_function"},
+			}},
+		},
+		{
+			name: "containers",
+			model: &core.WorkloadModel{Resources: []core.Resource{
+				{Address: "aws_ecs_service.api", Type: "aws_ecs_service"},
+			}},
+		},
+		{
+			name:    "mapping replaces the built-in requirement",
+			mapping: &core.ResourceTypeMapping{Requires: map[string][]string{"protect-compute": {"aws_cloudfront"}}},
+			model:   storageOnly,
+		},
+		{
+			name:           "built-in requirements disabled",
+			disableBuiltIn: true,
+			model:          storageOnly,
+		},
+		{
+			name:              "mapping requirement with built-in requirements disabled",
+			mapping:           &core.ResourceTypeMapping{Requires: map[string][]string{"protect-compute": {"aws_instance"}}},
+			disableBuiltIn:    true,
+			model:             storageOnly,
+			wantNotApplicable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bedrockClient := &MockBedrockClient{
+				EvaluateWAFRQuestionFunc: func(ctx context.Context, question *core.WAFRQuestion, workloadModel *core.WorkloadModel) (*core.QuestionEvaluation, error) {
+					return &core.QuestionEvaluation{Question: question, ConfidenceScore: 0.8}, nil
+				},
+			}
+
+			evaluator := NewEvaluator(&MockWAFRClient{}, &EvaluatorConfig{ResourceTypes: tt.mapping, DisableBuiltInRequirements: tt.disableBuiltIn})
+			evaluation, err := evaluator.EvaluateQuestion(context.Background(), protectCompute, tt.model, bedrockClient)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantNotApplicable, evaluation.NotApplicable)
+		})
+	}
+}
+
+func TestSubmitAnswer_NotApplicable(t *testing.T) {
+	var input *wellarchitected.UpdateAnswerInput
+	mockClient := &MockWAFRClient{
+		UpdateAnswerFunc: func(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error) {
+			input = params
+			return &wellarchitected.UpdateAnswerOutput{}, nil
+		},
+	}
+
+	evaluator := NewEvaluator(mockClient, nil)
+	err := evaluator.SubmitAnswer(context.Background(), "workload-123", "serverless-functions", &core.QuestionEvaluation{
+		Question:        &core.WAFRQuestion{ID: "serverless-functions"},
+		ConfidenceScore: 1.0,
+		Notes:           "Not applicable: the workload has no resources of type aws_lambda_function",
+		NotApplicable:   true,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, input)
+	assert.False(t, aws.ToBool(input.IsApplicable))
+	assert.Equal(t, types.AnswerReasonOutOfScope, input.Reason)
+	assert.Empty(t, input.SelectedChoices)
+	assert.Contains(t, aws.ToString(input.Notes), "no resources of type aws_lambda_function")
+}
+
+func TestFindAffectedResources_ResourceTypeMapping(t *testing.T) {
+	model := &core.WorkloadModel{Resources: []core.Resource{
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+		{Address: "aws_wafv2_web_acl.edge", Type: "aws_wafv2_web_acl"},
+		{Address: "aws_opensearch_domain.search", Type: "aws_opensearch_domain"},
+	}}
+	risk := &core.Risk{Pillar: core.PillarSecurity, Question: &core.WAFRQuestion{ID: "data-rest"}}
+
+	defaults := NewEvaluator(&MockWAFRClient{}, nil)
+	assert.Equal(t, []string{"aws_s3_bucket.logs"}, defaults.findAffectedResources(risk, model))
+
+	custom := NewEvaluator(&MockWAFRClient{}, &EvaluatorConfig{ResourceTypes: &core.ResourceTypeMapping{
+		Pillars:   map[core.Pillar][]string{core.PillarSecurity: {"aws_wafv2"}},
+		Questions: map[string][]string{"data-rest": {"aws_opensearch_domain"}},
+	}})
+	assert.Equal(t, []string{"aws_s3_bucket.logs", "aws_wafv2_web_acl.edge", "aws_opensearch_domain.search"}, custom.findAffectedResources(risk, model))
+
+	other := &core.Risk{Pillar: core.PillarReliability, Question: &core.WAFRQuestion{ID: "rel-1"}}
+	assert.Empty(t, custom.findAffectedResources(other, model), "types are only added to their pillar")
+}
+
+func TestGetImprovementPlan_DeterministicIDs(t *testing.T) {
+	planIDs := func(questionIDs ...string) map[string]string {
+		mockClient := &MockWAFRClient{
+			ListAnswersFunc: func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+				if aws.ToString(params.PillarId) != "security" {
+					return &wellarchitected.ListAnswersOutput{}, nil
+				}
+				var summaries []types.AnswerSummary
+				for _, id := range questionIDs {
+					summaries = append(summaries, types.AnswerSummary{QuestionId: aws.String(id), Risk: types.RiskHigh})
+				}
+				return &wellarchitected.ListAnswersOutput{AnswerSummaries: summaries}, nil
+			},
+		}
+		evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+		plan, err := evaluator.GetImprovementPlan(context.Background(), "wl-123", nil, nil)
+		require.NoError(t, err)
+		ids := make(map[string]string)
+		for _, item := range plan.Items {
+			ids[item.Risk.Question.ID] = item.ID
+		}
+		return ids
+	}
+
+	first := planIDs("sec-2")
+	// A new risk listed ahead of it does not shift the ID of sec-2
+	second := planIDs("sec-1", "sec-2")
+
+	assert.Equal(t, "security/sec-2", first["sec-2"])
+	assert.Equal(t, first["sec-2"], second["sec-2"])
+	assert.Equal(t, "security/sec-1", second["sec-1"])
+}
+
+func TestGetImprovementPlan_IDsIndependentOfPillarOrder(t *testing.T) {
+	planIDs := func(failingPillar string) map[string]string {
+		mockClient := &MockWAFRClient{
+			ListAnswersFunc: func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+				pillarID := aws.ToString(params.PillarId)
+				if pillarID == failingPillar {
+					return nil, errors.New("access denied")
+				}
+				return &wellarchitected.ListAnswersOutput{AnswerSummaries: []types.AnswerSummary{
+					{QuestionId: aws.String(pillarID + "-1"), Risk: types.RiskMedium},
+				}}, nil
+			},
+		}
+		evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+		plan, err := evaluator.GetImprovementPlan(context.Background(), "wl-123", nil, nil)
+		require.NoError(t, err)
+		ids := make(map[string]string)
+		for _, item := range plan.Items {
+			ids[item.Risk.Question.ID] = item.ID
+		}
+		return ids
+	}
+
+	all := planIDs("")
+	// Without the security risks, every later pillar's risk moves up the plan
+	withoutSecurity := planIDs("security")
+
+	assert.Equal(t, "reliability/reliability-1", all["reliability-1"])
+	assert.Equal(t, "security/security-1", all["security-1"])
+	assert.NotContains(t, withoutSecurity, "security-1")
+	for questionID, id := range withoutSecurity {
+		assert.Equal(t, all[questionID], id, questionID)
+	}
+}
+
+func TestCalculatePriority_BlastRadius(t *testing.T) {
+	graph := &core.ResourceGraph{
+		Edges: map[string][]string{
+			"aws_lb.api":                {"aws_vpc.main"},
+			"aws_ecs_service.api":       {"aws_lb.api", "aws_vpc.main"},
+			"aws_db_instance.orders":    {"aws_vpc.main"},
+			"aws_route53_record.api":    {"aws_lb.api"},
+			"aws_cloudwatch_alarm.api":  {"aws_ecs_service.api"},
+			"aws_s3_bucket_policy.logs": {"aws_s3_bucket.logs"},
+		},
+	}
+	shared := &core.Risk{Severity: core.RiskLevelMedium, AffectedResources: []string{"aws_vpc.main"}}
+	isolated := &core.Risk{Severity: core.RiskLevelMedium, AffectedResources: []string{"aws_sqs_queue.jobs"}}
+
+	sharedRadius := len(graph.Dependents(shared.AffectedResources))
+	isolatedRadius := len(graph.Dependents(isolated.AffectedResources))
+	assert.Equal(t, 5, sharedRadius)
+	assert.Equal(t, 0, isolatedRadius)
+
+	assert.Greater(t, calculatePriority(shared, sharedRadius), calculatePriority(isolated, isolatedRadius))
+	assert.Equal(t, calculatePriority(isolated, 0), calculatePriority(isolated, isolatedRadius))
+
+	// The bonus is capped below the gap between severities
+	severities := []core.RiskLevel{core.RiskLevelNone, core.RiskLevelUnassessed, core.RiskLevelMedium, core.RiskLevelHigh}
+	for i := 1; i < len(severities); i++ {
+		lower := &core.Risk{Severity: severities[i-1]}
+		higher := &core.Risk{Severity: severities[i]}
+		assert.Greater(t, calculatePriority(higher, 0), calculatePriority(lower, 1000), "%s over %s", severities[i], severities[i-1])
+	}
+}
+
+func TestGetImprovementPlan_SeverityOverrides(t *testing.T) {
+	mockClient := &MockWAFRClient{
+		ListAnswersFunc: func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+			if aws.ToString(params.PillarId) != "security" {
+				return &wellarchitected.ListAnswersOutput{}, nil
+			}
+			return &wellarchitected.ListAnswersOutput{
+				AnswerSummaries: []types.AnswerSummary{
+					{QuestionId: aws.String("data-classification"), Risk: types.RiskMedium},
+					{QuestionId: aws.String("sec-logging"), Risk: types.RiskHigh},
+					{QuestionId: aws.String("sec-identities"), Risk: types.RiskMedium},
+					{QuestionId: aws.String("sec-incidents"), Risk: types.RiskUnanswered},
+					{QuestionId: aws.String("sec-network"), Risk: types.RiskNone},
+					{QuestionId: aws.String("sec-compute"), Risk: types.RiskMedium},
+					{QuestionId: aws.String("sec-data-at-rest"), Risk: types.RiskNone},
+				},
+			}, nil
+		},
+	}
+	evaluator := NewEvaluator(mockClient, &EvaluatorConfig{
+		MaxRetries: 1,
+		BaseDelay:  time.Millisecond,
+		SeverityOverrides: map[string]core.RiskLevel{
+			"Data-Classification": core.RiskLevelHigh,
+			"sec-logging":         core.RiskLevelMedium,
+			"sec-incidents":       core.RiskLevelHigh,
+			"sec-network":         core.RiskLevelMedium,
+			"sec-compute":         core.RiskLevelNone,
+		},
+	})
+
+	plan, err := evaluator.GetImprovementPlan(context.Background(), "wl-123", nil, nil)
+	require.NoError(t, err)
+
+	items := make(map[string]*core.ImprovementPlanItem)
+	for _, item := range plan.Items {
+		items[item.Risk.Question.ID] = item
+	}
+	require.Len(t, items, 5)
+
+	escalated := items["data-classification"]
+	assert.Equal(t, core.RiskLevelHigh, escalated.Risk.Severity)
+	require.NotNil(t, escalated.Risk.OriginalSeverity)
+	assert.Equal(t, core.RiskLevelMedium, *escalated.Risk.OriginalSeverity)
+
+	lowered := items["sec-logging"]
+	assert.Equal(t, core.RiskLevelMedium, lowered.Risk.Severity)
+	require.NotNil(t, lowered.Risk.OriginalSeverity)
+	assert.Equal(t, core.RiskLevelHigh, *lowered.Risk.OriginalSeverity)
+
+	// The escalated risk now ranks with other high risks
+	assert.Greater(t, escalated.Priority, items["sec-identities"].Priority)
+	assert.Nil(t, items["sec-identities"].Risk.OriginalSeverity)
+
+	// An unanswered question stays unassessed
+	assert.Equal(t, core.RiskLevelUnassessed, items["sec-incidents"].Risk.Severity)
+	assert.Nil(t, items["sec-incidents"].Risk.OriginalSeverity)
+
+	// An override raises a question without risk into the plan
+	raised := items["sec-network"]
+	require.NotNil(t, raised)
+	assert.Equal(t, core.RiskLevelMedium, raised.Risk.Severity)
+	require.NotNil(t, raised.Risk.OriginalSeverity)
+	assert.Equal(t, core.RiskLevelNone, *raised.Risk.OriginalSeverity)
+
+	// An override to none leaves the question out of the plan, as do
+	// questions without risk
+	assert.NotContains(t, items, "sec-compute")
+	assert.NotContains(t, items, "sec-data-at-rest")
+}
@@ -0,0 +1,153 @@
+package wafr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
+	"github.com/waffle/waffle/internal/core"
+)
+
+// waffleNotesPrefix starts the notes SubmitAnswer writes
+const waffleNotesPrefix = "Automated analysis by Waffle"
+
+// maxNotesLength is the longest question note the Well-Architected Tool accepts
+const maxNotesLength = 2084
+
+// GetExistingAnswers returns the current answer of each of questions that is
+// answered, keyed by question ID. A question counts as answered when it has
+// selected choices, was marked not applicable or carries a risk rating. The
+// notes of answered questions are read with GetAnswer, as answer summaries
+// do not include them.
+func (e *Evaluator) GetExistingAnswers(
+	ctx context.Context,
+	awsWorkloadID string,
+	questions []*core.WAFRQuestion,
+) (map[string]core.ExistingAnswer, error) {
+	if awsWorkloadID == "" {
+		return nil, errors.New("AWS workload ID is required")
+	}
+
+	wanted := make(map[string]bool, len(questions))
+	var pillars []core.Pillar
+	seen := make(map[core.Pillar]bool)
+	for _, question := range questions {
+		wanted[question.ID] = true
+		if !seen[question.Pillar] {
+			seen[question.Pillar] = true
+			pillars = append(pillars, question.Pillar)
+		}
+	}
+
+	answers := make(map[string]core.ExistingAnswer)
+	for _, pillar := range pillars {
+		summaries, err := e.listAnswerSummaries(ctx, awsWorkloadID, pillar)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list answers for pillar %s: %w", pillar, err)
+		}
+
+		for _, summary := range summaries {
+			questionID := aws.ToString(summary.QuestionId)
+			if !wanted[questionID] || !isAnswered(summary) {
+				continue
+			}
+			notes, err := e.getAnswerNotes(ctx, awsWorkloadID, questionID)
+			if err != nil {
+				return nil, err
+			}
+			answers[questionID] = core.ExistingAnswer{
+				SelectedChoices: summary.SelectedChoices,
+				Notes:           notes,
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "read existing answers",
+		"aws_workload_id", awsWorkloadID,
+		"questions", len(questions),
+		"answered", len(answers),
+	)
+
+	return answers, nil
+}
+
+// listAnswerSummaries lists the answer summaries of a pillar
+func (e *Evaluator) listAnswerSummaries(ctx context.Context, awsWorkloadID string, pillar core.Pillar) ([]types.AnswerSummary, error) {
+	var summaries []types.AnswerSummary
+	var nextToken *string
+
+	for {
+		input := &wellarchitected.ListAnswersInput{
+			WorkloadId: aws.String(awsWorkloadID),
+			LensAlias:  aws.String("wellarchitected"),
+			PillarId:   aws.String(mapPillarToAWSID(pillar)),
+			NextToken:  nextToken,
+			MaxResults: aws.Int32(50),
+		}
+
+		var output *wellarchitected.ListAnswersOutput
+		err := e.retryWithBackoff(ctx, "ListAnswers", func() error {
+			var err error
+			output, err = e.client.ListAnswers(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, wrapWAFRError("ListAnswers", err)
+		}
+
+		summaries = append(summaries, output.AnswerSummaries...)
+		if output.NextToken == nil {
+			return summaries, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// getAnswerNotes returns the notes of a question without the notes an
+// earlier review appended
+func (e *Evaluator) getAnswerNotes(ctx context.Context, awsWorkloadID, questionID string) (string, error) {
+	input := &wellarchitected.GetAnswerInput{
+		WorkloadId: aws.String(awsWorkloadID),
+		LensAlias:  aws.String("wellarchitected"),
+		QuestionId: aws.String(questionID),
+	}
+
+	var output *wellarchitected.GetAnswerOutput
+	err := e.retryWithBackoff(ctx, "GetAnswer", func() error {
+		var err error
+		output, err = e.client.GetAnswer(ctx, input)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get answer for question %s: %w", questionID, wrapWAFRError("GetAnswer", err))
+	}
+	if output.Answer == nil {
+		return "", nil
+	}
+	return humanNotes(aws.ToString(output.Answer.Notes)), nil
+}
+
+// isAnswered reports whether someone has answered the question
+func isAnswered(summary types.AnswerSummary) bool {
+	if len(summary.SelectedChoices) > 0 {
+		return true
+	}
+	if summary.IsApplicable != nil && !*summary.IsApplicable {
+		return true
+	}
+	return summary.Risk != "" && summary.Risk != types.RiskUnanswered
+}
+
+// humanNotes strips the notes an earlier Waffle review appended, so reviews
+// run again do not pile them up
+func humanNotes(notes string) string {
+	if i := strings.Index(notes, waffleNotesPrefix); i >= 0 {
+		notes = notes[:i]
+	}
+	return strings.TrimSpace(notes)
+}
@@ -0,0 +1,77 @@
+package wafr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
+	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waffle/waffle/internal/core"
+)
+
+func TestGetExistingAnswers(t *testing.T) {
+	var listedPillars, fetched []string
+	mockClient := &MockWAFRClient{
+		ListAnswersFunc: func(ctx context.Context, params *wellarchitected.ListAnswersInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.ListAnswersOutput, error) {
+			listedPillars = append(listedPillars, aws.ToString(params.PillarId))
+			return &wellarchitected.ListAnswersOutput{
+				AnswerSummaries: []types.AnswerSummary{
+					{QuestionId: aws.String("sec_1"), SelectedChoices: []string{"sec_1_a"}, Risk: types.RiskNone},
+					{QuestionId: aws.String("sec_2"), Risk: types.RiskUnanswered},
+					{QuestionId: aws.String("sec_3"), IsApplicable: aws.Bool(false)},
+					// Answered, but not in the review
+					{QuestionId: aws.String("sec_4"), SelectedChoices: []string{"sec_4_a"}},
+				},
+			}, nil
+		},
+		GetAnswerFunc: func(ctx context.Context, params *wellarchitected.GetAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetAnswerOutput, error) {
+			fetched = append(fetched, aws.ToString(params.QuestionId))
+			notes := map[string]string{
+				"sec_1": "Reviewed with the security team\n\nAutomated analysis by Waffle (confidence: 0.80)\n\nEarlier run",
+			}[aws.ToString(params.QuestionId)]
+			return &wellarchitected.GetAnswerOutput{Answer: &types.Answer{Notes: aws.String(notes)}}, nil
+		},
+	}
+	evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+	questions := []*core.WAFRQuestion{
+		{ID: "sec_1", Pillar: core.PillarSecurity},
+		{ID: "sec_2", Pillar: core.PillarSecurity},
+		{ID: "sec_3", Pillar: core.PillarSecurity},
+	}
+	answers, err := evaluator.GetExistingAnswers(context.Background(), "wl-123", questions)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security"}, listedPillars, "each pillar is listed once")
+	assert.Equal(t, []string{"sec_1", "sec_3"}, fetched, "notes are read for answered questions only")
+	assert.Equal(t, map[string]core.ExistingAnswer{
+		// Notes an earlier review appended are dropped
+		"sec_1": {SelectedChoices: []string{"sec_1_a"}, Notes: "Reviewed with the security team"},
+		"sec_3": {},
+	}, answers)
+}
+
+func TestSubmitAnswer_ExistingNotes(t *testing.T) {
+	var captured *wellarchitected.UpdateAnswerInput
+	mockClient := &MockWAFRClient{
+		UpdateAnswerFunc: func(ctx context.Context, params *wellarchitected.UpdateAnswerInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.UpdateAnswerOutput, error) {
+			captured = params
+			return &wellarchitected.UpdateAnswerOutput{}, nil
+		},
+	}
+	evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+	err := evaluator.SubmitAnswer(context.Background(), "wl-123", "sec_1", &core.QuestionEvaluation{
+		ConfidenceScore: 0.9,
+		Notes:           "Buckets are encrypted",
+		ExistingNotes:   "Reviewed with the security team",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, captured)
+	assert.Equal(t, "Reviewed with the security team\n\nAutomated analysis by Waffle (confidence: 0.90)\n\nBuckets are encrypted", aws.ToString(captured.Notes))
+}
@@ -0,0 +1,277 @@
+package wafr
+
+import (
+	"sort"
+
+	"github.com/waffle/waffle/internal/core"
+)
+
+// questionResourceTypes narrows the resource types of questions whose
+// subject is more specific than their pillar. Other questions use the types
+// of their pillar.
+var questionResourceTypes = map[string][]string{
+	"data-rest": {
+		"aws_s3_bucket",
+		"aws_kms_key",
+		"aws_ebs_volume",
+		"aws_ebs_encryption_by_default",
+		"aws_db_instance",
+		"aws_rds_cluster",
+		"aws_dynamodb_table",
+		"aws_efs_file_system",
+		"aws_elasticache_replication_group",
+		"aws_sqs_queue",
+		"aws_sns_topic",
+	},
+	"data-transit": {
+		"aws_lb_listener",
+		"aws_alb_listener",
+		"aws_acm_certificate",
+		"aws_cloudfront_distribution",
+		"aws_api_gateway_domain_name",
+		"aws_apigatewayv2_domain_name",
+		"aws_s3_bucket_policy",
+	},
+	"backing-up-data": {
+		"aws_backup_plan",
+		"aws_backup_selection",
+		"aws_backup_vault",
+		"aws_db_instance",
+		"aws_rds_cluster",
+		"aws_dynamodb_table",
+		"aws_s3_bucket_versioning",
+		"aws_s3_bucket_replication_configuration",
+	},
+	"network-protection": {
+		"aws_vpc",
+		"aws_subnet",
+		"aws_security_group",
+		"aws_network_acl",
+		"aws_route_table",
+		"aws_wafv2_web_acl",
+		"aws_networkfirewall_firewall",
+	},
+	"identities": {
+		"aws_iam_user",
+		"aws_iam_group",
+		"aws_iam_role",
+		"aws_iam_openid_connect_provider",
+		"aws_iam_saml_provider",
+		"aws_identitystore_user",
+	},
+	"permissions": {
+		"aws_iam_role",
+		"aws_iam_policy",
+		"aws_iam_role_policy",
+		"aws_iam_user_policy",
+		"aws_organizations_policy",
+	},
+}
+
+// computeResourceTypes are the resource types that run workload code:
+// instances, containers and serverless functions
+var computeResourceTypes = []string{
+	"aws_instance",
+	"aws_launch_template",
+	"aws_launch_configuration",
+	"aws_autoscaling_group",
+	"aws_ecs",
+	"aws_eks",
+	"aws_lambda",
+	"aws_batch",
+	"aws_apprunner",
+	"aws_elastic_beanstalk",
+	"aws_lightsail_instance",
+	"aws_emr",
+}
+
+// questionRequiredResourceTypes lists, for questions about one kind of
+// resource, the types of which the workload needs at least one for the
+// question to apply. The wellarchitected lens has no questions specific to
+// containers or serverless functions, so only these three are covered;
+// questions of other lenses need a requires entry in the resource type
+// mapping, which also replaces the types of the questions listed here.
+var questionRequiredResourceTypes = map[string][]string{
+	"protect-compute":  computeResourceTypes,
+	"compute-hardware": computeResourceTypes,
+	"backing-up-data": {
+		"aws_s3_bucket",
+		"aws_db_instance",
+		"aws_rds_cluster",
+		"aws_dynamodb_table",
+		"aws_efs_file_system",
+		"aws_fsx",
+		"aws_ebs_volume",
+		"aws_instance",
+		"aws_elasticache",
+		"aws_redshift",
+		"aws_docdb",
+		"aws_neptune",
+		"aws_opensearch",
+		"aws_elasticsearch",
+		"aws_backup",
+	},
+}
+
+// questionScopedModel returns the part of workloadModel a question is
+// evaluated against: the resources of the question's relevantTypes and
+// their direct dependencies and dependents, with the relationships between
+// them. The addresses of those resources are returned sorted. When no
+// resource matches, the whole model is returned and no addresses, so a
+// question is never evaluated against nothing.
+func questionScopedModel(workloadModel *core.WorkloadModel, relevantTypes []string) (*core.WorkloadModel, []string) {
+	selected := make(map[string]bool)
+	for _, resource := range workloadModel.Resources {
+		for _, relevantType := range relevantTypes {
+			if matchesResourceType(resource.Type, relevantType) {
+				selected[resource.Address] = true
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return workloadModel, nil
+	}
+
+	// Add neighbors of the matched resources in either direction
+	matched := make(map[string]bool, len(selected))
+	for address := range selected {
+		matched[address] = true
+	}
+	for from, dependencies := range resourceEdges(workloadModel) {
+		for _, to := range dependencies {
+			if matched[from] {
+				selected[to] = true
+			}
+			if matched[to] {
+				selected[from] = true
+			}
+		}
+	}
+
+	scoped := *workloadModel
+	scoped.Resources = make([]core.Resource, 0, len(selected))
+	for _, resource := range workloadModel.Resources {
+		if selected[resource.Address] {
+			scoped.Resources = append(scoped.Resources, resource)
+		}
+	}
+	if workloadModel.Relationships != nil {
+		scoped.Relationships = &core.ResourceGraph{
+			Nodes: make(map[string]*core.Resource, len(selected)),
+			Edges: make(map[string][]string),
+		}
+		for i := range scoped.Resources {
+			scoped.Relationships.Nodes[scoped.Resources[i].Address] = &scoped.Resources[i]
+		}
+		for from, dependencies := range workloadModel.Relationships.Edges {
+			if !selected[from] {
+				continue
+			}
+			for _, to := range dependencies {
+				if selected[to] {
+					scoped.Relationships.Edges[from] = append(scoped.Relationships.Edges[from], to)
+				}
+			}
+		}
+	}
+
+	considered := make([]string, 0, len(scoped.Resources))
+	for _, resource := range scoped.Resources {
+		considered = append(considered, resource.Address)
+	}
+	sort.Strings(considered)
+	return &scoped, considered
+}
+
+// resourceEdges returns the dependencies of each resource, from the resource
+// graph when the model has one
+func resourceEdges(workloadModel *core.WorkloadModel) map[string][]string {
+	if workloadModel.Relationships != nil {
+		return workloadModel.Relationships.Edges
+	}
+	edges := make(map[string][]string)
+	for _, resource := range workloadModel.Resources {
+		if len(resource.Dependencies) > 0 {
+			edges[resource.Address] = resource.Dependencies
+		}
+	}
+	return edges
+}
+
+// hasResourceOfTypes reports whether workloadModel has a resource of any of
+// types
+func hasResourceOfTypes(workloadModel *core.WorkloadModel, types []string) bool {
+	for _, resource := range workloadModel.Resources {
+		for _, resourceType := range types {
+			if matchesResourceType(resource.Type, resourceType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResourceTypeMatch is a pillar or question whose relevant resource types
+// include a resource type
+type ResourceTypeMatch struct {
+	Pillar     core.Pillar `json:"pillar,omitempty"`
+	QuestionID string      `json:"question_id,omitempty"`
+	// MatchedType is the relevant type that matched, which may be a prefix
+	// of the resource type
+	MatchedType string `json:"matched_type"`
+	// FromMapping is set when the type comes from the resource type mapping
+	// rather than the built-in types
+	FromMapping bool `json:"from_mapping,omitempty"`
+}
+
+// ResourceTypeRelevance explains why questions consider resourceType. It
+// returns the pillars whose types match, in framework order, followed by the
+// questions with types of their own that match, sorted by question ID. The
+// other questions of a matched pillar consider the resource through their
+// pillar. mapping may be nil.
+func ResourceTypeRelevance(resourceType string, mapping *core.ResourceTypeMapping) []ResourceTypeMatch {
+	var matches []ResourceTypeMatch
+	for _, pillar := range core.AllPillars() {
+		if matched, fromMapping, ok := firstMatchingType(resourceType, getRelevantResourceTypes("", pillar), mapping.ResourceTypes("", pillar)); ok {
+			matches = append(matches, ResourceTypeMatch{Pillar: pillar, MatchedType: matched, FromMapping: fromMapping})
+		}
+	}
+
+	var mappedQuestions map[string][]string
+	if mapping != nil {
+		mappedQuestions = mapping.Questions
+	}
+	questionIDs := make([]string, 0, len(questionResourceTypes)+len(mappedQuestions))
+	for questionID := range questionResourceTypes {
+		questionIDs = append(questionIDs, questionID)
+	}
+	for questionID := range mappedQuestions {
+		if _, ok := questionResourceTypes[questionID]; !ok {
+			questionIDs = append(questionIDs, questionID)
+		}
+	}
+	sort.Strings(questionIDs)
+	for _, questionID := range questionIDs {
+		if matched, fromMapping, ok := firstMatchingType(resourceType, questionResourceTypes[questionID], mappedQuestions[questionID]); ok {
+			matches = append(matches, ResourceTypeMatch{QuestionID: questionID, MatchedType: matched, FromMapping: fromMapping})
+		}
+	}
+	return matches
+}
+
+// firstMatchingType returns the first of the built-in and then the mapped
+// types that resourceType matches
+func firstMatchingType(resourceType string, builtIn, mapped []string) (string, bool, bool) {
+	for _, relevantType := range builtIn {
+		if matchesResourceType(resourceType, relevantType) {
+			return relevantType, false, true
+		}
+	}
+	for _, relevantType := range mapped {
+		if matchesResourceType(resourceType, relevantType) {
+			return relevantType, true, true
+		}
+	}
+	return "", false, false
+}
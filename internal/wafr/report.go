@@ -12,6 +12,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected"
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
+
+	"github.com/waffle/waffle/internal/core"
 )
 
 // GetConsolidatedReport retrieves a consolidated report from AWS in the specified format
@@ -67,6 +69,107 @@ func (e *Evaluator) GetConsolidatedReport(
 	return decodedData, nil
 }
 
+// consolidatedReportPageSize is the largest page GetConsolidatedReport
+// returns in JSON format
+const consolidatedReportPageSize = 15
+
+// GetConsolidatedReportMetrics retrieves the consolidated report in JSON
+// format and returns the metrics of one workload. The report covers every
+// workload in the account, so pages are read until the workload is found.
+func (e *Evaluator) GetConsolidatedReportMetrics(ctx context.Context, awsWorkloadID string) (*core.ConsolidatedReport, error) {
+	if awsWorkloadID == "" {
+		return nil, errors.New("AWS workload ID is required")
+	}
+
+	var nextToken *string
+	for {
+		input := &wellarchitected.GetConsolidatedReportInput{
+			Format:                 types.ReportFormatJson,
+			IncludeSharedResources: aws.Bool(false),
+			MaxResults:             aws.Int32(consolidatedReportPageSize),
+			NextToken:              nextToken,
+		}
+
+		var output *wellarchitected.GetConsolidatedReportOutput
+		err := e.retryWithBackoff(ctx, "GetConsolidatedReport", func() error {
+			var err error
+			output, err = e.client.GetConsolidatedReport(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get consolidated report: %w", wrapWAFRError("GetConsolidatedReport", err))
+		}
+
+		for _, metric := range output.Metrics {
+			if aws.ToString(metric.WorkloadId) != awsWorkloadID {
+				continue
+			}
+			report := convertConsolidatedMetric(metric)
+			slog.InfoContext(ctx, "consolidated report metrics retrieved",
+				"aws_workload_id", awsWorkloadID,
+				"lens_count", len(report.Lenses),
+			)
+			return report, nil
+		}
+
+		if output.NextToken == nil {
+			return nil, fmt.Errorf("workload %s is not in the consolidated report", awsWorkloadID)
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// convertConsolidatedMetric converts the consolidated report metric of a
+// workload
+func convertConsolidatedMetric(metric types.ConsolidatedReportMetric) *core.ConsolidatedReport {
+	report := &core.ConsolidatedReport{
+		AWSWorkloadID: aws.ToString(metric.WorkloadId),
+		WorkloadName:  aws.ToString(metric.WorkloadName),
+		WorkloadARN:   aws.ToString(metric.WorkloadArn),
+		UpdatedAt:     metric.UpdatedAt,
+		RiskCounts:    riskCounts(metric.RiskCounts),
+		Lenses:        make([]core.ConsolidatedLens, 0, len(metric.Lenses)),
+	}
+	for _, lens := range metric.Lenses {
+		lensReport := core.ConsolidatedLens{
+			LensARN:    aws.ToString(lens.LensArn),
+			RiskCounts: riskCounts(lens.RiskCounts),
+			Pillars:    make([]core.ConsolidatedPillar, 0, len(lens.Pillars)),
+		}
+		for _, pillar := range lens.Pillars {
+			pillarReport := core.ConsolidatedPillar{
+				PillarID:   aws.ToString(pillar.PillarId),
+				RiskCounts: riskCounts(pillar.RiskCounts),
+			}
+			for _, question := range pillar.Questions {
+				questionReport := core.ConsolidatedQuestion{
+					QuestionID: aws.ToString(question.QuestionId),
+					Risk:       string(question.Risk),
+				}
+				for _, bp := range question.BestPractices {
+					questionReport.BestPractices = append(questionReport.BestPractices, core.ConsolidatedBestPractice{
+						ChoiceID:    aws.ToString(bp.ChoiceId),
+						ChoiceTitle: aws.ToString(bp.ChoiceTitle),
+					})
+				}
+				pillarReport.Questions = append(pillarReport.Questions, questionReport)
+			}
+			lensReport.Pillars = append(lensReport.Pillars, pillarReport)
+		}
+		report.Lenses = append(report.Lenses, lensReport)
+	}
+	return report
+}
+
+// riskCounts returns counts, or an empty map when there are none so the
+// JSON holds an object rather than null
+func riskCounts(counts map[string]int32) map[string]int32 {
+	if counts == nil {
+		return map[string]int32{}
+	}
+	return counts
+}
+
 // EnhancedReportData represents the enhanced JSON report with IaC evidence
 type EnhancedReportData struct {
 	// AWS workload information
@@ -144,7 +247,7 @@ func (e *Evaluator) GetResultsJSON(
 	// Build enhanced report
 	enhancedReport := &EnhancedReportData{
 		AWSWorkloadID: awsWorkloadID,
-		GeneratedAt:   time.Now().UTC(),
+		GeneratedAt:   e.clock.Now().UTC(),
 		AWSReport:     awsReportData,
 	}
 
@@ -10,6 +10,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/wellarchitected/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/waffle/waffle/internal/core"
 )
 
 func TestGetConsolidatedReport(t *testing.T) {
@@ -266,3 +268,110 @@ func TestGetResultsJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConsolidatedReportMetrics(t *testing.T) {
+	pages := map[string]*wellarchitected.GetConsolidatedReportOutput{
+		"": {
+			Metrics: []types.ConsolidatedReportMetric{{
+				WorkloadId:   aws.String("wl-other"),
+				WorkloadName: aws.String("other-app"),
+				MetricType:   types.MetricTypeWorkload,
+			}},
+			NextToken: aws.String("page-2"),
+		},
+		"page-2": {
+			Metrics: []types.ConsolidatedReportMetric{{
+				WorkloadId:   aws.String("wl-1"),
+				WorkloadName: aws.String("my-app"),
+				WorkloadArn:  aws.String("arn:aws:wellarchitected:us-east-1:123456789012:workload/wl-1"),
+				MetricType:   types.MetricTypeWorkload,
+				RiskCounts:   map[string]int32{"HIGH": 1, "MEDIUM": 1},
+				Lenses: []types.LensMetric{{
+					LensArn:    aws.String("arn:aws:wellarchitected::aws:lens/wellarchitected"),
+					RiskCounts: map[string]int32{"HIGH": 1, "MEDIUM": 1},
+					Pillars: []types.PillarMetric{{
+						PillarId:   aws.String("security"),
+						RiskCounts: map[string]int32{"HIGH": 1, "MEDIUM": 1},
+						Questions: []types.QuestionMetric{
+							{
+								QuestionId: aws.String("data-rest"),
+								Risk:       types.RiskHigh,
+								BestPractices: []types.BestPractice{{
+									ChoiceId:    aws.String("sec_protect_data_rest_encrypt"),
+									ChoiceTitle: aws.String("Enforce encryption at rest"),
+								}},
+							},
+							{
+								QuestionId: aws.String("data-transit"),
+								Risk:       types.RiskMedium,
+							},
+						},
+					}},
+				}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		awsWorkloadID string
+		wantErr       string
+		checkReport   func(t *testing.T, report *core.ConsolidatedReport)
+	}{
+		{
+			name:          "workload on a later page",
+			awsWorkloadID: "wl-1",
+			checkReport: func(t *testing.T, report *core.ConsolidatedReport) {
+				assert.Equal(t, "my-app", report.WorkloadName)
+				assert.Equal(t, "arn:aws:wellarchitected:us-east-1:123456789012:workload/wl-1", report.WorkloadARN)
+				assert.Equal(t, map[string]int32{"HIGH": 1, "MEDIUM": 1}, report.RiskCounts)
+
+				require.Len(t, report.Lenses, 1)
+				require.Len(t, report.Lenses[0].Pillars, 1)
+				pillar := report.Lenses[0].Pillars[0]
+				assert.Equal(t, "security", pillar.PillarID)
+				require.Len(t, pillar.Questions, 2)
+				assert.Equal(t, "data-rest", pillar.Questions[0].QuestionID)
+				assert.Equal(t, "HIGH", pillar.Questions[0].Risk)
+				assert.Equal(t, []core.ConsolidatedBestPractice{{
+					ChoiceID:    "sec_protect_data_rest_encrypt",
+					ChoiceTitle: "Enforce encryption at rest",
+				}}, pillar.Questions[0].BestPractices)
+				assert.Equal(t, "MEDIUM", pillar.Questions[1].Risk)
+			},
+		},
+		{
+			name:          "workload not in the report",
+			awsWorkloadID: "wl-missing",
+			wantErr:       "workload wl-missing is not in the consolidated report",
+		},
+		{
+			name:    "missing workload ID",
+			wantErr: "AWS workload ID is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockWAFRClient{
+				GetConsolidatedReportFunc: func(ctx context.Context, params *wellarchitected.GetConsolidatedReportInput, optFns ...func(*wellarchitected.Options)) (*wellarchitected.GetConsolidatedReportOutput, error) {
+					assert.Equal(t, types.ReportFormatJson, params.Format)
+					assert.Equal(t, int32(consolidatedReportPageSize), aws.ToInt32(params.MaxResults))
+					return pages[aws.ToString(params.NextToken)], nil
+				},
+			}
+			evaluator := NewEvaluator(mockClient, &EvaluatorConfig{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+			report, err := evaluator.GetConsolidatedReportMetrics(context.Background(), tt.awsWorkloadID)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.awsWorkloadID, report.AWSWorkloadID)
+			tt.checkReport(t, report)
+		})
+	}
+}